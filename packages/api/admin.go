@@ -0,0 +1,199 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package api
+
+import (
+	"net/http"
+	"net/http/pprof"
+	"strings"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/daemons"
+	"github.com/GenesisKernel/go-genesis/packages/diagnose"
+	logtools "github.com/GenesisKernel/go-genesis/packages/log"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	hr "github.com/julienschmidt/httprouter"
+	log "github.com/sirupsen/logrus"
+)
+
+type logLevelsResult struct {
+	Level      string            `json:"level"`
+	Subsystems map[string]string `json:"subsystems"`
+}
+
+func getLogLevels(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	global, levels := logtools.GetLevels()
+	result := logLevelsResult{Level: logtools.LevelName(global), Subsystems: make(map[string]string)}
+	for name, level := range levels {
+		result.Subsystems[name] = logtools.LevelName(level)
+	}
+	data.result = &result
+	return nil
+}
+
+func setLogLevel(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	subsystem := data.params[`subsystem`].(string)
+	level := data.params[`level`].(string)
+	switch {
+	case len(subsystem) == 0:
+		logtools.SetGlobalLevel(logtools.ParseLevel(level))
+	case len(level) == 0:
+		logtools.ResetSubsystemLevel(subsystem)
+	default:
+		logtools.SetSubsystemLevel(subsystem, logtools.ParseLevel(level))
+	}
+	logger.WithFields(log.Fields{"subsystem": subsystem, "level": level}).Info("log level changed")
+	return getLogLevels(w, r, data, logger)
+}
+
+func getRuntimeStats(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	data.result = diagnose.GetRuntimeStats()
+	return nil
+}
+
+// adminRawHandler checks the admin token for the handlers which write the response themselves
+func adminRawHandler(handler func(http.ResponseWriter, *http.Request, hr.Params)) hr.Handle {
+	return hr.Handle(func(w http.ResponseWriter, r *http.Request, ps hr.Params) {
+		logger := log.WithFields(log.Fields{"path": r.URL.Path, "remote": r.RemoteAddr})
+		token, err := jwtToken(r)
+		if err != nil || token == nil || !token.Valid {
+			logger.WithFields(log.Fields{"type": consts.JWTError, "error": err}).Error("admin token is not valid")
+			errorAPI(w, `E_UNAUTHORIZED`, http.StatusUnauthorized)
+			return
+		}
+		claims, ok := token.Claims.(*JWTClaims)
+		if !ok || converter.StrToInt64(claims.KeyID) != conf.Config.KeyID {
+			logger.WithFields(log.Fields{"type": consts.AccessDenied}).Error("admin access denied")
+			errorAPI(w, `E_PERMISSION`, http.StatusForbidden)
+			return
+		}
+		if len(claims.Id) > 0 && isRevoked(claims.Id) {
+			logger.WithFields(log.Fields{"type": consts.AccessDenied, "session": claims.Id}).Warning("session is revoked")
+			errorAPI(w, `E_TOKENREVOKED`, http.StatusUnauthorized)
+			return
+		}
+		r.ParseForm()
+		writeAudit(r, conf.Config.KeyID, converter.StrToInt64(claims.EcosystemID), model.AuditAdmin, logger)
+		handler(w, r, ps)
+	})
+}
+
+func pprofHandler(w http.ResponseWriter, r *http.Request, ps hr.Params) {
+	switch name := strings.Trim(ps.ByName(`name`), `/`); name {
+	case `profile`:
+		pprof.Profile(w, r)
+	case `trace`:
+		pprof.Trace(w, r)
+	case `symbol`:
+		pprof.Symbol(w, r)
+	case `cmdline`:
+		pprof.Cmdline(w, r)
+	case ``:
+		pprof.Index(w, r)
+	default:
+		pprof.Handler(name).ServeHTTP(w, r)
+	}
+}
+
+func diagnoseHandler(w http.ResponseWriter, r *http.Request, ps hr.Params) {
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", `attachment; filename="diagnose-`+
+		time.Now().Format("20060102150405")+`.tar.gz"`)
+	diagnose.WriteBundle(w)
+}
+
+type daemonsResult struct {
+	List []daemons.DaemonStatus `json:"list"`
+}
+
+func getDaemons(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	data.result = &daemonsResult{List: daemons.DaemonsStatus()}
+	return nil
+}
+
+func daemonAction(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	var err error
+	name := data.params[`name`].(string)
+	switch data.params[`action`].(string) {
+	case `pause`:
+		err = daemons.PauseDaemon(name, true)
+	case `resume`:
+		err = daemons.PauseDaemon(name, false)
+	case `restart`:
+		err = daemons.RestartDaemon(name)
+	default:
+		return errorAPI(w, `E_UNKNOWNACTION`, http.StatusBadRequest, data.params[`action`])
+	}
+	if err == daemons.ErrUnknownDaemon {
+		return errorAPI(w, `E_UNKNOWNDAEMON`, http.StatusNotFound, name)
+	}
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.ContextError, "daemon_name": name, "error": err}).Error("daemon action")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	return getDaemons(w, r, data, logger)
+}
+
+func reloadConfig(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	result, err := conf.ReloadConfig()
+	if err != nil {
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	data.result = result
+	return nil
+}
+
+// writable rejects the requests which change the state while the node is in the maintenance mode
+func writable(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	if status := daemons.Maintenance(); status.Enabled {
+		w.Header().Set(`Retry-After`, `60`)
+		return errorAPI(w, `E_MAINTENANCE`, http.StatusServiceUnavailable, status.Reason)
+	}
+	return nil
+}
+
+func getMaintenance(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	status := daemons.Maintenance()
+	data.result = &status
+	return nil
+}
+
+func setMaintenance(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	var enabled bool
+	switch data.params[`action`].(string) {
+	case `on`:
+		enabled = true
+	case `off`:
+	default:
+		return errorAPI(w, `E_UNKNOWNACTION`, http.StatusBadRequest, data.params[`action`])
+	}
+	if err := daemons.SetMaintenance(enabled, data.params[`reason`].(string)); err != nil {
+		logger.WithFields(log.Fields{"type": consts.ContextError, "error": err}).Error("waiting for daemons of maintenance mode")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	return getMaintenance(w, r, data, logger)
+}
@@ -64,6 +64,9 @@ type apiData struct {
 	vde         bool
 	vm          *script.VM
 	token       *jwt.Token
+	etag        bool
+	domain      *model.Domain // the domain of the request without token to the content API
+	requestID   string        // X-Request-ID of the request which is passed to the sent transactions
 }
 
 // ParamString reaturs string value of the api params
@@ -172,7 +175,8 @@ func getHeader(txName string, data *apiData) (tx.Header, error) {
 // DefaultHandler is a common handle function for api requests
 func DefaultHandler(method, pattern string, params map[string]int, handlers ...apiHandle) hr.Handle {
 
-	return hr.Handle(func(w http.ResponseWriter, r *http.Request, ps hr.Params) {
+	return hr.Handle(func(rw http.ResponseWriter, r *http.Request, ps hr.Params) {
+		w := &statusWriter{ResponseWriter: rw}
 		counterName := statsd.APIRouteCounterName(method, pattern)
 		statsd.Client.Inc(counterName+statsd.Count, 1, 1.0)
 		startTime := time.Now()
@@ -180,7 +184,9 @@ func DefaultHandler(method, pattern string, params map[string]int, handlers ...a
 			err  error
 			data apiData
 		)
-		requestLogger := log.WithFields(log.Fields{"headers": r.Header, "path": r.URL.Path, "protocol": r.Proto, "remote": r.RemoteAddr})
+		data.requestID = requestID(r)
+		requestLogger := log.WithFields(log.Fields{"headers": r.Header, "path": r.URL.Path, "protocol": r.Proto, "remote": r.RemoteAddr,
+			"request_id": data.requestID})
 		requestLogger.Info("received http request")
 
 		defer func() {
@@ -191,10 +197,13 @@ func DefaultHandler(method, pattern string, params map[string]int, handlers ...a
 				fmt.Println("API Recovered", fmt.Sprintf("%s: %s", r, debug.Stack()))
 				errorAPI(w, `E_RECOVERED`, http.StatusInternalServerError)
 			}
+			trackSLO(method+` `+pattern, endTime.Sub(startTime), w.Status())
 		}()
 
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Header().Set(requestIDHeader, data.requestID)
+		w.Header().Add("Access-Control-Expose-Headers", requestIDHeader)
 		if conf.Installed {
 			if r.URL.Path == apiInstallRoute {
 				errorAPI(w, `E_INSTALLED`, http.StatusInternalServerError)
@@ -222,6 +231,11 @@ func DefaultHandler(method, pattern string, params map[string]int, handlers ...a
 		data.token = token
 		if token != nil && token.Valid {
 			if claims, ok := token.Claims.(*JWTClaims); ok && len(claims.KeyID) > 0 {
+				if len(claims.Id) > 0 && isRevoked(claims.Id) {
+					requestLogger.WithFields(log.Fields{"type": consts.AccessDenied, "session": claims.Id}).Warning("session is revoked")
+					errorAPI(w, `E_TOKENREVOKED`, http.StatusUnauthorized)
+					return
+				}
 				data.ecosystemId = converter.StrToInt64(claims.EcosystemID)
 				data.keyId = converter.StrToInt64(claims.KeyID)
 			}
@@ -276,7 +290,7 @@ func DefaultHandler(method, pattern string, params map[string]int, handlers ...a
 			errorAPI(w, err, http.StatusInternalServerError)
 			return
 		}
-		w.Write(jsonResult)
+		writeResult(w, r, &data, jsonResult)
 	})
 }
 
@@ -0,0 +1,336 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/GenesisKernel/go-genesis/packages/apps"
+	"github.com/GenesisKernel/go-genesis/packages/apps/manifest"
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type exportResult struct {
+	Bundle  *apps.Bundle `json:"bundle"`
+	ForSign string       `json:"forsign"` // the account signs it and passes the signature to export/sign
+}
+
+type importCheckResult struct {
+	Name      string         `json:"name"`
+	KeyID     string         `json:"key_id"`
+	Ecosystem int64          `json:"ecosystem"`
+	Valid     bool           `json:"valid"`
+	Problems  []apps.Problem `json:"problems"`
+	Data      string         `json:"data"` // parameter Data of Import contract
+}
+
+func splitNames(data *apiData, name string) []string {
+	value, _ := data.params[name].(string)
+	if len(value) == 0 {
+		return nil
+	}
+	return strings.Split(value, `,`)
+}
+
+func isFounder(data *apiData) (bool, error) {
+	sp := &model.StateParameter{}
+	sp.SetTablePrefix(converter.Int64ToStr(data.ecosystemId))
+	if _, err := sp.Get(nil, `founder_account`); err != nil {
+		return false, err
+	}
+	return converter.StrToInt64(sp.Value) == data.keyId, nil
+}
+
+func exportApp(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	founder, err := isFounder(data)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting founder")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	if !founder && data.keyId != conf.Config.KeyID {
+		logger.WithFields(log.Fields{"type": consts.AccessDenied, "key_id": data.keyId}).Error("exporting application")
+		return errorAPI(w, `E_PERMISSION`, http.StatusUnauthorized)
+	}
+	appData, err := apps.Export(getPrefix(data), &apps.Selection{
+		Contracts:  splitNames(data, `contracts`),
+		Pages:      splitNames(data, `pages`),
+		Menus:      splitNames(data, `menus`),
+		Blocks:     splitNames(data, `blocks`),
+		Parameters: splitNames(data, `parameters`),
+		Languages:  splitNames(data, `languages`),
+		Tables:     splitNames(data, `tables`),
+		WithData:   data.params[`data`].(int64) == 1,
+	})
+	if err != nil {
+		return errorAPI(w, err, http.StatusBadRequest)
+	}
+	var m *manifest.Manifest
+	name := data.params[`name`].(string)
+	if value := data.params[`manifest`].(string); len(value) > 0 {
+		if m, err = manifest.Parse(value); err != nil {
+			return errorAPI(w, `E_MANIFEST`, http.StatusBadRequest, err.Error())
+		}
+		if len(name) == 0 {
+			name = m.Name
+		}
+	}
+	// the bundle is signed by the exporting account, the node never signs it with its own key
+	key := &model.Key{}
+	found, err := key.SetTablePrefix(data.ecosystemId).Get(data.keyId)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting public key")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	if !found || len(key.PublicKey) == 0 {
+		return errorAPI(w, `E_EMPTYPUBLIC`, http.StatusBadRequest)
+	}
+	bundle := apps.NewUnsignedBundle(name, data.ecosystemId, data.vde, appData, m, key.PublicKey)
+	forSign, err := bundle.ForSign()
+	if err != nil {
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	data.result = &exportResult{Bundle: bundle, ForSign: forSign}
+	return nil
+}
+
+// signExport adds the signature of the exporting account to the bundle returned by export
+func signExport(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	var bundle apps.Bundle
+	if err := json.Unmarshal([]byte(data.params[`bundle`].(string)), &bundle); err != nil {
+		logger.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err}).Error("unmarshalling bundle")
+		return errorAPI(w, `E_BUNDLE`, http.StatusBadRequest, err.Error())
+	}
+	if bundle.KeyID != data.keyId {
+		return errorAPI(w, `E_PERMISSION`, http.StatusUnauthorized)
+	}
+	bundle.Signature = data.params[`signature`].(string)
+	if err := bundle.Verify(); err != nil {
+		logger.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("verifying bundle")
+		return errorAPI(w, `E_BUNDLESIGN`, http.StatusBadRequest)
+	}
+	data.result = &bundle
+	return nil
+}
+
+func checkImport(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	var bundle apps.Bundle
+	if err := json.Unmarshal([]byte(data.params[`bundle`].(string)), &bundle); err != nil {
+		logger.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err}).Error("unmarshalling bundle")
+		return errorAPI(w, `E_BUNDLE`, http.StatusBadRequest, err.Error())
+	}
+	if err := bundle.Verify(); err != nil {
+		logger.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("verifying bundle")
+		return errorAPI(w, `E_BUNDLESIGN`, http.StatusBadRequest)
+	}
+	out, err := json.Marshal(bundle.Data)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Error("marshalling bundle data")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	problems := apps.Check(&bundle.Data, &apps.Target{Ecosystem: data.ecosystemId, VDE: data.vde})
+	data.result = &importCheckResult{
+		Name:      bundle.Name,
+		KeyID:     converter.Int64ToStr(bundle.KeyID),
+		Ecosystem: data.ecosystemId,
+		Valid:     !apps.HasErrors(problems),
+		Problems:  problems,
+		Data:      string(out),
+	}
+	return nil
+}
+
+type appsResult struct {
+	List []model.Application `json:"list"`
+}
+
+// appPlan is the result of the check of installation, upgrade or uninstallation of application
+type appPlan struct {
+	Name      string                 `json:"name"`
+	Version   string                 `json:"version"`
+	Installed string                 `json:"installed,omitempty"`
+	Hooks     []string               `json:"hooks"`
+	Problems  []apps.Problem         `json:"problems"`
+	Valid     bool                   `json:"valid"`
+	Contract  string                 `json:"contract"`
+	Params    map[string]interface{} `json:"params"`
+}
+
+func installedApps(data *apiData) (map[string]model.Application, error) {
+	list, err := model.GetApplications(nil, data.ecosystemId)
+	if err != nil {
+		return nil, err
+	}
+	ret := make(map[string]model.Application)
+	for _, app := range list {
+		ret[app.Name] = app
+	}
+	return ret, nil
+}
+
+func getApps(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	ecosystemID, _, err := checkEcosystem(w, data, logger)
+	if err != nil {
+		return err
+	}
+	list, err := model.GetApplications(nil, ecosystemID)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting applications")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	data.result = &appsResult{List: list}
+	return nil
+}
+
+// planApp checks the bundle of versioned application and returns the parameters of InstallApplication contract.
+// If upgrade is true then the application must be installed
+func planApp(w http.ResponseWriter, data *apiData, logger *log.Entry, upgrade bool) error {
+	var bundle apps.Bundle
+	if err := json.Unmarshal([]byte(data.params[`bundle`].(string)), &bundle); err != nil {
+		logger.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err}).Error("unmarshalling bundle")
+		return errorAPI(w, `E_BUNDLE`, http.StatusBadRequest, err.Error())
+	}
+	if err := bundle.Verify(); err != nil {
+		logger.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("verifying bundle")
+		return errorAPI(w, `E_BUNDLESIGN`, http.StatusBadRequest)
+	}
+	if bundle.Manifest == nil {
+		return errorAPI(w, `E_MANIFEST`, http.StatusBadRequest, `bundle has not manifest`)
+	}
+	m := bundle.Manifest
+	if err := m.Validate(); err != nil {
+		return errorAPI(w, `E_MANIFEST`, http.StatusBadRequest, err.Error())
+	}
+	installed, err := installedApps(data)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting applications")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	current, ok := installed[m.Name]
+	if upgrade != ok {
+		if upgrade {
+			return errorAPI(w, `E_APP`, http.StatusBadRequest, m.Name)
+		}
+		return errorAPI(w, `E_MANIFEST`, http.StatusBadRequest, m.Name+` is already installed, use upgrade`)
+	}
+	hooks, err := m.Hooks(current.Version)
+	if err != nil {
+		return errorAPI(w, `E_MANIFEST`, http.StatusBadRequest, err.Error())
+	}
+	out, err := json.Marshal(bundle.Data)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Error("marshalling bundle data")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	manifestOut, err := json.Marshal(m)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Error("marshalling manifest")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+
+	problems := make([]apps.Problem, 0)
+	if !upgrade {
+		problems = apps.Check(&bundle.Data, &apps.Target{Ecosystem: data.ecosystemId, VDE: data.vde})
+	}
+	versions := make(map[string]string)
+	for name, app := range installed {
+		versions[name] = app.Version
+	}
+	bundleParams := make(map[string]bool)
+	for _, item := range bundle.Data.Parameters {
+		bundleParams[item["Name"]] = true
+	}
+	sp := &model.StateParameter{}
+	sp.SetTablePrefix(converter.Int64ToStr(data.ecosystemId))
+	for _, message := range m.Problems(versions, func(name string) bool {
+		found, err := sp.Get(nil, name)
+		return bundleParams[name] || (found && err == nil)
+	}) {
+		problems = append(problems, apps.Problem{Kind: apps.ProblemDependency, Type: `application`,
+			Name: m.Name, Message: message})
+	}
+	data.result = &appPlan{
+		Name:      m.Name,
+		Version:   m.Version,
+		Installed: current.Version,
+		Hooks:     hooks,
+		Problems:  problems,
+		Valid:     !apps.HasErrors(problems),
+		Contract:  `InstallApplication`,
+		Params:    map[string]interface{}{`Data`: string(out), `Manifest`: string(manifestOut)},
+	}
+	return nil
+}
+
+func installApp(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	return planApp(w, data, logger, false)
+}
+
+func upgradeApp(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	return planApp(w, data, logger, true)
+}
+
+func uninstallApp(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	name := data.params[`name`].(string)
+	installed, err := installedApps(data)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting applications")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	app, ok := installed[name]
+	if !ok {
+		return errorAPI(w, `E_APP`, http.StatusBadRequest, name)
+	}
+	manifests := make([]*manifest.Manifest, 0, len(installed))
+	for _, item := range installed {
+		if m, err := manifest.Parse(item.Manifest); err == nil {
+			manifests = append(manifests, m)
+		}
+	}
+	problems := make([]apps.Problem, 0)
+	for _, dependent := range manifest.Dependents(name, manifests) {
+		problems = append(problems, apps.Problem{Kind: apps.ProblemDependency, Type: `application`,
+			Name: dependent, Message: dependent + ` depends on ` + name})
+	}
+	hooks := make([]string, 0)
+	if m, err := manifest.Parse(app.Manifest); err == nil && len(m.Uninstall) > 0 {
+		hooks = append(hooks, m.Uninstall)
+	}
+	data.result = &appPlan{
+		Name:      name,
+		Version:   app.Version,
+		Installed: app.Version,
+		Hooks:     hooks,
+		Problems:  problems,
+		Valid:     len(problems) == 0,
+		Contract:  `UninstallApplication`,
+		Params:    map[string]interface{}{`Name`: name},
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package api
+
+import (
+	"compress/gzip"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+	"github.com/GenesisKernel/go-genesis/packages/config/syspar"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const archiveDir = `archive`
+
+type archiveResult struct {
+	File   string           `json:"file"`
+	Tables map[string]int64 `json:"tables"`
+}
+
+func archiveFileName(id int64) string {
+	return conf.DataPath(archiveDir, fmt.Sprintf(`ecosystem_%d.jsonl.gz`, id))
+}
+
+// archiveEcosystem exports the tables of archived ecosystem into the file and removes them from the database
+func archiveEcosystem(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	id := converter.StrToInt64(data.params[`id`].(string))
+	state := &model.SystemState{}
+	found, err := state.Get(nil, id)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "ecosystem": id}).Error("getting ecosystem")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	if !found || id <= 1 {
+		return errorAPI(w, `E_ECOSYSTEM`, http.StatusNotFound, id)
+	}
+	block := &model.Block{}
+	if _, err = block.GetMaxBlock(); err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting max block")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	if state.Archived == 0 || block.ID-state.Archived < syspar.GetRbBlocks1() {
+		return errorAPI(w, `E_NOTARCHIVED`, http.StatusBadRequest, id)
+	}
+
+	tables, err := model.GetEcosystemTables(id)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "ecosystem": id}).Error("getting ecosystem tables")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	result := &archiveResult{File: archiveFileName(id), Tables: make(map[string]int64)}
+	if err = os.MkdirAll(filepath.Dir(result.File), 0755); err != nil {
+		logger.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("creating archive directory")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	if err = exportTables(result, tables); err != nil {
+		logger.WithFields(log.Fields{"type": consts.IOError, "error": err, "ecosystem": id}).Error("exporting ecosystem")
+		os.Remove(result.File)
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	if err = model.DropEcosystemTables(tables); err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "ecosystem": id}).Error("dropping ecosystem tables")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	data.result = result
+	return nil
+}
+
+func exportTables(result *archiveResult, tables []string) error {
+	// the file is written aside and renamed so the existing archive is never truncated
+	tmp := result.File + `.tmp`
+	file, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+
+	zw := gzip.NewWriter(file)
+	for _, table := range tables {
+		count, err := model.ExportTable(zw, table)
+		if err != nil {
+			file.Close()
+			return err
+		}
+		result.Tables[table] = count
+	}
+	if err = zw.Close(); err != nil {
+		file.Close()
+		return err
+	}
+	if err = file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err = file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, result.File)
+}
@@ -0,0 +1,142 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const defaultListLimit = 25
+
+type assetsResult struct {
+	List []model.Asset `json:"list"`
+}
+
+type assetBalanceResult struct {
+	Symbol string `json:"symbol"`
+	Amount string `json:"amount"`
+}
+
+type assetHistoryResult struct {
+	List []model.AssetHistory `json:"list"`
+}
+
+func listLimit(data *apiData) int {
+	if limit := data.params[`limit`].(int64); limit > 0 && limit <= 1000 {
+		return int(limit)
+	}
+	return defaultListLimit
+}
+
+// findAsset returns the ecosystem and the asset by the symbol from the request
+func findAsset(w http.ResponseWriter, data *apiData, logger *log.Entry) (int64, *model.Asset, error) {
+	ecosystemID, _, err := checkEcosystem(w, data, logger)
+	if err != nil {
+		return 0, nil, err
+	}
+	asset := &model.Asset{}
+	asset.SetTablePrefix(ecosystemID)
+	found, err := asset.GetBySymbol(nil, data.params[`symbol`].(string))
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting asset")
+		return 0, nil, errorAPI(w, err, http.StatusInternalServerError)
+	}
+	if !found {
+		return 0, nil, errorAPI(w, `E_ASSET`, http.StatusNotFound, data.params[`symbol`].(string))
+	}
+	return ecosystemID, asset, nil
+}
+
+func getAssets(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	ecosystemID, _, err := checkEcosystem(w, data, logger)
+	if err != nil {
+		return err
+	}
+	assets, err := model.GetAssets(ecosystemID, listLimit(data), int(data.params[`offset`].(int64)))
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting assets")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	data.result = &assetsResult{List: assets}
+	return nil
+}
+
+func getAsset(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	_, asset, err := findAsset(w, data, logger)
+	if err != nil {
+		return err
+	}
+	data.result = asset
+	return nil
+}
+
+func getAssetBalance(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	ecosystemID, asset, err := findAsset(w, data, logger)
+	if err != nil {
+		return err
+	}
+	keyID := resolveWallet(ecosystemID, data.params[`wallet`].(string), logger)
+	if keyID == 0 {
+		logger.WithFields(log.Fields{"type": consts.ConversionError, "value": data.params["wallet"].(string)}).Error("converting wallet to address")
+		return errorAPI(w, `E_INVALIDWALLET`, http.StatusBadRequest, data.params[`wallet`].(string))
+	}
+	balance := &model.AssetBalance{}
+	balance.SetTablePrefix(ecosystemID)
+	found, err := balance.Get(nil, asset.ID, keyID)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting asset balance")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	result := &assetBalanceResult{Symbol: asset.Symbol, Amount: `0`}
+	if found {
+		result.Amount = balance.Amount
+	}
+	data.result = result
+	return nil
+}
+
+func getAssetHistory(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	ecosystemID, asset, err := findAsset(w, data, logger)
+	if err != nil {
+		return err
+	}
+	var keyID int64
+	if wallet := data.params[`wallet`].(string); len(wallet) > 0 {
+		if keyID = resolveWallet(ecosystemID, wallet, logger); keyID == 0 {
+			logger.WithFields(log.Fields{"type": consts.ConversionError, "value": wallet}).Error("converting wallet to address")
+			return errorAPI(w, `E_INVALIDWALLET`, http.StatusBadRequest, wallet)
+		}
+	}
+	history, err := model.GetAssetHistory(ecosystemID, asset.ID, keyID, listLimit(data), int(data.params[`offset`].(int64)))
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting asset history")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	data.result = &assetHistoryResult{List: history}
+	return nil
+}
@@ -0,0 +1,111 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package api
+
+import (
+	"encoding/hex"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/crypto"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type auditItem struct {
+	ID        string `json:"id"`
+	Time      string `json:"time"`
+	KeyID     string `json:"key_id"`
+	Ecosystem string `json:"ecosystem"`
+	Action    string `json:"action"`
+	Target    string `json:"target"`
+	Remote    string `json:"remote"`
+	Digest    string `json:"digest"`
+}
+
+type auditResult struct {
+	List []auditItem `json:"list"`
+}
+
+// requestDigest returns the hash of the method, the path and the sorted form values of the request
+func requestDigest(r *http.Request) []byte {
+	keys := make([]string, 0, len(r.Form))
+	for key := range r.Form {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	list := []string{r.Method, r.URL.Path}
+	for _, key := range keys {
+		list = append(list, key+`=`+strings.Join(r.Form[key], `,`))
+	}
+	digest, _ := crypto.Hash([]byte(strings.Join(list, "\n")))
+	return digest
+}
+
+func writeAudit(r *http.Request, keyID, ecosystemID int64, action string, logger *log.Entry) {
+	record := &model.AuditLog{
+		Time:      time.Now().Unix(),
+		KeyID:     keyID,
+		Ecosystem: ecosystemID,
+		Action:    action,
+		Target:    r.URL.Path,
+		Remote:    r.RemoteAddr,
+		Digest:    requestDigest(r),
+	}
+	if err := record.Create(nil); err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("writing audit log")
+	}
+}
+
+func getAudit(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	limit := data.params[`limit`].(int64)
+	if limit <= 0 {
+		limit = 25
+	}
+	list, err := model.GetAuditLog(data.params[`key_id`].(int64), data.params[`action`].(string),
+		data.params[`offset`].(int64), limit)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting audit log")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	result := auditResult{List: make([]auditItem, 0, len(list))}
+	for _, item := range list {
+		result.List = append(result.List, auditItem{
+			ID:        converter.Int64ToStr(item.ID),
+			Time:      converter.Int64ToStr(item.Time),
+			KeyID:     converter.Int64ToStr(item.KeyID),
+			Ecosystem: converter.Int64ToStr(item.Ecosystem),
+			Action:    item.Action,
+			Target:    item.Target,
+			Remote:    item.Remote,
+			Digest:    hex.EncodeToString(item.Digest),
+		})
+	}
+	data.result = &result
+	return nil
+}
@@ -27,8 +27,11 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/GenesisKernel/go-genesis/packages/conf"
 	"github.com/GenesisKernel/go-genesis/packages/consts"
 	"github.com/GenesisKernel/go-genesis/packages/crypto"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/smart"
 
 	"github.com/dgrijalva/jwt-go"
 	log "github.com/sirupsen/logrus"
@@ -77,6 +80,36 @@ func authWallet(w http.ResponseWriter, r *http.Request, data *apiData, logger *l
 	return nil
 }
 
+// authContent allows the requests without token if the host of request is the domain of ecosystem.
+// Such requests get the content of the ecosystem or its VDE which the domain is bound to
+func authContent(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	if data.keyId != 0 {
+		return nil
+	}
+	host, err := smart.NormalizeHost(r.Host)
+	if err != nil {
+		return errorAPI(w, `E_UNAUTHORIZED`, http.StatusUnauthorized)
+	}
+	domain := &model.Domain{}
+	found, err := domain.GetByHost(nil, host)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting domain")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	if !found || domain.Ecosystem == 0 {
+		logger.WithFields(log.Fields{"type": consts.NotFound, "host": host}).Error("domain not found")
+		return errorAPI(w, `E_UNAUTHORIZED`, http.StatusUnauthorized)
+	}
+	data.ecosystemId = domain.Ecosystem
+	data.domain = domain
+	data.vde = domain.VDE != 0
+	data.vm = smart.GetVM(data.vde, data.ecosystemId)
+	if data.vm == nil {
+		return errorAPI(w, `E_VDE`, http.StatusBadRequest, data.ecosystemId)
+	}
+	return nil
+}
+
 func authState(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
 	if data.keyId == 0 || data.ecosystemId <= 1 {
 		logger.WithFields(log.Fields{"type": consts.EmptyObject}).Error("state is empty")
@@ -84,3 +117,12 @@ func authState(w http.ResponseWriter, r *http.Request, data *apiData, logger *lo
 	}
 	return nil
 }
+
+func authAdmin(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	if data.keyId == 0 || data.keyId != conf.Config.KeyID {
+		logger.WithFields(log.Fields{"type": consts.AccessDenied, "key_id": data.keyId}).Error("admin access denied")
+		return errorAPI(w, `E_PERMISSION`, http.StatusForbidden)
+	}
+	writeAudit(r, data.keyId, data.ecosystemId, model.AuditAdmin, logger)
+	return nil
+}
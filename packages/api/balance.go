@@ -42,7 +42,7 @@ func balance(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.
 	if err != nil {
 		return err
 	}
-	keyID := converter.StringToAddress(data.params[`wallet`].(string))
+	keyID := resolveWallet(ecosystemId, data.params[`wallet`].(string), logger)
 	if keyID == 0 {
 		logger.WithFields(log.Fields{"type": consts.ConversionError, "value": data.params["wallet"].(string)}).Error("converting wallet to address")
 		return errorAPI(w, `E_INVALIDWALLET`, http.StatusBadRequest, data.params[`wallet`].(string))
@@ -0,0 +1,85 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	hr "github.com/julienschmidt/httprouter"
+	log "github.com/sirupsen/logrus"
+)
+
+type binariesResult struct {
+	List []model.Binary `json:"list"`
+}
+
+func getAppBinaries(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	ecosystemID, _, err := checkEcosystem(w, data, logger)
+	if err != nil {
+		return err
+	}
+	list, err := model.GetBinaries(ecosystemID, data.params[`app`].(string))
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting assets")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	data.result = &binariesResult{List: list}
+	return nil
+}
+
+// assetHandler returns the static asset of the application. The asset can be cached forever
+// if the request contains its version, otherwise the client has to revalidate it by ETag
+func assetHandler() hr.Handle {
+	return hr.Handle(func(w http.ResponseWriter, r *http.Request, ps hr.Params) {
+		binary := &model.Binary{}
+		binary.SetTablePrefix(converter.StrToInt64(ps.ByName(`ecosystem`)))
+		found, err := binary.Get(nil, ps.ByName(`app`), strings.TrimPrefix(ps.ByName(`name`), `/`))
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting asset")
+		}
+		if !found {
+			errorAPI(w, `E_NOTFOUND`, http.StatusNotFound)
+			return
+		}
+		etag := fmt.Sprintf(`"%s"`, binary.Checksum)
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		if r.FormValue(`v`) == binary.Version {
+			w.Header().Set("Cache-Control", "public,max-age=31536000,immutable")
+		} else {
+			w.Header().Set("Cache-Control", "public,max-age=300,must-revalidate")
+		}
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", binary.MimeType)
+		w.Write(binary.Data)
+	})
+}
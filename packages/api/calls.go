@@ -0,0 +1,99 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package api
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type callItem struct {
+	Hash       string `json:"hash"`
+	Contract   string `json:"contract"`
+	Ecosystem  string `json:"ecosystem"`
+	ParamsHash string `json:"params_hash"`
+	BlockID    string `json:"block_id"`
+	Time       string `json:"time"`
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+}
+
+type callsResult struct {
+	List []callItem `json:"list"`
+}
+
+// callContractName returns the full name of the contract, the ecosystem of the request is used by default
+func callContractName(name string, ecosystemID int64) string {
+	if len(name) == 0 || strings.HasPrefix(name, `@`) {
+		return name
+	}
+	return fmt.Sprintf(`@%d%s`, ecosystemID, name)
+}
+
+func getContractCalls(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	keyID := data.keyId
+	if wallet := data.params[`wallet`].(string); len(wallet) > 0 {
+		if keyID = resolveWallet(data.ecosystemId, wallet, logger); keyID == 0 {
+			logger.WithFields(log.Fields{"type": consts.ConversionError, "value": wallet}).Error("converting wallet to address")
+			return errorAPI(w, `E_INVALIDWALLET`, http.StatusBadRequest, wallet)
+		}
+	}
+	filter := model.ContractCallFilter{
+		KeyID:    keyID,
+		Contract: callContractName(data.params[`contract`].(string), data.ecosystemId),
+		From:     data.params[`from`].(int64),
+		To:       data.params[`to`].(int64),
+	}
+	list, err := model.GetContractCalls(filter, data.params[`offset`].(int64), int64(listLimit(data)))
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting contract calls")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	result := callsResult{List: make([]callItem, 0, len(list))}
+	for _, item := range list {
+		status := `success`
+		if item.Status == model.CallFailed {
+			status = `failed`
+		}
+		result.List = append(result.List, callItem{
+			Hash:       hex.EncodeToString(item.Hash),
+			Contract:   item.Contract,
+			Ecosystem:  converter.Int64ToStr(item.Ecosystem),
+			ParamsHash: hex.EncodeToString(item.ParamsHash),
+			BlockID:    converter.Int64ToStr(item.BlockID),
+			Time:       converter.Int64ToStr(item.Time),
+			Status:     status,
+			Error:      item.Error,
+		})
+	}
+	data.result = &result
+	return nil
+}
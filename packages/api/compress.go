@@ -0,0 +1,110 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package api
+
+import (
+	"compress/gzip"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// minGzipSize is the minimal size of the response which is compressed
+const minGzipSize = 1024
+
+var gzipWriters = sync.Pool{
+	New: func() interface{} {
+		return gzip.NewWriter(nil)
+	},
+}
+
+// withETag marks the response of the route to be sent with ETag so clients can revalidate it with If-None-Match
+func withETag(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	data.etag = true
+	return nil
+}
+
+func responseETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	// the tag is weak because the same response can be sent compressed or not
+	return fmt.Sprintf(`W/"%x"`, sum[:16])
+}
+
+// matchETag reports whether the value of If-None-Match header matches the tag
+func matchETag(header, tag string) bool {
+	if len(header) == 0 {
+		return false
+	}
+	tag = strings.TrimPrefix(tag, `W/`)
+	for _, item := range strings.Split(header, `,`) {
+		item = strings.TrimSpace(item)
+		if item == `*` || strings.TrimPrefix(item, `W/`) == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func acceptsGzip(r *http.Request) bool {
+	for _, item := range strings.Split(r.Header.Get(`Accept-Encoding`), `,`) {
+		item = strings.TrimSpace(item)
+		if i := strings.IndexByte(item, ';'); i >= 0 {
+			if strings.TrimSpace(item[i+1:]) == `q=0` {
+				continue
+			}
+			item = strings.TrimSpace(item[:i])
+		}
+		if item == `gzip` || item == `*` {
+			return true
+		}
+	}
+	return false
+}
+
+// writeResult sends the response compressing it if the client accepts gzip
+func writeResult(w http.ResponseWriter, r *http.Request, data *apiData, body []byte) {
+	if data.etag {
+		tag := responseETag(body)
+		w.Header().Set(`ETag`, tag)
+		w.Header().Add(`Access-Control-Expose-Headers`, `ETag`)
+		if matchETag(r.Header.Get(`If-None-Match`), tag) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+	w.Header().Add(`Vary`, `Accept-Encoding`)
+	if len(body) < minGzipSize || !acceptsGzip(r) {
+		w.Write(body)
+		return
+	}
+	w.Header().Set(`Content-Encoding`, `gzip`)
+	gz := gzipWriters.Get().(*gzip.Writer)
+	gz.Reset(w)
+	gz.Write(body)
+	gz.Close()
+	gzipWriters.Put(gz)
+}
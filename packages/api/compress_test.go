@@ -0,0 +1,85 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package api
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWriteResult(t *testing.T) {
+	body := []byte(`{"value":"` + strings.Repeat(`a`, minGzipSize) + `"}`)
+
+	r := httptest.NewRequest(`GET`, `/api/v2/list/contracts`, nil)
+	r.Header.Set(`Accept-Encoding`, `deflate, gzip;q=0.8`)
+	w := httptest.NewRecorder()
+	writeResult(w, r, &apiData{etag: true}, body)
+	if w.Header().Get(`Content-Encoding`) != `gzip` {
+		t.Fatal(`response is not compressed`)
+	}
+	gz, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := ioutil.ReadAll(gz)
+	if err != nil || string(out) != string(body) {
+		t.Errorf(`wrong uncompressed body %v`, err)
+	}
+	tag := w.Header().Get(`ETag`)
+	if len(tag) == 0 {
+		t.Fatal(`ETag is empty`)
+	}
+
+	r = httptest.NewRequest(`GET`, `/api/v2/list/contracts`, nil)
+	r.Header.Set(`If-None-Match`, tag)
+	w = httptest.NewRecorder()
+	writeResult(w, r, &apiData{etag: true}, body)
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Errorf(`wrong response for matched ETag %d`, w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	writeResult(w, r, &apiData{}, []byte(`{}`))
+	if w.Code != http.StatusOK || w.Body.String() != `{}` || len(w.Header().Get(`ETag`)) > 0 {
+		t.Errorf(`wrong plain response %d %s`, w.Code, w.Body.String())
+	}
+}
+
+func TestMatchETag(t *testing.T) {
+	for header, match := range map[string]bool{
+		``:               false,
+		`*`:              true,
+		`"abc"`:          true,
+		`W/"abc"`:        true,
+		`"def", W/"abc"`: true,
+		`"def"`:          false,
+	} {
+		if matchETag(header, `W/"abc"`) != match {
+			t.Errorf(`wrong match of %s`, header)
+		}
+	}
+}
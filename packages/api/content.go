@@ -113,11 +113,12 @@ func getPage(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.
 	}()
 	go func() {
 		defer wg.Done()
-		if conf.Config.MaxPageGenerationTime == 0 {
+		maxTime := conf.MaxPageGenerationTime()
+		if maxTime == 0 {
 			return
 		}
 		select {
-		case <-time.After(time.Duration(conf.Config.MaxPageGenerationTime) * time.Millisecond):
+		case <-time.After(time.Duration(maxTime) * time.Millisecond):
 			timeout = true
 		case <-success:
 		}
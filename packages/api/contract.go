@@ -31,6 +31,7 @@ import (
 	"github.com/GenesisKernel/go-genesis/packages/consts"
 	"github.com/GenesisKernel/go-genesis/packages/converter"
 	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/parser"
 	"github.com/GenesisKernel/go-genesis/packages/script"
 	"github.com/GenesisKernel/go-genesis/packages/utils/tx"
 
@@ -143,11 +144,13 @@ func contract(w http.ResponseWriter, r *http.Request, data *apiData, logger *log
 	toSerialize = tx.SmartContract{
 		Header: tx.Header{Type: int(info.ID), Time: converter.StrToInt64(data.params[`time`].(string)),
 			EcosystemID: data.ecosystemId, KeyID: data.keyId, PublicKey: publicKey,
-			BinSignatures: converter.EncodeLengthPlusData(signature)},
+			BinSignatures: converter.EncodeLengthPlusData(signature), Nonce: data.params[`nonce`].(int64),
+			ExpireBlock: data.params[`expire_block`].(int64), ExpireTime: data.params[`expire_time`].(int64)},
 		TokenEcosystem: data.params[`token_ecosystem`].(int64),
 		MaxSum:         data.params[`max_sum`].(string),
 		PayOver:        data.params[`payover`].(string),
 		SignedBy:       signedBy,
+		MaxFee:         data.params[`max_fee`].(string),
 		Data:           idata,
 	}
 	serializedData, err := msgpack.Marshal(toSerialize)
@@ -163,10 +166,14 @@ func contract(w http.ResponseWriter, r *http.Request, data *apiData, logger *log
 		data.result = ret
 		return nil
 	}
-	if hash, err = model.SendTx(int64(info.ID), data.keyId,
-		append([]byte{128}, serializedData...)); err != nil {
+	if err = parser.CheckTxSizeByType(info.Name, int64(len(serializedData)+1)); err != nil {
+		return errorTxSize(w, err.(*parser.TxSizeError))
+	}
+	if hash, err = model.SendExpiringTx(int64(info.ID), data.keyId, data.params[`expire_block`].(int64),
+		data.params[`expire_time`].(int64), append([]byte{128}, serializedData...), data.requestID); err != nil {
 		return errorAPI(w, err, http.StatusInternalServerError)
 	}
+	watchTx(data, hash)
 	data.result = &contractResult{Hash: hex.EncodeToString(hash)}
 	return nil
 }
@@ -31,7 +31,9 @@ import (
 	"strings"
 
 	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
 	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/thumbnail"
 
 	hr "github.com/julienschmidt/httprouter"
 	log "github.com/sirupsen/logrus"
@@ -42,62 +44,117 @@ const (
 	base64header     = "base64,"
 )
 
-var base64regexp = regexp.MustCompile(`(?is)^data:([a-z0-9-]+\/[a-z0-9-]+);base64,$`)
+const thumbnailCacheSize = 32 << 20
 
-func dataHandler() hr.Handle {
-	return hr.Handle(func(w http.ResponseWriter, r *http.Request, ps hr.Params) {
-		tblname := ps.ByName("table")
-		column := ps.ByName("column")
+var (
+	base64regexp = regexp.MustCompile(`(?is)^data:([a-z0-9-]+\/[a-z0-9-]+);base64,$`)
+	thumbnails   = thumbnail.NewCache(thumbnailCacheSize)
+)
 
-		data, err := model.GetColumnByID(tblname, column, ps.ByName(`id`))
-		if err != nil {
-			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("selecting data from table")
-			errorAPI(w, `E_NOTFOUND`, http.StatusNotFound)
-			return
-		}
+// SetThumbnailCacheSize changes the limit of the cache of thumbnails
+func SetThumbnailCacheSize(maxBytes int) {
+	thumbnails.SetMaxBytes(maxBytes)
+}
 
-		if fmt.Sprintf(`%x`, md5.Sum([]byte(data))) != strings.ToLower(ps.ByName(`hash`)) {
-			log.WithFields(log.Fields{"type": consts.InvalidObject, "error": fmt.Errorf("wrong hash")}).Error("wrong hash")
-			errorAPI(w, `E_NOTFOUND`, http.StatusNotFound)
-			return
-		}
+// getBinaryData returns the mime type and the decoded content of the column,
+// the mime type is empty if the column doesn't contain base64 encoded data
+func getBinaryData(w http.ResponseWriter, ps hr.Params) (string, []byte, bool) {
+	tblname := ps.ByName("table")
+	column := ps.ByName("column")
 
-		columnType, err := model.GetColumnType(tblname, column)
-		if err != nil {
-			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("selecting column type")
-			errorAPI(w, `E_NOTFOUND`, http.StatusNotFound)
-			return
-		}
+	data, err := model.GetColumnByID(tblname, column, ps.ByName(`id`))
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("selecting data from table")
+		errorAPI(w, `E_NOTFOUND`, http.StatusNotFound)
+		return ``, nil, false
+	}
 
-		if columnType != base64columnType {
-			w.Header().Set("Content-Type", "text/plain")
-			w.Header().Set("Access-Control-Allow-Origin", "*")
-			w.Write([]byte(data))
-			return
-		}
+	if fmt.Sprintf(`%x`, md5.Sum([]byte(data))) != strings.ToLower(ps.ByName(`hash`)) {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "error": fmt.Errorf("wrong hash")}).Error("wrong hash")
+		errorAPI(w, `E_NOTFOUND`, http.StatusNotFound)
+		return ``, nil, false
+	}
+
+	columnType, err := model.GetColumnType(tblname, column)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("selecting column type")
+		errorAPI(w, `E_NOTFOUND`, http.StatusNotFound)
+		return ``, nil, false
+	}
+
+	if columnType != base64columnType {
+		return ``, []byte(data), true
+	}
 
-		offset := strings.Index(data, base64header)
-		if offset == -1 {
-			log.WithFields(log.Fields{"type": consts.InvalidObject, "error": fmt.Errorf("wrong data")}).Error("wrong data")
-			errorAPI(w, `E_NOTFOUND`, http.StatusNotFound)
+	offset := strings.Index(data, base64header)
+	if offset == -1 {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "error": fmt.Errorf("wrong data")}).Error("wrong data")
+		errorAPI(w, `E_NOTFOUND`, http.StatusNotFound)
+		return ``, nil, false
+	}
+	ret := base64regexp.FindStringSubmatch(data[:offset+len(base64header)])
+	if len(ret) != 2 {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "error": fmt.Errorf("wrong data")}).Error("wrong data")
+		errorAPI(w, `E_NOTFOUND`, http.StatusNotFound)
+		return ``, nil, false
+	}
+
+	bin, err := base64.StdEncoding.DecodeString(data[offset+len(base64header):])
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.ConversionError, "error": err}).Error("encoding base64")
+		errorAPI(w, `E_NOTFOUND`, http.StatusNotFound)
+		return ``, nil, false
+	}
+	return ret[1], bin, true
+}
+
+func dataHandler() hr.Handle {
+	return hr.Handle(func(w http.ResponseWriter, r *http.Request, ps hr.Params) {
+		datatype, bin, ok := getBinaryData(w, ps)
+		if !ok {
 			return
 		}
-		ret := base64regexp.FindStringSubmatch(data[:offset+len(base64header)])
-		if len(ret) != 2 {
-			log.WithFields(log.Fields{"type": consts.InvalidObject, "error": fmt.Errorf("wrong data")}).Error("wrong data")
-			errorAPI(w, `E_NOTFOUND`, http.StatusNotFound)
+		if len(datatype) == 0 {
+			w.Header().Set("Content-Type", "text/plain")
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+			w.Write(bin)
 			return
 		}
-
-		datatype := ret[1]
-		bin, err := base64.StdEncoding.DecodeString(data[offset+len(base64header):])
-		if err != nil {
-			log.WithFields(log.Fields{"type": consts.ConversionError, "error": err}).Error("encoding base64")
-			errorAPI(w, `E_NOTFOUND`, http.StatusNotFound)
-		}
 		w.Header().Set("Content-Type", datatype)
 		w.Header().Set("Cache-Control", "public,max-age=604800,immutable")
 		w.Write(bin)
 		return
 	})
 }
+
+// thumbnailHandler returns the reduced copy of the image which fits width x height
+func thumbnailHandler() hr.Handle {
+	return hr.Handle(func(w http.ResponseWriter, r *http.Request, ps hr.Params) {
+		width := converter.StrToInt(r.FormValue(`width`))
+		height := converter.StrToInt(r.FormValue(`height`))
+		key := fmt.Sprintf(`%s/%s/%s/%s/%dx%d`, ps.ByName(`table`), ps.ByName(`id`), ps.ByName(`column`),
+			strings.ToLower(ps.ByName(`hash`)), width, height)
+
+		out, mimeType, ok := thumbnails.Get(key)
+		if !ok {
+			datatype, bin, ok := getBinaryData(w, ps)
+			if !ok {
+				return
+			}
+			if !strings.HasPrefix(datatype, `image/`) {
+				errorAPI(w, `E_THUMBNAIL`, http.StatusBadRequest, `data is not an image`)
+				return
+			}
+			var err error
+			if out, mimeType, err = thumbnail.Make(bin, width, height); err != nil {
+				log.WithFields(log.Fields{"type": consts.InvalidObject, "error": err}).Error("making thumbnail")
+				errorAPI(w, `E_THUMBNAIL`, http.StatusBadRequest, err.Error())
+				return
+			}
+			thumbnails.Set(key, out, mimeType)
+		}
+		w.Header().Set("Content-Type", mimeType)
+		w.Header().Set("Cache-Control", "public,max-age=604800,immutable")
+		w.Write(out)
+	})
+}
@@ -24,7 +24,14 @@ package api
 
 var (
 	apiErrors = map[string]string{
+		`E_BADTX`:         `Transaction is invalid: %s`,
+		`E_BUNDLE`:        `Bundle is invalid: %s`,
+		`E_BUNDLESIGN`:    `Signature of bundle is incorrect`,
 		`E_CONTRACT`:      `There is not %s contract`,
+		`E_CONSOLE`:       `Console error: %s`,
+		`E_ASSET`:         `Asset %s has not been found`,
+		`E_APP`:           `Application %s is not installed`,
+		`E_AUTHPROVIDER`:  `Identity provider %s is not enabled`,
 		`E_DBNIL`:         `DB is nil`,
 		`E_ECOSYSTEM`:     `Ecosystem %d doesn't exist`,
 		`E_EMPTYPUBLIC`:   `Public key is undefined`,
@@ -32,24 +39,39 @@ var (
 		`E_HASHWRONG`:     `Hash is incorrect`,
 		`E_HASHNOTFOUND`:  `Hash has not been found`,
 		`E_HEAVYPAGE`:     `This page is heavy`,
+		`E_IDENTITY`:      `Identity %s is not bound to a key of ecosystem %d`,
 		`E_INSTALLED`:     `Apla is already installed`,
 		`E_INVALIDWALLET`: `Wallet %s is not valid`,
+		`E_LIMITTXSIZE`:   `Size %d of %s transaction exceeds limit %d`,
+		`E_MAINTENANCE`:   `Node is in maintenance mode: %s`,
+		`E_MANIFEST`:      `Manifest is invalid: %s`,
+		`E_NAME`:          `Name %s has not been found`,
+		`E_NFT`:           `NFT %s has not been found`,
 		`E_NOTFOUND`:      `Page not found`,
 		`E_NOTINSTALLED`:  `Apla is not installed`,
+		`E_NOTARCHIVED`:   `Ecosystem %d is not archived`,
+		`E_ORACLE`:        `Oracle data is invalid: %s`,
 		`E_PERMISSION`:    `Permission denied`,
 		`E_QUERY`:         `DB query is wrong`,
 		`E_RECOVERED`:     `API recovered`,
 		`E_REFRESHTOKEN`:  `Refresh token is not valid`,
+		`E_ROWNOTFOUND`:   `Row %s has not been found`,
 		`E_SERVER`:        `Server error`,
+		`E_SESSION`:       `Session %s has not been found`,
 		`E_SIGNATURE`:     `Signature is incorrect`,
 		`E_UNKNOWNSIGN`:   `Unknown signature`,
 		`E_STATELOGIN`:    `%s is not a membership of ecosystem %s`,
 		`E_TABLENOTFOUND`: `Table %s has not been found`,
+		`E_THUMBNAIL`:     `Unable to make thumbnail: %s`,
 		`E_TOKEN`:         `Token is not valid`,
 		`E_TOKENEXPIRED`:  `Token is expired by %s`,
+		`E_TOKENREVOKED`:  `Token is revoked`,
+		`E_TXNOTPENDING`:  `Transaction %s is not pending`,
 		`E_UNAUTHORIZED`:  `Unauthorized`,
 		`E_UNDEFINEVAL`:   `Value %s is undefined`,
 		`E_UNKNOWNUID`:    `Unknown uid`,
+		`E_UNKNOWNACTION`: `Unknown action %s`,
+		`E_UNKNOWNDAEMON`: `Unknown daemon %s`,
 		`E_VDE`:           `Virtual Dedicated Ecosystem %d doesn't exist`,
 		`E_VDECREATED`:    `Virtual Dedicated Ecosystem is already created`,
 	}
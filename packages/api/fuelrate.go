@@ -0,0 +1,63 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/parser"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type fuelRateResult struct {
+	FuelRate    string `json:"fuel_rate"`
+	PayOver     string `json:"payover"`
+	Recommended string `json:"recommended"`
+	Fullness    string `json:"fullness"`
+	Queue       string `json:"queue"`
+}
+
+func getFuelRate(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	ecosystem := data.params[`ecosystem`].(int64)
+	if ecosystem == 0 {
+		ecosystem = 1
+	}
+	fee, err := parser.EstimateFee(ecosystem)
+	if err == parser.ErrUnknownFuelRate {
+		return errorAPI(w, err, http.StatusBadRequest)
+	}
+	if err != nil {
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	data.result = &fuelRateResult{
+		FuelRate:    fee.FuelRate.String(),
+		PayOver:     fee.PayOver.String(),
+		Recommended: fee.Rate().String(),
+		Fullness:    strconv.FormatFloat(fee.Fullness, 'f', 4, 64),
+		Queue:       converter.Int64ToStr(fee.Queue),
+	}
+	return nil
+}
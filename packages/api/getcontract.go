@@ -42,6 +42,7 @@ type contractField struct {
 }
 
 type getContractResult struct {
+	ID       uint32          `json:"id"`
 	StateID  uint32          `json:"state"`
 	Active   bool            `json:"active"`
 	TableID  string          `json:"tableid"`
@@ -63,7 +64,7 @@ func getContract(w http.ResponseWriter, r *http.Request, data *apiData, logger *
 	}
 	info := (*contract).Block.Info.(*script.ContractInfo)
 	fields := make([]contractField, 0)
-	result = getContractResult{Name: info.Name, StateID: info.Owner.StateID,
+	result = getContractResult{ID: info.ID, Name: info.Name, StateID: info.Owner.StateID,
 		Active: info.Owner.Active, TableID: converter.Int64ToStr(info.Owner.TableID),
 		WalletID: converter.Int64ToStr(info.Owner.WalletID),
 		TokenID:  converter.Int64ToStr(info.Owner.TokenID),
@@ -23,10 +23,14 @@
 package api
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"net/http"
+	"sort"
+	"strings"
 
 	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
 	"github.com/GenesisKernel/go-genesis/packages/model"
 	log "github.com/sirupsen/logrus"
 )
@@ -38,8 +42,27 @@ type historyResult struct {
 }
 
 func getHistory(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
-	table := getPrefix(data) + "_" + data.params["table"].(string)
+	name := data.params["table"].(string)
+	table := getPrefix(data) + "_" + name
 	id := data.params["id"].(string)
+	where, params, err := rowReadFilter(data, name)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.InvalidObject, "error": err, "table": name}).Error("getting row condition")
+		return errorAPI(w, err, http.StatusBadRequest)
+	}
+	// the history of the row which is hidden by row_read is empty as the history of unknown row
+	if len(where) > 0 {
+		visible, err := model.GetOneRow(`SELECT id FROM `+converter.EscapeName(table)+` WHERE id = ? AND `+where,
+			append([]interface{}{id}, params...)...).String()
+		if err != nil {
+			logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "table": name, "id": id}).Error("getting one row")
+			return errorAPI(w, `E_QUERY`, http.StatusInternalServerError)
+		}
+		if len(visible) == 0 {
+			data.result = &historyResult{[]map[string]string{}}
+			return nil
+		}
+	}
 	rollbackTx := &model.RollbackTx{}
 	txs, err := rollbackTx.GetRollbackTxsByTableIDAndTableName(id, table, rollbackHistoryLimit)
 	if err != nil {
@@ -61,3 +84,125 @@ func getHistory(w http.ResponseWriter, r *http.Request, data *apiData, logger *l
 	data.result = &historyResult{rollbackList}
 	return nil
 }
+
+type columnChange struct {
+	Column string `json:"column"`
+	Old    string `json:"old"`
+	New    string `json:"new"`
+}
+
+type historyChange struct {
+	BlockID  string         `json:"block_id"`
+	TxHash   string         `json:"tx_hash"`
+	Inserted bool           `json:"inserted,omitempty"`
+	Changes  []columnChange `json:"changes"`
+}
+
+type historyDiffResult struct {
+	List []historyChange `json:"list"`
+}
+
+// rowSnapshot returns the previous values of the changed columns of the row from the rollback record,
+// nil means that the row has been inserted by the transaction
+func rowSnapshot(tx model.RollbackTx, id string) (map[string]string, error) {
+	if tx.TableID != model.BatchTableID {
+		if len(tx.Data) == 0 {
+			return nil, nil
+		}
+		values := make(map[string]string)
+		err := json.Unmarshal([]byte(tx.Data), &values)
+		return values, err
+	}
+	var items []model.RollbackBatchItem
+	if err := json.Unmarshal([]byte(tx.Data), &items); err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if item.ID == id {
+			return item.Data, nil
+		}
+	}
+	return nil, nil
+}
+
+// diffHistory reconstructs the changes of the row from its rollback records in chronological order.
+// The new value of the column is the previous value in the next record which changes it or the current value
+func diffHistory(txs []model.RollbackTx, id string, current map[string]string) ([]historyChange, error) {
+	snapshots := make([]map[string]string, len(txs))
+	for i, tx := range txs {
+		var err error
+		if snapshots[i], err = rowSnapshot(tx, id); err != nil {
+			return nil, err
+		}
+	}
+	newValue := func(i int, column string) string {
+		for _, next := range snapshots[i+1:] {
+			if value, ok := next[column]; ok {
+				return value
+			}
+		}
+		return current[column]
+	}
+	ret := make([]historyChange, 0, len(txs))
+	for i, tx := range txs {
+		change := historyChange{BlockID: converter.Int64ToStr(tx.BlockID), TxHash: hex.EncodeToString(tx.TxHash),
+			Inserted: snapshots[i] == nil, Changes: make([]columnChange, 0)}
+		old := snapshots[i]
+		if change.Inserted {
+			old = make(map[string]string, len(current))
+			for column := range current {
+				old[column] = ``
+			}
+		}
+		for column, value := range old {
+			if column == `id` {
+				continue
+			}
+			if next := newValue(i, column); next != value {
+				change.Changes = append(change.Changes, columnChange{Column: column, Old: value, New: next})
+			}
+		}
+		sort.Slice(change.Changes, func(a, b int) bool { return change.Changes[a].Column < change.Changes[b].Column })
+		ret = append(ret, change)
+	}
+	return ret, nil
+}
+
+func getHistoryDiff(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	name := data.params[`table`].(string)
+	table := converter.EscapeName(getPrefix(data) + `_` + name)
+	id := data.params[`id`].(string)
+	where, params, err := rowReadFilter(data, name)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.InvalidObject, "error": err, "table": name}).Error("getting row condition")
+		return errorAPI(w, err, http.StatusBadRequest)
+	}
+	if len(where) > 0 {
+		where = ` AND ` + where
+	}
+	current, err := model.GetOneRow(`SELECT * FROM `+table+` WHERE id = ?`+where,
+		append([]interface{}{id}, params...)...).String()
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "table": name, "id": id}).Error("getting one row")
+		return errorAPI(w, `E_QUERY`, http.StatusInternalServerError)
+	}
+	if len(current) == 0 {
+		return errorAPI(w, `E_ROWNOTFOUND`, http.StatusNotFound, id)
+	}
+	limit := rollbackHistoryLimit
+	if value := data.params[`limit`].(int64); value > 0 && value < int64(limit) {
+		limit = int(value)
+	}
+	txs, err := model.GetRowHistory(strings.Trim(table, `"`), id, limit)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("rollback history")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	list, err := diffHistory(txs, id, current)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err}).Error("unmarshalling rollback data from JSON")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	data.result = &historyDiffResult{List: list}
+	return nil
+}
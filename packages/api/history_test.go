@@ -24,7 +24,12 @@ package api
 
 import (
 	stdErrors "errors"
+	"fmt"
+	"net/url"
+	"reflect"
 	"testing"
+
+	"github.com/GenesisKernel/go-genesis/packages/model"
 )
 
 func TestHistory(t *testing.T) {
@@ -52,3 +57,78 @@ func TestHistory(t *testing.T) {
 		t.Error(stdErrors.New("History should be empty"))
 	}
 }
+
+func TestHistoryRowRead(t *testing.T) {
+	if err := keyLogin(1); err != nil {
+		t.Error(err)
+		return
+	}
+	name := randName(`hist`)
+	form := url.Values{"Name": {name}, "Columns": {`[{"name":"owner","type":"number","index":"0","conditions":"true"},
+	{"name":"info","type":"varchar","index":"0","conditions":"true"}]`},
+		"Permissions": {`{"insert": "true", "update": "true", "new_column": "true", "row_read": "owner == $key_id"}`}}
+	if err := postTx(`NewTable`, &form); err != nil {
+		t.Error(err)
+		return
+	}
+	form = url.Values{"Value": {fmt.Sprintf(`contract Fill%[1]s {
+		action {
+			DBInsert("%[1]s", "owner,info", $key_id, "mine")
+			DBInsert("%[1]s", "owner,info", 0, "other")
+			DBUpdate("%[1]s", 1, "info", "mine 2")
+			DBUpdate("%[1]s", 2, "info", "other 2")
+		}
+	}`, name)}, "Conditions": {`true`}}
+	if err := postTx(`NewContract`, &form); err != nil {
+		t.Error(err)
+		return
+	}
+	if err := postTx(`Fill`+name, &url.Values{}); err != nil {
+		t.Error(err)
+		return
+	}
+
+	var ret historyResult
+	if err := sendGet(`history/`+name+`/1`, nil, &ret); err != nil {
+		t.Error(err)
+		return
+	}
+	if len(ret.List) == 0 {
+		t.Error("history of the readable row should not be empty")
+	}
+	if err := sendGet(`history/`+name+`/2`, nil, &ret); err != nil {
+		t.Error(err)
+		return
+	}
+	if len(ret.List) != 0 {
+		t.Errorf("history of the hidden row should be empty: %v", ret.List)
+	}
+	var diff historyDiffResult
+	if err := sendGet(`history/`+name+`/2/diff`, nil, &diff); err == nil {
+		t.Errorf("diff of the hidden row should not be found: %v", diff.List)
+	}
+}
+
+func TestDiffHistory(t *testing.T) {
+	txs := []model.RollbackTx{
+		{BlockID: 10, TxHash: []byte{1}, TableID: `5`},
+		{BlockID: 11, TxHash: []byte{2}, TableID: `5`, Data: `{"name":"first","value":"1"}`},
+		{BlockID: 12, TxHash: []byte{3}, TableID: model.BatchTableID,
+			Data: `[{"id":"4","data":{"value":"x"}},{"id":"5","data":{"value":"2"}}]`},
+	}
+	current := map[string]string{`id`: `5`, `name`: `second`, `value`: `3`}
+	list, err := diffHistory(txs, `5`, current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []historyChange{
+		{BlockID: `10`, TxHash: `01`, Inserted: true, Changes: []columnChange{
+			{Column: `name`, Old: ``, New: `first`}, {Column: `value`, Old: ``, New: `1`}}},
+		{BlockID: `11`, TxHash: `02`, Changes: []columnChange{
+			{Column: `name`, Old: `first`, New: `second`}, {Column: `value`, Old: `1`, New: `2`}}},
+		{BlockID: `12`, TxHash: `03`, Changes: []columnChange{{Column: `value`, Old: `2`, New: `3`}}},
+	}
+	if !reflect.DeepEqual(list, want) {
+		t.Errorf("expected %+v, got %+v", want, list)
+	}
+}
@@ -0,0 +1,138 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/GenesisKernel/go-genesis/packages/auth"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type identityItem struct {
+	Provider string `json:"provider"`
+	Identity string `json:"identity"`
+	KeyID    string `json:"key_id"`
+	Address  string `json:"address"`
+}
+
+type identitiesResult struct {
+	List []identityItem `json:"list"`
+}
+
+// loginProvider issues the tokens of the key which is bound to the identity of the external provider
+func loginProvider(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	name := data.params[`provider`].(string)
+	provider, ok := auth.GetProvider(name)
+	if !ok {
+		logger.WithFields(log.Fields{"type": consts.NotFound, "provider": name}).Error("identity provider is not enabled")
+		return errorAPI(w, `E_AUTHPROVIDER`, http.StatusNotFound, name)
+	}
+	state := data.params[`ecosystem`].(int64)
+	if state == 0 {
+		state = 1
+	}
+	identity, err := provider.Authenticate(map[string]string{
+		`username`: data.params[`username`].(string),
+		`password`: data.params[`password`].(string),
+		`id_token`: data.params[`id_token`].(string),
+	})
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.AccessDenied, "provider": name, "error": err}).Warning("authenticating by identity provider")
+		return errorAPI(w, `E_UNAUTHORIZED`, http.StatusUnauthorized)
+	}
+	binding := &model.IdentityKey{}
+	if found, err := binding.Get(name, identity, state); err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting identity key")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	} else if !found {
+		logger.WithFields(log.Fields{"type": consts.NotFound, "provider": name, "identity": identity}).Warning("identity is not bound to a key")
+		return errorAPI(w, `E_IDENTITY`, http.StatusForbidden, identity, state)
+	}
+	key := &model.Key{}
+	key.SetTablePrefix(state)
+	if found, err := key.Get(binding.KeyID); err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting key")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	} else if !found {
+		logger.WithFields(log.Fields{"type": consts.NotFound, "key_id": binding.KeyID}).Error("bound key is not found")
+		return errorAPI(w, `E_STATELOGIN`, http.StatusForbidden, binding.KeyID, state)
+	}
+	return loginResponse(w, r, data, state, binding.KeyID, converter.AddressToString(binding.KeyID), logger)
+}
+
+// setIdentity binds the identity to the key of the ecosystem, empty key_id removes the binding
+func setIdentity(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	name, identity := data.params[`provider`].(string), data.params[`identity`].(string)
+	state := data.params[`ecosystem`].(int64)
+	if state == 0 {
+		state = data.ecosystemId
+	}
+	if len(data.params[`key_id`].(string)) == 0 {
+		if err := model.DeleteIdentityKey(name, identity, state); err != nil {
+			logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("deleting identity key")
+			return errorAPI(w, err, http.StatusInternalServerError)
+		}
+		data.result = &identityItem{Provider: name, Identity: identity}
+		return nil
+	}
+	wallet := converter.StringToAddress(data.params[`key_id`].(string))
+	if wallet == 0 {
+		return errorAPI(w, `E_INVALIDWALLET`, http.StatusBadRequest, data.params[`key_id`].(string))
+	}
+	binding := &model.IdentityKey{Provider: name, Identity: identity, Ecosystem: state, KeyID: wallet}
+	if err := binding.Save(); err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("saving identity key")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	data.result = &identityItem{Provider: name, Identity: identity, KeyID: converter.Int64ToStr(wallet),
+		Address: converter.AddressToString(wallet)}
+	return nil
+}
+
+func getIdentities(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	state := data.params[`ecosystem`].(int64)
+	if state == 0 {
+		state = data.ecosystemId
+	}
+	limit := data.params[`limit`].(int64)
+	if limit <= 0 {
+		limit = 25
+	}
+	list, err := model.GetIdentityKeys(data.params[`provider`].(string), state, data.params[`offset`].(int64), limit)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting identity keys")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	result := identitiesResult{List: make([]identityItem, 0, len(list))}
+	for _, item := range list {
+		result.List = append(result.List, identityItem{Provider: item.Provider, Identity: item.Identity,
+			KeyID: converter.Int64ToStr(item.KeyID), Address: converter.AddressToString(item.KeyID)})
+	}
+	data.result = &result
+	return nil
+}
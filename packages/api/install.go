@@ -67,7 +67,9 @@ func installCommon(data *installParams, logger *log.Entry) (err error) {
 		return fmt.Errorf(`E_INSTALLED`)
 	}
 
-	conf.Config.LogLevel = data.logLevel
+	conf.Update(func(cfg *conf.SavedConfig) {
+		cfg.LogLevel = data.logLevel
+	})
 
 	if len(data.firstLoadBlockchainURL) == 0 {
 		log.WithFields(log.Fields{
@@ -89,10 +91,12 @@ func installCommon(data *installParams, logger *log.Entry) (err error) {
 		return err
 	}
 
-	conf.Config.Centrifugo = conf.CentrifugoConfig{
-		Secret: data.centrifugoSecret,
-		URL:    data.centrifugoURL,
-	}
+	conf.Update(func(cfg *conf.SavedConfig) {
+		cfg.Centrifugo = conf.CentrifugoConfig{
+			Secret: data.centrifugoSecret,
+			URL:    data.centrifugoURL,
+		}
+	})
 
 	if !install.IsExistFirstBlock() {
 		err = install.GenerateFirstBlock()
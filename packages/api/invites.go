@@ -0,0 +1,148 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/smart"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// maxInviteCodes is the limit of invite codes generated by one request
+	maxInviteCodes = 100
+	// inviteCodeSize is the size of the random part of invite code in bytes
+	inviteCodeSize = 16
+
+	inviteActive   = `active`
+	inviteRedeemed = `redeemed`
+	inviteRevoked  = `revoked`
+	inviteExpired  = `expired`
+)
+
+type inviteCode struct {
+	Code string `json:"code"`
+	Hash string `json:"hash"`
+}
+
+type inviteCodesResult struct {
+	Contract string       `json:"contract"`
+	List     []inviteCode `json:"list"`
+}
+
+type inviteItem struct {
+	ID         int64  `json:"id"`
+	Hash       string `json:"hash"`
+	Roles      string `json:"roles"`
+	Amount     string `json:"amount"`
+	Expire     int64  `json:"expire"`
+	Status     string `json:"status"`
+	RedeemedBy string `json:"redeemed_by,omitempty"`
+}
+
+type invitesResult struct {
+	List []inviteItem `json:"list"`
+}
+
+// inviteStatus returns the status of invite code at the time
+func inviteStatus(invite *model.InviteCode, now int64) string {
+	switch {
+	case invite.RedeemedBy != 0:
+		return inviteRedeemed
+	case invite.Revoked != 0:
+		return inviteRevoked
+	case invite.Expire > 0 && invite.Expire <= now:
+		return inviteExpired
+	}
+	return inviteActive
+}
+
+// generateInvites returns the random invite codes and their hashes. The hashes are passed to NewInvite contract
+// and the codes are given to the new members
+func generateInvites(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	founder, err := isFounder(data)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting founder")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	if !founder {
+		logger.WithFields(log.Fields{"type": consts.AccessDenied, "key_id": data.keyId}).Error("generating invite codes")
+		return errorAPI(w, `E_PERMISSION`, http.StatusUnauthorized)
+	}
+	count := data.params[`count`].(int64)
+	if count <= 0 {
+		count = 1
+	} else if count > maxInviteCodes {
+		count = maxInviteCodes
+	}
+	result := inviteCodesResult{Contract: `NewInvite`, List: make([]inviteCode, 0, count)}
+	for i := int64(0); i < count; i++ {
+		buf := make([]byte, inviteCodeSize)
+		if _, err := rand.Read(buf); err != nil {
+			logger.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("generating invite code")
+			return errorAPI(w, err, http.StatusInternalServerError)
+		}
+		code := hex.EncodeToString(buf)
+		hash, err := smart.InviteHash(code)
+		if err != nil {
+			return errorAPI(w, err, http.StatusInternalServerError)
+		}
+		result.List = append(result.List, inviteCode{Code: code, Hash: hash})
+	}
+	data.result = &result
+	return nil
+}
+
+func getInvites(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	list, err := model.GetInviteCodes(data.ecosystemId, data.params[`offset`].(int64), int64(listLimit(data)))
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting invite codes")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	now := time.Now().Unix()
+	result := invitesResult{List: make([]inviteItem, 0, len(list))}
+	for i := range list {
+		item := inviteItem{
+			ID:     list[i].ID,
+			Hash:   list[i].Hash,
+			Roles:  list[i].Roles,
+			Amount: list[i].Amount,
+			Expire: list[i].Expire,
+			Status: inviteStatus(&list[i], now),
+		}
+		if list[i].RedeemedBy != 0 {
+			item.RedeemedBy = converter.AddressToString(list[i].RedeemedBy)
+		}
+		result.List = append(result.List, item)
+	}
+	data.result = &result
+	return nil
+}
@@ -0,0 +1,89 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/smart"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// keyImportResult is the result of the check of the bulk import of keys
+type keyImportResult struct {
+	Count    int                    `json:"count"`
+	Amount   string                 `json:"amount"`
+	Existing []string               `json:"existing"`
+	Valid    bool                   `json:"valid"`
+	Contract string                 `json:"contract"`
+	Params   map[string]interface{} `json:"params"`
+}
+
+func checkKeyImport(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	founder, err := isFounder(data)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting founder")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	if !founder {
+		logger.WithFields(log.Fields{"type": consts.AccessDenied, "key_id": data.keyId}).Error("importing keys")
+		return errorAPI(w, `E_PERMISSION`, http.StatusUnauthorized)
+	}
+	list, err := smart.ParseKeyImport(data.params[`data`].(string))
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.InvalidObject, "error": err}).Error("parsing imported keys")
+		return errorAPI(w, err, http.StatusBadRequest)
+	}
+	existing := make([]string, 0)
+	for _, item := range list {
+		key := &model.Key{}
+		key.SetTablePrefix(data.ecosystemId)
+		found, err := key.Get(item.ID)
+		if err != nil {
+			logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting key")
+			return errorAPI(w, err, http.StatusInternalServerError)
+		}
+		if found {
+			existing = append(existing, converter.AddressToString(item.ID))
+		}
+	}
+	out, err := json.Marshal(list)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Error("marshalling imported keys")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	data.result = &keyImportResult{
+		Count:    len(list),
+		Amount:   smart.ImportTotal(list).String(),
+		Existing: existing,
+		Valid:    len(existing) == 0,
+		Contract: `BulkImportKeys`,
+		Params:   map[string]interface{}{`Keys`: string(out)},
+	}
+	return nil
+}
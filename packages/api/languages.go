@@ -0,0 +1,82 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/language"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type langDocumentResult struct {
+	Format   string `json:"format"`
+	Document string `json:"document"`
+}
+
+type langDiffResult struct {
+	*language.DocumentDiff
+	Langs []string `json:"langs"`
+}
+
+func exportLanguages(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	_, prefix, err := checkEcosystem(w, data, logger)
+	if err != nil {
+		return err
+	}
+	doc, err := language.LoadDocument(prefix)
+	if err != nil {
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	format := data.params[`format`].(string)
+	if len(format) == 0 {
+		format = language.FormatJSON
+	}
+	out, err := doc.Marshal(format)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.InvalidObject, "error": err}).Error("exporting languages")
+		return errorAPI(w, err, http.StatusBadRequest)
+	}
+	data.result = &langDocumentResult{Format: format, Document: out}
+	return nil
+}
+
+func diffLanguages(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	_, prefix, err := checkEcosystem(w, data, logger)
+	if err != nil {
+		return err
+	}
+	doc, err := language.ParseDocument(data.params[`document`].(string), data.params[`format`].(string))
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.ParseError, "error": err}).Error("parsing language document")
+		return errorAPI(w, err, http.StatusBadRequest)
+	}
+	current, err := language.LoadDocument(prefix)
+	if err != nil {
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	data.result = &langDiffResult{DocumentDiff: doc.Diff(current), Langs: doc.Langs()}
+	return nil
+}
@@ -30,10 +30,19 @@ import (
 	"github.com/GenesisKernel/go-genesis/packages/consts"
 	"github.com/GenesisKernel/go-genesis/packages/converter"
 	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/smart"
+	"github.com/GenesisKernel/go-genesis/packages/template"
 
 	log "github.com/sirupsen/logrus"
 )
 
+const (
+	// deletedInclude returns both active and softly deleted rows
+	deletedInclude = `include`
+	// deletedOnly returns only softly deleted rows
+	deletedOnly = `only`
+)
+
 type listResult struct {
 	Count string              `json:"count"`
 	List  []map[string]string `json:"list"`
@@ -59,14 +68,88 @@ func list(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Ent
 	} else {
 		limit = 25
 	}
-	list, err := model.GetAll(`select `+cols+` from `+table+` order by id desc`+
-		fmt.Sprintf(` offset %d `, data.params[`offset`].(int64)), limit)
+	where, params, err := rowReadFilter(data, data.params[`name`].(string))
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.InvalidObject, "error": err, "table": table}).Error("Getting row condition")
+		return errorAPI(w, err, http.StatusBadRequest)
+	}
+	if filter := data.params[`filter`].(string); len(filter) > 0 {
+		rows, err := model.GetAllColumnTypes(strings.Trim(table, `"`))
+		if err != nil {
+			logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "table": table}).Error("Getting column types")
+			return errorAPI(w, err.Error(), http.StatusInternalServerError)
+		}
+		columnTypes := make(map[string]string, len(rows))
+		for _, row := range rows {
+			columnTypes[row[`column_name`]] = row[`data_type`]
+		}
+		fltWhere, fltParams, err := template.FilterToWhere(filter, columnTypes)
+		if err != nil {
+			logger.WithFields(log.Fields{"type": consts.InvalidObject, "error": err, "table": table}).Error("Parsing filter")
+			return errorAPI(w, err, http.StatusBadRequest)
+		}
+		if len(where) > 0 {
+			where = `(` + where + `) and ` + fltWhere
+		} else {
+			where = fltWhere
+		}
+		params = append(params, fltParams...)
+	}
+	if colType, _ := model.GetColumnType(strings.Trim(table, `"`), `deleted_at`); len(colType) > 0 {
+		cond, err := deletedCondition(data.params[`deleted`].(string))
+		if err != nil {
+			return errorAPI(w, err, http.StatusBadRequest)
+		}
+		if len(cond) > 0 {
+			if len(where) > 0 {
+				where = `(` + where + `) and ` + cond
+			} else {
+				where = cond
+			}
+		}
+	}
+	total := count - 1
+	if len(where) > 0 {
+		total, err = model.Single(`select count(*) from `+table+` where `+where, params...).Int64()
+		if err != nil {
+			logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "table": table}).Error("Getting count of rows")
+			return errorAPI(w, err.Error(), http.StatusInternalServerError)
+		}
+		where = ` where ` + where
+	}
+	list, err := model.GetAll(`select `+cols+` from `+table+where+` order by id desc`+
+		fmt.Sprintf(` offset %d `, data.params[`offset`].(int64)), limit, params...)
 	if err != nil {
 		logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "table": table}).Error("Getting rows from table")
 		return errorAPI(w, err.Error(), http.StatusInternalServerError)
 	}
 	data.result = &listResult{
-		Count: converter.Int64ToStr(count - 1), List: list,
+		Count: converter.Int64ToStr(total), List: list,
 	}
 	return
 }
+
+// rowReadFilter returns the row_read condition of the table for the caller
+func rowReadFilter(data *apiData, name string) (string, []interface{}, error) {
+	tables := &model.Table{}
+	tables.SetTablePrefix(getPrefix(data))
+	perm, err := tables.GetPermissions(nil, strings.ToLower(name), ``)
+	if err != nil || len(perm[smart.RowRead]) == 0 {
+		return ``, nil, err
+	}
+	return smart.RowCondition(perm[smart.RowRead], data.keyId, data.ecosystemId)
+}
+
+// deletedCondition returns the condition of the softly deleted rows for the mode of the list.
+// The deleted rows are excluded by default
+func deletedCondition(mode string) (string, error) {
+	switch mode {
+	case ``:
+		return smart.NotDeletedRows, nil
+	case deletedInclude:
+		return ``, nil
+	case deletedOnly:
+		return `not ` + smart.NotDeletedRows, nil
+	}
+	return ``, fmt.Errorf(`unknown deleted mode %s`, mode)
+}
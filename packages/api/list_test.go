@@ -27,6 +27,7 @@ import (
 	"testing"
 
 	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/smart"
 )
 
 func TestList(t *testing.T) {
@@ -50,3 +51,18 @@ func TestList(t *testing.T) {
 		return
 	}
 }
+
+func TestDeletedCondition(t *testing.T) {
+	for mode, want := range map[string]string{
+		``:        smart.NotDeletedRows,
+		`include`: ``,
+		`only`:    `not ` + smart.NotDeletedRows,
+	} {
+		if got, err := deletedCondition(mode); err != nil || got != want {
+			t.Errorf(`%s: got %s %v`, mode, got, err)
+		}
+	}
+	if _, err := deletedCondition(`all`); err == nil {
+		t.Error(`expected error of unknown mode`)
+	}
+}
@@ -108,10 +108,15 @@ func login(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.En
 		logger.WithFields(log.Fields{"type": consts.InvalidObject, "pubkey": pubkey, "msg": msg, "signature": string(data.params["signature"].([]byte))}).Error("incorrect signature")
 		return errorAPI(w, `E_SIGNATURE`, http.StatusBadRequest)
 	}
-	address := crypto.KeyToAddress(pubkey)
+	return loginResponse(w, r, data, state, wallet, crypto.KeyToAddress(pubkey), logger)
+}
+
+// loginResponse issues the tokens of the authenticated key
+func loginResponse(w http.ResponseWriter, r *http.Request, data *apiData, state, wallet int64, address string, logger *log.Entry) error {
 	var (
 		sp      model.StateParameter
 		founder int64
+		err     error
 	)
 	sp.SetTablePrefix(converter.Int64ToStr(state))
 	if ok, err := sp.Get(nil, "founder_account"); err != nil {
@@ -130,10 +135,17 @@ func login(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.En
 		logger.WithFields(log.Fields{"type": consts.JWTError, "expire": jwtExpire}).Warning("using expire from jwt")
 		expire = jwtExpire
 	}
+	refreshExpire := time.Now().Add(time.Hour * 30 * 24).Unix()
+	session, err := newSession(r, data, state, wallet, refreshExpire)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("creating session")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
 	claims := JWTClaims{
 		KeyID:       result.KeyID,
 		EcosystemID: result.EcosystemID,
 		StandardClaims: jwt.StandardClaims{
+			Id:        session,
 			ExpiresAt: time.Now().Add(time.Second * time.Duration(expire)).Unix(),
 		},
 	}
@@ -142,7 +154,7 @@ func login(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.En
 		logger.WithFields(log.Fields{"type": consts.JWTError, "error": err}).Error("generating jwt token")
 		return errorAPI(w, err, http.StatusInternalServerError)
 	}
-	claims.StandardClaims.ExpiresAt = time.Now().Add(time.Hour * 30 * 24).Unix()
+	claims.StandardClaims.ExpiresAt = refreshExpire
 	result.Refresh, err = jwtGenerateToken(w, claims)
 	if err != nil {
 		logger.WithFields(log.Fields{"type": consts.JWTError, "error": err}).Error("generating jwt token")
@@ -0,0 +1,118 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/smart"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type nameResult struct {
+	Name    string `json:"name"`
+	KeyID   string `json:"key_id"`
+	Address string `json:"address"`
+	Expire  string `json:"expire"`
+}
+
+type namesResult struct {
+	List []nameResult `json:"list"`
+}
+
+// resolveWallet returns the key of the address or of the account name registered in the ecosystem
+func resolveWallet(ecosystemID int64, wallet string, logger *log.Entry) int64 {
+	if keyID := converter.StringToAddress(wallet); keyID != 0 {
+		return keyID
+	}
+	name, err := smart.NormalizeAccountName(wallet)
+	if err != nil {
+		return 0
+	}
+	keyID, err := model.ResolveAccountName(ecosystemID, name, time.Now().Unix())
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("resolving account name")
+	}
+	return keyID
+}
+
+func getAccountName(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	ecosystemID, _, err := checkEcosystem(w, data, logger)
+	if err != nil {
+		return err
+	}
+	name, err := smart.NormalizeAccountName(data.params[`name`].(string))
+	if err != nil {
+		return errorAPI(w, `E_NAME`, http.StatusBadRequest, data.params[`name`].(string))
+	}
+	record := &model.AccountName{}
+	record.SetTablePrefix(ecosystemID)
+	found, err := record.GetByName(nil, name)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting account name")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	if !found || record.Expire < time.Now().Unix() {
+		return errorAPI(w, `E_NAME`, http.StatusNotFound, name)
+	}
+	data.result = newNameResult(record)
+	return nil
+}
+
+func getAccountNames(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	ecosystemID, _, err := checkEcosystem(w, data, logger)
+	if err != nil {
+		return err
+	}
+	wallet := data.params[`wallet`].(string)
+	keyID := converter.StringToAddress(wallet)
+	if keyID == 0 {
+		logger.WithFields(log.Fields{"type": consts.ConversionError, "value": wallet}).Error("converting wallet to address")
+		return errorAPI(w, `E_INVALIDWALLET`, http.StatusBadRequest, wallet)
+	}
+	list, err := model.GetAccountNames(ecosystemID, keyID, time.Now().Unix())
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting account names")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	result := namesResult{List: make([]nameResult, 0, len(list))}
+	for i := range list {
+		result.List = append(result.List, *newNameResult(&list[i]))
+	}
+	data.result = &result
+	return nil
+}
+
+func newNameResult(record *model.AccountName) *nameResult {
+	return &nameResult{
+		Name:    record.Name,
+		KeyID:   converter.Int64ToStr(record.KeyID),
+		Address: converter.AddressToString(record.KeyID),
+		Expire:  converter.Int64ToStr(record.Expire),
+	}
+}
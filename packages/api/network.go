@@ -0,0 +1,121 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package api
+
+import (
+	"encoding/hex"
+	"net/http"
+	"sort"
+
+	"github.com/GenesisKernel/go-genesis/packages/config/syspar"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// maxScheduledParams limits the pending changes of parameters which are returned with the configuration
+const maxScheduledParams = 1000
+
+type scheduledValue struct {
+	Value           string `json:"value"`
+	ActivationBlock string `json:"activation_block"`
+}
+
+type networkParam struct {
+	Name       string          `json:"name"`
+	Value      string          `json:"value"`
+	Type       string          `json:"type"`
+	Default    string          `json:"default"`
+	Changed    bool            `json:"changed"`
+	Conditions string          `json:"conditions"`
+	Block      string          `json:"block_id,omitempty"`
+	Tx         string          `json:"tx_hash,omitempty"`
+	Scheduled  *scheduledValue `json:"scheduled,omitempty"`
+}
+
+type networkResult struct {
+	Version string         `json:"version"`
+	BlockID string         `json:"block_id"`
+	List    []networkParam `json:"list"`
+}
+
+func getNetworkConfig(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	sp := &model.StateParameter{}
+	sp.SetTablePrefix(`system`)
+	list, err := sp.GetAllStateParameters()
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting all system parameters")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	changes, err := model.GetLastTableChanges(`system_parameters`)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting last changes of system parameters")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	lastChange := make(map[int64]model.RollbackTx, len(changes))
+	for _, item := range changes {
+		lastChange[converter.StrToInt64(item.TableID)] = item
+	}
+	pending, err := model.GetPendingSysParams(maxScheduledParams, 0)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting scheduled system parameters")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	scheduled := make(map[string]*scheduledValue)
+	for _, item := range pending {
+		// the earliest change is the next value of parameter
+		if _, ok := scheduled[item.Name]; !ok && len(item.Value) > 0 {
+			scheduled[item.Name] = &scheduledValue{Value: item.Value,
+				ActivationBlock: converter.Int64ToStr(item.ActivationBlock)}
+		}
+	}
+	block := &model.Block{}
+	if _, err = block.GetMaxBlock(); err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting max block")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	result := networkResult{Version: consts.VERSION, BlockID: converter.Int64ToStr(block.ID),
+		List: make([]networkParam, 0, len(list))}
+	for _, item := range list {
+		info, known := syspar.GetParamInfo(item.Name)
+		param := networkParam{
+			Name:       item.Name,
+			Value:      item.Value,
+			Type:       info.Type,
+			Default:    info.Default,
+			Changed:    known && item.Value != info.Default,
+			Conditions: item.Conditions,
+			Scheduled:  scheduled[item.Name],
+		}
+		if change, ok := lastChange[item.ID]; ok {
+			param.Block = converter.Int64ToStr(change.BlockID)
+			param.Tx = hex.EncodeToString(change.TxHash)
+		}
+		result.List = append(result.List, param)
+	}
+	sort.Slice(result.List, func(i, j int) bool { return result.List[i].Name < result.List[j].Name })
+	data.result = &result
+	return nil
+}
@@ -0,0 +1,123 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type nftCollectionsResult struct {
+	List []model.NFTCollection `json:"list"`
+}
+
+type nftsResult struct {
+	List []model.NFT `json:"list"`
+}
+
+type nftHistoryResult struct {
+	List []model.NFTHistory `json:"list"`
+}
+
+func getNFTCollections(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	ecosystemID, _, err := checkEcosystem(w, data, logger)
+	if err != nil {
+		return err
+	}
+	collections, err := model.GetNFTCollections(ecosystemID, listLimit(data), int(data.params[`offset`].(int64)))
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting nft collections")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	data.result = &nftCollectionsResult{List: collections}
+	return nil
+}
+
+func getNFTs(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	ecosystemID, _, err := checkEcosystem(w, data, logger)
+	if err != nil {
+		return err
+	}
+	var owner int64
+	if wallet := data.params[`owner`].(string); len(wallet) > 0 {
+		if owner = resolveWallet(ecosystemID, wallet, logger); owner == 0 {
+			logger.WithFields(log.Fields{"type": consts.ConversionError, "value": wallet}).Error("converting wallet to address")
+			return errorAPI(w, `E_INVALIDWALLET`, http.StatusBadRequest, wallet)
+		}
+	}
+	nfts, err := model.GetNFTs(ecosystemID, data.params[`collection`].(int64), owner, listLimit(data),
+		int(data.params[`offset`].(int64)))
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting nfts")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	data.result = &nftsResult{List: nfts}
+	return nil
+}
+
+// findNFT returns the ecosystem and the asset by the id from the request
+func findNFT(w http.ResponseWriter, data *apiData, logger *log.Entry) (int64, *model.NFT, error) {
+	ecosystemID, _, err := checkEcosystem(w, data, logger)
+	if err != nil {
+		return 0, nil, err
+	}
+	nft := &model.NFT{}
+	nft.SetTablePrefix(ecosystemID)
+	found, err := nft.Get(nil, converter.StrToInt64(data.params[`id`].(string)))
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting nft")
+		return 0, nil, errorAPI(w, err, http.StatusInternalServerError)
+	}
+	if !found {
+		return 0, nil, errorAPI(w, `E_NFT`, http.StatusNotFound, data.params[`id`].(string))
+	}
+	return ecosystemID, nft, nil
+}
+
+func getNFT(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	_, nft, err := findNFT(w, data, logger)
+	if err != nil {
+		return err
+	}
+	data.result = nft
+	return nil
+}
+
+func getNFTHistory(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	ecosystemID, nft, err := findNFT(w, data, logger)
+	if err != nil {
+		return err
+	}
+	history, err := model.GetNFTHistory(ecosystemID, nft.ID, listLimit(data), int(data.params[`offset`].(int64)))
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting nft history")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	data.result = &nftHistoryResult{List: history}
+	return nil
+}
@@ -0,0 +1,85 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package api
+
+import (
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/parser"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type oracleResult struct {
+	Hash string `json:"hash"`
+}
+
+func getOracleValue(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	value := &model.OracleValue{}
+	found, err := value.GetByFeed(nil, data.params[`feed`].(string))
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting oracle value")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	if !found {
+		return errorAPI(w, `E_NOTFOUND`, http.StatusNotFound)
+	}
+	data.result = value
+	return nil
+}
+
+// pushOracleData sends the transaction with the value of the feed signed by the oracle key
+func pushOracleData(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	oracleData := &consts.OracleData{
+		TxHeader: consts.TxHeader{
+			Type:  consts.TxTypeOracleData,
+			Time:  uint32(time.Now().Unix()),
+			KeyID: data.keyId,
+		},
+		Feed:     data.params[`feed`].(string),
+		Value:    data.params[`value`].(string),
+		DataTime: data.params[`data_time`].(int64),
+		Sign:     data.params[`signature`].([]byte),
+	}
+	if err := parser.CheckOracleData(nil, oracleData); err != nil {
+		logger.WithFields(log.Fields{"type": consts.InvalidObject, "error": err, "feed": oracleData.Feed}).Error("checking oracle data")
+		return errorAPI(w, `E_ORACLE`, http.StatusBadRequest, err.Error())
+	}
+
+	var txData []byte
+	if _, err := converter.BinMarshal(&txData, oracleData); err != nil {
+		logger.WithFields(log.Fields{"type": consts.MarshallingError, "error": err}).Error("marshalling oracle data")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	hash, err := model.SendTx(consts.TxTypeOracleData, data.keyId, txData, data.requestID)
+	if err != nil {
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	data.result = &oracleResult{Hash: hex.EncodeToString(hash)}
+	return nil
+}
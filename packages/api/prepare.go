@@ -63,10 +63,13 @@ func prepareContract(w http.ResponseWriter, r *http.Request, data *apiData, logg
 	smartTx.TokenEcosystem = data.params[`token_ecosystem`].(int64)
 	smartTx.MaxSum = data.params[`max_sum`].(string)
 	smartTx.PayOver = data.params[`payover`].(string)
+	smartTx.MaxFee = data.params[`max_fee`].(string)
 	if data.params[`signed_by`] != nil {
 		smartTx.SignedBy = data.params[`signed_by`].(int64)
 	}
-	smartTx.Header = tx.Header{Type: int(info.ID), Time: timeNow, EcosystemID: data.ecosystemId, KeyID: data.keyId}
+	smartTx.Header = tx.Header{Type: int(info.ID), Time: timeNow, EcosystemID: data.ecosystemId, KeyID: data.keyId,
+		Nonce: data.params[`nonce`].(int64), ExpireBlock: data.params[`expire_block`].(int64),
+		ExpireTime: data.params[`expire_time`].(int64)}
 	forsign := smartTx.ForSign()
 	if info.Tx != nil {
 		for _, fitem := range *info.Tx {
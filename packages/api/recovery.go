@@ -0,0 +1,107 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// recoveryHistoryLimit is the number of the last recovery requests which are returned
+const recoveryHistoryLimit = 10
+
+var recoveryStatuses = map[int64]string{
+	model.RecoveryPending:   `pending`,
+	model.RecoveryDone:      `done`,
+	model.RecoveryCancelled: `cancelled`,
+}
+
+type recoveryRequestItem struct {
+	ID          int64    `json:"id"`
+	PublicKey   string   `json:"public_key"`
+	Approvals   []string `json:"approvals"`
+	StartedAt   int64    `json:"started_at"`
+	ReleaseTime int64    `json:"release_time"`
+	Status      string   `json:"status"`
+}
+
+type recoveryResult struct {
+	Guardians []string              `json:"guardians"`
+	Threshold int64                 `json:"threshold"`
+	Delay     int64                 `json:"delay"`
+	Requests  []recoveryRequestItem `json:"requests"`
+}
+
+// addressList converts the identifiers separated by comma to the list of addresses
+func addressList(ids string) []string {
+	ret := make([]string, 0)
+	for _, item := range strings.Split(ids, `,`) {
+		if id := converter.StrToInt64(strings.TrimSpace(item)); id != 0 {
+			ret = append(ret, converter.AddressToString(id))
+		}
+	}
+	return ret
+}
+
+func getRecovery(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	wallet := data.params[`wallet`].(string)
+	keyID := converter.StringToAddress(wallet)
+	if keyID == 0 {
+		logger.WithFields(log.Fields{"type": consts.ConversionError, "value": wallet}).Error("converting wallet to address")
+		return errorAPI(w, `E_INVALIDWALLET`, http.StatusBadRequest, wallet)
+	}
+	guardians := &model.RecoveryGuardians{}
+	if _, err := guardians.Get(nil, data.ecosystemId, keyID); err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting guardians")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	list, err := model.GetRecoveryRequests(data.ecosystemId, keyID, recoveryHistoryLimit)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting recovery requests")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	result := recoveryResult{
+		Guardians: addressList(guardians.Guardians),
+		Threshold: guardians.Threshold,
+		Delay:     guardians.Delay,
+		Requests:  make([]recoveryRequestItem, 0, len(list)),
+	}
+	for _, item := range list {
+		result.Requests = append(result.Requests, recoveryRequestItem{
+			ID:          item.ID,
+			PublicKey:   item.PublicKey,
+			Approvals:   addressList(item.Approvals),
+			StartedAt:   item.StartedAt,
+			ReleaseTime: item.ReleaseTime,
+			Status:      recoveryStatuses[item.Status],
+		})
+	}
+	data.result = &result
+	return nil
+}
@@ -29,6 +29,7 @@ import (
 
 	"github.com/GenesisKernel/go-genesis/packages/consts"
 	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
 
 	"github.com/dgrijalva/jwt-go"
 	log "github.com/sirupsen/logrus"
@@ -80,6 +81,10 @@ func refresh(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.
 		logger.WithFields(log.Fields{"type": consts.JWTError}).Error("token wallet or state is invalid")
 		return errorAPI(w, `E_REFRESHTOKEN`, http.StatusBadRequest)
 	}
+	if len(refClaims.Id) > 0 && isRevoked(refClaims.Id) {
+		logger.WithFields(log.Fields{"type": consts.AccessDenied, "session": refClaims.Id}).Warning("session is revoked")
+		return errorAPI(w, `E_TOKENREVOKED`, http.StatusUnauthorized)
+	}
 	var result refreshResult
 	data.result = &result
 
@@ -88,6 +93,7 @@ func refresh(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.
 		logger.Warning("expire is 0, using jwt expire")
 		expire = jwtExpire
 	}
+	claims.StandardClaims.Id = refClaims.Id
 	claims.StandardClaims.ExpiresAt = time.Now().Add(time.Second * time.Duration(expire)).Unix()
 	result.Token, err = jwtGenerateToken(w, *claims)
 	if err != nil {
@@ -100,5 +106,12 @@ func refresh(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.
 		logger.WithFields(log.Fields{"type": consts.JWTError, "error": err}).Error("generating jwt token")
 		return errorAPI(w, err, http.StatusInternalServerError)
 	}
+	if len(refClaims.Id) > 0 {
+		session := &model.Session{ID: refClaims.Id}
+		if err = session.Prolong(time.Now().Unix(), claims.StandardClaims.ExpiresAt); err != nil {
+			logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("prolonging session")
+			return errorAPI(w, err, http.StatusInternalServerError)
+		}
+	}
 	return nil
 }
@@ -0,0 +1,46 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package api
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestID(t *testing.T) {
+	r := httptest.NewRequest(`GET`, `/api/v2/version`, nil)
+	r.Header.Set(requestIDHeader, `trace-1.2:3`)
+	if id := requestID(r); id != `trace-1.2:3` {
+		t.Errorf(`incoming id is not accepted %s`, id)
+	}
+	for _, bad := range []string{``, `a b`, "line\nbreak", strings.Repeat(`x`, 65)} {
+		r.Header.Set(requestIDHeader, bad)
+		if id := requestID(r); len(id) != 32 || id == bad {
+			t.Errorf(`id must be generated instead of %q, got %s`, bad, id)
+		}
+	}
+	if requestID(r) == requestID(r) {
+		t.Error(`generated ids must be unique`)
+	}
+}
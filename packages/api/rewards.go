@@ -0,0 +1,91 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+)
+
+type rewardItem struct {
+	BlockID string `json:"block_id"`
+	Role    string `json:"role"`
+	Amount  string `json:"amount"`
+}
+
+type rewardsResult struct {
+	Accrued   string       `json:"accrued"`
+	Claimed   string       `json:"claimed"`
+	Available string       `json:"available"`
+	List      []rewardItem `json:"list"`
+}
+
+func getRewards(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	wallet := data.params[`wallet`].(string)
+	keyID := converter.StringToAddress(wallet)
+	if keyID == 0 {
+		logger.WithFields(log.Fields{"type": consts.ConversionError, "value": wallet}).Error("converting wallet to address")
+		return errorAPI(w, `E_INVALIDWALLET`, http.StatusBadRequest, wallet)
+	}
+	ecosystem := data.params[`ecosystem`].(int64)
+	if ecosystem == 0 {
+		ecosystem = 1
+	}
+	accrued, claimed, err := model.GetRewardTotals(nil, keyID, ecosystem)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting reward totals")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	total, err := decimal.NewFromString(accrued)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.ConversionError, "error": err, "value": accrued}).Error("converting accrued rewards to decimal")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	paid, err := decimal.NewFromString(claimed)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.ConversionError, "error": err, "value": claimed}).Error("converting claimed rewards to decimal")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	list, err := model.GetValidatorRewards(keyID, ecosystem, data.params[`offset`].(int64), int64(listLimit(data)))
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting validator rewards")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	result := rewardsResult{Accrued: total.String(), Claimed: paid.String(), Available: total.Sub(paid).String(),
+		List: make([]rewardItem, 0, len(list))}
+	for _, item := range list {
+		result.List = append(result.List, rewardItem{
+			BlockID: converter.Int64ToStr(item.BlockID),
+			Role:    item.Role,
+			Amount:  item.Amount,
+		})
+	}
+	data.result = &result
+	return nil
+}
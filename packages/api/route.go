@@ -26,6 +26,7 @@ import (
 	"strings"
 
 	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/publisher"
 	hr "github.com/julienschmidt/httprouter"
 	log "github.com/sirupsen/logrus"
 )
@@ -47,7 +48,7 @@ func Route(route *hr.Router) {
 		if len(pars) > 0 {
 			pars = `,` + pars
 		}
-		methodRoute(route, method, `contract/`+pattern, `?pubkey signature:hex, time:string`+pars, authWallet, handle)
+		methodRoute(route, method, `contract/`+pattern, `?pubkey signature:hex, time:string`+pars, authWallet, writable, handle)
 	}
 	postTx := func(url string, params string, preHandle, handle apiHandle) {
 		anyTx(`POST`, url, params, preHandle, handle)
@@ -55,41 +56,103 @@ func Route(route *hr.Router) {
 
 	route.Handle(`OPTIONS`, consts.ApiPath+`*name`, optionsHandler())
 	route.Handle(`GET`, consts.ApiPath+`data/:table/:id/:column/:hash`, dataHandler())
+	route.Handle(`GET`, consts.ApiPath+`thumbnail/:table/:id/:column/:hash`, thumbnailHandler())
+	route.Handle(`GET`, consts.ApiPath+`static/:ecosystem/:app/*name`, assetHandler())
+	route.Handle(`GET`, consts.ApiPath+`admin/pprof/*name`, adminRawHandler(pprofHandler))
+	route.HandlerFunc(`GET`, consts.ApiPath+`notifications/stream`, publisher.ServeSSE)
+	route.Handle(`GET`, consts.ApiPath+`admin/diagnose`, adminRawHandler(diagnoseHandler))
 
 	get(`balance/:wallet`, `?ecosystem:int64`, authWallet, balance)
 	get(`contract/:name`, ``, authWallet, getContract)
-	get(`contracts`, `?limit ?offset:int64`, authWallet, getContracts)
+	get(`contracts`, `?limit ?offset:int64`, authWallet, withETag, getContracts)
+	get(`schema/contract/:name`, ``, authWallet, getContractSchema)
+	get(`schema/contracts`, `?limit ?offset:int64`, authWallet, withETag, getContractSchemas)
 	get(`ecosystemparam/:name`, `?ecosystem:int64`, authWallet, ecosystemParam)
 	get(`ecosystemparams`, `?ecosystem:int64,?names:string`, authWallet, ecosystemParams)
 	get(`ecosystems`, ``, authWallet, ecosystems)
 	get(`getuid`, ``, getUID)
-	get(`list/:name`, `?limit ?offset:int64,?columns:string`, authWallet, list)
+	get(`languages/export`, `?ecosystem:int64,?format:string`, authWallet, exportLanguages)
+	get(`list/:name`, `?limit ?offset:int64,?columns ?filter ?deleted:string`, authWallet, withETag, list)
 	get(`row/:name/:id`, `?columns:string`, authWallet, row)
 	get(`systemparams`, `?names:string`, authWallet, systemParams)
+	get(`systemparams/schedule`, `?limit ?offset:int64`, authWallet, systemParamsSchedule)
 	get(`table/:name`, ``, authWallet, table)
-	get(`tables`, `?limit ?offset:int64`, authWallet, tables)
+	get(`tables`, `?limit ?offset:int64`, authWallet, withETag, tables)
 	get(`txstatus/:hash`, ``, authWallet, txstatus)
 	get(`test/:name`, ``, getTest)
 	get(`history/:table/:id`, ``, authWallet, getHistory)
+	get(`history/:table/:id/diff`, `?limit:int64`, authWallet, getHistoryDiff)
+	get(`name/:name`, `?ecosystem:int64`, authWallet, getAccountName)
+	get(`names/:wallet`, `?ecosystem:int64`, authWallet, getAccountNames)
+	get(`nonce/:wallet`, ``, getNonce)
+	get(`fuelrate`, `?ecosystem:int64`, getFuelRate)
+	get(`rewards/:wallet`, `?ecosystem ?limit ?offset:int64`, authWallet, getRewards)
+	get(`validators`, `?limit ?offset:int64`, authWallet, getValidators)
+	get(`invites`, `?limit ?offset:int64`, authWallet, getInvites)
+	get(`recovery/:wallet`, ``, authWallet, getRecovery)
+	get(`calls`, `?limit ?offset ?from ?to:int64,?contract ?wallet:string`, authWallet, getContractCalls)
 	get(`block/:id`, ``, getBlockInfo)
 	get(`maxblockid`, ``, getMaxBlockID)
+	get(`network`, ``, getNetworkConfig)
+	get(`sessions`, `?limit ?offset:int64`, authWallet, getSessions)
+	get(`admin/loglevels`, ``, authWallet, authAdmin, getLogLevels)
+	get(`admin/runtime`, ``, authWallet, authAdmin, getRuntimeStats)
+	get(`admin/audit`, `?limit ?offset ?key_id:int64,?action:string`, authWallet, authAdmin, getAudit)
+	get(`admin/daemons`, ``, authWallet, authAdmin, getDaemons)
+	get(`admin/slo`, ``, authWallet, authAdmin, getSLO)
+	get(`admin/maintenance`, ``, authWallet, authAdmin, getMaintenance)
+	get(`admin/identities`, `?ecosystem ?limit ?offset:int64,?provider:string`, authWallet, authAdmin, getIdentities)
+	get(`oracle/:feed`, ``, authWallet, getOracleValue)
+	get(`assets`, `?ecosystem ?limit ?offset:int64`, authWallet, getAssets)
+	get(`asset/:symbol`, `?ecosystem:int64`, authWallet, getAsset)
+	get(`asset/:symbol/balance/:wallet`, `?ecosystem:int64`, authWallet, getAssetBalance)
+	get(`asset/:symbol/history`, `?ecosystem ?limit ?offset:int64,?wallet:string`, authWallet, getAssetHistory)
+	get(`nftcollections`, `?ecosystem ?limit ?offset:int64`, authWallet, getNFTCollections)
+	get(`nfts`, `?ecosystem ?collection ?limit ?offset:int64,?owner:string`, authWallet, getNFTs)
+	get(`nft/:id`, `?ecosystem:int64`, authWallet, getNFT)
+	get(`nft/:id/history`, `?ecosystem ?limit ?offset:int64`, authWallet, getNFTHistory)
+	get(`apps`, `?ecosystem:int64`, authWallet, getApps)
+	get(`binaries/:app`, `?ecosystem:int64`, authWallet, getAppBinaries)
+	get(`domains`, `?ecosystem:int64`, authWallet, getDomains)
+	get(`export`, `?name ?contracts ?pages ?menus ?blocks ?parameters ?languages ?tables ?manifest:string,?data:int64`, authWallet, exportApp)
+	post(`export/sign`, `bundle signature:string`, authWallet, signExport)
 
-	post(`content/source/:name`, ``, authWallet, getSource)
-	post(`content/page/:name`, `?lang:string`, authWallet, getPage)
-	post(`content/menu/:name`, `?lang:string`, authWallet, getMenu)
-	post(`content/hash/:name`, ``, authWallet, getPageHash)
+	post(`content/source/:name`, ``, authContent, withETag, getSource)
+	post(`content/page/:name`, `?lang:string`, authContent, withETag, getPage)
+	post(`content/page`, `?lang:string`, authContent, withETag, getDefaultPage)
+	post(`content/menu/:name`, `?lang:string`, authContent, withETag, getMenu)
+	post(`content/hash/:name`, ``, authContent, getPageHash)
 	post(`install`, `?first_load_blockchain_url ?first_block_dir log_level type db_host db_port 
 	db_name db_pass db_user ?centrifugo_url ?centrifugo_secret:string,?generate_first_block:int64`, doInstall)
-	post(`vde/create`, ``, authWallet, vdeCreate)
-	post(`login`, `?pubkey signature:hex,?key_id:string,?ecosystem ?expire:int64`, login)
-	postTx(`:name`, `?token_ecosystem:int64,?max_sum ?payover:string`, prepareContract, contract)
+	post(`vde/create`, ``, authWallet, writable, vdeCreate)
+	post(`vde/console`, `code:string`, authWallet, writable, vdeConsole)
+	post(`import/check`, `bundle:string`, authWallet, checkImport)
+	post(`keys/import/check`, `data:string`, authWallet, checkKeyImport)
+	post(`invites/generate`, `?count:int64`, authWallet, generateInvites)
+	post(`apps/install`, `bundle:string`, authWallet, writable, installApp)
+	post(`apps/upgrade`, `bundle:string`, authWallet, writable, upgradeApp)
+	post(`apps/uninstall`, `name:string`, authWallet, writable, uninstallApp)
+	post(`languages/diff`, `document:string,?ecosystem:int64,?format:string`, authWallet, diffLanguages)
+	post(`oracle/:feed`, `value:string,data_time:int64,signature:hex`, authWallet, writable, pushOracleData)
+	post(`login`, `?pubkey signature:hex,?key_id ?device:string,?ecosystem ?expire:int64`, login)
+	post(`login/:provider`, `?username ?password ?id_token ?device:string,?ecosystem ?expire:int64`, loginProvider)
+	postTx(`:name`, `?token_ecosystem ?nonce ?expire_block ?expire_time:int64,?max_sum ?payover ?max_fee:string`, prepareContract, contract)
 	post(`refresh`, `token:string,?expire:int64`, refresh)
+	post(`session/revoke`, `?id:string`, authWallet, revokeSession)
+	post(`sendtx`, `data:hex`, writable, sendTx)
+	post(`txcancel/:hash`, `signature:hex`, writable, cancelTx)
 	post(`signtest/`, `forsign private:string`, signTest)
 	post(`test/:name`, ``, getTest)
-	post(`content`, `template:string`, jsonContent)
+	post(`content`, `template:string`, withETag, jsonContent)
 	post(`updnotificator`, `ids:string`, updateNotificator)
+	post(`admin/loglevel`, `?subsystem ?level:string`, authWallet, authAdmin, setLogLevel)
+	post(`admin/daemon/:name/:action`, ``, authWallet, authAdmin, daemonAction)
+	post(`admin/reload`, ``, authWallet, authAdmin, reloadConfig)
+	post(`admin/maintenance/:action`, `?reason:string`, authWallet, authAdmin, setMaintenance)
+	post(`admin/ecosystem/:id/archive`, ``, authWallet, authAdmin, writable, archiveEcosystem)
+	post(`admin/identity`, `provider identity:string,?key_id:string,?ecosystem:int64`, authWallet, authAdmin, setIdentity)
 
-	methodRoute(route, `POST`, `node/:name`, `?token_ecosystem:int64,?max_sum ?payover:string`, nodeContract)
+	methodRoute(route, `POST`, `node/:name`, `?token_ecosystem:int64,?max_sum ?payover:string`, writable, nodeContract)
 }
 
 func processParams(input string) (params map[string]int) {
@@ -42,7 +42,16 @@ func row(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entr
 		cols = converter.EscapeName(data.params[`columns`].(string))
 	}
 	table := converter.EscapeName(getPrefix(data) + `_` + data.params[`name`].(string))
-	row, err := model.GetOneRow(`SELECT `+cols+` FROM `+table+` WHERE id = ?`, data.params[`id`].(string)).String()
+	where, params, err := rowReadFilter(data, data.params[`name`].(string))
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.InvalidObject, "error": err, "table": data.params["name"].(string)}).Error("getting row condition")
+		return errorAPI(w, err, http.StatusBadRequest)
+	}
+	if len(where) > 0 {
+		where = ` AND ` + where
+	}
+	row, err := model.GetOneRow(`SELECT `+cols+` FROM `+table+` WHERE id = ?`+where,
+		append([]interface{}{data.params[`id`].(string)}, params...)...).String()
 	if err != nil {
 		logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "table": data.params["name"].(string), "id": data.params["id"].(string)}).Error("getting one row")
 		return errorAPI(w, `E_QUERY`, http.StatusInternalServerError)
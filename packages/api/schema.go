@@ -0,0 +1,146 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/script"
+	"github.com/GenesisKernel/go-genesis/packages/smart"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type schemaField struct {
+	Name     string   `json:"name"`
+	Type     string   `json:"type"`
+	TxType   string   `json:"txtype"`
+	Optional bool     `json:"optional"`
+	Tags     []string `json:"tags"`
+}
+
+type contractSchema struct {
+	ID         uint32        `json:"id"`
+	Name       string        `json:"name"`
+	Ecosystem  uint32        `json:"ecosystem"`
+	Active     bool          `json:"active"`
+	Fields     []schemaField `json:"fields"`
+	Conditions string        `json:"conditions"`
+}
+
+type contractSchemasResult struct {
+	Count string            `json:"count"`
+	List  []*contractSchema `json:"list"`
+}
+
+func splitTags(tags string) []string {
+	return strings.FieldsFunc(tags, func(r rune) bool {
+		return r == ' ' || r == ','
+	})
+}
+
+// newContractSchema returns the data fields and conditions of the contract, source is the code of its row in contracts table
+func newContractSchema(contract *smart.Contract, source string) *contractSchema {
+	info := contract.Block.Info.(*script.ContractInfo)
+	schema := &contractSchema{ID: info.ID, Name: info.Name, Ecosystem: info.Owner.StateID,
+		Active: info.Owner.Active, Fields: make([]schemaField, 0)}
+	if info.Tx != nil {
+		for _, fitem := range *info.Tx {
+			tags := splitTags(fitem.Tags)
+			field := schemaField{Name: fitem.Name, Type: script.TypeName(fitem.Type),
+				TxType: fitem.Type.String(), Tags: tags}
+			for _, tag := range tags {
+				if tag == `optional` {
+					field.Optional = true
+				}
+			}
+			schema.Fields = append(schema.Fields, field)
+		}
+	}
+	schema.Conditions = script.ContractConditions(source, strings.TrimPrefix(info.Name,
+		fmt.Sprintf(`@%d`, info.Owner.StateID)))
+	return schema
+}
+
+func contractsTable(data *apiData, ecosystemID uint32) string {
+	table := converter.Int64ToStr(int64(ecosystemID))
+	if data.vde {
+		table += `_vde`
+	}
+	return table + `_contracts`
+}
+
+func getContractSchema(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	name := data.params[`name`].(string)
+	contract := smart.VMGetContract(data.vm, name, uint32(data.ecosystemId))
+	if contract == nil {
+		logger.WithFields(log.Fields{"type": consts.ContractError, "contract_name": name}).Error("contract name")
+		return errorAPI(w, `E_CONTRACT`, http.StatusBadRequest, name)
+	}
+	owner := contract.Block.Info.(*script.ContractInfo).Owner
+	source, err := model.Single(`SELECT value FROM "`+contractsTable(data, owner.StateID)+`" WHERE id = ?`,
+		owner.TableID).String()
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting contract source")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	data.result = newContractSchema(contract, source)
+	return nil
+}
+
+func getContractSchemas(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	limit := 25
+	if data.params[`limit`].(int64) > 0 {
+		limit = int(data.params[`limit`].(int64))
+	}
+	table := contractsTable(data, uint32(data.ecosystemId))
+	count, err := model.GetRecordsCountTx(nil, table)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting count of contracts")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	rows, err := model.GetAll(`select id, value from "`+table+`" order by id`+
+		fmt.Sprintf(` offset %d `, data.params[`offset`].(int64)), limit)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting contracts")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	result := &contractSchemasResult{Count: converter.Int64ToStr(count), List: make([]*contractSchema, 0)}
+	for _, row := range rows {
+		for _, name := range script.ContractsList(row[`value`]) {
+			contract := smart.VMGetContract(data.vm, name, uint32(data.ecosystemId))
+			if contract == nil {
+				// functions and the contracts which haven't been compiled
+				continue
+			}
+			result.List = append(result.List, newContractSchema(contract, row[`value`]))
+		}
+	}
+	data.result = result
+	return nil
+}
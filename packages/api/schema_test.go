@@ -0,0 +1,70 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package api
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/GenesisKernel/go-genesis/packages/script"
+	"github.com/GenesisKernel/go-genesis/packages/smart"
+)
+
+func TestContractSchema(t *testing.T) {
+	source := `contract TestSchema {
+		data {
+			Name string
+			Amount money "optional"
+			Image string "image optional"
+		}
+		conditions {
+			if $Amount < 0 {
+				error "wrong amount"
+			}
+		}
+		action {}
+	}`
+	vm := script.NewVM()
+	if err := vm.Compile([]rune(source), &script.OwnerInfo{StateID: 2, Active: true, TableID: 5}); err != nil {
+		t.Fatal(err)
+	}
+	contract := smart.VMGetContract(vm, `TestSchema`, 2)
+	if contract == nil {
+		t.Fatal(`contract isn't compiled`)
+	}
+	schema := newContractSchema(contract, source)
+	if schema.Name != `@2TestSchema` || schema.Ecosystem != 2 || !schema.Active {
+		t.Errorf(`wrong schema %+v`, schema)
+	}
+	want := []schemaField{
+		{Name: `Name`, Type: `string`, TxType: `string`, Tags: []string{}},
+		{Name: `Amount`, Type: `money`, TxType: script.Decimal, Optional: true, Tags: []string{`optional`}},
+		{Name: `Image`, Type: `string`, TxType: `string`, Optional: true, Tags: []string{`image`, `optional`}},
+	}
+	if !reflect.DeepEqual(schema.Fields, want) {
+		t.Errorf(`wrong fields %+v`, schema.Fields)
+	}
+	if schema.Conditions != "if $Amount < 0 {\n\t\t\t\terror \"wrong amount\"\n\t\t\t}" {
+		t.Errorf(`wrong conditions %q`, schema.Conditions)
+	}
+}
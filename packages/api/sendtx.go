@@ -0,0 +1,62 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package api
+
+import (
+	"encoding/hex"
+	"net/http"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/parser"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// sendTx relays the contract transaction which has been signed offline
+func sendTx(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	blob := data.params[`data`].([]byte)
+	if len(blob) == 0 || !parser.IsContractTransaction(int(blob[0])) {
+		return errorAPI(w, `E_BADTX`, http.StatusBadRequest, `contract transaction is expected`)
+	}
+	header, err := parser.CheckTransaction(blob)
+	if sizeErr, ok := err.(*parser.TxSizeError); ok {
+		return errorTxSize(w, sizeErr)
+	}
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.ParseError, "error": err}).Error("checking relayed transaction")
+		return errorAPI(w, `E_BADTX`, http.StatusBadRequest, err.Error())
+	}
+	hash, err := model.SendExpiringTx(int64(header.Type), header.KeyID, header.ExpireBlock, header.ExpireTime, blob, data.requestID)
+	if err != nil {
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	watchTx(data, hash)
+	data.result = &contractResult{Hash: hex.EncodeToString(hash)}
+	return nil
+}
+
+// errorTxSize returns E_LIMITTXSIZE error to the submitter of transaction
+func errorTxSize(w http.ResponseWriter, err *parser.TxSizeError) error {
+	return errorAPI(w, `E_LIMITTXSIZE`, http.StatusRequestEntityTooLarge, err.Size, err.Type, err.Limit)
+}
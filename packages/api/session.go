@@ -0,0 +1,195 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/notificator"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// revokedReload is the interval of reloading the revoked sessions from the database
+	revokedReload   = 10 * time.Second
+	maxDeviceLength = 255
+)
+
+type sessionItem struct {
+	ID       string `json:"id"`
+	Device   string `json:"device"`
+	Remote   string `json:"remote"`
+	Created  string `json:"created"`
+	LastSeen string `json:"last_seen"`
+	Expire   string `json:"expire"`
+	Current  bool   `json:"current,omitempty"`
+}
+
+type sessionsResult struct {
+	List []sessionItem `json:"list"`
+}
+
+type revokeResult struct {
+	Revoked string `json:"revoked"`
+}
+
+// revokedSessions is the cache of revoked sessions which are checked by each request
+var revokedSessions = struct {
+	sync.RWMutex
+	ids    map[string]bool
+	loaded time.Time
+}{}
+
+// isRevoked returns true if the session has been revoked
+func isRevoked(id string) bool {
+	revokedSessions.RLock()
+	fresh := time.Since(revokedSessions.loaded) < revokedReload
+	revoked := revokedSessions.ids[id]
+	revokedSessions.RUnlock()
+	if fresh {
+		return revoked
+	}
+	list, err := model.GetRevokedSessions(time.Now().Unix())
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting revoked sessions")
+		return revoked
+	}
+	ids := make(map[string]bool, len(list))
+	for _, item := range list {
+		ids[item] = true
+	}
+	revokedSessions.Lock()
+	revokedSessions.ids, revokedSessions.loaded = ids, time.Now()
+	revokedSessions.Unlock()
+	return ids[id]
+}
+
+func addRevoked(id string) {
+	revokedSessions.Lock()
+	if revokedSessions.ids == nil {
+		revokedSessions.ids = make(map[string]bool)
+	}
+	revokedSessions.ids[id] = true
+	revokedSessions.Unlock()
+}
+
+// newSession registers the session of the key with the device metadata of the request
+func newSession(r *http.Request, data *apiData, state, wallet, expire int64) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return ``, err
+	}
+	device := data.params[`device`].(string)
+	if len(device) == 0 {
+		device = r.UserAgent()
+	}
+	if len(device) > maxDeviceLength {
+		device = device[:maxDeviceLength]
+	}
+	now := time.Now().Unix()
+	session := &model.Session{ID: hex.EncodeToString(buf), KeyID: wallet, Ecosystem: state, Device: device,
+		Remote: r.RemoteAddr, Created: now, LastSeen: now, Expire: expire}
+	if err := session.Create(); err != nil {
+		return ``, err
+	}
+	return session.ID, nil
+}
+
+func sessionClaims(data *apiData) *JWTClaims {
+	if data.token != nil && data.token.Valid {
+		if claims, ok := data.token.Claims.(*JWTClaims); ok {
+			return claims
+		}
+	}
+	return nil
+}
+
+func getSessions(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	limit := data.params[`limit`].(int64)
+	if limit <= 0 {
+		limit = 25
+	}
+	list, err := model.GetActiveSessions(data.keyId, data.ecosystemId, time.Now().Unix(),
+		data.params[`offset`].(int64), limit)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting sessions")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	var current string
+	if claims := sessionClaims(data); claims != nil {
+		current = claims.Id
+	}
+	result := sessionsResult{List: make([]sessionItem, 0, len(list))}
+	for _, item := range list {
+		result.List = append(result.List, sessionItem{ID: item.ID, Device: item.Device, Remote: item.Remote,
+			Created: converter.Int64ToStr(item.Created), LastSeen: converter.Int64ToStr(item.LastSeen),
+			Expire: converter.Int64ToStr(item.Expire), Current: item.ID == current})
+	}
+	data.result = &result
+	return nil
+}
+
+// revokeSession revokes the session of the key, the current session is revoked if id is empty
+func revokeSession(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	id := data.params[`id`].(string)
+	if len(id) == 0 {
+		if claims := sessionClaims(data); claims != nil {
+			id = claims.Id
+		}
+	}
+	if len(id) == 0 {
+		return errorAPI(w, `E_SESSION`, http.StatusNotFound, id)
+	}
+	found, err := model.RevokeSession(id, data.keyId, data.ecosystemId, time.Now().Unix())
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("revoking session")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	if !found {
+		logger.WithFields(log.Fields{"type": consts.NotFound, "session": id}).Error("session is not found")
+		return errorAPI(w, `E_SESSION`, http.StatusNotFound, id)
+	}
+	addRevoked(id)
+	data.result = &revokeResult{Revoked: id}
+	return nil
+}
+
+// watchTx sends the statuses of the submitted transaction to the channel of the session
+func watchTx(data *apiData, hash []byte) {
+	if data.keyId == 0 {
+		return
+	}
+	var session string
+	if claims := sessionClaims(data); claims != nil {
+		session = claims.Id
+	}
+	notificator.WatchTx(hash, data.keyId, session)
+}
@@ -0,0 +1,58 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package api
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestSessions(t *testing.T) {
+	if err := keyLogin(1); err != nil {
+		t.Fatal(err)
+	}
+	var list sessionsResult
+	if err := sendGet(`sessions`, nil, &list); err != nil {
+		t.Fatal(err)
+	}
+	var current string
+	for _, item := range list.List {
+		if item.Current {
+			current = item.ID
+		}
+	}
+	if len(current) == 0 {
+		t.Fatal(`current session is not found`)
+	}
+	var ret revokeResult
+	if err := sendPost(`session/revoke`, &url.Values{}, &ret); err != nil {
+		t.Fatal(err)
+	}
+	if ret.Revoked != current {
+		t.Errorf(`wrong revoked session %s`, ret.Revoked)
+	}
+	if err := sendGet(`sessions`, nil, &list); err == nil || !strings.Contains(err.Error(), `E_TOKENREVOKED`) {
+		t.Errorf(`revoked token is accepted %v`, err)
+	}
+}
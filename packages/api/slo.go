@@ -0,0 +1,240 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	sloMaxSamples     = 1000 // the most recent requests which are kept for each route
+	sloWebhookTimeout = 5 * time.Second
+)
+
+type sloSample struct {
+	time     time.Time
+	duration time.Duration
+	failed   bool
+}
+
+type sloRoute struct {
+	samples []sloSample
+	alerted time.Time
+}
+
+type sloStats struct {
+	Route     string  `json:"route"`
+	Count     int     `json:"count"`
+	Errors    int     `json:"errors"`
+	ErrorRate float64 `json:"error_rate"` // in percents
+	P50       int64   `json:"p50"`        // in milliseconds
+	P95       int64   `json:"p95"`
+	P99       int64   `json:"p99"`
+}
+
+type sloAlert struct {
+	sloStats
+	Reason string `json:"reason"`
+	Time   int64  `json:"time"`
+}
+
+// sloTracker keeps the latencies and statuses of API routes over the sliding window
+type sloTracker struct {
+	mutex  sync.Mutex
+	routes map[string]*sloRoute
+}
+
+var slo = &sloTracker{routes: make(map[string]*sloRoute)}
+
+// statusWriter remembers the status code of the response
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *statusWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+func percentile(sorted []time.Duration, p float64) int64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	return int64(sorted[idx] / time.Millisecond)
+}
+
+func (rs *sloRoute) prune(now time.Time, window time.Duration) {
+	i := 0
+	for i < len(rs.samples) && now.Sub(rs.samples[i].time) > window {
+		i++
+	}
+	if over := len(rs.samples) - i - sloMaxSamples; over > 0 {
+		i += over
+	}
+	if i > 0 {
+		rs.samples = append(rs.samples[:0], rs.samples[i:]...)
+	}
+}
+
+func (rs *sloRoute) stats(route string) sloStats {
+	result := sloStats{Route: route, Count: len(rs.samples)}
+	durations := make([]time.Duration, len(rs.samples))
+	for i, sample := range rs.samples {
+		durations[i] = sample.duration
+		if sample.failed {
+			result.Errors++
+		}
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	if result.Count > 0 {
+		result.ErrorRate = float64(result.Errors) * 100 / float64(result.Count)
+	}
+	result.P50 = percentile(durations, 50)
+	result.P95 = percentile(durations, 95)
+	result.P99 = percentile(durations, 99)
+	return result
+}
+
+// record adds the request to the window of the route and returns the alert if the route breaks the objectives,
+// the alert of the route is repeated not more often than once per window
+func (t *sloTracker) record(route string, now time.Time, duration time.Duration, status int, cfg conf.SLOConfig) *sloAlert {
+	window := time.Duration(cfg.Window) * time.Second
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	rs := t.routes[route]
+	if rs == nil {
+		rs = &sloRoute{}
+		t.routes[route] = rs
+	}
+	rs.samples = append(rs.samples, sloSample{time: now, duration: duration, failed: status >= http.StatusInternalServerError})
+	rs.prune(now, window)
+	if int64(len(rs.samples)) < cfg.MinRequests || now.Sub(rs.alerted) < window {
+		return nil
+	}
+	stats := rs.stats(route)
+	var reason string
+	switch {
+	case cfg.P99Latency > 0 && stats.P99 > cfg.P99Latency:
+		reason = "latency"
+	case cfg.ErrorRate > 0 && stats.ErrorRate > cfg.ErrorRate:
+		reason = "error_rate"
+	default:
+		return nil
+	}
+	rs.alerted = now
+	return &sloAlert{sloStats: stats, Reason: reason, Time: now.Unix()}
+}
+
+func (t *sloTracker) list(now time.Time, cfg conf.SLOConfig) []sloStats {
+	window := time.Duration(cfg.Window) * time.Second
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	result := make([]sloStats, 0, len(t.routes))
+	for route, rs := range t.routes {
+		rs.prune(now, window)
+		if len(rs.samples) > 0 {
+			result = append(result, rs.stats(route))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Route < result[j].Route })
+	return result
+}
+
+// trackSLO records the request and reports the breach of the objectives to the log and the webhook
+func trackSLO(route string, duration time.Duration, status int) {
+	cfg := conf.SLO()
+	if cfg.Window <= 0 {
+		return
+	}
+	alert := slo.record(route, time.Now(), duration, status, cfg)
+	if alert == nil {
+		return
+	}
+	log.WithFields(log.Fields{"type": consts.ParameterExceeded, "route": alert.Route, "reason": alert.Reason,
+		"p50": alert.P50, "p95": alert.P95, "p99": alert.P99, "error_rate": alert.ErrorRate, "count": alert.Count}).Warning("API route breaks SLO")
+	if len(cfg.WebhookURL) > 0 {
+		go postSLOAlert(cfg.WebhookURL, alert)
+	}
+}
+
+func postSLOAlert(url string, alert *sloAlert) {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Error("marshalling SLO alert")
+		return
+	}
+	client := &http.Client{Timeout: sloWebhookTimeout}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.NetworkError, "error": err}).Error("posting SLO alert")
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		log.WithFields(log.Fields{"type": consts.NetworkError, "status": resp.StatusCode}).Error("SLO alert is rejected by webhook")
+	}
+}
+
+type sloResult struct {
+	Window int64      `json:"window"`
+	Routes []sloStats `json:"routes"`
+}
+
+func getSLO(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	cfg := conf.SLO()
+	data.result = &sloResult{Window: cfg.Window, Routes: slo.list(time.Now(), cfg)}
+	return nil
+}
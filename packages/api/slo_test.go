@@ -0,0 +1,66 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+)
+
+func TestSLOTracker(t *testing.T) {
+	cfg := conf.SLOConfig{Window: 60, MinRequests: 10, P99Latency: 500, ErrorRate: 20}
+	tracker := &sloTracker{routes: make(map[string]*sloRoute)}
+	now := time.Now()
+	for i := 1; i <= 100; i++ {
+		if alert := tracker.record(`GET a`, now, time.Duration(i)*time.Millisecond, http.StatusOK, cfg); alert != nil {
+			t.Errorf(`unexpected alert %v`, alert)
+		}
+	}
+	stats := tracker.list(now, cfg)
+	if len(stats) != 1 || stats[0].Count != 100 || stats[0].P50 != 50 || stats[0].P95 != 95 || stats[0].P99 != 99 {
+		t.Errorf(`wrong stats %+v`, stats)
+	}
+
+	var alerts int
+	for i := 0; i < 10; i++ {
+		if alert := tracker.record(`GET b`, now, time.Millisecond, http.StatusInternalServerError, cfg); alert != nil {
+			if alert.Reason != `error_rate` || i != 9 {
+				t.Errorf(`wrong alert %d %+v`, i, alert)
+			}
+			alerts++
+		}
+	}
+	if tracker.record(`GET b`, now.Add(time.Second), time.Second, http.StatusOK, cfg) != nil || alerts != 1 {
+		t.Error(`alert must be repeated once per window`)
+	}
+	alert := tracker.record(`GET b`, now.Add(61*time.Second), time.Second, http.StatusOK, cfg)
+	if alert != nil {
+		t.Errorf(`expired samples must be pruned %+v`, alert)
+	}
+	if stats = tracker.list(now.Add(61*time.Second), cfg); len(stats) != 1 || stats[0].Route != `GET b` || stats[0].Count != 2 || stats[0].Errors != 0 {
+		t.Errorf(`wrong stats after window %+v`, stats)
+	}
+}
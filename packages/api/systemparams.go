@@ -60,3 +60,17 @@ func systemParams(w http.ResponseWriter, r *http.Request, data *apiData, logger
 	data.result = &result
 	return
 }
+
+type sysParamScheduleResult struct {
+	List []model.SysParamSchedule `json:"list"`
+}
+
+func systemParamsSchedule(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	list, err := model.GetPendingSysParams(listLimit(data), int(data.params[`offset`].(int64)))
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting scheduled system parameters")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	data.result = &sysParamScheduleResult{List: list}
+	return nil
+}
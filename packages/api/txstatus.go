@@ -30,6 +30,7 @@ import (
 	"github.com/GenesisKernel/go-genesis/packages/consts"
 	"github.com/GenesisKernel/go-genesis/packages/converter"
 	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/parser"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -43,6 +44,11 @@ type txstatusResult struct {
 	BlockID string         `json:"blockid"`
 	Message *txstatusError `json:"errmsg,omitempty"`
 	Result  string         `json:"result"`
+	Payer   string         `json:"payer,omitempty"`
+	// ExpireBlock and ExpireTime are the expiration of the transaction, errmsg has expired type if it has been dropped
+	ExpireBlock string `json:"expire_block,omitempty"`
+	ExpireTime  string `json:"expire_time,omitempty"`
+	RequestID   string `json:"request_id,omitempty"`
 }
 
 func txstatus(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
@@ -62,9 +68,19 @@ func txstatus(w http.ResponseWriter, r *http.Request, data *apiData, logger *log
 		logger.WithFields(log.Fields{"type": consts.NotFound, "key": []byte(converter.HexToBin(data.params["hash"].(string)))}).Error("getting transaction status by hash")
 		return errorAPI(w, `E_HASHNOTFOUND`, http.StatusBadRequest)
 	}
+	status.RequestID = ts.RequestID
+	if ts.ExpireBlock > 0 {
+		status.ExpireBlock = converter.Int64ToStr(ts.ExpireBlock)
+	}
+	if ts.ExpireTime > 0 {
+		status.ExpireTime = converter.Int64ToStr(ts.ExpireTime)
+	}
 	if ts.BlockID > 0 {
 		status.BlockID = converter.Int64ToStr(ts.BlockID)
 		status.Result = ts.Error
+		if ts.Payer != 0 {
+			status.Payer = converter.AddressToString(ts.Payer)
+		}
 	} else if len(ts.Error) > 0 {
 		if err := json.Unmarshal([]byte(ts.Error), &status.Message); err != nil {
 			logger.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "text": ts.Error,
@@ -75,3 +91,30 @@ func txstatus(w http.ResponseWriter, r *http.Request, data *apiData, logger *log
 	data.result = &status
 	return nil
 }
+
+type txcancelResult struct {
+	Hash   string `json:"hash"`
+	Status string `json:"status"`
+}
+
+// cancelTx removes the pending transaction from the queue. The signature is the signature of
+// CANCEL and the hex hash of transaction by the key which has signed the transaction
+func cancelTx(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	hash, err := hex.DecodeString(data.params[`hash`].(string))
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.ConversionError, "error": err}).Error("decoding tx hash from hex")
+		return errorAPI(w, `E_HASHWRONG`, http.StatusBadRequest)
+	}
+	switch err = parser.CancelTx(hash, data.params[`signature`].([]byte)); err {
+	case nil:
+	case parser.ErrTxNotPending:
+		return errorAPI(w, `E_TXNOTPENDING`, http.StatusBadRequest, data.params[`hash`].(string))
+	case parser.ErrCancelSign:
+		logger.WithFields(log.Fields{"type": consts.InvalidObject, "hash": data.params[`hash`]}).Error("incorrect signature of cancellation")
+		return errorAPI(w, `E_SIGNATURE`, http.StatusBadRequest)
+	default:
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	data.result = &txcancelResult{Hash: hex.EncodeToString(hash), Status: `cancelled`}
+	return nil
+}
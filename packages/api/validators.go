@@ -0,0 +1,63 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type validatorItem struct {
+	Wallet string `json:"wallet"`
+	Host   string `json:"host"`
+	Stake  string `json:"stake"`
+	Active bool   `json:"active"`
+}
+
+type validatorsResult struct {
+	List []validatorItem `json:"list"`
+}
+
+func getValidators(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	list, err := model.GetValidatorCandidates(data.params[`offset`].(int64), int64(listLimit(data)))
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting validator candidates")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	result := validatorsResult{List: make([]validatorItem, 0, len(list))}
+	for _, item := range list {
+		result.List = append(result.List, validatorItem{
+			Wallet: converter.AddressToString(item.ID),
+			Host:   item.Host,
+			Stake:  item.Stake,
+			Active: item.Active != 0,
+		})
+	}
+	data.result = &result
+	return nil
+}
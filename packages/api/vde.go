@@ -64,10 +64,39 @@ func vdeCreate(w http.ResponseWriter, r *http.Request, data *apiData, logger *lo
 		return errorAPI(w, err, http.StatusInternalServerError)
 	}
 	smart.LoadVDEContracts(nil, converter.Int64ToStr(data.ecosystemId))
+	writeAudit(r, data.keyId, data.ecosystemId, model.AuditVDE, logger)
 	data.result = vdeCreateResult{Result: true}
 	return nil
 }
 
+type vdeConsoleResult struct {
+	Result string `json:"result"`
+}
+
+// vdeConsole evaluates the code against VDE, it is allowed only for the founder of VDE
+func vdeConsole(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	if smart.GetVM(true, data.ecosystemId) == nil {
+		return errorAPI(w, `E_VDE`, http.StatusBadRequest, data.ecosystemId)
+	}
+	sp := &model.StateParameter{}
+	sp.SetTablePrefix(converter.Int64ToStr(data.ecosystemId) + `_vde`)
+	if _, err := sp.Get(nil, `founder_account`); err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting VDE founder")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	if converter.StrToInt64(sp.Value) != data.keyId {
+		logger.WithFields(log.Fields{"type": consts.AccessDenied, "key_id": data.keyId}).Error("VDE console")
+		return errorAPI(w, `E_PERMISSION`, http.StatusUnauthorized)
+	}
+	writeAudit(r, data.keyId, data.ecosystemId, model.AuditVDE, logger)
+	ret, err := smart.ConsoleEval(data.ecosystemId, data.keyId, data.params[`code`].(string))
+	if err != nil {
+		return errorAPI(w, `E_CONSOLE`, http.StatusBadRequest, err.Error())
+	}
+	data.result = &vdeConsoleResult{Result: ret}
+	return nil
+}
+
 // InitSmartContract is initializes smart contract
 func InitSmartContract(sc *smart.SmartContract, data []byte) error {
 	if err := msgpack.Unmarshal(data, &sc.TxSmart); err != nil {
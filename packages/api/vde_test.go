@@ -449,11 +449,24 @@ func TestVDEImport(t *testing.T) {
 	}
 }
 
+func setVDEParameter(name, value string) error {
+	var par paramValue
+	if err := sendGet(`ecosystemparam/`+name+`?vde=true`, nil, &par); err != nil {
+		return err
+	}
+	return postTx(`EditParameter`, &url.Values{`Id`: {par.ID}, `Value`: {value},
+		`Conditions`: {par.Conditions}, `vde`: {`true`}})
+}
+
 func TestHTTPRequest(t *testing.T) {
 	if err := keyLogin(1); err != nil {
 		t.Error(err)
 		return
 	}
+	if err := setVDEParameter(`http_allowlist`, `www.instagram.com,www.google.com,localhost`); err != nil {
+		t.Error(err)
+		return
+	}
 	rnd := `rnd` + crypto.RandSeq(6)
 	form := url.Values{`Value`: {`contract ` + rnd + ` {
 		    data {
@@ -508,6 +521,11 @@ func TestNodeHTTPRequest(t *testing.T) {
 		return
 	}
 
+	if err = setVDEParameter(`http_allowlist`, `localhost`); err != nil {
+		t.Error(err)
+		return
+	}
+
 	rnd := `rnd` + crypto.RandSeq(4)
 
 	form := url.Values{`Value`: {`contract for` + rnd + ` {
@@ -0,0 +1,306 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package apps
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/apps/manifest"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/crypto"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/script"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrWrongSignature is returned if the signature of bundle is invalid
+var ErrWrongSignature = errors.New("wrong signature of bundle")
+
+// Item is an exported object, its keys are the parameters of New* contracts
+type Item map[string]string
+
+// TableData is the exported rows of table
+type TableData struct {
+	Table   string     `json:"Table"`
+	Columns []string   `json:"Columns"`
+	Data    [][]string `json:"Data"`
+}
+
+// Data is the content of application in the format of Import contract
+type Data struct {
+	Pages      []Item      `json:"pages,omitempty"`
+	Blocks     []Item      `json:"blocks,omitempty"`
+	Menus      []Item      `json:"menus,omitempty"`
+	Parameters []Item      `json:"parameters,omitempty"`
+	Languages  []Item      `json:"languages,omitempty"`
+	Contracts  []Item      `json:"contracts,omitempty"`
+	Tables     []Item      `json:"tables,omitempty"`
+	Data       []TableData `json:"data,omitempty"`
+}
+
+// Bundle is the signed package of application
+type Bundle struct {
+	Name      string `json:"name"`
+	Ecosystem int64  `json:"ecosystem"`
+	VDE       bool   `json:"vde"`
+	Time      int64  `json:"time"`
+	Data      Data   `json:"data"`
+	// Manifest is defined for the bundles which are installed as versioned applications
+	Manifest  *manifest.Manifest `json:"manifest,omitempty"`
+	KeyID     int64              `json:"key_id"`
+	PublicKey string             `json:"public_key"`
+	Signature string             `json:"signature"`
+}
+
+// Selection is the list of objects which are exported
+type Selection struct {
+	Contracts  []string
+	Pages      []string
+	Menus      []string
+	Blocks     []string
+	Parameters []string
+	Languages  []string
+	Tables     []string
+	WithData   bool // export rows of tables
+}
+
+func quoteNames(names []string) (string, []interface{}) {
+	marks := make([]string, len(names))
+	args := make([]interface{}, len(names))
+	for i, name := range names {
+		marks[i] = "?"
+		args[i] = name
+	}
+	return strings.Join(marks, ","), args
+}
+
+func getItems(table, columns string, names []string) ([]Item, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	marks, args := quoteNames(names)
+	rows, err := model.GetAllTransaction(nil, fmt.Sprintf(`SELECT %s FROM "%s" WHERE name IN (%s) ORDER BY name`,
+		columns, table, marks), -1, args...)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err, "table": table}).Error("getting exported items")
+		return nil, err
+	}
+	if len(rows) != len(names) {
+		return nil, fmt.Errorf("some of %s are not found", strings.Join(names, ","))
+	}
+	items := make([]Item, 0, len(rows))
+	for _, row := range rows {
+		item := make(Item)
+		for key, value := range row {
+			item[strings.Title(key)] = value
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func getContracts(prefix string, names []string) ([]Item, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	rows, err := model.GetAllTransaction(nil, fmt.Sprintf(`SELECT value, conditions FROM "%s_contracts" ORDER BY id`, prefix), -1)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting exported contracts")
+		return nil, err
+	}
+	wanted := make(map[string]bool)
+	for _, name := range names {
+		wanted[name] = true
+	}
+	items := make([]Item, 0, len(names))
+	for _, row := range rows {
+		list := script.ContractsList(row["value"])
+		if len(list) == 0 || !wanted[list[0]] {
+			continue
+		}
+		delete(wanted, list[0])
+		items = append(items, Item{"Name": list[0], "Value": row["value"], "Conditions": row["conditions"]})
+	}
+	if len(wanted) > 0 {
+		missing := make([]string, 0, len(wanted))
+		for name := range wanted {
+			missing = append(missing, name)
+		}
+		sort.Strings(missing)
+		return nil, fmt.Errorf("contracts %s are not found", strings.Join(missing, ","))
+	}
+	return items, nil
+}
+
+func getTables(prefix string, names []string, withData bool) ([]Item, []TableData, error) {
+	var data []TableData
+	items, err := getItems(prefix+"_tables", "name,columns,permissions", names)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, item := range items {
+		var perms map[string]string
+		if err = json.Unmarshal([]byte(item["Columns"]), &perms); err != nil {
+			log.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err}).Error("unmarshalling columns of table")
+			return nil, nil, err
+		}
+		columnNames := make([]string, 0, len(perms))
+		for name := range perms {
+			columnNames = append(columnNames, name)
+		}
+		sort.Strings(columnNames)
+		tableName := prefix + "_" + item["Name"]
+		columns := make([]map[string]string, 0, len(columnNames))
+		for _, name := range columnNames {
+			colType, err := model.GetColumnType(tableName, name)
+			if err != nil {
+				log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting column type")
+				return nil, nil, err
+			}
+			columns = append(columns, map[string]string{"name": name, "type": colType, "conditions": perms[name]})
+		}
+		out, err := json.Marshal(columns)
+		if err != nil {
+			return nil, nil, err
+		}
+		item["Columns"] = string(out)
+		if !withData || len(columnNames) == 0 {
+			continue
+		}
+		rows, err := model.GetAllTransaction(nil, fmt.Sprintf(`SELECT "%s" FROM "%s" ORDER BY id`,
+			strings.Join(columnNames, `","`), tableName), -1)
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting rows of table")
+			return nil, nil, err
+		}
+		td := TableData{Table: item["Name"], Columns: columnNames, Data: make([][]string, 0, len(rows))}
+		for _, row := range rows {
+			values := make([]string, len(columnNames))
+			for i, name := range columnNames {
+				values[i] = row[name]
+			}
+			td.Data = append(td.Data, values)
+		}
+		data = append(data, td)
+	}
+	return items, data, nil
+}
+
+// Export collects the selected objects of ecosystem, prefix is like "1" or "1_vde"
+func Export(prefix string, sel *Selection) (*Data, error) {
+	var (
+		data Data
+		err  error
+	)
+	if data.Contracts, err = getContracts(prefix, sel.Contracts); err != nil {
+		return nil, err
+	}
+	if data.Pages, err = getItems(prefix+"_pages", "name,value,menu,conditions", sel.Pages); err != nil {
+		return nil, err
+	}
+	if data.Menus, err = getItems(prefix+"_menu", "name,title,value,conditions", sel.Menus); err != nil {
+		return nil, err
+	}
+	if data.Blocks, err = getItems(prefix+"_blocks", "name,value,conditions", sel.Blocks); err != nil {
+		return nil, err
+	}
+	if data.Parameters, err = getItems(prefix+"_parameters", "name,value,conditions", sel.Parameters); err != nil {
+		return nil, err
+	}
+	if data.Languages, err = getItems(prefix+"_languages", "name,res AS trans", sel.Languages); err != nil {
+		return nil, err
+	}
+	if data.Tables, data.Data, err = getTables(prefix, sel.Tables, sel.WithData); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// ForSign returns the string which is signed by the author of bundle
+func (b *Bundle) ForSign() (string, error) {
+	unsigned := *b
+	unsigned.Signature = ""
+	out, err := json.Marshal(unsigned)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Error("marshalling bundle")
+		return "", err
+	}
+	return string(out), nil
+}
+
+// NewUnsignedBundle creates the bundle of the author with the public key, it's signed by the author later
+func NewUnsignedBundle(name string, ecosystem int64, vde bool, data *Data, m *manifest.Manifest, pub []byte) *Bundle {
+	return &Bundle{Name: name, Ecosystem: ecosystem, VDE: vde, Time: time.Now().Unix(), Data: *data,
+		Manifest: m, KeyID: crypto.Address(pub), PublicKey: hex.EncodeToString(pub)}
+}
+
+// NewBundle creates the bundle signed by the private key in hex, the manifest can be nil
+func NewBundle(name string, ecosystem int64, vde bool, data *Data, m *manifest.Manifest, privateKey string) (*Bundle, error) {
+	priv, err := hex.DecodeString(strings.TrimSpace(privateKey))
+	if err != nil {
+		return nil, err
+	}
+	pub, err := crypto.PrivateToPublic(priv)
+	if err != nil {
+		return nil, err
+	}
+	b := NewUnsignedBundle(name, ecosystem, vde, data, m, pub)
+	forSign, err := b.ForSign()
+	if err != nil {
+		return nil, err
+	}
+	signature, err := crypto.Sign(strings.TrimSpace(privateKey), forSign)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("signing bundle")
+		return nil, err
+	}
+	b.Signature = hex.EncodeToString(signature)
+	return b, nil
+}
+
+// Verify checks the signature of bundle
+func (b *Bundle) Verify() error {
+	pub, err := hex.DecodeString(b.PublicKey)
+	if err != nil {
+		return ErrWrongSignature
+	}
+	signature, err := hex.DecodeString(b.Signature)
+	if err != nil || crypto.Address(pub) != b.KeyID {
+		return ErrWrongSignature
+	}
+	forSign, err := b.ForSign()
+	if err != nil {
+		return err
+	}
+	if ok, err := crypto.CheckSign(pub, forSign, signature); err != nil || !ok {
+		return ErrWrongSignature
+	}
+	return nil
+}
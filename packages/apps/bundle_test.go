@@ -0,0 +1,60 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package apps
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/GenesisKernel/go-genesis/packages/crypto"
+)
+
+func TestUnsignedBundle(t *testing.T) {
+	priv, pub, err := crypto.GenBytesKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := NewUnsignedBundle("app", 1, false, &Data{}, nil, pub)
+	if b.KeyID != crypto.Address(pub) {
+		t.Errorf("wrong author %d", b.KeyID)
+	}
+	if err = b.Verify(); err != ErrWrongSignature {
+		t.Error("unsigned bundle is verified")
+	}
+	forSign, err := b.ForSign()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature, err := crypto.Sign(hex.EncodeToString(priv), forSign)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Signature = hex.EncodeToString(signature)
+	if err = b.Verify(); err != nil {
+		t.Errorf("bundle signed by author isn't verified: %v", err)
+	}
+	b.Name = "other"
+	if err = b.Verify(); err != ErrWrongSignature {
+		t.Error("changed bundle is verified")
+	}
+}
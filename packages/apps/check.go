@@ -0,0 +1,173 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package apps
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/smart"
+)
+
+// Kinds of import problems
+const (
+	ProblemDependency = "dependency" // the object refers to missing object, import will fail
+	ProblemCollision  = "collision"  // the object exists in the target ecosystem
+)
+
+var (
+	reContractRef = regexp.MustCompile(`(?:ContractConditions|ContractAccess|CallContract)\(\s*"@?(\d*)(\w+)"`)
+	reTableRef    = regexp.MustCompile(`(?:DBFind|DBInsert|DBUpdate|DBRow|DBUpdateExt)\(\s*"(\w+)"`)
+)
+
+// Problem is an issue of import found before the execution of Import contract
+type Problem struct {
+	Kind    string `json:"kind"`
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Message string `json:"message"`
+}
+
+// Target is the ecosystem where the application is imported
+type Target struct {
+	Ecosystem int64
+	VDE       bool
+}
+
+func (t *Target) prefix() string {
+	prefix := strconv.FormatInt(t.Ecosystem, 10)
+	if t.VDE {
+		prefix += "_vde"
+	}
+	return prefix
+}
+
+func (t *Target) exists(table, name string) bool {
+	row, err := model.GetOneRow(fmt.Sprintf(`SELECT name FROM "%s_%s" WHERE name = ?`, t.prefix(), table), name).String()
+	return err == nil && len(row) > 0
+}
+
+func (t *Target) contractExists(ecosystem int64, name string) bool {
+	vm := smart.GetVM(t.VDE, t.Ecosystem)
+	return vm != nil && smart.VMObjectExists(vm, name, uint32(ecosystem))
+}
+
+func (t *Target) tableExists(name string) bool {
+	return model.IsTable(t.prefix() + "_" + name)
+}
+
+func names(items []Item) map[string]bool {
+	ret := make(map[string]bool)
+	for _, item := range items {
+		ret[item["Name"]] = true
+	}
+	return ret
+}
+
+// Check returns the problems which are found for the import of data to the target ecosystem
+func Check(data *Data, target *Target) []Problem {
+	problems := make([]Problem, 0)
+	add := func(kind, typ, name, format string, args ...interface{}) {
+		problems = append(problems, Problem{Kind: kind, Type: typ, Name: name, Message: fmt.Sprintf(format, args...)})
+	}
+
+	contracts, tables, menus := names(data.Contracts), names(data.Tables), names(data.Menus)
+
+	for _, item := range data.Contracts {
+		if target.contractExists(target.Ecosystem, item["Name"]) {
+			add(ProblemCollision, "contract", item["Name"], "contract exists and will be skipped")
+		}
+	}
+	for _, item := range data.Tables {
+		if target.tableExists(item["Name"]) {
+			add(ProblemCollision, "table", item["Name"], "table exists and will be skipped")
+		}
+	}
+	overwritten := func(typ string, items []Item) {
+		for _, item := range items {
+			if target.exists(typ+"s", item["Name"]) {
+				add(ProblemCollision, typ, item["Name"], "%s exists and will be overwritten", typ)
+			}
+		}
+	}
+	overwritten("page", data.Pages)
+	overwritten("block", data.Blocks)
+	overwritten("parameter", data.Parameters)
+	for _, item := range data.Menus {
+		if target.exists("menu", item["Name"]) {
+			add(ProblemCollision, "menu", item["Name"], "menu exists, the items will be appended")
+		}
+	}
+
+	for _, item := range data.Pages {
+		if menu := item["Menu"]; len(menu) > 0 && !menus[menu] && !target.exists("menu", menu) {
+			add(ProblemDependency, "page", item["Name"], "menu %s is not found", menu)
+		}
+	}
+
+	checkRefs := func(typ string, items []Item, keys ...string) {
+		for _, item := range items {
+			for _, key := range keys {
+				for _, ref := range reContractRef.FindAllStringSubmatch(item[key], -1) {
+					ecosystem := target.Ecosystem
+					if len(ref[1]) > 0 {
+						ecosystem, _ = strconv.ParseInt(ref[1], 10, 64)
+					}
+					if (ecosystem == target.Ecosystem && contracts[ref[2]]) || target.contractExists(ecosystem, ref[2]) {
+						continue
+					}
+					add(ProblemDependency, typ, item["Name"], "contract %s is not found", ref[2])
+				}
+				for _, ref := range reTableRef.FindAllStringSubmatch(item[key], -1) {
+					if !tables[ref[1]] && !target.tableExists(ref[1]) {
+						add(ProblemDependency, typ, item["Name"], "table %s is not found", ref[1])
+					}
+				}
+			}
+		}
+	}
+	checkRefs("contract", data.Contracts, "Value", "Conditions")
+	checkRefs("page", data.Pages, "Value", "Conditions")
+	checkRefs("block", data.Blocks, "Value", "Conditions")
+	checkRefs("menu", data.Menus, "Value", "Conditions")
+	checkRefs("parameter", data.Parameters, "Conditions")
+
+	for _, td := range data.Data {
+		if !tables[td.Table] && !target.tableExists(td.Table) {
+			add(ProblemDependency, "data", td.Table, "table %s is not found", td.Table)
+		}
+	}
+	return problems
+}
+
+// HasErrors returns true if there are problems which break the import
+func HasErrors(problems []Problem) bool {
+	for _, p := range problems {
+		if p.Kind == ProblemDependency {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,265 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package manifest describes the versions, dependencies and hooks of application bundles.
+// It is used by the API to check the installation and by the contracts of ecosystem to perform it
+package manifest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrNotNewer is returned if the installed version of application isn't older than the bundle
+	ErrNotNewer = errors.New("installed version is not older")
+
+	regexpName     = regexp.MustCompile(`^[\w\-]{1,100}$`)
+	regexpContract = regexp.MustCompile(`^(@\d+)?\w+$`)
+)
+
+// Version is the semantic version major.minor.patch
+type Version struct {
+	Major, Minor, Patch int64
+}
+
+// ParseVersion parses the version like 1.2.3, the omitted parts are 0
+func ParseVersion(value string) (Version, error) {
+	var v Version
+	parts := strings.Split(strings.TrimPrefix(strings.TrimSpace(value), "v"), ".")
+	if len(parts) == 0 || len(parts) > 3 {
+		return v, fmt.Errorf("wrong version %s", value)
+	}
+	dst := []*int64{&v.Major, &v.Minor, &v.Patch}
+	for i, part := range parts {
+		n, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || n < 0 {
+			return v, fmt.Errorf("wrong version %s", value)
+		}
+		*dst[i] = n
+	}
+	return v, nil
+}
+
+// Compare returns -1, 0 or 1 if v is less, equal or greater than other
+func (v Version) Compare(other Version) int {
+	for _, d := range []int64{v.Major - other.Major, v.Minor - other.Minor, v.Patch - other.Patch} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+	return 0
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Dependency is the application which must be installed, Version is the minimal version
+// of the same major version
+type Dependency struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// Satisfied returns true if the installed version is compatible with the dependency
+func (d Dependency) Satisfied(installed string) bool {
+	want, err := ParseVersion(d.Version)
+	if err != nil {
+		return false
+	}
+	have, err := ParseVersion(installed)
+	return err == nil && have.Major == want.Major && have.Compare(want) >= 0
+}
+
+// Parameter is the ecosystem parameter which must be defined for the application
+type Parameter struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// Migration is the contract which is called on the upgrade to the version
+type Migration struct {
+	Version  string `json:"version"`
+	Contract string `json:"contract"`
+}
+
+// Manifest is the description of application bundle
+type Manifest struct {
+	Name         string       `json:"name"`
+	Version      string       `json:"version"`
+	Description  string       `json:"description,omitempty"`
+	Dependencies []Dependency `json:"dependencies,omitempty"`
+	Parameters   []Parameter  `json:"parameters,omitempty"`
+	// Install is the contract which is called after the first import of application
+	Install    string      `json:"install,omitempty"`
+	Migrations []Migration `json:"migrations,omitempty"`
+	// Uninstall is the contract which is called when the application is uninstalled
+	Uninstall string `json:"uninstall,omitempty"`
+}
+
+// Parse decodes and validates the manifest
+func Parse(data string) (*Manifest, error) {
+	var m Manifest
+	if err := json.Unmarshal([]byte(data), &m); err != nil {
+		return nil, fmt.Errorf("wrong manifest: %v", err)
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func checkContract(name string) error {
+	if len(name) > 0 && !regexpContract.MatchString(name) {
+		return fmt.Errorf("wrong contract name %s", name)
+	}
+	return nil
+}
+
+// Validate checks the names and versions of manifest
+func (m *Manifest) Validate() error {
+	if !regexpName.MatchString(m.Name) {
+		return fmt.Errorf("wrong application name %s", m.Name)
+	}
+	version, err := ParseVersion(m.Version)
+	if err != nil {
+		return err
+	}
+	for _, dep := range m.Dependencies {
+		if !regexpName.MatchString(dep.Name) || dep.Name == m.Name {
+			return fmt.Errorf("wrong dependency %s", dep.Name)
+		}
+		if _, err = ParseVersion(dep.Version); err != nil {
+			return err
+		}
+	}
+	for _, param := range m.Parameters {
+		if !regexpName.MatchString(param.Name) {
+			return fmt.Errorf("wrong parameter name %s", param.Name)
+		}
+	}
+	for _, name := range []string{m.Install, m.Uninstall} {
+		if err = checkContract(name); err != nil {
+			return err
+		}
+	}
+	for _, migration := range m.Migrations {
+		v, err := ParseVersion(migration.Version)
+		if err != nil {
+			return err
+		}
+		if v.Compare(version) > 0 {
+			return fmt.Errorf("migration to %s is newer than the application", migration.Version)
+		}
+		if len(migration.Contract) == 0 {
+			return fmt.Errorf("migration to %s doesn't have contract", migration.Version)
+		}
+		if err = checkContract(migration.Contract); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Hooks returns the contracts which are called on the installation of the manifest over the installed version.
+// The empty installed version means the first installation, otherwise the migrations of the newer versions
+// are returned in the order of versions
+func (m *Manifest) Hooks(installed string) ([]string, error) {
+	hooks := make([]string, 0)
+	if len(installed) == 0 {
+		if len(m.Install) > 0 {
+			hooks = append(hooks, m.Install)
+		}
+		return hooks, nil
+	}
+	from, err := ParseVersion(installed)
+	if err != nil {
+		return nil, err
+	}
+	to, err := ParseVersion(m.Version)
+	if err != nil {
+		return nil, err
+	}
+	if from.Compare(to) >= 0 {
+		return nil, ErrNotNewer
+	}
+	migrations := make([]Migration, 0, len(m.Migrations))
+	for _, migration := range m.Migrations {
+		if v, err := ParseVersion(migration.Version); err == nil && v.Compare(from) > 0 {
+			migrations = append(migrations, migration)
+		}
+	}
+	sort.SliceStable(migrations, func(i, j int) bool {
+		a, _ := ParseVersion(migrations[i].Version)
+		b, _ := ParseVersion(migrations[j].Version)
+		return a.Compare(b) < 0
+	})
+	for _, migration := range migrations {
+		hooks = append(hooks, migration.Contract)
+	}
+	return hooks, nil
+}
+
+// Problems returns the unsatisfied dependencies and missing parameters of the manifest,
+// installed contains the versions of installed applications by names
+func (m *Manifest) Problems(installed map[string]string, hasParameter func(name string) bool) []string {
+	problems := make([]string, 0)
+	for _, dep := range m.Dependencies {
+		version, ok := installed[dep.Name]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("application %s %s is not installed", dep.Name, dep.Version))
+		} else if !dep.Satisfied(version) {
+			problems = append(problems, fmt.Sprintf("application %s %s is incompatible with required %s",
+				dep.Name, version, dep.Version))
+		}
+	}
+	for _, param := range m.Parameters {
+		if !hasParameter(param.Name) {
+			problems = append(problems, fmt.Sprintf("parameter %s is not defined", param.Name))
+		}
+	}
+	return problems
+}
+
+// Dependents returns the names of applications which depend on the application
+func Dependents(name string, manifests []*Manifest) []string {
+	names := make([]string, 0)
+	for _, m := range manifests {
+		for _, dep := range m.Dependencies {
+			if dep.Name == name {
+				names = append(names, m.Name)
+				break
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
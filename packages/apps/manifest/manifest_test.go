@@ -0,0 +1,84 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package manifest
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestManifest(t *testing.T) {
+	m, err := Parse(`{"name": "crm", "version": "2.1.0",
+		"dependencies": [{"name": "basic", "version": "1.2"}],
+		"parameters": [{"name": "crm_admin"}],
+		"install": "CRMInstall",
+		"migrations": [{"version": "2.1.0", "contract": "CRMTo210"}, {"version": "1.5", "contract": "CRMTo150"},
+			{"version": "2.0.0", "contract": "@1CRMTo200"}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		installed string
+		hooks     string
+		err       error
+	}{
+		{``, `CRMInstall`, nil},
+		{`1.0.0`, `CRMTo150,@1CRMTo200,CRMTo210`, nil},
+		{`1.5.0`, `@1CRMTo200,CRMTo210`, nil},
+		{`2.0.9`, `CRMTo210`, nil},
+		{`2.1.0`, ``, ErrNotNewer},
+		{`3.0.0`, ``, ErrNotNewer},
+	} {
+		hooks, err := m.Hooks(c.installed)
+		if err != c.err || strings.Join(hooks, `,`) != c.hooks {
+			t.Errorf(`installed %s: wrong hooks %v %v`, c.installed, hooks, err)
+		}
+	}
+
+	params := map[string]bool{`crm_admin`: true}
+	hasParam := func(name string) bool { return params[name] }
+	if p := m.Problems(map[string]string{`basic`: `1.3.1`}, hasParam); len(p) != 0 {
+		t.Errorf(`unexpected problems %v`, p)
+	}
+	if p := m.Problems(map[string]string{`basic`: `2.0.0`}, hasParam); len(p) != 1 {
+		t.Errorf(`incompatible major version is expected, got %v`, p)
+	}
+	if p := m.Problems(map[string]string{`basic`: `1.1.9`}, func(string) bool { return false }); len(p) != 2 {
+		t.Errorf(`old dependency and missing parameter are expected, got %v`, p)
+	}
+	if d := Dependents(`basic`, []*Manifest{m, {Name: `other`}}); strings.Join(d, `,`) != `crm` {
+		t.Errorf(`wrong dependents %v`, d)
+	}
+
+	for _, wrong := range []string{
+		`{"name": "a b", "version": "1"}`,
+		`{"name": "a", "version": "1.x"}`,
+		`{"name": "a", "version": "1", "migrations": [{"version": "2", "contract": "A"}]}`,
+		`{"name": "a", "version": "1", "install": "Drop Table"}`,
+		`{"name": "a", "version": "1", "dependencies": [{"name": "a", "version": "1"}]}`,
+	} {
+		if _, err = Parse(wrong); err == nil {
+			t.Errorf(`error is expected for %s`, wrong)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package auth contains the external identity providers which can be used for login
+package auth
+
+import (
+	"errors"
+)
+
+var (
+	// ErrDisabled is returned if the provider isn't configured
+	ErrDisabled = errors.New("identity provider is disabled")
+	// ErrCredentials is returned if the credentials are missing or incorrect
+	ErrCredentials = errors.New("credentials are incorrect")
+)
+
+// Provider authenticates the user by the credentials and returns the identity of the user
+type Provider interface {
+	Enabled() bool
+	Authenticate(credentials map[string]string) (string, error)
+}
+
+var providers = map[string]Provider{
+	"ldap": &ldapProvider{},
+	"oidc": &oidcProvider{},
+}
+
+// RegisterProvider adds the identity provider with the name
+func RegisterProvider(name string, provider Provider) {
+	providers[name] = provider
+}
+
+// GetProvider returns the enabled provider by its name
+func GetProvider(name string) (Provider, bool) {
+	provider, ok := providers[name]
+	if !ok || !provider.Enabled() {
+		return nil, false
+	}
+	return provider, true
+}
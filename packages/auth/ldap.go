@@ -0,0 +1,232 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package auth
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	ldapTimeout = 10 * time.Second
+
+	berInteger     = 0x02
+	berOctetString = 0x04
+	berEnumerated  = 0x0a
+	berSequence    = 0x30
+	ldapBindReq    = 0x60
+	ldapBindResp   = 0x61
+	ldapSimpleAuth = 0x80
+	ldapVersion    = 3
+	ldapSuccess    = 0
+	ldapInvalid    = 49
+	// maxBERLength limits the length of the response of LDAP server
+	maxBERLength = 1 << 16
+)
+
+// ldapProvider checks the username and the password with the simple bind to LDAP server
+type ldapProvider struct{}
+
+func (p *ldapProvider) Enabled() bool {
+	return len(conf.Config.Auth.LDAP.URL) > 0
+}
+
+func (p *ldapProvider) Authenticate(credentials map[string]string) (string, error) {
+	cfg := conf.Config.Auth.LDAP
+	if len(cfg.URL) == 0 {
+		return "", ErrDisabled
+	}
+	username, password := credentials["username"], credentials["password"]
+	// the empty password means the unauthenticated bind which always succeeds
+	if len(username) == 0 || len(password) == 0 {
+		return "", ErrCredentials
+	}
+	conn, err := ldapDial(cfg.URL)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.AuthProviderError, "error": err}).Error("connecting to LDAP server")
+		return "", err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(ldapTimeout))
+
+	if _, err = conn.Write(ldapBindRequest(1, fmt.Sprintf(cfg.UserDN, escapeDN(username)), password)); err != nil {
+		log.WithFields(log.Fields{"type": consts.AuthProviderError, "error": err}).Error("sending LDAP bind request")
+		return "", err
+	}
+	code, err := ldapBindResult(bufio.NewReader(conn))
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.AuthProviderError, "error": err}).Error("reading LDAP bind response")
+		return "", err
+	}
+	switch code {
+	case ldapSuccess:
+		return username, nil
+	case ldapInvalid:
+		return "", ErrCredentials
+	}
+	return "", fmt.Errorf("LDAP bind result code %d", code)
+}
+
+func ldapDial(rawurl string) (net.Conn, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	dialer := &net.Dialer{Timeout: ldapTimeout}
+	switch u.Scheme {
+	case "ldap":
+		host := u.Host
+		if len(u.Port()) == 0 {
+			host = net.JoinHostPort(u.Hostname(), "389")
+		}
+		return dialer.Dial("tcp", host)
+	case "ldaps":
+		host := u.Host
+		if len(u.Port()) == 0 {
+			host = net.JoinHostPort(u.Hostname(), "636")
+		}
+		return tls.DialWithDialer(dialer, "tcp", host, &tls.Config{ServerName: u.Hostname()})
+	}
+	return nil, fmt.Errorf("unknown LDAP scheme %s", u.Scheme)
+}
+
+// escapeDN escapes the special characters of the attribute value of DN (RFC 4514)
+func escapeDN(value string) string {
+	var out strings.Builder
+	for i, ch := range value {
+		switch {
+		case strings.ContainsRune(`,+"\<>;=`, ch),
+			ch == '#' && i == 0,
+			ch == ' ' && (i == 0 || i == len(value)-1):
+			out.WriteByte('\\')
+			out.WriteRune(ch)
+		case ch == 0:
+			out.WriteString(`\00`)
+		default:
+			out.WriteRune(ch)
+		}
+	}
+	return out.String()
+}
+
+func berLength(length int) []byte {
+	switch {
+	case length < 0x80:
+		return []byte{byte(length)}
+	case length < 0x100:
+		return []byte{0x81, byte(length)}
+	case length < 0x10000:
+		return []byte{0x82, byte(length >> 8), byte(length)}
+	}
+	return []byte{0x83, byte(length >> 16), byte(length >> 8), byte(length)}
+}
+
+func berTLV(tag byte, content ...[]byte) []byte {
+	var size int
+	for _, item := range content {
+		size += len(item)
+	}
+	out := append([]byte{tag}, berLength(size)...)
+	for _, item := range content {
+		out = append(out, item...)
+	}
+	return out
+}
+
+// berInt encodes the small integer value which is less than 128
+func berInt(tag byte, value int) []byte {
+	return berTLV(tag, []byte{byte(value)})
+}
+
+// ldapBindRequest returns LDAPMessage with the simple BindRequest
+func ldapBindRequest(messageID int, dn, password string) []byte {
+	return berTLV(berSequence, berInt(berInteger, messageID),
+		berTLV(ldapBindReq, berInt(berInteger, ldapVersion), berTLV(berOctetString, []byte(dn)),
+			berTLV(ldapSimpleAuth, []byte(password))))
+}
+
+func berRead(r io.Reader) (byte, []byte, error) {
+	head := make([]byte, 2)
+	if _, err := io.ReadFull(r, head); err != nil {
+		return 0, nil, err
+	}
+	length := int(head[1])
+	if length >= 0x80 {
+		size := length & 0x7f
+		if size == 0 || size > 3 {
+			return 0, nil, fmt.Errorf("unsupported BER length")
+		}
+		buf := make([]byte, size)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return 0, nil, err
+		}
+		length = 0
+		for _, b := range buf {
+			length = length<<8 | int(b)
+		}
+	}
+	if length > maxBERLength {
+		return 0, nil, fmt.Errorf("BER length %d is too big", length)
+	}
+	content := make([]byte, length)
+	if _, err := io.ReadFull(r, content); err != nil {
+		return 0, nil, err
+	}
+	return head[0], content, nil
+}
+
+// ldapBindResult returns the result code of BindResponse
+func ldapBindResult(r io.Reader) (int, error) {
+	tag, message, err := berRead(r)
+	if err != nil {
+		return 0, err
+	}
+	if tag != berSequence {
+		return 0, fmt.Errorf("unexpected LDAP message tag %x", tag)
+	}
+	body := bytes.NewReader(message)
+	if tag, _, err = berRead(body); err != nil || tag != berInteger {
+		return 0, fmt.Errorf("invalid LDAP message id")
+	}
+	tag, response, err := berRead(body)
+	if err != nil || tag != ldapBindResp {
+		return 0, fmt.Errorf("unexpected LDAP response")
+	}
+	tag, code, err := berRead(bytes.NewReader(response))
+	if err != nil || tag != berEnumerated || len(code) != 1 {
+		return 0, fmt.Errorf("invalid LDAP result code")
+	}
+	return int(code[0]), nil
+}
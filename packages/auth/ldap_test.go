@@ -0,0 +1,106 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package auth
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+)
+
+func TestEscapeDN(t *testing.T) {
+	cases := map[string]string{
+		"john":         "john",
+		"a,b=c":        `a\,b\=c`,
+		"#admin ":      `\#admin\ `,
+		`x+y"<z>;\`:    `x\+y\"\<z\>\;\\`,
+		" lead":        `\ lead`,
+		"mid dle":      "mid dle",
+		"a\x00b":       `a\00b`,
+		"Müller,Hans":  `Müller\,Hans`,
+		"o=x,dc=admin": `o\=x\,dc\=admin`,
+	}
+	for in, want := range cases {
+		if got := escapeDN(in); got != want {
+			t.Errorf("escapeDN(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// serveLDAP answers the bind request with success if the password is secret
+func serveLDAP(t *testing.T, listener net.Listener) {
+	conn, err := listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	tag, message, err := berRead(bufio.NewReader(conn))
+	if err != nil || tag != berSequence {
+		t.Errorf("invalid request %x %v", tag, err)
+		return
+	}
+	code := ldapInvalid
+	if bytes.HasSuffix(message, berTLV(ldapSimpleAuth, []byte("secret"))) &&
+		bytes.Contains(message, []byte(`uid=john\,x,dc=test`)) {
+		code = ldapSuccess
+	}
+	conn.Write(berTLV(berSequence, berInt(berInteger, 1),
+		berTLV(ldapBindResp, berInt(berEnumerated, code), berTLV(berOctetString), berTLV(berOctetString))))
+}
+
+func TestLDAPAuthenticate(t *testing.T) {
+	old := conf.Config.Auth
+	defer func() { conf.Config.Auth = old }()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	conf.Config.Auth.LDAP = conf.LDAPConfig{URL: "ldap://" + listener.Addr().String(), UserDN: "uid=%s,dc=test"}
+
+	provider, ok := GetProvider("ldap")
+	if !ok {
+		t.Fatal("ldap provider is not enabled")
+	}
+	if _, err = provider.Authenticate(map[string]string{"username": "john,x"}); err != ErrCredentials {
+		t.Errorf("empty password must be rejected, got %v", err)
+	}
+	go serveLDAP(t, listener)
+	if _, err = provider.Authenticate(map[string]string{"username": "john,x", "password": "wrong"}); err != ErrCredentials {
+		t.Errorf("wrong password must be rejected, got %v", err)
+	}
+	go serveLDAP(t, listener)
+	identity, err := provider.Authenticate(map[string]string{"username": "john,x", "password": "secret"})
+	if err != nil || identity != "john,x" {
+		t.Errorf("unexpected result %s %v", identity, err)
+	}
+
+	conf.Config.Auth.LDAP.URL = ""
+	if _, ok = GetProvider("ldap"); ok {
+		t.Error("ldap provider must be disabled")
+	}
+}
@@ -0,0 +1,178 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+
+	"github.com/dgrijalva/jwt-go"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	jwksTimeout = 10 * time.Second
+	// jwksExpire is the time after which the keys of the issuer are fetched again
+	jwksExpire = time.Hour
+	// jwksRetry is the minimal interval of fetching the keys if the token has an unknown key id
+	jwksRetry = time.Minute
+)
+
+// oidcProvider checks ID token of OpenID Connect issuer
+type oidcProvider struct {
+	mutex   sync.Mutex
+	url     string
+	keys    map[string]*rsa.PublicKey
+	fetched time.Time
+}
+
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+func (p *oidcProvider) Enabled() bool {
+	return len(conf.Config.Auth.OIDC.Issuer) > 0
+}
+
+func (p *oidcProvider) Authenticate(credentials map[string]string) (string, error) {
+	cfg := conf.Config.Auth.OIDC
+	if len(cfg.Issuer) == 0 {
+		return "", ErrDisabled
+	}
+	idToken := credentials["id_token"]
+	if len(idToken) == 0 {
+		return "", ErrCredentials
+	}
+	token, err := jwt.Parse(idToken, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+		}
+		kid, _ := token.Header["kid"].(string)
+		return p.key(cfg.JWKSURL, kid)
+	})
+	if err != nil || !token.Valid {
+		log.WithFields(log.Fields{"type": consts.AuthProviderError, "error": err}).Warning("parsing ID token")
+		return "", ErrCredentials
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	if !claims.VerifyIssuer(cfg.Issuer, true) || !claims.VerifyExpiresAt(time.Now().Unix(), true) ||
+		!hasAudience(claims["aud"], cfg.ClientID) {
+		log.WithFields(log.Fields{"type": consts.AuthProviderError, "iss": claims["iss"], "aud": claims["aud"]}).Warning("ID token claims are not valid")
+		return "", ErrCredentials
+	}
+	claim := cfg.Claim
+	if len(claim) == 0 {
+		claim = "sub"
+	}
+	identity, _ := claims[claim].(string)
+	if len(identity) == 0 {
+		return "", ErrCredentials
+	}
+	return identity, nil
+}
+
+// hasAudience checks the aud claim which is either a string or an array of strings
+func hasAudience(aud interface{}, clientID string) bool {
+	switch v := aud.(type) {
+	case string:
+		return v == clientID
+	case []interface{}:
+		for _, item := range v {
+			if item == clientID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// key returns the public key of the issuer with the key id
+func (p *oidcProvider) key(jwksURL, kid string) (*rsa.PublicKey, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.url != jwksURL {
+		p.url, p.keys, p.fetched = jwksURL, nil, time.Time{}
+	}
+	key, ok := p.keys[kid]
+	if ok && time.Since(p.fetched) < jwksExpire {
+		return key, nil
+	}
+	if p.keys == nil || time.Since(p.fetched) >= jwksRetry {
+		keys, err := fetchJWKS(jwksURL)
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.AuthProviderError, "error": err, "url": jwksURL}).Error("fetching JSON web keys")
+			return nil, err
+		}
+		p.keys, p.fetched = keys, time.Now()
+	}
+	if key, ok = p.keys[kid]; !ok {
+		return nil, fmt.Errorf("unknown key id %s", kid)
+	}
+	return key, nil
+}
+
+func fetchJWKS(jwksURL string) (map[string]*rsa.PublicKey, error) {
+	client := &http.Client{Timeout: jwksTimeout}
+	resp, err := client.Get(jwksURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("JWKS status %s", resp.Status)
+	}
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, err
+	}
+	keys := make(map[string]*rsa.PublicKey)
+	for _, item := range set.Keys {
+		if item.Kty != "RSA" {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(item.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(item.E)
+		if err != nil {
+			return nil, err
+		}
+		keys[item.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+	return keys, nil
+}
@@ -0,0 +1,94 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestOIDCAuthenticate(t *testing.T) {
+	old := conf.Config.Auth
+	defer func() { conf.Config.Auth = old }()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string][]jsonWebKey{"keys": {{
+			Kid: "k1",
+			Kty: "RSA",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	}))
+	defer server.Close()
+	conf.Config.Auth.OIDC = conf.OIDCConfig{Issuer: "https://issuer", ClientID: "genesis",
+		JWKSURL: server.URL, Claim: "email"}
+
+	sign := func(claims jwt.MapClaims, kid string) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = kid
+		out, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+	exp := time.Now().Add(time.Minute).Unix()
+	cases := []struct {
+		token    string
+		identity string
+	}{
+		{sign(jwt.MapClaims{"iss": "https://issuer", "aud": "genesis", "exp": exp, "email": "a@b.c"}, "k1"), "a@b.c"},
+		{sign(jwt.MapClaims{"iss": "https://issuer", "aud": []string{"x", "genesis"}, "exp": exp, "email": "d@e.f"}, "k1"), "d@e.f"},
+		{sign(jwt.MapClaims{"iss": "https://other", "aud": "genesis", "exp": exp, "email": "a@b.c"}, "k1"), ""},
+		{sign(jwt.MapClaims{"iss": "https://issuer", "aud": "other", "exp": exp, "email": "a@b.c"}, "k1"), ""},
+		{sign(jwt.MapClaims{"iss": "https://issuer", "aud": "genesis", "email": "a@b.c"}, "k1"), ""},
+		{sign(jwt.MapClaims{"iss": "https://issuer", "aud": "genesis", "exp": time.Now().Unix() - 10, "email": "a@b.c"}, "k1"), ""},
+		{sign(jwt.MapClaims{"iss": "https://issuer", "aud": "genesis", "exp": exp}, "k1"), ""},
+		{sign(jwt.MapClaims{"iss": "https://issuer", "aud": "genesis", "exp": exp, "email": "a@b.c"}, "k2"), ""},
+	}
+	provider, ok := GetProvider("oidc")
+	if !ok {
+		t.Fatal("oidc provider is not enabled")
+	}
+	for i, item := range cases {
+		identity, err := provider.Authenticate(map[string]string{"id_token": item.token})
+		if identity != item.identity || (len(identity) == 0 && err != ErrCredentials) {
+			t.Errorf("case %d: unexpected result %s %v", i, identity, err)
+		}
+	}
+}
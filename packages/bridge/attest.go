@@ -0,0 +1,101 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package bridge
+
+import (
+	"encoding/hex"
+	"errors"
+
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/crypto"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/utils"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// maxAttestTransfers is the maximum count of transfers in one answer of the validator
+const maxAttestTransfers = 100
+
+// ErrNotValidator is returned if the node is not a bridge validator
+var ErrNotValidator = errors.New(`node is not a bridge validator`)
+
+// Attest signs the confirmed transfers to the network after fromID by the node key
+func Attest(network, fromID int64) ([]SignedTransfer, error) {
+	if !conf.Config.Bridge.Validator {
+		return nil, ErrNotValidator
+	}
+	source := NetworkID()
+	if source <= 0 {
+		return nil, ErrNotConfigured
+	}
+
+	infoBlock := &model.InfoBlock{}
+	if _, err := infoBlock.Get(); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting info block")
+		return nil, err
+	}
+	maxBlockID := infoBlock.BlockID - conf.Config.Bridge.Confirmations
+	if maxBlockID <= 0 {
+		return nil, nil
+	}
+	transfers, err := model.GetBridgeTransfers(network, fromID, maxBlockID, maxAttestTransfers)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting bridge transfers")
+		return nil, err
+	}
+	if len(transfers) == 0 {
+		return nil, nil
+	}
+
+	nodePrivateKey, nodePublicKey, err := utils.GetNodeKeys()
+	if err != nil || len(nodePrivateKey) == 0 {
+		if err == nil {
+			log.WithFields(log.Fields{"type": consts.EmptyObject}).Error("node private key is empty")
+			err = errors.New(`empty node private key`)
+		}
+		return nil, err
+	}
+
+	result := make([]SignedTransfer, 0, len(transfers))
+	for _, t := range transfers {
+		amount, err := NormalizeAmount(t.Amount)
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.ConversionError, "error": err, "id": t.ID}).Error("normalizing bridge amount")
+			return nil, err
+		}
+		sign, err := crypto.Sign(nodePrivateKey, ForSign(source, network, t.ID, t.Recipient, amount))
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("signing bridge transfer")
+			return nil, err
+		}
+		result = append(result, SignedTransfer{
+			ID:          t.ID,
+			Recipient:   t.Recipient,
+			Amount:      amount,
+			Attestation: Attestation{PublicKey: nodePublicKey, Signature: hex.EncodeToString(sign)},
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,192 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package bridge moves tokens between Genesis networks. The tokens are locked or burnt by
+// BridgeTransfer contract on the source network, the validators of the source network attest
+// the transfer and any node of the destination network submits BridgeReceive contract with
+// the attestations, which releases or mints the tokens there.
+package bridge
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/GenesisKernel/go-genesis/packages/config/syspar"
+	"github.com/GenesisKernel/go-genesis/packages/crypto"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// ModeLock means that the network holds the original tokens. Outgoing tokens are locked
+	// on the bridge wallet and incoming tokens are released from it
+	ModeLock = `lock`
+	// ModeMint means that the network holds the representation of the tokens. Outgoing tokens
+	// are burnt and incoming tokens are minted
+	ModeMint = `mint`
+)
+
+var (
+	// ErrNotConfigured is returned if the bridge network id is not specified
+	ErrNotConfigured = errors.New(`bridge is not configured`)
+	// ErrUnknownNetwork is returned for the network which isn't in bridge_networks
+	ErrUnknownNetwork = errors.New(`unknown bridge network`)
+	// ErrAttestations is returned if there is not enough valid attestations
+	ErrAttestations = errors.New(`not enough valid attestations`)
+)
+
+// Network is the route to other Genesis network
+type Network struct {
+	ID         int64    `json:"id"`
+	Ecosystem  int64    `json:"ecosystem"`  // ecosystem of bridged tokens in this network
+	Mode       string   `json:"mode"`       // lock or mint
+	Wallet     int64    `json:"wallet"`     // bridge wallet for the lock mode
+	Validators []string `json:"validators"` // public node keys of validators of other network
+	Threshold  int      `json:"threshold"`  // minimal count of attestations
+}
+
+// Attestation is the signature of the transfer by the validator
+type Attestation struct {
+	PublicKey string `json:"pub"`
+	Signature string `json:"sign"`
+}
+
+// SignedTransfer is the transfer attested by the validator of the source network
+type SignedTransfer struct {
+	ID        int64  `json:"id"`
+	Recipient int64  `json:"recipient"`
+	Amount    string `json:"amount"`
+	Attestation
+}
+
+// ParseNetworks parses the value of bridge_networks system parameter
+func ParseNetworks(value string) ([]Network, error) {
+	var networks []Network
+	if len(value) == 0 {
+		return networks, nil
+	}
+	if err := json.Unmarshal([]byte(value), &networks); err != nil {
+		return nil, err
+	}
+	ids := make(map[int64]bool)
+	for _, n := range networks {
+		if n.ID <= 0 || ids[n.ID] {
+			return nil, fmt.Errorf(`wrong bridge network id %d`, n.ID)
+		}
+		ids[n.ID] = true
+		if n.Ecosystem <= 0 {
+			return nil, fmt.Errorf(`wrong ecosystem of bridge network %d`, n.ID)
+		}
+		if n.Mode != ModeLock && n.Mode != ModeMint {
+			return nil, fmt.Errorf(`wrong mode %s of bridge network %d`, n.Mode, n.ID)
+		}
+		if n.Mode == ModeLock && n.Wallet == 0 {
+			return nil, fmt.Errorf(`bridge wallet of network %d is not specified`, n.ID)
+		}
+		if n.Threshold <= 0 || n.Threshold > len(n.Validators) {
+			return nil, fmt.Errorf(`wrong threshold of bridge network %d`, n.ID)
+		}
+		for _, pub := range n.Validators {
+			if key, err := hex.DecodeString(pub); err != nil || len(key) != 64 {
+				return nil, fmt.Errorf(`wrong validator key %s of bridge network %d`, pub, n.ID)
+			}
+		}
+	}
+	return networks, nil
+}
+
+// NetworkID returns the identifier of this network in the bridge
+func NetworkID() int64 {
+	return syspar.SysInt64(syspar.BridgeNetworkID)
+}
+
+// GetNetwork returns the route to the network from bridge_networks system parameter
+func GetNetwork(id int64) (*Network, error) {
+	if NetworkID() <= 0 {
+		return nil, ErrNotConfigured
+	}
+	networks, err := ParseNetworks(syspar.SysString(syspar.BridgeNetworks))
+	if err != nil {
+		return nil, err
+	}
+	for i := range networks {
+		if networks[i].ID == id {
+			return &networks[i], nil
+		}
+	}
+	return nil, ErrUnknownNetwork
+}
+
+// NormalizeAmount returns the canonical string of the amount, which is used for signing
+func NormalizeAmount(amount string) (string, error) {
+	value, err := decimal.NewFromString(amount)
+	if err != nil {
+		return ``, err
+	}
+	if value.Sign() <= 0 {
+		return ``, fmt.Errorf(`amount must be positive`)
+	}
+	return value.String(), nil
+}
+
+// ForSign returns the string which is signed by validators
+func ForSign(source, destination, transferID, recipient int64, amount string) string {
+	return fmt.Sprintf("bridge,%d,%d,%d,%d,%s", source, destination, transferID, recipient, amount)
+}
+
+// CountValid returns the count of different validators of the network which have signed the data
+func (n *Network) CountValid(forSign string, attestations []Attestation) int {
+	validators := make(map[string]bool)
+	for _, pub := range n.Validators {
+		validators[strings.ToLower(pub)] = true
+	}
+	signed := make(map[string]bool)
+	for _, a := range attestations {
+		key := strings.ToLower(a.PublicKey)
+		if !validators[key] || signed[key] {
+			continue
+		}
+		pub, err := hex.DecodeString(key)
+		if err != nil {
+			continue
+		}
+		sign, err := hex.DecodeString(a.Signature)
+		if err != nil {
+			continue
+		}
+		if ok, err := crypto.CheckSign(pub, forSign, sign); err == nil && ok {
+			signed[key] = true
+		}
+	}
+	return len(signed)
+}
+
+// Verify checks that the transfer has been attested by enough validators of the network
+func (n *Network) Verify(forSign string, attestations []Attestation) error {
+	if n.CountValid(forSign, attestations) < n.Threshold {
+		return ErrAttestations
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package bridge
+
+import (
+	"strings"
+	"testing"
+)
+
+var testKey = strings.Repeat(`ab`, 64)
+
+func TestParseNetworks(t *testing.T) {
+	cases := []struct {
+		value string
+		ok    bool
+	}{
+		{``, true},
+		{`[{"id":2,"ecosystem":1,"mode":"mint","validators":["` + testKey + `"],"threshold":1}]`, true},
+		{`[{"id":2,"ecosystem":1,"mode":"lock","wallet":10,"validators":["` + testKey + `"],"threshold":1}]`, true},
+		{`[{"id":2,"ecosystem":1,"mode":"lock","validators":["` + testKey + `"],"threshold":1}]`, false},
+		{`[{"id":2,"ecosystem":1,"mode":"burn","validators":["` + testKey + `"],"threshold":1}]`, false},
+		{`[{"id":2,"ecosystem":1,"mode":"mint","validators":["` + testKey + `"],"threshold":2}]`, false},
+		{`[{"id":2,"ecosystem":1,"mode":"mint","validators":["abcd"],"threshold":1}]`, false},
+		{`[{"id":0,"ecosystem":1,"mode":"mint","validators":["` + testKey + `"],"threshold":1}]`, false},
+		{`[{"id":2,"ecosystem":1,"mode":"mint","validators":["` + testKey + `"],"threshold":1},` +
+			`{"id":2,"ecosystem":1,"mode":"mint","validators":["` + testKey + `"],"threshold":1}]`, false},
+		{`{}`, false},
+	}
+	for i, c := range cases {
+		_, err := ParseNetworks(c.value)
+		if c.ok != (err == nil) {
+			t.Errorf(`case %d: unexpected result %v`, i, err)
+		}
+	}
+}
+
+func TestNormalizeAmount(t *testing.T) {
+	for amount, want := range map[string]string{`100`: `100`, `0100`: `100`, `1e3`: `1000`} {
+		got, err := NormalizeAmount(amount)
+		if err != nil || got != want {
+			t.Errorf(`%s: want %s got %s %v`, amount, want, got, err)
+		}
+	}
+	for _, amount := range []string{`0`, `-5`, `abc`} {
+		if _, err := NormalizeAmount(amount); err == nil {
+			t.Errorf(`%s: expected error`, amount)
+		}
+	}
+}
+
+func TestForSign(t *testing.T) {
+	if s := ForSign(1, 2, 3, -4, `5`); s != `bridge,1,2,3,-4,5` {
+		t.Errorf(`wrong data for sign %s`, s)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	n := &Network{Validators: []string{testKey}, Threshold: 1}
+	atts := []Attestation{
+		{PublicKey: strings.Repeat(`cd`, 64), Signature: `00`},
+		{PublicKey: testKey, Signature: `zz`},
+		{PublicKey: strings.ToUpper(testKey), Signature: `00`},
+	}
+	if count := n.CountValid(`data`, atts); count != 0 {
+		t.Errorf(`want 0 valid attestations got %d`, count)
+	}
+	if err := n.Verify(`data`, atts); err != ErrAttestations {
+		t.Errorf(`want ErrAttestations got %v`, err)
+	}
+}
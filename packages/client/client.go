@@ -0,0 +1,262 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package client is the Go client of the REST API of the node. It logs in by the private key,
+// calls the contracts, waits for the statuses of transactions and queries the tables of ecosystem.
+// The transactions are signed locally by the offline package and sent by sendtx API
+package client
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/crypto"
+	"github.com/GenesisKernel/go-genesis/packages/offline"
+)
+
+const apiPath = "/api/v2/"
+
+// APIError is the error returned by the node
+type APIError struct {
+	Status  int    `json:"-"`
+	Code    string `json:"error"`
+	Message string `json:"msg"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// Client sends the requests to the node
+type Client struct {
+	URL        string
+	HTTPClient *http.Client
+	// Token is the JWT token of the session, it is set by Login
+	Token       string
+	EcosystemID int64
+	KeyID       int64
+	// Expire is the lifetime of the sent transactions, 0 means the transactions don't expire
+	Expire time.Duration
+	// Attempts is how many times the expired transaction is signed and sent again by Call
+	Attempts int
+	// PollInterval is the interval of polling txstatus API
+	PollInterval time.Duration
+
+	privateKey string
+	mutex      sync.Mutex
+	contracts  map[string]*offline.Contract
+}
+
+// New returns the client of the node with the address like http://127.0.0.1:7079
+func New(nodeURL string) *Client {
+	return &Client{
+		URL:          strings.TrimRight(nodeURL, "/"),
+		HTTPClient:   http.DefaultClient,
+		Attempts:     3,
+		PollInterval: time.Second,
+		contracts:    make(map[string]*offline.Contract),
+	}
+}
+
+func (c *Client) request(method, path string, form url.Values, result interface{}) error {
+	var body io.Reader
+	if method == "GET" && len(form) > 0 {
+		path += "?" + form.Encode()
+	} else if method == "POST" {
+		body = strings.NewReader(form.Encode())
+	}
+	req, err := http.NewRequest(method, c.URL+apiPath+path, body)
+	if err != nil {
+		return err
+	}
+	if method == "POST" {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if len(c.Token) > 0 {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		apiErr := &APIError{Status: resp.StatusCode}
+		if err = json.Unmarshal(data, apiErr); err != nil || len(apiErr.Code) == 0 {
+			apiErr.Message = strings.TrimSpace(string(data))
+		}
+		return apiErr
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(data, result)
+}
+
+func (c *Client) get(path string, params url.Values, result interface{}) error {
+	return c.request("GET", path, params, result)
+}
+
+func (c *Client) post(path string, form url.Values, result interface{}) error {
+	return c.request("POST", path, form, result)
+}
+
+// Login opens the session of the key in the ecosystem, the key signs the transactions of client
+func (c *Client) Login(privateKey string, ecosystemID int64) error {
+	private, err := hex.DecodeString(privateKey)
+	if err != nil {
+		return err
+	}
+	public, err := crypto.PrivateToPublic(private)
+	if err != nil {
+		return err
+	}
+	var uid struct {
+		UID   string `json:"uid"`
+		Token string `json:"token"`
+	}
+	c.Token = ""
+	if err = c.get("getuid", nil, &uid); err != nil {
+		return err
+	}
+	signature, err := crypto.Sign(privateKey, uid.UID)
+	if err != nil {
+		return err
+	}
+	c.Token = uid.Token
+	var result struct {
+		Token       string `json:"token"`
+		EcosystemID string `json:"ecosystem_id"`
+		KeyID       string `json:"key_id"`
+	}
+	form := url.Values{"pubkey": {hex.EncodeToString(public)}, "signature": {hex.EncodeToString(signature)},
+		"ecosystem": {strconv.FormatInt(ecosystemID, 10)}}
+	if err = c.post("login", form, &result); err != nil {
+		c.Token = ""
+		return err
+	}
+	c.Token = result.Token
+	c.EcosystemID, _ = strconv.ParseInt(result.EcosystemID, 10, 64)
+	c.KeyID, _ = strconv.ParseInt(result.KeyID, 10, 64)
+	c.privateKey = privateKey
+	return nil
+}
+
+// Contract returns the description of the contract, the descriptions are cached
+func (c *Client) Contract(name string) (*offline.Contract, error) {
+	c.mutex.Lock()
+	contract, ok := c.contracts[name]
+	c.mutex.Unlock()
+	if ok {
+		return contract, nil
+	}
+	contract = &offline.Contract{}
+	if err := c.get("contract/"+name, nil, contract); err != nil {
+		return nil, err
+	}
+	c.mutex.Lock()
+	c.contracts[name] = contract
+	c.mutex.Unlock()
+	return contract, nil
+}
+
+func (c *Client) newTransaction(name string, params url.Values) (*offline.Transaction, error) {
+	if len(c.privateKey) == 0 {
+		return nil, ErrNotLoggedIn
+	}
+	contract, err := c.Contract(name)
+	if err != nil {
+		return nil, err
+	}
+	t := &offline.Transaction{
+		Contract:    contract,
+		EcosystemID: c.EcosystemID,
+		Time:        time.Now().Unix(),
+		Params:      params,
+	}
+	if c.Expire > 0 {
+		t.ExpireTime = time.Now().Add(c.Expire).Unix()
+	}
+	return t, nil
+}
+
+// Send signs the call of the contract and sends it without waiting, the hash of the transaction is returned
+func (c *Client) Send(name string, params url.Values) ([]byte, error) {
+	t, err := c.newTransaction(name, params)
+	if err != nil {
+		return nil, err
+	}
+	blob, err := t.Sign(c.privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return c.SendTx(blob)
+}
+
+// Call signs the call of the contract, sends it and waits for the block.
+// The expired transaction is sent again at most Attempts times
+func (c *Client) Call(name string, params url.Values) (*TxStatus, error) {
+	t, err := c.newTransaction(name, params)
+	if err != nil {
+		return nil, err
+	}
+	hash, _, err := t.Submit(c, c.privateKey, c.Attempts)
+	if err != nil {
+		return nil, err
+	}
+	return c.TxStatus(hash)
+}
+
+// SendTx sends the signed transaction, it implements offline.Node
+func (c *Client) SendTx(blob []byte) ([]byte, error) {
+	var result struct {
+		Hash string `json:"hash"`
+	}
+	if err := c.post("sendtx", url.Values{"data": {hex.EncodeToString(blob)}}, &result); err != nil {
+		return nil, err
+	}
+	return hex.DecodeString(result.Hash)
+}
+
+// MaxBlockID returns the id of the last block, it implements offline.Node
+func (c *Client) MaxBlockID() (int64, error) {
+	var result struct {
+		MaxBlockID int64 `json:"max_block_id"`
+	}
+	if err := c.get("maxblockid", nil, &result); err != nil {
+		return 0, err
+	}
+	return result.MaxBlockID, nil
+}
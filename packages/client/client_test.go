@@ -0,0 +1,135 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package client
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/crypto"
+	"github.com/GenesisKernel/go-genesis/packages/offline"
+)
+
+// testNode is the fake API of the node, the first transaction expires
+type testNode struct {
+	t      *testing.T
+	sent   int
+	hashes map[string]bool
+}
+
+func (n *testNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, apiPath)
+	if path != "getuid" && r.Header.Get("Authorization") == "" && path != "sendtx" {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error": "E_UNAUTHORIZED", "msg": "Unauthorized"}`))
+		return
+	}
+	var result interface{}
+	switch {
+	case path == "getuid":
+		result = map[string]string{"uid": "123", "token": "temp"}
+	case path == "login":
+		pub, _ := hex.DecodeString(r.FormValue("pubkey"))
+		sign, _ := hex.DecodeString(r.FormValue("signature"))
+		if ok, err := crypto.CheckSign(pub, "123", sign); !ok || err != nil {
+			n.t.Errorf("wrong login signature %v", err)
+		}
+		result = map[string]string{"token": "session", "ecosystem_id": r.FormValue("ecosystem"), "key_id": "-5"}
+	case path == "contract/@1Test":
+		result = &offline.Contract{ID: 5, Name: "@1Test", Fields: []offline.Field{{Name: "Value", Type: "string"}}}
+	case path == "maxblockid":
+		result = map[string]int64{"max_block_id": 10}
+	case path == "sendtx":
+		n.sent++
+		data, _ := hex.DecodeString(r.FormValue("data"))
+		hash, _ := crypto.Hash(data)
+		n.hashes[hex.EncodeToString(hash)] = n.sent > 1
+		result = map[string]string{"hash": hex.EncodeToString(hash)}
+	case strings.HasPrefix(path, "txstatus/"):
+		if n.hashes[strings.TrimPrefix(path, "txstatus/")] {
+			result = &TxStatus{BlockID: "11", Result: "ok"}
+		} else {
+			result = &TxStatus{Message: &TxError{Type: "expired", Error: "transaction has expired"}}
+		}
+	case path == "list/members":
+		if r.FormValue("columns") != "name" || r.FormValue("limit") != "2" {
+			n.t.Errorf("wrong list params %v", r.Form)
+		}
+		result = map[string]interface{}{"count": "3", "list": []map[string]string{{"name": "a"}, {"name": "b"}}}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error": "E_NOTFOUND", "msg": "Page not found"}`))
+		return
+	}
+	json.NewEncoder(w).Encode(result)
+}
+
+func TestClient(t *testing.T) {
+	node := &testNode{t: t, hashes: make(map[string]bool)}
+	server := httptest.NewServer(node)
+	defer server.Close()
+
+	c := New(server.URL)
+	c.PollInterval = time.Millisecond
+	c.Expire = time.Minute
+	if _, err := c.Call("@1Test", url.Values{"Value": {"a"}}); err != ErrNotLoggedIn {
+		t.Errorf("expected %v, got %v", ErrNotLoggedIn, err)
+	}
+	priv, _, err := crypto.GenHexKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = c.Login(priv, 2); err != nil {
+		t.Fatal(err)
+	}
+	if c.Token != "session" || c.EcosystemID != 2 || c.KeyID != -5 {
+		t.Errorf("wrong session %s %d %d", c.Token, c.EcosystemID, c.KeyID)
+	}
+
+	status, err := c.Call("@1Test", url.Values{"Value": {"a"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.BlockID != "11" || node.sent != 2 {
+		t.Errorf("wrong status %+v after %d transactions", status, node.sent)
+	}
+
+	count, list, err := c.List("members", 2, 0, "name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 || len(list) != 2 || list[1]["name"] != "b" {
+		t.Errorf("wrong list %d %v", count, list)
+	}
+
+	_, err = c.Row("members", 1)
+	if apiErr, ok := err.(*APIError); !ok || apiErr.Code != "E_NOTFOUND" || apiErr.Status != http.StatusNotFound {
+		t.Errorf("wrong error %v", err)
+	}
+}
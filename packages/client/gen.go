@@ -0,0 +1,154 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"regexp"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/GenesisKernel/go-genesis/packages/offline"
+	"github.com/GenesisKernel/go-genesis/packages/script"
+)
+
+// goTypes are the types of parameters of bindings by the types of contract fields
+var goTypes = map[string]string{
+	`int64`:          `int64`,
+	`uint64`:         `uint64`,
+	`float64`:        `float64`,
+	`string`:         `string`,
+	script.Decimal:   `string`,
+	`[]uint8`:        `[]byte`,
+	`[]interface {}`: `[]string`,
+}
+
+var ecosystemPrefix = regexp.MustCompile(`^@\d+`)
+
+type bindingField struct {
+	Name     string
+	GoName   string
+	GoType   string
+	Optional bool
+}
+
+type binding struct {
+	Name    string
+	GoName  string
+	Fields  []bindingField
+	Skipped string
+}
+
+func exportedName(name string) string {
+	name = ecosystemPrefix.ReplaceAllString(name, ``)
+	if len(name) == 0 {
+		return name
+	}
+	runes := []rune(name)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+func newBinding(contract *offline.Contract) binding {
+	b := binding{Name: contract.Name, GoName: exportedName(contract.Name)}
+	for _, field := range contract.Fields {
+		if strings.Contains(field.Tags, `signature`) {
+			b.Skipped = `the fields of signatures aren't supported`
+			return b
+		}
+		goType, ok := goTypes[field.Type]
+		if !ok {
+			b.Skipped = fmt.Sprintf(`type %s of %s field isn't supported`, field.Type, field.Name)
+			return b
+		}
+		b.Fields = append(b.Fields, bindingField{Name: field.Name, GoName: exportedName(field.Name),
+			GoType: goType, Optional: strings.Contains(field.Tags, `optional`)})
+	}
+	return b
+}
+
+var bindingsTemplate = template.Must(template.New(`bindings`).Parse(`// Code generated by bindgen from the contracts of the node. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/url"
+
+	"github.com/GenesisKernel/go-genesis/packages/client"
+)
+
+var (
+	_ = hex.EncodeToString
+	_ = fmt.Sprint
+)
+
+// Contracts calls the contracts by the client which has logged in
+type Contracts struct {
+	*client.Client
+}
+{{range .Bindings}}{{if .Skipped}}
+// {{.Name}} is skipped: {{.Skipped}}
+{{else}}
+// {{.GoName}}Params are the parameters of {{.Name}} contract
+type {{.GoName}}Params struct {
+{{range .Fields}}	{{.GoName}} {{.GoType}}{{if .Optional}} // optional{{end}}
+{{end}}}
+
+// Values returns the parameters of {{.Name}} contract as the form of request
+func (p *{{.GoName}}Params) Values() url.Values {
+	params := url.Values{}
+{{range .Fields}}{{if eq .GoType "[]string"}}	params[{{printf "%q" .Name}}] = p.{{.GoName}}
+{{else if eq .GoType "[]byte"}}	params.Set({{printf "%q" .Name}}, hex.EncodeToString(p.{{.GoName}}))
+{{else if eq .GoType "string"}}	params.Set({{printf "%q" .Name}}, p.{{.GoName}})
+{{else}}	params.Set({{printf "%q" .Name}}, fmt.Sprint(p.{{.GoName}}))
+{{end}}{{end}}	return params
+}
+
+// {{.GoName}} calls {{.Name}} contract and waits for the block
+func (c *Contracts) {{.GoName}}(p *{{.GoName}}Params) (*client.TxStatus, error) {
+	return c.Call({{printf "%q" .Name}}, p.Values())
+}
+{{end}}{{end}}`))
+
+// Generate returns the source of Go package with the typed bindings of the contracts.
+// The contracts which can't be signed by the client are skipped with a comment
+func Generate(pkg string, contracts []*offline.Contract) ([]byte, error) {
+	bindings := make([]binding, 0, len(contracts))
+	for _, contract := range contracts {
+		bindings = append(bindings, newBinding(contract))
+	}
+	var buf bytes.Buffer
+	err := bindingsTemplate.Execute(&buf, struct {
+		Package  string
+		Bindings []binding
+	}{pkg, bindings})
+	if err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
@@ -0,0 +1,67 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package client
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/GenesisKernel/go-genesis/packages/offline"
+)
+
+func TestGenerate(t *testing.T) {
+	src, err := Generate("contracts", []*offline.Contract{
+		{Name: "@1TransferTokens", Fields: []offline.Field{
+			{Name: "Recipient", Type: "string"},
+			{Name: "Amount", Type: "decimal.Decimal"},
+			{Name: "Comment", Type: "string", Tags: "optional"},
+		}},
+		{Name: "@1setData", Fields: []offline.Field{
+			{Name: "data", Type: "[]uint8"},
+			{Name: "Count", Type: "int64"},
+			{Name: "List", Type: "[]interface {}"},
+		}},
+		{Name: "@1MultiSign", Fields: []offline.Field{{Name: "Sign", Type: "string", Tags: "signature:Test"}}},
+		{Name: "@1Flag", Fields: []offline.Field{{Name: "On", Type: "bool"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := string(src)
+	for _, want := range []string{
+		"package contracts",
+		"func (c *Contracts) TransferTokens(p *TransferTokensParams) (*client.TxStatus, error)",
+		`return c.Call("@1TransferTokens", p.Values())`,
+		"Comment   string // optional",
+		"func (c *Contracts) SetData(p *SetDataParams)",
+		`params.Set("data", hex.EncodeToString(p.Data))`,
+		`params.Set("Count", fmt.Sprint(p.Count))`,
+		`params["List"] = p.List`,
+		"// @1MultiSign is skipped: the fields of signatures aren't supported",
+		"// @1Flag is skipped: type bool of On field isn't supported",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("%q isn't found in\n%s", want, code)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package client
+
+import (
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/offline"
+)
+
+// ErrNotLoggedIn is returned if the transaction is sent before Login
+var ErrNotLoggedIn = errors.New("client isn't logged in")
+
+// TxError is the error of the transaction which hasn't been included into the block
+type TxError struct {
+	Type  string `json:"type"`
+	Error string `json:"error"`
+}
+
+// TxStatus is the result of txstatus API
+type TxStatus struct {
+	BlockID     string   `json:"blockid"`
+	Result      string   `json:"result"`
+	Message     *TxError `json:"errmsg"`
+	Payer       string   `json:"payer"`
+	ExpireBlock string   `json:"expire_block"`
+	ExpireTime  string   `json:"expire_time"`
+}
+
+// TxFailed is returned by WaitTx if the transaction has been rejected
+type TxFailed struct {
+	*TxError
+}
+
+func (e *TxFailed) Error() string {
+	return e.Type + ": " + e.TxError.Error
+}
+
+// TxStatus returns the status of the transaction
+func (c *Client) TxStatus(hash []byte) (*TxStatus, error) {
+	status := &TxStatus{}
+	if err := c.get("txstatus/"+hex.EncodeToString(hash), nil, status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// WaitTx waits for the block of the transaction, it implements offline.Node
+func (c *Client) WaitTx(hash []byte) (int64, error) {
+	for {
+		status, err := c.TxStatus(hash)
+		if err != nil {
+			return 0, err
+		}
+		if len(status.BlockID) > 0 {
+			return strconv.ParseInt(status.BlockID, 10, 64)
+		}
+		if status.Message != nil {
+			if status.Message.Type == "expired" {
+				return 0, offline.ErrExpired
+			}
+			return 0, &TxFailed{status.Message}
+		}
+		time.Sleep(c.PollInterval)
+	}
+}
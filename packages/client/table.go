@@ -0,0 +1,94 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package client
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+func columnsParams(columns []string) url.Values {
+	params := url.Values{}
+	if len(columns) > 0 {
+		params.Set("columns", strings.Join(columns, ","))
+	}
+	return params
+}
+
+// List returns the count of rows and the rows of the table of ecosystem, all columns are returned if columns are empty
+func (c *Client) List(table string, limit, offset int64, columns ...string) (int64, []map[string]string, error) {
+	params := columnsParams(columns)
+	if limit > 0 {
+		params.Set("limit", strconv.FormatInt(limit, 10))
+	}
+	if offset > 0 {
+		params.Set("offset", strconv.FormatInt(offset, 10))
+	}
+	var result struct {
+		Count string              `json:"count"`
+		List  []map[string]string `json:"list"`
+	}
+	if err := c.get("list/"+table, params, &result); err != nil {
+		return 0, nil, err
+	}
+	count, _ := strconv.ParseInt(result.Count, 10, 64)
+	return count, result.List, nil
+}
+
+// Row returns the row of the table of ecosystem
+func (c *Client) Row(table string, id int64, columns ...string) (map[string]string, error) {
+	var result struct {
+		Value map[string]string `json:"value"`
+	}
+	if err := c.get("row/"+table+"/"+strconv.FormatInt(id, 10), columnsParams(columns), &result); err != nil {
+		return nil, err
+	}
+	return result.Value, nil
+}
+
+// Contracts returns the names of the contracts of ecosystem
+func (c *Client) Contracts() ([]string, error) {
+	const limit = 100
+	names := make([]string, 0)
+	for offset := int64(0); ; offset += limit {
+		var result struct {
+			List []map[string]string `json:"list"`
+		}
+		params := url.Values{"limit": {strconv.Itoa(limit)}, "offset": {strconv.FormatInt(offset, 10)}}
+		if err := c.get("contracts", params, &result); err != nil {
+			return nil, err
+		}
+		// the row of contracts can contain several contracts
+		for _, item := range result.List {
+			for _, name := range strings.Split(item["name"], ",") {
+				if len(name) > 0 {
+					names = append(names, name)
+				}
+			}
+		}
+		if len(result.List) < limit {
+			return names, nil
+		}
+	}
+}
@@ -0,0 +1,154 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package clock checks the drift of the local clock by NTP servers
+package clock
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ntpEpochOffset is the count of seconds between 1900 and 1970
+const ntpEpochOffset = 2208988800
+
+// ErrNoServers is returned if none of NTP servers has responded
+var ErrNoServers = errors.New("no NTP servers have responded")
+
+var (
+	mutex   sync.RWMutex
+	drift   time.Duration
+	checked time.Time
+)
+
+func toNTP(t time.Time) uint64 {
+	secs := uint64(t.Unix() + ntpEpochOffset)
+	frac := uint64(t.Nanosecond()) << 32 / 1e9
+	return secs<<32 | frac
+}
+
+func fromNTP(v uint64) time.Time {
+	secs := int64(v>>32) - ntpEpochOffset
+	nsec := (v & 0xffffffff) * 1e9 >> 32
+	return time.Unix(secs, int64(nsec))
+}
+
+// offset returns the clock offset by the SNTP response, t1 and t4 are local times of the request and the response
+func offset(resp []byte, t1, t4 time.Time) (time.Duration, error) {
+	if len(resp) < 48 {
+		return 0, fmt.Errorf("short NTP response")
+	}
+	if mode := resp[0] & 7; mode != 4 {
+		return 0, fmt.Errorf("wrong NTP mode %d", mode)
+	}
+	if resp[0]>>6 == 3 {
+		return 0, fmt.Errorf("NTP server isn't synchronized")
+	}
+	if resp[1] == 0 {
+		return 0, fmt.Errorf("NTP server has sent kiss-o'-death")
+	}
+	t2 := fromNTP(binary.BigEndian.Uint64(resp[32:]))
+	t3 := fromNTP(binary.BigEndian.Uint64(resp[40:]))
+	return (t2.Sub(t1) + t3.Sub(t4)) / 2, nil
+}
+
+// Query returns the offset of the local clock relative to NTP server, host:port or host
+func Query(server string, timeout time.Duration) (time.Duration, error) {
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "123")
+	}
+	conn, err := net.DialTimeout("udp", server, timeout)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	req := make([]byte, 48)
+	req[0] = 0x23 // version 4, client mode
+	t1 := time.Now()
+	binary.BigEndian.PutUint64(req[40:], toNTP(t1))
+	if _, err = conn.Write(req); err != nil {
+		return 0, err
+	}
+	resp := make([]byte, 48)
+	n, err := conn.Read(resp)
+	if err != nil {
+		return 0, err
+	}
+	t4 := time.Now()
+	if binary.BigEndian.Uint64(resp[24:]) != binary.BigEndian.Uint64(req[40:]) {
+		return 0, fmt.Errorf("NTP response doesn't match the request")
+	}
+	return offset(resp[:n], t1, t4)
+}
+
+// Check queries NTP servers and saves the median offset of the local clock
+func Check(servers []string, timeout time.Duration) (time.Duration, error) {
+	var offsets []time.Duration
+	var lastErr error
+	for _, server := range servers {
+		off, err := Query(server, timeout)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		offsets = append(offsets, off)
+	}
+	if len(offsets) == 0 {
+		if lastErr == nil {
+			lastErr = ErrNoServers
+		}
+		return 0, lastErr
+	}
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+	off := offsets[len(offsets)/2]
+
+	mutex.Lock()
+	drift, checked = off, time.Now()
+	mutex.Unlock()
+	return off, nil
+}
+
+// Drift returns the last measured offset of the local clock, false if it hasn't been measured
+func Drift() (time.Duration, bool) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return drift, !checked.IsZero()
+}
+
+// Exceeded returns true if the measured drift of the local clock is more than max
+func Exceeded(max time.Duration) bool {
+	off, ok := Drift()
+	if !ok || max <= 0 {
+		return false
+	}
+	if off < 0 {
+		off = -off
+	}
+	return off > max
+}
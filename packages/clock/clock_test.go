@@ -0,0 +1,81 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package clock
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+)
+
+// testServer is NTP server which clock is ahead by shift
+func testServer(t *testing.T, shift time.Duration) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		defer conn.Close()
+		buf := make([]byte, 48)
+		for {
+			_, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			resp := make([]byte, 48)
+			resp[0], resp[1] = 0x24, 2 // version 4, server mode, stratum 2
+			copy(resp[24:32], buf[40:48])
+			now := toNTP(time.Now().Add(shift))
+			binary.BigEndian.PutUint64(resp[32:], now)
+			binary.BigEndian.PutUint64(resp[40:], now)
+			conn.WriteTo(resp, addr)
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+func TestCheck(t *testing.T) {
+	now := time.Now()
+	if got := fromNTP(toNTP(now)); got.Sub(now) > time.Microsecond || now.Sub(got) > time.Microsecond {
+		t.Errorf("wrong NTP time conversion %v %v", now, got)
+	}
+
+	servers := []string{testServer(t, 3*time.Second), testServer(t, 2*time.Second), testServer(t, time.Hour)}
+	off, err := Check(servers, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if off < 2900*time.Millisecond || off > 3100*time.Millisecond {
+		t.Errorf("expected median offset 3s, got %v", off)
+	}
+	if !Exceeded(time.Second) || Exceeded(5*time.Second) {
+		t.Error("wrong drift threshold")
+	}
+
+	resp := make([]byte, 48)
+	resp[0] = 0x24
+	if _, err = offset(resp, now, now); err == nil {
+		t.Error("expected kiss-o'-death error")
+	}
+}
@@ -84,7 +84,13 @@ var configFlagMap = map[string]interface{}{
 
 	"logLevel":   &flagStr{confVar: &Config.LogLevel, defVal: "ERROR", flagBase: flagBase{help: "log level - ERROR,WARN,INFO,DEBUG"}},
 	"logFile":    &flagStr{confVar: &Config.LogFileName, flagBase: flagBase{help: "log file name"}},
+	"logFormat":  &flagStr{confVar: &Config.Log.Format, defVal: "text", flagBase: flagBase{help: "log format - text,json"}},
 	"privateDir": &flagStr{confVar: &Config.PrivateDir, flagBase: flagBase{help: "directory for public/private keys"}},
+	"dataDir":    &flagStr{confVar: &Config.DataDir, flagBase: flagBase{help: "directory for database artifacts"}},
+	"vdeDir":     &flagStr{confVar: &Config.VDEDir, flagBase: flagBase{help: "directory for VDE configuration files"}},
+	"logDir":     &flagStr{confVar: &Config.LogDir, flagBase: flagBase{help: "directory for log files"}},
+	"tempDir":    &flagStr{confVar: &Config.TempDir, flagBase: flagBase{help: "directory for temporary files"}},
+	"profile":    &flagStr{confVar: &Config.Resources.Profile, flagBase: flagBase{help: "resource profile - default,lowmem"}},
 
 	"updateServer":        &flagStr{confVar: &Config.Autoupdate.ServerAddress, defVal: defaultUpdateServer, flagBase: flagBase{help: "server address for autoupdates"}},
 	"updatePublicKeyPath": &flagStr{confVar: &Config.Autoupdate.PublicKeyPath, defVal: defaultUpdatePublicKeyPath, flagBase: flagBase{help: "public key path for autoupdates"}},
@@ -146,12 +152,21 @@ var (
 	// RollbackToBlockID is the target block for rollback
 	RollbackToBlockID = flag.Int64("rollbackToBlockId", 0, "Rollback to block_id")
 
+	// CheckDepth is the count of the last blocks which are verified by the self-check at startup
+	CheckDepth = flag.Int64("checkDepth", 1000, "Count of the last blocks whose hash links and rollback records are verified at startup, 0 disables the check of blocks")
+
+	// RepairDB repairs the corruption found by the self-check instead of refusing to start
+	RepairDB = flag.Bool("repairDB", false, "Roll back the corrupted blocks found at startup and download them again instead of refusing to start")
+
 	// TLS is a directory for .well-known and keys. It is required for https
 	TLS = flag.String("tls", "", "Enable https. Ddirectory for .well-known and keys")
 
 	// UpdateInterval is interval in seconds for checking updates
 	UpdateInterval = flag.Int64("updateInterval", defaultUpdateInterval, "Interval in seconds for checking updates, default 3600 seconds (1 hour)")
 
+	// Diagnose is a file name for the diagnostic bundle, the node exits after writing it
+	Diagnose = flag.String("diagnose", "", "write diagnostic bundle (config, logs, profiles) to the file and exit")
+
 	// CheckReadAccess access check for reading, is used only for VDE
 	CheckReadAccess = flag.Bool("checkReadAccess", true, "Check access for reading, only used for VDE")
 )
@@ -199,24 +214,24 @@ func InitConfigFlags() {
 			os.Exit(1)
 		}
 	}
+	initFieldFlags()
 	flag.Parse()
 }
 
-// SetConfigParams set config parameters from command line
-func SetConfigParams() {
-	flag.Visit(func(f *flag.Flag) {
-		paramsPtr, ok := configFlagMap[f.Name]
-		if ok {
-			switch flagParams := paramsPtr.(type) {
-			case *flagStr:
-				fp := *flagParams
-				*fp.confVar = fp.flagVar
-			case *flagInt:
-				fp := *flagParams
-				*fp.confVar = fp.flagVar
-			}
+// setTempDir makes os.TempDir return dir on all platforms
+func setTempDir(dir string) {
+	for _, name := range []string{"TMPDIR", "TMP", "TEMP"} {
+		if err := os.Setenv(name, dir); err != nil {
+			log.WithFields(log.Fields{"type": consts.ConfigError, "error": err, "name": name}).Error("setting temp dir")
 		}
-	})
+	}
+}
+
+// SetConfigParams set config parameters from environment and command line.
+// The precedence of values is default < config file < GENESIS_* environment < command line flag
+func SetConfigParams() {
+	applyEnv(&Config)
+	applyFlags(&Config)
 
 	if *WorkDirectory != "" {
 		Config.WorkDir = *WorkDirectory
@@ -228,8 +243,13 @@ func SetConfigParams() {
 		Config.WorkDir = cwd
 	}
 
-	if Config.PrivateDir == "" {
-		Config.PrivateDir = Config.WorkDir
+	for _, dir := range []*string{&Config.PrivateDir, &Config.DataDir, &Config.VDEDir, &Config.LogDir} {
+		if *dir == "" {
+			*dir = Config.WorkDir
+		}
+	}
+	if Config.TempDir != "" {
+		setTempDir(Config.TempDir)
 	}
 
 	if *FirstBlockPath == "" {
@@ -0,0 +1,140 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package conf
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// ListenConfig is the additional listening addresses of the node and the addresses announced to other nodes
+type ListenConfig struct {
+	TCP           string // space separated additional addresses of tcp server, e.g. "[::]:7078 10.0.0.1:7078"
+	HTTP          string // space separated additional addresses of http server
+	AdvertiseTCP  string // external tcp address of the node, TCPServer by default
+	AdvertiseHTTP string // external http address of the node, HTTP by default
+}
+
+// listenAddrs returns the main address and the additional ones without duplicates
+func listenAddrs(main HostPort, extra string) []string {
+	addrs := []string{main.Str()}
+	for _, addr := range strings.Fields(extra) {
+		dup := false
+		for _, item := range addrs {
+			if item == addr {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
+// TCPListenAddrs returns all addresses of tcp server
+func TCPListenAddrs() []string {
+	return listenAddrs(Config.TCPServer, Config.Listen.TCP)
+}
+
+// HTTPListenAddrs returns all addresses of http server
+func HTTPListenAddrs() []string {
+	return listenAddrs(Config.HTTP, Config.Listen.HTTP)
+}
+
+// AdvertisedTCP returns the tcp address which other nodes use to connect to the node
+func AdvertisedTCP() string {
+	if len(Config.Listen.AdvertiseTCP) > 0 {
+		return Config.Listen.AdvertiseTCP
+	}
+	return Config.TCPServer.Str()
+}
+
+// AdvertisedHTTP returns the http address of the node for clients
+func AdvertisedHTTP() string {
+	if len(Config.Listen.AdvertiseHTTP) > 0 {
+		return Config.Listen.AdvertiseHTTP
+	}
+	return Config.HTTP.Str()
+}
+
+// ListenNetwork returns tcp6 for IPv6 addresses and tcp4 for the others, so IPv4 and IPv6
+// wildcard addresses with the same port can be listened at the same time
+func ListenNetwork(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err == nil {
+		if ip := net.ParseIP(host); ip != nil && ip.To4() == nil {
+			return "tcp6"
+		}
+	}
+	return "tcp4"
+}
+
+// checkAddr checks that addr is host:port, the unspecified host is allowed only for listening
+func checkAddr(addr string, listen bool) error {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	if p, err := strconv.Atoi(port); err != nil || p < 1 || p > 65535 {
+		return fmt.Errorf("port %s is out of range", port)
+	}
+	if !listen {
+		if ip := net.ParseIP(host); len(host) == 0 || (ip != nil && ip.IsUnspecified()) {
+			return fmt.Errorf("host of %s is not specified", addr)
+		}
+	}
+	return nil
+}
+
+func (v *validator) checkListen() {
+	for field, addrs := range map[string]string{"Listen.TCP": Config.Listen.TCP, "Listen.HTTP": Config.Listen.HTTP} {
+		for _, addr := range strings.Fields(addrs) {
+			if err := checkAddr(addr, true); err != nil {
+				v.add(field, fmt.Sprintf("invalid address %s: %s", addr, err), "use the form host:port or [ipv6]:port")
+			}
+		}
+	}
+	for field, addr := range map[string]string{"Listen.AdvertiseTCP": Config.Listen.AdvertiseTCP,
+		"Listen.AdvertiseHTTP": Config.Listen.AdvertiseHTTP} {
+		if len(addr) == 0 {
+			continue
+		}
+		if err := checkAddr(addr, false); err != nil {
+			v.add(field, fmt.Sprintf("invalid address %s: %s", addr, err), "specify the external host:port of the node")
+		}
+	}
+	tcp := make(map[string]bool)
+	for _, addr := range TCPListenAddrs() {
+		tcp[addr] = true
+	}
+	for _, addr := range HTTPListenAddrs() {
+		if tcp[addr] {
+			v.add("Listen.HTTP", fmt.Sprintf("address %s is used by both tcp and http servers", addr),
+				"specify different addresses")
+		}
+	}
+}
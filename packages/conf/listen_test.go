@@ -0,0 +1,61 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package conf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestListenAddrs(t *testing.T) {
+	old := Config
+	defer func() { Config = old }()
+
+	Config.TCPServer = HostPort{Host: "::", Port: 7078}
+	Config.Listen = ListenConfig{TCP: "0.0.0.0:7078  [::]:7078 10.0.0.1:7080"}
+	want := []string{"[::]:7078", "0.0.0.0:7078", "10.0.0.1:7080"}
+	if addrs := TCPListenAddrs(); !reflect.DeepEqual(addrs, want) {
+		t.Errorf("expected %v, got %v", want, addrs)
+	}
+	if AdvertisedTCP() != "[::]:7078" {
+		t.Errorf("wrong advertised address %s", AdvertisedTCP())
+	}
+
+	for addr, network := range map[string]string{"[::]:7078": "tcp6", "[2001:db8::1]:7078": "tcp6",
+		"0.0.0.0:7078": "tcp4", "localhost:7078": "tcp4", "[::ffff:10.0.0.1]:7078": "tcp4"} {
+		if got := ListenNetwork(addr); got != network {
+			t.Errorf("network of %s: expected %s, got %s", addr, network, got)
+		}
+	}
+
+	for addr, ok := range map[string]bool{"[::]:7078": true, "10.0.0.1:0": false, "::1": false} {
+		if err := checkAddr(addr, true); (err == nil) != ok {
+			t.Errorf("listen address %s: %v", addr, err)
+		}
+	}
+	for addr, ok := range map[string]bool{"[2001:db8::1]:7078": true, "[::]:7078": false, ":7078": false} {
+		if err := checkAddr(addr, false); (err == nil) != ok {
+			t.Errorf("advertised address %s: %v", addr, err)
+		}
+	}
+}
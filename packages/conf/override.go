@@ -0,0 +1,230 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package conf
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// EnvPrefix is the prefix of environment variables overriding config fields
+const EnvPrefix = "GENESIS_"
+
+// configField is the leaf field of SavedConfig
+type configField struct {
+	path  string // e.g. "DB.Host"
+	value reflect.Value
+	tag   reflect.StructTag
+}
+
+// fieldFlag keeps the value of command line flag until the config file is loaded
+type fieldFlag struct {
+	field configField
+	value string
+}
+
+func (f *fieldFlag) String() string {
+	return f.value
+}
+
+func (f *fieldFlag) Set(value string) error {
+	if err := setFieldValue(reflect.New(f.field.value.Type()).Elem(), value); err != nil {
+		return err
+	}
+	f.value = value
+	return nil
+}
+
+var fieldFlags = make(map[string]*fieldFlag)
+
+// configFields returns all leaf fields of Config
+func configFields() []configField {
+	return fieldsOf(reflect.ValueOf(&Config).Elem())
+}
+
+// fieldByPath returns the field of the config struct by the path like "DB.Host"
+func fieldByPath(root reflect.Value, path string) reflect.Value {
+	for _, name := range strings.Split(path, ".") {
+		root = root.FieldByName(name)
+	}
+	return root
+}
+
+// fieldPath returns the path of the field of Config by its pointer
+func fieldPath(ptr interface{}) string {
+	addr := reflect.ValueOf(ptr).Pointer()
+	for _, field := range configFields() {
+		if field.value.Addr().Pointer() == addr {
+			return field.path
+		}
+	}
+	return ""
+}
+
+// fieldsOf returns all leaf fields of the config struct
+func fieldsOf(root reflect.Value) []configField {
+	var fields []configField
+	var walk func(prefix string, v reflect.Value)
+	walk = func(prefix string, v reflect.Value) {
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.Type.Kind() == reflect.Struct {
+				if field.Anonymous {
+					walk(prefix, v.Field(i))
+				} else {
+					walk(prefix+field.Name+".", v.Field(i))
+				}
+				continue
+			}
+			fields = append(fields, configField{path: prefix + field.Name, value: v.Field(i), tag: field.Tag})
+		}
+	}
+	walk("", root)
+	return fields
+}
+
+func splitWords(path string) []string {
+	var words []string
+	for _, part := range strings.Split(path, ".") {
+		runes := []rune(part)
+		start := 0
+		for i := 1; i < len(runes); i++ {
+			if unicode.IsUpper(runes[i]) && (unicode.IsLower(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				words = append(words, string(runes[start:i]))
+				start = i
+			}
+		}
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
+
+// EnvName returns the name of environment variable for config field, e.g. TCPServer.Host is GENESIS_TCP_SERVER_HOST
+func EnvName(path string) string {
+	return EnvPrefix + strings.ToUpper(strings.Join(splitWords(path), "_"))
+}
+
+// FlagName returns the name of command line flag for config field, e.g. Log.MaxSize is logMaxSize
+func FlagName(path string) string {
+	words := splitWords(path)
+	for i, word := range words {
+		if i == 0 {
+			words[i] = strings.ToLower(word)
+		} else {
+			words[i] = strings.ToUpper(word[:1]) + strings.ToLower(word[1:])
+		}
+	}
+	return strings.Join(words, "")
+}
+
+func setFieldValue(v reflect.Value, value string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Map:
+		// map values are specified as "key1=value1,key2=value2"
+		m := reflect.MakeMap(v.Type())
+		for _, item := range strings.Split(value, ",") {
+			if len(strings.TrimSpace(item)) == 0 {
+				continue
+			}
+			pair := strings.SplitN(item, "=", 2)
+			if len(pair) != 2 {
+				return fmt.Errorf("wrong map item %s", item)
+			}
+			m.SetMapIndex(reflect.ValueOf(strings.TrimSpace(pair[0])), reflect.ValueOf(strings.TrimSpace(pair[1])))
+		}
+		v.Set(m)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// initFieldFlags defines command line flags for the config fields which have not got them in configFlagMap
+func initFieldFlags() {
+	defined := make(map[uintptr]bool)
+	for _, paramsPtr := range configFlagMap {
+		switch flagParams := paramsPtr.(type) {
+		case *flagStr:
+			defined[reflect.ValueOf(flagParams.confVar).Pointer()] = true
+		case *flagInt:
+			defined[reflect.ValueOf(flagParams.confVar).Pointer()] = true
+		}
+	}
+	existing := make(map[string]bool)
+	flag.VisitAll(func(f *flag.Flag) {
+		existing[strings.ToLower(f.Name)] = true
+	})
+	for _, field := range configFields() {
+		name := FlagName(field.path)
+		if defined[field.value.Addr().Pointer()] || existing[strings.ToLower(name)] {
+			continue
+		}
+		ff := &fieldFlag{field: field}
+		fieldFlags[name] = ff
+		flag.Var(ff, name, fmt.Sprintf("config %s (env %s)", field.path, EnvName(field.path)))
+	}
+}
+
+// applyEnv overrides config values with GENESIS_* environment variables
+func applyEnv(cfg *SavedConfig) {
+	for _, field := range fieldsOf(reflect.ValueOf(cfg).Elem()) {
+		name := EnvName(field.path)
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		if err := setFieldValue(field.value, value); err != nil {
+			log.WithFields(log.Fields{"type": consts.ConfigError, "envName": name, "error": err}).Error("Incorrect value in environment")
+		}
+	}
+}
@@ -0,0 +1,119 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package conf
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestOverrideNames(t *testing.T) {
+	cases := map[string][2]string{
+		"TCPServer.Host":        {"GENESIS_TCP_SERVER_HOST", "tcpServerHost"},
+		"KeyID":                 {"GENESIS_KEY_ID", "keyId"},
+		"Log.MaxSize":           {"GENESIS_LOG_MAX_SIZE", "logMaxSize"},
+		"MaxPageGenerationTime": {"GENESIS_MAX_PAGE_GENERATION_TIME", "maxPageGenerationTime"},
+	}
+	for path, names := range cases {
+		if env := EnvName(path); env != names[0] {
+			t.Errorf("path: %s, expected env: %s, got: %s", path, names[0], env)
+		}
+		if name := FlagName(path); name != names[1] {
+			t.Errorf("path: %s, expected flag: %s, got: %s", path, names[1], name)
+		}
+	}
+}
+
+func TestApplyEnv(t *testing.T) {
+	old := Config
+	defer func() { Config = old }()
+
+	os.Setenv("GENESIS_DB_PORT", "6543")
+	os.Setenv("GENESIS_LOG_LEVELS", "api=DEBUG, parser=WARN")
+	os.Setenv("GENESIS_TEST_MODE", "true")
+	defer func() {
+		os.Unsetenv("GENESIS_DB_PORT")
+		os.Unsetenv("GENESIS_LOG_LEVELS")
+		os.Unsetenv("GENESIS_TEST_MODE")
+	}()
+
+	applyEnv(&Config)
+	if Config.DB.Port != 6543 {
+		t.Errorf("wrong DB.Port %d", Config.DB.Port)
+	}
+	if !Config.TestMode {
+		t.Errorf("wrong TestMode")
+	}
+	if Config.Log.Levels["api"] != "DEBUG" || Config.Log.Levels["parser"] != "WARN" {
+		t.Errorf("wrong Log.Levels %v", Config.Log.Levels)
+	}
+}
+
+func TestSetFieldValue(t *testing.T) {
+	cases := map[reflect.Kind]struct {
+		value string
+		want  interface{}
+	}{
+		reflect.String:  {"host", "host"},
+		reflect.Bool:    {"true", true},
+		reflect.Int:     {"12", 12},
+		reflect.Int64:   {"-7", int64(-7)},
+		reflect.Float64: {"2.5", 2.5},
+		reflect.Map:     {"a=1, b=2", map[string]string{"a": "1", "b": "2"}},
+	}
+	var cfg SavedConfig
+	for _, field := range fieldsOf(reflect.ValueOf(&cfg).Elem()) {
+		c, ok := cases[field.value.Kind()]
+		if !ok {
+			t.Errorf("%s: no case for %s", field.path, field.value.Kind())
+			continue
+		}
+		if err := setFieldValue(field.value, c.value); err != nil {
+			t.Errorf("%s: %s", field.path, err)
+			continue
+		}
+		if got := field.value.Interface(); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: expected %v, got %v", field.path, c.want, got)
+		}
+	}
+	var n int64
+	if err := setFieldValue(reflect.ValueOf(&n).Elem(), "wrong"); err == nil {
+		t.Error("expected error for wrong int")
+	}
+	var f float64
+	if err := setFieldValue(reflect.ValueOf(&f).Elem(), "wrong"); err == nil {
+		t.Error("expected error for wrong float")
+	}
+}
+
+func TestApplyEnvFloat(t *testing.T) {
+	var cfg SavedConfig
+	os.Setenv("GENESIS_SLO_ERROR_RATE", "0.5")
+	defer os.Unsetenv("GENESIS_SLO_ERROR_RATE")
+
+	applyEnv(&cfg)
+	if cfg.SLO.ErrorRate != 0.5 {
+		t.Errorf("wrong SLO.ErrorRate %v", cfg.SLO.ErrorRate)
+	}
+}
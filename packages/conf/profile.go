@@ -0,0 +1,78 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package conf
+
+const (
+	// ProfileDefault is the resource profile of servers
+	ProfileDefault = "default"
+	// ProfileLowMem is the resource profile of small machines like Raspberry Pi with 1 GB of memory
+	ProfileLowMem = "lowmem"
+)
+
+var profiles = map[string]ResourcesConfig{
+	ProfileDefault: {
+		SignCache:      16384,
+		CompileCache:   512,
+		ThumbnailCache: 32,
+		DBMaxIdleConns: 2,
+		PrefetchDepth:  4,
+		GCPercent:      100,
+	},
+	ProfileLowMem: {
+		SignCache:        2048,
+		CompileCache:     64,
+		ThumbnailCache:   4,
+		DBMaxOpenConns:   10,
+		DBMaxIdleConns:   2,
+		PrefetchDepth:    1,
+		MaxParallelHosts: 4,
+		GCPercent:        50,
+	},
+}
+
+// Resources returns the limits of Config.Resources, the omitted values are taken from the profile
+func Resources() ResourcesConfig {
+	res := current().Resources
+	profile, ok := profiles[res.Profile]
+	if !ok {
+		profile = profiles[ProfileDefault]
+	}
+	for _, v := range []struct {
+		value *int
+		def   int
+	}{
+		{&res.SignCache, profile.SignCache},
+		{&res.CompileCache, profile.CompileCache},
+		{&res.ThumbnailCache, profile.ThumbnailCache},
+		{&res.DBMaxOpenConns, profile.DBMaxOpenConns},
+		{&res.DBMaxIdleConns, profile.DBMaxIdleConns},
+		{&res.PrefetchDepth, profile.PrefetchDepth},
+		{&res.MaxParallelHosts, profile.MaxParallelHosts},
+		{&res.GCPercent, profile.GCPercent},
+	} {
+		if *v.value == 0 {
+			*v.value = v.def
+		}
+	}
+	return res
+}
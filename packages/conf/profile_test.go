@@ -0,0 +1,46 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package conf
+
+import "testing"
+
+func TestResources(t *testing.T) {
+	old := Config
+	defer Update(func(cfg *SavedConfig) { *cfg = old })
+
+	Update(func(cfg *SavedConfig) { cfg.Resources = ResourcesConfig{} })
+	if res := Resources(); res != profiles[ProfileDefault] {
+		t.Errorf("wrong default resources %+v", res)
+	}
+
+	Update(func(cfg *SavedConfig) { cfg.Resources = ResourcesConfig{Profile: ProfileLowMem, SignCache: 100} })
+	res := Resources()
+	if res.SignCache != 100 {
+		t.Errorf("sign cache %d isn't taken from config", res.SignCache)
+	}
+	low := profiles[ProfileLowMem]
+	if res.CompileCache != low.CompileCache || res.MaxParallelHosts != low.MaxParallelHosts ||
+		res.DBMaxOpenConns != low.DBMaxOpenConns {
+		t.Errorf("wrong lowmem resources %+v", res)
+	}
+}
@@ -0,0 +1,60 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package conf
+
+import (
+	"net/url"
+	"reflect"
+)
+
+// Redacted replaces the values of secrets in the shown config
+const Redacted = "*****"
+
+// RedactedConfig returns the copy of config which can be shown to users or sent to support.
+// The fields with secret:"true" tag and the fields resolved from secret references are redacted,
+// user info is removed from URLs of other fields
+func RedactedConfig() SavedConfig {
+	secretRefsMutex.Lock()
+	defer secretRefsMutex.Unlock()
+	cfg := copyConfig()
+	for _, field := range fieldsOf(reflect.ValueOf(&cfg).Elem()) {
+		if field.value.Kind() != reflect.String || field.value.Len() == 0 {
+			continue
+		}
+		if _, resolved := secretRefs[field.path]; resolved || field.tag.Get("secret") == "true" {
+			field.value.SetString(Redacted)
+			continue
+		}
+		field.value.SetString(withoutUserInfo(field.value.String()))
+	}
+	return cfg
+}
+
+func withoutUserInfo(value string) string {
+	u, err := url.Parse(value)
+	if err != nil || u.User == nil {
+		return value
+	}
+	u.User = nil
+	return u.String()
+}
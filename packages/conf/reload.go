@@ -0,0 +1,281 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package conf
+
+import (
+	"flag"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+
+	toml "github.com/BurntSushi/toml"
+	log "github.com/sirupsen/logrus"
+)
+
+// reloadableFields is the list of config fields which are applied without restart.
+// They must be read by the accessors below, because they are changed while the node is running.
+// Other fields are read from Config directly
+var reloadableFields = map[string]bool{
+	"LogLevel":                   true,
+	"Log.Levels":                 true,
+	"Centrifugo":                 true,
+	"MaxPageGenerationTime":      true,
+	"Health":                     true,
+	"Messages":                   true,
+	"DB.FastQueries":             true,
+	"SLO":                        true,
+	"Bridge.Hosts":               true,
+	"Resources.MaxParallelHosts": true,
+}
+
+// ReloadResult is the list of changed config fields
+type ReloadResult struct {
+	Applied        []string `json:"applied"`
+	RequireRestart []string `json:"require_restart"`
+}
+
+var (
+	reloadMutex    = &sync.Mutex{}
+	reloadHandlers []func(*ReloadResult)
+	configModTime  time.Time
+	configMutex    = &sync.RWMutex{}
+	// published is the copy of Config which is read by the accessors of reloadable fields
+	published atomic.Value
+)
+
+// Publish makes the values of Config visible to the accessors of reloadable fields.
+// It is called after Config is loaded on start, then it is called by Update
+func Publish() {
+	cfg := copyConfig()
+	published.Store(&cfg)
+}
+
+// copyConfig returns the copy of Config which is consistent while the config is being reloaded
+func copyConfig() SavedConfig {
+	configMutex.RLock()
+	defer configMutex.RUnlock()
+	return Config
+}
+
+// current returns the published copy of Config, it must not be changed
+func current() *SavedConfig {
+	if cfg, ok := published.Load().(*SavedConfig); ok {
+		return cfg
+	}
+	Publish()
+	return published.Load().(*SavedConfig)
+}
+
+// Update changes Config while the node is running and publishes it
+func Update(update func(cfg *SavedConfig)) {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+	update(&Config)
+	cfg := Config
+	published.Store(&cfg)
+}
+
+// LogLevel returns the current log level
+func LogLevel() string {
+	return current().LogLevel
+}
+
+// LogLevels returns the current log levels of subsystems, the map must not be changed
+func LogLevels() map[string]string {
+	return current().Log.Levels
+}
+
+// Centrifugo returns the current centrifugo settings
+func Centrifugo() CentrifugoConfig {
+	return current().Centrifugo
+}
+
+// MaxPageGenerationTime returns the current limit of the page generation in milliseconds
+func MaxPageGenerationTime() int64 {
+	return current().MaxPageGenerationTime
+}
+
+// Health returns the current thresholds of the health check
+func Health() HealthConfig {
+	return current().Health
+}
+
+// Messages returns the current settings of the delivery of messages
+func Messages() MessagesConfig {
+	return current().Messages
+}
+
+// FastQueries reports whether the hot queries bypass gorm
+func FastQueries() bool {
+	return current().DB.FastQueries
+}
+
+// SLO returns the current objectives of API
+func SLO() SLOConfig {
+	return current().SLO
+}
+
+// BridgeHosts returns the current addresses of validators by networks, the map must not be changed
+func BridgeHosts() map[string]string {
+	return current().Bridge.Hosts
+}
+
+// AddReloadHandler adds the function which is called after the reloading of config
+func AddReloadHandler(handler func(*ReloadResult)) {
+	reloadMutex.Lock()
+	reloadHandlers = append(reloadHandlers, handler)
+	reloadMutex.Unlock()
+}
+
+// applyFlags overrides config values with the specified command line flags
+func applyFlags(cfg *SavedConfig) {
+	root := reflect.ValueOf(cfg).Elem()
+	flag.Visit(func(f *flag.Flag) {
+		if ff, ok := fieldFlags[f.Name]; ok {
+			setFieldValue(fieldByPath(root, ff.field.path), ff.value)
+			return
+		}
+		paramsPtr, ok := configFlagMap[f.Name]
+		if ok {
+			switch flagParams := paramsPtr.(type) {
+			case *flagStr:
+				fieldByPath(root, fieldPath(flagParams.confVar)).SetString(flagParams.flagVar)
+			case *flagInt:
+				fieldByPath(root, fieldPath(flagParams.confVar)).SetInt(int64(flagParams.flagVar))
+			}
+		}
+	})
+}
+
+// diffConfig compares config values and returns the names of changed fields,
+// reloadable structures are compared as a whole
+func diffConfig(prefix string, old, cur reflect.Value) (changed []string) {
+	for i := 0; i < old.NumField(); i++ {
+		field := old.Type().Field(i)
+		name := prefix + field.Name
+		if field.Anonymous {
+			name = prefix
+		}
+		if field.Type.Kind() == reflect.Struct && !reloadableFields[name] {
+			nextPrefix := name
+			if !field.Anonymous {
+				nextPrefix += "."
+			}
+			changed = append(changed, diffConfig(nextPrefix, old.Field(i), cur.Field(i))...)
+			continue
+		}
+		if !reflect.DeepEqual(old.Field(i).Interface(), cur.Field(i).Interface()) {
+			changed = append(changed, name)
+		}
+	}
+	return
+}
+
+// restoreField sets the value of the field from the old config
+func restoreField(name string, old, cur reflect.Value) {
+	for {
+		field, ok := old.Type().FieldByName(name)
+		if ok {
+			cur.FieldByIndex(field.Index).Set(old.FieldByIndex(field.Index))
+			return
+		}
+		i := strings.IndexByte(name, '.')
+		if i < 0 {
+			return
+		}
+		old = old.FieldByName(name[:i])
+		cur = cur.FieldByName(name[:i])
+		name = name[i+1:]
+	}
+}
+
+// ReloadConfig reads config file and applies the values of reloadable fields,
+// other changed fields keep their values and are returned as requiring restart
+func ReloadConfig() (*ReloadResult, error) {
+	reloadMutex.Lock()
+	defer reloadMutex.Unlock()
+
+	// the new config is prepared in the copy, only reloadable fields are written to Config
+	old := copyConfig()
+	newConfig := old
+	// maps are decoded to the new ones, because the old ones can be read at the same time
+	newConfig.Log.Levels, newConfig.Bridge.Hosts = nil, nil
+	if _, err := toml.DecodeFile(GetConfigPath(), &newConfig); err != nil {
+		log.WithFields(log.Fields{"type": consts.ConfigError, "error": err}).Error("reloading config")
+		return nil, err
+	}
+	applyEnv(&newConfig)
+	applyFlags(&newConfig)
+	if err := resolveSecrets(&newConfig); err != nil {
+		return nil, err
+	}
+	newConfig.WorkDir, newConfig.PrivateDir, newConfig.KeyID = old.WorkDir, old.PrivateDir, old.KeyID
+	newConfig.DataDir, newConfig.VDEDir, newConfig.LogDir, newConfig.TempDir = old.DataDir, old.VDEDir, old.LogDir, old.TempDir
+
+	result := &ReloadResult{Applied: []string{}, RequireRestart: []string{}}
+	for _, name := range diffConfig("", reflect.ValueOf(old), reflect.ValueOf(newConfig)) {
+		if reloadableFields[name] {
+			result.Applied = append(result.Applied, name)
+		} else {
+			result.RequireRestart = append(result.RequireRestart, name)
+		}
+	}
+	Update(func(cfg *SavedConfig) {
+		for _, name := range result.Applied {
+			restoreField(name, reflect.ValueOf(newConfig), reflect.ValueOf(cfg).Elem())
+		}
+	})
+	log.WithFields(log.Fields{"applied": result.Applied, "require_restart": result.RequireRestart}).Info("config reloaded")
+	for _, handler := range reloadHandlers {
+		handler(result)
+	}
+	return result, nil
+}
+
+func configChanged() bool {
+	info, err := os.Stat(GetConfigPath())
+	if err != nil {
+		return false
+	}
+	if info.ModTime().Equal(configModTime) {
+		return false
+	}
+	changed := !configModTime.IsZero()
+	configModTime = info.ModTime()
+	return changed
+}
+
+// WatchConfig reloads config when the config file is modified
+func WatchConfig(interval time.Duration) {
+	configChanged()
+	for range time.Tick(interval) {
+		if configChanged() {
+			ReloadConfig()
+		}
+	}
+}
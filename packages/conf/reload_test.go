@@ -0,0 +1,119 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package conf
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestDiffConfig(t *testing.T) {
+	old := SavedConfig{LogLevel: "ERROR", DB: DBConfig{Name: "apla", HostPort: HostPort{Host: "127.0.0.1", Port: 5432}}}
+	cur := old
+	cur.LogLevel = "DEBUG"
+	cur.DB.Port = 5433
+	cur.Centrifugo.URL = "http://127.0.0.1:8000"
+	cur.Log.Levels = map[string]string{"api": "DEBUG"}
+
+	changed := diffConfig("", reflect.ValueOf(old), reflect.ValueOf(cur))
+	expected := []string{"LogLevel", "Log.Levels", "DB.Port", "Centrifugo"}
+	if !reflect.DeepEqual(changed, expected) {
+		t.Errorf("expected: %v, got: %v", expected, changed)
+	}
+
+	restoreField("DB.Port", reflect.ValueOf(old), reflect.ValueOf(&cur).Elem())
+	if cur.DB.Port != 5432 {
+		t.Errorf("DB.Port has not been restored: %d", cur.DB.Port)
+	}
+	restoreField("LogLevel", reflect.ValueOf(old), reflect.ValueOf(&cur).Elem())
+	if cur.LogLevel != "ERROR" {
+		t.Errorf("LogLevel has not been restored: %s", cur.LogLevel)
+	}
+}
+
+func TestReloadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "reload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.toml")
+	err = ioutil.WriteFile(path, []byte(`LogLevel = "DEBUG"
+[DB]
+Port = 5433
+[Bridge]
+[Bridge.Hosts]
+2 = "10.0.0.1:7078"
+[Resources]
+MaxParallelHosts = 4
+`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldPath, oldConfig := *ConfigPath, Config
+	defer func() {
+		*ConfigPath, Config = oldPath, oldConfig
+		Publish()
+	}()
+	*ConfigPath = path
+	Config.LogLevel, Config.DB.Port = "ERROR", 5432
+
+	var wg sync.WaitGroup
+	stop := make(chan bool)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				_ = BridgeHosts()["2"]
+			}
+		}
+	}()
+	result, err := ReloadConfig()
+	close(stop)
+	wg.Wait()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	applied := []string{"LogLevel", "Bridge.Hosts", "Resources.MaxParallelHosts"}
+	if !reflect.DeepEqual(result.Applied, applied) {
+		t.Errorf("expected applied %v, got %v", applied, result.Applied)
+	}
+	if !reflect.DeepEqual(result.RequireRestart, []string{"DB.Port"}) {
+		t.Errorf("expected DB.Port requiring restart, got %v", result.RequireRestart)
+	}
+	if LogLevel() != "DEBUG" || BridgeHosts()["2"] != "10.0.0.1:7078" || Resources().MaxParallelHosts != 4 {
+		t.Errorf("reloadable fields have not been applied: %+v", *current())
+	}
+	if port := copyConfig().DB.Port; port != 5432 {
+		t.Errorf("DB.Port has been changed: %d", port)
+	}
+}
@@ -0,0 +1,116 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package conf
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SecretsConfig is parameters of secret providers
+type SecretsConfig struct {
+	VaultAddress string // e.g. https://vault:8200, token is taken from VaultToken or VAULT_TOKEN
+	VaultToken   string `secret:"true"`
+	AWSRegion    string // credentials are taken from AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_SESSION_TOKEN
+}
+
+// SecretProvider returns the secret by its path and the optional key inside the secret
+type SecretProvider interface {
+	GetSecret(path, key string) (string, error)
+}
+
+var (
+	secretProviders = map[string]SecretProvider{
+		"vault": &vaultProvider{},
+		"asm":   &awsProvider{},
+	}
+	// secretRefs keeps the references of resolved fields so they are saved to config file instead of secrets
+	secretRefs      = make(map[string]string)
+	secretRefsMutex = &sync.Mutex{}
+)
+
+// RegisterSecretProvider adds the provider for values in form "scheme:path#key"
+func RegisterSecretProvider(scheme string, provider SecretProvider) {
+	secretProviders[scheme] = provider
+}
+
+// parseSecretRef splits the value "scheme:path#key" if the scheme is a known provider
+func parseSecretRef(value string) (provider SecretProvider, path, key string, ok bool) {
+	off := strings.IndexByte(value, ':')
+	if off <= 0 {
+		return
+	}
+	if provider, ok = secretProviders[value[:off]]; !ok {
+		return
+	}
+	path = value[off+1:]
+	if off = strings.LastIndexByte(path, '#'); off >= 0 {
+		path, key = path[:off], path[off+1:]
+	}
+	return
+}
+
+// ResolveSecrets replaces the references to secret providers in string config fields with the secrets
+func ResolveSecrets() error {
+	return resolveSecrets(&Config)
+}
+
+func resolveSecrets(cfg *SavedConfig) error {
+	secretRefsMutex.Lock()
+	defer secretRefsMutex.Unlock()
+	for _, field := range fieldsOf(reflect.ValueOf(cfg).Elem()) {
+		if field.value.Kind() != reflect.String {
+			continue
+		}
+		ref := field.value.String()
+		provider, path, key, ok := parseSecretRef(ref)
+		if !ok {
+			continue
+		}
+		secret, err := provider.GetSecret(path, key)
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.ConfigError, "field": field.path, "error": err}).Error("getting secret")
+			return fmt.Errorf("%s: %s", field.path, err)
+		}
+		field.value.SetString(secret)
+		secretRefs[field.path] = ref
+	}
+	return nil
+}
+
+// withSecretRefs returns the copy of config with the references instead of resolved secrets
+func withSecretRefs() SavedConfig {
+	secretRefsMutex.Lock()
+	defer secretRefsMutex.Unlock()
+	cfg := copyConfig()
+	for path, ref := range secretRefs {
+		fieldByPath(reflect.ValueOf(&cfg).Elem(), path).SetString(ref)
+	}
+	return cfg
+}
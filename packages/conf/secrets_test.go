@@ -0,0 +1,53 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package conf
+
+import (
+	"testing"
+)
+
+type testSecrets map[string]string
+
+func (s testSecrets) GetSecret(path, key string) (string, error) {
+	return s[path+"#"+key], nil
+}
+
+func TestResolveSecrets(t *testing.T) {
+	old := Config
+	defer func() { Config = old }()
+
+	RegisterSecretProvider("test", testSecrets{"db#password": "secret"})
+	defer delete(secretProviders, "test")
+
+	Config.DB.Password = "test:db#password"
+	Config.Centrifugo.Secret = "plain"
+	if err := ResolveSecrets(); err != nil {
+		t.Fatal(err)
+	}
+	if Config.DB.Password != "secret" || Config.Centrifugo.Secret != "plain" {
+		t.Errorf("wrong resolved values %s %s", Config.DB.Password, Config.Centrifugo.Secret)
+	}
+	if saved := withSecretRefs(); saved.DB.Password != "test:db#password" {
+		t.Errorf("reference is not restored: %s", saved.DB.Password)
+	}
+}
@@ -0,0 +1,224 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package conf
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+)
+
+const dialTimeout = 3 * time.Second
+
+// ConfigProblem is an issue found by the validation of config
+type ConfigProblem struct {
+	Field   string
+	Problem string
+	Hint    string
+}
+
+func (p ConfigProblem) String() string {
+	return fmt.Sprintf("%s: %s (%s)", p.Field, p.Problem, p.Hint)
+}
+
+type validator struct {
+	problems []ConfigProblem
+}
+
+func (v *validator) add(field, problem, hint string) {
+	v.problems = append(v.problems, ConfigProblem{Field: field, Problem: problem, Hint: hint})
+}
+
+func (v *validator) checkPort(field string, hp HostPort) {
+	if hp.Port < 1 || hp.Port > 65535 {
+		v.add(field+".Port", fmt.Sprintf("port %d is out of range", hp.Port), "use a port in range 1..65535")
+	}
+}
+
+func (v *validator) checkDir(field, dir string) {
+	fi, err := os.Stat(dir)
+	switch {
+	case os.IsNotExist(err):
+		v.add(field, fmt.Sprintf("directory %s does not exist", dir), "create the directory or fix the path")
+	case err != nil:
+		v.add(field, err.Error(), "check the permissions of the directory")
+	case !fi.IsDir():
+		v.add(field, fmt.Sprintf("%s is not a directory", dir), "specify the path to a directory")
+	}
+}
+
+func (v *validator) checkKeyFile(name string) {
+	path := filepath.Join(Config.PrivateDir, name)
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		v.add("PrivateDir", fmt.Sprintf("can't read key file %s: %s", path, err),
+			"generate keys with -generateFirstBlock or copy them to PrivateDir")
+		return
+	}
+	if key, err := hex.DecodeString(strings.TrimSpace(string(data))); err != nil || len(key) != consts.PrivkeyLength {
+		v.add("PrivateDir", fmt.Sprintf("key file %s doesn't contain a valid hex private key", path),
+			"the file must contain 64 hex characters")
+	}
+}
+
+func (v *validator) checkLevel(field, level string) {
+	switch strings.ToUpper(level) {
+	case "", "DEBUG", "INFO", "WARN", "WARNING", "ERROR", "FATAL", "PANIC":
+	default:
+		v.add(field, fmt.Sprintf("unknown log level %s", level), "use one of DEBUG, INFO, WARN, ERROR")
+	}
+}
+
+func (v *validator) checkDial(field, addr, hint string) {
+	conn, err := net.DialTimeout("tcp", addr, dialTimeout)
+	if err != nil {
+		v.add(field, fmt.Sprintf("%s is unreachable: %s", addr, err), hint)
+		return
+	}
+	conn.Close()
+}
+
+// ValidateConfig checks the parameters of Config and returns all found problems.
+// The availability of database and centrifugo is checked only if checkNetwork is true
+func ValidateConfig(checkNetwork bool) []ConfigProblem {
+	v := &validator{}
+
+	v.checkPort("TCPServer", Config.TCPServer)
+	v.checkPort("HTTP", Config.HTTP)
+	v.checkPort("DB", Config.DB.HostPort)
+	if Config.TCPServer.Port == Config.HTTP.Port {
+		v.add("HTTP.Port", fmt.Sprintf("port %d is used by both TCPServer and HTTP", Config.HTTP.Port),
+			"specify different ports with -tcpPort and -httpPort")
+	}
+	v.checkListen()
+	if len(Config.Proxy.URL) > 0 {
+		if u, err := url.Parse(Config.Proxy.URL); err != nil || len(u.Host) == 0 ||
+			(u.Scheme != "socks5" && u.Scheme != "socks5h" && u.Scheme != "http") {
+			v.add("Proxy.URL", fmt.Sprintf("invalid proxy url %s", Config.Proxy.URL),
+				"use the form socks5://host:port or http://host:port")
+		}
+	}
+	if len(*TLS) > 0 && (Config.TCPServer.Port == 443 || Config.HTTP.Port == 443) {
+		v.add("HTTP.Port", "port 443 is used by https server", "change the port or disable -tls")
+	}
+
+	v.checkDir("WorkDir", Config.WorkDir)
+	v.checkDir("PrivateDir", Config.PrivateDir)
+	// the directories are empty if SetConfigParams has not been called
+	for field, dir := range map[string]string{"DataDir": Config.DataDir, "VDEDir": Config.VDEDir,
+		"LogDir": Config.LogDir, "TempDir": Config.TempDir} {
+		if len(dir) > 0 {
+			v.checkDir(field, dir)
+		}
+	}
+	if len(Config.LogFileName) > 0 {
+		v.checkDir("LogFileName", filepath.Dir(GetLogPath()))
+	}
+	v.checkKeyFile(consts.PrivateKeyFilename)
+	v.checkKeyFile(consts.NodePrivateKeyFilename)
+
+	v.checkLevel("LogLevel", Config.LogLevel)
+	for subsystem, level := range Config.Log.Levels {
+		v.checkLevel("Log.Levels."+subsystem, level)
+	}
+	switch Config.Log.Format {
+	case "", "text", "json":
+	default:
+		v.add("Log.Format", fmt.Sprintf("unknown log format %s", Config.Log.Format), "use text or json")
+	}
+	if _, ok := profiles[Config.Resources.Profile]; !ok && len(Config.Resources.Profile) > 0 {
+		v.add("Resources.Profile", fmt.Sprintf("unknown profile %s", Config.Resources.Profile), "use default or lowmem")
+	}
+
+	if len(Config.DataMasterKey) > 0 {
+		if key, err := hex.DecodeString(Config.DataMasterKey); err != nil || len(key) != 32 {
+			v.add("DataMasterKey", "data master key isn't a valid hex AES-256 key", "the key must contain 64 hex characters")
+		}
+	}
+
+	if ldap := Config.Auth.LDAP; len(ldap.URL) > 0 {
+		if u, err := url.Parse(ldap.URL); err != nil || (u.Scheme != "ldap" && u.Scheme != "ldaps") || len(u.Host) == 0 {
+			v.add("Auth.LDAP.URL", fmt.Sprintf("invalid url %s", ldap.URL), "use the form ldap://host:389 or ldaps://host:636")
+		}
+		if strings.Count(ldap.UserDN, "%s") != 1 {
+			v.add("Auth.LDAP.UserDN", "DN template must contain one %s", "use the form uid=%s,ou=people,dc=example,dc=com")
+		}
+	}
+	if oidc := Config.Auth.OIDC; len(oidc.Issuer) > 0 {
+		if len(oidc.ClientID) == 0 {
+			v.add("Auth.OIDC.ClientID", "client id is empty", "specify the client id registered at the issuer")
+		}
+		if u, err := url.Parse(oidc.JWKSURL); err != nil || len(u.Host) == 0 {
+			v.add("Auth.OIDC.JWKSURL", fmt.Sprintf("invalid url %s", oidc.JWKSURL), "use the form https://host/path")
+		}
+	}
+
+	if smtp := Config.Messages.SMTP; len(smtp.From) > 0 && (len(smtp.Host) == 0 || smtp.Port < 1 || smtp.Port > 65535) {
+		v.add("Messages.SMTP", "mail server address is invalid", "specify Messages.SMTP.Host and Messages.SMTP.Port")
+	}
+	if twilio := Config.Messages.Twilio; len(twilio.From) > 0 && (len(twilio.AccountSID) == 0 || len(twilio.AuthToken) == 0) {
+		v.add("Messages.Twilio", "Twilio credentials are empty", "specify Messages.Twilio.AccountSID and Messages.Twilio.AuthToken")
+	}
+	if len(Config.Clock.NTPServers) > 0 && (Config.Clock.MaxDrift < 1 || Config.Clock.CheckInterval < 1) {
+		v.add("Clock", "max drift and check interval must be positive", "set Clock.MaxDrift to 1000 and Clock.CheckInterval to 600")
+	}
+	if Config.Messages.MaxAttempts < 1 {
+		v.add("Messages.MaxAttempts", "count of attempts must be positive", "set Messages.MaxAttempts to 5")
+	}
+
+	if len(Config.DB.Name) == 0 || len(Config.DB.User) == 0 {
+		v.add("DB", "database name or user is empty", "specify them with -dbName and -dbUser")
+	}
+	var centrifugoHost string
+	if len(Config.Centrifugo.URL) > 0 {
+		if u, err := url.Parse(Config.Centrifugo.URL); err != nil || len(u.Host) == 0 {
+			v.add("Centrifugo.URL", fmt.Sprintf("invalid url %s", Config.Centrifugo.URL),
+				"use the form http://host:port")
+		} else {
+			centrifugoHost = u.Host
+			if len(u.Port()) == 0 {
+				port := "80"
+				if u.Scheme == "https" {
+					port = "443"
+				}
+				centrifugoHost = net.JoinHostPort(u.Hostname(), port)
+			}
+		}
+	}
+
+	if checkNetwork {
+		v.checkDial("DB", Config.DB.Str(), "check that PostgreSQL is running and DB.Host, DB.Port are right")
+		if len(centrifugoHost) > 0 {
+			v.checkDial("Centrifugo.URL", centrifugoHost, "check that centrifugo is running or clear Centrifugo.URL")
+		}
+	}
+	return v.problems
+}
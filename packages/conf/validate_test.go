@@ -0,0 +1,101 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package conf
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+)
+
+func TestValidateConfig(t *testing.T) {
+	old := Config
+	defer func() { Config = old }()
+
+	dir, err := ioutil.TempDir("", "validate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	key := strings.Repeat("ab", consts.PrivkeyLength)
+	for _, name := range []string{consts.PrivateKeyFilename, consts.NodePrivateKeyFilename} {
+		if err = ioutil.WriteFile(filepath.Join(dir, name), []byte(key), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	Config.WorkDir, Config.PrivateDir = dir, dir
+	Config.TCPServer = HostPort{Host: "127.0.0.1", Port: 7078}
+	Config.HTTP = HostPort{Host: "127.0.0.1", Port: 7079}
+	Config.DB = DBConfig{Name: "genesis", User: "genesis", HostPort: HostPort{Host: "127.0.0.1", Port: 5432}}
+	Config.LogLevel = "INFO"
+	Config.Log = LogConfig{}
+	Config.Centrifugo = CentrifugoConfig{}
+	Config.Messages = MessagesConfig{MaxAttempts: 5}
+	if problems := ValidateConfig(false); len(problems) != 0 {
+		t.Fatalf("unexpected problems %v", problems)
+	}
+
+	Config.HTTP.Port = 7078
+	Config.PrivateDir = filepath.Join(dir, "missing")
+	Config.DataDir = filepath.Join(dir, "data")
+	Config.LogLevel = "VERBOSE"
+	Config.Auth.LDAP = LDAPConfig{URL: "http://ldap.local", UserDN: "uid=user"}
+	Config.Messages.SMTP = SMTPConfig{From: "node@example.com"}
+	fields := make(map[string]bool)
+	for _, problem := range ValidateConfig(false) {
+		fields[problem.Field] = true
+	}
+	for _, field := range []string{"HTTP.Port", "PrivateDir", "DataDir", "LogLevel", "Auth.LDAP.URL", "Auth.LDAP.UserDN",
+		"Messages.SMTP"} {
+		if !fields[field] {
+			t.Errorf("problem of %s is not found", field)
+		}
+	}
+}
+
+func TestDataPaths(t *testing.T) {
+	old := Config
+	defer func() { Config = old }()
+
+	Config.WorkDir, Config.DataDir, Config.LogDir = "/work", "/data", ""
+	Config.LogFileName = "genesis.log"
+	if path := DataPath("archive", "ecosystem_2.jsonl.gz"); path != filepath.Join("/data", "archive", "ecosystem_2.jsonl.gz") {
+		t.Errorf("wrong data path %s", path)
+	}
+	if path := GetLogPath(); path != filepath.Join("/work", "genesis.log") {
+		t.Errorf("wrong log path %s", path)
+	}
+	Config.LogDir = "/logs"
+	if path := GetLogPath(); path != filepath.Join("/logs", "genesis.log") {
+		t.Errorf("wrong log path %s", path)
+	}
+	Config.LogFileName = "/var/log/genesis.log"
+	if path := GetLogPath(); path != Config.LogFileName {
+		t.Errorf("wrong log path %s", path)
+	}
+}
@@ -0,0 +1,106 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package syspar
+
+import "strings"
+
+// Types of the values of system parameters
+const (
+	TypeInt     = `int`
+	TypeDecimal = `decimal`
+	TypeString  = `string`
+	TypeJSON    = `json`
+	TypeHex     = `hex`
+)
+
+// ParamInfo describes the value of system parameter, Default is the value of the new network
+type ParamInfo struct {
+	Type    string
+	Default string
+}
+
+var registry = map[string]ParamInfo{
+	`default_ecosystem_page`:     {TypeString, ``},
+	`default_ecosystem_menu`:     {TypeString, ``},
+	`default_ecosystem_contract`: {TypeString, ``},
+	GapsBetweenBlocks:            {TypeInt, `2`},
+	RbBlocks1:                    {TypeInt, `60`},
+	`new_version_url`:            {TypeString, `upd.apla.io`},
+	FullNodes:                    {TypeJSON, ``},
+	NumberNodes:                  {TypeInt, `101`},
+	`ecosystem_price`:            {TypeInt, `1000`},
+	`contract_price`:             {TypeInt, `200`},
+	`column_price`:               {TypeInt, `200`},
+	`table_price`:                {TypeInt, `200`},
+	`menu_price`:                 {TypeInt, `100`},
+	`page_price`:                 {TypeInt, `100`},
+	BlockchainURL:                {TypeString, ``},
+	MaxBlockSize:                 {TypeInt, `67108864`},
+	MaxTxSize:                    {TypeInt, `33554432`},
+	MaxTxCount:                   {TypeInt, `1000`},
+	MaxColumns:                   {TypeInt, `50`},
+	MaxIndexes:                   {TypeInt, `5`},
+	MaxBlockUserTx:               {TypeInt, `100`},
+	`max_fuel_tx`:                {TypeInt, `1000`},
+	`max_fuel_block`:             {TypeInt, `100000`},
+	SizeFuel:                     {TypeInt, ``},
+	CommissionSize:               {TypeInt, `3`},
+	CommissionWallet:             {TypeJSON, ``},
+	FuelRate:                     {TypeJSON, `[["1","1000000000000000"]]`},
+	OracleKeys:                   {TypeJSON, ``},
+	BridgeNetworkID:              {TypeInt, `0`},
+	BridgeNetworks:               {TypeJSON, ``},
+	MaxEcosystemsPerKey:          {TypeInt, `0`},
+	EcosystemFee:                 {TypeDecimal, `0`},
+	EcosystemTreasury:            {TypeString, ``},
+	EcosystemBillingContract:     {TypeString, ``},
+	NameRegistrationPeriod:       {TypeInt, `31536000`},
+	TxNonceWindow:                {TypeInt, `64`},
+	TxNonceRequired:              {TypeInt, `0`},
+	TxNonceBlock:                 {TypeInt, `0`},
+	FeeRewardBlock:               {TypeInt, `0`},
+	MaxTxSizeByType:              {TypeJSON, `{}`},
+	SystemTxContracts:            {TypeJSON, `["@1UpdateSysParam"]`},
+	SystemTxReserve:              {TypeInt, `10`},
+	NetworkCA:                    {TypeHex, ``},
+	MaxCallDepth:                 {TypeInt, `32`},
+	MaxAggregateRows:             {TypeInt, `10000`},
+	FeeRouting:                   {TypeJSON, ``},
+	StakingValidators:            {TypeInt, `0`},
+	StakingMinStake:              {TypeDecimal, `0`},
+	StakingUnbondingBlocks:       {TypeInt, `1000`},
+	RecoveryMinDelay:             {TypeInt, `86400`},
+	StateHashBlock:               {TypeInt, `0`},
+}
+
+// GetParamInfo returns the type and the default value of the system parameter. The costs of
+// functions extend_cost_* are integers without default, other unknown parameters are strings
+func GetParamInfo(name string) (ParamInfo, bool) {
+	if info, ok := registry[name]; ok {
+		return info, true
+	}
+	if strings.HasPrefix(name, `extend_cost_`) {
+		return ParamInfo{Type: TypeInt}, true
+	}
+	return ParamInfo{Type: TypeString}, false
+}
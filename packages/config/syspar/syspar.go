@@ -26,6 +26,8 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/GenesisKernel/go-genesis/packages/conf"
@@ -63,14 +65,77 @@ const (
 	SizeFuel = `size_fuel`
 	// CommissionWallet is the address for commissions
 	CommissionWallet = `commission_wallet`
+	// CommissionSize is the percent of fee paid to commission_wallet if fee_routing isn't defined
+	CommissionSize = `commission_size`
+	// FeeRouting is JSON object with the percents of fee paid to the block producer, split among the validators,
+	// paid to the ecosystem treasury and burned, e.g. {"producer": 80, "validators": 10, "treasury": 10, "burn": 0}
+	FeeRouting = `fee_routing`
 	// RbBlocks1 rollback from queue_bocks
 	RbBlocks1 = `rb_blocks_1`
+	// OracleKeys is the list of keys which can push oracle data
+	OracleKeys = `oracle_keys`
+	// BridgeNetworkID is the identifier of this network in the bridge
+	BridgeNetworkID = `bridge_network_id`
+	// BridgeNetworks is the list of routes to other networks
+	BridgeNetworks = `bridge_networks`
+	// MaxEcosystemsPerKey is the maximum number of ecosystems which can be created by one key
+	MaxEcosystemsPerKey = `max_ecosystems_per_key`
+	// EcosystemFee is the amount of tokens which is charged for the creation of ecosystem
+	EcosystemFee = `ecosystem_fee`
+	// EcosystemTreasury is the wallet which receives the ecosystem fee
+	EcosystemTreasury = `ecosystem_treasury`
+	// EcosystemBillingContract is the contract which is called after the creation of ecosystem
+	EcosystemBillingContract = `ecosystem_billing_contract`
+	// NameRegistrationPeriod is the period in seconds for which the account name is registered
+	NameRegistrationPeriod = `name_registration_period`
+	// TxNonceWindow is the number of nonces below the greatest used nonce of key which are still accepted
+	TxNonceWindow = `tx_nonce_window`
+	// TxNonceRequired rejects the contract transactions without nonce if it isn't 0
+	TxNonceRequired = `tx_nonce_required`
+	// FeeRewardBlock is the first block which accrues the fees of the producer and the validators as rewards,
+	// 0 means that they are paid to their wallets
+	FeeRewardBlock = `fee_reward_block`
+	// TxNonceBlock is the first block which accepts the transactions with nonce, 0 means not activated
+	TxNonceBlock = `tx_nonce_block`
+	// MaxTxSizeByType is JSON object with the maximum sizes of transactions by the names of contracts
+	// and embedded transactions, e.g. {"@1UploadBinary": 1048576, "OracleData": 1024}
+	MaxTxSizeByType = `max_tx_size_by_type`
+	// SystemTxContracts is JSON array with the names of contracts which are built into blocks in the system lane
+	SystemTxContracts = `system_tx_contracts`
+	// SystemTxReserve is the percent of the size and the count of transactions of block reserved for the system lane
+	SystemTxReserve = `system_tx_reserve`
+	// MaxCallDepth is the maximum depth of nested calls of contracts, 0 means no limit
+	MaxCallDepth = `max_call_depth`
+	// MaxAggregateRows is the maximum count of rows which can be scanned by the aggregate functions
+	MaxAggregateRows = `max_aggregate_rows`
+	// StakingValidators is the number of candidates with the greatest stakes which are elected to full_nodes,
+	// 0 means that full_nodes is edited by hand
+	StakingValidators = `staking_validators`
+	// StakingMinStake is the minimal stake of the candidate which can be elected to full_nodes
+	StakingMinStake = `staking_min_stake`
+	// StakingUnbondingBlocks is the number of blocks after which the unbonded stake can be withdrawn
+	StakingUnbondingBlocks = `staking_unbonding_blocks`
+	// RecoveryMinDelay is the minimal challenge period of the account recovery in seconds
+	RecoveryMinDelay = `recovery_min_delay`
+	// StateHashBlock is the first block which has the state hash of the previous block in the header, 0 means not activated
+	StateHashBlock = `state_hash_block`
+	// NetworkCA is the hex public key of CA which certifies the nodes of permissioned network, empty means open network
+	NetworkCA = `network_ca`
 )
 
+// FeeShares are the percents of the transaction fee by recipients, they sum up to 100
+type FeeShares struct {
+	Producer   int64 `json:"producer"`
+	Validators int64 `json:"validators"`
+	Treasury   int64 `json:"treasury"`
+	Burn       int64 `json:"burn"`
+}
+
 // FullNode is storing full node data
 type FullNode struct {
 	Host   string
 	Public []byte
+	Cert   string // certificate of node in permissioned network
 }
 
 var (
@@ -81,6 +146,8 @@ var (
 	nodesByPosition = make([][]string, 0)
 	fuels           = make(map[int64]string)
 	wallets         = make(map[int64]string)
+	txSizes         = make(map[string]int64)
+	systemContracts = make(map[string]bool)
 	mutex           = &sync.RWMutex{}
 )
 
@@ -117,7 +184,11 @@ func SysUpdate(dbTransaction *model.DbTransaction) error {
 				log.WithFields(log.Fields{"type": consts.ConversionError, "error": err, "value": item[2]}).Error("decoding inode from string")
 				return err
 			}
-			nodes[converter.StrToInt64(item[1])] = &FullNode{Host: item[0], Public: pub}
+			node := &FullNode{Host: item[0], Public: pub}
+			if len(item) > 3 {
+				node.Cert = item[3]
+			}
+			nodes[converter.StrToInt64(item[1])] = node
 		}
 	}
 	getParams := func(name string) (map[int64]string, error) {
@@ -141,9 +212,130 @@ func SysUpdate(dbTransaction *model.DbTransaction) error {
 	fuels, err = getParams(FuelRate)
 	wallets, err = getParams(CommissionWallet)
 
+	txSizes = make(map[string]int64)
+	if len(cache[MaxTxSizeByType]) > 0 {
+		sizes, errSizes := ParseTxSizes(cache[MaxTxSizeByType])
+		if errSizes != nil {
+			log.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": errSizes}).Error("unmarshalling max tx sizes from json")
+		} else {
+			txSizes = sizes
+		}
+	}
+
+	systemContracts = make(map[string]bool)
+	if len(cache[SystemTxContracts]) > 0 {
+		list, errList := ParseSystemTxContracts(cache[SystemTxContracts])
+		if errList != nil {
+			log.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": errList}).Error("unmarshalling system tx contracts from json")
+		}
+		for _, name := range list {
+			systemContracts[name] = true
+		}
+	}
+
 	return err
 }
 
+// ParseSystemTxContracts parses the value of system_tx_contracts parameter
+func ParseSystemTxContracts(value string) ([]string, error) {
+	var list []string
+	if err := json.Unmarshal([]byte(value), &list); err != nil {
+		return nil, err
+	}
+	for _, name := range list {
+		if !strings.HasPrefix(name, `@`) {
+			return nil, fmt.Errorf("contract %s must be specified with ecosystem, e.g. @1%[1]s", name)
+		}
+	}
+	return list, nil
+}
+
+// IsSystemTx returns true if the transaction of the contract or embedded type is built into blocks
+// in the system lane. Oracle data transactions are always system ones
+func IsSystemTx(name string) bool {
+	if name == consts.TxTypes[consts.TxTypeOracleData] {
+		return true
+	}
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return systemContracts[name]
+}
+
+// GetSystemTxReserve returns the percent of block which is reserved for system transactions
+func GetSystemTxReserve() int64 {
+	reserve := converter.StrToInt64(SysString(SystemTxReserve))
+	if reserve < 0 || reserve > 100 {
+		return 0
+	}
+	return reserve
+}
+
+// ParseTxSizes parses the value of max_tx_size_by_type parameter
+func ParseTxSizes(value string) (map[string]int64, error) {
+	sizes := make(map[string]int64)
+	if err := json.Unmarshal([]byte(value), &sizes); err != nil {
+		return nil, err
+	}
+	for name, size := range sizes {
+		if len(name) == 0 || size <= 0 {
+			return nil, fmt.Errorf("wrong max size %d of %s", size, name)
+		}
+	}
+	return sizes, nil
+}
+
+// ParseFeeRouting parses the value of fee_routing parameter
+func ParseFeeRouting(value string) (*FeeShares, error) {
+	var shares FeeShares
+	dec := json.NewDecoder(strings.NewReader(value))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&shares); err != nil {
+		return nil, err
+	}
+	var sum int64
+	for _, share := range []int64{shares.Producer, shares.Validators, shares.Treasury, shares.Burn} {
+		if share < 0 || share > 100 {
+			return nil, fmt.Errorf("wrong fee share %d", share)
+		}
+		sum += share
+	}
+	if sum != 100 {
+		return nil, fmt.Errorf("fee shares sum up to %d instead of 100", sum)
+	}
+	return &shares, nil
+}
+
+// GetFeeRouting returns the shares of the transaction fee. If fee_routing isn't defined then
+// commission_size percents are paid to the treasury and the rest is paid to the block producer
+func GetFeeRouting() FeeShares {
+	if value := SysString(FeeRouting); len(value) > 0 {
+		shares, err := ParseFeeRouting(value)
+		if err == nil {
+			return *shares
+		}
+		log.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err}).Error("parsing fee routing")
+	}
+	commission := SysInt64(CommissionSize)
+	if commission < 0 || commission > 100 {
+		commission = 0
+	}
+	return FeeShares{Producer: 100 - commission, Treasury: commission}
+}
+
+// GetValidatorKeys returns the key identifiers of full nodes in the order of their positions
+func GetValidatorKeys() []int64 {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	keys := make([]int64, 0, len(nodesByPosition))
+	for _, item := range nodesByPosition {
+		if len(item) < 3 {
+			continue
+		}
+		keys = append(keys, converter.StrToInt64(item[1]))
+	}
+	return keys
+}
+
 // GetNode is retrieving node by wallet
 func GetNode(wallet int64) *FullNode {
 	mutex.RLock()
@@ -301,6 +493,18 @@ func GetMaxTxSize() int64 {
 	return converter.StrToInt64(SysString(MaxTxSize))
 }
 
+// GetMaxTxSizeByType returns max size of the transaction of the contract or embedded type,
+// the limit of type can't exceed max_tx_size, 0 means there is no limit
+func GetMaxTxSizeByType(name string) int64 {
+	max := GetMaxTxSize()
+	mutex.RLock()
+	defer mutex.RUnlock()
+	if size, ok := txSizes[name]; ok && (size < max || max <= 0) {
+		return size
+	}
+	return max
+}
+
 // GetGapsBetweenBlocks is returns gaps between blocks
 func GetGapsBetweenBlocks() int64 {
 	return converter.StrToInt64(SysString(GapsBetweenBlocks))
@@ -321,6 +525,49 @@ func GetMaxIndexes() int {
 	return converter.StrToInt(SysString(MaxIndexes))
 }
 
+// GetMaxAggregateRows returns the maximum count of rows which are scanned by the aggregate functions
+func GetMaxAggregateRows() int64 {
+	return SysInt64(MaxAggregateRows)
+}
+
+// GetStakingValidators returns the number of validators elected by stake, 0 means staking is disabled
+func GetStakingValidators() int {
+	return SysInt(StakingValidators)
+}
+
+// GetStakingMinStake returns the minimal stake of the elected validator
+func GetStakingMinStake() string {
+	if value := SysString(StakingMinStake); len(value) > 0 {
+		return value
+	}
+	return `0`
+}
+
+// GetStakingUnbondingBlocks returns the unbonding period in blocks
+func GetStakingUnbondingBlocks() int64 {
+	return SysInt64(StakingUnbondingBlocks)
+}
+
+// GetRecoveryMinDelay returns the minimal challenge period of the account recovery in seconds
+func GetRecoveryMinDelay() int64 {
+	return SysInt64(RecoveryMinDelay)
+}
+
+// GetStateHashBlock returns the first block which has the state hash in the header, 0 if it isn't activated
+func GetStateHashBlock() int64 {
+	return SysInt64(StateHashBlock)
+}
+
+// GetTxNonceBlock returns the first block which accepts the transactions with nonce, 0 if it isn't activated
+func GetTxNonceBlock() int64 {
+	return SysInt64(TxNonceBlock)
+}
+
+// GetFeeRewardBlock returns the first block which accrues the fees as rewards, 0 if it isn't activated
+func GetFeeRewardBlock() int64 {
+	return SysInt64(FeeRewardBlock)
+}
+
 // GetMaxBlockUserTx is returns max tx block user
 func GetMaxBlockUserTx() int {
 	return converter.StrToInt(SysString(MaxBlockUserTx))
@@ -355,6 +602,16 @@ func GetRbBlocks1() int64 {
 	return SysInt64(RbBlocks1)
 }
 
+// IsOracleKey returns true if the key is in the list of oracle keys
+func IsOracleKey(keyID int64) bool {
+	for _, item := range strings.Split(SysString(OracleKeys), `,`) {
+		if id, err := strconv.ParseInt(strings.TrimSpace(item), 10, 64); err == nil && id == keyID {
+			return true
+		}
+	}
+	return false
+}
+
 // HasSys returns boolean whether this system parameter exists
 func HasSys(name string) bool {
 	mutex.RLock()
@@ -362,3 +619,24 @@ func HasSys(name string) bool {
 	mutex.RUnlock()
 	return ok
 }
+
+// GetNetworkCA returns the public key of network CA, nil means the network isn't permissioned
+func GetNetworkCA() []byte {
+	ca, err := hex.DecodeString(SysString(NetworkCA))
+	if err != nil || len(ca) == 0 {
+		return nil
+	}
+	return ca
+}
+
+// IsPermissioned returns true if only the nodes certified by network CA can connect
+func IsPermissioned() bool {
+	return GetNetworkCA() != nil
+}
+
+// GetFullNodes returns the list of full nodes, each item is host, key id, public key and optional certificate
+func GetFullNodes() [][]string {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return nodesByPosition
+}
@@ -0,0 +1,134 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package syspar
+
+import "testing"
+
+func TestGetMaxTxSizeByType(t *testing.T) {
+	sizes, err := ParseTxSizes(`{"@1UploadBinary": 2000, "OracleData": 100}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, value := range []string{`[]`, `{"@1Test": 0}`, `{"": 10}`} {
+		if _, err = ParseTxSizes(value); err == nil {
+			t.Errorf("expected error for %s", value)
+		}
+	}
+
+	mutex.Lock()
+	cache[MaxTxSize], txSizes = `1000`, sizes
+	mutex.Unlock()
+	defer func() {
+		mutex.Lock()
+		delete(cache, MaxTxSize)
+		txSizes = make(map[string]int64)
+		mutex.Unlock()
+	}()
+
+	for name, want := range map[string]int64{`OracleData`: 100, `@1UploadBinary`: 1000, `@1Test`: 1000} {
+		if got := GetMaxTxSizeByType(name); got != want {
+			t.Errorf("max size of %s: expected %d, got %d", name, want, got)
+		}
+	}
+}
+
+func TestIsSystemTx(t *testing.T) {
+	list, err := ParseSystemTxContracts(`["@1ElectNodes", "@1UpdateSysParam"]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, value := range []string{`{}`, `["ElectNodes"]`} {
+		if _, err = ParseSystemTxContracts(value); err == nil {
+			t.Errorf("expected error for %s", value)
+		}
+	}
+
+	mutex.Lock()
+	for _, name := range list {
+		systemContracts[name] = true
+	}
+	mutex.Unlock()
+	defer func() {
+		mutex.Lock()
+		systemContracts = make(map[string]bool)
+		mutex.Unlock()
+	}()
+
+	for name, want := range map[string]bool{`OracleData`: true, `@1ElectNodes`: true, `@1MoneyTransfer`: false} {
+		if got := IsSystemTx(name); got != want {
+			t.Errorf("system tx %s: expected %v, got %v", name, want, got)
+		}
+	}
+}
+
+func TestGetFeeRouting(t *testing.T) {
+	for _, value := range []string{`[]`, `{"producer": 90}`, `{"producer": 110, "burn": -10}`,
+		`{"producer": 50, "treasury": 50, "owner": 0}`} {
+		if _, err := ParseFeeRouting(value); err == nil {
+			t.Errorf("expected error for %s", value)
+		}
+	}
+
+	defer func() {
+		mutex.Lock()
+		delete(cache, FeeRouting)
+		delete(cache, CommissionSize)
+		mutex.Unlock()
+	}()
+	cases := []struct {
+		routing, commission string
+		want                FeeShares
+	}{
+		{``, ``, FeeShares{Producer: 100}},
+		{``, `3`, FeeShares{Producer: 97, Treasury: 3}},
+		{`{"producer": 70, "validators": 20, "burn": 10}`, `3`, FeeShares{Producer: 70, Validators: 20, Burn: 10}},
+		{`{"producer": 70}`, `5`, FeeShares{Producer: 95, Treasury: 5}},
+	}
+	for _, c := range cases {
+		mutex.Lock()
+		cache[FeeRouting], cache[CommissionSize] = c.routing, c.commission
+		mutex.Unlock()
+		if got := GetFeeRouting(); got != c.want {
+			t.Errorf("%s with commission %s: expected %+v, got %+v", c.routing, c.commission, c.want, got)
+		}
+	}
+}
+
+func TestGetParamInfo(t *testing.T) {
+	cases := []struct {
+		name  string
+		info  ParamInfo
+		known bool
+	}{
+		{MaxTxCount, ParamInfo{TypeInt, `1000`}, true},
+		{FuelRate, ParamInfo{TypeJSON, `[["1","1000000000000000"]]`}, true},
+		{`extend_cost_sha256`, ParamInfo{Type: TypeInt}, true},
+		{`unknown_param`, ParamInfo{Type: TypeString}, false},
+	}
+	for _, c := range cases {
+		info, known := GetParamInfo(c.name)
+		if info != c.info || known != c.known {
+			t.Errorf("%s: expected %+v %v, got %+v %v", c.name, c.info, c.known, info, known)
+		}
+	}
+}
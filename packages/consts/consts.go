@@ -23,10 +23,13 @@
 package consts
 
 // VERSION is current version
-const VERSION = "0.1.6b13"
+const VERSION = "0.1.7.51"
 
 // BLOCK_VERSION is block version
-const BLOCK_VERSION = 1
+const BLOCK_VERSION = 2
+
+// BASE_BLOCK_VERSION is the version of blocks before the activation of BLOCK_VERSION by state_hash_block
+const BASE_BLOCK_VERSION = 1
 
 // DEFAULT_TCP_PORT used when port number missed in host addr
 const DEFAULT_TCP_PORT = 7078
@@ -90,8 +93,12 @@ const HashSize = 32
 // TxTypes is the list of the embedded transactions
 var TxTypes = map[int]string{
 	1: "FirstBlock",
+	2: "OracleData",
 }
 
+// TxTypeOracleData is the type of the transaction with oracle data
+const TxTypeOracleData = 2
+
 // ApiPath is the beginning of the api url
 var ApiPath = `/api/v2/`
 
@@ -116,6 +123,9 @@ const NodePrivateKeyFilename = "NodePrivateKey"
 // NodePublicKeyFilename name of node public key file
 const NodePublicKeyFilename = "NodePublicKey"
 
+// NodeCertificateFilename name of the file of node certificate issued by network CA
+const NodeCertificateFilename = "NodeCertificate"
+
 // KeyIDFilename generated KeyID
 const KeyIDFilename = "KeyID"
 
@@ -130,3 +140,6 @@ const TLSFullchainPem = "/fullchain.pem"
 
 // TLSPrivkeyPem privkey pem file
 const TLSPrivkeyPem = "/privkey.pem"
+
+// SyslogTag is default tag of syslog entries
+const SyslogTag = "go-genesis"
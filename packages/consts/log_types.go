@@ -72,4 +72,8 @@ const (
 	MigrationError           = "MigrationError"
 	AutoupdateError          = "AutoupdateError"
 	SchedulerError           = "SchedulerError"
+	BridgeError              = "BridgeError"
+	AuthProviderError        = "AuthProviderError"
+	InjectedFault            = "InjectedFault"
+	ClockError               = "ClockError"
 )
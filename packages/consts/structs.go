@@ -51,27 +51,36 @@ type FirstBlock struct {
 	Host          string
 }
 
+// OracleData is the transaction with the signed value of the oracle data feed
+type OracleData struct {
+	TxHeader
+	Feed     string
+	Value    string
+	DataTime int64
+	Sign     []byte
+}
+
 // Don't forget to insert the structure in init() - list
 
 var blockStructs = make(map[string]reflect.Type)
 
 func init() {
-	list := []interface{}{FirstBlock{}} // New structures must be inserted here
+	list := []interface{}{FirstBlock{}, OracleData{}} // New structures must be inserted here
 
 	for _, item := range list {
 		blockStructs[reflect.TypeOf(item).Name()] = reflect.TypeOf(item)
 	}
 }
 
-// MakeStruct is only used for FirstBlock now
+// MakeStruct is only used for FirstBlock and OracleData now
 func MakeStruct(name string) interface{} {
 	v := reflect.New(blockStructs[name]) //.Elem()
 	return v.Interface()
 }
 
-// IsStruct is only used for FirstBlock now
+// IsStruct is only used for FirstBlock and OracleData now
 func IsStruct(tx int) bool {
-	return tx == 1
+	return tx == 1 || tx == TxTypeOracleData
 }
 
 // Header returns TxHeader
@@ -152,7 +152,12 @@ func DecodeLength(buf *[]byte) (ret int64, err error) {
 			log.WithFields(log.Fields{"data_length": len(*buf), "length": int(length + 1)}).Error("length of data is smaller then encoded length")
 			return 0, fmt.Errorf(`input slice has small size`)
 		}
-		ret = int64(binary.BigEndian.Uint64(append(make([]byte, 8-length), (*buf)[1:length+1]...)))
+		if length > 8 {
+			return 0, fmt.Errorf(`wrong length size %d`, length)
+		}
+		var val [8]byte
+		copy(val[8-length:], (*buf)[1:length+1])
+		ret = int64(binary.BigEndian.Uint64(val[:]))
 	} else {
 		ret = int64(length)
 		length = 0
@@ -181,7 +186,12 @@ func DecodeLengthBuf(buf *bytes.Buffer) (int, error) {
 		log.WithFields(log.Fields{"data_length": buf.Len(), "length": int(length), "type": consts.UnmarshallingError}).Error("length of data is smaller then encoded length")
 		return 0, fmt.Errorf(`input slice has small size`)
 	}
-	return int(binary.BigEndian.Uint64(append(make([]byte, 8-length), buf.Next(int(length))...))), nil
+	if length > 8 {
+		return 0, fmt.Errorf(`wrong length size %d`, length)
+	}
+	var val [8]byte
+	copy(val[8-length:], buf.Next(int(length)))
+	return int(binary.BigEndian.Uint64(val[:])), nil
 }
 
 // BinMarshal converts v parameter to []byte slice.
@@ -0,0 +1,145 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package converter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultTimeFormat is used if the format of date and time isn't specified
+const DefaultTimeFormat = `YYYY-MM-DD HH:MI:SS`
+
+// timeLayouts are the accepted representations of date and time, the time is UTC if the offset isn't specified
+var timeLayouts = []string{
+	time.RFC3339,
+	`2006-01-02T15:04:05`,
+	`2006-01-02 15:04:05Z07:00`,
+	`2006-01-02 15:04:05Z07`,
+	`2006-01-02 15:04:05`,
+	`2006-01-02T15:04`,
+	`2006-01-02 15:04`,
+	`2006-01-02`,
+}
+
+// timeFormat replaces YYYY, YY, MM, DD, HH, MI, SS in the format with the elements of Go layout
+var timeFormat = strings.NewReplacer(`YYYY`, `2006`, `YY`, `06`, `MM`, `01`, `DD`, `02`,
+	`HH`, `15`, `MI`, `04`, `SS`, `05`)
+
+var timeUnits = map[string]time.Duration{
+	`second`: time.Second,
+	`minute`: time.Minute,
+	`hour`:   time.Hour,
+	`day`:    24 * time.Hour,
+	`week`:   7 * 24 * time.Hour,
+}
+
+// ParseTime returns the unix time of the date and time, the value can be unix time too
+func ParseTime(value string) (int64, error) {
+	value = strings.TrimSpace(value)
+	if unix, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return unix, nil
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.Unix(), nil
+		}
+	}
+	return 0, fmt.Errorf(`wrong date and time %s`, value)
+}
+
+// FormatTime returns UTC date and time of the unix time in the format like YYYY-MM-DD HH:MI:SS
+func FormatTime(format string, unix int64) string {
+	if len(format) == 0 {
+		format = DefaultTimeFormat
+	}
+	return time.Unix(unix, 0).UTC().Format(timeFormat.Replace(format))
+}
+
+// addMonths adds months keeping the day of month if it is possible, otherwise the last day of month is taken
+func addMonths(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+	first := time.Date(year, month+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+	if last := first.AddDate(0, 1, -1).Day(); day > last {
+		day = last
+	}
+	return first.AddDate(0, 0, day-1)
+}
+
+// AddInterval adds the interval like '1 day', '-2 hours' or '1 year 3 months' to the unix time
+func AddInterval(unix int64, interval string) (int64, error) {
+	fields := strings.Fields(interval)
+	sign := 1
+	if len(fields) > 0 && (fields[0] == `+` || fields[0] == `-`) {
+		if fields[0] == `-` {
+			sign = -1
+		}
+		fields = fields[1:]
+	}
+	if len(fields) == 0 || len(fields)%2 != 0 {
+		return 0, fmt.Errorf(`wrong interval %s`, interval)
+	}
+	t := time.Unix(unix, 0).UTC()
+	for i := 0; i < len(fields); i += 2 {
+		count, err := strconv.Atoi(fields[i])
+		if err != nil {
+			return 0, fmt.Errorf(`wrong interval %s`, interval)
+		}
+		count *= sign
+		unit := strings.TrimSuffix(strings.ToLower(fields[i+1]), `s`)
+		switch unit {
+		case `month`:
+			t = addMonths(t, count)
+		case `year`:
+			t = addMonths(t, 12*count)
+		default:
+			duration, ok := timeUnits[unit]
+			if !ok {
+				return 0, fmt.Errorf(`wrong unit of interval %s`, fields[i+1])
+			}
+			t = t.Add(time.Duration(count) * duration)
+		}
+	}
+	return t.Unix(), nil
+}
+
+// CompareTime returns -1, 0 or 1 if the first date and time is earlier, equal or later than the second one
+func CompareTime(left, right string) (int, error) {
+	lunix, err := ParseTime(left)
+	if err != nil {
+		return 0, err
+	}
+	runix, err := ParseTime(right)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case lunix < runix:
+		return -1, nil
+	case lunix > runix:
+		return 1, nil
+	}
+	return 0, nil
+}
@@ -0,0 +1,80 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package converter
+
+import "testing"
+
+func TestParseTime(t *testing.T) {
+	for value, want := range map[string]int64{
+		`1510077068`:                1510077068,
+		`2017-11-07T17:51:08`:       1510077068,
+		`2017-11-07 17:51:08`:       1510077068,
+		`2017-11-07T17:51:08Z`:      1510077068,
+		`2017-11-07T20:51:08+03:00`: 1510077068,
+		`2017-11-07 20:51:08+03`:    1510077068,
+		`2017-11-07 17:51:08.4512`:  1510077068,
+		`2017-11-07`:                1510012800,
+	} {
+		got, err := ParseTime(value)
+		if err != nil || got != want {
+			t.Errorf(`%s: %d != %d, %v`, value, got, want, err)
+		}
+	}
+	if _, err := ParseTime(`07.11.2017`); err == nil {
+		t.Error(`expected error of wrong date`)
+	}
+	if got := FormatTime(`DD.MM.YYYY HH:MI`, 1510077068); got != `07.11.2017 17:51` {
+		t.Errorf(`wrong format %s`, got)
+	}
+	if got := FormatTime(``, 0); got != `1970-01-01 00:00:00` {
+		t.Errorf(`wrong default format %s`, got)
+	}
+}
+
+func TestAddInterval(t *testing.T) {
+	for _, item := range []struct {
+		date, interval, want string
+	}{
+		{`2017-11-07 17:51:08`, `1 day`, `2017-11-08 17:51:08`},
+		{`2017-11-07 17:51:08`, `-2 hours 30 minutes`, `2017-11-07 16:21:08`},
+		{`2017-11-07 17:51:08`, `- 1 week 1 second`, `2017-10-31 17:51:07`},
+		{`2018-01-31 10:00:00`, `1 month`, `2018-02-28 10:00:00`},
+		{`2016-02-29 10:00:00`, `1 year`, `2017-02-28 10:00:00`},
+		{`2017-03-31 00:00:00`, `-1 month`, `2017-02-28 00:00:00`},
+		{`2017-11-07 17:51:08`, `1 Year 2 Months`, `2019-01-07 17:51:08`},
+	} {
+		unix, _ := ParseTime(item.date)
+		got, err := AddInterval(unix, item.interval)
+		if err != nil || FormatTime(``, got) != item.want {
+			t.Errorf(`%s + %s: %s != %s, %v`, item.date, item.interval, FormatTime(``, got), item.want, err)
+		}
+	}
+	for _, interval := range []string{``, `1`, `day`, `1 fortnight`, `x days`} {
+		if _, err := AddInterval(0, interval); err == nil {
+			t.Errorf(`expected error of interval %q`, interval)
+		}
+	}
+	if cmp, err := CompareTime(`2017-11-07T20:51:08+03:00`, `2017-11-07 17:51:09`); err != nil || cmp != -1 {
+		t.Errorf(`wrong compare %d, %v`, cmp, err)
+	}
+}
@@ -0,0 +1,61 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package converter
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var regexpJSONKey = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// JSONPath splits the path to the keys of objects and the indexes of arrays, for example, items.0.name
+func JSONPath(path string) ([]string, error) {
+	keys := strings.Split(path, `.`)
+	for _, key := range keys {
+		if !regexpJSONKey.MatchString(key) {
+			return nil, fmt.Errorf(`wrong key '%s' in JSON path %s`, key, path)
+		}
+	}
+	return keys, nil
+}
+
+// JSONPathSQL returns SQL expression which gets the text value of jsonb column by the keys
+func JSONPathSQL(column string, keys []string) string {
+	out := `"` + column + `"`
+	for i, key := range keys {
+		if i == len(keys)-1 {
+			out += `->>`
+		} else {
+			out += `->`
+		}
+		if _, err := strconv.Atoi(key); err == nil {
+			out += key
+		} else {
+			out += `'` + key + `'`
+		}
+	}
+	return `(` + out + `)`
+}
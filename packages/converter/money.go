@@ -0,0 +1,140 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package converter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// MoneyFormat describes the representation of money amounts which are stored as integers
+// of the minimal units
+type MoneyFormat struct {
+	Digits             int32
+	DecimalSeparator   string
+	ThousandsSeparator string
+	Symbol             string
+	SymbolAfter        bool
+}
+
+// DefaultMoneyFormat returns the format without symbol and with the specified count of decimal places
+func DefaultMoneyFormat(digits int32) *MoneyFormat {
+	return &MoneyFormat{Digits: digits, DecimalSeparator: `.`, ThousandsSeparator: `,`}
+}
+
+func (f *MoneyFormat) check() error {
+	if f.Digits < 0 || f.Digits > 30 {
+		return fmt.Errorf(`wrong count of decimal places %d`, f.Digits)
+	}
+	if len(f.DecimalSeparator) == 0 || f.DecimalSeparator == f.ThousandsSeparator {
+		return fmt.Errorf(`wrong decimal separator '%s'`, f.DecimalSeparator)
+	}
+	return nil
+}
+
+// Format returns the amount with the separators and the currency symbol
+func (f *MoneyFormat) Format(value string) (string, error) {
+	if err := f.check(); err != nil {
+		return ``, err
+	}
+	amount, err := decimal.NewFromString(strings.TrimSpace(value))
+	if err != nil {
+		return ``, fmt.Errorf(`wrong money value %s`, value)
+	}
+	var sign string
+	if amount.Sign() < 0 {
+		sign = `-`
+		amount = amount.Neg()
+	}
+	fixed := amount.Mul(decimal.New(1, -f.Digits)).StringFixed(f.Digits)
+	intPart, fracPart := fixed, ``
+	if f.Digits > 0 {
+		intPart, fracPart = fixed[:len(fixed)-int(f.Digits)-1], fixed[len(fixed)-int(f.Digits):]
+	}
+	var out strings.Builder
+	for i, r := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			out.WriteString(f.ThousandsSeparator)
+		}
+		out.WriteRune(r)
+	}
+	if len(fracPart) > 0 {
+		out.WriteString(f.DecimalSeparator + fracPart)
+	}
+	if len(f.Symbol) == 0 {
+		return sign + out.String(), nil
+	}
+	if f.SymbolAfter {
+		return sign + out.String() + ` ` + f.Symbol, nil
+	}
+	return sign + f.Symbol + out.String(), nil
+}
+
+// Parse converts the formatted amount into the integer of the minimal units
+func (f *MoneyFormat) Parse(text string) (string, error) {
+	if err := f.check(); err != nil {
+		return ``, err
+	}
+	value := strings.TrimSpace(text)
+	var negative bool
+	if strings.HasPrefix(value, `-`) {
+		negative = true
+		value = strings.TrimSpace(value[1:])
+	}
+	if len(f.Symbol) > 0 {
+		value = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(value, f.Symbol), f.Symbol))
+	}
+	if len(f.ThousandsSeparator) > 0 {
+		value = strings.Replace(value, f.ThousandsSeparator, ``, -1)
+	}
+	intPart, fracPart := value, ``
+	if off := strings.Index(value, f.DecimalSeparator); off >= 0 {
+		intPart, fracPart = value[:off], value[off+len(f.DecimalSeparator):]
+	}
+	if len(intPart)+len(fracPart) == 0 || !isDigits(intPart) || !isDigits(fracPart) {
+		return ``, fmt.Errorf(`wrong money amount %s`, text)
+	}
+	if len(fracPart) > int(f.Digits) {
+		return ``, fmt.Errorf(`money amount %s has more than %d decimal places`, text, f.Digits)
+	}
+	amount, err := decimal.NewFromString(intPart + `.` + fracPart + `0`)
+	if err != nil {
+		return ``, fmt.Errorf(`wrong money amount %s`, text)
+	}
+	amount = amount.Mul(decimal.New(1, f.Digits))
+	if negative {
+		amount = amount.Neg()
+	}
+	return amount.StringFixed(0), nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
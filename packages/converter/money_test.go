@@ -0,0 +1,71 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package converter
+
+import "testing"
+
+func TestMoneyFormat(t *testing.T) {
+	euro := &MoneyFormat{Digits: 2, DecimalSeparator: `,`, ThousandsSeparator: ` `, Symbol: `€`, SymbolAfter: true}
+	dollar := &MoneyFormat{Digits: 2, DecimalSeparator: `.`, ThousandsSeparator: `,`, Symbol: `$`}
+	cases := []struct {
+		format *MoneyFormat
+		value  string
+		text   string
+	}{
+		{DefaultMoneyFormat(2), `123456789`, `1,234,567.89`},
+		{DefaultMoneyFormat(2), `5`, `0.05`},
+		{DefaultMoneyFormat(0), `1000`, `1,000`},
+		{DefaultMoneyFormat(18), `1500000000000000000`, `1.500000000000000000`},
+		{dollar, `-100000`, `-$1,000.00`},
+		{euro, `123456`, `1 234,56 €`},
+	}
+	for _, item := range cases {
+		text, err := item.format.Format(item.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if text != item.text {
+			t.Errorf("format %s: %s != %s", item.value, text, item.text)
+		}
+		value, err := item.format.Parse(text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if value != item.value {
+			t.Errorf("parse %s: %s != %s", text, value, item.value)
+		}
+	}
+	for _, text := range []string{`12.5`, `$ 1,000`, `.5`, `7`} {
+		if _, err := dollar.Parse(text); err != nil {
+			t.Errorf("%s: %v", text, err)
+		}
+	}
+	for _, text := range []string{``, `1.234`, `abc`, `1.2.3`, `$`, `1e5`} {
+		if _, err := dollar.Parse(text); err == nil {
+			t.Errorf("%s must be rejected", text)
+		}
+	}
+	if _, err := (&MoneyFormat{Digits: 2, DecimalSeparator: `,`, ThousandsSeparator: `,`}).Format(`1`); err == nil {
+		t.Error("equal separators must be rejected")
+	}
+}
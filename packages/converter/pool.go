@@ -0,0 +1,69 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package converter
+
+import (
+	"encoding/hex"
+	"sync"
+)
+
+const (
+	pooledBytesSize    = 4096
+	maxPooledBytesSize = 1 << 20
+)
+
+var bytesPool = sync.Pool{
+	New: func() interface{} {
+		buf := make([]byte, 0, pooledBytesSize)
+		return &buf
+	},
+}
+
+// GetBytes returns an empty byte slice from the pool
+func GetBytes() *[]byte {
+	buf := bytesPool.Get().(*[]byte)
+	*buf = (*buf)[:0]
+	return buf
+}
+
+// PutBytes returns the byte slice to the pool, the caller must not use it after that
+func PutBytes(buf *[]byte) {
+	if buf == nil || cap(*buf) > maxPooledBytesSize {
+		return
+	}
+	bytesPool.Put(buf)
+}
+
+// AppendHex appends the hex representation of src to dst
+func AppendHex(dst, src []byte) []byte {
+	n := len(dst)
+	size := hex.EncodedLen(len(src))
+	if cap(dst)-n < size {
+		grown := make([]byte, n, 2*cap(dst)+size)
+		copy(grown, dst)
+		dst = grown
+	}
+	dst = dst[:n+size]
+	hex.Encode(dst[n:], src)
+	return dst
+}
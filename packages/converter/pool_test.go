@@ -0,0 +1,79 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package converter
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAppendHex(t *testing.T) {
+	buf := GetBytes()
+	defer PutBytes(buf)
+	*buf = AppendHex(*buf, []byte{0x01, 0xab})
+	*buf = AppendHex(*buf, []byte{0xff})
+	if string(*buf) != `01abff` {
+		t.Errorf("wrong hex %s", *buf)
+	}
+	if got := AppendHex(make([]byte, 0, 1), []byte{0x10, 0x20}); string(got) != `1020` {
+		t.Errorf("wrong hex %s", got)
+	}
+}
+
+func TestDecodeLengthBuf(t *testing.T) {
+	for _, length := range []int64{0, 1, 127, 128, 1000, 1 << 40} {
+		encoded := EncodeLength(length)
+		val, err := DecodeLengthBuf(bytes.NewBuffer(encoded))
+		if err != nil || int64(val) != length {
+			t.Errorf("wrong decoded length %d (%d) %v", val, length, err)
+		}
+		if val, err := DecodeLength(&encoded); err != nil || val != length {
+			t.Errorf("wrong decoded length %d (%d) %v", val, length, err)
+		}
+	}
+	if _, err := DecodeLengthBuf(bytes.NewBuffer([]byte{0x89, 1, 2, 3, 4, 5, 6, 7, 8, 9})); err == nil {
+		t.Error("expected error for wrong length size")
+	}
+}
+
+func blockLengths(count int) []byte {
+	var data []byte
+	for i := 0; i < count; i++ {
+		data = append(data, EncodeLength(int64(i*100))...)
+	}
+	return data
+}
+
+func BenchmarkDecodeLengthBuf(b *testing.B) {
+	data := blockLengths(1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf := bytes.NewBuffer(data)
+		for buf.Len() > 0 {
+			if _, err := DecodeLengthBuf(buf); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
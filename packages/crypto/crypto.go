@@ -119,6 +119,39 @@ func Decrypt(msg []byte, key []byte, iv []byte) ([]byte, error) {
 	}
 }
 
+// EncryptAEAD encrypts the text with AES-GCM. The random nonce is prepended to the result
+func EncryptAEAD(text, key []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = crand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, text, nil), nil
+}
+
+// DecryptAEAD decrypts and authenticates the result of EncryptAEAD
+func DecryptAEAD(data, key []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, fmt.Errorf(`Wrong size of cipher %d`, len(data))
+	}
+	return gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+}
+
 // SharedEncrypt creates a shared key and encrypts text. The first 32 characters are the created public key.
 // The cipher text can be only decrypted with the original private key.
 func SharedEncrypt(public, text []byte) ([]byte, error) {
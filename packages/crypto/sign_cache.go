@@ -0,0 +1,134 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package crypto
+
+import (
+	"crypto/sha256"
+	"sync"
+	"sync/atomic"
+)
+
+// maxSignCache is the maximum count of the verified signatures kept in the cache
+var maxSignCache = 16384
+
+// SignCacheStats contains the counters of the cache of verified signatures
+type SignCacheStats struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+	Size   int   `json:"size"`
+}
+
+type signCache struct {
+	mutex sync.Mutex
+	items map[[sha256.Size]byte]struct{}
+	keys  [][sha256.Size]byte
+	next  int
+}
+
+var (
+	verifiedSigns        = &signCache{items: make(map[[sha256.Size]byte]struct{})}
+	signHits, signMisses int64
+)
+
+// signKey binds the signature to the public key and the signed data, so the changing of the key
+// of the wallet doesn't allow to use the previous verification
+func signKey(public []byte, data string, signature []byte) [sha256.Size]byte {
+	h := sha256.New()
+	for _, item := range [][]byte{public, []byte(data), signature} {
+		h.Write([]byte{byte(len(item) >> 24), byte(len(item) >> 16), byte(len(item) >> 8), byte(len(item))})
+		h.Write(item)
+	}
+	var key [sha256.Size]byte
+	copy(key[:], h.Sum(nil))
+	return key
+}
+
+func (c *signCache) has(key [sha256.Size]byte) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	_, ok := c.items[key]
+	return ok
+}
+
+func (c *signCache) add(key [sha256.Size]byte) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if _, ok := c.items[key]; ok {
+		return
+	}
+	if len(c.keys) < maxSignCache {
+		c.keys = append(c.keys, key)
+	} else {
+		delete(c.items, c.keys[c.next])
+		c.keys[c.next] = key
+		c.next = (c.next + 1) % maxSignCache
+	}
+	c.items[key] = struct{}{}
+}
+
+func (c *signCache) reset(limit int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	maxSignCache = limit
+	c.items = make(map[[sha256.Size]byte]struct{})
+	c.keys = nil
+	c.next = 0
+}
+
+func (c *signCache) size() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return len(c.items)
+}
+
+// CheckSignCached is CheckSign which remembers the valid signatures. Transactions are verified when
+// they come into the queue and once more when the block is processed, so the second check is free
+func CheckSignCached(public []byte, data string, signature []byte) (bool, error) {
+	key := signKey(public, data, signature)
+	if verifiedSigns.has(key) {
+		atomic.AddInt64(&signHits, 1)
+		return true, nil
+	}
+	atomic.AddInt64(&signMisses, 1)
+	ok, err := CheckSign(public, data, signature)
+	if err == nil && ok {
+		verifiedSigns.add(key)
+	}
+	return ok, err
+}
+
+// SetSignCacheSize clears the cache of verified signatures and changes its maximum count
+func SetSignCacheSize(limit int) {
+	if limit > 0 {
+		verifiedSigns.reset(limit)
+	}
+}
+
+// GetSignCacheStats returns the counters of the cache of verified signatures
+func GetSignCacheStats() SignCacheStats {
+	return SignCacheStats{
+		Hits:   atomic.LoadInt64(&signHits),
+		Misses: atomic.LoadInt64(&signMisses),
+		Size:   verifiedSigns.size(),
+	}
+}
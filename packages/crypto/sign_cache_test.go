@@ -0,0 +1,65 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	crand "crypto/rand"
+	"testing"
+
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+)
+
+func TestCheckSignCached(t *testing.T) {
+	private, err := ecdsa.GenerateKey(elliptic.P256(), crand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	public := append(converter.FillLeft(private.X.Bytes()), converter.FillLeft(private.Y.Bytes())...)
+	hash, err := Hash([]byte(`data`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, s, err := ecdsa.Sign(crand.Reader, private, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature := append(converter.FillLeft(r.Bytes()), converter.FillLeft(s.Bytes())...)
+	before := GetSignCacheStats()
+	for i := 0; i < 2; i++ {
+		if ok, err := CheckSignCached(public, `data`, signature); err != nil || !ok {
+			t.Fatalf("valid signature: %v", err)
+		}
+	}
+	if ok, _ := CheckSignCached(public, `other data`, signature); ok {
+		t.Error("signature of other data must be rejected")
+	}
+	if ok, _ := CheckSignCached(public, `other data`, signature); ok {
+		t.Error("invalid signature must not be cached")
+	}
+	stats := GetSignCacheStats()
+	if stats.Hits-before.Hits != 1 || stats.Misses-before.Misses != 3 {
+		t.Errorf("wrong stats %+v %+v", before, stats)
+	}
+}
@@ -26,11 +26,14 @@ import (
 	"context"
 	"time"
 
+	"github.com/GenesisKernel/go-genesis/packages/clock"
 	"github.com/GenesisKernel/go-genesis/packages/conf"
 
 	"github.com/GenesisKernel/go-genesis/packages/config/syspar"
 	"github.com/GenesisKernel/go-genesis/packages/consts"
 	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/diagnose"
+	"github.com/GenesisKernel/go-genesis/packages/faults"
 	"github.com/GenesisKernel/go-genesis/packages/model"
 	"github.com/GenesisKernel/go-genesis/packages/parser"
 	"github.com/GenesisKernel/go-genesis/packages/utils"
@@ -50,6 +53,13 @@ func BlockGenerator(ctx context.Context, d *daemon) error {
 		return nil
 	}
 
+	if clockDrifted() {
+		drift, _ := clock.Drift()
+		d.sleepTime = 10 * time.Second
+		d.logger.WithFields(log.Fields{"type": consts.ClockError, "drift": drift.String()}).Error("local clock drifts too much, block isn't generated")
+		return nil
+	}
+
 	DBLock()
 	defer DBUnlock()
 
@@ -101,17 +111,36 @@ func BlockGenerator(ctx context.Context, d *daemon) error {
 		d.logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting all unused transactions")
 		return err
 	}
+	if trs, err = dropOversizedTransactions(trs, d.logger); err != nil {
+		return err
+	}
+	blockTime := time.Now().Unix()
+	if trs, err = dropExpiredTransactions(trs, prevBlock.BlockID+1, blockTime, d.logger); err != nil {
+		return err
+	}
+	var usage diagnose.LaneUsage
+	trs, usage = fitTransactions(trs, blockLimits{
+		Size:    syspar.GetMaxBlockSize() - blockHeaderReserve,
+		Count:   syspar.GetMaxTxCount(),
+		UserTx:  syspar.GetMaxBlockUserTx(),
+		Reserve: syspar.GetSystemTxReserve(),
+	})
+	diagnose.ObserveLanes(usage)
 
 	//Block generation will be started only if we have transactions
 	//if len(trs) == 0 {
 	//	return nil
 	//}
 
+	if err = faults.Hit(faults.BlockGenerate); err != nil {
+		return err
+	}
+
 	blockBin, err := generateNextBlock(
 		prevBlock,
 		trs,
 		NodePrivateKey,
-		time.Now().Unix(),
+		blockTime,
 		myNodePosition,
 		conf.Config.EcosystemID,
 		conf.Config.KeyID,
@@ -134,11 +163,18 @@ func generateNextBlock(
 
 	header := &utils.BlockData{
 		BlockID:      prevBlock.BlockID + 1,
-		Time:         time.Now().Unix(),
+		Time:         blockTime,
 		EcosystemID:  ecosystemID,
 		KeyID:        keyID,
 		NodePosition: myNodePosition,
-		Version:      consts.BLOCK_VERSION,
+		Version:      parser.BlockVersion(prevBlock.BlockID + 1),
+	}
+	if header.Version >= consts.BLOCK_VERSION {
+		stateHash, err := parser.GetStateHash(prevBlock.BlockID)
+		if err != nil {
+			return nil, err
+		}
+		header.StateHash = stateHash
 	}
 
 	trData := make([][]byte, 0, len(trs))
@@ -148,3 +184,114 @@ func generateNextBlock(
 
 	return parser.MarshallBlock(header, trData, prevBlock.Hash, key)
 }
+
+// blockHeaderReserve is the part of max_block_size which is left for the header and the signature of block
+const blockHeaderReserve = 1024
+
+// dropOversizedTransactions marks the transactions which exceed the size limit of their type as bad,
+// the limit could be decreased after the transactions had been verified
+func dropOversizedTransactions(trs []model.Transaction, logger *log.Entry) ([]model.Transaction, error) {
+	ret := trs[:0]
+	for _, tr := range trs {
+		err := parser.CheckTxSize(tr.Data)
+		if _, ok := err.(*parser.TxSizeError); !ok {
+			ret = append(ret, tr)
+			continue
+		}
+		if _, err2 := model.MarkTransactionUsed(nil, tr.Hash); err2 != nil {
+			logger.WithFields(log.Fields{"type": consts.DBError, "error": err2}).Error("marking transaction used")
+			return nil, err2
+		}
+		if err2 := (&model.TransactionStatus{}).SetError(nil, err.Error(), tr.Hash); err2 != nil {
+			logger.WithFields(log.Fields{"type": consts.DBError, "error": err2}).Error("setting transaction status error")
+			return nil, err2
+		}
+	}
+	return ret, nil
+}
+
+// dropExpiredTransactions marks the transactions which can't be included into the next block
+// because of their expiration as bad
+func dropExpiredTransactions(trs []model.Transaction, blockID, blockTime int64, logger *log.Entry) ([]model.Transaction, error) {
+	ret := trs[:0]
+	for _, tr := range trs {
+		if parser.CheckTxExpire(tr.Data, blockID, blockTime) != parser.ErrTxExpired {
+			ret = append(ret, tr)
+			continue
+		}
+		if _, err := model.MarkTransactionUsed(nil, tr.Hash); err != nil {
+			logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("marking transaction used")
+			return nil, err
+		}
+		if err := (&model.TransactionStatus{}).SetError(nil, parser.ExpiredStatus, tr.Hash); err != nil {
+			logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("setting transaction status error")
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+// blockLimits are the limits of the transactions of block, 0 means there is no limit
+type blockLimits struct {
+	Size    int64
+	Count   int
+	UserTx  int
+	Reserve int64 // the percent of Size and Count reserved for the system transactions
+}
+
+// fitTransactions returns the transactions which fit into the block. The system transactions
+// are taken first and can fill the whole block, the other ones can't take the reserved part of block.
+// The rest of transactions are left for the next blocks
+func fitTransactions(trs []model.Transaction, limits blockLimits) ([]model.Transaction, diagnose.LaneUsage) {
+	usage := diagnose.LaneUsage{BlockSize: limits.Size, ReservedSize: limits.Size * limits.Reserve / 100}
+	ret := make([]model.Transaction, 0, len(trs))
+	users := make(map[int64]int)
+	var size int64
+
+	fill := func(system bool, maxSize int64, maxCount int) {
+		for _, tr := range trs {
+			if (tr.HighRate > 0) != system {
+				continue
+			}
+			if maxCount > 0 && len(ret) >= maxCount {
+				return
+			}
+			if limits.UserTx > 0 && users[tr.KeyID] >= limits.UserTx {
+				continue
+			}
+			txSize := int64(len(tr.Data) + len(converter.EncodeLength(int64(len(tr.Data)))))
+			if maxSize > 0 && size+txSize > maxSize {
+				continue
+			}
+			size += txSize
+			users[tr.KeyID]++
+			ret = append(ret, tr)
+			if system {
+				usage.SystemTx++
+				usage.SystemSize += txSize
+			} else {
+				usage.OtherTx++
+				usage.OtherSize += txSize
+			}
+		}
+	}
+	fill(true, limits.Size, limits.Count)
+
+	otherSize, otherCount := limits.Size, limits.Count
+	if otherSize > 0 {
+		otherSize = usage.SystemSize + limits.Size - usage.ReservedSize
+		if otherSize > limits.Size {
+			otherSize = limits.Size
+		}
+	}
+	if otherCount > 0 {
+		otherCount = usage.SystemTx + limits.Count - limits.Count*int(limits.Reserve)/100
+		if otherCount > limits.Count {
+			otherCount = limits.Count
+		}
+	}
+	fill(false, otherSize, otherCount)
+
+	usage.DeferredTx = len(trs) - len(ret)
+	return ret, usage
+}
@@ -31,6 +31,7 @@ import (
 	"net/http"
 	"os"
 	"sync"
+	"sync/atomic"
 
 	"github.com/GenesisKernel/go-genesis/packages/conf"
 	"github.com/GenesisKernel/go-genesis/packages/config/syspar"
@@ -38,12 +39,23 @@ import (
 	"github.com/GenesisKernel/go-genesis/packages/converter"
 	"github.com/GenesisKernel/go-genesis/packages/model"
 	"github.com/GenesisKernel/go-genesis/packages/parser"
+	"github.com/GenesisKernel/go-genesis/packages/tcpserver"
 	"github.com/GenesisKernel/go-genesis/packages/utils"
 
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/net/context/ctxhttp"
 )
 
+// networkMaxBlockID is the biggest block id of remote hosts got by the last collection,
+// it is -1 until some remote host has responded
+var networkMaxBlockID int64 = -1
+
+// NetworkMaxBlockID returns the biggest block id which has been found on remote hosts
+// or -1 if the height of the network is unknown yet
+func NetworkMaxBlockID() int64 {
+	return atomic.LoadInt64(&networkMaxBlockID)
+}
+
 // BlocksCollection collects and parses blocks
 func BlocksCollection(ctx context.Context, d *daemon) error {
 	if err := initialLoad(ctx, d); err != nil {
@@ -86,6 +98,12 @@ func blocksCollection(ctx context.Context, d *daemon) error {
 	if err != nil {
 		return err
 	}
+	if len(hosts) == 0 {
+		// the node is alone so its own chain is the network
+		atomic.StoreInt64(&networkMaxBlockID, 0)
+	} else if maxBlockID > 0 {
+		atomic.StoreInt64(&networkMaxBlockID, maxBlockID)
+	}
 
 	// NOTE: should be generalized in separate method
 	infoBlock := &model.InfoBlock{}
@@ -119,6 +137,7 @@ func chooseBestHost(ctx context.Context, hosts []string, logger *log.Entry) (str
 	}
 	c := make(chan blockAndHost, len(hosts))
 
+	limiter := newHostLimiter()
 	var wg sync.WaitGroup
 	for _, h := range hosts {
 		if ctx.Err() != nil {
@@ -128,7 +147,9 @@ func chooseBestHost(ctx context.Context, hosts []string, logger *log.Entry) (str
 		wg.Add(1)
 
 		go func(host string) {
+			limiter.acquire()
 			blockID, err := getHostBlockID(host, logger)
+			limiter.release()
 			wg.Done()
 
 			c <- blockAndHost{
@@ -154,8 +175,37 @@ func chooseBestHost(ctx context.Context, hosts []string, logger *log.Entry) (str
 	return bestHost, maxBlockID, nil
 }
 
+type blockBody struct {
+	data []byte
+	err  error
+}
+
+// prefetchBlocks downloads the blocks from the host and verifies the signatures of their transactions
+// while the previous blocks are being applied. The channel is closed after the last block or an error
+func prefetchBlocks(ctx context.Context, host string, fromID, toID int64) <-chan blockBody {
+	bodies := make(chan blockBody, conf.Resources().PrefetchDepth)
+	go func() {
+		defer close(bodies)
+		for blockID := fromID; blockID <= toID; blockID++ {
+			data, err := tcpserver.GetBlockBody(host, blockID, consts.DATA_TYPE_BLOCK_BODY)
+			if err == nil {
+				parser.PrefetchBlock(data)
+			}
+			select {
+			case bodies <- blockBody{data: data, err: err}:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return bodies
+}
+
 func getHostBlockID(host string, logger *log.Entry) (int64, error) {
-	conn, err := utils.TCPConn(host)
+	conn, err := tcpserver.Dial(host)
 	if err != nil {
 		logger.WithFields(log.Fields{"error": err, "type": consts.ConnectionError, "host": host}).Debug("error connecting to host")
 		return 0, err
@@ -171,11 +221,12 @@ func getHostBlockID(host string, logger *log.Entry) (int64, error) {
 
 	// response
 	blockIDBin := make([]byte, 4)
-	_, err = conn.Read(blockIDBin)
+	_, err = io.ReadFull(conn, blockIDBin)
 	if err != nil {
 		logger.WithFields(log.Fields{"error": err, "type": consts.ConnectionError, "host": host}).Error("reading max block id from host")
 		return 0, err
 	}
+	conn.Release()
 
 	return converter.BinToDec(blockIDBin), nil
 }
@@ -190,17 +241,25 @@ func UpdateChain(ctx context.Context, d *daemon, host string, maxBlockID int64)
 		return err
 	}
 
+	prefetchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	bodies := prefetchBlocks(prefetchCtx, host, curBlock.BlockID+1, maxBlockID)
+
 	for blockID := curBlock.BlockID + 1; blockID <= maxBlockID; blockID++ {
 		if ctx.Err() != nil {
 			d.logger.WithFields(log.Fields{"type": consts.ContextError, "error": ctx.Err()}).Error("context error")
 			return ctx.Err()
 		}
 
-		blockBin, err := utils.GetBlockBody(host, blockID, consts.DATA_TYPE_BLOCK_BODY)
-		if err != nil {
-			d.logger.WithFields(log.Fields{"error": err, "type": consts.BlockError}).Error("getting block body")
-			return err
+		body, ok := <-bodies
+		if !ok {
+			return ctx.Err()
+		}
+		if body.err != nil {
+			d.logger.WithFields(log.Fields{"error": body.err, "type": consts.BlockError}).Error("getting block body")
+			return body.err
 		}
+		blockBin := body.data
 
 		block, err := parser.ProcessBlockWherePrevFromBlockchainTable(blockBin)
 		if err != nil {
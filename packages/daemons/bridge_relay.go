@@ -0,0 +1,192 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package daemons
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/bridge"
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/scheduler/contract"
+	"github.com/GenesisKernel/go-genesis/packages/tcpserver"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// bridgeResubmitTimeout is the time after which the transfer is submitted again if it hasn't been received
+const bridgeResubmitTimeout = 5 * time.Minute
+
+// bridgeSubmitted contains the time of the last submission of the transfers
+var bridgeSubmitted = make(map[string]time.Time)
+
+// BridgeRelay gets the attested transfers from the validators of other networks and
+// sends BridgeReceive contracts with the attestations
+func BridgeRelay(ctx context.Context, d *daemon) error {
+	bridgeHosts := conf.BridgeHosts()
+	if len(bridgeHosts) == 0 {
+		d.sleepTime = time.Minute
+		return nil
+	}
+	d.sleepTime = 10 * time.Second
+
+	localID := bridge.NetworkID()
+	if localID <= 0 {
+		return nil
+	}
+	for key, hosts := range bridgeHosts {
+		network := converter.StrToInt64(key)
+		route, err := bridge.GetNetwork(network)
+		if err != nil {
+			d.logger.WithFields(log.Fields{"type": consts.BridgeError, "error": err, "network": key}).Error("getting bridge network")
+			continue
+		}
+		if err = relayNetwork(ctx, d.logger, route, localID, strings.Fields(hosts)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type relayTransfer struct {
+	recipient    int64
+	amount       string
+	attestations []bridge.Attestation
+}
+
+func relayNetwork(ctx context.Context, logger *log.Entry, route *bridge.Network, localID int64, hosts []string) error {
+	fromID, err := model.GetLastBridgeTransferID(route.ID)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting last bridge transfer")
+		return err
+	}
+
+	transfers := make(map[int64]*relayTransfer)
+	for _, host := range hosts {
+		for _, item := range getBridgeTransfers(host, localID, fromID, logger) {
+			t, ok := transfers[item.ID]
+			if !ok {
+				t = &relayTransfer{recipient: item.Recipient, amount: item.Amount}
+				transfers[item.ID] = t
+			}
+			// the attestation of other data is ignored, BridgeReceive would reject it anyway
+			if t.recipient == item.Recipient && t.amount == item.Amount {
+				t.attestations = append(t.attestations, item.Attestation)
+			}
+		}
+	}
+
+	ids := make([]int64, 0, len(transfers))
+	for id := range transfers {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		t := transfers[id]
+		if route.CountValid(bridge.ForSign(route.ID, localID, id, t.recipient, t.amount), t.attestations) < route.Threshold {
+			// the next transfers can't be received before this one
+			break
+		}
+		key := converter.Int64ToStr(route.ID) + `,` + converter.Int64ToStr(id)
+		if last, ok := bridgeSubmitted[key]; ok && time.Since(last) < bridgeResubmitTimeout {
+			continue
+		}
+		attestations, err := json.Marshal(t.attestations)
+		if err != nil {
+			logger.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Error("marshalling attestations")
+			return err
+		}
+		params := url.Values{
+			`Network`:      {converter.Int64ToStr(route.ID)},
+			`TransferId`:   {converter.Int64ToStr(id)},
+			`Recipient`:    {converter.Int64ToStr(t.recipient)},
+			`Amount`:       {t.amount},
+			`Attestations`: {string(attestations)},
+		}
+		if _, err = contract.NodeTxContract(1, `@1BridgeReceive`, params); err != nil {
+			logger.WithFields(log.Fields{"type": consts.ContractError, "error": err, "network": route.ID, "transfer": id}).Error("sending BridgeReceive contract")
+			return nil
+		}
+		bridgeSubmitted[key] = time.Now()
+	}
+	for key, last := range bridgeSubmitted {
+		if time.Since(last) >= bridgeResubmitTimeout {
+			delete(bridgeSubmitted, key)
+		}
+	}
+	return nil
+}
+
+func getBridgeTransfers(host string, network, fromID int64, logger *log.Entry) []bridge.SignedTransfer {
+	peer, err := tcpserver.GetPeerInfo(getHostPort(host))
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.ConnectionError, "error": err, "host": host}).Debug("getting protocol of host")
+		return nil
+	}
+	if !peer.Supports(tcpserver.CapBridge) {
+		logger.WithFields(log.Fields{"type": consts.ProtocolError, "host": host, "version": peer.Version}).Debug("host doesn't support bridge requests")
+		return nil
+	}
+
+	conn, err := tcpserver.Dial(getHostPort(host))
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.ConnectionError, "error": err, "host": host}).Debug("dialing to host")
+		return nil
+	}
+	defer conn.Close()
+
+	type bridgeRequest struct {
+		Type    uint16
+		Network int64
+		FromID  int64
+	}
+	err = tcpserver.SendRequest(&bridgeRequest{Type: 11, Network: network, FromID: fromID}, conn)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.IOError, "error": err, "host": host}).Error("sending bridge request")
+		return nil
+	}
+
+	resp := &tcpserver.BridgeResponse{}
+	if err = tcpserver.ReadRequest(resp, conn); err != nil {
+		logger.WithFields(log.Fields{"type": consts.IOError, "error": err, "host": host}).Error("receiving bridge response")
+		return nil
+	}
+	conn.Release()
+	var transfers []bridge.SignedTransfer
+	if err = json.Unmarshal(resp.Data, &transfers); err != nil {
+		logger.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err, "host": host}).Error("unmarshalling bridge transfers")
+		return nil
+	}
+	return transfers
+}
@@ -0,0 +1,228 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package daemons
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/parser"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// exportBatch is the count of blocks which are read from database at once
+const exportBatch = 1000
+
+// checksumExt is the extension of the file with sha256 checksum of chain file
+const checksumExt = ".sha256"
+
+// ErrChecksumMismatch is returned if the chain file doesn't match its checksum
+var ErrChecksumMismatch = fmt.Errorf("checksum of chain file mismatch")
+
+// scanChainFile returns the last complete block id and the size of complete records of chain file
+func scanChainFile(r io.Reader) (lastID, size int64, err error) {
+	buf := bufio.NewReader(r)
+	word := make([]byte, WordSize)
+	for {
+		if _, err = io.ReadFull(buf, word); err != nil {
+			break
+		}
+		length := converter.BinToDec(word)
+		if length <= WordSize {
+			err = fmt.Errorf("wrong record size %d after block %d", length, lastID)
+			return
+		}
+		record := make([]byte, length+WordSize)
+		if _, err = io.ReadFull(buf, record); err != nil {
+			break
+		}
+		lastID = converter.BinToDec(record[:WordSize])
+		size += length + 2*WordSize
+	}
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		err = nil
+	}
+	return
+}
+
+func fileChecksum(fileName string) (string, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ExportChain appends blocks up to endBlockID (all blocks if it's 0) to the chain file.
+// If the file exists the export is continued after its last complete block.
+// The checksum of the file is written in sha256sum format to the file with .sha256 extension
+func ExportChain(ctx context.Context, fileName string, endBlockID int64) error {
+	logger := log.WithFields(log.Fields{"path": fileName})
+	file, err := os.OpenFile(fileName, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("opening chain file")
+		return err
+	}
+	defer file.Close()
+
+	lastID, size, err := scanChainFile(file)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("scanning chain file")
+		return err
+	}
+	// the incomplete record of interrupted export is dropped
+	if err = file.Truncate(size); err != nil {
+		logger.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("truncating chain file")
+		return err
+	}
+	if _, err = file.Seek(size, io.SeekStart); err != nil {
+		logger.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("seeking chain file")
+		return err
+	}
+	if lastID > 0 {
+		logger.WithFields(log.Fields{"block_id": lastID}).Info("continue export of chain")
+	}
+
+	for endBlockID == 0 || lastID < endBlockID {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		to := lastID + exportBatch
+		if endBlockID > 0 && to > endBlockID {
+			to = endBlockID
+		}
+		blocks, err := model.GetBlockchain(lastID, to)
+		if err != nil {
+			logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting blockchain")
+			return err
+		}
+		if len(blocks) == 0 {
+			break
+		}
+		for _, b := range blocks {
+			if _, err = file.Write(marshallFileBlock(blockData{ID: b.ID, Data: b.Data})); err != nil {
+				logger.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("writing block to file")
+				return err
+			}
+			lastID = b.ID
+		}
+		if err = file.Sync(); err != nil {
+			logger.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("syncing chain file")
+			return err
+		}
+	}
+
+	checksum, err := fileChecksum(fileName)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("calculating checksum")
+		return err
+	}
+	logger.WithFields(log.Fields{"block_id": lastID}).Info("chain is exported")
+	return ioutil.WriteFile(fileName+checksumExt, []byte(checksum+"  "+filepath.Base(fileName)+"\n"), 0600)
+}
+
+// VerifyChainFile compares the chain file with its checksum file if it exists
+func VerifyChainFile(fileName string) error {
+	data, err := ioutil.ReadFile(fileName + checksumExt)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	checksum, err := fileChecksum(fileName)
+	if err != nil {
+		return err
+	}
+	if fields := strings.Fields(string(data)); len(fields) == 0 || fields[0] != checksum {
+		return ErrChecksumMismatch
+	}
+	return nil
+}
+
+// ImportChain inserts the blocks of chain file which follow the last block of blockchain.
+// An interrupted import is continued by the next call
+func ImportChain(ctx context.Context, fileName string) error {
+	logger := log.WithFields(log.Fields{"path": fileName})
+	if err := VerifyChainFile(fileName); err != nil {
+		logger.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("verifying chain file")
+		return err
+	}
+	infoBlock := &model.InfoBlock{}
+	if _, err := infoBlock.Get(); err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting info block")
+		return err
+	}
+	file, err := os.Open(fileName)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("opening chain file")
+		return err
+	}
+	defer file.Close()
+
+	r := bufio.NewReader(file)
+	lastID := infoBlock.BlockID
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		block, err := readBlock(r, logger)
+		if err == io.EOF || (err == nil && block == nil) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if block.ID <= lastID {
+			continue
+		}
+		if block.ID != lastID+1 {
+			logger.WithFields(log.Fields{"type": consts.BlockError, "block_id": block.ID, "last_id": lastID}).Error("gap in chain file")
+			return fmt.Errorf("block %d is missing in chain file", lastID+1)
+		}
+		if err = parser.InsertBlockWOForks(block.Data); err != nil {
+			logger.WithFields(log.Fields{"type": consts.ParserError, "block_id": block.ID, "error": err}).Error("inserting block")
+			return err
+		}
+		lastID = block.ID
+	}
+	logger.WithFields(log.Fields{"block_id": lastID}).Info("chain is imported")
+	return nil
+}
@@ -0,0 +1,71 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package daemons
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/clock"
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const ntpTimeout = 5 * time.Second
+
+// maxClockDrift returns the allowed drift of the local clock
+func maxClockDrift() time.Duration {
+	return time.Duration(conf.Config.Clock.MaxDrift) * time.Millisecond
+}
+
+// clockDrifted returns true if the drift of local clock is too large for generating blocks
+func clockDrifted() bool {
+	return len(conf.Config.Clock.NTPServers) > 0 && clock.Exceeded(maxClockDrift())
+}
+
+// ClockChecker measures the drift of the local clock by NTP servers
+func ClockChecker(ctx context.Context, d *daemon) error {
+	d.sleepTime = time.Duration(conf.Config.Clock.CheckInterval) * time.Second
+	servers := strings.Fields(conf.Config.Clock.NTPServers)
+	if len(servers) == 0 {
+		d.sleepTime = time.Minute
+		return nil
+	}
+	drift, err := clock.Check(servers, ntpTimeout)
+	if err != nil {
+		d.logger.WithFields(log.Fields{"type": consts.ClockError, "error": err, "servers": servers}).Warning("checking clock drift")
+		// the next attempt is earlier, the servers could be unavailable for a short time
+		d.sleepTime = time.Minute
+		return nil
+	}
+	if clockDrifted() {
+		d.logger.WithFields(log.Fields{"type": consts.ClockError, "drift": drift.String(), "max_drift": maxClockDrift().String()}).
+			Error("local clock drifts too much, blocks are not generated until the clock is synchronized")
+	} else {
+		d.logger.WithFields(log.Fields{"drift": drift.String()}).Debug("clock drift")
+	}
+	return nil
+}
@@ -24,24 +24,17 @@ package daemons
 
 import (
 	"context"
-	"fmt"
+	"net"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/GenesisKernel/go-genesis/packages/conf"
 	"github.com/GenesisKernel/go-genesis/packages/consts"
-	"github.com/GenesisKernel/go-genesis/packages/converter"
-	"github.com/GenesisKernel/go-genesis/packages/statsd"
-	"github.com/GenesisKernel/go-genesis/packages/utils"
 
 	log "github.com/sirupsen/logrus"
 )
 
-var (
-	// MonitorDaemonCh is monitor daemon channel
-	MonitorDaemonCh = make(chan []string, 100)
-)
-
 type daemon struct {
 	goRoutineName string
 	sleepTime     time.Duration
@@ -57,6 +50,9 @@ var daemonsList = map[string]func(context.Context, *daemon) error{
 	"Confirmations":     Confirmations,
 	"Notificator":       Notificate,
 	"Scheduler":         Scheduler,
+	"BridgeRelay":       BridgeRelay,
+	"MessageSender":     MessageSender,
+	"ClockChecker":      ClockChecker,
 }
 
 var serverList = []string{
@@ -68,6 +64,9 @@ var serverList = []string{
 	"Confirmations",
 	"Notificator",
 	"Scheduler",
+	"BridgeRelay",
+	"MessageSender",
+	"ClockChecker",
 }
 
 var rollbackList = []string{
@@ -75,14 +74,8 @@ var rollbackList = []string{
 	"Confirmations",
 }
 
-func daemonLoop(ctx context.Context, goRoutineName string, handler func(context.Context, *daemon) error, retCh chan string) {
-	logger := log.WithFields(log.Fields{"daemon_name": goRoutineName})
-	defer func() {
-		if r := recover(); r != nil {
-			logger.WithFields(log.Fields{"type": consts.PanicRecoveredError, "error": r}).Error("panic in daemon")
-			panic(r)
-		}
-	}()
+func daemonLoop(ctx context.Context, sd *supervisedDaemon) {
+	logger := log.WithFields(log.Fields{"daemon_name": sd.status.Name})
 
 	err := WaitDB(ctx)
 	if err != nil {
@@ -90,29 +83,24 @@ func daemonLoop(ctx context.Context, goRoutineName string, handler func(context.
 	}
 
 	d := &daemon{
-		goRoutineName: goRoutineName,
+		goRoutineName: sd.status.Name,
 		sleepTime:     100 * time.Millisecond,
 		logger:        logger,
 	}
 
-	startTime := time.Now()
-	counterName := statsd.DaemonCounterName(goRoutineName)
-	handler(ctx, d)
-	statsd.Client.TimingDuration(counterName+statsd.Time, time.Now().Sub(startTime), 1.0)
+	sd.iterate(ctx, d)
 
 	for {
 		select {
 		case <-ctx.Done():
 			logger.Info("daemon done his work")
-			retCh <- goRoutineName
 			return
 
 		case <-time.After(d.sleepTime):
-			MonitorDaemonCh <- []string{d.goRoutineName, converter.Int64ToStr(time.Now().Unix())}
-			startTime := time.Now()
-			counterName := statsd.DaemonCounterName(goRoutineName)
-			handler(ctx, d)
-			statsd.Client.TimingDuration(counterName+statsd.Time, time.Now().Sub(startTime), 1.0)
+			if sd.isPaused() || inMaintenance(sd.status.Name) {
+				continue
+			}
+			sd.iterate(ctx, d)
 		}
 	}
 }
@@ -125,20 +113,9 @@ func StartDaemons() {
 
 	go WaitStopTime()
 
-	daemonsTable := make(map[string]string)
-	go func() {
-		for {
-			daemonNameAndTime := <-MonitorDaemonCh
-			daemonsTable[daemonNameAndTime[0]] = daemonNameAndTime[1]
-			if time.Now().Unix()%10 == 0 {
-				log.Debug("daemonsTable: %v\n", daemonsTable)
-			}
-		}
-	}()
-
-	ctx, cancel := context.WithCancel(context.Background())
-	utils.CancelFunc = cancel
-	utils.ReturnCh = make(chan string)
+	daemonSupervisor.mutex.Lock()
+	daemonSupervisor.ctx, daemonSupervisor.cancel = context.WithCancel(context.Background())
+	daemonSupervisor.mutex.Unlock()
 
 	daemonsToStart := serverList
 	if len(conf.Config.StartDaemons) > 0 {
@@ -152,9 +129,8 @@ func StartDaemons() {
 	for _, name := range daemonsToStart {
 		handler, ok := daemonsList[name]
 		if ok {
-			go daemonLoop(ctx, name, handler, utils.ReturnCh)
+			daemonSupervisor.start(name, handler)
 			log.WithFields(log.Fields{"daemon_name": name}).Info("started")
-			utils.DaemonsCount++
 			continue
 		}
 
@@ -162,9 +138,10 @@ func StartDaemons() {
 	}
 }
 
+// getHostPort adds the default port to the host if it doesn't have a port, IPv6 hosts may be without brackets
 func getHostPort(h string) string {
-	if strings.Contains(h, ":") {
+	if _, _, err := net.SplitHostPort(h); err == nil {
 		return h
 	}
-	return fmt.Sprintf("%s:%d", h, consts.DEFAULT_TCP_PORT)
+	return net.JoinHostPort(strings.Trim(h, "[]"), strconv.Itoa(consts.DEFAULT_TCP_PORT))
 }
@@ -24,8 +24,6 @@ package daemons
 
 import (
 	"context"
-	"net"
-	"strconv"
 	"time"
 
 	"github.com/GenesisKernel/go-genesis/packages/conf"
@@ -111,11 +109,13 @@ func Confirmations(ctx context.Context, d *daemon) error {
 		}
 
 		ch := make(chan string)
+		limiter := newHostLimiter()
 		for i := 0; i < len(hosts); i++ {
-			// NOTE: host should not use default port number
-			host := hosts[i] + ":" + strconv.Itoa(consts.DEFAULT_TCP_PORT)
+			host := getHostPort(hosts[i])
 			d.logger.WithFields(log.Fields{"host": host, "block_id": blockID}).Debug("checking block id confirmed at node")
 			go func() {
+				limiter.acquire()
+				defer limiter.release()
 				IsReachable(host, blockID, ch, d.logger)
 			}()
 		}
@@ -162,16 +162,13 @@ func Confirmations(ctx context.Context, d *daemon) error {
 }
 
 func checkConf(host string, blockID int64, logger *log.Entry) string {
-	conn, err := net.DialTimeout("tcp", host, 5*time.Second)
+	conn, err := tcpserver.Dial(host)
 	if err != nil {
 		logger.WithFields(log.Fields{"type": consts.ConnectionError, "error": err, "host": host, "block_id": blockID}).Debug("dialing to host")
 		return "0"
 	}
 	defer conn.Close()
 
-	conn.SetReadDeadline(time.Now().Add(consts.READ_TIMEOUT * time.Second))
-	conn.SetWriteDeadline(time.Now().Add(consts.WRITE_TIMEOUT * time.Second))
-
 	type confRequest struct {
 		Type    uint16
 		BlockID uint32
@@ -188,6 +185,7 @@ func checkConf(host string, blockID int64, logger *log.Entry) string {
 		logger.WithFields(log.Fields{"type": consts.IOError, "error": err, "host": host, "block_id": blockID}).Error("receiving confirmation response")
 		return "0"
 	}
+	conn.Release()
 	return string(converter.BinToHex(resp.Hash))
 }
 
@@ -122,7 +122,7 @@ func readBlock(r io.Reader, logger *log.Entry) (*blockData, error) {
 	}
 
 	dataBinary := make([]byte, size+WordSize)
-	if _, err = r.Read(dataBinary); err != nil {
+	if _, err = io.ReadFull(r, dataBinary); err != nil {
 		logger.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("reading block from file")
 		return nil, utils.ErrInfo(err)
 	}
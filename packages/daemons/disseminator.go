@@ -33,7 +33,7 @@ import (
 	"github.com/GenesisKernel/go-genesis/packages/consts"
 	"github.com/GenesisKernel/go-genesis/packages/converter"
 	"github.com/GenesisKernel/go-genesis/packages/model"
-	"github.com/GenesisKernel/go-genesis/packages/utils"
+	"github.com/GenesisKernel/go-genesis/packages/tcpserver"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -237,12 +237,15 @@ func MarshallTrHash(tr model.Transaction) []byte {
 func sendPacketToAll(reqType int, buf []byte, respHand func(resp []byte, w io.Writer, logger *log.Entry) error, logger *log.Entry) error {
 
 	hosts := syspar.GetRemoteHosts()
+	limiter := newHostLimiter()
 	var wg sync.WaitGroup
 
 	for _, host := range hosts {
 		wg.Add(1)
 		go func(h string) {
+			limiter.acquire()
 			sendDRequest(h, reqType, buf, respHand, logger)
+			limiter.release()
 			wg.Done()
 		}(getHostPort(host))
 	}
@@ -259,7 +262,7 @@ data  len bytes
 */
 
 func sendDRequest(host string, reqType int, buf []byte, respHandler func([]byte, io.Writer, *log.Entry) error, logger *log.Entry) error {
-	conn, err := utils.TCPConn(host)
+	conn, err := tcpserver.Dial(host)
 	if err != nil {
 		logger.WithFields(log.Fields{"type": consts.ConnectionError, "error": err, "host": host}).Debug("tcp connection to host")
 		return err
@@ -300,6 +303,7 @@ func sendDRequest(host string, reqType int, buf []byte, respHandler func([]byte,
 			} else {
 				logger.WithFields(log.Fields{"type": consts.IOError, "error": err, "host": host}).Error("reading data size")
 			}
+			return err
 		}
 
 		respSize := converter.BinToDec(buf)
@@ -320,5 +324,6 @@ func sendDRequest(host string, reqType int, buf []byte, respHandler func([]byte,
 			return err
 		}
 	}
+	conn.Release()
 	return nil
 }
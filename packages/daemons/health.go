@@ -0,0 +1,181 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package daemons
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/clock"
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/system"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type healthCheck struct {
+	OK    bool        `json:"ok"`
+	Error string      `json:"error,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+type healthResult struct {
+	OK     bool                    `json:"ok"`
+	Checks map[string]*healthCheck `json:"checks"`
+}
+
+func (h *healthResult) add(name string, check *healthCheck) {
+	h.Checks[name] = check
+	if !check.OK {
+		h.OK = false
+	}
+}
+
+func writeHealth(w http.ResponseWriter, result *healthResult) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if result.OK {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Error("marshalling health result")
+	}
+}
+
+func checkDBHealth() *healthCheck {
+	if err := model.PingDB(); err != nil {
+		return &healthCheck{Error: err.Error()}
+	}
+	return &healthCheck{OK: true}
+}
+
+func checkSyncHealth() *healthCheck {
+	infoBlock := &model.InfoBlock{}
+	found, err := infoBlock.Get()
+	if err != nil {
+		return &healthCheck{Error: err.Error()}
+	}
+	if !found {
+		return &healthCheck{Error: "info block not found"}
+	}
+	return syncHealth(infoBlock.BlockID, NetworkMaxBlockID(), daemonSupervisor.has("BlocksCollection"),
+		conf.Health().MaxBlocksBehind)
+}
+
+// syncHealth compares the local chain with the network. If the blocks aren't collected
+// the network height is never known, so the node is ready by its own chain
+func syncHealth(blockID, networkBlockID int64, collecting bool, maxBehind int64) *healthCheck {
+	if !collecting {
+		return &healthCheck{OK: true}
+	}
+	if networkBlockID < 0 {
+		return &healthCheck{Error: "network height is unknown"}
+	}
+	behind := networkBlockID - blockID
+	if behind < 0 {
+		behind = 0
+	}
+	check := &healthCheck{OK: behind <= maxBehind, Value: behind}
+	if !check.OK {
+		check.Error = "node is not synchronized"
+	}
+	return check
+}
+
+func checkDaemonsHealth() *healthCheck {
+	var dead []string
+	timeout := time.Now().Unix() - conf.Health().DaemonTimeout
+	for name, lastTime := range DaemonsActivity() {
+		if lastTime < timeout {
+			dead = append(dead, name)
+		}
+	}
+	if len(dead) > 0 {
+		return &healthCheck{Error: "daemons are not responding", Value: dead}
+	}
+	return &healthCheck{OK: true}
+}
+
+// DaemonsAlive reports whether all running daemons have been active within Health.DaemonTimeout
+func DaemonsAlive() bool {
+	return checkDaemonsHealth().OK
+}
+
+func checkDiskHealth() *healthCheck {
+	free, err := system.DiskFree(conf.Config.DataDir)
+	if err != nil {
+		return &healthCheck{Error: err.Error()}
+	}
+	free >>= 20
+	check := &healthCheck{OK: int64(free) >= conf.Health().MinFreeDisk, Value: free}
+	if !check.OK {
+		check.Error = "low disk space"
+	}
+	return check
+}
+
+func checkClockHealth() *healthCheck {
+	drift, ok := clock.Drift()
+	if !ok {
+		return &healthCheck{OK: true}
+	}
+	check := &healthCheck{OK: !clockDrifted(), Value: drift.String()}
+	if !check.OK {
+		check.Error = "local clock drifts too much"
+	}
+	return check
+}
+
+// Healthz reports whether the node process is alive and has the database connection
+func Healthz(w http.ResponseWriter, r *http.Request) {
+	result := &healthResult{OK: true, Checks: make(map[string]*healthCheck)}
+	if conf.Installed {
+		result.add("db", checkDBHealth())
+	}
+	writeHealth(w, result)
+}
+
+// Readyz reports whether the node is synchronized and can serve the requests
+func Readyz(w http.ResponseWriter, r *http.Request) {
+	result := &healthResult{OK: true, Checks: make(map[string]*healthCheck)}
+	if !conf.Installed {
+		result.add("installed", &healthCheck{Error: "node is not installed"})
+		writeHealth(w, result)
+		return
+	}
+	db := checkDBHealth()
+	result.add("db", db)
+	if db.OK {
+		result.add("sync", checkSyncHealth())
+	}
+	result.add("daemons", checkDaemonsHealth())
+	result.add("disk", checkDiskHealth())
+	if len(conf.Config.Clock.NTPServers) > 0 {
+		result.add("clock", checkClockHealth())
+	}
+	writeHealth(w, result)
+}
@@ -0,0 +1,46 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package daemons
+
+import "testing"
+
+func TestSyncHealth(t *testing.T) {
+	cases := []struct {
+		blockID, networkBlockID int64
+		collecting              bool
+		ok                      bool
+	}{
+		{10, -1, false, true},
+		{10, -1, true, false},
+		{10, 0, true, true},
+		{10, 15, true, true},
+		{10, 30, true, false},
+		{30, 10, true, true},
+	}
+	for _, c := range cases {
+		check := syncHealth(c.blockID, c.networkBlockID, c.collecting, 10)
+		if check.OK != c.ok {
+			t.Errorf("%+v: expected ok %v, got %+v", c, c.ok, check)
+		}
+	}
+}
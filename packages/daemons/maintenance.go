@@ -0,0 +1,107 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package daemons
+
+import (
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// writerDaemons change the database, their iterations are suspended in the maintenance mode
+var writerDaemons = map[string]bool{
+	"BlocksCollection":  true,
+	"BlockGenerator":    true,
+	"QueueParserTx":     true,
+	"QueueParserBlocks": true,
+	"Confirmations":     true,
+	"Scheduler":         true,
+	"BridgeRelay":       true,
+	"MessageSender":     true,
+}
+
+// MaintenanceStatus is the state of the maintenance mode of the node
+type MaintenanceStatus struct {
+	Enabled bool   `json:"enabled"`
+	Reason  string `json:"reason,omitempty"`
+	Since   int64  `json:"since,omitempty"`
+}
+
+var maintenance = struct {
+	sync.RWMutex
+	status MaintenanceStatus
+}{}
+
+// Maintenance returns the state of the maintenance mode
+func Maintenance() MaintenanceStatus {
+	maintenance.RLock()
+	defer maintenance.RUnlock()
+	return maintenance.status
+}
+
+func inMaintenance(name string) bool {
+	return writerDaemons[name] && Maintenance().Enabled
+}
+
+// SetMaintenance turns the maintenance mode on or off. The block production and other writes
+// of daemons are paused in the maintenance mode, the function waits for the end of their current iterations
+func SetMaintenance(enabled bool, reason string) error {
+	maintenance.Lock()
+	if enabled {
+		if !maintenance.status.Enabled {
+			maintenance.status.Since = time.Now().Unix()
+		}
+		maintenance.status.Enabled, maintenance.status.Reason = true, reason
+	} else {
+		maintenance.status = MaintenanceStatus{}
+	}
+	maintenance.Unlock()
+	log.WithFields(log.Fields{"enabled": enabled, "reason": reason}).Warning("maintenance mode changed")
+	if !enabled {
+		return nil
+	}
+	for deadline := time.Now().Add(restartTimeout); writersBusy(); time.Sleep(100 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			return ErrDaemonBusy
+		}
+	}
+	return nil
+}
+
+func writersBusy() bool {
+	daemonSupervisor.mutex.RLock()
+	defer daemonSupervisor.mutex.RUnlock()
+	for name, sd := range daemonSupervisor.daemons {
+		if !writerDaemons[name] {
+			continue
+		}
+		sd.mutex.Lock()
+		busy := sd.busy
+		sd.mutex.Unlock()
+		if busy {
+			return true
+		}
+	}
+	return false
+}
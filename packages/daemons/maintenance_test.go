@@ -0,0 +1,48 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package daemons
+
+import (
+	"testing"
+)
+
+func TestMaintenance(t *testing.T) {
+	if err := SetMaintenance(true, `vacuum`); err != nil {
+		t.Fatal(err)
+	}
+	status := Maintenance()
+	if !status.Enabled || status.Reason != `vacuum` || status.Since == 0 {
+		t.Errorf(`wrong status %+v`, status)
+	}
+	if !inMaintenance(`BlockGenerator`) || inMaintenance(`Disseminator`) {
+		t.Error(`only writer daemons must be suspended`)
+	}
+	daemonSupervisor.daemons[`QueueParserTx`] = &supervisedDaemon{busy: true}
+	defer delete(daemonSupervisor.daemons, `QueueParserTx`)
+	if !writersBusy() {
+		t.Error(`busy writer is not detected`)
+	}
+	if err := SetMaintenance(false, ``); err != nil || Maintenance().Enabled || inMaintenance(`BlockGenerator`) {
+		t.Error(`maintenance mode is not turned off`)
+	}
+}
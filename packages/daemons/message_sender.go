@@ -0,0 +1,129 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package daemons
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/messages"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// messagesBatch is the maximum count of messages of the ecosystem which are sent per iteration
+	messagesBatch = 100
+	// messageRetryDelay is the delay before the second attempt, it is doubled for each next attempt
+	messageRetryDelay = time.Minute
+	maxRetryShift     = 10
+	maxMessageError   = 1024
+)
+
+// MessageSender delivers the pending email and SMS messages of VDE ecosystems
+// and writes the delivery status back to the messages table
+func MessageSender(ctx context.Context, d *daemon) error {
+	if !messages.Enabled() {
+		d.sleepTime = time.Minute
+		return nil
+	}
+	d.sleepTime = 5 * time.Second
+
+	stateIDs, err := model.GetAllSystemStatesIDs()
+	if err != nil {
+		d.logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("get all system states ids")
+		return err
+	}
+	for _, stateID := range stateIDs {
+		if !model.IsTable(fmt.Sprintf("%d_vde_messages", stateID)) {
+			continue
+		}
+		m := &model.Message{}
+		m.SetTablePrefix(fmt.Sprintf("%d_vde", stateID))
+		list, err := m.GetPending(time.Now().Unix(), messagesBatch)
+		if err != nil {
+			d.logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting pending messages")
+			return err
+		}
+		for _, item := range list {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err = deliverMessage(item, d.logger); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// deliverMessage makes the attempt of the delivery and updates the status of the message
+func deliverMessage(m *model.Message, logger *log.Entry) error {
+	m.Attempts++
+	now := time.Now()
+	err := sendMessage(m)
+	switch {
+	case err == nil:
+		m.Status, m.SentAt, m.Error = model.MessageSent, now.Unix(), ``
+	case messages.IsPermanent(err) || m.Attempts >= conf.Messages().MaxAttempts:
+		m.Status, m.Error = model.MessageFailed, err.Error()
+	default:
+		shift := m.Attempts - 1
+		if shift > maxRetryShift {
+			shift = maxRetryShift
+		}
+		m.NextAttempt, m.Error = now.Add(messageRetryDelay<<uint(shift)).Unix(), err.Error()
+	}
+	if len(m.Error) > maxMessageError {
+		m.Error = m.Error[:maxMessageError]
+	}
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.NetworkError, "error": err, "table": m.TableName(),
+			"id": m.ID, "attempts": m.Attempts}).Warning("delivering message")
+	}
+	if err = m.SetStatus(); err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("updating status of message")
+		return err
+	}
+	return nil
+}
+
+func sendMessage(m *model.Message) error {
+	sender, ok := messages.GetSender(m.Channel)
+	if !ok {
+		return fmt.Errorf("channel %s is not enabled", m.Channel)
+	}
+	subject, err := messages.Render(m.Subject, m.Params)
+	if err != nil {
+		return err
+	}
+	body, err := messages.Render(m.Body, m.Params)
+	if err != nil {
+		return err
+	}
+	return sender.Send(m.Recipient, subject, body)
+}
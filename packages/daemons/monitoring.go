@@ -30,6 +30,7 @@ import (
 	"github.com/GenesisKernel/go-genesis/packages/config/syspar"
 	"github.com/GenesisKernel/go-genesis/packages/consts"
 	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/diagnose"
 	"github.com/GenesisKernel/go-genesis/packages/model"
 
 	log "github.com/sirupsen/logrus"
@@ -76,6 +77,13 @@ func Monitoring(w http.ResponseWriter, r *http.Request) {
 	}
 	addKey(&buf, "transactions_count", trCount)
 
+	lanes := diagnose.GetLaneStats()
+	addKey(&buf, "lane_system_tx", lanes.SystemTx)
+	addKey(&buf, "lane_other_tx", lanes.OtherTx)
+	addKey(&buf, "lane_deferred_tx", lanes.DeferredTx)
+	addKey(&buf, "lane_system_usage", lanes.SystemUsage)
+	addKey(&buf, "lane_other_usage", lanes.OtherUsage)
+
 	w.Write(buf.Bytes())
 }
 
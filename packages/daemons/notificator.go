@@ -31,5 +31,6 @@ import (
 // Notificate is sending notifications
 func Notificate(ctx context.Context, d *daemon) error {
 	notificator.SendNotifications()
+	notificator.SendTxStatuses()
 	return nil
 }
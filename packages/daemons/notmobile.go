@@ -30,43 +30,14 @@ import (
 	"github.com/GenesisKernel/go-genesis/packages/conf"
 	"github.com/GenesisKernel/go-genesis/packages/consts"
 	"github.com/GenesisKernel/go-genesis/packages/model"
-	"github.com/GenesisKernel/go-genesis/packages/utils"
+	"github.com/GenesisKernel/go-genesis/packages/service"
 
 	log "github.com/sirupsen/logrus"
 )
 
-/*
-#include <stdio.h>
-#include <signal.h>
-
-extern void go_callback_int();
-static inline void SigBreak_Handler(int n_signal){
-    printf("closed\n");
-	go_callback_int();
-}
-static inline void waitSig() {
-    #if (WIN32 || WIN64)
-    signal(SIGBREAK, &SigBreak_Handler);
-    signal(SIGINT, &SigBreak_Handler);
-    #endif
-}
-*/
-import (
-	"C"
-)
-
-//export go_callback_int
-func go_callback_int() {
-	SigChan <- syscall.Signal(1)
-}
-
 // SigChan is a channel
 var SigChan chan os.Signal
 
-func waitSig() {
-	C.waitSig()
-}
-
 // WaitForSignals waits for Interrupt os.Kill signals
 func WaitForSignals() {
 	SigChan = make(chan os.Signal, 1)
@@ -76,15 +47,8 @@ func WaitForSignals() {
 		signal.Notify(SigChan, os.Interrupt, os.Kill, Term)
 		<-SigChan
 
-		if utils.CancelFunc != nil {
-			utils.CancelFunc()
-			for i := 0; i < utils.DaemonsCount; i++ {
-				name := <-utils.ReturnCh
-				log.WithFields(log.Fields{"daemon_name": name}).Debug("daemon stopped")
-			}
-
-			log.Debug("Daemons killed")
-		}
+		service.Notify(service.NotifyStopping)
+		StopAllDaemons()
 
 		if model.DBConn != nil {
 			err := model.GormClose()
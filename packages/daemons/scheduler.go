@@ -44,7 +44,7 @@ func loadContractTasks() error {
 
 	for _, stateID := range stateIDs {
 		if !model.IsTable(fmt.Sprintf("%d_vde_cron", stateID)) {
-			return nil
+			continue
 		}
 
 		c := model.Cron{}
@@ -59,9 +59,7 @@ func loadContractTasks() error {
 			err = scheduler.UpdateTask(&scheduler.Task{
 				ID:       cronTask.UID(),
 				CronSpec: cronTask.Cron,
-				Handler: &contract.ContractHandler{
-					Contract: cronTask.Contract,
-				},
+				Handler:  contract.NewTaskHandler(cronTask, stateID),
 			})
 			if err != nil {
 				return err
@@ -0,0 +1,56 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package daemons
+
+import (
+	"syscall"
+)
+
+/*
+#include <stdio.h>
+#include <signal.h>
+
+extern void go_callback_int();
+static inline void SigBreak_Handler(int n_signal){
+    printf("closed\n");
+	go_callback_int();
+}
+static inline void waitSig() {
+    #if (WIN32 || WIN64)
+    signal(SIGBREAK, &SigBreak_Handler);
+    signal(SIGINT, &SigBreak_Handler);
+    #endif
+}
+*/
+import (
+	"C"
+)
+
+//export go_callback_int
+func go_callback_int() {
+	SigChan <- syscall.Signal(1)
+}
+
+func waitSig() {
+	C.waitSig()
+}
@@ -29,7 +29,6 @@ import (
 
 	"github.com/GenesisKernel/go-genesis/packages/consts"
 	"github.com/GenesisKernel/go-genesis/packages/model"
-	"github.com/GenesisKernel/go-genesis/packages/utils"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -54,11 +53,7 @@ func WaitStopTime() {
 			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("selecting stop_time from StopDaemons")
 		}
 		if dExists > 0 {
-			utils.CancelFunc()
-			for i := 0; i < utils.DaemonsCount; i++ {
-				name := <-utils.ReturnCh
-				log.WithFields(log.Fields{"daemon_name": name}).Debug("daemon stopped")
-			}
+			StopAllDaemons()
 
 			err := model.GormClose()
 			if err != nil {
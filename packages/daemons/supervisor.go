@@ -0,0 +1,326 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package daemons
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/statsd"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// restartTimeout is the time for waiting the end of the current iteration of the daemon
+	restartTimeout = 30 * time.Second
+	// panicBackoffMin and panicBackoffMax limit the delay of restarting the daemon after panic.
+	// The delay is doubled while the daemon panics sooner than panicBackoffMax after the restart
+	panicBackoffMin = time.Second
+	panicBackoffMax = time.Minute
+)
+
+var (
+	// ErrUnknownDaemon is returned for names which are not started by the supervisor
+	ErrUnknownDaemon = errors.New("Unknown daemon")
+	// ErrDaemonBusy is returned if the daemon has not finished its iteration in time
+	ErrDaemonBusy = errors.New("Daemon is busy")
+)
+
+// DaemonStatus is the state of the supervised daemon
+type DaemonStatus struct {
+	Name       string `json:"name"`
+	Running    bool   `json:"running"`
+	Paused     bool   `json:"paused"`
+	LastRun    int64  `json:"last_run"`
+	RunCount   int64  `json:"run_count"`
+	ErrorCount int64  `json:"error_count"`
+	LastError  string `json:"last_error,omitempty"`
+	ErrorTime  int64  `json:"error_time,omitempty"`
+	Restarts   int64  `json:"restarts"`
+}
+
+type supervisedDaemon struct {
+	mutex   sync.Mutex
+	status  DaemonStatus
+	handler func(context.Context, *daemon) error
+	cancel  context.CancelFunc
+	done    chan struct{}
+	busy    bool // the handler is running
+	restart bool // the daemon is being restarted
+}
+
+type supervisor struct {
+	mutex   sync.RWMutex
+	ctx     context.Context
+	cancel  context.CancelFunc
+	daemons map[string]*supervisedDaemon
+	wg      sync.WaitGroup
+	loop    func(context.Context, *supervisedDaemon) // daemonLoop if nil
+}
+
+var daemonSupervisor = &supervisor{daemons: make(map[string]*supervisedDaemon)}
+
+func (sd *supervisedDaemon) setError(err error) {
+	sd.mutex.Lock()
+	sd.status.ErrorCount++
+	sd.status.LastError = err.Error()
+	sd.status.ErrorTime = time.Now().Unix()
+	sd.mutex.Unlock()
+}
+
+func (sd *supervisedDaemon) isPaused() bool {
+	sd.mutex.Lock()
+	defer sd.mutex.Unlock()
+	return sd.status.Paused
+}
+
+// iterate calls the handler of the daemon once and saves the result
+func (sd *supervisedDaemon) iterate(ctx context.Context, d *daemon) {
+	sd.mutex.Lock()
+	sd.busy = true
+	sd.mutex.Unlock()
+	defer func() {
+		sd.mutex.Lock()
+		sd.busy = false
+		sd.mutex.Unlock()
+	}()
+	startTime := time.Now()
+	counterName := statsd.DaemonCounterName(d.goRoutineName)
+	err := sd.handler(ctx, d)
+	statsd.Client.TimingDuration(counterName+statsd.Time, time.Now().Sub(startTime), 1.0)
+
+	sd.mutex.Lock()
+	sd.status.LastRun = time.Now().Unix()
+	sd.status.RunCount++
+	sd.mutex.Unlock()
+	if err != nil && ctx.Err() == nil {
+		sd.setError(err)
+	}
+}
+
+func (s *supervisor) start(name string, handler func(context.Context, *daemon) error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	sd := &supervisedDaemon{status: DaemonStatus{Name: name, LastRun: time.Now().Unix()}, handler: handler}
+	s.daemons[name] = sd
+	s.run(sd)
+}
+
+// run starts the goroutine of the daemon, the supervisor must be locked
+func (s *supervisor) run(sd *supervisedDaemon) {
+	ctx, cancel := context.WithCancel(s.ctx)
+	sd.mutex.Lock()
+	sd.cancel = cancel
+	sd.done = make(chan struct{})
+	sd.status.Running = true
+	done := sd.done
+	sd.mutex.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer func() {
+			sd.mutex.Lock()
+			sd.status.Running = false
+			sd.mutex.Unlock()
+			close(done)
+			s.wg.Done()
+		}()
+		backoff := panicBackoffMin
+		for {
+			started := time.Now()
+			if !s.runLoop(ctx, sd) {
+				return
+			}
+			if time.Since(started) > panicBackoffMax {
+				backoff = panicBackoffMin
+			}
+			// the daemon isn't running until the restart, so the node is unhealthy
+			sd.mutex.Lock()
+			sd.status.Running = false
+			sd.mutex.Unlock()
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			if backoff *= 2; backoff > panicBackoffMax {
+				backoff = panicBackoffMax
+			}
+			sd.mutex.Lock()
+			sd.status.Running = true
+			sd.status.Restarts++
+			sd.status.LastRun = time.Now().Unix()
+			sd.mutex.Unlock()
+			log.WithFields(log.Fields{"daemon_name": sd.status.Name}).Info("daemon restarted after panic")
+		}
+	}()
+}
+
+// runLoop runs the daemon until the context is done, it returns true if the daemon has panicked
+func (s *supervisor) runLoop(ctx context.Context, sd *supervisedDaemon) (panicked bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.WithFields(log.Fields{"type": consts.PanicRecoveredError, "error": r, "daemon_name": sd.status.Name}).Error("panic in daemon")
+			sd.setError(fmt.Errorf("panic: %v", r))
+			panicked = true
+		}
+	}()
+	loop := s.loop
+	if loop == nil {
+		loop = daemonLoop
+	}
+	loop(ctx, sd)
+	return false
+}
+
+func (s *supervisor) get(name string) (*supervisedDaemon, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	sd, ok := s.daemons[name]
+	if !ok {
+		return nil, ErrUnknownDaemon
+	}
+	return sd, nil
+}
+
+// has returns true if the daemon is supervised
+func (s *supervisor) has(name string) bool {
+	_, err := s.get(name)
+	return err == nil
+}
+
+// DaemonsStatus returns the states of all supervised daemons
+func DaemonsStatus() []DaemonStatus {
+	daemonSupervisor.mutex.RLock()
+	list := make([]DaemonStatus, 0, len(daemonSupervisor.daemons))
+	for _, sd := range daemonSupervisor.daemons {
+		sd.mutex.Lock()
+		list = append(list, sd.status)
+		sd.mutex.Unlock()
+	}
+	daemonSupervisor.mutex.RUnlock()
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// DaemonsActivity returns the unix time of the last iteration of each not paused daemon,
+// it is 0 for the daemon which is waiting for the restart after panic
+func DaemonsActivity() map[string]int64 {
+	activity := make(map[string]int64)
+	for _, status := range DaemonsStatus() {
+		if status.Paused || inMaintenance(status.Name) {
+			continue
+		}
+		if status.Running {
+			activity[status.Name] = status.LastRun
+		} else {
+			activity[status.Name] = 0
+		}
+	}
+	return activity
+}
+
+// PauseDaemon suspends the iterations of the daemon
+func PauseDaemon(name string, pause bool) error {
+	sd, err := daemonSupervisor.get(name)
+	if err != nil {
+		return err
+	}
+	sd.mutex.Lock()
+	sd.status.Paused = pause
+	if !pause {
+		sd.status.LastRun = time.Now().Unix()
+	}
+	sd.mutex.Unlock()
+	log.WithFields(log.Fields{"daemon_name": name, "paused": pause}).Info("daemon pause changed")
+	return nil
+}
+
+// RestartDaemon stops the goroutine of the daemon and starts it again. The supervisor isn't locked
+// while the daemon is stopping, so the statuses of daemons are available during the restart
+func RestartDaemon(name string) error {
+	sd, err := daemonSupervisor.get(name)
+	if err != nil {
+		return err
+	}
+	daemonSupervisor.mutex.RLock()
+	err = daemonSupervisor.ctx.Err()
+	daemonSupervisor.mutex.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	sd.mutex.Lock()
+	if sd.restart {
+		sd.mutex.Unlock()
+		return ErrDaemonBusy
+	}
+	sd.restart = true
+	cancel, done := sd.cancel, sd.done
+	sd.mutex.Unlock()
+	defer func() {
+		sd.mutex.Lock()
+		sd.restart = false
+		sd.mutex.Unlock()
+	}()
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(restartTimeout):
+		log.WithFields(log.Fields{"type": consts.JustWaiting, "daemon_name": name}).Error("daemon has not stopped")
+		return ErrDaemonBusy
+	}
+
+	daemonSupervisor.mutex.Lock()
+	defer daemonSupervisor.mutex.Unlock()
+	if daemonSupervisor.ctx.Err() != nil {
+		return daemonSupervisor.ctx.Err()
+	}
+	sd.mutex.Lock()
+	sd.status.Restarts++
+	sd.status.Paused = false
+	sd.status.LastRun = time.Now().Unix()
+	sd.mutex.Unlock()
+	daemonSupervisor.run(sd)
+	log.WithFields(log.Fields{"daemon_name": name}).Info("daemon restarted")
+	return nil
+}
+
+// StopAllDaemons cancels all daemons and waits for them
+func StopAllDaemons() {
+	daemonSupervisor.mutex.Lock()
+	cancel := daemonSupervisor.cancel
+	daemonSupervisor.mutex.Unlock()
+	if cancel == nil {
+		return
+	}
+	cancel()
+	daemonSupervisor.wg.Wait()
+	log.Debug("Daemons killed")
+}
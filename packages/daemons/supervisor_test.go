@@ -0,0 +1,113 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package daemons
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPanickedDaemonResumes(t *testing.T) {
+	resumed := make(chan bool)
+	var calls int
+	s := &supervisor{daemons: make(map[string]*supervisedDaemon)}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.loop = func(ctx context.Context, sd *supervisedDaemon) {
+		if calls++; calls == 1 {
+			panic("test panic")
+		}
+		close(resumed)
+		<-ctx.Done()
+	}
+	s.start(`Panicking`, nil)
+	sd := s.daemons[`Panicking`]
+
+	time.Sleep(panicBackoffMin / 2)
+	sd.mutex.Lock()
+	status := sd.status
+	sd.mutex.Unlock()
+	if status.Running || status.ErrorCount != 1 || status.LastError != `panic: test panic` {
+		t.Errorf("daemon must be down after panic: %+v", status)
+	}
+
+	select {
+	case <-resumed:
+	case <-time.After(2 * panicBackoffMin):
+		t.Fatal("daemon has not been restarted")
+	}
+	sd.mutex.Lock()
+	status = sd.status
+	sd.mutex.Unlock()
+	if !status.Running || status.Restarts != 1 {
+		t.Errorf("daemon must be running after restart: %+v", status)
+	}
+	s.cancel()
+	s.wg.Wait()
+}
+
+func TestRestartDaemonDoesNotBlockStatus(t *testing.T) {
+	old := daemonSupervisor
+	defer func() { daemonSupervisor = old }()
+
+	stopping := make(chan struct{})
+	s := &supervisor{daemons: make(map[string]*supervisedDaemon)}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	var calls int
+	s.loop = func(ctx context.Context, sd *supervisedDaemon) {
+		<-ctx.Done()
+		if calls++; calls == 1 {
+			// the first iteration finishes slowly after the cancel
+			close(stopping)
+			time.Sleep(200 * time.Millisecond)
+		}
+	}
+	daemonSupervisor = s
+	s.start(`Slow`, nil)
+
+	restarted := make(chan error)
+	go func() { restarted <- RestartDaemon(`Slow`) }()
+	<-stopping
+
+	listed := make(chan []DaemonStatus)
+	go func() { listed <- DaemonsStatus() }()
+	select {
+	case list := <-listed:
+		if len(list) != 1 || list[0].Name != `Slow` {
+			t.Errorf("wrong status %+v", list)
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Error("status is blocked by restart")
+	}
+	if err := RestartDaemon(`Slow`); err != ErrDaemonBusy {
+		t.Errorf("expected ErrDaemonBusy for concurrent restart, got %v", err)
+	}
+	if err := <-restarted; err != nil {
+		t.Fatal(err)
+	}
+	if status := DaemonsStatus()[0]; !status.Running || status.Restarts != 1 {
+		t.Errorf("daemon must be running after restart: %+v", status)
+	}
+	s.cancel()
+	s.wg.Wait()
+}
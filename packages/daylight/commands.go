@@ -0,0 +1,674 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package daylight
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/apps"
+	"github.com/GenesisKernel/go-genesis/packages/apps/manifest"
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+	"github.com/GenesisKernel/go-genesis/packages/config/syspar"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/crypto"
+	"github.com/GenesisKernel/go-genesis/packages/daemons"
+	"github.com/GenesisKernel/go-genesis/packages/install"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/nodecert"
+	"github.com/GenesisKernel/go-genesis/packages/parser"
+	"github.com/GenesisKernel/go-genesis/packages/smart"
+	"github.com/GenesisKernel/go-genesis/packages/tcpserver"
+
+	toml "github.com/BurntSushi/toml"
+	flags "github.com/jessevdk/go-flags"
+	log "github.com/sirupsen/logrus"
+)
+
+// errCommandFailed is returned by commands which have already reported the problem
+var errCommandFailed = fmt.Errorf("command failed")
+
+// setFlag passes the value of command option to the global flag
+func setFlag(name, value string) {
+	if err := flag.Set(name, value); err != nil {
+		log.WithFields(log.Fields{"type": consts.ConfigError, "flag": name, "error": err}).Fatal("setting flag")
+	}
+}
+
+// initDB connects to the database of node
+func initDB() error {
+	dbCfg := conf.Config.DB
+	if err := model.GormInit(dbCfg.Host, dbCfg.Port, dbCfg.User, dbCfg.Password, dbCfg.Name); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("can't init gorm")
+		return err
+	}
+	return nil
+}
+
+// initChain connects to the database and loads system parameters and contracts
+func initChain() error {
+	if err := initDB(); err != nil {
+		return err
+	}
+	if err := syspar.SysUpdate(nil); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("can't read system parameters")
+		return err
+	}
+	return smart.LoadContracts(nil)
+}
+
+func printJSON(v interface{}) error {
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
+}
+
+type initCommand struct {
+	Config     bool   `long:"config" description:"write config parameters to file"`
+	Database   bool   `long:"database" description:"initialize database"`
+	FirstBlock bool   `long:"first-block" description:"generate first block and keys"`
+	Host       string `long:"first-block-host" description:"host of the first block"`
+}
+
+func (c *initCommand) Execute(args []string) error {
+	if !c.Config && !c.Database && !c.FirstBlock {
+		c.Config, c.Database, c.FirstBlock = true, true, true
+	}
+	*conf.InitConfig, *conf.InitDatabase, *conf.GenerateFirstBlock = c.Config, c.Database, c.FirstBlock
+	if len(c.Host) > 0 {
+		*conf.FirstBlockHost = c.Host
+	}
+	*conf.NoStart = true
+	runNode()
+	return nil
+}
+
+type startCommand struct {
+	Daemons string `long:"daemons" description:"comma separated list of daemons to start, 'null' for none"`
+	TLS     string `long:"tls" description:"directory for .well-known and keys, enables https"`
+}
+
+func (c *startCommand) Execute(args []string) error {
+	if len(c.Daemons) > 0 {
+		setFlag("startDaemons", c.Daemons)
+	}
+	if len(c.TLS) > 0 {
+		*conf.TLS = c.TLS
+	}
+	runNode()
+	return nil
+}
+
+type configCheckCommand struct{}
+
+func (c *configCheckCommand) Execute(args []string) error {
+	if err := loadConfig(); err != nil {
+		return err
+	}
+	if checkConfig() != 0 {
+		return errCommandFailed
+	}
+	return nil
+}
+
+type configShowCommand struct{}
+
+func (c *configShowCommand) Execute(args []string) error {
+	if err := loadConfig(); err != nil {
+		return err
+	}
+	return toml.NewEncoder(os.Stdout).Encode(conf.RedactedConfig())
+}
+
+type configCommand struct {
+	Check configCheckCommand `command:"check" description:"validate config and report problems"`
+	Show  configShowCommand  `command:"show" description:"print effective config with hidden secrets"`
+}
+
+type keysGenerateCommand struct{}
+
+func (c *keysGenerateCommand) Execute(args []string) error {
+	if err := loadConfig(); err != nil {
+		return err
+	}
+	if err := install.GenerateKeys(); err != nil {
+		return err
+	}
+	fmt.Println("Keys are created in", conf.Config.PrivateDir)
+	return nil
+}
+
+type keysShowCommand struct{}
+
+func (c *keysShowCommand) Execute(args []string) error {
+	if err := loadConfig(); err != nil {
+		return err
+	}
+	for _, name := range []string{consts.PrivateKeyFilename, consts.NodePrivateKeyFilename} {
+		data, err := ioutil.ReadFile(filepath.Join(conf.Config.PrivateDir, name))
+		if err != nil {
+			return err
+		}
+		priv, err := hex.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil {
+			return err
+		}
+		pub, err := crypto.PrivateToPublic(priv)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s:\n  key_id: %d\n  address: %s\n  public: %x\n", name, crypto.Address(pub), crypto.KeyToAddress(pub), pub)
+	}
+	return nil
+}
+
+type keysCertificateCommand struct {
+	CAKey   string `long:"ca-key" required:"true" description:"file with the private key of network CA"`
+	NodeKey string `long:"node-key" description:"public key of node in hex, the key of this node by default"`
+	Days    int    `long:"days" default:"365" description:"validity period of the certificate in days"`
+	Out     string `long:"out" description:"output file, NodeCertificate in the private directory by default"`
+}
+
+func (c *keysCertificateCommand) Execute(args []string) error {
+	if err := loadConfig(); err != nil {
+		return err
+	}
+	caKey, err := ioutil.ReadFile(c.CAKey)
+	if err != nil {
+		return err
+	}
+	nodeKey := c.NodeKey
+	if len(nodeKey) == 0 {
+		data, err := ioutil.ReadFile(filepath.Join(conf.Config.PrivateDir, consts.NodePublicKeyFilename))
+		if err != nil {
+			return err
+		}
+		nodeKey = string(data)
+	}
+	pub, err := hex.DecodeString(strings.TrimSpace(nodeKey))
+	if err != nil {
+		return err
+	}
+	cert, err := nodecert.Issue(strings.TrimSpace(string(caKey)), pub, time.Now().AddDate(0, 0, c.Days).Unix())
+	if err != nil {
+		return err
+	}
+	out := c.Out
+	if len(out) == 0 {
+		out = filepath.Join(conf.Config.PrivateDir, consts.NodeCertificateFilename)
+	}
+	if err = ioutil.WriteFile(out, []byte(cert.String()), 0600); err != nil {
+		return err
+	}
+	fmt.Println("Certificate is written to", out)
+	return nil
+}
+
+type keysCommand struct {
+	Generate    keysGenerateCommand    `command:"generate" description:"create wallet and node keys in the private directory"`
+	Show        keysShowCommand        `command:"show" description:"print key ids, addresses and public keys"`
+	Certificate keysCertificateCommand `command:"certificate" description:"issue the certificate of node signed by network CA key"`
+}
+
+type blockFirstCommand struct {
+	Host string `long:"host" description:"host of the first block, Listen.AdvertiseTCP by default"`
+}
+
+func (c *blockFirstCommand) Execute(args []string) error {
+	if err := loadConfig(); err != nil {
+		return err
+	}
+	if len(c.Host) > 0 {
+		*conf.FirstBlockHost = c.Host
+	} else if len(conf.Config.Listen.AdvertiseTCP) > 0 {
+		*conf.FirstBlockHost = conf.Config.Listen.AdvertiseTCP
+	}
+	if err := install.GenerateFirstBlock(); err != nil {
+		return err
+	}
+	fmt.Println("First block is created in", *conf.FirstBlockPath)
+	return nil
+}
+
+type blockFetchFirstCommand struct {
+	Host string `long:"host" required:"yes" description:"tcp address of a node of the network"`
+	Hash string `long:"hash" description:"expected sha256 hash of the first block in hex"`
+}
+
+func (c *blockFetchFirstCommand) Execute(args []string) error {
+	if err := loadConfig(); err != nil {
+		return err
+	}
+	data, params, err := tcpserver.GetFirstBlock(c.Host)
+	if err != nil {
+		return err
+	}
+	hash := sha256.Sum256(data)
+	if len(c.Hash) > 0 && !strings.EqualFold(c.Hash, hex.EncodeToString(hash[:])) {
+		return fmt.Errorf("hash of the first block %x doesn't match %s", hash, c.Hash)
+	}
+	if err = ioutil.WriteFile(*conf.FirstBlockPath, data, 0600); err != nil {
+		return err
+	}
+	fmt.Printf("First block %x is saved in %s\n", hash, *conf.FirstBlockPath)
+	fmt.Printf("Network version %s, protocol %d, last block %d\n", params.Version, params.Protocol, params.BlockID)
+	return nil
+}
+
+type blockCommand struct {
+	First      blockFirstCommand      `command:"first" description:"generate the first block and keys"`
+	FetchFirst blockFetchFirstCommand `command:"fetch-first" description:"get the first block from a node of the network"`
+}
+
+type vdeCreateCommand struct {
+	Ecosystem int64 `long:"ecosystem" default:"1" description:"ecosystem id"`
+	KeyID     int64 `long:"key-id" description:"wallet of VDE founder, node key id by default"`
+}
+
+func (c *vdeCreateCommand) Execute(args []string) error {
+	if err := loadConfig(); err != nil {
+		return err
+	}
+	if c.KeyID == 0 {
+		c.KeyID = conf.Config.KeyID
+	}
+	if err := initDB(); err != nil {
+		return err
+	}
+	defer model.GormClose()
+	if model.IsTable(fmt.Sprintf(`%d_vde_tables`, c.Ecosystem)) {
+		return fmt.Errorf("VDE of ecosystem %d has already been created", c.Ecosystem)
+	}
+	if err := model.ExecSchemaLocalData(int(c.Ecosystem), c.KeyID); err != nil {
+		return err
+	}
+	fmt.Printf("VDE of ecosystem %d is created\n", c.Ecosystem)
+	return nil
+}
+
+type vdeConsoleCommand struct {
+	Ecosystem int64 `long:"ecosystem" default:"1" description:"ecosystem id"`
+	KeyID     int64 `long:"key-id" description:"key id on whose behalf the code is run, node key id by default"`
+}
+
+// Execute reads the code from stdin and evaluates it against VDE, the input is collected
+// until all the braces are closed so that blocks can be written on several lines
+func (c *vdeConsoleCommand) Execute(args []string) error {
+	if err := loadConfig(); err != nil {
+		return err
+	}
+	if c.KeyID == 0 {
+		c.KeyID = conf.Config.KeyID
+	}
+	if err := initDB(); err != nil {
+		return err
+	}
+	defer model.GormClose()
+	if err := smart.LoadVDEContracts(nil, strconv.FormatInt(c.Ecosystem, 10)); err != nil {
+		return err
+	}
+	if smart.GetVM(true, c.Ecosystem) == nil {
+		return fmt.Errorf("VDE of ecosystem %d doesn't exist", c.Ecosystem)
+	}
+	var code []string
+	scanner := bufio.NewScanner(os.Stdin)
+	fmt.Print("> ")
+	for scanner.Scan() {
+		code = append(code, scanner.Text())
+		source := strings.TrimSpace(strings.Join(code, "\n"))
+		if strings.Count(source, "{") > strings.Count(source, "}") {
+			fmt.Print(". ")
+			continue
+		}
+		code = code[:0]
+		if len(source) > 0 {
+			if ret, err := smart.ConsoleEval(c.Ecosystem, c.KeyID, source); err != nil {
+				fmt.Println("error:", err)
+			} else if len(ret) > 0 {
+				fmt.Println(ret)
+			}
+		}
+		fmt.Print("> ")
+	}
+	fmt.Println()
+	return scanner.Err()
+}
+
+type vdeCommand struct {
+	Create  vdeCreateCommand  `command:"create" description:"create VDE tables of ecosystem"`
+	Console vdeConsoleCommand `command:"console" description:"evaluate expressions and call contracts of VDE interactively"`
+}
+
+type exportChainCommand struct {
+	To   int64 `long:"to" description:"last exported block id, all blocks by default"`
+	Args struct {
+		File string `positional-arg-name:"file" description:"chain file, the export is continued if it exists"`
+	} `positional-args:"yes" required:"yes"`
+}
+
+func (c *exportChainCommand) Execute(args []string) error {
+	if err := loadConfig(); err != nil {
+		return err
+	}
+	if err := initDB(); err != nil {
+		return err
+	}
+	defer model.GormClose()
+	return daemons.ExportChain(context.Background(), c.Args.File, c.To)
+}
+
+type importChainCommand struct {
+	Args struct {
+		File string `positional-arg-name:"file" description:"chain file, blocks after the last block of node are imported"`
+	} `positional-args:"yes" required:"yes"`
+}
+
+func (c *importChainCommand) Execute(args []string) error {
+	if err := loadConfig(); err != nil {
+		return err
+	}
+	if err := initChain(); err != nil {
+		return err
+	}
+	defer model.GormClose()
+	return daemons.ImportChain(context.Background(), c.Args.File)
+}
+
+type inspectBlockCommand struct {
+	Args struct {
+		Block string `positional-arg-name:"id|hash" description:"block id or hex hash of block"`
+	} `positional-args:"yes" required:"yes"`
+}
+
+func (c *inspectBlockCommand) Execute(args []string) error {
+	var (
+		id   int64
+		hash []byte
+		err  error
+	)
+	if id, err = strconv.ParseInt(c.Args.Block, 10, 64); err != nil {
+		if hash, err = hex.DecodeString(c.Args.Block); err != nil {
+			return fmt.Errorf("%s is neither block id nor hash", c.Args.Block)
+		}
+	}
+	if err = loadConfig(); err != nil {
+		return err
+	}
+	if err = initChain(); err != nil {
+		return err
+	}
+	defer model.GormClose()
+	info, err := parser.InspectStoredBlock(id, hash)
+	if err != nil {
+		return err
+	}
+	return printJSON(info)
+}
+
+type inspectTxCommand struct {
+	Args struct {
+		Hash string `positional-arg-name:"hash" description:"hex hash of transaction"`
+	} `positional-args:"yes" required:"yes"`
+}
+
+func (c *inspectTxCommand) Execute(args []string) error {
+	hash, err := hex.DecodeString(c.Args.Hash)
+	if err != nil {
+		return fmt.Errorf("%s is not a hex hash", c.Args.Hash)
+	}
+	if err = loadConfig(); err != nil {
+		return err
+	}
+	if err = initChain(); err != nil {
+		return err
+	}
+	defer model.GormClose()
+	info, blockID, err := parser.InspectTx(hash)
+	if err != nil {
+		return err
+	}
+	return printJSON(map[string]interface{}{"block_id": blockID, "transaction": info})
+}
+
+type inspectCommand struct {
+	Block inspectBlockCommand `command:"block" description:"decode the block to JSON"`
+	Tx    inspectTxCommand    `command:"tx" description:"decode the transaction to JSON"`
+}
+
+type appsExportCommand struct {
+	Ecosystem  int64  `long:"ecosystem" default:"1" description:"ecosystem id"`
+	VDE        bool   `long:"vde" description:"export from VDE of ecosystem"`
+	Name       string `long:"name" description:"name of application"`
+	Contracts  string `long:"contracts" description:"comma separated names of contracts"`
+	Pages      string `long:"pages" description:"comma separated names of pages"`
+	Menus      string `long:"menus" description:"comma separated names of menus"`
+	Blocks     string `long:"blocks" description:"comma separated names of blocks"`
+	Parameters string `long:"parameters" description:"comma separated names of parameters"`
+	Languages  string `long:"languages" description:"comma separated names of language resources"`
+	Tables     string `long:"tables" description:"comma separated names of tables"`
+	Data       bool   `long:"data" description:"export rows of tables"`
+	Manifest   string `long:"manifest" description:"manifest file of versioned application"`
+	Key        string `long:"key" description:"private key file of the author, the wallet key in private directory by default"`
+	Output     string `short:"o" long:"output" description:"bundle file, stdout by default"`
+}
+
+func splitList(list string) []string {
+	if len(list) == 0 {
+		return nil
+	}
+	return strings.Split(list, ",")
+}
+
+func appsPrefix(ecosystem int64, vde bool) string {
+	prefix := strconv.FormatInt(ecosystem, 10)
+	if vde {
+		prefix += "_vde"
+	}
+	return prefix
+}
+
+func (c *appsExportCommand) Execute(args []string) error {
+	if err := loadConfig(); err != nil {
+		return err
+	}
+	if err := initDB(); err != nil {
+		return err
+	}
+	defer model.GormClose()
+	data, err := apps.Export(appsPrefix(c.Ecosystem, c.VDE), &apps.Selection{
+		Contracts:  splitList(c.Contracts),
+		Pages:      splitList(c.Pages),
+		Menus:      splitList(c.Menus),
+		Blocks:     splitList(c.Blocks),
+		Parameters: splitList(c.Parameters),
+		Languages:  splitList(c.Languages),
+		Tables:     splitList(c.Tables),
+		WithData:   c.Data,
+	})
+	if err != nil {
+		return err
+	}
+	var m *manifest.Manifest
+	if len(c.Manifest) > 0 {
+		content, err := ioutil.ReadFile(c.Manifest)
+		if err != nil {
+			return err
+		}
+		if m, err = manifest.Parse(string(content)); err != nil {
+			return err
+		}
+		if len(c.Name) == 0 {
+			c.Name = m.Name
+		}
+	}
+	if len(c.Key) == 0 {
+		c.Key = filepath.Join(conf.Config.PrivateDir, consts.PrivateKeyFilename)
+	}
+	privateKey, err := ioutil.ReadFile(c.Key)
+	if err != nil {
+		return err
+	}
+	bundle, err := apps.NewBundle(c.Name, c.Ecosystem, c.VDE, data, m, string(privateKey))
+	if err != nil {
+		return err
+	}
+	out, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return err
+	}
+	if len(c.Output) == 0 {
+		fmt.Println(string(out))
+		return nil
+	}
+	return ioutil.WriteFile(c.Output, out, 0644)
+}
+
+type appsCheckCommand struct {
+	Ecosystem int64 `long:"ecosystem" default:"1" description:"target ecosystem id"`
+	VDE       bool  `long:"vde" description:"import to VDE of ecosystem"`
+	Args      struct {
+		File string `positional-arg-name:"file" description:"bundle file"`
+	} `positional-args:"yes" required:"yes"`
+}
+
+func (c *appsCheckCommand) Execute(args []string) error {
+	in, err := ioutil.ReadFile(c.Args.File)
+	if err != nil {
+		return err
+	}
+	var bundle apps.Bundle
+	if err = json.Unmarshal(in, &bundle); err != nil {
+		return err
+	}
+	if err = bundle.Verify(); err != nil {
+		return err
+	}
+	if err = loadConfig(); err != nil {
+		return err
+	}
+	if err = initChain(); err != nil {
+		return err
+	}
+	defer model.GormClose()
+	if c.VDE {
+		if err = smart.LoadVDEContracts(nil, strconv.FormatInt(c.Ecosystem, 10)); err != nil {
+			return err
+		}
+	}
+	problems := apps.Check(&bundle.Data, &apps.Target{Ecosystem: c.Ecosystem, VDE: c.VDE})
+	fmt.Printf("Bundle %s is signed by %d\n", bundle.Name, bundle.KeyID)
+	for _, p := range problems {
+		fmt.Printf("%s %s %s: %s\n", p.Kind, p.Type, p.Name, p.Message)
+	}
+	if apps.HasErrors(problems) {
+		return errCommandFailed
+	}
+	fmt.Println("Bundle can be imported with Import contract, its Data parameter is the data field of bundle")
+	return nil
+}
+
+type appsCommand struct {
+	Export appsExportCommand `command:"export" description:"export objects of ecosystem to the signed bundle"`
+	Check  appsCheckCommand  `command:"check" description:"verify bundle and check dependencies and collisions in target ecosystem"`
+}
+
+type rollbackCommand struct {
+	Args struct {
+		BlockID int64 `positional-arg-name:"block_id" description:"target block id, 1 rolls back everything"`
+	} `positional-args:"yes" required:"yes"`
+}
+
+func (c *rollbackCommand) Execute(args []string) error {
+	if c.Args.BlockID < 1 {
+		return fmt.Errorf("block id must be greater than 0")
+	}
+	*conf.RollbackToBlockID = c.Args.BlockID
+	runNode()
+	return nil
+}
+
+// checkConfigCommand is kept for compatibility, use 'config check'
+type checkConfigCommand struct {
+	configCheckCommand
+}
+
+type commandList struct {
+	Init        initCommand        `command:"init" description:"create config, database and first block"`
+	Start       startCommand       `command:"start" description:"start the node (default)"`
+	Config      configCommand      `command:"config" description:"check or show config"`
+	Keys        keysCommand        `command:"keys" description:"manage wallet and node keys"`
+	Block       blockCommand       `command:"block" description:"manage blocks"`
+	VDE         vdeCommand         `command:"vde" description:"manage virtual dedicated ecosystems"`
+	Rollback    rollbackCommand    `command:"rollback" description:"rollback the blockchain to the block"`
+	ExportChain exportChainCommand `command:"export-chain" description:"write blocks to the chain file"`
+	ImportChain importChainCommand `command:"import-chain" description:"insert blocks from the chain file"`
+	Inspect     inspectCommand     `command:"inspect" description:"decode stored blocks and transactions"`
+	Apps        appsCommand        `command:"apps" description:"export and check application bundles"`
+	Devnet      devnetCommand      `command:"devnet" description:"create and start the local test network of several nodes"`
+	Service     serviceCommand     `command:"service" description:"run the node as Windows service or systemd unit"`
+	DevnetNode  devnetNodeCommand  `command:"devnet-node" hidden:"yes"`
+	CheckConfig checkConfigCommand `command:"checkconfig" hidden:"yes"`
+}
+
+func newCommandParser() *flags.Parser {
+	parser := flags.NewParser(&commandList{}, flags.HelpFlag)
+	parser.Name = filepath.Base(os.Args[0])
+	parser.Usage = "[global flags]"
+	return parser
+}
+
+// runCommand executes the command with its arguments
+func runCommand(args []string) int {
+	if _, err := newCommandParser().ParseArgs(args); err != nil {
+		if ferr, ok := err.(*flags.Error); ok && ferr.Type == flags.ErrHelp {
+			fmt.Println(err)
+			return 0
+		}
+		if err != errCommandFailed {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		return 1
+	}
+	return 0
+}
+
+// usage prints the commands and the global flags
+func usage() {
+	newCommandParser().WriteHelp(os.Stderr)
+	fmt.Fprintln(os.Stderr, "\nGlobal flags (also accepted without a command for compatibility):")
+	flag.PrintDefaults()
+}
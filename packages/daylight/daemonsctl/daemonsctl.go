@@ -23,9 +23,13 @@
 package daemonsctl
 
 import (
+	"fmt"
+
 	conf "github.com/GenesisKernel/go-genesis/packages/conf"
 	"github.com/GenesisKernel/go-genesis/packages/config/syspar"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
 	"github.com/GenesisKernel/go-genesis/packages/daemons"
+	"github.com/GenesisKernel/go-genesis/packages/parser"
 	"github.com/GenesisKernel/go-genesis/packages/smart"
 	"github.com/GenesisKernel/go-genesis/packages/tcpserver"
 	"github.com/GenesisKernel/go-genesis/packages/utils"
@@ -47,10 +51,31 @@ func RunAllDaemons() error {
 		return err
 	}
 
+	recovered, err := parser.RecoverInterruptedBlocks()
+	if err != nil {
+		log.Errorf("can't recover interrupted blocks: %s", err)
+		return err
+	}
+	repaired, err := checkConsistency()
+	if err != nil {
+		return err
+	}
+	if recovered || repaired {
+		// system parameters and contracts are reloaded after the rollback
+		if err = syspar.SysUpdate(nil); err != nil {
+			log.Errorf("can't read system parameters: %s", utils.ErrInfo(err))
+			return err
+		}
+		if err = smart.LoadContracts(nil); err != nil {
+			log.Errorf("Load Contracts error: %s", err)
+			return err
+		}
+	}
+
 	log.Info("start daemons")
 	daemons.StartDaemons()
 
-	err = tcpserver.TcpListener(conf.Config.TCPServer.Str())
+	err = tcpserver.TcpListener(conf.TCPListenAddrs()...)
 	if err != nil {
 		log.Errorf("can't start tcp servers, stop")
 		return err
@@ -58,3 +83,33 @@ func RunAllDaemons() error {
 
 	return nil
 }
+
+// checkConsistency refuses to start on the corrupted database or repairs it if repairDB flag is set
+func checkConsistency() (bool, error) {
+	problems, err := parser.CheckConsistency(*conf.CheckDepth)
+	if err != nil {
+		log.Errorf("can't check consistency of database: %s", err)
+		return false, err
+	}
+	if len(problems) == 0 {
+		return false, nil
+	}
+	for _, problem := range problems {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "check": problem.Check, "block_id": problem.BlockID}).Error(problem.Message)
+	}
+	if !*conf.RepairDB {
+		return false, fmt.Errorf("database is inconsistent, restart with -repairDB to roll back the corrupted blocks")
+	}
+	if err = parser.RepairConsistency(problems); err != nil {
+		log.Errorf("can't repair database: %s", err)
+		return false, err
+	}
+	if problems, err = parser.CheckConsistency(*conf.CheckDepth); err != nil {
+		return false, err
+	}
+	if len(problems) > 0 {
+		return false, fmt.Errorf("database is inconsistent after repair: %s", problems[0])
+	}
+	log.Warning("database is repaired")
+	return true, nil
+}
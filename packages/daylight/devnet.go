@@ -0,0 +1,317 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package daylight
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/GenesisKernel/go-genesis/packages/config/syspar"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/crypto"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/parser"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	devnetHost    = "127.0.0.1"
+	devnetLogFile = "node.log"
+)
+
+// devnetNode is the local node of test network
+type devnetNode struct {
+	Dir      string
+	DBName   string
+	TCPPort  int
+	HTTPPort int
+}
+
+// devnetPlan returns the directories, databases and ports of nodes,
+// the ports of node i are tcpPort+2i and httpPort+2i
+func devnetPlan(dir, dbPrefix string, count, tcpPort, httpPort int) ([]devnetNode, error) {
+	if count < 1 {
+		return nil, fmt.Errorf("number of nodes must be greater than 0")
+	}
+	ports := make(map[int]bool)
+	nodes := make([]devnetNode, count)
+	for i := range nodes {
+		nodes[i] = devnetNode{
+			Dir:      filepath.Join(dir, fmt.Sprintf("node%d", i)),
+			DBName:   fmt.Sprintf("%s%d", dbPrefix, i),
+			TCPPort:  tcpPort + 2*i,
+			HTTPPort: httpPort + 2*i,
+		}
+		for _, port := range []int{nodes[i].TCPPort, nodes[i].HTTPPort} {
+			if ports[port] {
+				return nil, fmt.Errorf("port %d is used twice, choose other base ports", port)
+			}
+			ports[port] = true
+		}
+	}
+	return nodes, nil
+}
+
+func (n *devnetNode) tcpAddress() string {
+	return fmt.Sprintf("%s:%d", devnetHost, n.TCPPort)
+}
+
+// publicKeys reads the hex public keys of wallet and node from the private directory of node
+func (n *devnetNode) publicKeys() (wallet, node string, err error) {
+	var data []byte
+	keys := make([]string, 2)
+	for i, name := range []string{consts.PublicKeyFilename, consts.NodePublicKeyFilename} {
+		if data, err = ioutil.ReadFile(filepath.Join(n.Dir, name)); err != nil {
+			return
+		}
+		keys[i] = strings.TrimSpace(string(data))
+	}
+	return keys[0], keys[1], nil
+}
+
+// devnetFullNodes returns the value of full_nodes parameter for the nodes
+func devnetFullNodes(nodes []devnetNode) (string, error) {
+	list := make([][]string, len(nodes))
+	for i := range nodes {
+		wallet, node, err := nodes[i].publicKeys()
+		if err != nil {
+			return ``, err
+		}
+		pub, err := hex.DecodeString(wallet)
+		if err != nil {
+			return ``, err
+		}
+		list[i] = []string{nodes[i].tcpAddress(), strconv.FormatInt(crypto.Address(pub), 10), node}
+	}
+	out, err := json.Marshal(list)
+	return string(out), err
+}
+
+type devnetCommand struct {
+	Nodes      int    `short:"n" long:"nodes" default:"3" description:"number of nodes"`
+	Dir        string `long:"dir" default:"devnet" description:"directory for the work directories of nodes"`
+	DBHost     string `long:"db-host" default:"localhost" description:"database host"`
+	DBPort     int    `long:"db-port" default:"5432" description:"database port"`
+	DBUser     string `long:"db-user" default:"postgres" description:"database user"`
+	DBPassword string `long:"db-password" description:"database password"`
+	DBPrefix   string `long:"db-prefix" default:"devnet" description:"prefix of database names, the number of node is appended"`
+	TCPPort    int    `long:"tcp-port" default:"7078" description:"tcp port of the first node"`
+	HTTPPort   int    `long:"http-port" default:"7079" description:"http port of the first node"`
+	Reset      bool   `long:"reset" description:"initialize the network again if it exists"`
+	InitOnly   bool   `long:"init-only" description:"create the network without starting it"`
+}
+
+// devnetProcess is the running daylight command of the node
+type devnetProcess struct {
+	cmd *exec.Cmd
+	log *os.File
+}
+
+func (p *devnetProcess) wait() error {
+	defer p.log.Close()
+	return p.cmd.Wait()
+}
+
+// run starts the daylight command for the node, the output is appended to node.log
+func (c *devnetCommand) run(n *devnetNode, args ...string) (*devnetProcess, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	if err = os.MkdirAll(n.Dir, 0755); err != nil {
+		return nil, err
+	}
+	out, err := os.OpenFile(filepath.Join(n.Dir, devnetLogFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	flags := []string{
+		"-workDir", n.Dir,
+		"-tcpHost", devnetHost, "-tcpPort", strconv.Itoa(n.TCPPort),
+		"-httpHost", devnetHost, "-httpPort", strconv.Itoa(n.HTTPPort),
+		"-dbHost", c.DBHost, "-dbPort", strconv.Itoa(c.DBPort), "-dbUser", c.DBUser, "-dbName", n.DBName,
+	}
+	cmd := exec.Command(exe, append(flags, args...)...)
+	cmd.Env = append(os.Environ(), "PGPASSWORD="+c.DBPassword)
+	cmd.Stdout, cmd.Stderr = out, out
+	if err = cmd.Start(); err != nil {
+		out.Close()
+		return nil, err
+	}
+	return &devnetProcess{cmd: cmd, log: out}, nil
+}
+
+// exec runs the daylight command for the node and waits for it
+func (c *devnetCommand) exec(n *devnetNode, args ...string) error {
+	p, err := c.run(n, args...)
+	if err != nil {
+		return err
+	}
+	if err = p.wait(); err != nil {
+		return fmt.Errorf("%s failed for %s: %s, see %s", strings.Join(args, " "), n.Dir, err, devnetLogFile)
+	}
+	return nil
+}
+
+func (c *devnetCommand) createDatabases(nodes []devnetNode) error {
+	if err := model.GormInit(c.DBHost, c.DBPort, c.DBUser, c.DBPassword, "postgres"); err != nil {
+		return err
+	}
+	defer model.GormClose()
+	for _, n := range nodes {
+		if err := model.CreateDatabase(n.DBName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *devnetCommand) init(nodes []devnetNode) error {
+	if err := c.createDatabases(nodes); err != nil {
+		return err
+	}
+	for i := range nodes {
+		os.RemoveAll(nodes[i].Dir)
+		if i == 0 {
+			if err := c.exec(&nodes[i], "init", "--first-block-host", nodes[i].tcpAddress()); err != nil {
+				return err
+			}
+		} else {
+			if err := c.exec(&nodes[i], "keys", "generate"); err != nil {
+				return err
+			}
+			if err := c.exec(&nodes[i], "init", "--config", "--database"); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("Node %d is created in %s\n", i, nodes[i].Dir)
+	}
+	fullNodes, err := devnetFullNodes(nodes)
+	if err != nil {
+		return err
+	}
+	firstBlock := filepath.Join(nodes[0].Dir, consts.FirstBlockFilename)
+	for i := range nodes {
+		if err := c.exec(&nodes[i], "devnet-node", "--first-block", firstBlock, "--full-nodes", fullNodes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *devnetCommand) start(nodes []devnetNode) error {
+	var wg sync.WaitGroup
+	procs := make([]*devnetProcess, 0, len(nodes))
+	stop := func() {
+		for _, p := range procs {
+			p.cmd.Process.Signal(syscall.SIGTERM)
+		}
+	}
+	for i := range nodes {
+		p, err := c.run(&nodes[i], "start")
+		if err != nil {
+			stop()
+			return err
+		}
+		procs = append(procs, p)
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := p.wait(); err != nil {
+				log.WithFields(log.Fields{"type": consts.CommandExecutionError, "node": i, "error": err}).Error("node is stopped")
+			}
+		}(i)
+		fmt.Printf("Node %d: tcp %s, api http://%s:%d/api/v2, log %s\n", i, nodes[i].tcpAddress(),
+			devnetHost, nodes[i].HTTPPort, filepath.Join(nodes[i].Dir, devnetLogFile))
+	}
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigs
+		fmt.Println("Stopping nodes")
+		stop()
+	}()
+	wg.Wait()
+	return nil
+}
+
+func (c *devnetCommand) Execute(args []string) error {
+	dir, err := filepath.Abs(c.Dir)
+	if err != nil {
+		return err
+	}
+	nodes, err := devnetPlan(dir, c.DBPrefix, c.Nodes, c.TCPPort, c.HTTPPort)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stat(filepath.Join(nodes[len(nodes)-1].Dir, consts.DefaultConfigFile))
+	if c.Reset || err != nil {
+		if err = c.init(nodes); err != nil {
+			return err
+		}
+	}
+	if c.InitOnly {
+		return nil
+	}
+	return c.start(nodes)
+}
+
+// devnetNodeCommand loads the first block to the node of test network and
+// replaces the list of full nodes, it is called by devnet command
+type devnetNodeCommand struct {
+	FirstBlock string `long:"first-block" required:"yes" description:"first block file"`
+	FullNodes  string `long:"full-nodes" required:"yes" description:"value of full_nodes parameter"`
+}
+
+func (c *devnetNodeCommand) Execute(args []string) error {
+	data, err := ioutil.ReadFile(c.FirstBlock)
+	if err != nil {
+		return err
+	}
+	if err = loadConfig(); err != nil {
+		return err
+	}
+	if err = initChain(); err != nil {
+		return err
+	}
+	defer model.GormClose()
+	if err = parser.InsertBlockWOForks(data); err != nil {
+		return err
+	}
+	if err = (&model.SystemParameter{Name: syspar.FullNodes}).Update(c.FullNodes); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("updating full nodes")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package daylight
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestDevnetPlan(t *testing.T) {
+	nodes, err := devnetPlan("net", "devnet", 3, 7078, 7079)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) != 3 {
+		t.Fatalf("expected 3 nodes, got %d", len(nodes))
+	}
+	last := nodes[2]
+	if last.Dir != filepath.Join("net", "node2") || last.DBName != "devnet2" ||
+		last.TCPPort != 7082 || last.HTTPPort != 7083 {
+		t.Errorf("wrong plan of node: %+v", last)
+	}
+	if _, err = devnetPlan("net", "devnet", 2, 7078, 7080); err == nil {
+		t.Error("expected error for the port used twice")
+	}
+	if _, err = devnetPlan("net", "devnet", 0, 7078, 7079); err == nil {
+		t.Error("expected error for empty network")
+	}
+}
@@ -25,19 +25,18 @@
 package daylight
 
 import (
-	"net"
+	"context"
 	"net/http"
-	"strconv"
 
 	conf "github.com/GenesisKernel/go-genesis/packages/conf"
 	"github.com/GenesisKernel/go-genesis/packages/consts"
-	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/graceful"
 
 	log "github.com/sirupsen/logrus"
 )
 
-func httpListener(ListenHTTPHost string, route http.Handler) {
-	l, err := net.Listen("tcp4", ListenHTTPHost)
+func httpListener(name, ListenHTTPHost string, route http.Handler) {
+	l, err := graceful.Listen(name, conf.ListenNetwork(ListenHTTPHost), ListenHTTPHost)
 	log.WithFields(log.Fields{"host": ListenHTTPHost, "type": consts.NetworkError}).Debug("trying to listen at")
 	if err == nil {
 		log.WithFields(log.Fields{"host": ListenHTTPHost}).Info("listening at")
@@ -45,45 +44,16 @@ func httpListener(ListenHTTPHost string, route http.Handler) {
 		log.WithFields(log.Fields{"host": ListenHTTPHost, "error": err, "type": consts.NetworkError}).Debug("cannot listen at host")
 	}
 
-	go func() {
-		srv := &http.Server{Handler: route}
-		err = srv.Serve(l)
-		if err != nil {
-			log.WithFields(log.Fields{"host": ListenHTTPHost, "error": err, "type": consts.NetworkError}).Fatal("serving http at host")
-			panic(err)
+	srv := &http.Server{Handler: route}
+	graceful.OnDrain(func(ctx context.Context) {
+		if err := srv.Shutdown(ctx); err != nil {
+			log.WithFields(log.Fields{"host": ListenHTTPHost, "error": err, "type": consts.NetworkError}).Warning("http requests have not been finished")
 		}
-	}()
-}
-
-// For ipv6 on the server
-func httpListenerV6(route http.Handler) {
-	i := 0
-	port := strconv.Itoa(conf.Config.HTTP.Port)
-	var l net.Listener
-	var err error
-	for {
-		if i > 7 {
-			log.WithFields(log.Fields{"type": consts.NetworkError}).Error("tried all ports")
-			panic("Error listening ")
-		}
-		if i > 0 {
-			port = "7" + converter.IntToStr(i) + "79"
-		}
-		i++
-		l, err = net.Listen("tcp6", ":"+port)
-		if err == nil {
-			log.WithFields(log.Fields{"host": ":" + port}).Info("listening ipv6 at")
-			break
-		} else {
-			log.WithFields(log.Fields{"error": err, "host": ":" + port, "type": consts.NetworkError}).Error("cannot listenin at host")
-		}
-	}
-
+	})
 	go func() {
-		srv := &http.Server{Handler: route}
 		err = srv.Serve(l)
-		if err != nil {
-			log.WithFields(log.Fields{"error": err, "host": ":" + port}).Error("serving http at host")
+		if err != nil && err != http.ErrServerClosed {
+			log.WithFields(log.Fields{"host": ListenHTTPHost, "error": err, "type": consts.NetworkError}).Fatal("serving http at host")
 			panic(err)
 		}
 	}()
@@ -0,0 +1,76 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package daylight
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+	logtools "github.com/GenesisKernel/go-genesis/packages/log"
+	"github.com/GenesisKernel/go-genesis/packages/publisher"
+	"github.com/GenesisKernel/go-genesis/packages/service"
+)
+
+// configWatchInterval is the period of checking the modification of config file
+const configWatchInterval = 10 * time.Second
+
+func applyReloadedConfig(result *conf.ReloadResult) {
+	for _, name := range result.Applied {
+		switch name {
+		case "LogLevel":
+			logtools.SetGlobalLevel(logtools.ParseLevel(conf.LogLevel()))
+		case "Log.Levels":
+			_, levels := logtools.GetLevels()
+			newLevels := conf.LogLevels()
+			for subsystem := range levels {
+				if _, ok := newLevels[subsystem]; !ok {
+					logtools.ResetSubsystemLevel(subsystem)
+				}
+			}
+			for subsystem, level := range newLevels {
+				logtools.SetSubsystemLevel(subsystem, logtools.ParseLevel(level))
+			}
+		case "Centrifugo":
+			publisher.InitCentrifugo(conf.Centrifugo())
+		}
+	}
+}
+
+// initConfigReload reloads config on SIGHUP and on the modification of config file
+func initConfigReload() {
+	conf.AddReloadHandler(applyReloadedConfig)
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP)
+	go func() {
+		for range sigCh {
+			service.Notify(service.NotifyReloading)
+			conf.ReloadConfig()
+			service.Notify(service.NotifyReady)
+		}
+	}()
+	go conf.WatchConfig(configWatchInterval)
+}
@@ -0,0 +1,171 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package daylight
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/daemons"
+	"github.com/GenesisKernel/go-genesis/packages/graceful"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/service"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const serviceDescription = "Genesis blockchain node"
+
+// serviceConfig returns the service which starts the binary with the current work dir and config
+func serviceConfig(name string, command ...string) (*service.Config, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	workDir := *conf.WorkDirectory
+	if len(workDir) == 0 {
+		if workDir, err = os.Getwd(); err != nil {
+			return nil, err
+		}
+	}
+	if workDir, err = filepath.Abs(workDir); err != nil {
+		return nil, err
+	}
+	args := []string{"-workDir=" + workDir}
+	if len(*conf.ConfigPath) > 0 {
+		path, err := filepath.Abs(*conf.ConfigPath)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, "-configPath="+path)
+	}
+	return &service.Config{
+		Name:        name,
+		DisplayName: serviceDescription,
+		Description: serviceDescription,
+		Executable:  exe,
+		Args:        append(args, command...),
+		WorkDir:     workDir,
+	}, nil
+}
+
+type serviceInstallCommand struct {
+	Name string `long:"name" default:"genesis" description:"name of the service"`
+}
+
+func (c *serviceInstallCommand) Execute(args []string) error {
+	cfg, err := serviceConfig(c.Name, "service", "run", "--name", c.Name)
+	if err != nil {
+		return err
+	}
+	if err = service.Install(cfg); err != nil {
+		return err
+	}
+	fmt.Printf("Service %s is installed, start it with 'sc start %s'\n", c.Name, c.Name)
+	return nil
+}
+
+type serviceUninstallCommand struct {
+	Name string `long:"name" default:"genesis" description:"name of the service"`
+}
+
+func (c *serviceUninstallCommand) Execute(args []string) error {
+	if err := service.Uninstall(c.Name); err != nil {
+		return err
+	}
+	fmt.Printf("Service %s is removed\n", c.Name)
+	return nil
+}
+
+type serviceRunCommand struct {
+	Name string `long:"name" default:"genesis" description:"name of the service"`
+}
+
+func (c *serviceRunCommand) Execute(args []string) error {
+	return service.Run(c.Name, runNode, stopNode)
+}
+
+type serviceSystemdCommand struct {
+	Name     string `long:"name" default:"genesis" description:"name of the unit"`
+	User     string `long:"user" description:"user of the node process"`
+	Watchdog int64  `long:"watchdog" default:"60" description:"watchdog timeout in seconds, 0 disables the watchdog"`
+	Output   string `short:"o" long:"output" description:"unit file, stdout by default"`
+}
+
+func (c *serviceSystemdCommand) Execute(args []string) error {
+	cfg, err := serviceConfig(c.Name, "start")
+	if err != nil {
+		return err
+	}
+	cfg.User = c.User
+	cfg.Watchdog = time.Duration(c.Watchdog) * time.Second
+	unit, err := service.SystemdUnit(cfg)
+	if err != nil {
+		return err
+	}
+	if len(c.Output) == 0 {
+		fmt.Print(unit)
+		return nil
+	}
+	return ioutil.WriteFile(c.Output, []byte(unit), 0644)
+}
+
+type serviceCommand struct {
+	Install   serviceInstallCommand   `command:"install" description:"register the node as Windows service"`
+	Uninstall serviceUninstallCommand `command:"uninstall" description:"remove Windows service of the node"`
+	Run       serviceRunCommand       `command:"run" description:"run the node under Windows service control manager"`
+	Systemd   serviceSystemdCommand   `command:"systemd-unit" description:"generate systemd unit of the node"`
+}
+
+// stopNode stops the node which is run as Windows service
+func stopNode() {
+	daemons.StopAllDaemons()
+	if model.DBConn != nil {
+		if err := model.GormClose(); err != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("closing gorm")
+		}
+	}
+	delPidFile()
+}
+
+// notifyReady tells systemd that the node is started and runs the watchdog
+func notifyReady() {
+	state := service.NotifyReady
+	if graceful.Inherited() {
+		// the new process of the graceful restart becomes the main process of the unit
+		state = fmt.Sprintf("MAINPID=%d\n%s", os.Getpid(), state)
+	}
+	ok, err := service.Notify(state)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.NetworkError, "error": err}).Error("notifying systemd")
+		return
+	}
+	if ok {
+		go service.RunWatchdog(daemons.DaemonsAlive)
+	}
+}
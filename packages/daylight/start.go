@@ -24,12 +24,14 @@ package daylight
 
 import (
 	"encoding/json"
+	"flag"
 	"fmt"
 	"io/ioutil"
 	"math/rand"
 	"net/http"
 	"os"
-	"path/filepath"
+	"runtime/debug"
+	"strconv"
 	"time"
 
 	"github.com/GenesisKernel/go-genesis/packages/api"
@@ -38,13 +40,17 @@ import (
 	"github.com/GenesisKernel/go-genesis/packages/config/syspar"
 	"github.com/GenesisKernel/go-genesis/packages/consts"
 	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/crypto"
 	"github.com/GenesisKernel/go-genesis/packages/daemons"
 	"github.com/GenesisKernel/go-genesis/packages/daylight/daemonsctl"
+	"github.com/GenesisKernel/go-genesis/packages/diagnose"
+	"github.com/GenesisKernel/go-genesis/packages/graceful"
 	"github.com/GenesisKernel/go-genesis/packages/install"
 	logtools "github.com/GenesisKernel/go-genesis/packages/log"
 	"github.com/GenesisKernel/go-genesis/packages/model"
 	"github.com/GenesisKernel/go-genesis/packages/parser"
 	"github.com/GenesisKernel/go-genesis/packages/publisher"
+	"github.com/GenesisKernel/go-genesis/packages/script"
 	"github.com/GenesisKernel/go-genesis/packages/smart"
 	"github.com/GenesisKernel/go-genesis/packages/statsd"
 	"github.com/GenesisKernel/go-genesis/packages/utils"
@@ -59,6 +65,16 @@ func initStatsd() {
 	}
 }
 
+// initResources applies the limits of the resource profile, the pool of DB connections is set by GormInit
+func initResources() {
+	res := conf.Resources()
+	crypto.SetSignCacheSize(res.SignCache)
+	script.SetCompileCacheSize(res.CompileCache)
+	api.SetThumbnailCacheSize(res.ThumbnailCache << 20)
+	debug.SetGCPercent(res.GCPercent)
+	log.WithFields(log.Fields{"profile": conf.Config.Resources.Profile, "resources": res}).Debug("resource limits")
+}
+
 func killOld() {
 	pidPath := conf.GetPidFile()
 	if _, err := os.Stat(pidPath); err == nil {
@@ -88,38 +104,41 @@ func killOld() {
 }
 
 func initLogs() error {
+	var sinks []*logtools.Sink
+	formatter := logtools.NewFormatter(conf.Config.Log.Format)
 
-	if len(conf.Config.LogFileName) == 0 {
-		log.SetOutput(os.Stdout)
-	} else {
-		fileName := filepath.Join(conf.Config.WorkDir, conf.Config.LogFileName)
-		openMode := os.O_APPEND
-		if _, err := os.Stat(fileName); os.IsNotExist(err) {
-			openMode = os.O_CREATE
-		}
-
-		f, err := os.OpenFile(fileName, os.O_WRONLY|openMode, 0755)
+	if len(conf.Config.LogFileName) == 0 || conf.Config.Log.Stdout {
+		sinks = append(sinks, &logtools.Sink{Name: "stdout", Writer: os.Stdout, Formatter: formatter})
+	}
+	if len(conf.Config.LogFileName) > 0 {
+		fileName := conf.GetLogPath()
+		f, err := logtools.OpenRotateFile(fileName, conf.Config.Log.MaxSize<<20, conf.Config.Log.MaxBackups)
 		if err != nil {
 			fmt.Fprintln(os.Stderr, "Can't open log file: ", fileName)
 			return err
 		}
-		log.SetOutput(f)
+		sinks = append(sinks, &logtools.Sink{Name: "file", Writer: f, Formatter: formatter})
+	}
+	if conf.Config.Log.Syslog {
+		tag := conf.Config.Log.SyslogTag
+		if len(tag) == 0 {
+			tag = consts.SyslogTag
+		}
+		w, err := logtools.NewSyslogWriter(tag)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Can't connect to syslog: ", err)
+			return err
+		}
+		sinks = append(sinks, &logtools.Sink{Name: "syslog", Writer: w, Formatter: logtools.NewFormatter("json")})
 	}
 
-	switch conf.Config.LogLevel {
-	case "DEBUG":
-		log.SetLevel(log.DebugLevel)
-	case "INFO":
-		log.SetLevel(log.InfoLevel)
-	case "WARN":
-		log.SetLevel(log.WarnLevel)
-	case "ERROR":
-		log.SetLevel(log.ErrorLevel)
-	default:
-		log.SetLevel(log.InfoLevel)
+	logtools.SetGlobalLevel(logtools.ParseLevel(conf.LogLevel()))
+	for subsystem, level := range conf.LogLevels() {
+		logtools.SetSubsystemLevel(subsystem, logtools.ParseLevel(level))
 	}
 
 	log.AddHook(logtools.ContextHook{})
+	logtools.SetupSinks(sinks...)
 
 	return nil
 }
@@ -171,7 +190,7 @@ func rollbackToBlock(blockID int64) error {
 	}
 
 	if warn == 0 {
-		rbFile := filepath.Join(conf.Config.WorkDir, consts.RollbackResultFilename)
+		rbFile := conf.DataPath(consts.RollbackResultFilename)
 		ioutil.WriteFile(rbFile, []byte("1"), 0644)
 		if err != nil {
 			log.WithFields(log.Fields{"error": err, "type": consts.WritingFile, "path": rbFile}).Error("rollback result flag")
@@ -181,26 +200,80 @@ func rollbackToBlock(blockID int64) error {
 	return nil
 }
 
+// checkConfig prints the problems of config and returns the exit code
+func checkConfig() int {
+	problems := conf.ValidateConfig(true)
+	for _, problem := range problems {
+		fmt.Println(problem)
+	}
+	if len(problems) > 0 {
+		return 1
+	}
+	fmt.Println("Config is OK")
+	return 0
+}
+
 func setRoute(route *httprouter.Router, path string, handle func(http.ResponseWriter, *http.Request), methods ...string) {
 	for _, method := range methods {
 		route.HandlerFunc(method, path, handle)
 	}
 }
 
-func initRoutes(listenHost string) {
+func initRoutes(listenHosts []string) {
 	route := httprouter.New()
 	setRoute(route, `/monitoring`, daemons.Monitoring, `GET`)
+	setRoute(route, `/healthz`, daemons.Healthz, `GET`)
+	setRoute(route, `/readyz`, daemons.Readyz, `GET`)
 	api.Route(route)
 	route.Handler(`GET`, consts.WellKnownRoute, http.FileServer(http.Dir(*conf.TLS)))
 	if len(*conf.TLS) > 0 {
 		go http.ListenAndServeTLS(":443", *conf.TLS+consts.TLSFullchainPem, *conf.TLS+consts.TLSPrivkeyPem, route)
 	}
 
-	httpListener(listenHost, route)
+	for i, host := range listenHosts {
+		name := "api"
+		if i > 0 {
+			name += strconv.Itoa(i)
+		}
+		httpListener(name, host, route)
+	}
 }
 
-// Start starts the main code of the program
+// loadConfig reads config file and applies environment, flags and secrets to it
+func loadConfig() error {
+	if conf.NoConfig() {
+		conf.Installed = false
+		log.Info("Config file missing.")
+	} else if !*conf.InitConfig {
+		if err := conf.LoadConfig(); err != nil {
+			log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("LoadConfig")
+			return err
+		}
+		conf.Installed = true
+	}
+	conf.SetConfigParams()
+	if err := conf.ResolveSecrets(); err != nil {
+		log.WithFields(log.Fields{"type": consts.ConfigError, "error": err}).Error("ResolveSecrets")
+		return err
+	}
+	conf.Publish()
+	return nil
+}
+
+// Start starts the main code of the program. Without a command the node is started,
+// the legacy flags like -initConfig or -rollbackToBlockId are processed in this case
 func Start() {
+	flag.Usage = usage
+	conf.InitConfigFlags()
+	if flag.NArg() == 0 {
+		runNode()
+		return
+	}
+	os.Exit(runCommand(flag.Args()))
+}
+
+// runNode processes the directive flags and starts the node
+func runNode() {
 
 	var err error
 
@@ -228,24 +301,23 @@ func Start() {
 		}
 	}
 
-	conf.InitConfigFlags()
-	if conf.NoConfig() {
-		conf.Installed = false
-		log.Info("Config file missing.")
-	} else {
-		if !*conf.InitConfig {
-			if err := conf.LoadConfig(); err != nil {
-				log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("LoadConfig")
-				return
-			}
-			conf.Installed = true
-		}
+	if err := loadConfig(); err != nil {
+		return
 	}
-	conf.SetConfigParams()
 
 	autoupdate.InitUpdater(conf.Config.Autoupdate.ServerAddress, conf.Config.Autoupdate.PublicKeyPath)
 
 	// process directives
+	// the node can be running, so its pid file is kept
+	if len(*conf.Diagnose) > 0 {
+		if err := diagnose.WriteBundleFile(*conf.Diagnose); err != nil {
+			log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("Diagnose")
+			os.Exit(1)
+		}
+		log.WithFields(log.Fields{"path": *conf.Diagnose}).Info("Diagnostic bundle created")
+		os.Exit(0)
+	}
+
 	if *conf.GenerateFirstBlock {
 		if err := install.GenerateFirstBlock(); err != nil {
 			log.WithFields(log.Fields{"type": consts.BlockError, "error": err}).Error("GenerateFirstBlock")
@@ -270,6 +342,12 @@ func Start() {
 	}
 
 	if conf.Installed {
+		if problems := conf.ValidateConfig(true); len(problems) > 0 {
+			for _, problem := range problems {
+				log.WithFields(log.Fields{"type": consts.ConfigError, "field": problem.Field, "hint": problem.Hint}).Error(problem.Problem)
+			}
+			Exit(1)
+		}
 		if conf.Config.KeyID == 0 {
 			key, err := parser.GetKeyIDFromPrivateKey()
 			if err != nil {
@@ -283,6 +361,10 @@ func Start() {
 			}
 		}
 		initGorm(conf.Config.DB)
+		if err = model.Upgrade(); err != nil {
+			log.WithFields(log.Fields{"type": consts.MigrationError, "error": err}).Error("upgrading database")
+			Exit(1)
+		}
 
 		err = autoupdate.Run()
 		if err != nil {
@@ -292,9 +374,12 @@ func Start() {
 
 	log.WithFields(log.Fields{"work_dir": conf.Config.WorkDir, "version": consts.VERSION}).Info("started with")
 
-	killOld()
+	// the parent process of the graceful restart exits by itself
+	if !graceful.Inherited() {
+		killOld()
+	}
 
-	publisher.InitCentrifugo(conf.Config.Centrifugo)
+	publisher.InitCentrifugo(conf.Centrifugo())
 
 	initStatsd()
 
@@ -304,6 +389,14 @@ func Start() {
 		Exit(1)
 	}
 
+	if conf.Installed {
+		initConfigReload()
+	}
+	initGracefulRestart()
+	initMaintenanceSignal()
+
+	initResources()
+
 	rand.Seed(time.Now().UTC().UnixNano())
 
 	// save the current pid and version
@@ -345,7 +438,9 @@ func Start() {
 
 	daemons.WaitForSignals()
 
-	initRoutes(conf.Config.HTTP.Str())
+	initRoutes(conf.HTTPListenAddrs())
+
+	notifyReady()
 
 	select {}
 }
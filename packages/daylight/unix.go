@@ -1,5 +1,5 @@
 // +build linux freebsd darwin
-// +build 386 amd64
+// +build 386 amd64 arm arm64
 
 // MIT License
 //
@@ -26,9 +26,16 @@
 package daylight
 
 import (
+	"os"
+	"os/signal"
 	"syscall"
+	"time"
 
+	"github.com/GenesisKernel/go-genesis/packages/consts"
 	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/daemons"
+	"github.com/GenesisKernel/go-genesis/packages/graceful"
+	"github.com/GenesisKernel/go-genesis/packages/model"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -42,3 +49,49 @@ func KillPid(pid string) error {
 	}
 	return nil
 }
+
+// drainTimeout is the time given to the current requests before the old process exits
+const drainTimeout = 30 * time.Second
+
+// initGracefulRestart starts a new process on SIGUSR2 and stops the current one without dropping connections
+func initGracefulRestart() {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGUSR2)
+	go func() {
+		for range sigCh {
+			gracefulRestart()
+		}
+	}()
+}
+
+func gracefulRestart() {
+	log.Info("graceful restart")
+	// the current block has to be processed before the new process starts its daemons
+	daemons.StopAllDaemons()
+	if _, err := graceful.Restart(); err != nil {
+		log.WithFields(log.Fields{"type": consts.CommandExecutionError, "error": err}).Error("starting new process")
+		daemons.StartDaemons()
+		return
+	}
+	graceful.Drain(drainTimeout)
+	if model.DBConn != nil {
+		if err := model.GormClose(); err != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("closing gorm")
+		}
+	}
+	// the pid file belongs to the new process now
+	os.Exit(0)
+}
+
+// initMaintenanceSignal toggles the maintenance mode on SIGUSR1
+func initMaintenanceSignal() {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGUSR1)
+	go func() {
+		for range sigCh {
+			if err := daemons.SetMaintenance(!daemons.Maintenance().Enabled, "SIGUSR1"); err != nil {
+				log.WithFields(log.Fields{"type": consts.ContextError, "error": err}).Error("waiting for daemons of maintenance mode")
+			}
+		}
+	}()
+}
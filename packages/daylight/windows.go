@@ -60,3 +60,9 @@ func KillPid(pid string) error {
 	}
 	return nil
 }
+
+// initGracefulRestart does nothing because sockets cannot be passed to the new process
+func initGracefulRestart() {}
+
+// initMaintenanceSignal does nothing because there is no user signals, the maintenance mode is changed by API
+func initMaintenanceSignal() {}
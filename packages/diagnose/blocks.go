@@ -0,0 +1,63 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package diagnose
+
+import (
+	"sync"
+	"time"
+)
+
+// BlockStats is the latency of the applying of blocks in milliseconds
+type BlockStats struct {
+	Count int64   `json:"count"`
+	Last  float64 `json:"last_ms"`
+	Avg   float64 `json:"avg_ms"`
+	Max   float64 `json:"max_ms"`
+}
+
+var (
+	blockMutex sync.Mutex
+	blockStats BlockStats
+	blockTotal float64
+)
+
+// ObserveBlockApply registers the duration of the applying of the block which has been started at the specified time
+func ObserveBlockApply(start time.Time) {
+	ms := float64(time.Since(start)) / float64(time.Millisecond)
+	blockMutex.Lock()
+	defer blockMutex.Unlock()
+	blockStats.Count++
+	blockStats.Last = ms
+	if ms > blockStats.Max {
+		blockStats.Max = ms
+	}
+	blockTotal += ms
+	blockStats.Avg = blockTotal / float64(blockStats.Count)
+}
+
+// GetBlockStats returns the latency of the applying of blocks
+func GetBlockStats() BlockStats {
+	blockMutex.Lock()
+	defer blockMutex.Unlock()
+	return blockStats
+}
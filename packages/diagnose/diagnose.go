@@ -0,0 +1,195 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package diagnose
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"runtime/pprof"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/crypto"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/script"
+
+	toml "github.com/BurntSushi/toml"
+	log "github.com/sirupsen/logrus"
+)
+
+// maxLogSize is the maximum size of the tail of the log file in the bundle
+const maxLogSize = 10 << 20
+
+// RuntimeStats is the statistics of go runtime
+type RuntimeStats struct {
+	Version    string           `json:"version"`
+	GoVersion  string           `json:"go_version"`
+	Goroutines int              `json:"goroutines"`
+	CPU        int              `json:"cpu"`
+	Memory     runtime.MemStats `json:"memory"`
+	GC         debug.GCStats    `json:"gc"`
+	// CompileCache contains the counters of the cache of compiled contracts
+	CompileCache script.CacheStats `json:"compile_cache"`
+	// SignCache contains the counters of the cache of verified signatures
+	SignCache crypto.SignCacheStats `json:"sign_cache"`
+	// Blocks contains the latency of the applying of blocks
+	Blocks BlockStats `json:"blocks"`
+	// Rollbacks contains the counters of the written rollback records
+	Rollbacks model.RollbackStats `json:"rollbacks"`
+	// Lanes contains the utilization of the lanes of the generated blocks
+	Lanes LaneStats `json:"lanes"`
+}
+
+// GetRuntimeStats returns the current statistics of go runtime
+func GetRuntimeStats() *RuntimeStats {
+	stats := &RuntimeStats{
+		Version:      consts.VERSION,
+		GoVersion:    runtime.Version(),
+		Goroutines:   runtime.NumGoroutine(),
+		CPU:          runtime.NumCPU(),
+		CompileCache: script.GetCacheStats(),
+		SignCache:    crypto.GetSignCacheStats(),
+		Blocks:       GetBlockStats(),
+		Rollbacks:    model.GetRollbackStats(),
+		Lanes:        GetLaneStats(),
+	}
+	runtime.ReadMemStats(&stats.Memory)
+	debug.ReadGCStats(&stats.GC)
+	return stats
+}
+
+type bundle struct {
+	tw  *tar.Writer
+	now time.Time
+}
+
+func (b *bundle) add(name string, data []byte) error {
+	err := b.tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), ModTime: b.now})
+	if err != nil {
+		return err
+	}
+	_, err = b.tw.Write(data)
+	return err
+}
+
+func (b *bundle) addConfig() error {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(conf.RedactedConfig()); err != nil {
+		return err
+	}
+	return b.add("config.toml", buf.Bytes())
+}
+
+func (b *bundle) addStats() error {
+	data, err := json.MarshalIndent(GetRuntimeStats(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return b.add("runtime.json", data)
+}
+
+func (b *bundle) addProfiles() error {
+	for _, name := range []string{"goroutine", "heap", "threadcreate", "block", "mutex"} {
+		profile := pprof.Lookup(name)
+		if profile == nil {
+			continue
+		}
+		var buf bytes.Buffer
+		debugLevel := 0
+		if name == "goroutine" {
+			debugLevel = 2
+		}
+		if err := profile.WriteTo(&buf, debugLevel); err != nil {
+			return err
+		}
+		if err := b.add("pprof/"+name, buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *bundle) addLog() error {
+	if len(conf.Config.LogFileName) == 0 {
+		return nil
+	}
+	fileName := conf.GetLogPath()
+	f, err := os.Open(fileName)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() > maxLogSize {
+		if _, err = f.Seek(-maxLogSize, io.SeekEnd); err != nil {
+			return err
+		}
+	}
+	var buf bytes.Buffer
+	if _, err = io.Copy(&buf, f); err != nil {
+		return err
+	}
+	return b.add("log/"+filepath.Base(fileName), buf.Bytes())
+}
+
+// WriteBundle writes tar.gz archive with the redacted config, the tail of the log,
+// runtime statistics and profiles
+func WriteBundle(w io.Writer) error {
+	gz := gzip.NewWriter(w)
+	b := &bundle{tw: tar.NewWriter(gz), now: time.Now()}
+	for _, add := range []func() error{b.addConfig, b.addStats, b.addProfiles, b.addLog} {
+		if err := add(); err != nil {
+			log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("writing diagnostic bundle")
+			return err
+		}
+	}
+	if err := b.tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// WriteBundleFile writes the diagnostic bundle to the file
+func WriteBundleFile(fileName string) error {
+	f, err := os.Create(fileName)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err, "path": fileName}).Error("creating diagnostic bundle")
+		return err
+	}
+	defer f.Close()
+	return WriteBundle(f)
+}
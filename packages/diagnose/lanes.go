@@ -0,0 +1,79 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package diagnose
+
+import "sync"
+
+// LaneUsage is the filling of the lanes of one generated block
+type LaneUsage struct {
+	SystemTx     int
+	SystemSize   int64
+	OtherTx      int
+	OtherSize    int64
+	DeferredTx   int   // transactions which have been left for the next blocks
+	BlockSize    int64 // the space of block for transactions
+	ReservedSize int64 // the part of BlockSize reserved for the system lane
+}
+
+// LaneStats is the utilization of the system and other lanes of the generated blocks
+type LaneStats struct {
+	Blocks     int64 `json:"blocks"`
+	SystemTx   int64 `json:"system_tx"`
+	OtherTx    int64 `json:"other_tx"`
+	DeferredTx int64 `json:"deferred_tx"`
+	// SystemUsage is the percent of the reserved space used by system transactions in the last block
+	SystemUsage float64 `json:"system_usage"`
+	// OtherUsage is the percent of the unreserved space used by other transactions in the last block
+	OtherUsage float64 `json:"other_usage"`
+}
+
+var (
+	laneMutex sync.Mutex
+	laneStats LaneStats
+)
+
+func percent(size, total int64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return float64(size) * 100 / float64(total)
+}
+
+// ObserveLanes registers the filling of the lanes of the generated block
+func ObserveLanes(usage LaneUsage) {
+	laneMutex.Lock()
+	defer laneMutex.Unlock()
+	laneStats.Blocks++
+	laneStats.SystemTx += int64(usage.SystemTx)
+	laneStats.OtherTx += int64(usage.OtherTx)
+	laneStats.DeferredTx += int64(usage.DeferredTx)
+	laneStats.SystemUsage = percent(usage.SystemSize, usage.ReservedSize)
+	laneStats.OtherUsage = percent(usage.OtherSize, usage.BlockSize-usage.ReservedSize)
+}
+
+// GetLaneStats returns the utilization of the lanes of block building
+func GetLaneStats() LaneStats {
+	laneMutex.Lock()
+	defer laneMutex.Unlock()
+	return laneStats
+}
@@ -0,0 +1,109 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package faults injects failures at the defined points of parser, daemons and smart contracts.
+// The injection works only in the binary built with 'faults' tag, otherwise Hit does nothing,
+// so resilience scenarios can be covered by integration tests without the cost in production.
+//
+// The faults are set with Inject in tests or with GENESIS_FAULTS environment variable, e.g.
+// GENESIS_FAULTS="PeerConnect=error,BlockGenerate=delay:5s,CallContract=panic:2"
+// where the optional number after the colon is the count of hits for error and panic
+package faults
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Point is the place where the failure can be injected
+type Point string
+
+// Points of injection
+const (
+	// PlayBlock is hit before the block is applied to the database
+	PlayBlock Point = "PlayBlock"
+	// PeerConnect is hit when the node connects to other node
+	PeerConnect Point = "PeerConnect"
+	// BlockGenerate is hit before the node generates the new block
+	BlockGenerate Point = "BlockGenerate"
+	// CallContract is hit before the contract is executed
+	CallContract Point = "CallContract"
+)
+
+// EnvName is the environment variable with the faults
+const EnvName = "GENESIS_FAULTS"
+
+// ErrInjected is the default error of the fault
+var ErrInjected = errors.New("injected fault")
+
+// Fault describes the failure at the point
+type Fault struct {
+	Err   error         // the error returned by Hit
+	Panic bool          // Hit panics with Err or ErrInjected
+	Delay time.Duration // Hit sleeps before returning
+	Count int           // the number of hits, 0 means always
+}
+
+// parse converts the value of GENESIS_FAULTS to the faults
+func parse(spec string) (map[Point]Fault, error) {
+	ret := make(map[Point]Fault)
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if len(item) == 0 {
+			continue
+		}
+		pair := strings.SplitN(item, "=", 2)
+		if len(pair) != 2 {
+			return nil, fmt.Errorf("fault %s must be point=kind", item)
+		}
+		var fault Fault
+		kind := strings.SplitN(pair[1], ":", 2)
+		arg := ""
+		if len(kind) == 2 {
+			arg = kind[1]
+		}
+		switch kind[0] {
+		case "error", "panic":
+			fault.Err, fault.Panic = ErrInjected, kind[0] == "panic"
+			if len(arg) > 0 {
+				count, err := strconv.Atoi(arg)
+				if err != nil || count < 0 {
+					return nil, fmt.Errorf("wrong count of fault %s", item)
+				}
+				fault.Count = count
+			}
+		case "delay":
+			delay, err := time.ParseDuration(arg)
+			if err != nil {
+				return nil, fmt.Errorf("wrong delay of fault %s", item)
+			}
+			fault.Delay = delay
+		default:
+			return nil, fmt.Errorf("unknown kind of fault %s", item)
+		}
+		ret[Point(strings.TrimSpace(pair[0]))] = fault
+	}
+	return ret, nil
+}
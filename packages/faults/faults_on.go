@@ -0,0 +1,94 @@
+// +build faults
+
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package faults
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	mutex  sync.Mutex
+	points = make(map[Point]Fault)
+)
+
+func init() {
+	spec := os.Getenv(EnvName)
+	if len(spec) == 0 {
+		return
+	}
+	list, err := parse(spec)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.ConfigError, "error": err}).Fatal("parsing faults")
+	}
+	points = list
+}
+
+// Inject sets the fault at the point
+func Inject(point Point, fault Fault) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	points[point] = fault
+}
+
+// Reset removes all faults
+func Reset() {
+	mutex.Lock()
+	defer mutex.Unlock()
+	points = make(map[Point]Fault)
+}
+
+// Hit fires the fault at the point if it has been injected
+func Hit(point Point) error {
+	mutex.Lock()
+	fault, ok := points[point]
+	if ok && fault.Count > 0 {
+		if fault.Count--; fault.Count == 0 {
+			delete(points, point)
+		} else {
+			points[point] = fault
+		}
+	}
+	mutex.Unlock()
+	if !ok {
+		return nil
+	}
+	log.WithFields(log.Fields{"type": consts.InjectedFault, "point": point}).Warning("injected fault")
+	if fault.Delay > 0 {
+		time.Sleep(fault.Delay)
+	}
+	if fault.Panic {
+		if fault.Err == nil {
+			panic(ErrInjected)
+		}
+		panic(fault.Err)
+	}
+	return fault.Err
+}
@@ -0,0 +1,52 @@
+// +build faults
+
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package faults
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestHit(t *testing.T) {
+	defer Reset()
+	errDB := errors.New("db is down")
+	Inject(PlayBlock, Fault{Err: errDB, Count: 2})
+	for i := 0; i < 2; i++ {
+		if err := Hit(PlayBlock); err != errDB {
+			t.Errorf("expected injected error, got %v", err)
+		}
+	}
+	if err := Hit(PlayBlock); err != nil {
+		t.Errorf("fault must be removed after count of hits, got %v", err)
+	}
+
+	Inject(CallContract, Fault{Panic: true})
+	defer func() {
+		if r := recover(); r != ErrInjected {
+			t.Errorf("expected panic with ErrInjected, got %v", r)
+		}
+	}()
+	Hit(CallContract)
+}
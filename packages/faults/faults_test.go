@@ -0,0 +1,49 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package faults
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	list, err := parse("PeerConnect=error, BlockGenerate=delay:5s,CallContract=panic:2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f := list[PeerConnect]; f.Err != ErrInjected || f.Panic || f.Count != 0 {
+		t.Errorf("wrong PeerConnect fault %+v", f)
+	}
+	if f := list[BlockGenerate]; f.Delay != 5*time.Second || f.Err != nil {
+		t.Errorf("wrong BlockGenerate fault %+v", f)
+	}
+	if f := list[CallContract]; !f.Panic || f.Count != 2 {
+		t.Errorf("wrong CallContract fault %+v", f)
+	}
+	for _, spec := range []string{"PlayBlock", "PlayBlock=crash", "PlayBlock=delay:soon", "PlayBlock=error:-1"} {
+		if _, err = parse(spec); err == nil {
+			t.Errorf("expected error for %s", spec)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package graceful hands the listening sockets over to a new process of the node
+// and drains the connections of the old one
+package graceful
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// listenersEnv is the environment variable with the names and descriptors of inherited listeners
+const listenersEnv = "GENESIS_LISTENERS"
+
+// ErrNotSupported is returned if the platform cannot pass sockets to the child process
+var ErrNotSupported = errors.New("graceful restart is not supported")
+
+type filer interface {
+	File() (*os.File, error)
+}
+
+var (
+	mutex     sync.Mutex
+	listeners = make(map[string]net.Listener)
+	drainers  []func(context.Context)
+	draining  int32
+)
+
+// Inherited reports whether the process has been started by the graceful restart
+func Inherited() bool {
+	return len(os.Getenv(listenersEnv)) > 0
+}
+
+func inheritedFD(name string) (int, bool) {
+	for _, item := range strings.Split(os.Getenv(listenersEnv), ",") {
+		pair := strings.SplitN(item, ":", 2)
+		if len(pair) != 2 || pair[0] != name {
+			continue
+		}
+		fd, err := strconv.Atoi(pair[1])
+		if err != nil {
+			return 0, false
+		}
+		return fd, true
+	}
+	return 0, false
+}
+
+// Listen returns the listener with the name inherited from the parent process or creates a new one
+func Listen(name, network, addr string) (net.Listener, error) {
+	var (
+		l   net.Listener
+		err error
+	)
+	if fd, ok := inheritedFD(name); ok {
+		f := os.NewFile(uintptr(fd), name)
+		l, err = net.FileListener(f)
+		f.Close()
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.NetworkError, "error": err, "listener": name}).Error("using inherited listener")
+		} else {
+			log.WithFields(log.Fields{"listener": name, "host": addr}).Info("listener is inherited")
+		}
+	}
+	if l == nil {
+		if l, err = net.Listen(network, addr); err != nil {
+			return nil, err
+		}
+	}
+	mutex.Lock()
+	listeners[name] = l
+	mutex.Unlock()
+	return l, nil
+}
+
+// OnDrain adds the function which finishes the processing of the current requests
+func OnDrain(drain func(ctx context.Context)) {
+	mutex.Lock()
+	drainers = append(drainers, drain)
+	mutex.Unlock()
+}
+
+// Restart starts a new process of the node with the same arguments which takes over the listeners
+func Restart() (*os.Process, error) {
+	if runtime.GOOS == "windows" {
+		return nil, ErrNotSupported
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	var (
+		files []*os.File
+		names []string
+	)
+	defer func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}()
+	for name, l := range listeners {
+		fl, ok := l.(filer)
+		if !ok {
+			return nil, fmt.Errorf("listener %s cannot be passed", name)
+		}
+		f, err := fl.File()
+		if err != nil {
+			return nil, err
+		}
+		// descriptors 0, 1, 2 are used by stdin, stdout and stderr
+		names = append(names, fmt.Sprintf("%s:%d", name, len(files)+3))
+		files = append(files, f)
+	}
+
+	env := make([]string, 0, len(os.Environ())+1)
+	for _, item := range os.Environ() {
+		if !strings.HasPrefix(item, listenersEnv+"=") {
+			env = append(env, item)
+		}
+	}
+	env = append(env, listenersEnv+"="+strings.Join(names, ","))
+
+	path, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.Command(path, os.Args[1:]...)
+	cmd.Env = env
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	cmd.ExtraFiles = files
+	if err = cmd.Start(); err != nil {
+		return nil, err
+	}
+	log.WithFields(log.Fields{"pid": cmd.Process.Pid, "listeners": names}).Info("new process is started")
+	return cmd.Process, nil
+}
+
+// Draining reports whether the listeners are closed because the process is going to exit
+func Draining() bool {
+	return atomic.LoadInt32(&draining) != 0
+}
+
+// Reset forgets the closed listeners and their drainers, so the listeners can be opened again in the same process
+func Reset() {
+	mutex.Lock()
+	listeners = make(map[string]net.Listener)
+	drainers = nil
+	mutex.Unlock()
+	atomic.StoreInt32(&draining, 0)
+}
+
+// Drain stops accepting connections and waits for the current requests no longer than timeout
+func Drain(timeout time.Duration) {
+	atomic.StoreInt32(&draining, 1)
+	mutex.Lock()
+	list := drainers
+	for _, l := range listeners {
+		l.Close()
+	}
+	mutex.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	var wg sync.WaitGroup
+	for _, drain := range list {
+		wg.Add(1)
+		go func(drain func(context.Context)) {
+			defer wg.Done()
+			drain(ctx)
+		}(drain)
+	}
+	wg.Wait()
+}
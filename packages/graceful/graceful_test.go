@@ -0,0 +1,74 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package graceful
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestListenInherited(t *testing.T) {
+	parent, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer parent.Close()
+	f, err := parent.(*net.TCPListener).File()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Setenv(listenersEnv, fmt.Sprintf("api:%d", f.Fd()))
+	defer os.Unsetenv(listenersEnv)
+
+	if !Inherited() {
+		t.Error("listeners are not inherited")
+	}
+	l, err := Listen("api", "tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Addr().String() != parent.Addr().String() {
+		t.Errorf("wrong inherited address %s != %s", l.Addr(), parent.Addr())
+	}
+
+	var drained bool
+	OnDrain(func(ctx context.Context) {
+		drained = true
+	})
+	Drain(time.Second)
+	if !drained || !Draining() {
+		t.Error("drain has not been called")
+	}
+	if _, err := l.Accept(); err == nil {
+		t.Error("listener is not closed")
+	}
+
+	Reset()
+	if Draining() || len(listeners) != 0 || len(drainers) != 0 {
+		t.Error("state is not reset")
+	}
+}
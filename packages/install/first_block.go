@@ -46,6 +46,9 @@ const fileMode = 0644
 // ErrFirstBlockHostIsEmpty host for first block is not specified
 var ErrFirstBlockHostIsEmpty = errors.New("FirstBlockHost is empty")
 
+// ErrKeyExists key file already exists
+var ErrKeyExists = errors.New("key file already exists")
+
 func createKeyPair(privFilename, pubFilename string) (priv, pub []byte, err error) {
 	priv, pub, err = crypto.GenBytesKeys()
 	if err != nil {
@@ -88,7 +91,7 @@ func generateFirstBlock(publicKey, nodePublicKey []byte) error {
 		EcosystemID:  0,
 		KeyID:        conf.Config.KeyID,
 		NodePosition: 0,
-		Version:      consts.BLOCK_VERSION,
+		Version:      consts.BASE_BLOCK_VERSION,
 	}
 
 	var tx []byte
@@ -174,6 +177,26 @@ func GenerateFirstBlock() error {
 	return generateFirstBlock(publicKey, nodePublicKey)
 }
 
+// GenerateKeys creates the wallet and node key pairs in the private directory,
+// the existing keys are not overwritten
+func GenerateKeys() error {
+	pairs := [][2]string{
+		{consts.PrivateKeyFilename, consts.PublicKeyFilename},
+		{consts.NodePrivateKeyFilename, consts.NodePublicKeyFilename},
+	}
+	for _, pair := range pairs {
+		privFilename := filepath.Join(conf.Config.PrivateDir, pair[0])
+		if _, err := os.Stat(privFilename); err == nil {
+			log.WithFields(log.Fields{"type": consts.IOError, "path": privFilename}).Error("key file already exists")
+			return ErrKeyExists
+		}
+		if _, _, err := createKeyPair(privFilename, filepath.Join(conf.Config.PrivateDir, pair[1])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // IsExistFirstBlock returns a boolean indicating whether first block file exists
 func IsExistFirstBlock() bool {
 	if _, err := os.Stat(*conf.FirstBlockPath); err != nil {
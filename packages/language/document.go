@@ -0,0 +1,204 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package language
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// FormatJSON is the format of the document as the JSON object {"name": {"lang": "text"}}
+	FormatJSON = `json`
+	// FormatCSV is the format of the document as CSV with the header "name,lang1,lang2..."
+	FormatCSV = `csv`
+
+	csvNameColumn = `name`
+)
+
+// Document contains all language resources of the ecosystem as name -> language -> text
+type Document map[string]map[string]string
+
+// DocumentDiff is the difference between the current language resources and the imported document
+type DocumentDiff struct {
+	Added   []string `json:"added"`
+	Changed []string `json:"changed"`
+	Removed []string `json:"removed"`
+}
+
+// LoadDocument returns all language resources of the table with the specified prefix
+func LoadDocument(prefix string) (Document, error) {
+	languages, err := (&model.Language{}).GetAll(prefix)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("Error querying all languages")
+		return nil, err
+	}
+	doc := make(Document)
+	for _, item := range languages {
+		var res map[string]string
+		if err := json.Unmarshal([]byte(item.Res), &res); err != nil {
+			log.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "value": item.Res, "error": err}).Error("Unmarshalling json")
+			return nil, err
+		}
+		doc[item.Name] = res
+	}
+	return doc, nil
+}
+
+// Names returns the sorted names of the resources
+func (doc Document) Names() []string {
+	names := make([]string, 0, len(doc))
+	for name := range doc {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Langs returns the sorted codes of all languages of the document
+func (doc Document) Langs() []string {
+	codes := make(map[string]bool)
+	for _, res := range doc {
+		for code := range res {
+			codes[code] = true
+		}
+	}
+	langs := make([]string, 0, len(codes))
+	for code := range codes {
+		langs = append(langs, code)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Marshal returns the document in the specified format
+func (doc Document) Marshal(format string) (string, error) {
+	switch format {
+	case FormatJSON, ``:
+		out, err := json.Marshal(doc)
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Error("marshalling languages")
+		}
+		return string(out), err
+	case FormatCSV:
+		var buf bytes.Buffer
+		langs := doc.Langs()
+		w := csv.NewWriter(&buf)
+		w.Write(append([]string{csvNameColumn}, langs...))
+		for _, name := range doc.Names() {
+			row := []string{name}
+			for _, code := range langs {
+				row = append(row, doc[name][code])
+			}
+			w.Write(row)
+		}
+		w.Flush()
+		return buf.String(), w.Error()
+	}
+	return ``, fmt.Errorf(`unknown format %s`, format)
+}
+
+// ParseDocument parses the document in the specified format. Empty texts are skipped
+func ParseDocument(data, format string) (Document, error) {
+	doc := make(Document)
+	switch format {
+	case FormatJSON, ``:
+		if err := json.Unmarshal([]byte(data), &doc); err != nil {
+			return nil, fmt.Errorf(`invalid json document: %v`, err)
+		}
+	case FormatCSV:
+		records, err := csv.NewReader(strings.NewReader(data)).ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf(`invalid csv document: %v`, err)
+		}
+		if len(records) == 0 || len(records[0]) < 2 || records[0][0] != csvNameColumn {
+			return nil, fmt.Errorf(`csv header must be %s,lang1,lang2...`, csvNameColumn)
+		}
+		header := records[0]
+		for _, row := range records[1:] {
+			res := make(map[string]string)
+			for i := 1; i < len(row); i++ {
+				res[header[i]] = row[i]
+			}
+			doc[row[0]] = res
+		}
+	default:
+		return nil, fmt.Errorf(`unknown format %s`, format)
+	}
+	for name, res := range doc {
+		if len(name) == 0 || len(name) > 100 || strings.IndexByte(name, ' ') >= 0 {
+			return nil, fmt.Errorf(`invalid name of language resource '%s'`, name)
+		}
+		for code, text := range res {
+			if len(text) == 0 {
+				delete(res, code)
+			}
+		}
+		if len(res) == 0 {
+			delete(doc, name)
+		}
+	}
+	return doc, nil
+}
+
+// Diff compares the document with the current one
+func (doc Document) Diff(current Document) *DocumentDiff {
+	diff := &DocumentDiff{Added: []string{}, Changed: []string{}, Removed: []string{}}
+	for _, name := range doc.Names() {
+		cur, ok := current[name]
+		if !ok {
+			diff.Added = append(diff.Added, name)
+			continue
+		}
+		if !equalRes(cur, doc[name]) {
+			diff.Changed = append(diff.Changed, name)
+		}
+	}
+	for _, name := range current.Names() {
+		if _, ok := doc[name]; !ok {
+			diff.Removed = append(diff.Removed, name)
+		}
+	}
+	return diff
+}
+
+func equalRes(left, right map[string]string) bool {
+	if len(left) != len(right) {
+		return false
+	}
+	for code, text := range left {
+		if val, ok := right[code]; !ok || val != text {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,79 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package language
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDocument(t *testing.T) {
+	doc := Document{
+		`hello`: {`en`: `Hello`, `fr`: `Bonjour`},
+		`quote`: {`en`: `Say "yes", please`},
+	}
+	for _, format := range []string{FormatJSON, FormatCSV} {
+		out, err := doc.Marshal(format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed, err := ParseDocument(out, format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(parsed, doc) {
+			t.Errorf("%s: %v", format, parsed)
+		}
+	}
+	out, _ := doc.Marshal(FormatCSV)
+	if want := "name,en,fr\nhello,Hello,Bonjour\nquote,\"Say \"\"yes\"\", please\",\n"; out != want {
+		t.Errorf("wrong csv %q", out)
+	}
+	for _, item := range []struct{ data, format string }{
+		{`{"hello": "text"}`, FormatJSON},
+		{`{"bad name": {"en": "text"}}`, FormatJSON},
+		{"lang,en\nhello,Hello", FormatCSV},
+		{`{}`, `xml`},
+	} {
+		if _, err := ParseDocument(item.data, item.format); err == nil {
+			t.Errorf("%s must be rejected", item.data)
+		}
+	}
+}
+
+func TestDocumentDiff(t *testing.T) {
+	current := Document{
+		`hello`: {`en`: `Hello`},
+		`bye`:   {`en`: `Bye`},
+		`same`:  {`en`: `Same`},
+	}
+	doc, err := ParseDocument("name,en,fr\nhello,Hello,Bonjour\nsame,Same,\nnew,New,Nouveau\nempty,,", FormatCSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff := doc.Diff(current)
+	want := &DocumentDiff{Added: []string{`new`}, Changed: []string{`hello`}, Removed: []string{`bye`}}
+	if !reflect.DeepEqual(diff, want) {
+		t.Errorf("wrong diff %+v", diff)
+	}
+}
@@ -0,0 +1,128 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package log
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	levelsMutex     = &sync.RWMutex{}
+	globalLevel     = logrus.InfoLevel
+	subsystemLevels = make(map[string]logrus.Level)
+)
+
+// ParseLevel converts the level name from config to logrus level, INFO is used by default
+func ParseLevel(name string) logrus.Level {
+	switch strings.ToUpper(name) {
+	case "DEBUG":
+		return logrus.DebugLevel
+	case "INFO":
+		return logrus.InfoLevel
+	case "WARN", "WARNING":
+		return logrus.WarnLevel
+	case "ERROR":
+		return logrus.ErrorLevel
+	}
+	return logrus.InfoLevel
+}
+
+// LevelName returns the config name of logrus level
+func LevelName(level logrus.Level) string {
+	if level == logrus.WarnLevel {
+		return "WARN"
+	}
+	return strings.ToUpper(level.String())
+}
+
+// SetGlobalLevel sets the level for entries of subsystems without their own level
+func SetGlobalLevel(level logrus.Level) {
+	levelsMutex.Lock()
+	globalLevel = level
+	levelsMutex.Unlock()
+	updateLoggerLevel()
+}
+
+// SetSubsystemLevel sets the level for the subsystem (the name of go package which writes to log)
+func SetSubsystemLevel(subsystem string, level logrus.Level) {
+	levelsMutex.Lock()
+	subsystemLevels[subsystem] = level
+	levelsMutex.Unlock()
+	updateLoggerLevel()
+}
+
+// ResetSubsystemLevel removes the own level of the subsystem
+func ResetSubsystemLevel(subsystem string) {
+	levelsMutex.Lock()
+	delete(subsystemLevels, subsystem)
+	levelsMutex.Unlock()
+	updateLoggerLevel()
+}
+
+// GetLevels returns the global level and the levels of subsystems
+func GetLevels() (logrus.Level, map[string]logrus.Level) {
+	levelsMutex.RLock()
+	defer levelsMutex.RUnlock()
+	levels := make(map[string]logrus.Level, len(subsystemLevels))
+	for name, level := range subsystemLevels {
+		levels[name] = level
+	}
+	return globalLevel, levels
+}
+
+// isEnabled checks whether the entry of the subsystem must be written
+func isEnabled(subsystem string, level logrus.Level) bool {
+	levelsMutex.RLock()
+	defer levelsMutex.RUnlock()
+	if subLevel, ok := subsystemLevels[subsystem]; ok {
+		return level <= subLevel
+	}
+	return level <= globalLevel
+}
+
+// updateLoggerLevel sets the most verbose level to the standard logger,
+// entries are filtered out by subsystems in SinkHook
+func updateLoggerLevel() {
+	levelsMutex.RLock()
+	level := globalLevel
+	for _, subLevel := range subsystemLevels {
+		if subLevel > level {
+			level = subLevel
+		}
+	}
+	levelsMutex.RUnlock()
+	logrus.SetLevel(level)
+}
+
+// entrySubsystem returns the package name from the func field filled by ContextHook
+func entrySubsystem(entry *logrus.Entry) string {
+	if name, ok := entry.Data["func"].(string); ok {
+		if off := strings.IndexByte(name, '.'); off > 0 {
+			return name[:off]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,102 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package log
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// RotateFile is the log file which is rotated when its size exceeds MaxSize
+type RotateFile struct {
+	FileName   string
+	MaxSize    int64 // in bytes, 0 disables rotation
+	MaxBackups int   // count of rotated files fileName.1 ... fileName.N
+
+	mutex sync.Mutex
+	file  *os.File
+	size  int64
+}
+
+// OpenRotateFile opens or creates the log file
+func OpenRotateFile(fileName string, maxSize int64, maxBackups int) (*RotateFile, error) {
+	rf := &RotateFile{FileName: fileName, MaxSize: maxSize, MaxBackups: maxBackups}
+	if err := rf.open(); err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+func (rf *RotateFile) open() error {
+	f, err := os.OpenFile(rf.FileName, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	rf.file = f
+	rf.size = info.Size()
+	return nil
+}
+
+func (rf *RotateFile) rotate() error {
+	if err := rf.file.Close(); err != nil {
+		return err
+	}
+	if rf.MaxBackups > 0 {
+		for i := rf.MaxBackups - 1; i > 0; i-- {
+			os.Rename(fmt.Sprintf("%s.%d", rf.FileName, i), fmt.Sprintf("%s.%d", rf.FileName, i+1))
+		}
+		if err := os.Rename(rf.FileName, rf.FileName+".1"); err != nil {
+			return err
+		}
+	} else if err := os.Truncate(rf.FileName, 0); err != nil {
+		return err
+	}
+	return rf.open()
+}
+
+// Write writes data to the file and rotates it if it is necessary
+func (rf *RotateFile) Write(data []byte) (int, error) {
+	rf.mutex.Lock()
+	defer rf.mutex.Unlock()
+	if rf.MaxSize > 0 && rf.size+int64(len(data)) > rf.MaxSize && rf.size > 0 {
+		if err := rf.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := rf.file.Write(data)
+	rf.size += int64(n)
+	return n, err
+}
+
+// Close closes the file
+func (rf *RotateFile) Close() error {
+	rf.mutex.Lock()
+	defer rf.mutex.Unlock()
+	return rf.file.Close()
+}
@@ -0,0 +1,97 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package log
+
+import (
+	"io"
+	"io/ioutil"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Sink is the destination of log entries with its own format
+type Sink struct {
+	Name      string
+	Writer    io.Writer
+	Formatter logrus.Formatter
+}
+
+// SinkHook writes log entries to the sinks filtering them by levels of subsystems
+type SinkHook struct {
+	mutex sync.Mutex
+	sinks []*Sink
+}
+
+// NewFormatter returns json or text formatter
+func NewFormatter(format string) logrus.Formatter {
+	if format == "json" {
+		return &logrus.JSONFormatter{}
+	}
+	return &logrus.TextFormatter{DisableColors: true}
+}
+
+// Levels returns all log levels
+func (hook *SinkHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire writes the entry to all sinks
+func (hook *SinkHook) Fire(entry *logrus.Entry) error {
+	if !isEnabled(entrySubsystem(entry), entry.Level) {
+		return nil
+	}
+	hook.mutex.Lock()
+	defer hook.mutex.Unlock()
+	for _, sink := range hook.sinks {
+		data, err := sink.Formatter.Format(entry)
+		if err != nil {
+			return err
+		}
+		if _, err = sink.Writer.Write(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes the sinks which implement io.Closer
+func (hook *SinkHook) Close() {
+	hook.mutex.Lock()
+	defer hook.mutex.Unlock()
+	for _, sink := range hook.sinks {
+		if closer, ok := sink.Writer.(io.Closer); ok {
+			closer.Close()
+		}
+	}
+	hook.sinks = nil
+}
+
+// SetupSinks directs the standard logger to the specified sinks
+func SetupSinks(sinks ...*Sink) *SinkHook {
+	hook := &SinkHook{sinks: sinks}
+	logrus.SetOutput(ioutil.Discard)
+	logrus.AddHook(hook)
+	updateLoggerLevel()
+	return hook
+}
@@ -0,0 +1,119 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package messages delivers email and SMS messages of VDE ecosystems
+package messages
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"text/template"
+)
+
+const (
+	// ChannelEmail is the channel of email messages
+	ChannelEmail = "email"
+	// ChannelSMS is the channel of SMS messages
+	ChannelSMS = "sms"
+)
+
+// PermanentError is the error after which the delivery isn't retried
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+// IsPermanent returns true if the delivery of the message can't succeed
+func IsPermanent(err error) bool {
+	_, ok := err.(*PermanentError)
+	return ok
+}
+
+var errLineBreak = &PermanentError{errors.New("recipient and subject must not contain line breaks")}
+
+// Sender delivers the message to the recipient
+type Sender interface {
+	Enabled() bool
+	Send(recipient, subject, body string) error
+}
+
+var senders = map[string]Sender{
+	ChannelEmail: &smtpSender{},
+	ChannelSMS:   &twilioSender{},
+}
+
+// RegisterSender adds the sender of the channel
+func RegisterSender(channel string, sender Sender) {
+	senders[channel] = sender
+}
+
+// GetSender returns the enabled sender of the channel
+func GetSender(channel string) (Sender, bool) {
+	sender, ok := senders[channel]
+	if !ok || !sender.Enabled() {
+		return nil, false
+	}
+	return sender, true
+}
+
+// Enabled returns true if any sender is enabled
+func Enabled() bool {
+	for _, sender := range senders {
+		if sender.Enabled() {
+			return true
+		}
+	}
+	return false
+}
+
+// Render executes the template with JSON object of parameters, e.g. "Hello, {{.name}}"
+func Render(text, params string) (string, error) {
+	values := make(map[string]interface{})
+	if len(strings.TrimSpace(params)) > 0 {
+		if err := json.Unmarshal([]byte(params), &values); err != nil {
+			return "", &PermanentError{err}
+		}
+	}
+	tmpl, err := template.New("message").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", &PermanentError{err}
+	}
+	var out bytes.Buffer
+	if err = tmpl.Execute(&out, values); err != nil {
+		return "", &PermanentError{err}
+	}
+	return out.String(), nil
+}
+
+func hasLineBreak(values ...string) bool {
+	for _, value := range values {
+		if strings.ContainsAny(value, "\r\n") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package messages
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+)
+
+func TestRender(t *testing.T) {
+	cases := []struct {
+		text, params, want string
+		permanent          bool
+	}{
+		{`Hello, {{.name}}!`, `{"name": "Alice"}`, `Hello, Alice!`, false},
+		{`Amount {{.amount}} of {{.token}}`, `{"amount": "10.5", "token": "GEN"}`, `Amount 10.5 of GEN`, false},
+		{`No params`, ``, `No params`, false},
+		{`{{.missing}}`, `{}`, ``, true},
+		{`{{.name}`, `{"name": "x"}`, ``, true},
+		{`{{.name}}`, `[1, 2]`, ``, true},
+	}
+	for i, item := range cases {
+		out, err := Render(item.text, item.params)
+		if item.permanent {
+			if !IsPermanent(err) {
+				t.Errorf("case %d: permanent error is expected, got %v", i, err)
+			}
+			continue
+		}
+		if err != nil || out != item.want {
+			t.Errorf("case %d: %q %v", i, out, err)
+		}
+	}
+}
+
+func TestBuildMail(t *testing.T) {
+	out := string(buildMail("node@example.com", "user@example.com", "Привет", "body text"))
+	if !strings.Contains(out, "To: user@example.com\r\n") || !strings.HasSuffix(out, "\r\n\r\nbody text") ||
+		!strings.Contains(out, "Subject: =?utf-8?q?") {
+		t.Errorf("unexpected mail %q", out)
+	}
+	sender := &smtpSender{}
+	if err := sender.Send("user@example.com\r\nBcc: other@example.com", "subject", "body"); !IsPermanent(err) {
+		t.Errorf("line break in recipient must be rejected, got %v", err)
+	}
+}
+
+func TestTwilio(t *testing.T) {
+	old := conf.Messages()
+	defer conf.Update(func(cfg *conf.SavedConfig) { cfg.Messages = old })
+
+	var form map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, _ := r.BasicAuth()
+		if user != "AC1" || pass != "secret" || r.URL.Path != "/2010-04-01/Accounts/AC1/Messages.json" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		r.ParseForm()
+		form = map[string]string{"To": r.FormValue("To"), "From": r.FormValue("From"), "Body": r.FormValue("Body")}
+		if r.FormValue("To") == "bad" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"message": "invalid number"}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	conf.Update(func(cfg *conf.SavedConfig) {
+		cfg.Messages.Twilio = conf.TwilioConfig{AccountSID: "AC1", AuthToken: "secret", From: "+100", URL: server.URL}
+	})
+	sender, ok := GetSender(ChannelSMS)
+	if !ok {
+		t.Fatal("sms sender is not enabled")
+	}
+	if err := sender.Send("+200", "", "code 1234"); err != nil {
+		t.Fatal(err)
+	}
+	if form["To"] != "+200" || form["From"] != "+100" || form["Body"] != "code 1234" {
+		t.Errorf("unexpected request %v", form)
+	}
+	if err := sender.Send("bad", "", "text"); !IsPermanent(err) || !strings.Contains(err.Error(), "invalid number") {
+		t.Errorf("permanent error is expected, got %v", err)
+	}
+	conf.Update(func(cfg *conf.SavedConfig) { cfg.Messages.Twilio.AuthToken = "wrong" })
+	if err := sender.Send("+200", "", "text"); err == nil || IsPermanent(err) {
+		t.Errorf("temporary error is expected, got %v", err)
+	}
+}
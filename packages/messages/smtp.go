@@ -0,0 +1,71 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package messages
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"net/mail"
+	"net/smtp"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+)
+
+// smtpSender sends email messages through the mail server, STARTTLS is used if the server supports it
+type smtpSender struct{}
+
+func (s *smtpSender) Enabled() bool {
+	return len(conf.Messages().SMTP.From) > 0
+}
+
+func (s *smtpSender) Send(recipient, subject, body string) error {
+	cfg := conf.Messages().SMTP
+	if hasLineBreak(recipient, subject) {
+		return errLineBreak
+	}
+	to, err := mail.ParseAddress(recipient)
+	if err != nil {
+		return &PermanentError{err}
+	}
+	var auth smtp.Auth
+	if len(cfg.Username) > 0 {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+	return smtp.SendMail(cfg.Str(), auth, cfg.From, []string{to.Address}, buildMail(cfg.From, to.Address, subject, body))
+}
+
+// buildMail returns the plain text message with headers
+func buildMail(from, to, subject, body string) []byte {
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "From: %s\r\n", from)
+	fmt.Fprintf(&out, "To: %s\r\n", to)
+	fmt.Fprintf(&out, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&out, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	out.WriteString("MIME-Version: 1.0\r\n")
+	out.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	out.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	out.WriteString(body)
+	return out.Bytes()
+}
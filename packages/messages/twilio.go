@@ -0,0 +1,84 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package messages
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+)
+
+const (
+	twilioURL     = "https://api.twilio.com"
+	twilioTimeout = 15 * time.Second
+)
+
+// twilioSender sends SMS messages with Twilio API
+type twilioSender struct{}
+
+func (s *twilioSender) Enabled() bool {
+	return len(conf.Messages().Twilio.From) > 0
+}
+
+func (s *twilioSender) Send(recipient, subject, body string) error {
+	cfg := conf.Messages().Twilio
+	if hasLineBreak(recipient) {
+		return errLineBreak
+	}
+	apiURL := cfg.URL
+	if len(apiURL) == 0 {
+		apiURL = twilioURL
+	}
+	form := url.Values{"To": {recipient}, "From": {cfg.From}, "Body": {body}}
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s/2010-04-01/Accounts/%s/Messages.json",
+		strings.TrimRight(apiURL, "/"), url.PathEscape(cfg.AccountSID)), strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(cfg.AccountSID, cfg.AuthToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	client := &http.Client{Timeout: twilioTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusCreated {
+		return nil
+	}
+	var answer struct {
+		Message string `json:"message"`
+	}
+	json.NewDecoder(resp.Body).Decode(&answer)
+	err = fmt.Errorf("twilio status %d: %s", resp.StatusCode, answer.Message)
+	// the request is rejected, e.g. the phone number is invalid
+	if resp.StatusCode == http.StatusBadRequest {
+		return &PermanentError{err}
+	}
+	return err
+}
@@ -220,7 +220,8 @@ var (
 		"tx_hash" bytea  NOT NULL DEFAULT '',
 		"table_name" varchar(255) NOT NULL DEFAULT '',
 		"table_id" varchar(255) NOT NULL DEFAULT '',
-		"data" TEXT NOT NULL DEFAULT ''
+		"data" TEXT NOT NULL DEFAULT '',
+		"redacted" bigint NOT NULL DEFAULT '0'
 		);
 		ALTER SEQUENCE rollback_tx_id_seq owned by rollback_tx.id;
 		ALTER TABLE ONLY "rollback_tx" ADD CONSTRAINT rollback_tx_pkey PRIMARY KEY (id);
@@ -251,4 +252,1014 @@ var (
 		"stop_time" int NOT NULL DEFAULT '0'
 		);
 		`
+	migrationAuditLog = `
+		DROP SEQUENCE IF EXISTS audit_log_id_seq CASCADE;
+		CREATE SEQUENCE audit_log_id_seq START WITH 1;
+		DROP TABLE IF EXISTS "audit_log"; CREATE TABLE "audit_log" (
+		"id" bigint NOT NULL default nextval('audit_log_id_seq'),
+		"time" bigint NOT NULL DEFAULT '0',
+		"key_id" bigint NOT NULL DEFAULT '0',
+		"ecosystem" bigint NOT NULL DEFAULT '0',
+		"action" varchar(64) NOT NULL DEFAULT '',
+		"target" varchar(255) NOT NULL DEFAULT '',
+		"remote" varchar(255) NOT NULL DEFAULT '',
+		"digest" bytea NOT NULL DEFAULT ''
+		);
+		ALTER SEQUENCE audit_log_id_seq owned by audit_log.id;
+		ALTER TABLE ONLY "audit_log" ADD CONSTRAINT audit_log_pkey PRIMARY KEY (id);
+		CREATE INDEX "audit_log_index_key_id" ON "audit_log" (key_id);
+		CREATE RULE audit_log_no_update AS ON UPDATE TO "audit_log" DO INSTEAD NOTHING;
+		CREATE RULE audit_log_no_delete AS ON DELETE TO "audit_log" DO INSTEAD NOTHING;
+		`
+	migrationVDECronHTTP = `
+		DO $$
+		DECLARE t record;
+		BEGIN
+			FOR t IN SELECT table_name FROM information_schema.tables
+				WHERE table_schema = 'public' AND table_name LIKE '%\_vde\_cron' LOOP
+				EXECUTE format('ALTER TABLE %I ADD COLUMN IF NOT EXISTS "url" varchar(2048) NOT NULL DEFAULT ''''', t.table_name);
+				EXECUTE format('ALTER TABLE %I ADD COLUMN IF NOT EXISTS "method" varchar(16) NOT NULL DEFAULT ''''', t.table_name);
+				EXECUTE format('ALTER TABLE %I ADD COLUMN IF NOT EXISTS "body" text NOT NULL DEFAULT ''''', t.table_name);
+			END LOOP;
+		END $$;
+		`
+	migrationVDEHTTPParams = `
+		DO $$
+		DECLARE t record;
+		BEGIN
+			FOR t IN SELECT table_name FROM information_schema.tables
+				WHERE table_schema = 'public' AND table_name LIKE '%\_vde\_parameters' LOOP
+				EXECUTE format('INSERT INTO %1$I ("id", "name", "value", "conditions")
+					SELECT (SELECT COALESCE(max(id), 0) + 1 FROM %1$I), ''http_allowlist'', '''', ''ContractConditions("MainCondition")''
+					WHERE NOT EXISTS (SELECT 1 FROM %1$I WHERE name = ''http_allowlist'')', t.table_name);
+				EXECUTE format('INSERT INTO %1$I ("id", "name", "value", "conditions")
+					SELECT (SELECT COALESCE(max(id), 0) + 1 FROM %1$I), ''http_timeout'', ''10'', ''ContractConditions("MainCondition")''
+					WHERE NOT EXISTS (SELECT 1 FROM %1$I WHERE name = ''http_timeout'')', t.table_name);
+			END LOOP;
+		END $$;
+		`
+	migrationOracleValues = `
+		DROP TABLE IF EXISTS "oracle_values"; CREATE TABLE "oracle_values" (
+		"id" bigint NOT NULL DEFAULT '0',
+		"feed" varchar(100) NOT NULL DEFAULT '',
+		"value" text NOT NULL DEFAULT '',
+		"data_time" bigint NOT NULL DEFAULT '0',
+		"key_id" bigint NOT NULL DEFAULT '0',
+		"block_id" bigint NOT NULL DEFAULT '0',
+		"source_tx" varchar(64) NOT NULL DEFAULT '',
+		"signature" text NOT NULL DEFAULT ''
+		);
+		ALTER TABLE ONLY "oracle_values" ADD CONSTRAINT oracle_values_pkey PRIMARY KEY (id);
+		CREATE UNIQUE INDEX "oracle_values_index_feed" ON "oracle_values" (feed);
+
+		INSERT INTO system_parameters ("id","name", "value", "conditions")
+		SELECT (SELECT COALESCE(max(id), 0) + 1 FROM system_parameters), 'oracle_keys', '', 'true'
+		WHERE NOT EXISTS (SELECT 1 FROM system_parameters WHERE name = 'oracle_keys');
+		`
+	migrationBridge = `
+		DROP TABLE IF EXISTS "bridge_transfers"; CREATE TABLE "bridge_transfers" (
+		"id" bigint NOT NULL DEFAULT '0',
+		"network" bigint NOT NULL DEFAULT '0',
+		"ecosystem" bigint NOT NULL DEFAULT '0',
+		"sender" bigint NOT NULL DEFAULT '0',
+		"recipient" bigint NOT NULL DEFAULT '0',
+		"amount" decimal(30) NOT NULL DEFAULT '0',
+		"block_id" bigint NOT NULL DEFAULT '0'
+		);
+		ALTER TABLE ONLY "bridge_transfers" ADD CONSTRAINT bridge_transfers_pkey PRIMARY KEY (id);
+		CREATE INDEX "bridge_transfers_index_network" ON "bridge_transfers" (network, id);
+
+		DROP TABLE IF EXISTS "bridge_receipts"; CREATE TABLE "bridge_receipts" (
+		"id" bigint NOT NULL DEFAULT '0',
+		"network" bigint NOT NULL DEFAULT '0',
+		"transfer_id" bigint NOT NULL DEFAULT '0',
+		"recipient" bigint NOT NULL DEFAULT '0',
+		"amount" decimal(30) NOT NULL DEFAULT '0',
+		"block_id" bigint NOT NULL DEFAULT '0'
+		);
+		ALTER TABLE ONLY "bridge_receipts" ADD CONSTRAINT bridge_receipts_pkey PRIMARY KEY (id);
+		CREATE UNIQUE INDEX "bridge_receipts_index_transfer" ON "bridge_receipts" (network, transfer_id);
+
+		INSERT INTO system_parameters ("id","name", "value", "conditions")
+		SELECT (SELECT COALESCE(max(id), 0) + 1 FROM system_parameters), 'bridge_network_id', '0', 'true'
+		WHERE NOT EXISTS (SELECT 1 FROM system_parameters WHERE name = 'bridge_network_id');
+		INSERT INTO system_parameters ("id","name", "value", "conditions")
+		SELECT (SELECT COALESCE(max(id), 0) + 1 FROM system_parameters), 'bridge_networks', '', 'true'
+		WHERE NOT EXISTS (SELECT 1 FROM system_parameters WHERE name = 'bridge_networks');
+		`
+	migrationAssets = `
+		DO $$
+		DECLARE e record;
+		BEGIN
+			FOR e IN SELECT id FROM system_states LOOP
+				EXECUTE format('DROP TABLE IF EXISTS "%1$s_assets"; CREATE TABLE "%1$s_assets" (
+				"id" bigint NOT NULL DEFAULT ''0'',
+				"symbol" varchar(16) UNIQUE NOT NULL DEFAULT '''',
+				"name" varchar(255) NOT NULL DEFAULT '''',
+				"supply" decimal(30) NOT NULL DEFAULT ''0'',
+				"max_supply" decimal(30) NOT NULL DEFAULT ''0'',
+				"owner" bigint NOT NULL DEFAULT ''0'',
+				"conditions" text NOT NULL DEFAULT '''',
+				"block_id" bigint NOT NULL DEFAULT ''0''
+				);
+				ALTER TABLE ONLY "%1$s_assets" ADD CONSTRAINT "%1$s_assets_pkey" PRIMARY KEY (id);
+
+				DROP TABLE IF EXISTS "%1$s_asset_balances"; CREATE TABLE "%1$s_asset_balances" (
+				"id" bigint NOT NULL DEFAULT ''0'',
+				"asset_id" bigint NOT NULL DEFAULT ''0'',
+				"wallet" bigint NOT NULL DEFAULT ''0'',
+				"amount" decimal(30) NOT NULL DEFAULT ''0''
+				);
+				ALTER TABLE ONLY "%1$s_asset_balances" ADD CONSTRAINT "%1$s_asset_balances_pkey" PRIMARY KEY (id);
+				CREATE UNIQUE INDEX "%1$s_asset_balances_index_wallet" ON "%1$s_asset_balances" (asset_id, wallet);
+
+				DROP TABLE IF EXISTS "%1$s_asset_history"; CREATE TABLE "%1$s_asset_history" (
+				"id" bigint NOT NULL DEFAULT ''0'',
+				"asset_id" bigint NOT NULL DEFAULT ''0'',
+				"sender" bigint NOT NULL DEFAULT ''0'',
+				"recipient" bigint NOT NULL DEFAULT ''0'',
+				"amount" decimal(30) NOT NULL DEFAULT ''0'',
+				"block_id" bigint NOT NULL DEFAULT ''0''
+				);
+				ALTER TABLE ONLY "%1$s_asset_history" ADD CONSTRAINT "%1$s_asset_history_pkey" PRIMARY KEY (id);
+				CREATE INDEX "%1$s_asset_history_index_sender" ON "%1$s_asset_history" (asset_id, sender);
+				CREATE INDEX "%1$s_asset_history_index_recipient" ON "%1$s_asset_history" (asset_id, recipient);', e.id);
+			END LOOP;
+		END $$;
+		`
+	migrationNFT = `
+		DO $$
+		DECLARE e record;
+		BEGIN
+			FOR e IN SELECT id FROM system_states LOOP
+				EXECUTE format('DROP TABLE IF EXISTS "%1$s_nft_collections"; CREATE TABLE "%1$s_nft_collections" (
+				"id" bigint NOT NULL DEFAULT ''0'',
+				"name" varchar(100) UNIQUE NOT NULL DEFAULT '''',
+				"supply" bigint NOT NULL DEFAULT ''0'',
+				"max_supply" bigint NOT NULL DEFAULT ''0'',
+				"owner" bigint NOT NULL DEFAULT ''0'',
+				"mint_conditions" text NOT NULL DEFAULT '''',
+				"transfer_conditions" text NOT NULL DEFAULT '''',
+				"block_id" bigint NOT NULL DEFAULT ''0''
+				);
+				ALTER TABLE ONLY "%1$s_nft_collections" ADD CONSTRAINT "%1$s_nft_collections_pkey" PRIMARY KEY (id);
+
+				DROP TABLE IF EXISTS "%1$s_nfts"; CREATE TABLE "%1$s_nfts" (
+				"id" bigint NOT NULL DEFAULT ''0'',
+				"collection_id" bigint NOT NULL DEFAULT ''0'',
+				"owner" bigint NOT NULL DEFAULT ''0'',
+				"approved" bigint NOT NULL DEFAULT ''0'',
+				"uri" varchar(2048) NOT NULL DEFAULT '''',
+				"hash" varchar(64) NOT NULL DEFAULT '''',
+				"block_id" bigint NOT NULL DEFAULT ''0''
+				);
+				ALTER TABLE ONLY "%1$s_nfts" ADD CONSTRAINT "%1$s_nfts_pkey" PRIMARY KEY (id);
+				CREATE INDEX "%1$s_nfts_index_collection" ON "%1$s_nfts" (collection_id);
+				CREATE INDEX "%1$s_nfts_index_owner" ON "%1$s_nfts" (owner);
+
+				DROP TABLE IF EXISTS "%1$s_nft_history"; CREATE TABLE "%1$s_nft_history" (
+				"id" bigint NOT NULL DEFAULT ''0'',
+				"nft_id" bigint NOT NULL DEFAULT ''0'',
+				"sender" bigint NOT NULL DEFAULT ''0'',
+				"recipient" bigint NOT NULL DEFAULT ''0'',
+				"block_id" bigint NOT NULL DEFAULT ''0''
+				);
+				ALTER TABLE ONLY "%1$s_nft_history" ADD CONSTRAINT "%1$s_nft_history_pkey" PRIMARY KEY (id);
+				CREATE INDEX "%1$s_nft_history_index_nft" ON "%1$s_nft_history" (nft_id);', e.id);
+			END LOOP;
+		END $$;
+		`
+	migrationEscrow = `
+		DO $$
+		DECLARE e record;
+		BEGIN
+			FOR e IN SELECT id FROM system_states LOOP
+				EXECUTE format('DROP TABLE IF EXISTS "%1$s_escrows"; CREATE TABLE "%1$s_escrows" (
+				"id" bigint NOT NULL DEFAULT ''0'',
+				"sender" bigint NOT NULL DEFAULT ''0'',
+				"recipient" bigint NOT NULL DEFAULT ''0'',
+				"arbiter" bigint NOT NULL DEFAULT ''0'',
+				"amount" decimal(30) NOT NULL DEFAULT ''0'',
+				"status" bigint NOT NULL DEFAULT ''0'',
+				"block_id" bigint NOT NULL DEFAULT ''0''
+				);
+				ALTER TABLE ONLY "%1$s_escrows" ADD CONSTRAINT "%1$s_escrows_pkey" PRIMARY KEY (id);
+
+				DROP TABLE IF EXISTS "%1$s_timelocks"; CREATE TABLE "%1$s_timelocks" (
+				"id" bigint NOT NULL DEFAULT ''0'',
+				"sender" bigint NOT NULL DEFAULT ''0'',
+				"recipient" bigint NOT NULL DEFAULT ''0'',
+				"amount" decimal(30) NOT NULL DEFAULT ''0'',
+				"unlock_block" bigint NOT NULL DEFAULT ''0'',
+				"unlock_time" bigint NOT NULL DEFAULT ''0'',
+				"claimed" bigint NOT NULL DEFAULT ''0'',
+				"block_id" bigint NOT NULL DEFAULT ''0''
+				);
+				ALTER TABLE ONLY "%1$s_timelocks" ADD CONSTRAINT "%1$s_timelocks_pkey" PRIMARY KEY (id);
+				CREATE INDEX "%1$s_timelocks_index_recipient" ON "%1$s_timelocks" (recipient);', e.id);
+			END LOOP;
+		END $$;
+		`
+	migrationFeePolicy = `
+		ALTER TABLE "transactions_status" ADD COLUMN IF NOT EXISTS "payer" bigint NOT NULL DEFAULT '0';
+
+		DO $$
+		DECLARE e record;
+		BEGIN
+			FOR e IN SELECT id FROM system_states LOOP
+				EXECUTE format('INSERT INTO "%1$s_parameters" ("id", "name", "value", "conditions")
+					SELECT (SELECT COALESCE(max(id), 0) + 1 FROM "%1$s_parameters"), ''fee_payer'', ''contract'', ''ContractConditions("MainCondition")''
+					WHERE NOT EXISTS (SELECT 1 FROM "%1$s_parameters" WHERE name = ''fee_payer'')', e.id);
+				EXECUTE format('INSERT INTO "%1$s_parameters" ("id", "name", "value", "conditions")
+					SELECT (SELECT COALESCE(max(id), 0) + 1 FROM "%1$s_parameters"), ''fee_sponsor'', ''0'', ''ContractConditions("MainCondition")''
+					WHERE NOT EXISTS (SELECT 1 FROM "%1$s_parameters" WHERE name = ''fee_sponsor'')', e.id);
+			END LOOP;
+		END $$;
+		`
+	migrationGovernance = `
+		DO $$
+		DECLARE e record;
+		BEGIN
+			FOR e IN SELECT id FROM system_states LOOP
+				EXECUTE format('DROP TABLE IF EXISTS "%1$s_proposals"; CREATE TABLE "%1$s_proposals" (
+				"id" bigint NOT NULL DEFAULT ''0'',
+				"creator" bigint NOT NULL DEFAULT ''0'',
+				"title" varchar(255) NOT NULL DEFAULT '''',
+				"description" text NOT NULL DEFAULT '''',
+				"strategy" varchar(32) NOT NULL DEFAULT '''',
+				"role_id" bigint NOT NULL DEFAULT ''0'',
+				"quorum" decimal(30) NOT NULL DEFAULT ''0'',
+				"threshold" bigint NOT NULL DEFAULT ''0'',
+				"end_block" bigint NOT NULL DEFAULT ''0'',
+				"param_name" varchar(255) NOT NULL DEFAULT '''',
+				"param_value" text NOT NULL DEFAULT '''',
+				"apply_block" bigint NOT NULL DEFAULT ''0'',
+				"status" bigint NOT NULL DEFAULT ''0'',
+				"votes_for" decimal(30) NOT NULL DEFAULT ''0'',
+				"votes_against" decimal(30) NOT NULL DEFAULT ''0'',
+				"block_id" bigint NOT NULL DEFAULT ''0''
+				);
+				ALTER TABLE ONLY "%1$s_proposals" ADD CONSTRAINT "%1$s_proposals_pkey" PRIMARY KEY (id);
+				CREATE INDEX "%1$s_proposals_index_status" ON "%1$s_proposals" (status);
+
+				DROP TABLE IF EXISTS "%1$s_proposal_votes"; CREATE TABLE "%1$s_proposal_votes" (
+				"id" bigint NOT NULL DEFAULT ''0'',
+				"proposal_id" bigint NOT NULL DEFAULT ''0'',
+				"voter" bigint NOT NULL DEFAULT ''0'',
+				"weight" decimal(30) NOT NULL DEFAULT ''0'',
+				"approve" bigint NOT NULL DEFAULT ''0'',
+				"block_id" bigint NOT NULL DEFAULT ''0''
+				);
+				ALTER TABLE ONLY "%1$s_proposal_votes" ADD CONSTRAINT "%1$s_proposal_votes_pkey" PRIMARY KEY (id);
+				CREATE UNIQUE INDEX "%1$s_proposal_votes_index_voter" ON "%1$s_proposal_votes" (proposal_id, voter);', e.id);
+				EXECUTE format('INSERT INTO "%1$s_parameters" ("id", "name", "value", "conditions")
+					SELECT (SELECT COALESCE(max(id), 0) + 1 FROM "%1$s_parameters"), ''governance_strategy'', ''balance'', ''ContractConditions("MainCondition")''
+					WHERE NOT EXISTS (SELECT 1 FROM "%1$s_parameters" WHERE name = ''governance_strategy'')', e.id);
+				EXECUTE format('INSERT INTO "%1$s_parameters" ("id", "name", "value", "conditions")
+					SELECT (SELECT COALESCE(max(id), 0) + 1 FROM "%1$s_parameters"), ''governance_role'', ''0'', ''ContractConditions("MainCondition")''
+					WHERE NOT EXISTS (SELECT 1 FROM "%1$s_parameters" WHERE name = ''governance_role'')', e.id);
+				EXECUTE format('INSERT INTO "%1$s_parameters" ("id", "name", "value", "conditions")
+					SELECT (SELECT COALESCE(max(id), 0) + 1 FROM "%1$s_parameters"), ''governance_quorum'', ''0'', ''ContractConditions("MainCondition")''
+					WHERE NOT EXISTS (SELECT 1 FROM "%1$s_parameters" WHERE name = ''governance_quorum'')', e.id);
+				EXECUTE format('INSERT INTO "%1$s_parameters" ("id", "name", "value", "conditions")
+					SELECT (SELECT COALESCE(max(id), 0) + 1 FROM "%1$s_parameters"), ''governance_threshold'', ''50'', ''ContractConditions("MainCondition")''
+					WHERE NOT EXISTS (SELECT 1 FROM "%1$s_parameters" WHERE name = ''governance_threshold'')', e.id);
+			END LOOP;
+		END $$;
+		`
+	migrationRoleHierarchy = `
+		DO $$
+		DECLARE e record;
+		BEGIN
+			FOR e IN SELECT id FROM system_states LOOP
+				EXECUTE format('ALTER TABLE "%1$s_roles_list" ADD COLUMN IF NOT EXISTS "parent_id" bigint NOT NULL DEFAULT ''0'';
+				UPDATE "%1$s_tables" SET columns = columns || ''{"parent_id": "ContractConditions(\"MainCondition\")"}''::jsonb
+				WHERE name = ''roles_list''', e.id);
+			END LOOP;
+		END $$;
+		`
+	migrationDataKeys = `
+		DO $$
+		DECLARE e record;
+		BEGIN
+			FOR e IN SELECT id FROM system_states LOOP
+				IF to_regclass(format('"%s_vde_tables"', e.id)) IS NOT NULL THEN
+					EXECUTE format('CREATE TABLE IF NOT EXISTS "%1$s_vde_data_keys" (
+					"id" bigint NOT NULL DEFAULT ''0'',
+					"data_key" bytea NOT NULL DEFAULT '''',
+					"created_at" bigint NOT NULL DEFAULT ''0'',
+					CONSTRAINT "%1$s_vde_data_keys_pkey" PRIMARY KEY (id)
+					)', e.id);
+				END IF;
+			END LOOP;
+		END $$;
+		`
+	migrationErasure = `
+		ALTER TABLE "rollback_tx" ADD COLUMN IF NOT EXISTS "redacted" bigint NOT NULL DEFAULT '0';
+		`
+	migrationIdentityKeys = `
+		DROP SEQUENCE IF EXISTS identity_keys_id_seq CASCADE;
+		CREATE SEQUENCE identity_keys_id_seq START WITH 1;
+		DROP TABLE IF EXISTS "identity_keys"; CREATE TABLE "identity_keys" (
+		"id" bigint NOT NULL default nextval('identity_keys_id_seq'),
+		"provider" varchar(32) NOT NULL DEFAULT '',
+		"identity" varchar(255) NOT NULL DEFAULT '',
+		"ecosystem" bigint NOT NULL DEFAULT '0',
+		"key_id" bigint NOT NULL DEFAULT '0'
+		);
+		ALTER SEQUENCE identity_keys_id_seq owned by identity_keys.id;
+		ALTER TABLE ONLY "identity_keys" ADD CONSTRAINT identity_keys_pkey PRIMARY KEY (id);
+		CREATE UNIQUE INDEX "identity_keys_index_identity" ON "identity_keys" (provider, identity, ecosystem);
+		`
+	migrationSessions = `
+		DROP TABLE IF EXISTS "sessions"; CREATE TABLE "sessions" (
+		"id" varchar(32) NOT NULL DEFAULT '',
+		"key_id" bigint NOT NULL DEFAULT '0',
+		"ecosystem" bigint NOT NULL DEFAULT '0',
+		"device" varchar(255) NOT NULL DEFAULT '',
+		"remote" varchar(255) NOT NULL DEFAULT '',
+		"created" bigint NOT NULL DEFAULT '0',
+		"last_seen" bigint NOT NULL DEFAULT '0',
+		"expire" bigint NOT NULL DEFAULT '0',
+		"revoked" bigint NOT NULL DEFAULT '0'
+		);
+		ALTER TABLE ONLY "sessions" ADD CONSTRAINT sessions_pkey PRIMARY KEY (id);
+		CREATE INDEX "sessions_index_key_id" ON "sessions" (key_id, ecosystem);
+		CREATE INDEX "sessions_index_revoked" ON "sessions" (revoked, expire);
+		`
+	migrationVDEMessages = `
+		DO $$
+		DECLARE
+			e record;
+			perms text := '{"insert": "ContractConditions(\"MainCondition\")", "update": "ContractConditions(\"MainCondition\")",
+				"new_column": "ContractConditions(\"MainCondition\")"}';
+			cols text := '{"channel": "ContractConditions(\"MainCondition\")",
+				"recipient": "ContractConditions(\"MainCondition\")",
+				"subject": "ContractConditions(\"MainCondition\")",
+				"body": "ContractConditions(\"MainCondition\")",
+				"params": "ContractConditions(\"MainCondition\")",
+				"status": "ContractConditions(\"MainCondition\")",
+				"attempts": "ContractConditions(\"MainCondition\")",
+				"next_attempt": "ContractConditions(\"MainCondition\")",
+				"error": "ContractConditions(\"MainCondition\")",
+				"sent_at": "ContractConditions(\"MainCondition\")"}';
+		BEGIN
+			FOR e IN SELECT id FROM system_states LOOP
+				IF to_regclass(format('"%s_vde_tables"', e.id)) IS NOT NULL THEN
+					EXECUTE format('CREATE TABLE IF NOT EXISTS "%1$s_vde_messages" (
+					"id" bigint NOT NULL DEFAULT ''0'',
+					"channel" varchar(16) NOT NULL DEFAULT '''',
+					"recipient" varchar(255) NOT NULL DEFAULT '''',
+					"subject" varchar(255) NOT NULL DEFAULT '''',
+					"body" text NOT NULL DEFAULT '''',
+					"params" text NOT NULL DEFAULT '''',
+					"status" bigint NOT NULL DEFAULT ''0'',
+					"attempts" bigint NOT NULL DEFAULT ''0'',
+					"next_attempt" bigint NOT NULL DEFAULT ''0'',
+					"error" text NOT NULL DEFAULT '''',
+					"sent_at" bigint NOT NULL DEFAULT ''0'',
+					CONSTRAINT "%1$s_vde_messages_pkey" PRIMARY KEY (id)
+					)', e.id);
+					EXECUTE format('CREATE INDEX IF NOT EXISTS "%1$s_vde_messages_index_status" ON "%1$s_vde_messages" (status, next_attempt)', e.id);
+					EXECUTE format('INSERT INTO "%1$s_vde_tables" ("id", "name", "permissions", "columns", "conditions")
+					SELECT (SELECT COALESCE(max(id),0)+1 FROM "%1$s_vde_tables"), ''messages'', $1::jsonb, $2::jsonb,
+					''ContractConditions("MainCondition")''
+					WHERE NOT EXISTS (SELECT 1 FROM "%1$s_vde_tables" WHERE name = ''messages'')', e.id) USING perms, cols;
+				END IF;
+			END LOOP;
+		END $$;
+		`
+	migrationMoneyFormat = `
+		DO $$
+		DECLARE e record;
+		BEGIN
+			FOR e IN SELECT id FROM system_states LOOP
+				EXECUTE format('INSERT INTO "%1$s_parameters" ("id", "name", "value", "conditions")
+					SELECT (SELECT COALESCE(max(id), 0) + 1 FROM "%1$s_parameters"), ''money_decimal_separator'', ''.'', ''ContractConditions("MainCondition")''
+					WHERE NOT EXISTS (SELECT 1 FROM "%1$s_parameters" WHERE name = ''money_decimal_separator'')', e.id);
+				EXECUTE format('INSERT INTO "%1$s_parameters" ("id", "name", "value", "conditions")
+					SELECT (SELECT COALESCE(max(id), 0) + 1 FROM "%1$s_parameters"), ''money_thousands_separator'', '','', ''ContractConditions("MainCondition")''
+					WHERE NOT EXISTS (SELECT 1 FROM "%1$s_parameters" WHERE name = ''money_thousands_separator'')', e.id);
+				EXECUTE format('INSERT INTO "%1$s_parameters" ("id", "name", "value", "conditions")
+					SELECT (SELECT COALESCE(max(id), 0) + 1 FROM "%1$s_parameters"), ''money_symbol'', '''', ''ContractConditions("MainCondition")''
+					WHERE NOT EXISTS (SELECT 1 FROM "%1$s_parameters" WHERE name = ''money_symbol'')', e.id);
+				EXECUTE format('INSERT INTO "%1$s_parameters" ("id", "name", "value", "conditions")
+					SELECT (SELECT COALESCE(max(id), 0) + 1 FROM "%1$s_parameters"), ''money_symbol_position'', ''before'', ''ContractConditions("MainCondition")''
+					WHERE NOT EXISTS (SELECT 1 FROM "%1$s_parameters" WHERE name = ''money_symbol_position'')', e.id);
+			END LOOP;
+		END $$;
+		`
+	migrationBlockStateHash = `
+		ALTER TABLE "block_chain" ADD COLUMN IF NOT EXISTS "state_hash" bytea NOT NULL DEFAULT '';
+		`
+	migrationEcosystemQuota = `
+		ALTER TABLE "system_states" ADD COLUMN IF NOT EXISTS "key_id" bigint NOT NULL DEFAULT '0';
+		CREATE INDEX IF NOT EXISTS "system_states_index_key" ON "system_states" (key_id);
+
+		DO $$
+		DECLARE e record;
+		BEGIN
+			FOR e IN SELECT id FROM system_states WHERE key_id = 0 LOOP
+				EXECUTE format('UPDATE system_states SET key_id = COALESCE((SELECT value::bigint FROM "%1$s_parameters"
+					WHERE name = ''founder_account'' AND value ~ ''^-?[0-9]+$''), 0) WHERE id = %1$s', e.id);
+			END LOOP;
+		END $$;
+
+		INSERT INTO system_parameters ("id","name", "value", "conditions")
+		SELECT (SELECT COALESCE(max(id), 0) + 1 FROM system_parameters), 'max_ecosystems_per_key', '0', 'true'
+		WHERE NOT EXISTS (SELECT 1 FROM system_parameters WHERE name = 'max_ecosystems_per_key');
+		INSERT INTO system_parameters ("id","name", "value", "conditions")
+		SELECT (SELECT COALESCE(max(id), 0) + 1 FROM system_parameters), 'ecosystem_fee', '0', 'true'
+		WHERE NOT EXISTS (SELECT 1 FROM system_parameters WHERE name = 'ecosystem_fee');
+		INSERT INTO system_parameters ("id","name", "value", "conditions")
+		SELECT (SELECT COALESCE(max(id), 0) + 1 FROM system_parameters), 'ecosystem_treasury', '', 'true'
+		WHERE NOT EXISTS (SELECT 1 FROM system_parameters WHERE name = 'ecosystem_treasury');
+		INSERT INTO system_parameters ("id","name", "value", "conditions")
+		SELECT (SELECT COALESCE(max(id), 0) + 1 FROM system_parameters), 'ecosystem_billing_contract', '', 'true'
+		WHERE NOT EXISTS (SELECT 1 FROM system_parameters WHERE name = 'ecosystem_billing_contract');
+		`
+	migrationEcosystemFreeze = `
+		ALTER TABLE "system_states" ADD COLUMN IF NOT EXISTS "frozen" bigint NOT NULL DEFAULT '0';
+		ALTER TABLE "system_states" ADD COLUMN IF NOT EXISTS "unfreeze_contract" varchar(255) NOT NULL DEFAULT '';
+		ALTER TABLE "system_states" ADD COLUMN IF NOT EXISTS "archived" bigint NOT NULL DEFAULT '0';
+		`
+	migrationContractCalls = `
+		DROP SEQUENCE IF EXISTS contract_calls_id_seq CASCADE;
+		CREATE SEQUENCE contract_calls_id_seq START WITH 1;
+		DROP TABLE IF EXISTS "contract_calls"; CREATE TABLE "contract_calls" (
+		"id" bigint NOT NULL default nextval('contract_calls_id_seq'),
+		"hash" bytea NOT NULL DEFAULT '',
+		"key_id" bigint NOT NULL DEFAULT '0',
+		"ecosystem" bigint NOT NULL DEFAULT '0',
+		"contract" varchar(255) NOT NULL DEFAULT '',
+		"params_hash" bytea NOT NULL DEFAULT '',
+		"block_id" bigint NOT NULL DEFAULT '0',
+		"time" bigint NOT NULL DEFAULT '0',
+		"status" bigint NOT NULL DEFAULT '0',
+		"error" varchar(255) NOT NULL DEFAULT ''
+		);
+		ALTER SEQUENCE contract_calls_id_seq owned by contract_calls.id;
+		ALTER TABLE ONLY "contract_calls" ADD CONSTRAINT contract_calls_pkey PRIMARY KEY (id);
+		CREATE INDEX "contract_calls_index_key" ON "contract_calls" (key_id, id);
+		CREATE INDEX "contract_calls_index_contract" ON "contract_calls" (key_id, contract, id);
+		CREATE INDEX "contract_calls_index_hash" ON "contract_calls" (hash);
+		`
+	migrationAccountNames = `
+		DO $$
+		DECLARE e record;
+		BEGIN
+			FOR e IN SELECT id FROM system_states LOOP
+				EXECUTE format('CREATE TABLE IF NOT EXISTS "%1$s_names" (
+				"id" bigint NOT NULL DEFAULT ''0'',
+				"name" varchar(64) UNIQUE NOT NULL DEFAULT '''',
+				"key_id" bigint NOT NULL DEFAULT ''0'',
+				"expire" bigint NOT NULL DEFAULT ''0'',
+				"block_id" bigint NOT NULL DEFAULT ''0'',
+				CONSTRAINT "%1$s_names_pkey" PRIMARY KEY (id)
+				);
+				CREATE INDEX IF NOT EXISTS "%1$s_names_index_key" ON "%1$s_names" (key_id);', e.id);
+			END LOOP;
+		END $$;
+
+		INSERT INTO system_parameters ("id","name", "value", "conditions")
+		SELECT (SELECT COALESCE(max(id), 0) + 1 FROM system_parameters), 'name_registration_period', '31536000', 'true'
+		WHERE NOT EXISTS (SELECT 1 FROM system_parameters WHERE name = 'name_registration_period');
+		`
+	migrationKeyNonces = `
+		DROP TABLE IF EXISTS "key_nonces"; CREATE TABLE "key_nonces" (
+		"key_id" bigint NOT NULL DEFAULT '0',
+		"nonce" bigint NOT NULL DEFAULT '0',
+		"hash" bytea NOT NULL DEFAULT '',
+		"block_id" bigint NOT NULL DEFAULT '0'
+		);
+		ALTER TABLE ONLY "key_nonces" ADD CONSTRAINT key_nonces_pkey PRIMARY KEY (key_id, nonce);
+		CREATE INDEX "key_nonces_index_hash" ON "key_nonces" (hash);
+
+		INSERT INTO system_parameters ("id","name", "value", "conditions")
+		SELECT (SELECT COALESCE(max(id), 0) + 1 FROM system_parameters), 'tx_nonce_window', '64', 'true'
+		WHERE NOT EXISTS (SELECT 1 FROM system_parameters WHERE name = 'tx_nonce_window');
+		INSERT INTO system_parameters ("id","name", "value", "conditions")
+		SELECT (SELECT COALESCE(max(id), 0) + 1 FROM system_parameters), 'tx_nonce_required', '0', 'true'
+		WHERE NOT EXISTS (SELECT 1 FROM system_parameters WHERE name = 'tx_nonce_required');
+		`
+	migrationMaxTxSizeByType = `
+		INSERT INTO system_parameters ("id","name", "value", "conditions")
+		SELECT (SELECT COALESCE(max(id), 0) + 1 FROM system_parameters), 'max_tx_size_by_type', '{}', 'true'
+		WHERE NOT EXISTS (SELECT 1 FROM system_parameters WHERE name = 'max_tx_size_by_type');
+		`
+	migrationSystemTxLanes = `
+		INSERT INTO system_parameters ("id","name", "value", "conditions")
+		SELECT (SELECT COALESCE(max(id), 0) + 1 FROM system_parameters), 'system_tx_contracts', '["@1UpdateSysParam"]', 'true'
+		WHERE NOT EXISTS (SELECT 1 FROM system_parameters WHERE name = 'system_tx_contracts');
+		INSERT INTO system_parameters ("id","name", "value", "conditions")
+		SELECT (SELECT COALESCE(max(id), 0) + 1 FROM system_parameters), 'system_tx_reserve', '10', 'true'
+		WHERE NOT EXISTS (SELECT 1 FROM system_parameters WHERE name = 'system_tx_reserve');
+		`
+	migrationNetworkCA = `
+		INSERT INTO system_parameters ("id","name", "value", "conditions")
+		SELECT (SELECT COALESCE(max(id), 0) + 1 FROM system_parameters), 'network_ca', '', 'true'
+		WHERE NOT EXISTS (SELECT 1 FROM system_parameters WHERE name = 'network_ca');
+		`
+	migrationTxExpiration = `
+		ALTER TABLE "transactions_status" ADD COLUMN IF NOT EXISTS "expire_block" bigint NOT NULL DEFAULT '0';
+		ALTER TABLE "transactions_status" ADD COLUMN IF NOT EXISTS "expire_time" bigint NOT NULL DEFAULT '0';
+		`
+	migrationApplications = `
+		DO $$
+		DECLARE e record;
+		BEGIN
+			FOR e IN SELECT id FROM system_states LOOP
+				EXECUTE format('DROP TABLE IF EXISTS "%1$s_applications"; CREATE TABLE "%1$s_applications" (
+				"id" bigint NOT NULL DEFAULT ''0'',
+				"name" varchar(100) UNIQUE NOT NULL DEFAULT '''',
+				"version" varchar(32) NOT NULL DEFAULT '''',
+				"manifest" text NOT NULL DEFAULT '''',
+				"block_id" bigint NOT NULL DEFAULT ''0''
+				);
+				ALTER TABLE ONLY "%1$s_applications" ADD CONSTRAINT "%1$s_applications_pkey" PRIMARY KEY (id);', e.id);
+			END LOOP;
+		END $$;
+		`
+	migrationNodeUpgrades = `
+		DROP SEQUENCE IF EXISTS node_upgrades_id_seq CASCADE;
+		CREATE SEQUENCE node_upgrades_id_seq START WITH 1;
+		DROP TABLE IF EXISTS "node_upgrades";
+		CREATE TABLE "node_upgrades" (
+			"id" int NOT NULL default nextval('node_upgrades_id_seq'),
+			"name" varchar(255) UNIQUE NOT NULL,
+			"version" varchar(255) NOT NULL,
+			"date_applied" int NOT NULL
+		);
+		ALTER SEQUENCE node_upgrades_id_seq owned by node_upgrades.id;
+		ALTER TABLE ONLY "node_upgrades" ADD CONSTRAINT node_upgrades_pkey PRIMARY KEY (id);
+		`
+	migrationSysParamSchedule = `
+		DROP TABLE IF EXISTS "system_parameters_schedule"; CREATE TABLE "system_parameters_schedule" (
+		"id" bigint NOT NULL DEFAULT '0',
+		"name" varchar(255) NOT NULL DEFAULT '',
+		"value" text NOT NULL DEFAULT '',
+		"conditions" text NOT NULL DEFAULT '',
+		"activation_block" bigint NOT NULL DEFAULT '0',
+		"applied_block" bigint NOT NULL DEFAULT '0',
+		"prev_value" text NOT NULL DEFAULT '',
+		"prev_conditions" text NOT NULL DEFAULT ''
+		);
+		ALTER TABLE ONLY "system_parameters_schedule" ADD CONSTRAINT system_parameters_schedule_pkey PRIMARY KEY (id);
+		CREATE INDEX "system_parameters_schedule_index_activation" ON "system_parameters_schedule" (applied_block, activation_block);
+		`
+	migrationDomains = `
+		DROP TABLE IF EXISTS "ecosystem_domains"; CREATE TABLE "ecosystem_domains" (
+		"id" bigint NOT NULL DEFAULT '0',
+		"host" varchar(255) UNIQUE NOT NULL DEFAULT '',
+		"ecosystem" bigint NOT NULL DEFAULT '0',
+		"vde" bigint NOT NULL DEFAULT '0',
+		"page" varchar(255) NOT NULL DEFAULT '',
+		"block_id" bigint NOT NULL DEFAULT '0'
+		);
+		ALTER TABLE ONLY "ecosystem_domains" ADD CONSTRAINT ecosystem_domains_pkey PRIMARY KEY (id);
+		CREATE INDEX "ecosystem_domains_index_ecosystem" ON "ecosystem_domains" (ecosystem);
+		`
+	migrationBinaries = `
+		DO $$
+		DECLARE e record;
+		BEGIN
+			FOR e IN SELECT id FROM system_states LOOP
+				EXECUTE format('DROP TABLE IF EXISTS "%1$s_binaries"; CREATE TABLE "%1$s_binaries" (
+				"id" bigint NOT NULL DEFAULT ''0'',
+				"app" varchar(100) NOT NULL DEFAULT '''',
+				"name" varchar(255) NOT NULL DEFAULT '''',
+				"version" varchar(32) NOT NULL DEFAULT '''',
+				"mime_type" varchar(255) NOT NULL DEFAULT '''',
+				"data" bytea NOT NULL DEFAULT '''',
+				"checksum" varchar(64) NOT NULL DEFAULT '''',
+				"block_id" bigint NOT NULL DEFAULT ''0''
+				);
+				ALTER TABLE ONLY "%1$s_binaries" ADD CONSTRAINT "%1$s_binaries_pkey" PRIMARY KEY (id);
+				CREATE UNIQUE INDEX "%1$s_binaries_index_name" ON "%1$s_binaries" (app, name);', e.id);
+			END LOOP;
+		END $$;
+		`
+	migrationMaxCallDepth = `
+		INSERT INTO system_parameters ("id","name", "value", "conditions")
+		SELECT (SELECT COALESCE(max(id), 0) + 1 FROM system_parameters), 'max_call_depth', '32', 'true'
+		WHERE NOT EXISTS (SELECT 1 FROM system_parameters WHERE name = 'max_call_depth');
+		`
+	migrationSchemaRollback = `
+		CREATE SEQUENCE IF NOT EXISTS rollback_schema_id_seq START WITH 1;
+		CREATE TABLE IF NOT EXISTS "rollback_schema" (
+		"id" bigint NOT NULL default nextval('rollback_schema_id_seq'),
+		"block_id" bigint NOT NULL DEFAULT '0',
+		"tx_hash" bytea NOT NULL DEFAULT '',
+		"query" text NOT NULL DEFAULT '',
+		"data" text NOT NULL DEFAULT '',
+		CONSTRAINT rollback_schema_pkey PRIMARY KEY (id)
+		);
+		ALTER SEQUENCE rollback_schema_id_seq owned by rollback_schema.id;
+		CREATE INDEX IF NOT EXISTS "rollback_schema_index_tx" ON "rollback_schema" (tx_hash);
+		`
+	migrationMaxAggregateRows = `
+		INSERT INTO system_parameters ("id","name", "value", "conditions")
+		SELECT (SELECT COALESCE(max(id), 0) + 1 FROM system_parameters), 'max_aggregate_rows', '10000', 'true'
+		WHERE NOT EXISTS (SELECT 1 FROM system_parameters WHERE name = 'max_aggregate_rows');
+		`
+	migrationFeeRouting = `
+		INSERT INTO system_parameters ("id","name", "value", "conditions")
+		SELECT (SELECT COALESCE(max(id), 0) + 1 FROM system_parameters), 'fee_routing', '', 'true'
+		WHERE NOT EXISTS (SELECT 1 FROM system_parameters WHERE name = 'fee_routing');
+		`
+	migrationValidatorRewards = `
+		CREATE TABLE IF NOT EXISTS "validator_rewards" (
+		"id" bigint NOT NULL DEFAULT '0',
+		"block_id" bigint NOT NULL DEFAULT '0',
+		"key_id" bigint NOT NULL DEFAULT '0',
+		"ecosystem" bigint NOT NULL DEFAULT '1',
+		"role" varchar(16) NOT NULL DEFAULT '',
+		"amount" decimal(30) NOT NULL DEFAULT '0',
+		CONSTRAINT validator_rewards_pkey PRIMARY KEY (id)
+		);
+		CREATE UNIQUE INDEX IF NOT EXISTS "validator_rewards_index_block" ON "validator_rewards" (block_id, key_id, ecosystem, role);
+		CREATE INDEX IF NOT EXISTS "validator_rewards_index_key" ON "validator_rewards" (key_id, ecosystem);
+		CREATE TABLE IF NOT EXISTS "validator_reward_claims" (
+		"id" bigint NOT NULL DEFAULT '0',
+		"key_id" bigint NOT NULL DEFAULT '0',
+		"ecosystem" bigint NOT NULL DEFAULT '1',
+		"amount" decimal(30) NOT NULL DEFAULT '0',
+		"block_id" bigint NOT NULL DEFAULT '0',
+		CONSTRAINT validator_reward_claims_pkey PRIMARY KEY (id)
+		);
+		CREATE INDEX IF NOT EXISTS "validator_reward_claims_index_key" ON "validator_reward_claims" (key_id, ecosystem);
+		`
+	migrationStaking = `
+		CREATE TABLE IF NOT EXISTS "validator_candidates" (
+		"id" bigint NOT NULL DEFAULT '0',
+		"host" varchar(255) NOT NULL DEFAULT '',
+		"public_key" varchar(128) NOT NULL DEFAULT '',
+		"cert" text NOT NULL DEFAULT '',
+		"stake" decimal(30) NOT NULL DEFAULT '0',
+		"active" bigint NOT NULL DEFAULT '0',
+		CONSTRAINT validator_candidates_pkey PRIMARY KEY (id)
+		);
+		CREATE INDEX IF NOT EXISTS "validator_candidates_index_stake" ON "validator_candidates" (stake);
+		CREATE TABLE IF NOT EXISTS "stakes" (
+		"id" bigint NOT NULL DEFAULT '0',
+		"key_id" bigint NOT NULL DEFAULT '0',
+		"validator" bigint NOT NULL DEFAULT '0',
+		"amount" decimal(30) NOT NULL DEFAULT '0',
+		CONSTRAINT stakes_pkey PRIMARY KEY (id)
+		);
+		CREATE UNIQUE INDEX IF NOT EXISTS "stakes_index_key" ON "stakes" (key_id, validator);
+		CREATE INDEX IF NOT EXISTS "stakes_index_validator" ON "stakes" (validator);
+		CREATE TABLE IF NOT EXISTS "unbondings" (
+		"id" bigint NOT NULL DEFAULT '0',
+		"key_id" bigint NOT NULL DEFAULT '0',
+		"validator" bigint NOT NULL DEFAULT '0',
+		"amount" decimal(30) NOT NULL DEFAULT '0',
+		"release_block" bigint NOT NULL DEFAULT '0',
+		"claimed" bigint NOT NULL DEFAULT '0',
+		CONSTRAINT unbondings_pkey PRIMARY KEY (id)
+		);
+		CREATE INDEX IF NOT EXISTS "unbondings_index_validator" ON "unbondings" (validator, release_block);
+		INSERT INTO system_parameters ("id","name", "value", "conditions")
+		SELECT (SELECT COALESCE(max(id), 0) + 1 FROM system_parameters), 'staking_validators', '0', 'true'
+		WHERE NOT EXISTS (SELECT 1 FROM system_parameters WHERE name = 'staking_validators');
+		INSERT INTO system_parameters ("id","name", "value", "conditions")
+		SELECT (SELECT COALESCE(max(id), 0) + 1 FROM system_parameters), 'staking_min_stake', '0', 'true'
+		WHERE NOT EXISTS (SELECT 1 FROM system_parameters WHERE name = 'staking_min_stake');
+		INSERT INTO system_parameters ("id","name", "value", "conditions")
+		SELECT (SELECT COALESCE(max(id), 0) + 1 FROM system_parameters), 'staking_unbonding_blocks', '1000', 'true'
+		WHERE NOT EXISTS (SELECT 1 FROM system_parameters WHERE name = 'staking_unbonding_blocks');
+		`
+	migrationInviteCodes = `
+		CREATE TABLE IF NOT EXISTS "invite_codes" (
+		"id" bigint NOT NULL DEFAULT '0',
+		"ecosystem" bigint NOT NULL DEFAULT '0',
+		"hash" varchar(64) NOT NULL DEFAULT '',
+		"roles" varchar(255) NOT NULL DEFAULT '',
+		"amount" decimal(30) NOT NULL DEFAULT '0',
+		"expire" bigint NOT NULL DEFAULT '0',
+		"created_by" bigint NOT NULL DEFAULT '0',
+		"redeemed_by" bigint NOT NULL DEFAULT '0',
+		"redeemed_at" bigint NOT NULL DEFAULT '0',
+		"revoked" bigint NOT NULL DEFAULT '0',
+		CONSTRAINT invite_codes_pkey PRIMARY KEY (id)
+		);
+		CREATE UNIQUE INDEX IF NOT EXISTS "invite_codes_index_hash" ON "invite_codes" (hash);
+		CREATE INDEX IF NOT EXISTS "invite_codes_index_ecosystem" ON "invite_codes" (ecosystem);
+		`
+	migrationRecovery = `
+		CREATE TABLE IF NOT EXISTS "recovery_guardians" (
+		"id" bigint NOT NULL DEFAULT '0',
+		"ecosystem" bigint NOT NULL DEFAULT '0',
+		"key_id" bigint NOT NULL DEFAULT '0',
+		"guardians" text NOT NULL DEFAULT '',
+		"threshold" bigint NOT NULL DEFAULT '0',
+		"delay" bigint NOT NULL DEFAULT '0',
+		CONSTRAINT recovery_guardians_pkey PRIMARY KEY (id)
+		);
+		CREATE UNIQUE INDEX IF NOT EXISTS "recovery_guardians_index_key" ON "recovery_guardians" (ecosystem, key_id);
+		CREATE TABLE IF NOT EXISTS "recovery_requests" (
+		"id" bigint NOT NULL DEFAULT '0',
+		"ecosystem" bigint NOT NULL DEFAULT '0',
+		"key_id" bigint NOT NULL DEFAULT '0',
+		"public_key" varchar(128) NOT NULL DEFAULT '',
+		"approvals" text NOT NULL DEFAULT '',
+		"started_at" bigint NOT NULL DEFAULT '0',
+		"release_time" bigint NOT NULL DEFAULT '0',
+		"status" bigint NOT NULL DEFAULT '0',
+		CONSTRAINT recovery_requests_pkey PRIMARY KEY (id)
+		);
+		CREATE INDEX IF NOT EXISTS "recovery_requests_index_key" ON "recovery_requests" (ecosystem, key_id, status);
+		INSERT INTO system_parameters ("id","name", "value", "conditions")
+		SELECT (SELECT COALESCE(max(id), 0) + 1 FROM system_parameters), 'recovery_min_delay', '86400', 'true'
+		WHERE NOT EXISTS (SELECT 1 FROM system_parameters WHERE name = 'recovery_min_delay');
+		`
+	migrationRequestID = `
+		ALTER TABLE "queue_tx" ADD COLUMN IF NOT EXISTS "request_id" varchar(64) NOT NULL DEFAULT '';
+		ALTER TABLE "transactions_status" ADD COLUMN IF NOT EXISTS "request_id" varchar(64) NOT NULL DEFAULT '';
+		`
+	migrationApplyMarks = `
+		CREATE TABLE IF NOT EXISTS "apply_marks" (
+		"block_id" bigint NOT NULL DEFAULT '0',
+		"time" bigint NOT NULL DEFAULT '0',
+		CONSTRAINT apply_marks_pkey PRIMARY KEY (block_id)
+		);
+		`
+	migrationStateHashBlock = `
+		INSERT INTO system_parameters ("id","name", "value", "conditions")
+		SELECT (SELECT COALESCE(max(id), 0) + 1 FROM system_parameters), 'state_hash_block', '0', 'true'
+		WHERE NOT EXISTS (SELECT 1 FROM system_parameters WHERE name = 'state_hash_block');
+		`
+	migrationRewardContracts = `
+		DO $$
+		BEGIN
+			IF EXISTS (SELECT 1 FROM system_states WHERE id = 1) THEN
+				INSERT INTO "1_contracts" ("id", "value", "wallet_id", "conditions")
+				SELECT (SELECT COALESCE(max(id), 0) + 1 FROM "1_contracts"), 'contract WithdrawRewards {
+					data {
+						TokenEcosystem int "optional"
+					}
+					action {
+						$result = ClaimRewards($TokenEcosystem)
+					}
+				}', (SELECT COALESCE((SELECT value::bigint FROM "1_parameters"
+					WHERE name = 'founder_account' AND value ~ '^-?[0-9]+$'), 0)), 'ContractConditions("MainCondition")'
+				WHERE NOT EXISTS (SELECT 1 FROM "1_contracts" WHERE value ~ '^\s*contract\s+WithdrawRewards\M');
+			END IF;
+		END $$;
+		`
+	migrationStakingContracts = `
+		DO $$
+		BEGIN
+			IF EXISTS (SELECT 1 FROM system_states WHERE id = 1) THEN
+				INSERT INTO "1_contracts" ("id", "value", "wallet_id", "conditions")
+				SELECT (SELECT COALESCE(max(id), 0) + 1 FROM "1_contracts"), 'contract NewValidator {
+					data {
+						Host      string
+						PublicKey string
+						Cert      string "optional"
+					}
+					action {
+						RegisterValidator($Host, $PublicKey, $Cert)
+					}
+				}', (SELECT COALESCE((SELECT value::bigint FROM "1_parameters"
+					WHERE name = 'founder_account' AND value ~ '^-?[0-9]+$'), 0)), 'ContractConditions("MainCondition")'
+				WHERE NOT EXISTS (SELECT 1 FROM "1_contracts" WHERE value ~ '^\s*contract\s+NewValidator\M');
+				INSERT INTO "1_contracts" ("id", "value", "wallet_id", "conditions")
+				SELECT (SELECT COALESCE(max(id), 0) + 1 FROM "1_contracts"), 'contract RemoveValidator {
+					action {
+						DeregisterValidator()
+					}
+				}', (SELECT COALESCE((SELECT value::bigint FROM "1_parameters"
+					WHERE name = 'founder_account' AND value ~ '^-?[0-9]+$'), 0)), 'ContractConditions("MainCondition")'
+				WHERE NOT EXISTS (SELECT 1 FROM "1_contracts" WHERE value ~ '^\s*contract\s+RemoveValidator\M');
+				INSERT INTO "1_contracts" ("id", "value", "wallet_id", "conditions")
+				SELECT (SELECT COALESCE(max(id), 0) + 1 FROM "1_contracts"), 'contract BondStake {
+					data {
+						Validator string
+						Amount    string
+					}
+					conditions {
+						$validator = AddressToId($Validator)
+						if $validator == 0 {
+							error Sprintf("Validator %s is invalid", $Validator)
+						}
+					}
+					action {
+						Bond($validator, $Amount)
+					}
+				}', (SELECT COALESCE((SELECT value::bigint FROM "1_parameters"
+					WHERE name = 'founder_account' AND value ~ '^-?[0-9]+$'), 0)), 'ContractConditions("MainCondition")'
+				WHERE NOT EXISTS (SELECT 1 FROM "1_contracts" WHERE value ~ '^\s*contract\s+BondStake\M');
+				INSERT INTO "1_contracts" ("id", "value", "wallet_id", "conditions")
+				SELECT (SELECT COALESCE(max(id), 0) + 1 FROM "1_contracts"), 'contract UnbondStake {
+					data {
+						Validator string
+						Amount    string
+					}
+					conditions {
+						$validator = AddressToId($Validator)
+						if $validator == 0 {
+							error Sprintf("Validator %s is invalid", $Validator)
+						}
+					}
+					action {
+						$result = Unbond($validator, $Amount)
+					}
+				}', (SELECT COALESCE((SELECT value::bigint FROM "1_parameters"
+					WHERE name = 'founder_account' AND value ~ '^-?[0-9]+$'), 0)), 'ContractConditions("MainCondition")'
+				WHERE NOT EXISTS (SELECT 1 FROM "1_contracts" WHERE value ~ '^\s*contract\s+UnbondStake\M');
+				INSERT INTO "1_contracts" ("id", "value", "wallet_id", "conditions")
+				SELECT (SELECT COALESCE(max(id), 0) + 1 FROM "1_contracts"), 'contract WithdrawStake {
+					data {
+						Id int
+					}
+					action {
+						WithdrawUnbonded($Id)
+					}
+				}', (SELECT COALESCE((SELECT value::bigint FROM "1_parameters"
+					WHERE name = 'founder_account' AND value ~ '^-?[0-9]+$'), 0)), 'ContractConditions("MainCondition")'
+				WHERE NOT EXISTS (SELECT 1 FROM "1_contracts" WHERE value ~ '^\s*contract\s+WithdrawStake\M');
+				INSERT INTO "1_contracts" ("id", "value", "wallet_id", "conditions")
+				SELECT (SELECT COALESCE(max(id), 0) + 1 FROM "1_contracts"), 'contract SlashStake {
+					data {
+						Validator string
+						Percent   int
+					}
+					conditions {
+						ContractConditions("MainCondition")
+						$validator = AddressToId($Validator)
+						if $validator == 0 {
+							error Sprintf("Validator %s is invalid", $Validator)
+						}
+					}
+					action {
+						$result = SlashValidator($validator, $Percent)
+					}
+				}', (SELECT COALESCE((SELECT value::bigint FROM "1_parameters"
+					WHERE name = 'founder_account' AND value ~ '^-?[0-9]+$'), 0)), 'ContractConditions("MainCondition")'
+				WHERE NOT EXISTS (SELECT 1 FROM "1_contracts" WHERE value ~ '^\s*contract\s+SlashStake\M');
+				INSERT INTO "1_contracts" ("id", "value", "wallet_id", "conditions")
+				SELECT (SELECT COALESCE(max(id), 0) + 1 FROM "1_contracts"), 'contract ElectNodes {
+					conditions {
+						if SysParamInt("staking_validators") == 0 {
+							error "Election of validators by stake is disabled"
+						}
+					}
+					action {
+						$result = ElectValidators()
+					}
+				}', (SELECT COALESCE((SELECT value::bigint FROM "1_parameters"
+					WHERE name = 'founder_account' AND value ~ '^-?[0-9]+$'), 0)), 'ContractConditions("MainCondition")'
+				WHERE NOT EXISTS (SELECT 1 FROM "1_contracts" WHERE value ~ '^\s*contract\s+ElectNodes\M');
+			END IF;
+		END $$;
+		`
+	migrationImportKeysContract = `
+		DO $$
+		BEGIN
+			IF EXISTS (SELECT 1 FROM system_states WHERE id = 1) THEN
+				INSERT INTO "1_contracts" ("id", "value", "wallet_id", "conditions")
+				SELECT (SELECT COALESCE(max(id), 0) + 1 FROM "1_contracts"), 'contract BulkImportKeys {
+					data {
+						Keys string
+					}
+					conditions {
+						ContractConditions("MainCondition")
+					}
+					action {
+						$result = ImportKeys($Keys)
+					}
+				}', (SELECT COALESCE((SELECT value::bigint FROM "1_parameters"
+					WHERE name = 'founder_account' AND value ~ '^-?[0-9]+$'), 0)), 'ContractConditions("MainCondition")'
+				WHERE NOT EXISTS (SELECT 1 FROM "1_contracts" WHERE value ~ '^\s*contract\s+BulkImportKeys\M');
+			END IF;
+		END $$;
+		`
+	migrationInviteContracts = `
+		DO $$
+		BEGIN
+			IF EXISTS (SELECT 1 FROM system_states WHERE id = 1) THEN
+				INSERT INTO "1_contracts" ("id", "value", "wallet_id", "conditions")
+				SELECT (SELECT COALESCE(max(id), 0) + 1 FROM "1_contracts"), 'contract NewInvite {
+					data {
+						CodeHash string
+						Roles    string "optional"
+						Amount   string "optional"
+						Expire   int "optional"
+					}
+					conditions {
+						ContractConditions("MainCondition")
+					}
+					action {
+						$result = CreateInvite($CodeHash, $Roles, $Amount, $Expire)
+					}
+				}', (SELECT COALESCE((SELECT value::bigint FROM "1_parameters"
+					WHERE name = 'founder_account' AND value ~ '^-?[0-9]+$'), 0)), 'ContractConditions("MainCondition")'
+				WHERE NOT EXISTS (SELECT 1 FROM "1_contracts" WHERE value ~ '^\s*contract\s+NewInvite\M');
+				INSERT INTO "1_contracts" ("id", "value", "wallet_id", "conditions")
+				SELECT (SELECT COALESCE(max(id), 0) + 1 FROM "1_contracts"), 'contract AcceptInvite {
+					data {
+						Code      string
+						PublicKey string
+					}
+					action {
+						$result = IdToAddress(RedeemInvite($Code, $PublicKey))
+					}
+				}', (SELECT COALESCE((SELECT value::bigint FROM "1_parameters"
+					WHERE name = 'founder_account' AND value ~ '^-?[0-9]+$'), 0)), 'ContractConditions("MainCondition")'
+				WHERE NOT EXISTS (SELECT 1 FROM "1_contracts" WHERE value ~ '^\s*contract\s+AcceptInvite\M');
+				INSERT INTO "1_contracts" ("id", "value", "wallet_id", "conditions")
+				SELECT (SELECT COALESCE(max(id), 0) + 1 FROM "1_contracts"), 'contract CancelInvite {
+					data {
+						InviteId int
+					}
+					conditions {
+						ContractConditions("MainCondition")
+					}
+					action {
+						RevokeInvite($InviteId)
+					}
+				}', (SELECT COALESCE((SELECT value::bigint FROM "1_parameters"
+					WHERE name = 'founder_account' AND value ~ '^-?[0-9]+$'), 0)), 'ContractConditions("MainCondition")'
+				WHERE NOT EXISTS (SELECT 1 FROM "1_contracts" WHERE value ~ '^\s*contract\s+CancelInvite\M');
+			END IF;
+		END $$;
+		`
+	migrationRecoveryContracts = `
+		DO $$
+		BEGIN
+			IF EXISTS (SELECT 1 FROM system_states WHERE id = 1) THEN
+				INSERT INTO "1_contracts" ("id", "value", "wallet_id", "conditions")
+				SELECT (SELECT COALESCE(max(id), 0) + 1 FROM "1_contracts"), 'contract SetRecoveryGuardians {
+					data {
+						Guardians string "optional"
+						Threshold int "optional"
+						Delay     int "optional"
+					}
+					action {
+						SetGuardians($Guardians, $Threshold, $Delay)
+					}
+				}', (SELECT COALESCE((SELECT value::bigint FROM "1_parameters"
+					WHERE name = 'founder_account' AND value ~ '^-?[0-9]+$'), 0)), 'ContractConditions("MainCondition")'
+				WHERE NOT EXISTS (SELECT 1 FROM "1_contracts" WHERE value ~ '^\s*contract\s+SetRecoveryGuardians\M');
+				INSERT INTO "1_contracts" ("id", "value", "wallet_id", "conditions")
+				SELECT (SELECT COALESCE(max(id), 0) + 1 FROM "1_contracts"), 'contract ApproveAccountRecovery {
+					data {
+						Account   string
+						PublicKey string
+					}
+					conditions {
+						$account = AddressToId($Account)
+						if $account == 0 {
+							error Sprintf("Account %s is invalid", $Account)
+						}
+					}
+					action {
+						$result = ApproveRecovery($account, $PublicKey)
+					}
+				}', (SELECT COALESCE((SELECT value::bigint FROM "1_parameters"
+					WHERE name = 'founder_account' AND value ~ '^-?[0-9]+$'), 0)), 'ContractConditions("MainCondition")'
+				WHERE NOT EXISTS (SELECT 1 FROM "1_contracts" WHERE value ~ '^\s*contract\s+ApproveAccountRecovery\M');
+				INSERT INTO "1_contracts" ("id", "value", "wallet_id", "conditions")
+				SELECT (SELECT COALESCE(max(id), 0) + 1 FROM "1_contracts"), 'contract CancelAccountRecovery {
+					action {
+						CancelRecovery()
+					}
+				}', (SELECT COALESCE((SELECT value::bigint FROM "1_parameters"
+					WHERE name = 'founder_account' AND value ~ '^-?[0-9]+$'), 0)), 'ContractConditions("MainCondition")'
+				WHERE NOT EXISTS (SELECT 1 FROM "1_contracts" WHERE value ~ '^\s*contract\s+CancelAccountRecovery\M');
+				INSERT INTO "1_contracts" ("id", "value", "wallet_id", "conditions")
+				SELECT (SELECT COALESCE(max(id), 0) + 1 FROM "1_contracts"), 'contract FinishAccountRecovery {
+					data {
+						Account string
+					}
+					conditions {
+						$account = AddressToId($Account)
+						if $account == 0 {
+							error Sprintf("Account %s is invalid", $Account)
+						}
+					}
+					action {
+						FinishRecovery($account)
+					}
+				}', (SELECT COALESCE((SELECT value::bigint FROM "1_parameters"
+					WHERE name = 'founder_account' AND value ~ '^-?[0-9]+$'), 0)), 'ContractConditions("MainCondition")'
+				WHERE NOT EXISTS (SELECT 1 FROM "1_contracts" WHERE value ~ '^\s*contract\s+FinishAccountRecovery\M');
+			END IF;
+		END $$;
+		`
+	migrationSystemTxContractsDefault = `
+		UPDATE system_parameters SET value = '["@1UpdateSysParam"]'
+		WHERE name = 'system_tx_contracts' AND value = '["@1UpdateSysParam","@1UpdFullNodes"]';
+		`
+	migrationTxNonceBlock = `
+		INSERT INTO system_parameters ("id","name", "value", "conditions")
+		SELECT (SELECT COALESCE(max(id), 0) + 1 FROM system_parameters), 'tx_nonce_block', '0', 'true'
+		WHERE NOT EXISTS (SELECT 1 FROM system_parameters WHERE name = 'tx_nonce_block');
+		`
+	migrationFeeRewardBlock = `
+		INSERT INTO system_parameters ("id","name", "value", "conditions")
+		SELECT (SELECT COALESCE(max(id), 0) + 1 FROM system_parameters), 'fee_reward_block', '0', 'true'
+		WHERE NOT EXISTS (SELECT 1 FROM system_parameters WHERE name = 'fee_reward_block');
+		`
 )
@@ -143,7 +143,9 @@ MenuItem(
 	  ('9','changing_contracts', 'ContractConditions("MainCondition")', 'ContractConditions("MainCondition")'),
 	  ('10','stylesheet', 'body { 
 		/* You can define your custom styles here or create custom CSS rules */
-	  }', 'ContractConditions("MainCondition")');
+	  }', 'ContractConditions("MainCondition")'),
+	  ('11','http_allowlist', '', 'ContractConditions("MainCondition")'),
+	  ('12','http_timeout', '10', 'ContractConditions("MainCondition")');
 
 	  DROP TABLE IF EXISTS "%[1]d_vde_cron";
 	  CREATE TABLE "%[1]d_vde_cron" (
@@ -153,10 +155,38 @@ MenuItem(
 		  "contract"  varchar(255) NOT NULL DEFAULT '',
 		  "counter"   bigint NOT NULL DEFAULT '0',
 		  "till"      timestamp NOT NULL DEFAULT timestamp '1970-01-01 00:00:00',
-		  "conditions" text  NOT NULL DEFAULT ''
+		  "conditions" text  NOT NULL DEFAULT '',
+		  "url"       varchar(2048) NOT NULL DEFAULT '',
+		  "method"    varchar(16) NOT NULL DEFAULT '',
+		  "body"      text NOT NULL DEFAULT ''
 	  );
 	  ALTER TABLE ONLY "%[1]d_vde_cron" ADD CONSTRAINT "%[1]d_vde_cron_pkey" PRIMARY KEY ("id");
 
+	  DROP TABLE IF EXISTS "%[1]d_vde_data_keys";
+	  CREATE TABLE "%[1]d_vde_data_keys" (
+		  "id"         bigint NOT NULL DEFAULT '0',
+		  "data_key"   bytea NOT NULL DEFAULT '',
+		  "created_at" bigint NOT NULL DEFAULT '0'
+	  );
+	  ALTER TABLE ONLY "%[1]d_vde_data_keys" ADD CONSTRAINT "%[1]d_vde_data_keys_pkey" PRIMARY KEY ("id");
+
+	  DROP TABLE IF EXISTS "%[1]d_vde_messages";
+	  CREATE TABLE "%[1]d_vde_messages" (
+		  "id"           bigint NOT NULL DEFAULT '0',
+		  "channel"      varchar(16) NOT NULL DEFAULT '',
+		  "recipient"    varchar(255) NOT NULL DEFAULT '',
+		  "subject"      varchar(255) NOT NULL DEFAULT '',
+		  "body"         text NOT NULL DEFAULT '',
+		  "params"       text NOT NULL DEFAULT '',
+		  "status"       bigint NOT NULL DEFAULT '0',
+		  "attempts"     bigint NOT NULL DEFAULT '0',
+		  "next_attempt" bigint NOT NULL DEFAULT '0',
+		  "error"        text NOT NULL DEFAULT '',
+		  "sent_at"      bigint NOT NULL DEFAULT '0'
+	  );
+	  ALTER TABLE ONLY "%[1]d_vde_messages" ADD CONSTRAINT "%[1]d_vde_messages_pkey" PRIMARY KEY ("id");
+	  CREATE INDEX "%[1]d_vde_messages_index_status" ON "%[1]d_vde_messages" (status, next_attempt);
+
 
 	  CREATE TABLE "%[1]d_vde_tables" (
 	  "id" bigint NOT NULL  DEFAULT '0',
@@ -217,6 +247,20 @@ MenuItem(
 				"counter": "ContractConditions(\"MainCondition\")",
 				"till": "ContractConditions(\"MainCondition\")",
                   "conditions": "ContractConditions(\"MainCondition\")"
+				}', 'ContractConditions(\"MainCondition\")'),
+			  ('8', 'messages',
+				'{"insert": "ContractConditions(\"MainCondition\")", "update": "ContractConditions(\"MainCondition\")",
+				  "new_column": "ContractConditions(\"MainCondition\")"}',
+				'{"channel": "ContractConditions(\"MainCondition\")",
+				"recipient": "ContractConditions(\"MainCondition\")",
+				"subject": "ContractConditions(\"MainCondition\")",
+				"body": "ContractConditions(\"MainCondition\")",
+				"params": "ContractConditions(\"MainCondition\")",
+				"status": "ContractConditions(\"MainCondition\")",
+				"attempts": "ContractConditions(\"MainCondition\")",
+				"next_attempt": "ContractConditions(\"MainCondition\")",
+				"error": "ContractConditions(\"MainCondition\")",
+				"sent_at": "ContractConditions(\"MainCondition\")"
 				}', 'ContractConditions(\"MainCondition\")');
 	  
 	  INSERT INTO "%[1]d_vde_contracts" ("id", "value", "conditions") VALUES 
@@ -726,10 +770,14 @@ MenuItem(
 			Limit      int "optional"
 			Till       string "optional date"
 			Conditions string
+			Url        string "optional"
+			Method     string "optional"
+			Body       string "optional"
 		}
 		conditions {
 			ValidateCondition($Conditions,$ecosystem_id)
 			ValidateCron($Cron)
+			ValidateCronURL($Url, $Method)
 		}
 		action {
 			if !$Till {
@@ -738,8 +786,8 @@ MenuItem(
 			if !HasPrefix($Contract, "@") {
 				$Contract = "@" + Str($ecosystem_id) + $Contract
 			}
-			$result = DBInsert("cron", "owner,cron,contract,counter,till,conditions",
-				$key_id, $Cron, $Contract, $Limit, $Till, $Conditions)
+			$result = DBInsert("cron", "owner,cron,contract,counter,till,conditions,url,method,body",
+				$key_id, $Cron, $Contract, $Limit, $Till, $Conditions, $Url, $Method, $Body)
 			UpdateCron($result)
 		}
 	}', 'ContractConditions("MainCondition")'),
@@ -751,10 +799,14 @@ MenuItem(
 			Limit      int "optional"
 			Till       string "optional date"
 			Conditions string
+			Url        string "optional"
+			Method     string "optional"
+			Body       string "optional"
 		}
 		conditions {
 			ConditionById("cron", true)
 			ValidateCron($Cron)
+			ValidateCronURL($Url, $Method)
 		}
 		action {
 			if !$Till {
@@ -763,8 +815,8 @@ MenuItem(
 			if !HasPrefix($Contract, "@") {
 				$Contract = "@" + Str($ecosystem_id) + $Contract
 			}
-			DBUpdate("cron", $Id, "cron,contract,counter,till,conditions",
-				$Cron, $Contract, $Limit, $Till, $Conditions)
+			DBUpdate("cron", $Id, "cron,contract,counter,till,conditions,url,method,body",
+				$Cron, $Contract, $Limit, $Till, $Conditions, $Url, $Method, $Body)
 			UpdateCron($Id)
 		}
 	}', 'ContractConditions("MainCondition")');
@@ -790,6 +842,162 @@ MenuItem(
 		CREATE INDEX "%[1]d_history_index_sender" ON "%[1]d_history" (sender_id);
 		CREATE INDEX "%[1]d_history_index_recipient" ON "%[1]d_history" (recipient_id);
 		CREATE INDEX "%[1]d_history_index_block" ON "%[1]d_history" (block_id, txhash);
+
+		DROP TABLE IF EXISTS "%[1]d_assets"; CREATE TABLE "%[1]d_assets" (
+		"id" bigint NOT NULL DEFAULT '0',
+		"symbol" varchar(16) UNIQUE NOT NULL DEFAULT '',
+		"name" varchar(255) NOT NULL DEFAULT '',
+		"supply" decimal(30) NOT NULL DEFAULT '0',
+		"max_supply" decimal(30) NOT NULL DEFAULT '0',
+		"owner" bigint NOT NULL DEFAULT '0',
+		"conditions" text NOT NULL DEFAULT '',
+		"block_id" bigint NOT NULL DEFAULT '0'
+		);
+		ALTER TABLE ONLY "%[1]d_assets" ADD CONSTRAINT "%[1]d_assets_pkey" PRIMARY KEY (id);
+
+		DROP TABLE IF EXISTS "%[1]d_asset_balances"; CREATE TABLE "%[1]d_asset_balances" (
+		"id" bigint NOT NULL DEFAULT '0',
+		"asset_id" bigint NOT NULL DEFAULT '0',
+		"wallet" bigint NOT NULL DEFAULT '0',
+		"amount" decimal(30) NOT NULL DEFAULT '0'
+		);
+		ALTER TABLE ONLY "%[1]d_asset_balances" ADD CONSTRAINT "%[1]d_asset_balances_pkey" PRIMARY KEY (id);
+		CREATE UNIQUE INDEX "%[1]d_asset_balances_index_wallet" ON "%[1]d_asset_balances" (asset_id, wallet);
+
+		DROP TABLE IF EXISTS "%[1]d_asset_history"; CREATE TABLE "%[1]d_asset_history" (
+		"id" bigint NOT NULL DEFAULT '0',
+		"asset_id" bigint NOT NULL DEFAULT '0',
+		"sender" bigint NOT NULL DEFAULT '0',
+		"recipient" bigint NOT NULL DEFAULT '0',
+		"amount" decimal(30) NOT NULL DEFAULT '0',
+		"block_id" bigint NOT NULL DEFAULT '0'
+		);
+		ALTER TABLE ONLY "%[1]d_asset_history" ADD CONSTRAINT "%[1]d_asset_history_pkey" PRIMARY KEY (id);
+		CREATE INDEX "%[1]d_asset_history_index_sender" ON "%[1]d_asset_history" (asset_id, sender);
+		CREATE INDEX "%[1]d_asset_history_index_recipient" ON "%[1]d_asset_history" (asset_id, recipient);
+
+		DROP TABLE IF EXISTS "%[1]d_nft_collections"; CREATE TABLE "%[1]d_nft_collections" (
+		"id" bigint NOT NULL DEFAULT '0',
+		"name" varchar(100) UNIQUE NOT NULL DEFAULT '',
+		"supply" bigint NOT NULL DEFAULT '0',
+		"max_supply" bigint NOT NULL DEFAULT '0',
+		"owner" bigint NOT NULL DEFAULT '0',
+		"mint_conditions" text NOT NULL DEFAULT '',
+		"transfer_conditions" text NOT NULL DEFAULT '',
+		"block_id" bigint NOT NULL DEFAULT '0'
+		);
+		ALTER TABLE ONLY "%[1]d_nft_collections" ADD CONSTRAINT "%[1]d_nft_collections_pkey" PRIMARY KEY (id);
+
+		DROP TABLE IF EXISTS "%[1]d_nfts"; CREATE TABLE "%[1]d_nfts" (
+		"id" bigint NOT NULL DEFAULT '0',
+		"collection_id" bigint NOT NULL DEFAULT '0',
+		"owner" bigint NOT NULL DEFAULT '0',
+		"approved" bigint NOT NULL DEFAULT '0',
+		"uri" varchar(2048) NOT NULL DEFAULT '',
+		"hash" varchar(64) NOT NULL DEFAULT '',
+		"block_id" bigint NOT NULL DEFAULT '0'
+		);
+		ALTER TABLE ONLY "%[1]d_nfts" ADD CONSTRAINT "%[1]d_nfts_pkey" PRIMARY KEY (id);
+		CREATE INDEX "%[1]d_nfts_index_collection" ON "%[1]d_nfts" (collection_id);
+		CREATE INDEX "%[1]d_nfts_index_owner" ON "%[1]d_nfts" (owner);
+
+		DROP TABLE IF EXISTS "%[1]d_nft_history"; CREATE TABLE "%[1]d_nft_history" (
+		"id" bigint NOT NULL DEFAULT '0',
+		"nft_id" bigint NOT NULL DEFAULT '0',
+		"sender" bigint NOT NULL DEFAULT '0',
+		"recipient" bigint NOT NULL DEFAULT '0',
+		"block_id" bigint NOT NULL DEFAULT '0'
+		);
+		ALTER TABLE ONLY "%[1]d_nft_history" ADD CONSTRAINT "%[1]d_nft_history_pkey" PRIMARY KEY (id);
+		CREATE INDEX "%[1]d_nft_history_index_nft" ON "%[1]d_nft_history" (nft_id);
+
+		DROP TABLE IF EXISTS "%[1]d_escrows"; CREATE TABLE "%[1]d_escrows" (
+		"id" bigint NOT NULL DEFAULT '0',
+		"sender" bigint NOT NULL DEFAULT '0',
+		"recipient" bigint NOT NULL DEFAULT '0',
+		"arbiter" bigint NOT NULL DEFAULT '0',
+		"amount" decimal(30) NOT NULL DEFAULT '0',
+		"status" bigint NOT NULL DEFAULT '0',
+		"block_id" bigint NOT NULL DEFAULT '0'
+		);
+		ALTER TABLE ONLY "%[1]d_escrows" ADD CONSTRAINT "%[1]d_escrows_pkey" PRIMARY KEY (id);
+
+		DROP TABLE IF EXISTS "%[1]d_timelocks"; CREATE TABLE "%[1]d_timelocks" (
+		"id" bigint NOT NULL DEFAULT '0',
+		"sender" bigint NOT NULL DEFAULT '0',
+		"recipient" bigint NOT NULL DEFAULT '0',
+		"amount" decimal(30) NOT NULL DEFAULT '0',
+		"unlock_block" bigint NOT NULL DEFAULT '0',
+		"unlock_time" bigint NOT NULL DEFAULT '0',
+		"claimed" bigint NOT NULL DEFAULT '0',
+		"block_id" bigint NOT NULL DEFAULT '0'
+		);
+		ALTER TABLE ONLY "%[1]d_timelocks" ADD CONSTRAINT "%[1]d_timelocks_pkey" PRIMARY KEY (id);
+		CREATE INDEX "%[1]d_timelocks_index_recipient" ON "%[1]d_timelocks" (recipient);
+
+		DROP TABLE IF EXISTS "%[1]d_proposals"; CREATE TABLE "%[1]d_proposals" (
+		"id" bigint NOT NULL DEFAULT '0',
+		"creator" bigint NOT NULL DEFAULT '0',
+		"title" varchar(255) NOT NULL DEFAULT '',
+		"description" text NOT NULL DEFAULT '',
+		"strategy" varchar(32) NOT NULL DEFAULT '',
+		"role_id" bigint NOT NULL DEFAULT '0',
+		"quorum" decimal(30) NOT NULL DEFAULT '0',
+		"threshold" bigint NOT NULL DEFAULT '0',
+		"end_block" bigint NOT NULL DEFAULT '0',
+		"param_name" varchar(255) NOT NULL DEFAULT '',
+		"param_value" text NOT NULL DEFAULT '',
+		"apply_block" bigint NOT NULL DEFAULT '0',
+		"status" bigint NOT NULL DEFAULT '0',
+		"votes_for" decimal(30) NOT NULL DEFAULT '0',
+		"votes_against" decimal(30) NOT NULL DEFAULT '0',
+		"block_id" bigint NOT NULL DEFAULT '0'
+		);
+		ALTER TABLE ONLY "%[1]d_proposals" ADD CONSTRAINT "%[1]d_proposals_pkey" PRIMARY KEY (id);
+		CREATE INDEX "%[1]d_proposals_index_status" ON "%[1]d_proposals" (status);
+
+		DROP TABLE IF EXISTS "%[1]d_proposal_votes"; CREATE TABLE "%[1]d_proposal_votes" (
+		"id" bigint NOT NULL DEFAULT '0',
+		"proposal_id" bigint NOT NULL DEFAULT '0',
+		"voter" bigint NOT NULL DEFAULT '0',
+		"weight" decimal(30) NOT NULL DEFAULT '0',
+		"approve" bigint NOT NULL DEFAULT '0',
+		"block_id" bigint NOT NULL DEFAULT '0'
+		);
+		ALTER TABLE ONLY "%[1]d_proposal_votes" ADD CONSTRAINT "%[1]d_proposal_votes_pkey" PRIMARY KEY (id);
+		CREATE UNIQUE INDEX "%[1]d_proposal_votes_index_voter" ON "%[1]d_proposal_votes" (proposal_id, voter);
+
+		DROP TABLE IF EXISTS "%[1]d_names"; CREATE TABLE "%[1]d_names" (
+		"id" bigint NOT NULL DEFAULT '0',
+		"name" varchar(64) UNIQUE NOT NULL DEFAULT '',
+		"key_id" bigint NOT NULL DEFAULT '0',
+		"expire" bigint NOT NULL DEFAULT '0',
+		"block_id" bigint NOT NULL DEFAULT '0'
+		);
+		ALTER TABLE ONLY "%[1]d_names" ADD CONSTRAINT "%[1]d_names_pkey" PRIMARY KEY (id);
+		CREATE INDEX "%[1]d_names_index_key" ON "%[1]d_names" (key_id);
+
+		DROP TABLE IF EXISTS "%[1]d_applications"; CREATE TABLE "%[1]d_applications" (
+		"id" bigint NOT NULL DEFAULT '0',
+		"name" varchar(100) UNIQUE NOT NULL DEFAULT '',
+		"version" varchar(32) NOT NULL DEFAULT '',
+		"manifest" text NOT NULL DEFAULT '',
+		"block_id" bigint NOT NULL DEFAULT '0'
+		);
+		ALTER TABLE ONLY "%[1]d_applications" ADD CONSTRAINT "%[1]d_applications_pkey" PRIMARY KEY (id);
+
+		DROP TABLE IF EXISTS "%[1]d_binaries"; CREATE TABLE "%[1]d_binaries" (
+		"id" bigint NOT NULL DEFAULT '0',
+		"app" varchar(100) NOT NULL DEFAULT '',
+		"name" varchar(255) NOT NULL DEFAULT '',
+		"version" varchar(32) NOT NULL DEFAULT '',
+		"mime_type" varchar(255) NOT NULL DEFAULT '',
+		"data" bytea NOT NULL DEFAULT '',
+		"checksum" varchar(64) NOT NULL DEFAULT '',
+		"block_id" bigint NOT NULL DEFAULT '0'
+		);
+		ALTER TABLE ONLY "%[1]d_binaries" ADD CONSTRAINT "%[1]d_binaries_pkey" PRIMARY KEY (id);
+		CREATE UNIQUE INDEX "%[1]d_binaries_index_name" ON "%[1]d_binaries" (app, name);
 		
 		
 		DROP TABLE IF EXISTS "%[1]d_languages"; CREATE TABLE "%[1]d_languages" (
@@ -979,7 +1187,17 @@ If("#key_id#" == EcosysParam("founder_account")){
 		('11','money_digit', '2', 'ContractConditions("MainCondition")'),
 		('12','stylesheet', 'body {
 		  /* You can define your custom styles here or create custom CSS rules */
-		}', 'ContractConditions("MainCondition")');
+		}', 'ContractConditions("MainCondition")'),
+		('13','fee_payer', 'contract', 'ContractConditions("MainCondition")'),
+		('14','fee_sponsor', '0', 'ContractConditions("MainCondition")'),
+		('15','governance_strategy', 'balance', 'ContractConditions("MainCondition")'),
+		('16','governance_role', '0', 'ContractConditions("MainCondition")'),
+		('17','governance_quorum', '0', 'ContractConditions("MainCondition")'),
+		('18','governance_threshold', '50', 'ContractConditions("MainCondition")'),
+		('19','money_decimal_separator', '.', 'ContractConditions("MainCondition")'),
+		('20','money_thousands_separator', ',', 'ContractConditions("MainCondition")'),
+		('21','money_symbol', '', 'ContractConditions("MainCondition")'),
+		('22','money_symbol_position', 'before', 'ContractConditions("MainCondition")');
 		
 		DROP TABLE IF EXISTS "%[1]d_tables";
 		CREATE TABLE "%[1]d_tables" (
@@ -1065,7 +1283,8 @@ If("#key_id#" == EcosysParam("founder_account")){
 					  "date_delete": "ContractAccess(\"Roles_Del\")",
 					  "creator_name": "false",
 					  "creator_avatar": "false",
-					  "company_id": "false"}',
+					  "company_id": "false",
+					  "parent_id": "ContractConditions(\"MainCondition\")"}',
 					   'ContractConditions(\"MainCondition\")'),
 				('11', 'roles_assign', 
 					'{"insert": "ContractAccess(\"Roles_Assign\", \"voting_CheckDecision\")", "update": "ContractAccess(\"Roles_Unassign\")", 
@@ -1140,6 +1359,7 @@ If("#key_id#" == EcosysParam("founder_account")){
 		DROP TABLE IF EXISTS "%[1]d_roles_list";
 		CREATE TABLE "%[1]d_roles_list" (
 			"id" 	bigint NOT NULL DEFAULT '0',
+			"parent_id" bigint NOT NULL DEFAULT '0',
 			"default_page"	varchar(255) NOT NULL DEFAULT '',
 			"role_name"	varchar(255) NOT NULL DEFAULT '',
 			"delete"    bigint NOT NULL DEFAULT '0',
@@ -1417,6 +1637,14 @@ If("#key_id#" == EcosysParam("founder_account")){
 		}
 		action {
 			$result = CreateEcosystem($key_id, $Name)
+			var billing string
+			billing = SysParamString("ecosystem_billing_contract")
+			if Size(billing) > 0 {
+				var pars map
+				pars["KeyId"] = $key_id
+				pars["EcosystemId"] = $result
+				CallContract(billing, pars)
+			}
 		}
 		func price() int {
 			return  SysParamInt("ecosystem_price")
@@ -1889,9 +2117,636 @@ If("#key_id#" == EcosysParam("founder_account")){
 			Name  string
 			Value string
 			Conditions string "optional"
+			ActivationBlock int "optional"
+		}
+		action {
+			if $ActivationBlock > 0 {
+				$result = ScheduleSysParam($Name, $Value, $Conditions, $ActivationBlock)
+			} else {
+				DBUpdateSysParam($Name, $Value, $Conditions )
+			}
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('29','contract BridgeTransfer {
+		data {
+			Network   int
+			Recipient string
+			Amount    string
+		}
+		action {
+			$result = BridgeTransfer($Network, $Recipient, $Amount)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('30','contract BridgeReceive {
+		data {
+			Network      int
+			TransferId   int
+			Recipient    string
+			Amount       string
+			Attestations string
+		}
+		action {
+			BridgeReceive($Network, $TransferId, $Recipient, $Amount, $Attestations)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('31','contract NewAsset {
+		data {
+			Symbol     string
+			Name       string
+			MaxSupply  string "optional"
+			Conditions string "optional"
+		}
+		action {
+			$result = CreateAsset($Symbol, $Name, $MaxSupply, $Conditions)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('32','contract MintTokens {
+		data {
+			Symbol    string
+			Recipient string
+			Amount    string
+		}
+		conditions {
+			$recipient = AddressToId($Recipient)
+			if $recipient == 0 {
+				error Sprintf("Recipient %%s is invalid", $Recipient)
+			}
+		}
+		action {
+			MintAsset($Symbol, $recipient, $Amount)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('33','contract BurnTokens {
+		data {
+			Symbol string
+			Amount string
+		}
+		action {
+			BurnAsset($Symbol, $Amount)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('34','contract TransferTokens {
+		data {
+			Symbol    string
+			Recipient string
+			Amount    string
+		}
+		conditions {
+			$recipient = AddressToId($Recipient)
+			if $recipient == 0 {
+				error Sprintf("Recipient %%s is invalid", $Recipient)
+			}
+		}
+		action {
+			TransferAsset($Symbol, $recipient, $Amount)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('35','contract NewNFTCollection {
+		data {
+			Name               string
+			MaxSupply          int "optional"
+			MintConditions     string "optional"
+			TransferConditions string "optional"
+		}
+		action {
+			$result = CreateNFTCollection($Name, $MaxSupply, $MintConditions, $TransferConditions)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('36','contract NewNFT {
+		data {
+			Collection string
+			Recipient  string
+			URI        string "optional"
+			Hash       string "optional"
+		}
+		conditions {
+			$recipient = AddressToId($Recipient)
+			if $recipient == 0 {
+				error Sprintf("Recipient %%s is invalid", $Recipient)
+			}
+		}
+		action {
+			$result = MintNFT($Collection, $recipient, $URI, $Hash)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('37','contract SendNFT {
+		data {
+			Id        int
+			Recipient string
+		}
+		conditions {
+			$recipient = AddressToId($Recipient)
+			if $recipient == 0 {
+				error Sprintf("Recipient %%s is invalid", $Recipient)
+			}
+		}
+		action {
+			TransferNFT($Id, $recipient)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('38','contract ApproveNFTSpender {
+		data {
+			Id      int
+			Spender string "optional"
+		}
+		conditions {
+			$spender = 0
+			if $Spender {
+				$spender = AddressToId($Spender)
+				if $spender == 0 {
+					error Sprintf("Spender %%s is invalid", $Spender)
+				}
+			}
+		}
+		action {
+			ApproveNFT($Id, $spender)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('39','contract NewEscrow {
+		data {
+			Recipient string
+			Arbiter   string
+			Amount    string
+		}
+		conditions {
+			$recipient = AddressToId($Recipient)
+			$arbiter = AddressToId($Arbiter)
+			if $recipient == 0 || $arbiter == 0 {
+				error "Recipient or arbiter is invalid"
+			}
+		}
+		action {
+			$result = CreateEscrow($recipient, $arbiter, $Amount)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('40','contract CompleteEscrow {
+		data {
+			Id     int
+			Refund int "optional"
+		}
+		action {
+			if $Refund == 1 {
+				RefundEscrow($Id)
+			} else {
+				ReleaseEscrow($Id)
+			}
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('41','contract NewTimeLock {
+		data {
+			Recipient   string
+			Amount      string
+			UnlockBlock int "optional"
+			UnlockTime  int "optional"
+		}
+		conditions {
+			$recipient = AddressToId($Recipient)
+			if $recipient == 0 {
+				error Sprintf("Recipient %%s is invalid", $Recipient)
+			}
+		}
+		action {
+			$result = CreateTimeLock($recipient, $Amount, $UnlockBlock, $UnlockTime)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('42','contract UnlockTimeLock {
+		data {
+			Id int
+		}
+		action {
+			ClaimTimeLock($Id)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('43','contract NewProposal {
+		data {
+			Title       string
+			Description string "optional"
+			ParamName   string "optional"
+			ParamValue  string "optional"
+			EndBlock    int
+			ApplyBlock  int "optional"
+		}
+		action {
+			$result = CreateProposal($Title, $Description, $ParamName, $ParamValue, $EndBlock, $ApplyBlock)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('44','contract VoteForProposal {
+		data {
+			Id      int
+			Approve int "optional"
+		}
+		action {
+			VoteProposal($Id, $Approve)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('45','contract ExecuteProposal {
+		data {
+			Id int
+		}
+		action {
+			$result = ApplyProposal($Id)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('46','contract EraseRow {
+		data {
+			TableName string
+			Id        int
+		}
+		action {
+			ErasePersonalData($TableName, $Id)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('47','contract ImportLangs {
+		data {
+			Document string
+			Format   string "optional"
+		}
+		conditions {
+			EvalCondition("parameters", "changing_language", "value")
+			$list = ParseLangDocument($Document, $Format)
+		}
+		action {
+			var i int
+			while i < Len($list) {
+				var item map
+				item = $list[i]
+				if Str(item["Action"]) == "edit" {
+					CallContract("EditLang", item)
+				} else {
+					CallContract("NewLang", item)
+				}
+				i = i + 1
+			}
+			$result = Len($list)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('48','contract FreezeEcosystem {
+		data {
+			EcosystemId      int
+			UnfreezeContract string "optional"
+		}
+		conditions {
+			ContractConditions("MainCondition")
+		}
+		action {
+			FreezeEcosystem($EcosystemId, $UnfreezeContract)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('49','contract UnfreezeEcosystem {
+		data {
+			EcosystemId int
+		}
+		conditions {
+			ContractConditions("MainCondition")
+		}
+		action {
+			UnfreezeEcosystem($EcosystemId)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('50','contract ArchiveEcosystem {
+		data {
+			EcosystemId int
+		}
+		conditions {
+			ContractConditions("MainCondition")
+		}
+		action {
+			ArchiveEcosystem($EcosystemId)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('51','contract RegisterName {
+		data {
+			Name string
+		}
+		action {
+			$result = RegisterName($Name)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('52','contract RenewName {
+		data {
+			Name string
+		}
+		action {
+			RenewName($Name)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('53','contract TransferName {
+		data {
+			Name      string
+			Recipient string
+		}
+		conditions {
+			$recipient = AddressToId($Recipient)
+			if $recipient == 0 {
+				$recipient = ResolveName($Recipient)
+			}
+			if $recipient == 0 {
+				error Sprintf("Recipient %%s is invalid", $Recipient)
+			}
+		}
+		action {
+			TransferName($Name, $recipient)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('54','contract InstallApplication {
+		data {
+			Data     string
+			Manifest string
+		}
+		conditions {
+			$app = JSONToMap($Manifest)
+			$installed = ApplicationVersion($app["name"])
+		}
+		action {
+			var i int
+			if $installed {
+				var list map
+				var contracts array
+				list = JSONToMap($Data)
+				contracts = list["contracts"]
+				while i < Len(contracts) {
+					var idata map
+					idata = contracts[i]
+					$cid = GetContractByName(idata["Name"])
+					if $cid {
+						idata["Id"] = $cid
+						CallContract("EditContract", idata)
+					}
+					i = i + 1
+				}
+			}
+			var pars map
+			pars["Data"] = $Data
+			CallContract("Import", pars)
+
+			var hooks array
+			var hpars map
+			hooks = ApplicationHooks($Manifest)
+			hpars["FromVersion"] = $installed
+			hpars["ToVersion"] = $app["version"]
+			i = 0
+			while i < Len(hooks) {
+				CallContract(hooks[i], hpars)
+				i = i + 1
+			}
+			SetApplication($Manifest)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('55','contract UninstallApplication {
+		data {
+			Name string
+		}
+		action {
+			var pars map
+			$hook = RemoveApplication($Name)
+			if $hook {
+				CallContract($hook, pars)
+			}
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('56','contract SetDomain {
+		data {
+			Host string
+			Page string "optional"
+			VDE  int "optional"
+		}
+		action {
+			SetDomain($Host, $Page, $VDE)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('57','contract RemoveDomain {
+		data {
+			Host string
+		}
+		action {
+			RemoveDomain($Host)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('58','contract UploadAsset {
+		data {
+			App      string
+			Version  string
+			Name     string
+			MimeType string "optional"
+			Data     bytes
+		}
+		action {
+			$result = UploadAsset($App, $Version, $Name, $MimeType, $Data)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('59','contract AlterColumn {
+		data {
+			TableName string
+			Name      string
+			Type      string
+		}
+		action {
+			AlterColumnType($TableName, $Name, $Type)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('60','contract DropColumn {
+		data {
+			TableName string
+			Name      string
+		}
+		action {
+			RemoveColumn($TableName, $Name)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('61','contract NewIndex {
+		data {
+			TableName string
+			Name      string
+			Columns   string
+			Unique    bool "optional"
+		}
+		action {
+			CreateTableIndex($TableName, $Name, $Columns, $Unique)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('62','contract DropIndex {
+		data {
+			TableName string
+			Name      string
+		}
+		action {
+			RemoveTableIndex($TableName, $Name)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('63','contract WithdrawRewards {
+		data {
+			TokenEcosystem int "optional"
+		}
+		action {
+			$result = ClaimRewards($TokenEcosystem)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('64','contract NewValidator {
+		data {
+			Host      string
+			PublicKey string
+			Cert      string "optional"
+		}
+		action {
+			RegisterValidator($Host, $PublicKey, $Cert)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('65','contract RemoveValidator {
+		action {
+			DeregisterValidator()
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('66','contract BondStake {
+		data {
+			Validator string
+			Amount    string
+		}
+		conditions {
+			$validator = AddressToId($Validator)
+			if $validator == 0 {
+				error Sprintf("Validator %%s is invalid", $Validator)
+			}
+		}
+		action {
+			Bond($validator, $Amount)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('67','contract UnbondStake {
+		data {
+			Validator string
+			Amount    string
+		}
+		conditions {
+			$validator = AddressToId($Validator)
+			if $validator == 0 {
+				error Sprintf("Validator %%s is invalid", $Validator)
+			}
+		}
+		action {
+			$result = Unbond($validator, $Amount)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('68','contract WithdrawStake {
+		data {
+			Id int
+		}
+		action {
+			WithdrawUnbonded($Id)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('69','contract SlashStake {
+		data {
+			Validator string
+			Percent   int
+		}
+		conditions {
+			ContractConditions("MainCondition")
+			$validator = AddressToId($Validator)
+			if $validator == 0 {
+				error Sprintf("Validator %%s is invalid", $Validator)
+			}
+		}
+		action {
+			$result = SlashValidator($validator, $Percent)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('70','contract ElectNodes {
+		conditions {
+			if SysParamInt("staking_validators") == 0 {
+				error "Election of validators by stake is disabled"
+			}
+		}
+		action {
+			$result = ElectValidators()
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('71','contract BulkImportKeys {
+		data {
+			Keys string
+		}
+		conditions {
+			ContractConditions("MainCondition")
+		}
+		action {
+			$result = ImportKeys($Keys)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('72','contract NewInvite {
+		data {
+			CodeHash string
+			Roles    string "optional"
+			Amount   string "optional"
+			Expire   int "optional"
+		}
+		conditions {
+			ContractConditions("MainCondition")
+		}
+		action {
+			$result = CreateInvite($CodeHash, $Roles, $Amount, $Expire)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('73','contract AcceptInvite {
+		data {
+			Code      string
+			PublicKey string
+		}
+		action {
+			$result = IdToAddress(RedeemInvite($Code, $PublicKey))
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('74','contract CancelInvite {
+		data {
+			InviteId int
+		}
+		conditions {
+			ContractConditions("MainCondition")
+		}
+		action {
+			RevokeInvite($InviteId)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('75','contract SetRecoveryGuardians {
+		data {
+			Guardians string "optional"
+			Threshold int "optional"
+			Delay     int "optional"
+		}
+		action {
+			SetGuardians($Guardians, $Threshold, $Delay)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('76','contract ApproveAccountRecovery {
+		data {
+			Account   string
+			PublicKey string
+		}
+		conditions {
+			$account = AddressToId($Account)
+			if $account == 0 {
+				error Sprintf("Account %%s is invalid", $Account)
+			}
+		}
+		action {
+			$result = ApproveRecovery($account, $PublicKey)
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('77','contract CancelAccountRecovery {
+		action {
+			CancelRecovery()
+		}
+	}', '%[1]d','ContractConditions("MainCondition")'),
+	('78','contract FinishAccountRecovery {
+		data {
+			Account string
+		}
+		conditions {
+			$account = AddressToId($Account)
+			if $account == 0 {
+				error Sprintf("Account %%s is invalid", $Account)
+			}
 		}
 		action {
-			DBUpdateSysParam($Name, $Value, $Conditions )
+			FinishRecovery($account)
 		}
 	}', '%[1]d','ContractConditions("MainCondition")');`
 )
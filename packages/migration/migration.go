@@ -23,6 +23,10 @@
 package migration
 
 import (
+	"fmt"
+	"regexp"
+	"strconv"
+
 	"github.com/GenesisKernel/go-genesis/packages/consts"
 
 	version "github.com/hashicorp/go-version"
@@ -35,6 +39,174 @@ var migrations = []*migration{
 
 	// Initial schema
 	&migration{"0.1.6b9", migrationInitialSchema},
+
+	// Audit log of administrative actions
+	&migration{"0.1.7.1", migrationAuditLog},
+
+	// HTTP tasks of VDE scheduler
+	&migration{"0.1.7.2", migrationVDECronHTTP},
+
+	// Allowlist and timeout of HTTP requests from VDE contracts
+	&migration{"0.1.7.3", migrationVDEHTTPParams},
+
+	// Oracle data feeds
+	&migration{"0.1.7.4", migrationOracleValues},
+
+	// Bridge between Genesis networks
+	&migration{"0.1.7.5", migrationBridge},
+
+	// Fungible assets of ecosystems
+	&migration{"0.1.7.6", migrationAssets},
+
+	// Registry of non-fungible assets
+	&migration{"0.1.7.7", migrationNFT},
+
+	// Escrowed and time-locked transfers
+	&migration{"0.1.7.8", migrationEscrow},
+
+	// Fee policy of ecosystems
+	&migration{"0.1.7.9", migrationFeePolicy},
+
+	// Governance proposals and votes
+	&migration{"0.1.7.10", migrationGovernance},
+
+	// Hierarchy of roles
+	&migration{"0.1.7.11", migrationRoleHierarchy},
+
+	// Data keys of encrypted columns in VDE
+	&migration{"0.1.7.12", migrationDataKeys},
+
+	// Redaction of personal data in rollback records
+	&migration{"0.1.7.13", migrationErasure},
+
+	// Bindings of external identities to keys
+	&migration{"0.1.7.14", migrationIdentityKeys},
+
+	// Issued sessions of keys and their revocation
+	&migration{"0.1.7.15", migrationSessions},
+
+	// Email and SMS messages of VDE
+	&migration{"0.1.7.16", migrationVDEMessages},
+
+	// Money format parameters of ecosystems
+	&migration{"0.1.7.17", migrationMoneyFormat},
+
+	// State hashes of blocks
+	&migration{"0.1.7.18", migrationBlockStateHash},
+
+	// Quotas and fee of the ecosystem creation
+	&migration{"0.1.7.19", migrationEcosystemQuota},
+
+	// Freezing and archiving of ecosystems
+	&migration{"0.1.7.20", migrationEcosystemFreeze},
+
+	// Index of contract calls by keys
+	&migration{"0.1.7.21", migrationContractCalls},
+
+	// Registry of account names
+	&migration{"0.1.7.22", migrationAccountNames},
+
+	// Nonces of transactions
+	&migration{"0.1.7.23", migrationKeyNonces},
+
+	// Limits of transaction sizes by types
+	&migration{"0.1.7.24", migrationMaxTxSizeByType},
+
+	// Reserved lane of block for system transactions
+	&migration{"0.1.7.25", migrationSystemTxLanes},
+
+	// Public key of CA of permissioned network
+	&migration{"0.1.7.26", migrationNetworkCA},
+
+	// Expiration of transactions in their status
+	&migration{"0.1.7.27", migrationTxExpiration},
+
+	// Registry of installed applications
+	&migration{"0.1.7.28", migrationApplications},
+
+	// Data migrations applied after upgrades of node
+	&migration{"0.1.7.29", migrationNodeUpgrades},
+
+	// Changes of system parameters scheduled to block heights
+	&migration{"0.1.7.30", migrationSysParamSchedule},
+
+	// Custom domains of ecosystems
+	&migration{"0.1.7.31", migrationDomains},
+
+	// Static assets of applications
+	&migration{"0.1.7.32", migrationBinaries},
+
+	// Limit of nested contract calls
+	&migration{"0.1.7.33", migrationMaxCallDepth},
+
+	// Rollback of changes of table schemas
+	&migration{"0.1.7.34", migrationSchemaRollback},
+
+	// Limit of rows scanned by aggregate functions
+	&migration{"0.1.7.35", migrationMaxAggregateRows},
+
+	// Routing of transaction fees
+	&migration{"0.1.7.36", migrationFeeRouting},
+
+	// Rewards of block producers and validators
+	&migration{"0.1.7.37", migrationValidatorRewards},
+
+	// Delegated staking of validators
+	&migration{"0.1.7.38", migrationStaking},
+
+	// Invite codes of members
+	&migration{"0.1.7.39", migrationInviteCodes},
+
+	// Recovery of accounts by guardian keys
+	&migration{"0.1.7.40", migrationRecovery},
+
+	// X-Request-ID of API calls in the queue and statuses of transactions
+	&migration{"0.1.7.41", migrationRequestID},
+
+	// Marks of blocks which are being applied for the recovery after crash
+	&migration{"0.1.7.42", migrationApplyMarks},
+
+	// Block version 2 with the state hash in the header. It is activated at the block state_hash_block
+	// which must be scheduled by the network, nodes of older versions can't process blocks after it
+	&migration{"0.1.7.43", migrationStateHashBlock},
+
+	// Contract of claiming rewards in the existing first ecosystem
+	&migration{"0.1.7.44", migrationRewardContracts},
+
+	// Contracts of staking in the existing first ecosystem
+	&migration{"0.1.7.45", migrationStakingContracts},
+
+	// Contract of bulk import of keys in the existing first ecosystem
+	&migration{"0.1.7.46", migrationImportKeysContract},
+
+	// Contracts of invite codes in the existing first ecosystem
+	&migration{"0.1.7.47", migrationInviteContracts},
+
+	// Contracts of account recovery in the existing first ecosystem
+	&migration{"0.1.7.48", migrationRecoveryContracts},
+
+	// Default system_tx_contracts without the nonexistent UpdFullNodes contract
+	&migration{"0.1.7.49", migrationSystemTxContractsDefault},
+
+	// Activation block of transaction nonces. Transactions with nonce are rejected before the block tx_nonce_block
+	&migration{"0.1.7.50", migrationTxNonceBlock},
+
+	// Activation block of fee rewards. Fees of producers and validators are paid to their wallets before the block fee_reward_block
+	&migration{"0.1.7.51", migrationFeeRewardBlock},
+}
+
+// legacyVersions are the versions of migrations after 0.1.6b9 which had been numbered as 0.1.6bN.
+// The prerelease tags are compared as strings so they are renumbered as 0.1.7.N
+var legacyVersions = regexp.MustCompile(`^0\.1\.6b(\d{2})$`)
+
+// legacyVersion returns the current number of the migration which was applied with the legacy version
+func legacyVersion(ver string) string {
+	match := legacyVersions.FindStringSubmatch(ver)
+	if len(match) == 0 {
+		return ver
+	}
+	n, _ := strconv.Atoi(match[1])
+	return fmt.Sprintf("0.1.7.%d", n-12)
 }
 
 type migration struct {
@@ -54,7 +226,7 @@ func migrate(db database, appVer *version.Version, migrations []*migration) erro
 		return err
 	}
 
-	dbVer, err := version.NewVersion(dbVerString)
+	dbVer, err := version.NewVersion(legacyVersion(dbVerString))
 	if err != nil {
 		log.WithFields(log.Fields{"type": consts.MigrationError, "err": err}).Errorf("parse version")
 		return err
@@ -23,8 +23,11 @@
 package migration
 
 import (
+	"fmt"
 	"testing"
 
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+
 	version "github.com/hashicorp/go-version"
 )
 
@@ -84,3 +87,34 @@ func TestMockMigration(t *testing.T) {
 		t.Errorf("current version expected 0.0.2 get %s", v)
 	}
 }
+
+func TestMigrateFromInitialSchema(t *testing.T) {
+	appVer := version.Must(version.NewVersion(consts.VERSION))
+
+	db := createDBMock("0.1.6b9")
+	if err := migrate(db, appVer, migrations); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(db.versions)-1, len(migrations)-2; got != want {
+		t.Errorf("expected %d applied migrations, got %d", want, got)
+	}
+	if v, _ := db.CurrentVersion(); v != consts.VERSION {
+		t.Errorf("current version expected %s get %s", consts.VERSION, v)
+	}
+	for i := 2; i < len(db.versions); i++ {
+		prev := version.Must(version.NewVersion(db.versions[i-1]))
+		if !prev.LessThan(version.Must(version.NewVersion(db.versions[i]))) {
+			t.Errorf("migration %s is applied after %s", db.versions[i], db.versions[i-1])
+		}
+	}
+
+	for legacy, first := range map[string]int{"0.1.6b61": 50, "0.1.6b40": 29} {
+		db = createDBMock(legacy)
+		if err := migrate(db, appVer, migrations); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := len(db.versions)-1, len(migrations)-1-first; got != want || db.versions[1] != fmt.Sprintf("0.1.7.%d", first) {
+			t.Errorf("legacy version %s: unexpected migrations %v", legacy, db.versions[1:])
+		}
+	}
+}
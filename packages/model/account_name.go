@@ -0,0 +1,69 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package model
+
+import "fmt"
+
+// AccountName is the human-readable name of the key in the ecosystem
+type AccountName struct {
+	tableName string
+	ID        int64  `gorm:"primary_key;not null" json:"id"`
+	Name      string `gorm:"not null;size:64" json:"name"`
+	KeyID     int64  `gorm:"not null" json:"key_id"`
+	Expire    int64  `gorm:"not null" json:"expire"`
+	BlockID   int64  `gorm:"not null" json:"block_id"`
+}
+
+// SetTablePrefix is setting table prefix
+func (an *AccountName) SetTablePrefix(prefix int64) {
+	an.tableName = fmt.Sprintf("%d_names", prefix)
+}
+
+// TableName returns name of table
+func (an AccountName) TableName() string {
+	return an.tableName
+}
+
+// GetByName is retrieving the record by the name, expired names are also returned
+func (an *AccountName) GetByName(transaction *DbTransaction, name string) (bool, error) {
+	return isFound(GetDB(transaction).Where("name = ?", name).First(an))
+}
+
+// ResolveAccountName returns the key of the name which hasn't expired at the time
+func ResolveAccountName(prefix int64, name string, now int64) (int64, error) {
+	an := &AccountName{}
+	an.SetTablePrefix(prefix)
+	found, err := an.GetByName(nil, name)
+	if err != nil || !found || an.Expire < now {
+		return 0, err
+	}
+	return an.KeyID, nil
+}
+
+// GetAccountNames returns the names of the key which haven't expired at the time
+func GetAccountNames(prefix, keyID, now int64) ([]AccountName, error) {
+	var names []AccountName
+	err := DBConn.Table(fmt.Sprintf("%d_names", prefix)).Where("key_id = ? AND expire >= ?", keyID, now).
+		Order("id").Find(&names).Error
+	return names, err
+}
@@ -0,0 +1,58 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package model
+
+import "fmt"
+
+// Application is the installed application of the ecosystem. The version is empty if the application has been uninstalled
+type Application struct {
+	tableName string
+	ID        int64  `gorm:"primary_key;not null" json:"id"`
+	Name      string `gorm:"not null;size:100" json:"name"`
+	Version   string `gorm:"not null;size:32" json:"version"`
+	Manifest  string `gorm:"not null" json:"manifest"`
+	BlockID   int64  `gorm:"not null" json:"block_id"`
+}
+
+// SetTablePrefix is setting table prefix
+func (a *Application) SetTablePrefix(prefix int64) {
+	a.tableName = fmt.Sprintf("%d_applications", prefix)
+}
+
+// TableName returns name of table
+func (a Application) TableName() string {
+	return a.tableName
+}
+
+// GetByName is retrieving the application by its name
+func (a *Application) GetByName(transaction *DbTransaction, name string) (bool, error) {
+	return isFound(GetDB(transaction).Where("name = ?", name).First(a))
+}
+
+// GetApplications returns the installed applications of the ecosystem
+func GetApplications(transaction *DbTransaction, prefix int64) ([]Application, error) {
+	var list []Application
+	err := GetDB(transaction).Table(fmt.Sprintf("%d_applications", prefix)).Where("version <> ''").
+		Order("name").Find(&list).Error
+	return list, err
+}
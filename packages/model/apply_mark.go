@@ -0,0 +1,58 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package model
+
+import "time"
+
+// ApplyMark is the first block which is changed by the application or rollback of blocks. The mark is removed
+// by the database transaction which finishes the operation, so it remains only if the operation has been interrupted
+type ApplyMark struct {
+	BlockID int64 `gorm:"primary_key;not null"`
+	Time    int64 `gorm:"not null"`
+}
+
+// TableName returns name of table
+func (am *ApplyMark) TableName() string {
+	return "apply_marks"
+}
+
+// Get returns the lowest mark
+func (am *ApplyMark) Get() (bool, error) {
+	return isFound(DBConn.Order("block_id").First(am))
+}
+
+// SetApplyMark marks the block before its application or rollback
+func SetApplyMark(blockID int64) error {
+	return DBConn.Exec(`INSERT INTO "apply_marks" (block_id, time) VALUES (?, ?) ON CONFLICT DO NOTHING`,
+		blockID, time.Now().Unix()).Error
+}
+
+// DeleteApplyMark removes the mark of the block
+func DeleteApplyMark(transaction *DbTransaction, blockID int64) error {
+	return GetDB(transaction).Exec(`DELETE FROM "apply_marks" WHERE block_id = ?`, blockID).Error
+}
+
+// DeleteApplyMarks removes all marks
+func DeleteApplyMarks(transaction *DbTransaction) error {
+	return GetDB(transaction).Exec(`DELETE FROM "apply_marks"`).Error
+}
@@ -0,0 +1,82 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// archiveRow is a line of the ecosystem archive
+type archiveRow struct {
+	Table string          `json:"table"`
+	Row   json.RawMessage `json:"row"`
+}
+
+// GetEcosystemTables returns the names of all tables of the ecosystem
+func GetEcosystemTables(ecosystemID int64) ([]string, error) {
+	var tables []string
+	err := DBConn.Raw(`SELECT table_name FROM information_schema.tables
+		WHERE table_schema = 'public' AND table_name LIKE ? ORDER BY table_name`,
+		fmt.Sprintf(`%d\_%%`, ecosystemID)).Pluck(`table_name`, &tables).Error
+	return tables, err
+}
+
+// ExportTable writes all rows of the table into w as JSON lines
+func ExportTable(w io.Writer, table string) (int64, error) {
+	rows, err := DBConn.Raw(fmt.Sprintf(`SELECT row_to_json(t)::text FROM "%s" t ORDER BY id`, table)).Rows()
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var count int64
+	enc := json.NewEncoder(w)
+	for rows.Next() {
+		var row string
+		if err = rows.Scan(&row); err != nil {
+			return count, err
+		}
+		if err = enc.Encode(archiveRow{Table: table, Row: json.RawMessage(row)}); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, rows.Err()
+}
+
+// DropEcosystemTables removes the tables of the ecosystem in one transaction
+func DropEcosystemTables(tables []string) error {
+	tr, err := StartTransaction()
+	if err != nil {
+		return err
+	}
+	for _, table := range tables {
+		if err = GetDB(tr).Exec(fmt.Sprintf(`DROP TABLE IF EXISTS "%s"`, table)).Error; err != nil {
+			tr.Rollback()
+			return err
+		}
+	}
+	return tr.Commit()
+}
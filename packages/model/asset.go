@@ -0,0 +1,106 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package model
+
+import "fmt"
+
+// Asset is the fungible token of the ecosystem
+type Asset struct {
+	tableName  string
+	ID         int64  `gorm:"primary_key;not null" json:"id"`
+	Symbol     string `gorm:"not null;size:16" json:"symbol"`
+	Name       string `gorm:"not null;size:255" json:"name"`
+	Supply     string `gorm:"not null" json:"supply"`
+	MaxSupply  string `gorm:"not null" json:"max_supply"`
+	Owner      int64  `gorm:"not null" json:"owner"`
+	Conditions string `gorm:"not null" json:"conditions"`
+	BlockID    int64  `gorm:"not null" json:"block_id"`
+}
+
+// SetTablePrefix is setting table prefix
+func (a *Asset) SetTablePrefix(prefix int64) {
+	a.tableName = fmt.Sprintf("%d_assets", prefix)
+}
+
+// TableName returns name of table
+func (a Asset) TableName() string {
+	return a.tableName
+}
+
+// GetBySymbol is retrieving the asset by its symbol
+func (a *Asset) GetBySymbol(transaction *DbTransaction, symbol string) (bool, error) {
+	return isFound(GetDB(transaction).Where("symbol = ?", symbol).First(a))
+}
+
+// GetAssets returns the assets of the ecosystem
+func GetAssets(prefix int64, limit, offset int) ([]Asset, error) {
+	var assets []Asset
+	err := DBConn.Table(fmt.Sprintf("%d_assets", prefix)).Order("id").Limit(limit).Offset(offset).Find(&assets).Error
+	return assets, err
+}
+
+// AssetBalance is the amount of the asset on the wallet
+type AssetBalance struct {
+	tableName string
+	ID        int64  `gorm:"primary_key;not null" json:"-"`
+	AssetID   int64  `gorm:"not null" json:"asset_id"`
+	Wallet    int64  `gorm:"not null" json:"wallet"`
+	Amount    string `gorm:"not null" json:"amount"`
+}
+
+// SetTablePrefix is setting table prefix
+func (ab *AssetBalance) SetTablePrefix(prefix int64) {
+	ab.tableName = fmt.Sprintf("%d_asset_balances", prefix)
+}
+
+// TableName returns name of table
+func (ab AssetBalance) TableName() string {
+	return ab.tableName
+}
+
+// Get is retrieving the balance of the wallet
+func (ab *AssetBalance) Get(transaction *DbTransaction, assetID, wallet int64) (bool, error) {
+	return isFound(GetDB(transaction).Where("asset_id = ? AND wallet = ?", assetID, wallet).First(ab))
+}
+
+// AssetHistory is the record of the mint, burn or transfer of the asset.
+// Sender is zero for minted tokens and recipient is zero for burnt tokens
+type AssetHistory struct {
+	ID        int64  `gorm:"primary_key;not null" json:"id"`
+	AssetID   int64  `gorm:"not null" json:"asset_id"`
+	Sender    int64  `gorm:"not null" json:"sender"`
+	Recipient int64  `gorm:"not null" json:"recipient"`
+	Amount    string `gorm:"not null" json:"amount"`
+	BlockID   int64  `gorm:"not null" json:"block_id"`
+}
+
+// GetAssetHistory returns the history of the asset. If wallet isn't zero then only its records are returned
+func GetAssetHistory(prefix, assetID, wallet int64, limit, offset int) ([]AssetHistory, error) {
+	var history []AssetHistory
+	query := DBConn.Table(fmt.Sprintf("%d_asset_history", prefix)).Where("asset_id = ?", assetID)
+	if wallet != 0 {
+		query = query.Where("sender = ? OR recipient = ?", wallet, wallet)
+	}
+	err := query.Order("id desc").Limit(limit).Offset(offset).Find(&history).Error
+	return history, err
+}
@@ -0,0 +1,67 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package model
+
+// Audit actions
+const (
+	AuditAdmin    = "admin"
+	AuditVDE      = "vde"
+	AuditSysParam = "system_parameter"
+	AuditErasure  = "erasure"
+)
+
+// AuditLog is an append-only record of administrative and key-holder action
+type AuditLog struct {
+	ID        int64  `gorm:"primary_key;not null"`
+	Time      int64  `gorm:"not null"`
+	KeyID     int64  `gorm:"not null"`
+	Ecosystem int64  `gorm:"not null"`
+	Action    string `gorm:"not null;size:64"`
+	Target    string `gorm:"not null;size:255"`
+	Remote    string `gorm:"not null;size:255"`
+	Digest    []byte `gorm:"not null"`
+}
+
+// TableName returns name of table
+func (al *AuditLog) TableName() string {
+	return "audit_log"
+}
+
+// Create is creating record of model
+func (al *AuditLog) Create(transaction *DbTransaction) error {
+	return GetDB(transaction).Create(al).Error
+}
+
+// GetAuditLog returns the records of audit log in reverse order, zero keyID and empty action are ignored
+func GetAuditLog(keyID int64, action string, offset, limit int64) ([]AuditLog, error) {
+	var list []AuditLog
+	query := DBConn.Order("id desc").Offset(offset).Limit(limit)
+	if keyID != 0 {
+		query = query.Where("key_id = ?", keyID)
+	}
+	if len(action) > 0 {
+		query = query.Where("action = ?", action)
+	}
+	err := query.Find(&list).Error
+	return list, err
+}
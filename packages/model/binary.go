@@ -0,0 +1,61 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package model
+
+import "fmt"
+
+// Binary is the static asset of the application, for example, script, style sheet or image
+type Binary struct {
+	tableName string
+	ID        int64  `gorm:"primary_key;not null" json:"id"`
+	App       string `gorm:"not null;size:100" json:"app"`
+	Name      string `gorm:"not null;size:255" json:"name"`
+	Version   string `gorm:"not null;size:32" json:"version"`
+	MimeType  string `gorm:"not null;size:255" json:"mime_type"`
+	Data      []byte `gorm:"not null" json:"-"`
+	Checksum  string `gorm:"not null;size:64" json:"checksum"`
+	BlockID   int64  `gorm:"not null" json:"block_id"`
+}
+
+// SetTablePrefix is setting table prefix
+func (b *Binary) SetTablePrefix(prefix int64) {
+	b.tableName = fmt.Sprintf("%d_binaries", prefix)
+}
+
+// TableName returns name of table
+func (b Binary) TableName() string {
+	return b.tableName
+}
+
+// Get is retrieving the asset of the application by its name
+func (b *Binary) Get(transaction *DbTransaction, app, name string) (bool, error) {
+	return isFound(GetDB(transaction).Where("app = ? and name = ?", app, name).First(b))
+}
+
+// GetBinaries returns the assets of the application without their data
+func GetBinaries(prefix int64, app string) ([]Binary, error) {
+	var list []Binary
+	err := DBConn.Table(fmt.Sprintf("%d_binaries", prefix)).Select("id,app,name,version,mime_type,checksum,block_id").
+		Where("app = ?", app).Order("name").Find(&list).Error
+	return list, err
+}
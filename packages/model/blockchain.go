@@ -27,6 +27,7 @@ type Block struct {
 	ID            int64  `gorm:"primary_key;not_null"`
 	Hash          []byte `gorm:"not null"`
 	RollbacksHash []byte `gorm:"not null"`
+	StateHash     []byte `gorm:"not null"`
 	Data          []byte `gorm:"not null"`
 	EcosystemID   int64  `gorm:"not null"`
 	KeyID         int64  `gorm:"not null"`
@@ -50,6 +51,11 @@ func (b *Block) Get(blockID int64) (bool, error) {
 	return isFound(DBConn.Where("id = ?", blockID).First(b))
 }
 
+// GetByHash is retrieving model from database by block hash
+func (b *Block) GetByHash(hash []byte) (bool, error) {
+	return isFound(DBConn.Where("hash = ?", hash).First(b))
+}
+
 // GetMaxBlock returns last block existence
 func (b *Block) GetMaxBlock() (bool, error) {
 	return isFound(DBConn.Last(b))
@@ -0,0 +1,74 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package model
+
+// BridgeTransfer is the outgoing transfer to other network
+type BridgeTransfer struct {
+	ID        int64  `gorm:"primary_key;not null"`
+	Network   int64  `gorm:"not null"`
+	Ecosystem int64  `gorm:"not null"`
+	Sender    int64  `gorm:"not null"`
+	Recipient int64  `gorm:"not null"`
+	Amount    string `gorm:"not null"`
+	BlockID   int64  `gorm:"not null"`
+}
+
+// TableName returns name of table
+func (BridgeTransfer) TableName() string {
+	return "bridge_transfers"
+}
+
+// GetBridgeTransfers returns the transfers to the network after fromID which are included in blocks up to maxBlockID
+func GetBridgeTransfers(network, fromID, maxBlockID int64, limit int) ([]BridgeTransfer, error) {
+	var transfers []BridgeTransfer
+	err := DBConn.Where("network = ? AND id > ? AND block_id <= ?", network, fromID, maxBlockID).
+		Order("id").Limit(limit).Find(&transfers).Error
+	return transfers, err
+}
+
+// BridgeReceipt is the incoming transfer from other network
+type BridgeReceipt struct {
+	ID         int64  `gorm:"primary_key;not null"`
+	Network    int64  `gorm:"not null"`
+	TransferID int64  `gorm:"not null"`
+	Recipient  int64  `gorm:"not null"`
+	Amount     string `gorm:"not null"`
+	BlockID    int64  `gorm:"not null"`
+}
+
+// TableName returns name of table
+func (BridgeReceipt) TableName() string {
+	return "bridge_receipts"
+}
+
+// Get is retrieving the receipt of the transfer from the network
+func (br *BridgeReceipt) Get(transaction *DbTransaction, network, transferID int64) (bool, error) {
+	return isFound(GetDB(transaction).Where("network = ? AND transfer_id = ?", network, transferID).First(br))
+}
+
+// GetLastBridgeTransferID returns the greatest id of the received transfer from the network
+func GetLastBridgeTransferID(network int64) (int64, error) {
+	var id int64
+	err := DBConn.Raw(`SELECT COALESCE(max(transfer_id), 0) FROM "bridge_receipts" WHERE network = ?`, network).Row().Scan(&id)
+	return id, err
+}
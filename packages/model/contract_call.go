@@ -0,0 +1,83 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package model
+
+// Statuses of contract calls
+const (
+	CallSuccess = 0
+	CallFailed  = 1
+)
+
+// ContractCall is an index record of the contract invocation by the key
+type ContractCall struct {
+	ID         int64  `gorm:"primary_key;not null"`
+	Hash       []byte `gorm:"not null"`
+	KeyID      int64  `gorm:"not null"`
+	Ecosystem  int64  `gorm:"not null"`
+	Contract   string `gorm:"not null;size:255"`
+	ParamsHash []byte `gorm:"not null"`
+	BlockID    int64  `gorm:"not null"`
+	Time       int64  `gorm:"not null"`
+	Status     int64  `gorm:"not null"`
+	Error      string `gorm:"not null;size:255"`
+}
+
+// TableName returns name of table
+func (cc *ContractCall) TableName() string {
+	return "contract_calls"
+}
+
+// Create is creating record of model
+func (cc *ContractCall) Create(transaction *DbTransaction) error {
+	return GetDB(transaction).Create(cc).Error
+}
+
+// DeleteContractCallsByHash deletes the records of the transaction
+func DeleteContractCallsByHash(transaction *DbTransaction, hash []byte) error {
+	return GetDB(transaction).Where("hash = ?", hash).Delete(&ContractCall{}).Error
+}
+
+// ContractCallFilter contains the conditions of GetContractCalls, zero values are ignored
+type ContractCallFilter struct {
+	KeyID    int64
+	Contract string
+	From     int64
+	To       int64
+}
+
+// GetContractCalls returns the contract calls of the key in reverse order
+func GetContractCalls(filter ContractCallFilter, offset, limit int64) ([]ContractCall, error) {
+	var list []ContractCall
+	query := DBConn.Where("key_id = ?", filter.KeyID).Order("id desc").Offset(offset).Limit(limit)
+	if len(filter.Contract) > 0 {
+		query = query.Where("contract = ?", filter.Contract)
+	}
+	if filter.From > 0 {
+		query = query.Where("time >= ?", filter.From)
+	}
+	if filter.To > 0 {
+		query = query.Where("time <= ?", filter.To)
+	}
+	err := query.Find(&list).Error
+	return list, err
+}
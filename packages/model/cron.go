@@ -31,6 +31,9 @@ type Cron struct {
 	ID        int64
 	Cron      string
 	Contract  string
+	URL       string `gorm:"column:url"`
+	Method    string
+	Body      string
 }
 
 // SetTablePrefix is setting table prefix
@@ -52,9 +55,17 @@ func (c *Cron) Get(id int64) (bool, error) {
 func (c *Cron) GetAllCronTasks() ([]*Cron, error) {
 	var crons []*Cron
 	err := DBConn.Table(c.TableName()).Find(&crons).Error
+	for _, cron := range crons {
+		cron.tableName = c.tableName
+	}
 	return crons, err
 }
 
+// IsHTTP returns true if the task requests an external URL before calling the contract
+func (c *Cron) IsHTTP() bool {
+	return len(c.URL) > 0
+}
+
 // UID returns unique identifier for cron task
 func (c *Cron) UID() string {
 	return fmt.Sprintf("%s_%d", c.tableName, c.ID)
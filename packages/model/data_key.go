@@ -0,0 +1,56 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package model
+
+// DataKey is the data key of VDE ecosystem which is encrypted with the master key of the node
+type DataKey struct {
+	tableName string
+	ID        int64  `gorm:"primary_key;not null" json:"id"`
+	DataKey   []byte `gorm:"not null" json:"-"`
+	CreatedAt int64  `gorm:"not null" json:"created_at"`
+}
+
+// SetTablePrefix is setting table prefix
+func (k *DataKey) SetTablePrefix(prefix string) {
+	k.tableName = prefix + "_data_keys"
+}
+
+// TableName returns name of table
+func (k DataKey) TableName() string {
+	return k.tableName
+}
+
+// Get is retrieving the data key by its identifier
+func (k *DataKey) Get(transaction *DbTransaction, id int64) (bool, error) {
+	return isFound(GetDB(transaction).Where("id = ?", id).First(k))
+}
+
+// GetLast is retrieving the current data key
+func (k *DataKey) GetLast(transaction *DbTransaction) (bool, error) {
+	return isFound(GetDB(transaction).Order("id desc").First(k))
+}
+
+// Create is creating record of model
+func (k *DataKey) Create(transaction *DbTransaction) error {
+	return GetDB(transaction).Create(k).Error
+}
@@ -71,6 +71,9 @@ func GormInit(host string, port int, user string, pass string, dbName string) er
 		DBConn.LogMode(true)
 		DBConn.SetLogger(log.New())
 	}
+	res := conf.Resources()
+	DBConn.DB().SetMaxIdleConns(res.DBMaxIdleConns)
+	DBConn.DB().SetMaxOpenConns(res.DBMaxOpenConns)
 	return nil
 }
 
@@ -88,7 +91,8 @@ func GormClose() error {
 
 // DbTransaction is gorm.DB wrapper
 type DbTransaction struct {
-	conn *gorm.DB
+	conn      *gorm.DB
+	rollbacks []*RollbackTx
 }
 
 // StartTransaction is beginning transaction
@@ -106,11 +110,16 @@ func StartTransaction() (*DbTransaction, error) {
 
 // Rollback is transaction rollback
 func (tr *DbTransaction) Rollback() {
+	tr.DiscardRollbacks()
 	tr.conn.Rollback()
 }
 
 // Commit is transaction commit
 func (tr *DbTransaction) Commit() error {
+	if err := tr.FlushRollbacks(); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("writing rollback records")
+		return err
+	}
 	return tr.conn.Commit().Error
 }
 
@@ -174,7 +183,12 @@ func ExecSchema() error {
 
 // Update is updating table rows
 func Update(transaction *DbTransaction, tblname, set, where string) error {
-	return GetDB(transaction).Exec(`UPDATE "` + strings.Trim(tblname, `"`) + `" SET ` + set + " " + where).Error
+	query := `UPDATE "` + strings.Trim(tblname, `"`) + `" SET ` + set + " " + where
+	if fastQueries() {
+		_, err := sqlConn(transaction).Exec(query)
+		return err
+	}
+	return GetDB(transaction).Exec(query).Error
 }
 
 // Delete is deleting table rows
@@ -197,7 +211,13 @@ func GetColumnCount(tableName string) (int64, error) {
 }
 
 // SendTx is creates transaction
-func SendTx(txType int64, adminWallet int64, data []byte) ([]byte, error) {
+func SendTx(txType int64, adminWallet int64, data []byte, requestID string) ([]byte, error) {
+	return SendExpiringTx(txType, adminWallet, 0, 0, data, requestID)
+}
+
+// SendExpiringTx creates the transaction which is valid until expireBlock and expireTime, 0 means no limit.
+// requestID is the id of API call which is kept in the queue and the status of the transaction
+func SendExpiringTx(txType int64, adminWallet int64, expireBlock, expireTime int64, data []byte, requestID string) ([]byte, error) {
 	hash, err := crypto.Hash(data)
 	if err != nil {
 		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("hashing data")
@@ -208,6 +228,10 @@ func SendTx(txType int64, adminWallet int64, data []byte) ([]byte, error) {
 		Time:     time.Now().Unix(),
 		Type:     txType,
 		WalletID: adminWallet,
+
+		ExpireBlock: expireBlock,
+		ExpireTime:  expireTime,
+		RequestID:   requestID,
 	}
 	err = ts.Create()
 	if err != nil {
@@ -215,8 +239,9 @@ func SendTx(txType int64, adminWallet int64, data []byte) ([]byte, error) {
 		return nil, err
 	}
 	qtx := &QueueTx{
-		Hash: hash,
-		Data: data,
+		Hash:      hash,
+		Data:      data,
+		RequestID: requestID,
 	}
 	err = qtx.Create()
 	return hash, err
@@ -403,6 +428,10 @@ func InitDB(cfg conf.DBConfig) error {
 		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("executing db schema")
 		return err
 	}
+	if err = markUpgrades(); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("marking upgrades")
+		return err
+	}
 
 	install := &Install{Progress: ProgressComplete}
 	if err = install.Create(); err != nil {
@@ -412,3 +441,31 @@ func InitDB(cfg conf.DBConfig) error {
 
 	return nil
 }
+
+// PingDB checks the connection to the database
+func PingDB() error {
+	if DBConn == nil {
+		return ErrDBConn
+	}
+	return DBConn.DB().Ping()
+}
+
+// CreateDatabase creates the database if it doesn't exist
+func CreateDatabase(name string) error {
+	if DBConn == nil {
+		return ErrDBConn
+	}
+	var count int64
+	if err := DBConn.Raw(`SELECT count(*) FROM pg_database WHERE datname = ?`, name).Row().Scan(&count); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("checking database")
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	if err := DBConn.Exec(fmt.Sprintf(`CREATE DATABASE "%s"`, strings.Replace(name, `"`, `""`, -1))).Error; err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err, "name": name}).Error("creating database")
+		return err
+	}
+	return nil
+}
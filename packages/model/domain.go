@@ -0,0 +1,50 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package model
+
+// Domain is the hostname which is bound to the content of the ecosystem
+type Domain struct {
+	ID        int64  `gorm:"primary_key;not null" json:"id"`
+	Host      string `gorm:"not null;size:255" json:"host"`
+	Ecosystem int64  `gorm:"not null" json:"ecosystem"`
+	VDE       int64  `gorm:"column:vde;not null" json:"vde"`
+	Page      string `gorm:"not null;size:255" json:"page"`
+	BlockID   int64  `gorm:"not null" json:"block_id"`
+}
+
+// TableName returns name of table
+func (d Domain) TableName() string {
+	return "ecosystem_domains"
+}
+
+// GetByHost is retrieving the domain by the hostname
+func (d *Domain) GetByHost(transaction *DbTransaction, host string) (bool, error) {
+	return isFound(GetDB(transaction).Where("host = ?", host).First(d))
+}
+
+// GetDomains returns the domains of the ecosystem
+func GetDomains(ecosystem int64) ([]Domain, error) {
+	var domains []Domain
+	err := DBConn.Where("ecosystem = ?", ecosystem).Order("host").Find(&domains).Error
+	return domains, err
+}
@@ -0,0 +1,89 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package model
+
+import "fmt"
+
+const (
+	// EscrowOpen is the status of the escrow which holds the tokens
+	EscrowOpen = iota
+	// EscrowReleased is the status of the escrow which tokens have been paid to the recipient
+	EscrowReleased
+	// EscrowRefunded is the status of the escrow which tokens have been returned to the sender
+	EscrowRefunded
+)
+
+// Escrow is the transfer which is held until the arbiter decides
+type Escrow struct {
+	tableName string
+	ID        int64  `gorm:"primary_key;not null" json:"id"`
+	Sender    int64  `gorm:"not null" json:"sender"`
+	Recipient int64  `gorm:"not null" json:"recipient"`
+	Arbiter   int64  `gorm:"not null" json:"arbiter"`
+	Amount    string `gorm:"not null" json:"amount"`
+	Status    int64  `gorm:"not null" json:"status"`
+	BlockID   int64  `gorm:"not null" json:"block_id"`
+}
+
+// SetTablePrefix is setting table prefix
+func (e *Escrow) SetTablePrefix(prefix int64) {
+	e.tableName = fmt.Sprintf("%d_escrows", prefix)
+}
+
+// TableName returns name of table
+func (e Escrow) TableName() string {
+	return e.tableName
+}
+
+// Get is retrieving the escrow by its identifier
+func (e *Escrow) Get(transaction *DbTransaction, id int64) (bool, error) {
+	return isFound(GetDB(transaction).Where("id = ?", id).First(e))
+}
+
+// TimeLock is the transfer which can be claimed by the recipient after the block or the time
+type TimeLock struct {
+	tableName   string
+	ID          int64  `gorm:"primary_key;not null" json:"id"`
+	Sender      int64  `gorm:"not null" json:"sender"`
+	Recipient   int64  `gorm:"not null" json:"recipient"`
+	Amount      string `gorm:"not null" json:"amount"`
+	UnlockBlock int64  `gorm:"not null" json:"unlock_block"`
+	UnlockTime  int64  `gorm:"not null" json:"unlock_time"`
+	Claimed     int64  `gorm:"not null" json:"claimed"`
+	BlockID     int64  `gorm:"not null" json:"block_id"`
+}
+
+// SetTablePrefix is setting table prefix
+func (t *TimeLock) SetTablePrefix(prefix int64) {
+	t.tableName = fmt.Sprintf("%d_timelocks", prefix)
+}
+
+// TableName returns name of table
+func (t TimeLock) TableName() string {
+	return t.tableName
+}
+
+// Get is retrieving the time-lock by its identifier
+func (t *TimeLock) Get(transaction *DbTransaction, id int64) (bool, error) {
+	return isFound(GetDB(transaction).Where("id = ?", id).First(t))
+}
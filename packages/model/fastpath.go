@@ -0,0 +1,82 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package model
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+
+	"github.com/jinzhu/gorm"
+)
+
+// fastQueries reports whether the hot queries bypass gorm
+func fastQueries() bool {
+	return conf.FastQueries()
+}
+
+// sqlConn returns the database/sql connection of the transaction
+func sqlConn(transaction *DbTransaction) gorm.SQLCommon {
+	return GetDB(transaction).CommonDB()
+}
+
+// scanFound converts sql.ErrNoRows into the result of isFound
+func scanFound(err error) (bool, error) {
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (m *Key) getFast(transaction *DbTransaction, wallet int64) (bool, error) {
+	var amount sql.NullString
+	found, err := scanFound(sqlConn(transaction).QueryRow(
+		fmt.Sprintf(`SELECT "id", "pub", "amount" FROM "%s" WHERE "id" = $1`, m.tableName), wallet).
+		Scan(&m.ID, &m.PublicKey, &amount))
+	m.Amount = amount.String
+	return found, err
+}
+
+func (sp *StateParameter) getFast(transaction *DbTransaction, name string) (bool, error) {
+	return scanFound(sqlConn(transaction).QueryRow(
+		fmt.Sprintf(`SELECT "id", "name", "value", "conditions" FROM "%s" WHERE "name" = $1 LIMIT 1`, sp.tableName), name).
+		Scan(&sp.ID, &sp.Name, &sp.Value, &sp.Conditions))
+}
+
+// placeholders returns the list of numbered placeholders for count rows of size values
+func placeholders(count, size int) string {
+	rows := make([]string, count)
+	row := make([]string, size)
+	for i := range rows {
+		for j := range row {
+			row[j] = fmt.Sprintf("$%d", i*size+j+1)
+		}
+		rows[i] = `(` + strings.Join(row, `,`) + `)`
+	}
+	return strings.Join(rows, `,`)
+}
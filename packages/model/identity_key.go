@@ -0,0 +1,67 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package model
+
+// IdentityKey binds the identity of the external provider to the key of the ecosystem
+type IdentityKey struct {
+	ID        int64  `gorm:"primary_key;not null"`
+	Provider  string `gorm:"not null;size:32"`
+	Identity  string `gorm:"not null;size:255"`
+	Ecosystem int64  `gorm:"not null"`
+	KeyID     int64  `gorm:"not null"`
+}
+
+// TableName returns name of table
+func (ik *IdentityKey) TableName() string {
+	return "identity_keys"
+}
+
+// Get is retrieving model from database
+func (ik *IdentityKey) Get(provider, identity string, ecosystem int64) (bool, error) {
+	return isFound(DBConn.Where("provider = ? and identity = ? and ecosystem = ?",
+		provider, identity, ecosystem).First(ik))
+}
+
+// Save binds the identity to the key replacing the previous binding
+func (ik *IdentityKey) Save() error {
+	keyID := ik.KeyID
+	return DBConn.Where(IdentityKey{Provider: ik.Provider, Identity: ik.Identity, Ecosystem: ik.Ecosystem}).
+		Assign(IdentityKey{KeyID: keyID}).FirstOrCreate(ik).Error
+}
+
+// DeleteIdentityKey removes the binding of the identity
+func DeleteIdentityKey(provider, identity string, ecosystem int64) error {
+	return DBConn.Where("provider = ? and identity = ? and ecosystem = ?",
+		provider, identity, ecosystem).Delete(&IdentityKey{}).Error
+}
+
+// GetIdentityKeys returns the bindings of the ecosystem, empty provider is ignored
+func GetIdentityKeys(provider string, ecosystem, offset, limit int64) ([]IdentityKey, error) {
+	var list []IdentityKey
+	query := DBConn.Where("ecosystem = ?", ecosystem).Order("id").Offset(offset).Limit(limit)
+	if len(provider) > 0 {
+		query = query.Where("provider = ?", provider)
+	}
+	err := query.Find(&list).Error
+	return list, err
+}
@@ -0,0 +1,59 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package model
+
+// InviteCode is the single-use code which registers the new member of the ecosystem
+type InviteCode struct {
+	ID         int64  `gorm:"primary_key;not null" json:"id"`
+	Ecosystem  int64  `gorm:"not null" json:"ecosystem"`
+	Hash       string `gorm:"not null;size:64" json:"hash"`
+	Roles      string `gorm:"not null;size:255" json:"roles"`
+	Amount     string `gorm:"not null" json:"amount"`
+	Expire     int64  `gorm:"not null" json:"expire"`
+	CreatedBy  int64  `gorm:"not null" json:"created_by"`
+	RedeemedBy int64  `gorm:"not null" json:"redeemed_by"`
+	RedeemedAt int64  `gorm:"not null" json:"redeemed_at"`
+	Revoked    int64  `gorm:"not null" json:"revoked"`
+}
+
+// TableName returns name of table
+func (InviteCode) TableName() string {
+	return "invite_codes"
+}
+
+// Get is retrieving the invite code by its identifier
+func (c *InviteCode) Get(transaction *DbTransaction, id int64) (bool, error) {
+	return isFound(GetDB(transaction).Where("id = ?", id).First(c))
+}
+
+// GetByHash is retrieving the invite code by the hash of the code
+func (c *InviteCode) GetByHash(transaction *DbTransaction, hash string) (bool, error) {
+	return isFound(GetDB(transaction).Where("hash = ?", hash).First(c))
+}
+
+// GetInviteCodes returns the invite codes of the ecosystem in order of decreasing identifier
+func GetInviteCodes(ecosystem, offset, limit int64) ([]InviteCode, error) {
+	var list []InviteCode
+	err := DBConn.Where("ecosystem = ?", ecosystem).Order("id desc").Offset(offset).Limit(limit).Find(&list).Error
+	return list, err
+}
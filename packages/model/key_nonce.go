@@ -0,0 +1,60 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package model
+
+// KeyNonce is the nonce used by the key in the contract transaction
+type KeyNonce struct {
+	KeyID   int64  `gorm:"primary_key;not null"`
+	Nonce   int64  `gorm:"primary_key;not null"`
+	Hash    []byte `gorm:"not null"`
+	BlockID int64  `gorm:"not null"`
+}
+
+// TableName returns name of table
+func (kn *KeyNonce) TableName() string {
+	return "key_nonces"
+}
+
+// Create is creating record of model
+func (kn *KeyNonce) Create(transaction *DbTransaction) error {
+	return GetDB(transaction).Create(kn).Error
+}
+
+// GetMaxNonce returns the greatest nonce used by the key, 0 if there are no nonces
+func GetMaxNonce(transaction *DbTransaction, keyID int64) (int64, error) {
+	var max int64
+	err := GetDB(transaction).Raw(`SELECT COALESCE(max(nonce), 0) FROM key_nonces WHERE key_id = ?`, keyID).Row().Scan(&max)
+	return max, err
+}
+
+// IsNonceUsed returns true if the key has used the nonce
+func IsNonceUsed(transaction *DbTransaction, keyID, nonce int64) (bool, error) {
+	var count int64
+	err := GetDB(transaction).Model(&KeyNonce{}).Where("key_id = ? and nonce = ?", keyID, nonce).Count(&count).Error
+	return count > 0, err
+}
+
+// DeleteKeyNoncesByHash deletes the nonce of the transaction
+func DeleteKeyNoncesByHash(transaction *DbTransaction, hash []byte) error {
+	return GetDB(transaction).Where("hash = ?", hash).Delete(&KeyNonce{}).Error
+}
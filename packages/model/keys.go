@@ -50,5 +50,16 @@ func (m Key) TableName() string {
 
 // Get is retrieving model from database
 func (m *Key) Get(wallet int64) (bool, error) {
+	if fastQueries() {
+		return m.getFast(nil, wallet)
+	}
 	return isFound(DBConn.Where("id = ?", wallet).First(m))
 }
+
+// GetTx is retrieving model from database in the transaction
+func (m *Key) GetTx(transaction *DbTransaction, wallet int64) (bool, error) {
+	if fastQueries() {
+		return m.getFast(transaction, wallet)
+	}
+	return isFound(GetDB(transaction).Where("id = ?", wallet).First(m))
+}
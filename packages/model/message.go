@@ -0,0 +1,78 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package model
+
+// Delivery statuses of messages
+const (
+	MessagePending = 0
+	MessageSent    = 1
+	MessageFailed  = 2
+)
+
+// Message is the email or SMS message of VDE which is delivered by the node
+type Message struct {
+	tableName   string
+	ID          int64  `gorm:"primary_key;not null"`
+	Channel     string `gorm:"not null"`
+	Recipient   string `gorm:"not null"`
+	Subject     string `gorm:"not null"`
+	Body        string `gorm:"not null"`
+	Params      string `gorm:"not null"`
+	Status      int64  `gorm:"not null"`
+	Attempts    int64  `gorm:"not null"`
+	NextAttempt int64  `gorm:"not null"`
+	Error       string `gorm:"not null"`
+	SentAt      int64  `gorm:"not null"`
+}
+
+// SetTablePrefix is setting table prefix
+func (m *Message) SetTablePrefix(prefix string) {
+	m.tableName = prefix + "_messages"
+}
+
+// TableName returns name of table
+func (m *Message) TableName() string {
+	return m.tableName
+}
+
+// GetPending returns the pending messages which should be delivered at the time
+func (m *Message) GetPending(now int64, limit int) ([]*Message, error) {
+	var list []*Message
+	err := DBConn.Table(m.TableName()).Where("status = ? and next_attempt <= ?", MessagePending, now).
+		Order("id").Limit(limit).Find(&list).Error
+	for _, item := range list {
+		item.tableName = m.tableName
+	}
+	return list, err
+}
+
+// SetStatus writes the result of the delivery attempt
+func (m *Message) SetStatus() error {
+	return DBConn.Table(m.TableName()).Where("id = ?", m.ID).Updates(map[string]interface{}{
+		"status":       m.Status,
+		"attempts":     m.Attempts,
+		"next_attempt": m.NextAttempt,
+		"error":        m.Error,
+		"sent_at":      m.SentAt,
+	}).Error
+}
@@ -0,0 +1,123 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package model
+
+import "fmt"
+
+// NFTCollection is the collection of non-fungible assets of the ecosystem
+type NFTCollection struct {
+	tableName          string
+	ID                 int64  `gorm:"primary_key;not null" json:"id"`
+	Name               string `gorm:"not null;size:100" json:"name"`
+	Supply             int64  `gorm:"not null" json:"supply"`
+	MaxSupply          int64  `gorm:"not null" json:"max_supply"`
+	Owner              int64  `gorm:"not null" json:"owner"`
+	MintConditions     string `gorm:"not null" json:"mint_conditions"`
+	TransferConditions string `gorm:"not null" json:"transfer_conditions"`
+	BlockID            int64  `gorm:"not null" json:"block_id"`
+}
+
+// SetTablePrefix is setting table prefix
+func (c *NFTCollection) SetTablePrefix(prefix int64) {
+	c.tableName = fmt.Sprintf("%d_nft_collections", prefix)
+}
+
+// TableName returns name of table
+func (c NFTCollection) TableName() string {
+	return c.tableName
+}
+
+// GetByName is retrieving the collection by its name
+func (c *NFTCollection) GetByName(transaction *DbTransaction, name string) (bool, error) {
+	return isFound(GetDB(transaction).Where("name = ?", name).First(c))
+}
+
+// Get is retrieving the collection by its identifier
+func (c *NFTCollection) Get(transaction *DbTransaction, id int64) (bool, error) {
+	return isFound(GetDB(transaction).Where("id = ?", id).First(c))
+}
+
+// GetNFTCollections returns the collections of the ecosystem
+func GetNFTCollections(prefix int64, limit, offset int) ([]NFTCollection, error) {
+	var collections []NFTCollection
+	err := DBConn.Table(fmt.Sprintf("%d_nft_collections", prefix)).Order("id").Limit(limit).Offset(offset).Find(&collections).Error
+	return collections, err
+}
+
+// NFT is the unique asset of the collection
+type NFT struct {
+	tableName    string
+	ID           int64  `gorm:"primary_key;not null" json:"id"`
+	CollectionID int64  `gorm:"not null" json:"collection_id"`
+	Owner        int64  `gorm:"not null" json:"owner"`
+	Approved     int64  `gorm:"not null" json:"approved"`
+	URI          string `gorm:"column:uri;not null" json:"uri"`
+	Hash         string `gorm:"not null" json:"hash"`
+	BlockID      int64  `gorm:"not null" json:"block_id"`
+}
+
+// SetTablePrefix is setting table prefix
+func (n *NFT) SetTablePrefix(prefix int64) {
+	n.tableName = fmt.Sprintf("%d_nfts", prefix)
+}
+
+// TableName returns name of table
+func (n NFT) TableName() string {
+	return n.tableName
+}
+
+// Get is retrieving the asset by its identifier
+func (n *NFT) Get(transaction *DbTransaction, id int64) (bool, error) {
+	return isFound(GetDB(transaction).Where("id = ?", id).First(n))
+}
+
+// GetNFTs returns the assets of the ecosystem. The assets are filtered by the collection and the owner if they aren't zero
+func GetNFTs(prefix, collectionID, owner int64, limit, offset int) ([]NFT, error) {
+	var nfts []NFT
+	query := DBConn.Table(fmt.Sprintf("%d_nfts", prefix))
+	if collectionID != 0 {
+		query = query.Where("collection_id = ?", collectionID)
+	}
+	if owner != 0 {
+		query = query.Where("owner = ?", owner)
+	}
+	err := query.Order("id").Limit(limit).Offset(offset).Find(&nfts).Error
+	return nfts, err
+}
+
+// NFTHistory is the record of the mint or transfer of the asset. Sender is zero for minted assets
+type NFTHistory struct {
+	ID        int64 `gorm:"primary_key;not null" json:"id"`
+	NFTID     int64 `gorm:"column:nft_id;not null" json:"nft_id"`
+	Sender    int64 `gorm:"not null" json:"sender"`
+	Recipient int64 `gorm:"not null" json:"recipient"`
+	BlockID   int64 `gorm:"not null" json:"block_id"`
+}
+
+// GetNFTHistory returns the history of the asset
+func GetNFTHistory(prefix, id int64, limit, offset int) ([]NFTHistory, error) {
+	var history []NFTHistory
+	err := DBConn.Table(fmt.Sprintf("%d_nft_history", prefix)).Where("nft_id = ?", id).
+		Order("id desc").Limit(limit).Offset(offset).Find(&history).Error
+	return history, err
+}
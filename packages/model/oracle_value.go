@@ -0,0 +1,62 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package model
+
+// OracleValue is the latest value of the oracle data feed
+type OracleValue struct {
+	ID        int64  `gorm:"primary_key;not null" json:"id"`
+	Feed      string `gorm:"not null;size:100" json:"feed"`
+	Value     string `gorm:"not null" json:"value"`
+	DataTime  int64  `gorm:"not null" json:"data_time"`
+	KeyID     int64  `gorm:"not null" json:"key_id"`
+	BlockID   int64  `gorm:"not null" json:"block_id"`
+	SourceTx  string `gorm:"not null" json:"source_tx"`
+	Signature string `gorm:"not null" json:"signature"`
+}
+
+// TableName returns name of table
+func (OracleValue) TableName() string {
+	return "oracle_values"
+}
+
+// GetByFeed is retrieving the latest value of the feed
+func (ov *OracleValue) GetByFeed(transaction *DbTransaction, feed string) (bool, error) {
+	return isFound(GetDB(transaction).Where("feed = ?", feed).First(ov))
+}
+
+// Create is creating record of model
+func (ov *OracleValue) Create(transaction *DbTransaction) error {
+	return GetDB(transaction).Create(ov).Error
+}
+
+// Update is updating record of model
+func (ov *OracleValue) Update(transaction *DbTransaction) error {
+	return GetDB(transaction).Save(ov).Error
+}
+
+// GetNextOracleValueID returns the identifier for the new feed
+func GetNextOracleValueID(transaction *DbTransaction) (int64, error) {
+	var id int64
+	err := GetDB(transaction).Raw(`SELECT COALESCE(max(id), 0) + 1 FROM "oracle_values"`).Row().Scan(&id)
+	return id, err
+}
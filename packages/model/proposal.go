@@ -0,0 +1,98 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package model
+
+import "fmt"
+
+const (
+	// ProposalOpen is the status of the proposal which is being voted
+	ProposalOpen = iota
+	// ProposalApproved is the status of the approved proposal which change is waiting for the apply block
+	ProposalApproved
+	// ProposalRejected is the status of the proposal which has not reached the quorum or the threshold
+	ProposalRejected
+	// ProposalApplied is the status of the approved proposal which change has been applied
+	ProposalApplied
+)
+
+// Proposal is the governance proposal of the ecosystem
+type Proposal struct {
+	tableName    string
+	ID           int64  `gorm:"primary_key;not null" json:"id"`
+	Creator      int64  `gorm:"not null" json:"creator"`
+	Title        string `gorm:"not null" json:"title"`
+	Description  string `gorm:"not null" json:"description"`
+	Strategy     string `gorm:"not null" json:"strategy"`
+	RoleID       int64  `gorm:"not null" json:"role_id"`
+	Quorum       string `gorm:"not null" json:"quorum"`
+	Threshold    int64  `gorm:"not null" json:"threshold"`
+	EndBlock     int64  `gorm:"not null" json:"end_block"`
+	ParamName    string `gorm:"not null" json:"param_name"`
+	ParamValue   string `gorm:"not null" json:"param_value"`
+	ApplyBlock   int64  `gorm:"not null" json:"apply_block"`
+	Status       int64  `gorm:"not null" json:"status"`
+	VotesFor     string `gorm:"not null" json:"votes_for"`
+	VotesAgainst string `gorm:"not null" json:"votes_against"`
+	BlockID      int64  `gorm:"not null" json:"block_id"`
+}
+
+// SetTablePrefix is setting table prefix
+func (p *Proposal) SetTablePrefix(prefix int64) {
+	p.tableName = fmt.Sprintf("%d_proposals", prefix)
+}
+
+// TableName returns name of table
+func (p Proposal) TableName() string {
+	return p.tableName
+}
+
+// Get is retrieving the proposal by its identifier
+func (p *Proposal) Get(transaction *DbTransaction, id int64) (bool, error) {
+	return isFound(GetDB(transaction).Where("id = ?", id).First(p))
+}
+
+// ProposalVote is the vote of the wallet for the proposal
+type ProposalVote struct {
+	tableName  string
+	ID         int64  `gorm:"primary_key;not null" json:"id"`
+	ProposalID int64  `gorm:"not null" json:"proposal_id"`
+	Voter      int64  `gorm:"not null" json:"voter"`
+	Weight     string `gorm:"not null" json:"weight"`
+	Approve    int64  `gorm:"not null" json:"approve"`
+	BlockID    int64  `gorm:"not null" json:"block_id"`
+}
+
+// SetTablePrefix is setting table prefix
+func (v *ProposalVote) SetTablePrefix(prefix int64) {
+	v.tableName = fmt.Sprintf("%d_proposal_votes", prefix)
+}
+
+// TableName returns name of table
+func (v ProposalVote) TableName() string {
+	return v.tableName
+}
+
+// Get is retrieving the vote of the wallet for the proposal
+func (v *ProposalVote) Get(transaction *DbTransaction, proposalID, voter int64) (bool, error) {
+	return isFound(GetDB(transaction).Where("proposal_id = ? and voter = ?", proposalID, voter).First(v))
+}
@@ -27,6 +27,8 @@ type QueueTx struct {
 	Hash     []byte `gorm:"primary_key;not null"`
 	Data     []byte `gorm:"not null"`
 	FromGate int    `gorm:"not null"`
+	// RequestID is X-Request-ID of the API call which has sent the transaction
+	RequestID string `gorm:"not null"`
 }
 
 // TableName returns name of table
@@ -67,16 +69,25 @@ func GetQueuedTransactionsCount(hash []byte) (int64, error) {
 	return rowsCount, err
 }
 
+// GetQueueTxCount counts the transactions which haven't been verified yet
+func GetQueueTxCount() (int64, error) {
+	var rowsCount int64
+	err := DBConn.Table("queue_tx").Count(&rowsCount).Error
+	return rowsCount, err
+}
+
 // GetAllUnverifiedAndUnusedTransactions is returns all unverified and unused transaction
 func GetAllUnverifiedAndUnusedTransactions() ([]*QueueTx, error) {
 	query := `SELECT *
 		  FROM (
 	              SELECT data,
-	                     hash
+	                     hash,
+	                     request_id
 	              FROM queue_tx
 		      UNION
 		      SELECT data,
-			     hash
+			     hash,
+			     '' AS request_id
 		      FROM transactions
 		      WHERE verified = 0 AND used = 0
 			)  AS x`
@@ -85,13 +96,16 @@ func GetAllUnverifiedAndUnusedTransactions() ([]*QueueTx, error) {
 		return nil, err
 	}
 	defer rows.Close()
-	var data, hash []byte
+	var (
+		data, hash []byte
+		requestID  string
+	)
 	result := []*QueueTx{}
 	for rows.Next() {
-		if err := rows.Scan(&data, &hash); err != nil {
+		if err := rows.Scan(&data, &hash, &requestID); err != nil {
 			return nil, err
 		}
-		result = append(result, &QueueTx{Data: data, Hash: hash})
+		result = append(result, &QueueTx{Data: data, Hash: hash, RequestID: requestID})
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
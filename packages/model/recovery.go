@@ -0,0 +1,82 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package model
+
+const (
+	// RecoveryPending is the request which collects the approvals of guardians or waits for the challenge period
+	RecoveryPending = 0
+	// RecoveryDone is the request which has rotated the public key of the account
+	RecoveryDone = 1
+	// RecoveryCancelled is the request which has been cancelled by the owner of the account
+	RecoveryCancelled = 2
+)
+
+// RecoveryGuardians are the keys which can jointly rotate the public key of the account
+type RecoveryGuardians struct {
+	ID        int64  `gorm:"primary_key;not null" json:"id"`
+	Ecosystem int64  `gorm:"not null" json:"ecosystem"`
+	KeyID     int64  `gorm:"not null" json:"key_id"`
+	Guardians string `gorm:"not null" json:"guardians"`
+	Threshold int64  `gorm:"not null" json:"threshold"`
+	Delay     int64  `gorm:"not null" json:"delay"`
+}
+
+// TableName returns name of table
+func (RecoveryGuardians) TableName() string {
+	return "recovery_guardians"
+}
+
+// Get is retrieving the guardians of the account of the ecosystem
+func (g *RecoveryGuardians) Get(transaction *DbTransaction, ecosystem, keyID int64) (bool, error) {
+	return isFound(GetDB(transaction).Where("ecosystem = ? AND key_id = ?", ecosystem, keyID).First(g))
+}
+
+// RecoveryRequest is the request of the guardians to rotate the public key of the account
+type RecoveryRequest struct {
+	ID          int64  `gorm:"primary_key;not null" json:"id"`
+	Ecosystem   int64  `gorm:"not null" json:"ecosystem"`
+	KeyID       int64  `gorm:"not null" json:"key_id"`
+	PublicKey   string `gorm:"not null;size:128" json:"public_key"`
+	Approvals   string `gorm:"not null" json:"approvals"`
+	StartedAt   int64  `gorm:"not null" json:"started_at"`
+	ReleaseTime int64  `gorm:"not null" json:"release_time"`
+	Status      int64  `gorm:"not null" json:"status"`
+}
+
+// TableName returns name of table
+func (RecoveryRequest) TableName() string {
+	return "recovery_requests"
+}
+
+// GetPending is retrieving the pending request of the recovery of the account
+func (r *RecoveryRequest) GetPending(transaction *DbTransaction, ecosystem, keyID int64) (bool, error) {
+	return isFound(GetDB(transaction).Where("ecosystem = ? AND key_id = ? AND status = ?",
+		ecosystem, keyID, RecoveryPending).First(r))
+}
+
+// GetRecoveryRequests returns the recovery requests of the account in order of decreasing identifier
+func GetRecoveryRequests(ecosystem, keyID, limit int64) ([]RecoveryRequest, error) {
+	var list []RecoveryRequest
+	err := DBConn.Where("ecosystem = ? AND key_id = ?", ecosystem, keyID).Order("id desc").Limit(limit).Find(&list).Error
+	return list, err
+}
@@ -0,0 +1,84 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package model
+
+import "fmt"
+
+// Role is the role of the ecosystem. The members of the parent role have the rights of the role
+type Role struct {
+	tableName string
+	ID        int64  `gorm:"primary_key;not null" json:"id"`
+	ParentID  int64  `gorm:"not null" json:"parent_id"`
+	RoleName  string `gorm:"not null" json:"role_name"`
+	RoleType  int64  `gorm:"not null" json:"role_type"`
+	Delete    int64  `gorm:"not null" json:"delete"`
+}
+
+// SetTablePrefix is setting table prefix
+func (r *Role) SetTablePrefix(prefix int64) {
+	r.tableName = fmt.Sprintf("%d_roles_list", prefix)
+}
+
+// TableName returns name of table
+func (r Role) TableName() string {
+	return r.tableName
+}
+
+// Get is retrieving the role by its identifier
+func (r *Role) Get(transaction *DbTransaction, id int64) (bool, error) {
+	return isFound(GetDB(transaction).Where("id = ?", id).First(r))
+}
+
+// RoleAssign is the membership of the wallet in the role
+type RoleAssign struct {
+	tableName string
+	ID        int64 `gorm:"primary_key;not null" json:"id"`
+	RoleID    int64 `gorm:"not null" json:"role_id"`
+	MemberID  int64 `gorm:"not null" json:"member_id"`
+	Delete    int64 `gorm:"not null" json:"delete"`
+}
+
+// SetTablePrefix is setting table prefix
+func (r *RoleAssign) SetTablePrefix(prefix int64) {
+	r.tableName = fmt.Sprintf("%d_roles_assign", prefix)
+}
+
+// TableName returns name of table
+func (r RoleAssign) TableName() string {
+	return r.tableName
+}
+
+// GetActive is retrieving the membership which is not deleted and has not expired at the time
+func (r *RoleAssign) GetActive(transaction *DbTransaction, roleID, member, time int64) (bool, error) {
+	return isFound(GetDB(transaction).Where("role_id = ? and member_id = ? and delete = 0 and (date_end is null or date_end > to_timestamp(?))",
+		roleID, member, time).First(r))
+}
+
+// IsRoleMember returns true if the member has any of the roles in the ecosystem at the time
+func IsRoleMember(transaction *DbTransaction, ecosystem int64, roleIDs []int64, member, time int64) (bool, error) {
+	var count int64
+	err := GetDB(transaction).Table(fmt.Sprintf("%d_roles_assign", ecosystem)).
+		Where("role_id in (?) and member_id = ? and delete = 0 and (date_end is null or date_end > to_timestamp(?))",
+			roleIDs, member, time).Count(&count).Error
+	return count > 0, err
+}
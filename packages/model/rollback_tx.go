@@ -22,6 +22,33 @@
 
 package model
 
+import (
+	"encoding/json"
+	"strings"
+	"sync/atomic"
+)
+
+// rollbackBatchSize is the maximum count of the rollback records which are inserted by one query
+const rollbackBatchSize = 100
+
+// RollbackStats contains the counters of the written rollback records
+type RollbackStats struct {
+	Records int64 `json:"records"`
+	Queries int64 `json:"queries"`
+}
+
+var rollbackRecords, rollbackQueries int64
+
+// BatchTableID is the table_id of the rollback record which restores several rows of the table
+const BatchTableID = `*`
+
+// RollbackBatchItem is the previous data of the row in the batch rollback record,
+// empty Data means that the row has been inserted
+type RollbackBatchItem struct {
+	ID   string            `json:"id"`
+	Data map[string]string `json:"data,omitempty"`
+}
+
 // RollbackTx is model
 type RollbackTx struct {
 	ID        int64  `gorm:"primary_key;not null" json:"-"`
@@ -30,6 +57,7 @@ type RollbackTx struct {
 	NameTable string `gorm:"not null;size:255;column:table_name" json:"table_name"`
 	TableID   string `gorm:"not null;size:255" json:"table_id"`
 	Data      string `gorm:"not null;type:jsonb(PostgreSQL)" json:"data"`
+	Redacted  int64  `gorm:"not null" json:"redacted"`
 }
 
 // TableName returns name of table
@@ -39,10 +67,16 @@ func (RollbackTx) TableName() string {
 
 // GetRollbackTransactions is returns rollback transactions
 func (rt *RollbackTx) GetRollbackTransactions(dbTransaction *DbTransaction, transactionHash []byte) ([]map[string]string, error) {
+	if err := dbTransaction.FlushRollbacks(); err != nil {
+		return nil, err
+	}
 	return GetAllTx(dbTransaction, "SELECT * from rollback_tx WHERE tx_hash = ? ORDER BY ID DESC", -1, transactionHash)
 }
 
 func (rt *RollbackTx) GetBlockRollbackTransactions(dbTransaction *DbTransaction, blockID int64) ([]RollbackTx, error) {
+	if err := dbTransaction.FlushRollbacks(); err != nil {
+		return nil, err
+	}
 	var rollbackTransactions []RollbackTx
 	err := GetDB(dbTransaction).Where("block_id = ?", blockID).Order("tx_hash asc").Find(&rollbackTransactions).Error
 	return rollbackTransactions, err
@@ -56,22 +90,153 @@ func (rt *RollbackTx) GetRollbackTxsByTableIDAndTableName(tableID, tableName str
 	return rollbackTx, nil
 }
 
+// GetRowHistory returns the last rollback records of the row including the batch records which contain it,
+// the records are in chronological order
+func GetRowHistory(tableName, tableID string, limit int) ([]RollbackTx, error) {
+	item, err := json.Marshal([]RollbackBatchItem{{ID: tableID}})
+	if err != nil {
+		return nil, err
+	}
+	var list []RollbackTx
+	err = DBConn.Where("table_name = ? AND (table_id = ? OR (table_id = ? AND data @> ?))",
+		tableName, tableID, BatchTableID, string(item)).Order("id desc").Limit(limit).Find(&list).Error
+	for i, j := 0, len(list)-1; i < j; i, j = i+1, j-1 {
+		list[i], list[j] = list[j], list[i]
+	}
+	return list, err
+}
+
 // DeleteByHash is deleting rollbackTx by hash
 func (rt *RollbackTx) DeleteByHash(dbTransaction *DbTransaction) error {
+	if err := dbTransaction.FlushRollbacks(); err != nil {
+		return err
+	}
 	return GetDB(dbTransaction).Exec("DELETE FROM rollback_tx WHERE tx_hash = ?", rt.TxHash).Error
 }
 
 // DeleteByHashAndTableName is deleting tx by hash and table name
 func (rt *RollbackTx) DeleteByHashAndTableName(transaction *DbTransaction) error {
+	if err := transaction.FlushRollbacks(); err != nil {
+		return err
+	}
 	return GetDB(transaction).Where("tx_hash = ? and table_name = ?", rt.TxHash, rt.NameTable).Delete(rt).Error
 }
 
-// Create is creating record of model
+// Create is creating record of model. The records of the transaction are buffered and written
+// by multi-row inserts before the commit or before the reading of rollback records
 func (rt *RollbackTx) Create(transaction *DbTransaction) error {
-	return GetDB(transaction).Create(rt).Error
+	if transaction == nil || transaction.conn == nil {
+		atomic.AddInt64(&rollbackRecords, 1)
+		atomic.AddInt64(&rollbackQueries, 1)
+		return DBConn.Create(rt).Error
+	}
+	transaction.rollbacks = append(transaction.rollbacks, rt)
+	if len(transaction.rollbacks) >= rollbackBatchSize {
+		return transaction.FlushRollbacks()
+	}
+	return nil
+}
+
+// FlushRollbacks writes the buffered rollback records of the transaction
+func (tr *DbTransaction) FlushRollbacks() error {
+	if tr == nil || len(tr.rollbacks) == 0 {
+		return nil
+	}
+	list := tr.rollbacks
+	tr.rollbacks = nil
+	for len(list) > 0 {
+		count := len(list)
+		if count > rollbackBatchSize {
+			count = rollbackBatchSize
+		}
+		values := make([]string, count)
+		args := make([]interface{}, 0, count*6)
+		for i, rt := range list[:count] {
+			txHash := rt.TxHash
+			if txHash == nil {
+				txHash = []byte{}
+			}
+			values[i] = `(?,?,?,?,?,?)`
+			args = append(args, rt.BlockID, txHash, rt.NameTable, rt.TableID, rt.Data, rt.Redacted)
+		}
+		query := `INSERT INTO "rollback_tx" ("block_id", "tx_hash", "table_name", "table_id", "data", "redacted") VALUES `
+		var err error
+		if fastQueries() {
+			_, err = tr.conn.CommonDB().Exec(query+placeholders(count, 6), args...)
+		} else {
+			err = tr.conn.Exec(query+strings.Join(values, `,`), args...).Error
+		}
+		if err != nil {
+			return err
+		}
+		atomic.AddInt64(&rollbackRecords, int64(count))
+		atomic.AddInt64(&rollbackQueries, 1)
+		list = list[count:]
+	}
+	return nil
+}
+
+// DiscardRollbacks drops the buffered rollback records when the changes of the transaction are rolled back
+func (tr *DbTransaction) DiscardRollbacks() {
+	if tr != nil {
+		tr.rollbacks = nil
+	}
+}
+
+// GetRollbackStats returns the counters of the written rollback records
+func GetRollbackStats() RollbackStats {
+	return RollbackStats{
+		Records: atomic.LoadInt64(&rollbackRecords),
+		Queries: atomic.LoadInt64(&rollbackQueries),
+	}
 }
 
 // Get is retrieving model from database
 func (rt *RollbackTx) Get(dbTransaction *DbTransaction, transactionHash []byte, tableName string) (bool, error) {
+	if err := dbTransaction.FlushRollbacks(); err != nil {
+		return false, err
+	}
 	return isFound(GetDB(dbTransaction).Where("tx_hash = ? AND table_name = ?", transactionHash, tableName).First(rt))
 }
+
+// GetByTableRow returns the rollback records of the row of the table
+func (rt *RollbackTx) GetByTableRow(dbTransaction *DbTransaction, tableName, tableID string) ([]RollbackTx, error) {
+	if err := dbTransaction.FlushRollbacks(); err != nil {
+		return nil, err
+	}
+	var list []RollbackTx
+	err := GetDB(dbTransaction).Where("table_name = ? AND table_id = ?", tableName, tableID).Find(&list).Error
+	return list, err
+}
+
+// Redact replaces the data of the rollback record and marks it as redacted
+func (rt *RollbackTx) Redact(dbTransaction *DbTransaction, data string) error {
+	return GetDB(dbTransaction).Model(rt).Updates(map[string]interface{}{"data": data, "redacted": 1}).Error
+}
+
+// GetLastTableChanges returns the last rollback record of each row of the table
+func GetLastTableChanges(tableName string) ([]RollbackTx, error) {
+	var list []RollbackTx
+	err := DBConn.Raw(`SELECT DISTINCT ON (table_id) id, block_id, tx_hash, table_name, table_id
+		FROM rollback_tx WHERE table_name = ? AND table_id <> ? ORDER BY table_id, id DESC`,
+		tableName, BatchTableID).Scan(&list).Error
+	return list, err
+}
+
+// incoherentRollbackTx is the condition of the rollback records which don't belong to the applied transactions
+// of the chain: they follow the last block or their transaction isn't logged
+const incoherentRollbackTx = `block_id >= ? AND (block_id > ? OR (tx_hash <> '' AND
+	NOT EXISTS (SELECT 1 FROM log_transactions WHERE log_transactions.hash = rollback_tx.tx_hash)))`
+
+// CountIncoherentRollbackTx counts the incoherent rollback records starting from fromBlockID
+func CountIncoherentRollbackTx(fromBlockID, lastBlockID int64) (int64, error) {
+	var count int64
+	err := DBConn.Table("rollback_tx").Where(incoherentRollbackTx, fromBlockID, lastBlockID).Count(&count).Error
+	return count, err
+}
+
+// DeleteIncoherentRollbackTx deletes the incoherent rollback records starting from fromBlockID
+func DeleteIncoherentRollbackTx(transaction *DbTransaction, fromBlockID, lastBlockID int64) (int64, error) {
+	query := GetDB(transaction).Exec(`DELETE FROM rollback_tx WHERE `+incoherentRollbackTx, fromBlockID, lastBlockID)
+	return query.RowsAffected, query.Error
+}
@@ -0,0 +1,150 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package model
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// SchemaRollback is the statement which restores the schema of table when the transaction is rolled back.
+// Data is passed as the parameter of the statement if it isn't empty
+type SchemaRollback struct {
+	ID      int64  `gorm:"primary_key;not null"`
+	BlockID int64  `gorm:"not null"`
+	TxHash  []byte `gorm:"not null"`
+	Query   string `gorm:"not null"`
+	Data    string `gorm:"not null"`
+}
+
+// TableName returns name of table
+func (SchemaRollback) TableName() string {
+	return `rollback_schema`
+}
+
+// Create is creating record of model
+func (s *SchemaRollback) Create(transaction *DbTransaction) error {
+	return GetDB(transaction).Create(s).Error
+}
+
+// RollbackSchema executes the restoring statements of the transaction in reverse order and deletes them
+func RollbackSchema(transaction *DbTransaction, txHash []byte) error {
+	var list []SchemaRollback
+	if err := GetDB(transaction).Where("tx_hash = ?", txHash).Order("id desc").Find(&list).Error; err != nil {
+		return err
+	}
+	for _, item := range list {
+		var err error
+		if len(item.Data) > 0 {
+			err = GetDB(transaction).Exec(item.Query, item.Data).Error
+		} else {
+			err = GetDB(transaction).Exec(item.Query).Error
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return GetDB(transaction).Where("tx_hash = ?", txHash).Delete(SchemaRollback{}).Error
+}
+
+// ColumnSchema is the definition of the column of table
+type ColumnSchema struct {
+	Type    string
+	Default string
+	NotNull bool
+}
+
+// GetColumnSchema returns SQL type, default value and nullability of the column
+func GetColumnSchema(transaction *DbTransaction, tableName, column string) (*ColumnSchema, error) {
+	var (
+		col        ColumnSchema
+		colDefault sql.NullString
+	)
+	err := GetDB(transaction).Raw(`SELECT format_type(a.atttypid, a.atttypmod), pg_get_expr(d.adbin, d.adrelid), a.attnotnull
+		FROM pg_attribute a LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+		WHERE a.attrelid = ?::regclass AND a.attname = ? AND a.attnum > 0 AND NOT a.attisdropped`,
+		fmt.Sprintf(`"%s"`, tableName), column).Row().Scan(&col.Type, &colDefault, &col.NotNull)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	col.Default = colDefault.String
+	return &col, nil
+}
+
+// GetColumnValues returns JSON object with the values of the column by the identifiers of rows
+func GetColumnValues(transaction *DbTransaction, tableName, column string) (string, error) {
+	var values string
+	err := GetDB(transaction).Raw(fmt.Sprintf(`SELECT COALESCE(jsonb_object_agg(id, "%s"), '{}')::text FROM "%s"`,
+		column, tableName)).Row().Scan(&values)
+	return values, err
+}
+
+// GetIndexDef returns the statement which creates the index, it is empty if the index doesn't exist
+func GetIndexDef(transaction *DbTransaction, tableName, indexName string) (string, error) {
+	var def string
+	err := GetDB(transaction).Raw(`SELECT indexdef FROM pg_indexes WHERE schemaname = current_schema()
+		AND tablename = ? AND indexname = ?`, tableName, indexName).Row().Scan(&def)
+	if err == sql.ErrNoRows {
+		return ``, nil
+	}
+	return def, err
+}
+
+// GetIndexCount returns the count of indexes of the table
+func GetIndexCount(transaction *DbTransaction, tableName string) (int64, error) {
+	var count int64
+	err := GetDB(transaction).Raw(`SELECT count(*) FROM pg_indexes WHERE schemaname = current_schema()
+		AND tablename = ?`, tableName).Row().Scan(&count)
+	return count, err
+}
+
+// ExecSchemaChange executes the statement which changes the schema of table
+func ExecSchemaChange(transaction *DbTransaction, query string) error {
+	return GetDB(transaction).Exec(query).Error
+}
+
+// GetColumnIndexes returns the statements which create the indexes containing the column
+func GetColumnIndexes(transaction *DbTransaction, tableName, column string) ([]string, error) {
+	// pg_depend contains the columns of both simple and expression indexes
+	rows, err := GetDB(transaction).Raw(`SELECT pg_get_indexdef(i.indexrelid) FROM pg_index i
+		WHERE i.indexrelid IN (SELECT d.objid FROM pg_depend d
+		JOIN pg_attribute a ON a.attrelid = d.refobjid AND a.attnum = d.refobjsubid
+		WHERE d.classid = 'pg_class'::regclass AND d.refobjid = ?::regclass AND a.attname = ?)
+		ORDER BY i.indexrelid`, fmt.Sprintf(`"%s"`, tableName), column).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var list []string
+	for rows.Next() {
+		var def string
+		if err = rows.Scan(&def); err != nil {
+			return nil, err
+		}
+		list = append(list, def)
+	}
+	return list, rows.Err()
+}
@@ -0,0 +1,78 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package model
+
+// Session is the issued pair of access and refresh tokens of the key
+type Session struct {
+	ID        string `gorm:"primary_key;not null;size:32"`
+	KeyID     int64  `gorm:"not null"`
+	Ecosystem int64  `gorm:"not null"`
+	Device    string `gorm:"not null;size:255"`
+	Remote    string `gorm:"not null;size:255"`
+	Created   int64  `gorm:"not null"`
+	LastSeen  int64  `gorm:"not null"`
+	Expire    int64  `gorm:"not null"`
+	Revoked   int64  `gorm:"not null"`
+}
+
+// TableName returns name of table
+func (s *Session) TableName() string {
+	return "sessions"
+}
+
+// Create is creating record of model
+func (s *Session) Create() error {
+	return DBConn.Create(s).Error
+}
+
+// Get is retrieving model from database
+func (s *Session) Get(id string) (bool, error) {
+	return isFound(DBConn.Where("id = ?", id).First(s))
+}
+
+// Prolong updates the time of the last refresh and the expiration of the session
+func (s *Session) Prolong(lastSeen, expire int64) error {
+	return DBConn.Model(s).Updates(map[string]interface{}{"last_seen": lastSeen, "expire": expire}).Error
+}
+
+// GetActiveSessions returns the sessions of the key which are neither expired nor revoked
+func GetActiveSessions(keyID, ecosystem, now, offset, limit int64) ([]Session, error) {
+	var list []Session
+	err := DBConn.Where("key_id = ? and ecosystem = ? and revoked = 0 and expire > ?", keyID, ecosystem, now).
+		Order("last_seen desc").Offset(offset).Limit(limit).Find(&list).Error
+	return list, err
+}
+
+// RevokeSession marks the active session of the key as revoked and returns false if it isn't found
+func RevokeSession(id string, keyID, ecosystem, now int64) (bool, error) {
+	query := DBConn.Model(&Session{}).Where("id = ? and key_id = ? and ecosystem = ? and revoked = 0",
+		id, keyID, ecosystem).Update("revoked", now)
+	return query.RowsAffected > 0, query.Error
+}
+
+// GetRevokedSessions returns the identifiers of revoked sessions which are not expired yet
+func GetRevokedSessions(now int64) ([]string, error) {
+	var list []string
+	err := DBConn.Model(&Session{}).Where("revoked > 0 and expire > ?", now).Pluck("id", &list).Error
+	return list, err
+}
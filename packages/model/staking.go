@@ -0,0 +1,113 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package model
+
+// ValidatorCandidate is the node which can be elected to full_nodes by the bonded stake
+type ValidatorCandidate struct {
+	ID        int64  `gorm:"primary_key;not null" json:"id"`
+	Host      string `gorm:"not null;size:255" json:"host"`
+	PublicKey string `gorm:"not null;size:128" json:"public_key"`
+	Cert      string `gorm:"not null" json:"cert"`
+	Stake     string `gorm:"not null" json:"stake"`
+	Active    int64  `gorm:"not null" json:"active"`
+}
+
+// TableName returns name of table
+func (ValidatorCandidate) TableName() string {
+	return "validator_candidates"
+}
+
+// Get is retrieving the candidate by the key identifier
+func (c *ValidatorCandidate) Get(transaction *DbTransaction, keyID int64) (bool, error) {
+	return isFound(GetDB(transaction).Where("id = ?", keyID).First(c))
+}
+
+// GetElectableCandidates returns the active candidates with the stake not less than minStake
+// in order of decreasing stake
+func GetElectableCandidates(transaction *DbTransaction, minStake string, limit int) ([]ValidatorCandidate, error) {
+	var list []ValidatorCandidate
+	err := GetDB(transaction).Where("active = 1 AND stake > 0 AND stake >= ?", minStake).
+		Order("stake desc, id").Limit(limit).Find(&list).Error
+	return list, err
+}
+
+// GetValidatorCandidates returns the candidates in order of decreasing stake
+func GetValidatorCandidates(offset, limit int64) ([]ValidatorCandidate, error) {
+	var list []ValidatorCandidate
+	err := DBConn.Order("stake desc, id").Offset(offset).Limit(limit).Find(&list).Error
+	return list, err
+}
+
+// Stake is the amount of tokens bonded by the key to the candidate
+type Stake struct {
+	ID        int64  `gorm:"primary_key;not null" json:"id"`
+	KeyID     int64  `gorm:"not null" json:"key_id"`
+	Validator int64  `gorm:"not null" json:"validator"`
+	Amount    string `gorm:"not null" json:"amount"`
+}
+
+// TableName returns name of table
+func (Stake) TableName() string {
+	return "stakes"
+}
+
+// Get is retrieving the stake of the key bonded to the validator
+func (s *Stake) Get(transaction *DbTransaction, keyID, validator int64) (bool, error) {
+	return isFound(GetDB(transaction).Where("key_id = ? AND validator = ?", keyID, validator).First(s))
+}
+
+// GetStakesOfValidator returns the non-zero stakes bonded to the validator
+func GetStakesOfValidator(transaction *DbTransaction, validator int64) ([]Stake, error) {
+	var list []Stake
+	err := GetDB(transaction).Where("validator = ? AND amount > 0", validator).Order("id").Find(&list).Error
+	return list, err
+}
+
+// Unbonding is the stake which is being unbonded, it can be withdrawn after the release block
+type Unbonding struct {
+	ID           int64  `gorm:"primary_key;not null" json:"id"`
+	KeyID        int64  `gorm:"not null" json:"key_id"`
+	Validator    int64  `gorm:"not null" json:"validator"`
+	Amount       string `gorm:"not null" json:"amount"`
+	ReleaseBlock int64  `gorm:"not null" json:"release_block"`
+	Claimed      int64  `gorm:"not null" json:"claimed"`
+}
+
+// TableName returns name of table
+func (Unbonding) TableName() string {
+	return "unbondings"
+}
+
+// Get is retrieving the unbonding by its identifier
+func (u *Unbonding) Get(transaction *DbTransaction, id int64) (bool, error) {
+	return isFound(GetDB(transaction).Where("id = ?", id).First(u))
+}
+
+// GetPendingUnbondings returns the unclaimed unbondings of the validator which are not released at the block,
+// they still can be slashed
+func GetPendingUnbondings(transaction *DbTransaction, validator, blockID int64) ([]Unbonding, error) {
+	var list []Unbonding
+	err := GetDB(transaction).Where("validator = ? AND claimed = 0 AND release_block > ? AND amount > 0",
+		validator, blockID).Order("id").Find(&list).Error
+	return list, err
+}
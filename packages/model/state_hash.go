@@ -0,0 +1,159 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type stateRow struct {
+	table string
+	id    string
+}
+
+// stateRows returns the sorted unique rows of the rollback records. The batch record is expanded
+// to the rows which it restores
+func stateRows(rollbacks []RollbackTx) ([]stateRow, error) {
+	rows := make([]stateRow, 0, len(rollbacks))
+	exists := make(map[stateRow]bool)
+	add := func(row stateRow) {
+		if !exists[row] {
+			exists[row] = true
+			rows = append(rows, row)
+		}
+	}
+	for _, rt := range rollbacks {
+		if rt.TableID != BatchTableID {
+			add(stateRow{table: rt.NameTable, id: rt.TableID})
+			continue
+		}
+		var items []RollbackBatchItem
+		if err := json.Unmarshal([]byte(rt.Data), &items); err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			add(stateRow{table: rt.NameTable, id: item.ID})
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].table != rows[j].table {
+			return rows[i].table < rows[j].table
+		}
+		return rows[i].id < rows[j].id
+	})
+	return rows, nil
+}
+
+// BlockStateHash returns the hash of the rows which have been modified by the block.
+// The rows are taken from the rollback records so every node hashes the same set of rows
+func BlockStateHash(transaction *DbTransaction, rollbacks []RollbackTx) ([]byte, error) {
+	rows, err := stateRows(rollbacks)
+	if err != nil {
+		return nil, err
+	}
+	tables := make(map[string]bool)
+	hash := sha256.New()
+	for _, row := range rows {
+		found, ok := tables[row.table]
+		if !ok {
+			var count int64
+			err := GetDB(transaction).Table("information_schema.tables").
+				Where("table_schema NOT IN ('pg_catalog', 'information_schema') AND table_name = ?", row.table).
+				Count(&count).Error
+			if err != nil {
+				return nil, err
+			}
+			found = count > 0
+			tables[row.table] = found
+		}
+		var value string
+		if found {
+			if value, err = stateRowValue(transaction, row); err != nil {
+				return nil, err
+			}
+		}
+		fmt.Fprintf(hash, "%s,%s,%s\n", row.table, row.id, value)
+	}
+	return hash.Sum(nil), nil
+}
+
+// stateRowValue returns the canonical text of the row or an empty string if the row doesn't exist.
+// The text of the row isn't taken from the database because it depends on the version of Postgres
+// and the settings of the session like extra_float_digits and DateStyle
+func stateRowValue(transaction *DbTransaction, row stateRow) (string, error) {
+	rows, err := GetDB(transaction).Raw(fmt.Sprintf(`SELECT * FROM "%s" WHERE id::text = ?`, row.table), row.id).Rows()
+	if err != nil {
+		return ``, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return ``, rows.Err()
+	}
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return ``, err
+	}
+	values := make([]interface{}, len(types))
+	ptrs := make([]interface{}, len(types))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	if err = rows.Scan(ptrs...); err != nil {
+		return ``, err
+	}
+	columns := make([]string, len(types))
+	for i, column := range types {
+		columns[i] = column.Name() + `=` + stateValue(column.DatabaseTypeName(), values[i])
+	}
+	return strings.Join(columns, `,`), nil
+}
+
+// stateValue returns the canonical text of the column value. Floats are formatted
+// by the shortest representation and the time is formatted in UTC
+func stateValue(typeName string, value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return `NULL`
+	case []byte:
+		if typeName == `BYTEA` {
+			return hex.EncodeToString(v)
+		}
+		return string(v)
+	case float64:
+		if typeName == `FLOAT4` {
+			return strconv.FormatFloat(v, 'g', -1, 32)
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,85 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package model
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestStateRows(t *testing.T) {
+	rows, err := stateRows([]RollbackTx{
+		{NameTable: "1_keys", TableID: "5"},
+		{NameTable: "1_assets", TableID: BatchTableID, Data: `[{"id":"3","data":{"amount":"1"}},{"id":"4"}]`},
+		{NameTable: "1_keys", TableID: "2"},
+		{NameTable: "1_assets", TableID: "3"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []stateRow{{"1_assets", "3"}, {"1_assets", "4"}, {"1_keys", "2"}, {"1_keys", "5"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("wrong rows %v", rows)
+	}
+	if _, err = stateRows([]RollbackTx{{NameTable: "1_assets", TableID: BatchTableID, Data: `{`}}); err == nil {
+		t.Error("invalid batch record must be rejected")
+	}
+}
+
+func TestStateValue(t *testing.T) {
+	parse := func(text string, bits int) float64 {
+		f, err := strconv.ParseFloat(text, bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return f
+	}
+	moscow := time.FixedZone("MSK", 3*3600)
+	instant := time.Date(2018, 5, 1, 10, 0, 0, 500000000, time.UTC)
+	// the values of the same column which are printed by different versions and settings of Postgres
+	cases := []struct {
+		typeName string
+		values   []interface{}
+		want     string
+	}{
+		{"FLOAT8", []interface{}{parse("0.1", 64), parse("0.10000000000000001", 64)}, "0.1"},
+		{"FLOAT8", []interface{}{parse("1e+20", 64), parse("100000000000000000000", 64)}, "1e+20"},
+		{"FLOAT4", []interface{}{parse("0.1", 32), parse("0.100000001", 32)}, "0.1"},
+		{"TIMESTAMPTZ", []interface{}{instant, instant.In(moscow), instant.Local()}, "2018-05-01T10:00:00.5Z"},
+		{"TIMESTAMP", []interface{}{time.Date(2018, 5, 1, 10, 0, 0, 0, time.FixedZone("", 0))}, "2018-05-01T10:00:00Z"},
+		{"BYTEA", []interface{}{[]byte{1, 0xab}}, "01ab"},
+		{"NUMERIC", []interface{}{[]byte("12.50")}, "12.50"},
+		{"INT8", []interface{}{int64(-3)}, "-3"},
+		{"BOOL", []interface{}{true}, "true"},
+		{"TEXT", []interface{}{nil}, "NULL"},
+	}
+	for _, c := range cases {
+		for _, value := range c.values {
+			if got := stateValue(c.typeName, value); got != c.want {
+				t.Errorf("%s %v: expected %s, got %s", c.typeName, value, c.want, got)
+			}
+		}
+	}
+}
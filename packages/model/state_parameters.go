@@ -43,6 +43,9 @@ func (sp *StateParameter) SetTablePrefix(tablePrefix string) {
 
 // Get is retrieving model from database
 func (sp *StateParameter) Get(transaction *DbTransaction, name string) (bool, error) {
+	if fastQueries() {
+		return sp.getFast(transaction, name)
+	}
 	return isFound(GetDB(transaction).Where("name = ?", name).First(sp))
 }
 
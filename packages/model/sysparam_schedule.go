@@ -0,0 +1,108 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package model
+
+// SysParamSchedule is the change of system parameter which is activated at the block.
+// The previous value and conditions are kept to restore them when the activation block is rolled back
+type SysParamSchedule struct {
+	ID              int64  `gorm:"primary_key;not null" json:"id"`
+	Name            string `gorm:"not null;size:255" json:"name"`
+	Value           string `gorm:"not null" json:"value"`
+	Conditions      string `gorm:"not null" json:"conditions"`
+	ActivationBlock int64  `gorm:"not null" json:"activation_block"`
+	AppliedBlock    int64  `gorm:"not null" json:"applied_block"`
+	PrevValue       string `gorm:"not null" json:"-"`
+	PrevConditions  string `gorm:"not null" json:"-"`
+}
+
+// TableName returns name of table
+func (s SysParamSchedule) TableName() string {
+	return "system_parameters_schedule"
+}
+
+// GetPendingSysParams returns the scheduled changes which haven't been activated yet
+func GetPendingSysParams(limit, offset int) ([]SysParamSchedule, error) {
+	var list []SysParamSchedule
+	err := DBConn.Where("applied_block = 0").Order("activation_block, id").Limit(limit).Offset(offset).Find(&list).Error
+	return list, err
+}
+
+// ActivateSysParams writes the changes scheduled to the block or earlier to system_parameters
+// and returns the count of activated changes
+func ActivateSysParams(transaction *DbTransaction, blockID int64) (int, error) {
+	var list []SysParamSchedule
+	err := GetDB(transaction).Where("applied_block = 0 and activation_block <= ?", blockID).
+		Order("activation_block, id").Find(&list).Error
+	if err != nil {
+		return 0, err
+	}
+	for _, item := range list {
+		par := &SystemParameter{}
+		found, err := isFound(GetDB(transaction).Where("name = ?", item.Name).First(par))
+		if err != nil {
+			return 0, err
+		}
+		if !found {
+			continue
+		}
+		err = GetDB(transaction).Model(&SysParamSchedule{}).Where("id = ?", item.ID).Updates(map[string]interface{}{
+			"applied_block": blockID, "prev_value": par.Value, "prev_conditions": par.Conditions}).Error
+		if err != nil {
+			return 0, err
+		}
+		update := map[string]interface{}{}
+		if len(item.Value) > 0 {
+			update["value"] = item.Value
+		}
+		if len(item.Conditions) > 0 {
+			update["conditions"] = item.Conditions
+		}
+		if err = GetDB(transaction).Model(&SystemParameter{}).Where("id = ?", par.ID).Updates(update).Error; err != nil {
+			return 0, err
+		}
+	}
+	return len(list), nil
+}
+
+// DeactivateSysParams restores the system parameters which have been changed at the block
+// and returns the count of restored changes
+func DeactivateSysParams(transaction *DbTransaction, blockID int64) (int, error) {
+	var list []SysParamSchedule
+	err := GetDB(transaction).Where("applied_block = ?", blockID).Order("activation_block desc, id desc").Find(&list).Error
+	if err != nil {
+		return 0, err
+	}
+	for _, item := range list {
+		err = GetDB(transaction).Model(&SystemParameter{}).Where("name = ?", item.Name).Updates(map[string]interface{}{
+			"value": item.PrevValue, "conditions": item.PrevConditions}).Error
+		if err != nil {
+			return 0, err
+		}
+		err = GetDB(transaction).Model(&SysParamSchedule{}).Where("id = ?", item.ID).Updates(map[string]interface{}{
+			"applied_block": 0, "prev_value": "", "prev_conditions": ""}).Error
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(list), nil
+}
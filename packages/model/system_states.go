@@ -24,7 +24,11 @@ package model
 
 // SystemState is model
 type SystemState struct {
-	ID int64 `gorm:"primary_key;not null"`
+	ID               int64  `gorm:"primary_key;not null"`
+	KeyID            int64  `gorm:"not null"`
+	Frozen           int64  `gorm:"not null"`
+	UnfreezeContract string `gorm:"not null"`
+	Archived         int64  `gorm:"not null"`
 }
 
 // TableName returns name of table
@@ -32,6 +36,11 @@ func (ss *SystemState) TableName() string {
 	return "system_states"
 }
 
+// Get is retrieving model from database
+func (ss *SystemState) Get(transaction *DbTransaction, id int64) (bool, error) {
+	return isFound(GetDB(transaction).Where("id = ?", id).First(ss))
+}
+
 // GetAllSystemStatesIDs is retrieving all system states ids
 func GetAllSystemStatesIDs() ([]int64, error) {
 	states := new([]SystemState)
@@ -49,3 +58,9 @@ func GetAllSystemStatesIDs() ([]int64, error) {
 func (ss *SystemState) Delete(transaction *DbTransaction) error {
 	return GetDB(transaction).Delete(ss).Error
 }
+
+// CountEcosystemsByKey returns the number of ecosystems created by the key
+func CountEcosystemsByKey(transaction *DbTransaction, keyID int64) (count int64, err error) {
+	err = GetDB(transaction).Model(&SystemState{}).Where("key_id = ?", keyID).Count(&count).Error
+	return
+}
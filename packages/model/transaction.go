@@ -44,10 +44,10 @@ func GetAllTransactions(limit int) (*[]Transaction, error) {
 	return transactions, nil
 }
 
-// GetAllUnusedTransactions is retrieving all unused transactions
+// GetAllUnusedTransactions is retrieving all unused transactions, the system ones are first
 func GetAllUnusedTransactions() ([]Transaction, error) {
 	var transactions []Transaction
-	if err := DBConn.Where("used = ?", "0").Find(&transactions).Error; err != nil {
+	if err := DBConn.Where("used = ?", "0").Order("high_rate desc").Find(&transactions).Error; err != nil {
 		return nil, err
 	}
 	return transactions, nil
@@ -71,6 +71,15 @@ func GetTransactionCountAll() (int64, error) {
 	return rowsCount, nil
 }
 
+// GetUnusedTransactionsCount counts the transactions which are waiting for a block
+func GetUnusedTransactionsCount() (int64, error) {
+	var rowsCount int64
+	if err := DBConn.Table("transactions").Where("used = ?", "0").Count(&rowsCount).Error; err != nil {
+		return -1, err
+	}
+	return rowsCount, nil
+}
+
 // GetTransactionsCount count all transactions by hash
 func GetTransactionsCount(hash []byte) (int64, error) {
 	var rowsCount int64
@@ -116,12 +125,25 @@ func MarkTransactionUsed(transaction *DbTransaction, transactionHash []byte) (in
 	return query.RowsAffected, query.Error
 }
 
+// DeleteUnusedTransaction deletes the transaction if it hasn't been used yet
+func DeleteUnusedTransaction(transaction *DbTransaction, transactionHash []byte) (int64, error) {
+	query := GetDB(transaction).Exec("DELETE FROM transactions WHERE hash = ? AND used = 0", transactionHash)
+	return query.RowsAffected, query.Error
+}
+
 // MarkTransactionUnusedAndUnverified is marking transaction unused and unverified
 func MarkTransactionUnusedAndUnverified(transaction *DbTransaction, transactionHash []byte) (int64, error) {
 	query := GetDB(transaction).Exec("UPDATE transactions SET used = 0, verified = 0 WHERE hash = ?", transactionHash)
 	return query.RowsAffected, query.Error
 }
 
+// MarkUnloggedTransactionsUnused returns to the queue the used transactions which are not in any applied block
+func MarkUnloggedTransactionsUnused(transaction *DbTransaction) (int64, error) {
+	query := GetDB(transaction).Exec(`UPDATE transactions SET used = 0, verified = 0 WHERE used = 1 AND
+		NOT EXISTS (SELECT 1 FROM log_transactions WHERE log_transactions.hash = transactions.hash)`)
+	return query.RowsAffected, query.Error
+}
+
 // MarkVerifiedAndNotUsedTransactionsUnverified is marking verified and unused transaction as unverified
 func MarkVerifiedAndNotUsedTransactionsUnverified() (int64, error) {
 	query := DBConn.Exec("UPDATE transactions SET verified = 0 WHERE verified = 1 AND used = 0")
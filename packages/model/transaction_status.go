@@ -30,6 +30,12 @@ type TransactionStatus struct {
 	WalletID int64  `gorm:"not null"`
 	BlockID  int64  `gorm:"not null"`
 	Error    string `gorm:"not null;size 255"`
+	Payer    int64  `gorm:"not null"`
+	// ExpireBlock and ExpireTime are the expiration of the transaction from its header
+	ExpireBlock int64 `gorm:"not null"`
+	ExpireTime  int64 `gorm:"not null"`
+	// RequestID is X-Request-ID of the API call which has sent the transaction
+	RequestID string `gorm:"not null"`
 }
 
 // TableName returns name of table
@@ -52,10 +58,10 @@ func (ts *TransactionStatus) UpdateBlockID(transaction *DbTransaction, newBlockI
 	return GetDB(transaction).Model(&TransactionStatus{}).Where("hash = ?", transactionHash).Update("block_id", newBlockID).Error
 }
 
-// UpdateBlockMsg is updating block msg
-func (ts *TransactionStatus) UpdateBlockMsg(transaction *DbTransaction, newBlockID int64, msg string, transactionHash []byte) error {
+// UpdateBlockMsg is updating block msg and the wallet which has paid for the transaction
+func (ts *TransactionStatus) UpdateBlockMsg(transaction *DbTransaction, newBlockID int64, msg string, payer int64, transactionHash []byte) error {
 	return GetDB(transaction).Model(&TransactionStatus{}).Where("hash = ?", transactionHash).Updates(
-		map[string]interface{}{"block_id": newBlockID, "error": msg}).Error
+		map[string]interface{}{"block_id": newBlockID, "error": msg, "payer": payer}).Error
 }
 
 // SetError is updating transaction status error
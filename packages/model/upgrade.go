@@ -0,0 +1,165 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package model
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+
+	version "github.com/hashicorp/go-version"
+	log "github.com/sirupsen/logrus"
+)
+
+// UpgradeFunc is the data migration, for example, the recomputation of indexes. It is run in the transaction
+type UpgradeFunc func(transaction *DbTransaction) error
+
+type upgrade struct {
+	version string
+	name    string
+	run     UpgradeFunc
+}
+
+var upgrades []*upgrade
+
+// RegisterUpgrade registers the data migration which is run once at the first start of the node
+// of the version or newer. The name identifies the migration in node_upgrades table
+func RegisterUpgrade(ver, name string, run UpgradeFunc) {
+	for _, u := range upgrades {
+		if u.name == name {
+			panic(fmt.Sprintf("upgrade %s is already registered", name))
+		}
+	}
+	upgrades = append(upgrades, &upgrade{version: ver, name: name, run: run})
+}
+
+// NodeUpgrade is the record of applied data migration
+type NodeUpgrade struct {
+	ID          int64  `gorm:"primary_key;not null"`
+	Name        string `gorm:"not null"`
+	Version     string `gorm:"not null"`
+	DateApplied int64  `gorm:"not null"`
+}
+
+// TableName returns name of table
+func (nu *NodeUpgrade) TableName() string {
+	return "node_upgrades"
+}
+
+func appliedUpgrades() (map[string]bool, error) {
+	var list []NodeUpgrade
+	if err := DBConn.Find(&list).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[string]bool)
+	for _, item := range list {
+		applied[item.Name] = true
+	}
+	return applied, nil
+}
+
+// pendingUpgrades returns the not applied migrations of versions up to appVer in the order of versions
+func pendingUpgrades(list []*upgrade, applied map[string]bool, appVer *version.Version) ([]*upgrade, error) {
+	versions := make(map[string]*version.Version)
+	pending := make([]*upgrade, 0)
+	for _, u := range list {
+		ver, err := version.NewVersion(u.version)
+		if err != nil {
+			return nil, err
+		}
+		if applied[u.name] || ver.GreaterThan(appVer) {
+			continue
+		}
+		versions[u.name] = ver
+		pending = append(pending, u)
+	}
+	sort.SliceStable(pending, func(i, j int) bool {
+		return versions[pending[i].name].LessThan(versions[pending[j].name])
+	})
+	return pending, nil
+}
+
+func applyUpgrade(u *upgrade) error {
+	transaction, err := StartTransaction()
+	if err != nil {
+		return err
+	}
+	if u.run != nil {
+		if err = u.run(transaction); err != nil {
+			transaction.Rollback()
+			return err
+		}
+	}
+	err = GetDB(transaction).Create(&NodeUpgrade{Name: u.name, Version: u.version,
+		DateApplied: time.Now().Unix()}).Error
+	if err != nil {
+		transaction.Rollback()
+		return err
+	}
+	return transaction.Commit()
+}
+
+// markUpgrades records the registered migrations as applied, the new database doesn't need them
+func markUpgrades() error {
+	for _, u := range upgrades {
+		err := DBConn.Create(&NodeUpgrade{Name: u.name, Version: u.version, DateApplied: time.Now().Unix()}).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Upgrade applies the schema migrations and then the registered data migrations
+// which haven't been applied to the database yet
+func Upgrade() error {
+	if err := ExecSchema(); err != nil {
+		log.WithFields(log.Fields{"type": consts.MigrationError, "error": err}).Error("executing db schema")
+		return err
+	}
+	appVer, err := version.NewVersion(consts.VERSION)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.MigrationError, "error": err}).Error("parse version")
+		return err
+	}
+	applied, err := appliedUpgrades()
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting applied upgrades")
+		return err
+	}
+	pending, err := pendingUpgrades(upgrades, applied, appVer)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.MigrationError, "error": err}).Error("parse version")
+		return err
+	}
+	for _, u := range pending {
+		if err = applyUpgrade(u); err != nil {
+			log.WithFields(log.Fields{"type": consts.MigrationError, "error": err, "name": u.name}).Error("applying upgrade")
+			return err
+		}
+		log.WithFields(log.Fields{"version": u.version, "name": u.name}).Info("apply upgrade")
+	}
+	return nil
+}
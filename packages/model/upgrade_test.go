@@ -0,0 +1,56 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	version "github.com/hashicorp/go-version"
+)
+
+func TestPendingUpgrades(t *testing.T) {
+	list := []*upgrade{
+		{version: "0.1.7", name: "c"},
+		{version: "0.1.6b41", name: "b"},
+		{version: "0.1.6b40", name: "a"},
+		{version: "0.1.8", name: "future"},
+		{version: "0.1.6b41", name: "applied"},
+	}
+	pending, err := pendingUpgrades(list, map[string]bool{"applied": true},
+		version.Must(version.NewVersion("0.1.7")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make([]string, len(pending))
+	for i, u := range pending {
+		names[i] = u.name
+	}
+	if strings.Join(names, ",") != "a,b,c" {
+		t.Errorf("wrong order of upgrades %v", names)
+	}
+	if _, err = pendingUpgrades([]*upgrade{{version: "wrong", name: "x"}}, nil,
+		version.Must(version.NewVersion("0.1.7"))); err == nil {
+		t.Error("error is expected")
+	}
+}
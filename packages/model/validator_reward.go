@@ -0,0 +1,78 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package model
+
+import "fmt"
+
+// Roles of the keys which receive the rewards
+const (
+	RewardProducer  = `producer`
+	RewardValidator = `validator`
+)
+
+// ValidatorReward is the part of fees accrued to the key for the block
+type ValidatorReward struct {
+	ID        int64  `gorm:"primary_key;not null" json:"id"`
+	BlockID   int64  `gorm:"not null" json:"block_id"`
+	KeyID     int64  `gorm:"not null" json:"key_id"`
+	Ecosystem int64  `gorm:"not null" json:"ecosystem"`
+	Role      string `gorm:"not null;size:16" json:"role"`
+	Amount    string `gorm:"not null" json:"amount"`
+}
+
+// TableName returns name of table
+func (ValidatorReward) TableName() string {
+	return "validator_rewards"
+}
+
+// RewardClaim is the withdrawal of the accrued rewards to the wallet
+type RewardClaim struct {
+	ID        int64  `gorm:"primary_key;not null" json:"id"`
+	KeyID     int64  `gorm:"not null" json:"key_id"`
+	Ecosystem int64  `gorm:"not null" json:"ecosystem"`
+	Amount    string `gorm:"not null" json:"amount"`
+	BlockID   int64  `gorm:"not null" json:"block_id"`
+}
+
+// TableName returns name of table
+func (RewardClaim) TableName() string {
+	return "validator_reward_claims"
+}
+
+// GetRewardTotals returns the sums of the accrued and the claimed rewards of the key in the token ecosystem
+func GetRewardTotals(transaction *DbTransaction, keyID, ecosystem int64) (accrued string, claimed string, err error) {
+	query := `SELECT COALESCE(SUM(amount), 0) FROM "%s" WHERE key_id = ? AND ecosystem = ?`
+	if err = GetDB(transaction).Raw(fmt.Sprintf(query, ValidatorReward{}.TableName()), keyID, ecosystem).Row().Scan(&accrued); err != nil {
+		return
+	}
+	err = GetDB(transaction).Raw(fmt.Sprintf(query, RewardClaim{}.TableName()), keyID, ecosystem).Row().Scan(&claimed)
+	return
+}
+
+// GetValidatorRewards returns the accruals of the key in the token ecosystem in reverse order
+func GetValidatorRewards(keyID, ecosystem, offset, limit int64) ([]ValidatorReward, error) {
+	var list []ValidatorReward
+	err := DBConn.Where("key_id = ? AND ecosystem = ?", keyID, ecosystem).Order("id desc").
+		Offset(offset).Limit(limit).Find(&list).Error
+	return list, err
+}
@@ -0,0 +1,134 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package netguard
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// AllowlistParam is the VDE parameter with comma separated list of allowed domains
+const AllowlistParam = `http_allowlist`
+
+const maxRedirects = 5
+
+// ErrPrivateAddress is returned if the host is resolved to the loopback, private or link-local address
+var ErrPrivateAddress = errors.New(`private network addresses are not allowed`)
+
+// Policy restricts outgoing http requests to the domains of the allowlist. The addresses of the domains
+// are checked by the dialer after the resolution, so the allowed domain can't point to the internal network
+type Policy struct {
+	Allowlist []string
+	Timeout   time.Duration
+	// AllowPrivate disables the check of addresses, it must be used only for the local services
+	AllowPrivate bool
+}
+
+// ParseAllowlist parses the comma separated list of domains
+func ParseAllowlist(value string) []string {
+	list := make([]string, 0)
+	for _, item := range strings.Split(value, `,`) {
+		item = strings.ToLower(strings.TrimSpace(item))
+		if len(item) > 0 {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// IsAllowedHost checks the host against the allowlist. The item can be the exact domain,
+// the wildcard like *.example.com for subdomains, or * for any domain
+func (p *Policy) IsAllowedHost(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, `.`))
+	for _, item := range p.Allowlist {
+		switch {
+		case item == `*`:
+			return true
+		case strings.HasPrefix(item, `*.`):
+			if strings.HasSuffix(host, item[1:]) {
+				return true
+			}
+		case host == item:
+			return true
+		}
+	}
+	return false
+}
+
+// CheckURL checks the scheme and the domain of url
+func (p *Policy) CheckURL(u *url.URL) error {
+	if u.Scheme != `http` && u.Scheme != `https` {
+		return fmt.Errorf(`unsupported url scheme %s`, u.Scheme)
+	}
+	host := u.Hostname()
+	if !p.IsAllowedHost(host) {
+		return fmt.Errorf(`domain %s is not in %s`, host, AllowlistParam)
+	}
+	return nil
+}
+
+// Client returns the http client which checks the redirects and the addresses of connections
+func (p *Policy) Client() *http.Client {
+	dialer := &net.Dialer{Timeout: p.Timeout}
+	if !p.AllowPrivate {
+		dialer.Control = checkAddress
+	}
+	return &http.Client{
+		Timeout: p.Timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: p.Timeout,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf(`stopped after %d redirects`, maxRedirects)
+			}
+			return p.CheckURL(req.URL)
+		},
+	}
+}
+
+// IsPrivateIP returns true for the loopback, private, link-local and unspecified addresses
+func IsPrivateIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsUnspecified()
+}
+
+// checkAddress is called by the dialer with the resolved address before the connection
+func checkAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || IsPrivateIP(ip) {
+		return ErrPrivateAddress
+	}
+	return nil
+}
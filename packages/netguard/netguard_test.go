@@ -0,0 +1,101 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package netguard
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestPolicyAllowedHost(t *testing.T) {
+	policy := &Policy{Allowlist: ParseAllowlist(` api.example.com, *.Data.org ,`)}
+	cases := map[string]bool{
+		`http://api.example.com/v1`:    true,
+		`https://API.example.com:8080`: true,
+		`http://example.com`:           false,
+		`http://eu.data.org/feed`:      true,
+		`http://data.org`:              false,
+		`http://evildata.org`:          false,
+		`ftp://api.example.com`:        false,
+	}
+	for rawURL, allowed := range cases {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = policy.CheckURL(u); (err == nil) != allowed {
+			t.Errorf("%s: expected allowed %v, got %v", rawURL, allowed, err)
+		}
+	}
+
+	u, _ := url.Parse(`http://localhost`)
+	if err := (&Policy{}).CheckURL(u); err == nil {
+		t.Error("empty allowlist must deny all domains")
+	}
+	if err := (&Policy{Allowlist: []string{`*`}}).CheckURL(u); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestIsPrivateIP(t *testing.T) {
+	cases := map[string]bool{
+		`127.0.0.1`:       true,
+		`10.1.2.3`:        true,
+		`172.16.0.1`:      true,
+		`192.168.1.1`:     true,
+		`169.254.169.254`: true,
+		`0.0.0.0`:         true,
+		`::1`:             true,
+		`fd00::1`:         true,
+		`fe80::1`:         true,
+		`8.8.8.8`:         false,
+		`2001:4860::8888`: false,
+	}
+	for addr, private := range cases {
+		if IsPrivateIP(net.ParseIP(addr)) != private {
+			t.Errorf("%s: expected private %v", addr, private)
+		}
+	}
+}
+
+func TestClientBlocksPrivate(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	policy := &Policy{Allowlist: []string{`*`}}
+	_, err := policy.Client().Get(ts.URL)
+	if err == nil || !strings.Contains(err.Error(), ErrPrivateAddress.Error()) {
+		t.Errorf("expected %v, got %v", ErrPrivateAddress, err)
+	}
+
+	policy.AllowPrivate = true
+	resp, err := policy.Client().Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+}
@@ -0,0 +1,186 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package node runs Genesis node in the process of Go program
+package node
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/api"
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+	"github.com/GenesisKernel/go-genesis/packages/config/syspar"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/daemons"
+	"github.com/GenesisKernel/go-genesis/packages/daylight/daemonsctl"
+	"github.com/GenesisKernel/go-genesis/packages/graceful"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/parser"
+	"github.com/GenesisKernel/go-genesis/packages/smart"
+
+	"github.com/jinzhu/gorm"
+	"github.com/julienschmidt/httprouter"
+	log "github.com/sirupsen/logrus"
+)
+
+// drainTimeout is the time of waiting for the current tcp requests when the node is stopped
+const drainTimeout = 10 * time.Second
+
+var (
+	// ErrStarted is returned if a node has already been started in the process
+	ErrStarted = errors.New("node is already started in the process")
+	// ErrNotStarted is returned if the node isn't started
+	ErrNotStarted = errors.New("node is not started")
+
+	mutex   sync.Mutex
+	running *Node
+)
+
+// Node is Genesis node embedded into the process. The node keeps its state in the global variables
+// of the packages like conf.Config and model.DBConn, so only one node can be started in the process
+type Node struct {
+	// Config replaces conf.Config when the node is started, WorkDir and DB are required
+	Config conf.SavedConfig
+	// Daemons starts the daemons and the tcp server of the node, otherwise the node only serves API
+	Daemons bool
+
+	router *httprouter.Router
+}
+
+// New returns the node with the config
+func New(config conf.SavedConfig) *Node {
+	return &Node{Config: config}
+}
+
+// Start connects to the database, upgrades it, loads the contracts and starts the daemons if they are enabled
+func (n *Node) Start() (err error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if running != nil {
+		return ErrStarted
+	}
+
+	oldConfig, oldInstalled := conf.Config, conf.Installed
+	defer func() {
+		if err != nil {
+			conf.Config, conf.Installed = oldConfig, oldInstalled
+		}
+	}()
+	conf.Config = n.Config
+	conf.Installed = true
+	for _, dir := range []*string{&conf.Config.PrivateDir, &conf.Config.DataDir, &conf.Config.VDEDir, &conf.Config.LogDir} {
+		if len(*dir) == 0 {
+			*dir = conf.Config.WorkDir
+		}
+	}
+	if problems := conf.ValidateConfig(false); len(problems) > 0 {
+		return fmt.Errorf("invalid config: %s", problems[0])
+	}
+	if conf.Config.KeyID == 0 {
+		if conf.Config.KeyID, err = parser.GetKeyIDFromPrivateKey(); err != nil {
+			return err
+		}
+	}
+
+	db := conf.Config.DB
+	if err = model.GormInit(db.Host, db.Port, db.User, db.Password, db.Name); err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			model.GormClose()
+		}
+	}()
+	if err = model.Upgrade(); err != nil {
+		log.WithFields(log.Fields{"type": consts.MigrationError, "error": err}).Error("upgrading database")
+		return err
+	}
+	if n.Daemons {
+		if err = daemonsctl.RunAllDaemons(); err != nil {
+			daemons.StopAllDaemons()
+			graceful.Drain(drainTimeout)
+			graceful.Reset()
+			return err
+		}
+	} else {
+		if err = syspar.SysUpdate(nil); err != nil {
+			return err
+		}
+		if err = smart.LoadContracts(nil); err != nil {
+			return err
+		}
+	}
+
+	n.router = httprouter.New()
+	n.router.HandlerFunc(`GET`, `/monitoring`, daemons.Monitoring)
+	n.router.HandlerFunc(`GET`, `/healthz`, daemons.Healthz)
+	n.router.HandlerFunc(`GET`, `/readyz`, daemons.Readyz)
+	api.Route(n.router)
+	running = n
+	return nil
+}
+
+// Stop stops the daemons and the tcp server and closes the connection to the database
+func (n *Node) Stop() error {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if running != n {
+		return ErrNotStarted
+	}
+	if n.Daemons {
+		daemons.StopAllDaemons()
+		graceful.Drain(drainTimeout)
+		graceful.Reset()
+	}
+	running, n.router = nil, nil
+	return model.GormClose()
+}
+
+// Router returns the router of API which can be served by http.Server or httptest.Server, it's nil if the node isn't started
+func (n *Node) Router() *httprouter.Router {
+	return n.router
+}
+
+// DB returns the connection to the database of the node
+func (n *Node) DB() *gorm.DB {
+	return model.DBConn
+}
+
+// CreateVDE creates the tables of VDE of the ecosystem, the founder is the node key if keyID is 0
+func (n *Node) CreateVDE(ecosystem, keyID int64) error {
+	if n.router == nil {
+		return ErrNotStarted
+	}
+	if keyID == 0 {
+		keyID = conf.Config.KeyID
+	}
+	if model.IsTable(fmt.Sprintf(`%d_vde_tables`, ecosystem)) {
+		return fmt.Errorf("VDE of ecosystem %d has already been created", ecosystem)
+	}
+	if err := model.ExecSchemaLocalData(int(ecosystem), keyID); err != nil {
+		return err
+	}
+	return smart.LoadVDEContracts(nil, fmt.Sprint(ecosystem))
+}
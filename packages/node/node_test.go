@@ -0,0 +1,58 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package node
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+)
+
+func TestStartInvalidConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "node")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	old := conf.Config
+	n := New(conf.SavedConfig{WorkDir: dir, PrivateDir: filepath.Join(dir, "missing"), LogLevel: "INFO"})
+	if err = n.Start(); err == nil {
+		t.Fatal("node is started without keys")
+	}
+	if conf.Config.WorkDir != old.WorkDir || conf.Config.PrivateDir != old.PrivateDir {
+		t.Error("config is not restored")
+	}
+	if n.Router() != nil {
+		t.Error("router of not started node")
+	}
+	if err = n.Stop(); err != ErrNotStarted {
+		t.Errorf("wrong error of stop %v", err)
+	}
+	if err = n.CreateVDE(1, 0); err != ErrNotStarted {
+		t.Errorf("wrong error of VDE %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package nodecert issues and verifies the certificates of nodes of permissioned networks.
+// The certificate is the signature of the network CA for the public key of node
+package nodecert
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/crypto"
+)
+
+var (
+	// ErrFormat is returned if the certificate can't be parsed
+	ErrFormat = errors.New("wrong format of certificate")
+	// ErrExpired is returned if the certificate has expired
+	ErrExpired = errors.New("certificate has expired")
+	// ErrSign is returned if the certificate isn't signed by the network CA
+	ErrSign = errors.New("certificate isn't signed by network CA")
+	// ErrNode is returned if the certificate belongs to another node
+	ErrNode = errors.New("certificate belongs to another node")
+)
+
+// Certificate is the public key of node signed by the network CA
+type Certificate struct {
+	NodePublicKey []byte
+	Expire        int64 // unix time, 0 means the certificate doesn't expire
+	Sign          []byte
+}
+
+func (c *Certificate) data() string {
+	return fmt.Sprintf("node,%x,%d", c.NodePublicKey, c.Expire)
+}
+
+// String returns the certificate in form "public_key:expire:sign" with hex values
+func (c *Certificate) String() string {
+	return fmt.Sprintf("%x:%d:%x", c.NodePublicKey, c.Expire, c.Sign)
+}
+
+// Issue signs the public key of node by the private key of CA
+func Issue(caPrivateKey string, nodePublicKey []byte, expire int64) (*Certificate, error) {
+	cert := &Certificate{NodePublicKey: nodePublicKey, Expire: expire}
+	sign, err := crypto.Sign(caPrivateKey, cert.data())
+	if err != nil {
+		return nil, err
+	}
+	cert.Sign = sign
+	return cert, nil
+}
+
+// Parse returns the certificate from its string form
+func Parse(value string) (*Certificate, error) {
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	if len(parts) != 3 {
+		return nil, ErrFormat
+	}
+	var (
+		cert Certificate
+		err  error
+	)
+	if cert.NodePublicKey, err = hex.DecodeString(parts[0]); err != nil || len(cert.NodePublicKey) == 0 {
+		return nil, ErrFormat
+	}
+	if cert.Expire, err = strconv.ParseInt(parts[1], 10, 64); err != nil {
+		return nil, ErrFormat
+	}
+	if cert.Sign, err = hex.DecodeString(parts[2]); err != nil || len(cert.Sign) == 0 {
+		return nil, ErrFormat
+	}
+	return &cert, nil
+}
+
+// Load reads the certificate from the file
+func Load(path string) (*Certificate, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(string(data))
+}
+
+// Verify checks that the certificate is signed by CA and hasn't expired
+func (c *Certificate) Verify(caPublicKey []byte, now time.Time) error {
+	if c.Expire > 0 && now.Unix() > c.Expire {
+		return ErrExpired
+	}
+	ok, err := crypto.CheckSign(caPublicKey, c.data(), c.Sign)
+	if err != nil || !ok {
+		return ErrSign
+	}
+	return nil
+}
+
+// VerifyNode checks the certificate of the node with the public key
+func VerifyNode(value string, nodePublicKey, caPublicKey []byte, now time.Time) error {
+	cert, err := Parse(value)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(cert.NodePublicKey, nodePublicKey) {
+		return ErrNode
+	}
+	return cert.Verify(caPublicKey, now)
+}
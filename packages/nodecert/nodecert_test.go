@@ -0,0 +1,62 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nodecert
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/crypto"
+)
+
+func TestCertificate(t *testing.T) {
+	caPriv, caPub, err := crypto.GenBytesKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, nodePub, err := crypto.GenBytesKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	cert, err := Issue(hex.EncodeToString(caPriv), nodePub, now.Add(time.Hour).Unix())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = VerifyNode(cert.String(), nodePub, caPub, now); err != nil {
+		t.Error(err)
+	}
+	if err = VerifyNode(cert.String(), nodePub, caPub, now.Add(2*time.Hour)); err != ErrExpired {
+		t.Errorf("expected %v, got %v", ErrExpired, err)
+	}
+	if err = VerifyNode(cert.String(), caPub, caPub, now); err != ErrNode {
+		t.Errorf("expected %v, got %v", ErrNode, err)
+	}
+	if err = VerifyNode(cert.String(), nodePub, nodePub, now); err != ErrSign {
+		t.Errorf("expected %v, got %v", ErrSign, err)
+	}
+	if _, err = Parse("00:1"); err != ErrFormat {
+		t.Errorf("expected %v, got %v", ErrFormat, err)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/publisher"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -217,3 +219,52 @@ func TestStatsChanged(t *testing.T) {
 		}
 	}
 }
+
+func TestTxStatus(t *testing.T) {
+	for _, item := range []struct {
+		ts     model.TransactionStatus
+		found  bool
+		status string
+		err    string
+	}{
+		{model.TransactionStatus{}, false, TxQueued, ``},
+		{model.TransactionStatus{}, true, TxQueued, ``},
+		{model.TransactionStatus{BlockID: 10, Error: `ok`}, true, TxInBlock, ``},
+		{model.TransactionStatus{Error: `not enough money`}, true, TxFailed, `not enough money`},
+		{model.TransactionStatus{Error: `{"type":"cancelled","error":"transaction has been cancelled"}`}, true,
+			`cancelled`, `transaction has been cancelled`},
+	} {
+		msg := txStatus(&item.ts, item.found)
+		assert.Equal(t, item.status, msg.Status)
+		assert.Equal(t, item.err, msg.Error)
+		if item.status == TxInBlock {
+			assert.Equal(t, int64(10), msg.BlockID)
+			assert.Equal(t, `ok`, msg.Result)
+		}
+	}
+}
+
+type testPublisher struct {
+	channels []string
+	messages []string
+}
+
+func (tp *testPublisher) Publish(channel string, data []byte) (bool, error) {
+	tp.channels = append(tp.channels, channel)
+	tp.messages = append(tp.messages, string(data))
+	return true, nil
+}
+
+func TestWatchTx(t *testing.T) {
+	pub := &testPublisher{}
+	publisher.SetPublisher(pub)
+	defer publisher.SetPublisher(nil)
+	WatchTx([]byte{1, 2}, 5, `sess`)
+	assert.Equal(t, []string{`client5`}, pub.channels)
+	var msg TxStatusMessage
+	assert.NoError(t, json.Unmarshal([]byte(pub.messages[0]), &msg))
+	assert.Equal(t, TxStatusMessage{Type: `txstatus`, Hash: `0102`, Session: `sess`, Status: TxQueued}, msg)
+	watchedTxsMu.Lock()
+	delete(watchedTxs, `0102`)
+	watchedTxsMu.Unlock()
+}
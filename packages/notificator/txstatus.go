@@ -0,0 +1,151 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package notificator
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/publisher"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// TxQueued is the status of the transaction which waits for a block
+	TxQueued = `queued`
+	// TxInBlock is the status of the transaction which has been included into the block
+	TxInBlock = `block`
+	// TxFailed is the status of the transaction which has been rejected with the error
+	TxFailed = `failed`
+
+	// txWatchTimeout is the time after which the transaction without final status isn't watched
+	txWatchTimeout = time.Hour
+)
+
+// TxStatusMessage is the message about the change of the status of transaction
+// which is sent to the channel of the submitter
+type TxStatusMessage struct {
+	Type    string `json:"type"`
+	Hash    string `json:"hash"`
+	Session string `json:"session,omitempty"`
+	Status  string `json:"status"`
+	BlockID int64  `json:"block_id,omitempty"`
+	Result  string `json:"result,omitempty"`
+	Error   string `json:"error,omitempty"`
+	// RequestID is X-Request-ID of the API call which has sent the transaction
+	RequestID string `json:"request_id,omitempty"`
+}
+
+type watchedTx struct {
+	user    int64
+	session string
+	status  string
+	added   time.Time
+}
+
+var (
+	watchedTxs   = make(map[string]*watchedTx)
+	watchedTxsMu sync.Mutex
+)
+
+// txStatus returns the status of the transaction by its record in transactions_status. The expired
+// and cancelled transactions have the errors of the corresponding types
+func txStatus(ts *model.TransactionStatus, found bool) *TxStatusMessage {
+	msg := &TxStatusMessage{Type: `txstatus`, Hash: hex.EncodeToString(ts.Hash), Status: TxQueued,
+		RequestID: ts.RequestID}
+	switch {
+	case !found:
+	case ts.BlockID > 0:
+		msg.Status, msg.BlockID, msg.Result = TxInBlock, ts.BlockID, ts.Error
+	case len(ts.Error) > 0:
+		msg.Status, msg.Error = TxFailed, ts.Error
+		var errmsg struct {
+			Type  string `json:"type"`
+			Error string `json:"error"`
+		}
+		if json.Unmarshal([]byte(ts.Error), &errmsg) == nil && len(errmsg.Type) > 0 {
+			msg.Status, msg.Error = errmsg.Type, errmsg.Error
+		}
+	}
+	return msg
+}
+
+func sendTxStatus(user int64, msg *TxStatusMessage) {
+	out, err := json.Marshal(msg)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Error("marshalling transaction status")
+		return
+	}
+	if _, err = publisher.Write(user, string(out)); err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("writing transaction status")
+	}
+}
+
+// WatchTx sends the changes of the status of the transaction to the channel of the user which has submitted it.
+// The session is the identifier of the session of the submitter, it is sent with the status
+func WatchTx(hash []byte, user int64, session string) {
+	key := hex.EncodeToString(hash)
+	watchedTxsMu.Lock()
+	watchedTxs[key] = &watchedTx{user: user, session: session, status: TxQueued, added: time.Now()}
+	watchedTxsMu.Unlock()
+	sendTxStatus(user, &TxStatusMessage{Type: `txstatus`, Hash: key, Session: session, Status: TxQueued})
+}
+
+// SendTxStatuses sends the new statuses of the watched transactions, the transactions with the final status
+// are not watched any more
+func SendTxStatuses() {
+	watchedTxsMu.Lock()
+	list := make(map[string]watchedTx, len(watchedTxs))
+	for key, item := range watchedTxs {
+		list[key] = *item
+	}
+	watchedTxsMu.Unlock()
+	for key, item := range list {
+		hash, _ := hex.DecodeString(key)
+		ts := &model.TransactionStatus{}
+		found, err := ts.Get(hash)
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting transaction status")
+			continue
+		}
+		ts.Hash = hash
+		msg := txStatus(ts, found)
+		msg.Session = item.session
+		final := msg.Status != TxQueued
+		if msg.Status != item.status {
+			sendTxStatus(item.user, msg)
+		}
+		watchedTxsMu.Lock()
+		if final || time.Since(item.added) > txWatchTimeout {
+			delete(watchedTxs, key)
+		} else if cur, ok := watchedTxs[key]; ok {
+			cur.status = msg.Status
+		}
+		watchedTxsMu.Unlock()
+	}
+}
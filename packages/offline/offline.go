@@ -0,0 +1,228 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package offline builds and signs contract transactions without a connection to the node.
+// The description of the contract is fetched once from the node, after that the transaction
+// can be signed on the isolated machine and sent by any node.
+package offline
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/crypto"
+	"github.com/GenesisKernel/go-genesis/packages/script"
+	"github.com/GenesisKernel/go-genesis/packages/utils/tx"
+
+	"github.com/shopspring/decimal"
+	"gopkg.in/vmihailenco/msgpack.v2"
+)
+
+// ContractTxType is the first byte of the contract transaction
+const ContractTxType = 128
+
+// ErrMultiSign is returned for the contracts with additional signatures
+var ErrMultiSign = errors.New(`contracts with signature fields are not supported`)
+
+// Field is the parameter of the contract
+type Field struct {
+	Name string `json:"name"`
+	Type string `json:"txtype"`
+	Tags string `json:"tags"`
+}
+
+// Contract is the description of the contract which is returned by contract/{name} API
+type Contract struct {
+	ID     uint32  `json:"id"`
+	Name   string  `json:"name"`
+	Fields []Field `json:"fields"`
+}
+
+// Transaction contains the data of the contract call
+type Transaction struct {
+	Contract       *Contract
+	EcosystemID    int64
+	Time           int64
+	TokenEcosystem int64
+	MaxSum         string
+	PayOver        string
+	// MaxFee is the maximal amount of tokens paid for the transaction, empty means no limit
+	MaxFee string
+	// Nonce is the unused nonce of the key, 0 means the transaction without nonce
+	Nonce int64
+	// ExpireBlock and ExpireTime are the last block and time which the transaction is valid in, 0 means no limit
+	ExpireBlock int64
+	ExpireTime  int64
+	// Params contains the values of the parameters, arrays have several values
+	Params url.Values
+}
+
+// encodeParams returns the binary data of the parameters and the string which is appended to forsign.
+// The values are converted in the same way as the node decodes them
+func (t *Transaction) encodeParams() ([]byte, string, error) {
+	data := make([]byte, 0)
+	var forsign string
+	for _, field := range t.Contract.Fields {
+		if strings.Contains(field.Tags, `signature`) {
+			return nil, ``, ErrMultiSign
+		}
+		val := strings.TrimSpace(t.Params.Get(field.Name))
+		if strings.Contains(field.Tags, `address`) {
+			val = converter.Int64ToStr(converter.StringToAddress(val))
+		}
+		var forv interface{}
+		switch field.Type {
+		case `[]interface {}`:
+			list := t.Params[field.Name]
+			if len(list) == 1 && len(list[0]) == 0 {
+				list = nil
+			}
+			data = append(data, converter.EncodeLength(int64(len(list)))...)
+			for _, item := range list {
+				data = append(append(data, converter.EncodeLength(int64(len(item)))...), item...)
+			}
+			forv = strings.Join(list, `,`)
+		case `uint64`:
+			v, err := parseUint(val)
+			if err != nil {
+				return nil, ``, fmt.Errorf(`%s: %v`, field.Name, err)
+			}
+			converter.BinMarshal(&data, v)
+			forv = v
+		case `int64`:
+			v, err := parseInt(val)
+			if err != nil {
+				return nil, ``, fmt.Errorf(`%s: %v`, field.Name, err)
+			}
+			converter.EncodeLenInt64(&data, v)
+			forv = v
+		case `float64`:
+			v := float64(0)
+			if len(val) > 0 {
+				var err error
+				if v, err = strconv.ParseFloat(val, 64); err != nil {
+					return nil, ``, fmt.Errorf(`%s: %v`, field.Name, err)
+				}
+			}
+			converter.BinMarshal(&data, v)
+			forv = v
+		case script.Decimal:
+			v, err := decimal.NewFromString(val)
+			if err != nil {
+				return nil, ``, fmt.Errorf(`%s: %v`, field.Name, err)
+			}
+			data = append(append(data, converter.EncodeLength(int64(len(val)))...), val...)
+			forv = v
+		case `string`:
+			data = append(append(data, converter.EncodeLength(int64(len(val)))...), val...)
+			forv = val
+		case `[]uint8`:
+			v, err := hex.DecodeString(val)
+			if err != nil {
+				return nil, ``, fmt.Errorf(`%s: %v`, field.Name, err)
+			}
+			data = append(append(data, converter.EncodeLength(int64(len(v)))...), v...)
+			forv = hex.EncodeToString(v)
+		default:
+			return nil, ``, fmt.Errorf(`%s: unsupported type %s`, field.Name, field.Type)
+		}
+		if !strings.Contains(field.Tags, `image`) {
+			forsign += fmt.Sprintf(`,%v`, forv)
+		}
+	}
+	return data, forsign, nil
+}
+
+func parseInt(val string) (int64, error) {
+	if len(val) == 0 {
+		return 0, nil
+	}
+	return strconv.ParseInt(val, 10, 64)
+}
+
+func parseUint(val string) (uint64, error) {
+	if len(val) == 0 {
+		return 0, nil
+	}
+	return strconv.ParseUint(val, 10, 64)
+}
+
+func (t *Transaction) smartContract(keyID int64) tx.SmartContract {
+	return tx.SmartContract{
+		Header: tx.Header{Type: int(t.Contract.ID), Time: t.Time, EcosystemID: t.EcosystemID,
+			KeyID: keyID, Nonce: t.Nonce, ExpireBlock: t.ExpireBlock, ExpireTime: t.ExpireTime},
+		TokenEcosystem: t.TokenEcosystem,
+		MaxSum:         t.MaxSum,
+		PayOver:        t.PayOver,
+		MaxFee:         t.MaxFee,
+	}
+}
+
+// ForSign returns the string which is signed by the key
+func (t *Transaction) ForSign(keyID int64) (string, error) {
+	_, forsign, err := t.encodeParams()
+	if err != nil {
+		return ``, err
+	}
+	return t.smartContract(keyID).ForSign() + forsign, nil
+}
+
+// Sign returns the binary transaction signed by the private key in hex.
+// The result can be sent to the network by sendtx API
+func (t *Transaction) Sign(privateKey string) ([]byte, error) {
+	private, err := hex.DecodeString(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	public, err := crypto.PrivateToPublic(private)
+	if err != nil {
+		return nil, err
+	}
+	data, forsign, err := t.encodeParams()
+	if err != nil {
+		return nil, err
+	}
+	smartTx := t.smartContract(crypto.Address(public))
+	signature, err := crypto.Sign(privateKey, smartTx.ForSign()+forsign)
+	if err != nil {
+		return nil, err
+	}
+	// the public key is ignored by the node if the key has been already registered
+	smartTx.PublicKey = public
+	smartTx.BinSignatures = converter.EncodeLengthPlusData(signature)
+	smartTx.Data = data
+	serialized, err := msgpack.Marshal(smartTx)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{ContractTxType}, serialized...), nil
+}
+
+// Hash returns the hash of the transaction which is used by txstatus API
+func Hash(blob []byte) ([]byte, error) {
+	return crypto.Hash(blob)
+}
@@ -0,0 +1,114 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package offline
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/crypto"
+	"github.com/GenesisKernel/go-genesis/packages/utils/tx"
+
+	"gopkg.in/vmihailenco/msgpack.v2"
+)
+
+func TestSign(t *testing.T) {
+	priv, pub, err := crypto.GenHexKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	trans := &Transaction{
+		Contract: &Contract{ID: 35, Name: `@1Test`, Fields: []Field{
+			{Name: `Name`, Type: `string`},
+			{Name: `Count`, Type: `int64`},
+			{Name: `Amount`, Type: `decimal.Decimal`},
+			{Name: `Data`, Type: `[]uint8`},
+			{Name: `Image`, Type: `string`, Tags: `image optional`},
+			{Name: `List`, Type: `[]interface {}`},
+		}},
+		EcosystemID: 2,
+		Time:        1500000000,
+		Params: url.Values{`Name`: {` test `}, `Count`: {`-25`}, `Amount`: {`10.50`},
+			`Data`: {`0AFF`}, `Image`: {`img`}, `List`: {`a`, `b,c`}},
+	}
+	blob, err := trans.Sign(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if blob[0] != ContractTxType {
+		t.Fatalf(`wrong type %d`, blob[0])
+	}
+	var smartTx tx.SmartContract
+	if err = msgpack.Unmarshal(blob[1:], &smartTx); err != nil {
+		t.Fatal(err)
+	}
+	public := converter.HexToBin(pub)
+	if smartTx.KeyID != crypto.Address(public) || smartTx.Type != 35 || smartTx.EcosystemID != 2 {
+		t.Errorf(`wrong header %+v`, smartTx.Header)
+	}
+	forsign, err := trans.ForSign(smartTx.KeyID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := smartTx.ForSign() + `,test,-25,10.5,0aff,a,b,c`
+	if forsign != want {
+		t.Errorf(`forsign %s != %s`, forsign, want)
+	}
+	signature := smartTx.BinSignatures
+	length, err := converter.DecodeLength(&signature)
+	if err != nil || int(length) != len(signature) {
+		t.Fatalf(`wrong signatures %d %v`, length, err)
+	}
+	if ok, err := crypto.CheckSign(public, forsign, signature); !ok || err != nil {
+		t.Errorf(`wrong signature %v`, err)
+	}
+
+	input := smartTx.Data
+	var name string
+	converter.BinUnmarshal(&input, &name)
+	count, _ := converter.DecodeLenInt64(&input)
+	if name != `test` || count != -25 {
+		t.Errorf(`wrong data %s %d`, name, count)
+	}
+
+	trans.Nonce = 12
+	nonceForsign, err := trans.ForSign(smartTx.KeyID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nonceForsign == forsign {
+		t.Errorf(`nonce must be signed`)
+	}
+	if blob, err = trans.Sign(priv); err != nil {
+		t.Fatal(err)
+	}
+	if err = msgpack.Unmarshal(blob[1:], &smartTx); err != nil || smartTx.Nonce != 12 {
+		t.Errorf(`wrong nonce %d %v`, smartTx.Nonce, err)
+	}
+
+	trans.Contract.Fields = append(trans.Contract.Fields, Field{Name: `Sign`, Type: `string`, Tags: `signature:Test`})
+	if _, err = trans.Sign(priv); err != ErrMultiSign {
+		t.Errorf(`multisign error is expected, got %v`, err)
+	}
+}
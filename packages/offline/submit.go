@@ -0,0 +1,93 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package offline
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrExpired is returned by Node if the transaction has been dropped by the network as expired
+var ErrExpired = errors.New("transaction has expired")
+
+// Node sends the transactions to the network, usually through the API of the node
+type Node interface {
+	// SendTx sends the signed transaction and returns its hash
+	SendTx(blob []byte) ([]byte, error)
+	// WaitTx waits until the transaction is included into the block and returns the id of the block,
+	// ErrExpired is returned if the transaction has been dropped as expired
+	WaitTx(hash []byte) (int64, error)
+	// MaxBlockID returns the id of the last block
+	MaxBlockID() (int64, error)
+}
+
+// Submit signs the transaction by the private key, sends it and waits for the block.
+// The expired transaction is signed again with the current time, its expiration is moved forward
+// by the same number of blocks and seconds, and it is sent again at most attempts times.
+// The hash of the last sent transaction and the id of the block are returned
+func (t *Transaction) Submit(node Node, privateKey string, attempts int) ([]byte, int64, error) {
+	var blockTTL, timeTTL int64
+	if t.ExpireBlock > 0 {
+		maxBlockID, err := node.MaxBlockID()
+		if err != nil {
+			return nil, 0, err
+		}
+		blockTTL = t.ExpireBlock - maxBlockID
+	}
+	if t.ExpireTime > 0 {
+		timeTTL = t.ExpireTime - t.Time
+	}
+	for attempt := 0; ; attempt++ {
+		blob, err := t.Sign(privateKey)
+		if err != nil {
+			return nil, 0, err
+		}
+		hash, err := node.SendTx(blob)
+		if err != nil {
+			return nil, 0, err
+		}
+		blockID, err := node.WaitTx(hash)
+		if err != ErrExpired || attempt >= attempts {
+			return hash, blockID, err
+		}
+		if err = t.renew(node, blockTTL, timeTTL); err != nil {
+			return hash, 0, err
+		}
+	}
+}
+
+// renew sets the current time of the transaction and moves its expiration forward
+func (t *Transaction) renew(node Node, blockTTL, timeTTL int64) error {
+	t.Time = time.Now().Unix()
+	if t.ExpireTime > 0 {
+		t.ExpireTime = t.Time + timeTTL
+	}
+	if t.ExpireBlock > 0 {
+		maxBlockID, err := node.MaxBlockID()
+		if err != nil {
+			return err
+		}
+		t.ExpireBlock = maxBlockID + blockTTL
+	}
+	return nil
+}
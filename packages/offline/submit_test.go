@@ -0,0 +1,95 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package offline
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/GenesisKernel/go-genesis/packages/crypto"
+	"github.com/GenesisKernel/go-genesis/packages/utils/tx"
+
+	"gopkg.in/vmihailenco/msgpack.v2"
+)
+
+type testNode struct {
+	maxBlockID int64
+	expire     int // the number of transactions which expire
+	sent       []tx.SmartContract
+}
+
+func (n *testNode) SendTx(blob []byte) ([]byte, error) {
+	var smartTx tx.SmartContract
+	if err := msgpack.Unmarshal(blob[1:], &smartTx); err != nil {
+		return nil, err
+	}
+	n.sent = append(n.sent, smartTx)
+	return Hash(blob)
+}
+
+func (n *testNode) WaitTx(hash []byte) (int64, error) {
+	n.maxBlockID += 10
+	if len(n.sent) <= n.expire {
+		return 0, ErrExpired
+	}
+	return n.maxBlockID, nil
+}
+
+func (n *testNode) MaxBlockID() (int64, error) {
+	return n.maxBlockID, nil
+}
+
+func TestSubmit(t *testing.T) {
+	priv, _, err := crypto.GenHexKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	trans := &Transaction{
+		Contract:    &Contract{ID: 35, Name: `@1Test`, Fields: []Field{{Name: `Name`, Type: `string`}}},
+		EcosystemID: 1,
+		Time:        1500000000,
+		ExpireBlock: 105,
+		ExpireTime:  1500000060,
+		Params:      url.Values{`Name`: {`test`}},
+	}
+	node := &testNode{maxBlockID: 100, expire: 2}
+	hash, blockID, err := trans.Submit(node, priv, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(node.sent) != 3 || blockID != 130 || len(hash) == 0 {
+		t.Fatalf(`wrong submission %d %d`, len(node.sent), blockID)
+	}
+	last := node.sent[2].Header
+	if last.ExpireBlock != 125 || last.ExpireTime-last.Time != 60 || last.Time <= 1500000000 {
+		t.Errorf(`wrong renewed header %+v`, last)
+	}
+	if node.sent[0].ExpireBlock != 105 || node.sent[0].ExpireTime != 1500000060 {
+		t.Errorf(`wrong header %+v`, node.sent[0].Header)
+	}
+
+	node = &testNode{maxBlockID: 100, expire: 5}
+	if _, _, err = trans.Submit(node, priv, 1); err != ErrExpired || len(node.sent) != 2 {
+		t.Errorf(`expected %v after 2 transactions, got %v after %d`, ErrExpired, err, len(node.sent))
+	}
+}
@@ -0,0 +1,151 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package parser
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/crypto"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	// ErrTxNotPending is returned if the transaction isn't in the queue of the node
+	ErrTxNotPending = errors.New("transaction is not pending")
+	// ErrCancelSign is returned if the cancellation isn't signed by the signer of the transaction
+	ErrCancelSign = errors.New("cancellation is not signed by the signer of transaction")
+)
+
+// CancelledStatus is the error of the cancelled transaction in transactions_status,
+// txstatus API returns it as errmsg with cancelled type
+const CancelledStatus = `{"type":"cancelled","error":"transaction has been cancelled"}`
+
+// CancelMessage returns the message which is signed by the signer of the transaction to cancel it
+func CancelMessage(hash []byte) string {
+	return `CANCEL` + hex.EncodeToString(hash)
+}
+
+// pendingTxData returns the data of the transaction which is in the queue and hasn't been included into a block
+func pendingTxData(hash []byte) ([]byte, error) {
+	tx := &model.Transaction{}
+	found, err := tx.Get(hash)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting transaction")
+		return nil, err
+	}
+	if found {
+		if tx.Used != 0 {
+			return nil, ErrTxNotPending
+		}
+		return tx.Data, nil
+	}
+	qtx := &model.QueueTx{}
+	if found, err = qtx.GetByHash(nil, hash); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting transaction from queue")
+		return nil, err
+	}
+	if !found {
+		return nil, ErrTxNotPending
+	}
+	return qtx.Data, nil
+}
+
+// signerPublicKey returns the public key of the signer of the contract transaction
+func signerPublicKey(data []byte) ([]byte, error) {
+	p, err := ParseTransaction(bytes.NewBuffer(data))
+	if err != nil {
+		return nil, err
+	}
+	if p.TxSmart == nil {
+		return nil, ErrTxNotPending
+	}
+	pub, err := model.Single(`select pub from "`+converter.Int64ToStr(p.TxSmart.EcosystemID)+`_keys" where id=?`,
+		p.TxSmart.KeyID).Bytes()
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("selecting public key from keys")
+		return nil, err
+	}
+	if len(pub) == 0 && crypto.Address(p.TxSmart.PublicKey) == p.TxSmart.KeyID {
+		pub = p.TxSmart.PublicKey
+	}
+	if len(pub) == 0 {
+		return nil, ErrCancelSign
+	}
+	return pub, nil
+}
+
+// CancelTx removes the pending transaction from the queue of the node if the cancellation is signed by its signer.
+// The transaction which has already been sent to other nodes still can be included into a block by them
+func CancelTx(hash, signature []byte) error {
+	data, err := pendingTxData(hash)
+	if err != nil {
+		return err
+	}
+	pub, err := signerPublicKey(data)
+	if err != nil {
+		return err
+	}
+	verify, err := crypto.CheckSign(pub, CancelMessage(hash), signature)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("checking cancellation signature")
+		return ErrCancelSign
+	}
+	if !verify {
+		return ErrCancelSign
+	}
+	dbTx, err := model.StartTransaction()
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("starting transaction")
+		return err
+	}
+	queued, err := model.DeleteQueueTxByHash(dbTx, hash)
+	if err != nil {
+		dbTx.Rollback()
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("deleting transaction from queue")
+		return err
+	}
+	// the cancelled transaction is deleted, the recovery of interrupted blocks would return it to the queue if it was marked used
+	unused, err := model.DeleteUnusedTransaction(dbTx, hash)
+	if err != nil {
+		dbTx.Rollback()
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("deleting transaction")
+		return err
+	}
+	if queued == 0 && unused == 0 {
+		// the transaction has been taken into a block meanwhile
+		dbTx.Rollback()
+		return ErrTxNotPending
+	}
+	if err = (&model.TransactionStatus{}).SetError(dbTx, CancelledStatus, hash); err != nil {
+		dbTx.Rollback()
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("setting transaction status error")
+		return err
+	}
+	return dbTx.Commit()
+}
@@ -142,6 +142,8 @@ func GetParser(p *Parser, txType string) (ParserInterface, error) {
 	switch txType {
 	case "FirstBlock":
 		return &FirstBlockParser{p}, nil
+	case "OracleData":
+		return &OracleDataParser{p}, nil
 	}
 	log.WithFields(log.Fields{"tx_type": txType, "type": consts.UnknownObject}).Error("unknown txType")
 	return nil, fmt.Errorf("Unknown txType: %s", txType)
@@ -171,6 +173,7 @@ type Parser struct {
 	TxType           int64
 	TxCost           int64           // Maximum cost of executing contract
 	TxUsedCost       decimal.Decimal // Used cost of CPU resources
+	TxPayer          int64           // Wallet which pays for the transaction
 	TxPtr            interface{}     // Pointer to the corresponding struct in consts/struct.go
 	TxData           map[string]interface{}
 	TxSmart          *tx.SmartContract
@@ -179,6 +182,7 @@ type Parser struct {
 	txParser         ParserInterface
 	DbTransaction    *model.DbTransaction
 	SysUpdate        bool
+	AuditLog         []*model.AuditLog
 
 	SmartContract smart.SmartContract
 }
@@ -295,6 +299,11 @@ func InsertIntoBlockchain(transaction *model.DbTransaction, block *Block) error
 		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("hashing block rollback_txs")
 		return err
 	}
+	stateHash, err := model.BlockStateHash(transaction, blockRollbackTxs)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("hashing block state")
+		return err
+	}
 	b := &model.Block{
 		ID:            blockID,
 		Hash:          block.Header.Hash,
@@ -304,6 +313,7 @@ func InsertIntoBlockchain(transaction *model.DbTransaction, block *Block) error
 		NodePosition:  block.Header.NodePosition,
 		Time:          block.Header.Time,
 		RollbacksHash: rollbackTxsHash,
+		StateHash:     stateHash,
 		Tx:            int32(len(block.Parsers)),
 	}
 	err = b.Create(transaction)
@@ -508,5 +518,7 @@ func (p *Parser) CallContract(flags int) (resultContract string, err error) {
 	}
 	resultContract, err = sc.CallContract(flags)
 	p.SysUpdate = sc.SysUpdate
+	p.AuditLog = sc.AuditLog
+	p.TxPayer = sc.TxPayer
 	return
 }
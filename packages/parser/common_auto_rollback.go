@@ -66,6 +66,41 @@ func (p *Parser) deleteInsertedDBRow(tx map[string]string, where string) error {
 	return nil
 }
 
+// rollbackBatch restores the rows which have been inserted or updated by one batch query
+func (p *Parser) rollbackBatch(tx map[string]string) error {
+	logger := p.GetLogger()
+	var items []model.RollbackBatchItem
+	if err := json.Unmarshal([]byte(tx["data"]), &items); err != nil {
+		logger.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err}).Error("unmarshalling batch rollback from json")
+		return p.ErrInfo(err)
+	}
+	var inserted []string
+	for i := len(items) - 1; i >= 0; i-- {
+		if items[i].Data == nil {
+			inserted = append(inserted, converter.Int64ToStr(converter.StrToInt64(items[i].ID)))
+			continue
+		}
+		data, err := json.Marshal(items[i].Data)
+		if err != nil {
+			logger.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Error("marshalling rollback data")
+			return p.ErrInfo(err)
+		}
+		row := map[string]string{"table_name": tx["table_name"], "data": string(data)}
+		if err = p.restoreUpdatedDBRowToPreviousData(row, " WHERE id='"+items[i].ID+"'"); err != nil {
+			return err
+		}
+	}
+	if len(inserted) > 0 {
+		err := model.GetDB(p.DbTransaction).Exec(`DELETE FROM "` + tx["table_name"] + `" WHERE id IN (` +
+			strings.Join(inserted, `,`) + `)`).Error
+		if err != nil {
+			logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("deleting from table")
+			return p.ErrInfo(err)
+		}
+	}
+	return nil
+}
+
 func (p *Parser) autoRollback() error {
 	logger := p.GetLogger()
 	rollbackTx := &model.RollbackTx{}
@@ -75,6 +110,12 @@ func (p *Parser) autoRollback() error {
 		return utils.ErrInfo(err)
 	}
 	for _, tx := range txs {
+		if tx["table_id"] == model.BatchTableID {
+			if err := p.rollbackBatch(tx); err != nil {
+				return err
+			}
+			continue
+		}
 		where := " WHERE id='" + tx["table_id"] + `'`
 		if len(tx["data"]) > 0 {
 			if err := p.restoreUpdatedDBRowToPreviousData(tx, where); err != nil {
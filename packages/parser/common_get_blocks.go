@@ -29,8 +29,8 @@ import (
 	"github.com/GenesisKernel/go-genesis/packages/config/syspar"
 	"github.com/GenesisKernel/go-genesis/packages/consts"
 	"github.com/GenesisKernel/go-genesis/packages/converter"
-	"github.com/GenesisKernel/go-genesis/packages/crypto"
 	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/tcpserver"
 	"github.com/GenesisKernel/go-genesis/packages/utils"
 
 	log "github.com/sirupsen/logrus"
@@ -57,7 +57,7 @@ func GetBlocks(blockID int64, host string) error {
 		}
 
 		// load the block body from the host
-		binaryBlock, err := utils.GetBlockBody(host, blockID, consts.DATA_TYPE_BLOCK_BODY)
+		binaryBlock, err := tcpserver.GetBlockBody(host, blockID, consts.DATA_TYPE_BLOCK_BODY)
 		if err != nil {
 			return utils.ErrInfo(err)
 		}
@@ -114,6 +114,21 @@ func GetBlocks(blockID int64, host string) error {
 		return utils.ErrInfo(err)
 	}
 
+	// the state is half-applied if the replacement of our blocks fails, so it is rolled back to blockID
+	if err = markApplying(blockID + 1); err != nil {
+		return err
+	}
+	if err = playForkBlocks(blockID, blocks); err != nil {
+		if _, errRec := RecoverInterruptedBlocks(); errRec != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": errRec}).Error("recovering interrupted blocks")
+		}
+		return err
+	}
+	return nil
+}
+
+// playForkBlocks replaces our blocks after blockID with the received blocks
+func playForkBlocks(blockID int64, blocks []*Block) error {
 	// we have the slice of blocks for applying
 	// first of all we should rollback old blocks
 	block := &model.Block{}
@@ -150,8 +165,7 @@ func GetBlocks(blockID int64, host string) error {
 			block.PrevHeader.NodePosition = prevBlocks[block.Header.BlockID-1].Header.NodePosition
 		}
 
-		forSha := fmt.Sprintf("%d,%x,%s,%d,%d,%d,%d", block.Header.BlockID, block.PrevHeader.Hash, block.MrklRoot, block.Header.Time, block.Header.EcosystemID, block.Header.KeyID, block.Header.NodePosition)
-		hash, err := crypto.DoubleHash([]byte(forSha))
+		hash, err := blockHash(&block.Header, block.PrevHeader.Hash, block.MrklRoot)
 		if err != nil {
 			log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Fatal("double hashing block")
 		}
@@ -200,7 +214,17 @@ func GetBlocks(blockID int64, host string) error {
 			return err
 		}
 	}
+	if err = model.DeleteApplyMark(dbTransaction, blockID+1); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("deleting apply mark")
+		dbTransaction.Rollback()
+		return err
+	}
 
-	err = dbTransaction.Commit()
-	return err
+	if err = dbTransaction.Commit(); err != nil {
+		return err
+	}
+	for _, block := range blocks {
+		block.writeAuditLog()
+	}
+	return nil
 }
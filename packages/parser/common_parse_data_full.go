@@ -29,10 +29,13 @@ import (
 	"strings"
 	"time"
 
+	"github.com/GenesisKernel/go-genesis/packages/clock"
 	"github.com/GenesisKernel/go-genesis/packages/config/syspar"
 	"github.com/GenesisKernel/go-genesis/packages/consts"
 	"github.com/GenesisKernel/go-genesis/packages/converter"
 	"github.com/GenesisKernel/go-genesis/packages/crypto"
+	"github.com/GenesisKernel/go-genesis/packages/diagnose"
+	"github.com/GenesisKernel/go-genesis/packages/faults"
 	"github.com/GenesisKernel/go-genesis/packages/model"
 	"github.com/GenesisKernel/go-genesis/packages/script"
 	"github.com/GenesisKernel/go-genesis/packages/smart"
@@ -52,6 +55,7 @@ type Block struct {
 	BinData    []byte
 	Parsers    []*Parser
 	SysUpdate  bool
+	AuditLog   []*model.AuditLog
 }
 
 // GetLogger is returns logger
@@ -83,15 +87,37 @@ func InsertBlockWOForks(data []byte) error {
 // PlayBlockSafe is inserting block safely
 func (b *Block) PlayBlockSafe() error {
 	logger := b.GetLogger()
+	if err := markApplying(b.Header.BlockID); err != nil {
+		return err
+	}
+	// the block is rolled back by the database on errors, the mark remains only if the node crashes
+	committed := false
+	defer func() {
+		if !committed {
+			model.DeleteApplyMark(nil, b.Header.BlockID)
+		}
+	}()
 	dbTransaction, err := model.StartTransaction()
 	if err != nil {
 		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("starting db transaction")
 		return err
 	}
 
+	if err = faults.Hit(faults.PlayBlock); err != nil {
+		dbTransaction.Rollback()
+		return err
+	}
+
 	err = b.playBlock(dbTransaction)
 	if err != nil {
 		dbTransaction.Rollback()
+		b.AuditLog = nil
+		if b.SysUpdate {
+			b.SysUpdate = false
+			if errUpd := syspar.SysUpdate(nil); errUpd != nil {
+				log.WithFields(log.Fields{"type": consts.DBError, "error": errUpd}).Error("updating syspar")
+			}
+		}
 		return err
 	}
 
@@ -105,7 +131,15 @@ func (b *Block) PlayBlockSafe() error {
 		return err
 	}
 
+	if err := model.DeleteApplyMark(dbTransaction, b.Header.BlockID); err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("deleting apply mark")
+		dbTransaction.Rollback()
+		return err
+	}
+
 	dbTransaction.Commit()
+	committed = true
+	b.writeAuditLog()
 	if b.SysUpdate {
 		b.SysUpdate = false
 		if err = syspar.SysUpdate(nil); err != nil {
@@ -116,6 +150,17 @@ func (b *Block) PlayBlockSafe() error {
 	return nil
 }
 
+// writeAuditLog writes the audit records of the transactions when the block has been committed.
+// The block is already in the blockchain so the errors are only logged
+func (b *Block) writeAuditLog() {
+	for _, audit := range b.AuditLog {
+		if err := audit.Create(nil); err != nil {
+			b.GetLogger().WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("writing audit log")
+		}
+	}
+	b.AuditLog = nil
+}
+
 // ProcessBlockWherePrevFromMemory is processing block with in memory previous block
 func ProcessBlockWherePrevFromMemory(data []byte) (*Block, error) {
 	if int64(len(data)) > syspar.GetMaxBlockSize() {
@@ -167,7 +212,27 @@ func ProcessBlockWherePrevFromBlockchainTable(data []byte) (*Block, error) {
 	return block, nil
 }
 
+// badTxError is the error of the transaction which can't be parsed
+type badTxError struct {
+	p   *Parser
+	err error
+}
+
+func (e *badTxError) Error() string {
+	return fmt.Sprintf("parse transaction error(%s)", e.err)
+}
+
+// parseBlock parses the block, the transaction which can't be parsed is marked as bad
 func parseBlock(blockBuffer *bytes.Buffer) (*Block, error) {
+	block, err := decodeBlock(blockBuffer)
+	if bad, ok := err.(*badTxError); ok && bad.p != nil && bad.p.TxHash != nil {
+		bad.p.processBadTransaction(bad.p.TxHash, bad.err.Error())
+	}
+	return block, err
+}
+
+// decodeBlock parses the header and the transactions of the block without changes of the database
+func decodeBlock(blockBuffer *bytes.Buffer) (*Block, error) {
 	header, err := ParseBlockHeader(blockBuffer)
 	if err != nil {
 		return nil, err
@@ -177,7 +242,8 @@ func parseBlock(blockBuffer *bytes.Buffer) (*Block, error) {
 		"block_state_id": header.EcosystemID, "block_hash": header.Hash, "block_version": header.Version})
 	parsers := make([]*Parser, 0)
 
-	var mrklSlice [][]byte
+	hashes := newMerkleHashes()
+	defer hashes.release()
 
 	// parse transactions
 	for blockBuffer.Len() > 0 {
@@ -199,10 +265,7 @@ func parseBlock(blockBuffer *bytes.Buffer) (*Block, error) {
 		bufTransaction := bytes.NewBuffer(blockBuffer.Next(int(transactionSize)))
 		p, err := ParseTransaction(bufTransaction)
 		if err != nil {
-			if p != nil && p.TxHash != nil {
-				p.processBadTransaction(p.TxHash, err.Error())
-			}
-			return nil, fmt.Errorf("parse transaction error(%s)", err)
+			return nil, &badTxError{p: p, err: err}
 		}
 		p.BlockData = &header
 
@@ -210,24 +273,16 @@ func parseBlock(blockBuffer *bytes.Buffer) (*Block, error) {
 
 		// build merkle tree
 		if len(p.TxFullData) > 0 {
-			dSha256Hash, err := crypto.DoubleHash(p.TxFullData)
-			if err != nil {
-				logger.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("double hashing tx full data")
+			if err := hashes.add(p.TxFullData); err != nil {
 				return nil, err
 			}
-			dSha256Hash = converter.BinToHex(dSha256Hash)
-			mrklSlice = append(mrklSlice, dSha256Hash)
 		}
 	}
 
-	if len(mrklSlice) == 0 {
-		mrklSlice = append(mrklSlice, []byte("0"))
-	}
-
 	return &Block{
 		Header:   header,
 		Parsers:  parsers,
-		MrklRoot: utils.MerkleTreeRoot(mrklSlice),
+		MrklRoot: hashes.root(),
 	}, nil
 }
 
@@ -261,6 +316,14 @@ func ParseBlockHeader(binaryBlock *bytes.Buffer) (utils.BlockData, error) {
 		return utils.BlockData{}, err
 	}
 	block.NodePosition = converter.BinToDec(binaryBlock.Next(1))
+	if block.Version >= stateHashVersion {
+		size, err := converter.DecodeLengthBuf(binaryBlock)
+		if err != nil || binaryBlock.Len() < size {
+			log.WithFields(log.Fields{"type": consts.UnmarshallingError, "block_id": block.BlockID, "error": err}).Error("decoding binary state hash")
+			return utils.BlockData{}, fmt.Errorf("bad block format (state hash)")
+		}
+		block.StateHash = binaryBlock.Next(size)
+	}
 
 	if block.BlockID > 1 {
 		signSize, err := converter.DecodeLengthBuf(binaryBlock)
@@ -507,6 +570,9 @@ func parseRegularTransaction(p *Parser, buf *bytes.Buffer, txType int64) error {
 }
 
 func checkTransaction(p *Parser, checkTime int64, checkForDupTr bool) error {
+	if err := p.checkTxSize(); err != nil {
+		return err
+	}
 	err := CheckLogTx(p.TxFullData, checkForDupTr, false)
 	if err != nil {
 		return utils.ErrInfo(err)
@@ -524,6 +590,29 @@ func checkTransaction(p *Parser, checkTime int64, checkForDupTr bool) error {
 		return utils.ErrInfo(fmt.Errorf("incorrect transaction time"))
 	}
 
+	if err = p.checkTxExpire(checkTime); err != nil {
+		logger.WithFields(log.Fields{"type": consts.InvalidObject, "error": err}).Error("checking expiration")
+		return err
+	}
+
+	if p.TxSmart != nil {
+		if err = checkMaxFee(p.TxSmart.MaxFee); err != nil {
+			logger.WithFields(log.Fields{"type": consts.InvalidObject, "error": err, "value": p.TxSmart.MaxFee}).Error("checking max fee")
+			return utils.ErrInfo(err)
+		}
+	}
+
+	if p.TxSmart != nil && p.BlockData == nil {
+		blockID, err := nextBlockID()
+		if err != nil {
+			return utils.ErrInfo(err)
+		}
+		if err = checkNonce(nil, p.TxSmart.KeyID, p.TxSmart.Nonce, blockID); err != nil {
+			logger.WithFields(log.Fields{"type": consts.InvalidObject, "error": err}).Error("checking nonce")
+			return utils.ErrInfo(err)
+		}
+	}
+
 	if p.TxContract == nil {
 		if p.BlockData != nil && p.BlockData.BlockID != 1 {
 			if p.TxKeyID == 0 {
@@ -538,6 +627,14 @@ func checkTransaction(p *Parser, checkTime int64, checkForDupTr bool) error {
 
 // CheckTransaction is checking transaction
 func CheckTransaction(data []byte) (*tx.Header, error) {
+	p, err := parseAndCheckTransaction(data)
+	if err != nil {
+		return nil, err
+	}
+	return p.TxHeader, nil
+}
+
+func parseAndCheckTransaction(data []byte) (*Parser, error) {
 	trBuff := bytes.NewBuffer(data)
 	p, err := ParseTransaction(trBuff)
 	if err != nil {
@@ -549,7 +646,7 @@ func CheckTransaction(data []byte) (*tx.Header, error) {
 		return nil, err
 	}
 
-	return p.TxHeader, nil
+	return p, nil
 }
 
 func (b *Block) readPreviousBlockFromMemory() error {
@@ -590,10 +687,23 @@ func playTransaction(p *Parser) (string, error) {
 
 func (b *Block) playBlock(dbTransaction *model.DbTransaction) error {
 	logger := b.GetLogger()
+	b.AuditLog = nil
+	defer diagnose.ObserveBlockApply(time.Now())
 	if _, err := model.DeleteUsedTransactions(dbTransaction); err != nil {
 		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("delete used transactions")
 		return err
 	}
+	// the scheduled system parameters are changed before the transactions of the activation block
+	if count, err := model.ActivateSysParams(dbTransaction, b.Header.BlockID); err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("activating scheduled system parameters")
+		return err
+	} else if count > 0 {
+		if err = syspar.SysUpdate(dbTransaction); err != nil {
+			logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("updating syspar")
+			return err
+		}
+		b.SysUpdate = true
+	}
 
 	for curTx, p := range b.Parsers {
 		var msg string
@@ -605,9 +715,12 @@ func (b *Block) playBlock(dbTransaction *model.DbTransaction) error {
 			logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "tx_hash": p.TxHash}).Error("using savepoint")
 			return err
 		}
-		msg, err = playTransaction(p)
+		if err = p.useNonce(b.Header.BlockID); err == nil {
+			msg, err = playTransaction(p)
+		}
 		if err != nil {
 			// skip this transaction
+			dbTransaction.DiscardRollbacks()
 			errRoll := dbTransaction.Connection().Exec(fmt.Sprintf("ROLLBACK TO SAVEPOINT \"tx-%d\";", curTx)).Error
 			if errRoll != nil {
 				logger.WithFields(log.Fields{"type": consts.DBError, "error": errRoll, "tx_hash": p.TxHash}).Error("rolling back to previous savepoint")
@@ -618,14 +731,22 @@ func (b *Block) playBlock(dbTransaction *model.DbTransaction) error {
 				return err2
 			}
 			p.processBadTransaction(p.TxHash, err.Error())
+			if err2 = p.recordContractCall(b.Header.BlockID, err.Error()); err2 != nil {
+				return err2
+			}
 			if p.SysUpdate {
 				if err = syspar.SysUpdate(p.DbTransaction); err != nil {
 					log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("updating syspar")
 				}
 				p.SysUpdate = false
 			}
+			p.AuditLog = nil
 			continue
 		}
+		if err = dbTransaction.FlushRollbacks(); err != nil {
+			logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "tx_hash": p.TxHash}).Error("writing rollback records")
+			return err
+		}
 		err = dbTransaction.Connection().Exec(fmt.Sprintf("RELEASE SAVEPOINT \"tx-%d\";", curTx)).Error
 		if err != nil {
 			logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "tx_hash": p.TxHash}).Error("releasing savepoint")
@@ -634,6 +755,8 @@ func (b *Block) playBlock(dbTransaction *model.DbTransaction) error {
 			b.SysUpdate = true
 			p.SysUpdate = false
 		}
+		b.AuditLog = append(b.AuditLog, p.AuditLog...)
+		p.AuditLog = nil
 
 		if _, err := model.MarkTransactionUsed(p.DbTransaction, p.TxHash); err != nil {
 			logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "tx_hash": p.TxHash}).Error("marking transaction used")
@@ -642,13 +765,16 @@ func (b *Block) playBlock(dbTransaction *model.DbTransaction) error {
 
 		// update status
 		ts := &model.TransactionStatus{}
-		if err := ts.UpdateBlockMsg(p.DbTransaction, b.Header.BlockID, msg, p.TxHash); err != nil {
+		if err := ts.UpdateBlockMsg(p.DbTransaction, b.Header.BlockID, msg, p.TxPayer, p.TxHash); err != nil {
 			logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "tx_hash": p.TxHash}).Error("updating transaction status block id")
 			return err
 		}
 		if err := InsertInLogTx(p.DbTransaction, p.TxFullData, p.TxTime); err != nil {
 			return utils.ErrInfo(err)
 		}
+		if err := p.recordContractCall(b.Header.BlockID, ``); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -658,7 +784,12 @@ func (b *Block) CheckBlock() error {
 	logger := b.GetLogger()
 	// exclude blocks from future
 	if b.Header.Time > time.Now().Unix() {
-		logger.WithFields(log.Fields{"type": consts.ParameterExceeded}).Error("block time is larger than now")
+		fields := log.Fields{"type": consts.ParameterExceeded, "block_time": b.Header.Time}
+		if drift, ok := clock.Drift(); ok {
+			// the local clock or the clock of generating node could be wrong
+			fields["clock_drift"] = drift.String()
+		}
+		logger.WithFields(fields).Error("block time is larger than now")
 		return utils.ErrInfo(fmt.Errorf("incorrect block time - block.Header.Time > time.Now().Unix()"))
 	}
 	if b.PrevHeader == nil || b.PrevHeader.BlockID != b.Header.BlockID-1 {
@@ -689,6 +820,11 @@ func (b *Block) CheckBlock() error {
 		}
 	}
 
+	if maxCount := syspar.GetMaxTxCount(); maxCount > 0 && len(b.Parsers) > maxCount {
+		logger.WithFields(log.Fields{"type": consts.ParameterExceeded, "count": len(b.Parsers), "max_count": maxCount}).Error("too many transactions in block")
+		return utils.ErrInfo(fmt.Errorf("max_tx_count %d is exceeded", maxCount))
+	}
+
 	// check each transaction
 	txCounter := make(map[int64]int)
 	txHashes := make(map[string]struct{})
@@ -713,6 +849,10 @@ func (b *Block) CheckBlock() error {
 
 	}
 
+	if err := b.checkStateHash(); err != nil {
+		return err
+	}
+
 	result, err := b.CheckHash()
 	if err != nil {
 		return utils.ErrInfo(err)
@@ -741,8 +881,7 @@ func (b *Block) CheckHash() (bool, error) {
 			return false, utils.ErrInfo(fmt.Errorf("empty nodePublicKey"))
 		}
 		// check the signature
-		forSign := fmt.Sprintf("0,%d,%x,%d,%d,%d,%d,%s", b.Header.BlockID, b.PrevHeader.Hash,
-			b.Header.Time, b.Header.EcosystemID, b.Header.KeyID, b.Header.NodePosition, b.MrklRoot)
+		forSign := blockForSign(&b.Header, b.PrevHeader.Hash, b.MrklRoot)
 
 		resultCheckSign, err := utils.CheckSign([][]byte{nodePublicKey}, forSign, b.Header.Sign, true)
 		if err != nil {
@@ -779,8 +918,7 @@ func MarshallBlock(header *utils.BlockData, trData [][]byte, prevHash []byte, ke
 		}
 		mrklRoot := utils.MerkleTreeRoot(mrklArray)
 
-		forSign := fmt.Sprintf("0,%d,%x,%d,%d,%d,%d,%s",
-			header.BlockID, prevHash, header.Time, header.EcosystemID, header.KeyID, header.NodePosition, mrklRoot)
+		forSign := blockForSign(header, prevHash, mrklRoot)
 
 		var err error
 		signed, err = crypto.Sign(key, forSign)
@@ -798,6 +936,9 @@ func MarshallBlock(header *utils.BlockData, trData [][]byte, prevHash []byte, ke
 	buf.Write(converter.DecToBin(header.EcosystemID, 4))
 	buf.Write(converter.EncodeLenInt64InPlace(header.KeyID))
 	buf.Write(converter.DecToBin(header.NodePosition, 1))
+	if header.Version >= stateHashVersion {
+		buf.Write(converter.EncodeLengthPlusData(header.StateHash))
+	}
 	buf.Write(converter.EncodeLengthPlusData(signed))
 	// data
 	buf.Write(blockDataTx)
@@ -26,6 +26,7 @@ import (
 	"bytes"
 	"fmt"
 
+	"github.com/GenesisKernel/go-genesis/packages/config/syspar"
 	"github.com/GenesisKernel/go-genesis/packages/consts"
 	"github.com/GenesisKernel/go-genesis/packages/model"
 	"github.com/GenesisKernel/go-genesis/packages/smart"
@@ -120,6 +121,16 @@ func doBlockRollback(transaction *model.DbTransaction, block *Block) error {
 			return utils.ErrInfo(err)
 		}
 
+		if err = model.DeleteKeyNoncesByHash(transaction, p.TxHash); err != nil {
+			logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("deleting key nonces by hash")
+			return utils.ErrInfo(err)
+		}
+
+		if err = model.DeleteContractCallsByHash(transaction, p.TxHash); err != nil {
+			logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("deleting contract calls by hash")
+			return utils.ErrInfo(err)
+		}
+
 		ts := &model.TransactionStatus{}
 		err = ts.UpdateBlockID(transaction, 0, p.TxHash)
 		if err != nil {
@@ -140,6 +151,9 @@ func doBlockRollback(transaction *model.DbTransaction, block *Block) error {
 			if err = p.autoRollback(); err != nil {
 				return p.ErrInfo(err)
 			}
+			if err = model.RollbackSchema(transaction, p.TxHash); err != nil {
+				return p.ErrInfo(err)
+			}
 		} else {
 			MethodName := consts.TxTypes[int(p.TxType)]
 			parser, err := GetParser(p, MethodName)
@@ -157,5 +171,16 @@ func doBlockRollback(transaction *model.DbTransaction, block *Block) error {
 		}
 	}
 
+	count, err := model.DeactivateSysParams(transaction, block.Header.BlockID)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("restoring scheduled system parameters")
+		return err
+	}
+	if count > 0 {
+		if err = syspar.SysUpdate(transaction); err != nil {
+			logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("updating syspar")
+			return err
+		}
+	}
 	return nil
 }
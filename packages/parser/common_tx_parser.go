@@ -25,6 +25,7 @@ package parser
 import (
 	"errors"
 
+	"github.com/GenesisKernel/go-genesis/packages/config/syspar"
 	"github.com/GenesisKernel/go-genesis/packages/consts"
 	"github.com/GenesisKernel/go-genesis/packages/model"
 	"github.com/GenesisKernel/go-genesis/packages/utils"
@@ -38,11 +39,16 @@ func (p *Parser) TxParser(hash, binaryTx []byte, myTx bool) error {
 	logger := p.GetLogger()
 	txType, keyID := GetTxTypeAndUserID(binaryTx)
 
-	header, err := CheckTransaction(binaryTx)
+	parsed, err := parseAndCheckTransaction(binaryTx)
+	if err == ErrTxExpired {
+		// the expired transaction is dropped from the queue, it doesn't stop the parsing of others
+		return p.processBadTransaction(hash, ExpiredStatus)
+	}
 	if err != nil {
 		p.processBadTransaction(hash, err.Error())
 		return err
 	}
+	header := parsed.TxHeader
 
 	if !( /*txType > 127 ||*/ consts.IsStruct(int(txType))) {
 		if header == nil {
@@ -81,6 +87,9 @@ func (p *Parser) TxParser(hash, binaryTx []byte, myTx bool) error {
 		Counter:  counter,
 		Verified: 1,
 	}
+	if syspar.IsSystemTx(parsed.txTypeName()) {
+		newTx.HighRate = 1
+	}
 	err = newTx.Create()
 	if err != nil {
 		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("creating new transaction")
@@ -109,6 +118,9 @@ func (p *Parser) processBadTransaction(hash []byte, errText string) error {
 		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting tx by hash from queue")
 	}
 
+	if len(qtx.RequestID) > 0 {
+		logger.WithFields(log.Fields{"type": consts.InvalidObject, "tx_hash": hash, "request_id": qtx.RequestID, "error": errText}).Warning("transaction is rejected")
+	}
 	if qtx.FromGate == 0 {
 		m := &model.TransactionStatus{}
 		err = m.SetError(nil, errText, hash)
@@ -153,11 +165,13 @@ func (p *Parser) AllTxParser() error {
 		return err
 	}
 	for _, data := range all {
+		txLogger := logger.WithFields(log.Fields{"tx_hash": data.Hash, "request_id": data.RequestID})
 		err := p.TxParser(data.Hash, data.Data, false)
 		if err != nil {
+			txLogger.WithFields(log.Fields{"type": consts.ParseError, "error": err}).Error("parsing transaction")
 			return utils.ErrInfo(err)
 		}
-		logger.Debug("transaction parsed successfully")
+		txLogger.Debug("transaction parsed successfully")
 	}
 	return nil
 }
@@ -30,6 +30,7 @@ import (
 	"github.com/GenesisKernel/go-genesis/packages/converter"
 	"github.com/GenesisKernel/go-genesis/packages/crypto"
 	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/utils"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -43,10 +44,9 @@ func UpdBlockInfo(dbTransaction *model.DbTransaction, block *Block) error {
 			blockID = *conf.StartBlockID
 		}
 	}
-	forSha := fmt.Sprintf("%d,%x,%s,%d,%d,%d,%d", blockID, block.PrevHeader.Hash, block.MrklRoot,
-		block.Header.Time, block.Header.EcosystemID, block.Header.KeyID, block.Header.NodePosition)
-
-	hash, err := crypto.DoubleHash([]byte(forSha))
+	header := block.Header
+	header.BlockID = blockID
+	hash, err := blockHash(&header, block.PrevHeader.Hash, block.MrklRoot)
 	if err != nil {
 		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Fatal("double hashing block")
 	}
@@ -85,3 +85,10 @@ func UpdBlockInfo(dbTransaction *model.DbTransaction, block *Block) error {
 
 	return nil
 }
+
+// blockHash returns the hash of the block which follows the block with prevHash
+func blockHash(header *utils.BlockData, prevHash, mrklRoot []byte) ([]byte, error) {
+	forSha := fmt.Sprintf("%d,%x,%s,%d,%d,%d,%d", header.BlockID, prevHash, mrklRoot,
+		header.Time, header.EcosystemID, header.KeyID, header.NodePosition)
+	return crypto.DoubleHash([]byte(forSha))
+}
@@ -0,0 +1,64 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package parser
+
+import (
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/crypto"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// recordContractCall adds the contract invocation of the transaction to the index of the caller key
+func (p *Parser) recordContractCall(blockID int64, errText string) error {
+	if p.TxContract == nil || p.TxSmart == nil {
+		return nil
+	}
+	call := &model.ContractCall{
+		Hash:      p.TxHash,
+		KeyID:     p.TxSmart.KeyID,
+		Ecosystem: p.TxSmart.EcosystemID,
+		Contract:  p.TxContract.Name,
+		BlockID:   blockID,
+		Time:      p.TxTime,
+		Status:    model.CallSuccess,
+	}
+	if len(errText) > 0 {
+		call.Status = model.CallFailed
+		if len(errText) > 255 {
+			errText = errText[:255]
+		}
+		call.Error = errText
+	}
+	var err error
+	if call.ParamsHash, err = crypto.Hash(p.TxSmart.Data); err != nil {
+		p.GetLogger().WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("hashing contract params")
+		return err
+	}
+	if err = call.Create(p.DbTransaction); err != nil {
+		p.GetLogger().WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("recording contract call")
+		return err
+	}
+	return nil
+}
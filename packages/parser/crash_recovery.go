@@ -0,0 +1,131 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package parser
+
+import (
+	"bytes"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// recoveryBatch is the count of blocks which are read at once during the recovery
+const recoveryBatch = 1000
+
+// markApplying marks the first block which is changed by the following operation
+func markApplying(blockID int64) error {
+	if err := model.SetApplyMark(blockID); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err, "block_id": blockID}).Error("setting apply mark")
+		return err
+	}
+	return nil
+}
+
+// isRolledBack returns true if the transactions of the block aren't logged as applied,
+// the block without transactions is rolled back again because it changes nothing
+func isRolledBack(block *Block, logged func([]byte) (int64, error)) (bool, error) {
+	if len(block.Parsers) == 0 {
+		return false, nil
+	}
+	for _, p := range block.Parsers {
+		count, err := logged(p.TxHash)
+		if err != nil {
+			return false, err
+		}
+		if count > 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func recoverBlock(data []byte) error {
+	block, err := parseBlock(bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	rolledBack, err := isRolledBack(block, model.GetLogTransactionsCount)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting log transactions count")
+		return err
+	}
+	if !rolledBack {
+		return BlockRollback(data)
+	}
+	if err = (&model.Block{}).DeleteById(nil, block.Header.BlockID); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("deleting block by id")
+	}
+	return err
+}
+
+// RecoverInterruptedBlocks rolls back the blocks whose application or rollback has been interrupted
+// by crash or error. The blocks from the mark are removed from the chain by rollback records
+// and the blocks collection downloads them again from other nodes. It returns true if the state has been recovered
+func RecoverInterruptedBlocks() (bool, error) {
+	mark := &model.ApplyMark{}
+	found, err := mark.Get()
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting apply mark")
+		return false, err
+	}
+	if !found {
+		return false, nil
+	}
+	logger := log.WithFields(log.Fields{"block_id": mark.BlockID, "mark_time": mark.Time})
+	logger.Warning("recovering interrupted application of blocks")
+
+	if mark.BlockID > 1 {
+		for {
+			blocks, err := (&model.Block{}).GetBlocks(mark.BlockID-1, recoveryBatch)
+			if err != nil {
+				logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting blocks")
+				return false, err
+			}
+			if len(blocks) == 0 {
+				break
+			}
+			for _, item := range blocks {
+				if err = recoverBlock(item.Data); err != nil {
+					return false, err
+				}
+			}
+		}
+		// info_block is set to the last remaining block
+		if err = new(Parser).RollbackToBlockID(mark.BlockID - 1); err != nil {
+			return false, err
+		}
+	}
+	// the transactions which are marked used by the interrupted block return to the queue
+	if _, err = model.MarkUnloggedTransactionsUnused(nil); err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("marking unlogged transactions unused")
+		return false, err
+	}
+	if err = model.DeleteApplyMarks(nil); err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("deleting apply marks")
+		return false, err
+	}
+	logger.Warning("interrupted blocks are rolled back")
+	return true, nil
+}
@@ -0,0 +1,46 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package parser
+
+import (
+	"testing"
+)
+
+func TestIsRolledBack(t *testing.T) {
+	logged := map[string]int64{`a`: 1}
+	count := func(hash []byte) (int64, error) {
+		return logged[string(hash)], nil
+	}
+	block := &Block{}
+	if ok, _ := isRolledBack(block, count); ok {
+		t.Error(`block without transactions must be rolled back again`)
+	}
+	block.Parsers = []*Parser{{TxHash: []byte(`b`)}, {TxHash: []byte(`c`)}}
+	if ok, _ := isRolledBack(block, count); !ok {
+		t.Error(`block with unlogged transactions is rolled back`)
+	}
+	block.Parsers = append(block.Parsers, &Parser{TxHash: []byte(`a`)})
+	if ok, _ := isRolledBack(block, count); ok {
+		t.Error(`block with logged transaction isn't rolled back`)
+	}
+}
@@ -0,0 +1,90 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package parser
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/utils/tx"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrTxExpired is returned if the block can't contain the transaction because of its expiration
+var ErrTxExpired = errors.New("transaction has expired")
+
+// ExpiredStatus is the error of the expired transaction in transactions_status,
+// txstatus API returns it as errmsg with expired type
+const ExpiredStatus = `{"type":"expired","error":"transaction has expired"}`
+
+// checkExpire returns ErrTxExpired if the transaction can't be included into the block with blockID and blockTime
+func checkExpire(header *tx.Header, blockID, blockTime int64) error {
+	if header.ExpireBlock > 0 && blockID > header.ExpireBlock {
+		return ErrTxExpired
+	}
+	if header.ExpireTime > 0 && blockTime > header.ExpireTime {
+		return ErrTxExpired
+	}
+	return nil
+}
+
+// checkTxExpire checks the expiration of the contract transaction. The transaction of the queue
+// is checked against the next block
+func (p *Parser) checkTxExpire(blockTime int64) error {
+	if p.TxSmart == nil || (p.TxSmart.ExpireBlock == 0 && p.TxSmart.ExpireTime == 0) {
+		return nil
+	}
+	if p.BlockData != nil {
+		return checkExpire(&p.TxSmart.Header, p.BlockData.BlockID, blockTime)
+	}
+	blockID, err := nextBlockID()
+	if err != nil {
+		return err
+	}
+	return checkExpire(&p.TxSmart.Header, blockID, blockTime)
+}
+
+// nextBlockID returns the id of the next block which the transactions of the queue are checked against
+func nextBlockID() (int64, error) {
+	infoBlock := &model.InfoBlock{}
+	if _, err := infoBlock.Get(); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting info block")
+		return 0, err
+	}
+	return infoBlock.BlockID + 1, nil
+}
+
+// CheckTxExpire parses the binary transaction and checks its expiration for the block with blockID and blockTime
+func CheckTxExpire(data []byte, blockID, blockTime int64) error {
+	p, err := ParseTransaction(bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	if p.TxSmart == nil {
+		return nil
+	}
+	return checkExpire(&p.TxSmart.Header, blockID, blockTime)
+}
@@ -0,0 +1,49 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package parser
+
+import (
+	"testing"
+
+	"github.com/GenesisKernel/go-genesis/packages/utils/tx"
+)
+
+func TestCheckExpire(t *testing.T) {
+	cases := []struct {
+		header           tx.Header
+		blockID, blkTime int64
+		err              error
+	}{
+		{tx.Header{}, 100, 1500000000, nil},
+		{tx.Header{ExpireBlock: 100}, 100, 1500000000, nil},
+		{tx.Header{ExpireBlock: 100}, 101, 1500000000, ErrTxExpired},
+		{tx.Header{ExpireTime: 1500000000}, 101, 1500000000, nil},
+		{tx.Header{ExpireTime: 1500000000}, 101, 1500000001, ErrTxExpired},
+		{tx.Header{ExpireBlock: 200, ExpireTime: 1500000000}, 101, 1500000001, ErrTxExpired},
+	}
+	for _, c := range cases {
+		if err := checkExpire(&c.header, c.blockID, c.blkTime); err != c.err {
+			t.Errorf("%+v in block %d at %d: expected %v, got %v", c.header, c.blockID, c.blkTime, c.err, err)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package parser
+
+import (
+	"errors"
+
+	"github.com/GenesisKernel/go-genesis/packages/config/syspar"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// feeBlocks is the number of the last blocks which fullness is used to estimate the fee
+	feeBlocks = 10
+	// feeTargetLoad is the load of network above which the additional payment is recommended
+	feeTargetLoad = 0.5
+	// feeMaxSurcharge limits the recommended additional payment to the multiple of the fuel rate
+	feeMaxSurcharge = 3
+)
+
+var (
+	// ErrInvalidMaxFee is returned if max_fee of the transaction isn't a positive number
+	ErrInvalidMaxFee = errors.New("max_fee must be a positive number")
+	// ErrUnknownFuelRate is returned if the fuel rate isn't defined for the token ecosystem
+	ErrUnknownFuelRate = errors.New("fuel rate is not defined")
+)
+
+// FeeEstimate is the recommended fuel rate of the token ecosystem
+type FeeEstimate struct {
+	FuelRate decimal.Decimal
+	PayOver  decimal.Decimal
+	// Fullness is the average share of the limits used by the last blocks, from 0 to 1
+	Fullness float64
+	// Queue is the number of transactions which are waiting for a block
+	Queue int64
+}
+
+// Rate returns the recommended fuel rate including the additional payment
+func (f *FeeEstimate) Rate() decimal.Decimal {
+	return f.FuelRate.Add(f.PayOver)
+}
+
+// checkMaxFee verifies that max_fee of the transaction is empty or a positive number
+func checkMaxFee(maxFee string) error {
+	if len(maxFee) == 0 {
+		return nil
+	}
+	fee, err := decimal.NewFromString(maxFee)
+	if err != nil || fee.Sign() <= 0 {
+		return ErrInvalidMaxFee
+	}
+	return nil
+}
+
+// blockFullness returns the share of the count or size limit used by the block, whichever is greater
+func blockFullness(txCount, size int64, maxCount int, maxSize int64) float64 {
+	var ret float64
+	if maxCount > 0 {
+		ret = float64(txCount) / float64(maxCount)
+	}
+	if maxSize > 0 {
+		if bySize := float64(size) / float64(maxSize); bySize > ret {
+			ret = bySize
+		}
+	}
+	if ret > 1 {
+		ret = 1
+	}
+	return ret
+}
+
+// recommendPayOver returns the additional payment to the fuel rate for the load of network.
+// The load is the fullness of the last blocks plus the queue measured in full blocks
+func recommendPayOver(fuelRate decimal.Decimal, load float64) decimal.Decimal {
+	over := load - feeTargetLoad
+	if over <= 0 {
+		return decimal.Zero
+	}
+	if over > feeMaxSurcharge {
+		over = feeMaxSurcharge
+	}
+	return fuelRate.Mul(decimal.NewFromFloat(over)).Floor()
+}
+
+// EstimateFee returns the recommended fuel rate of the token ecosystem based on the fullness
+// of the last blocks and the number of waiting transactions
+func EstimateFee(ecosystem int64) (*FeeEstimate, error) {
+	rate := syspar.GetFuelRate(ecosystem)
+	if len(rate) == 0 {
+		return nil, ErrUnknownFuelRate
+	}
+	fuelRate, err := decimal.NewFromString(rate)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.ConversionError, "error": err, "value": rate}).Error("converting fuel rate from string to decimal")
+		return nil, err
+	}
+	blocks, err := (&model.Block{}).GetBlocks(0, feeBlocks)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting last blocks")
+		return nil, err
+	}
+	maxCount, maxSize := syspar.GetMaxTxCount(), syspar.GetMaxBlockSize()
+	ret := &FeeEstimate{FuelRate: fuelRate}
+	if len(blocks) > 0 {
+		for _, b := range blocks {
+			ret.Fullness += blockFullness(int64(b.Tx), int64(len(b.Data)), maxCount, maxSize)
+		}
+		ret.Fullness /= float64(len(blocks))
+	}
+	unused, err := model.GetUnusedTransactionsCount()
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("counting unused transactions")
+		return nil, err
+	}
+	queued, err := model.GetQueueTxCount()
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("counting queued transactions")
+		return nil, err
+	}
+	ret.Queue = unused + queued
+	load := ret.Fullness
+	if maxCount > 0 {
+		load += float64(ret.Queue) / float64(maxCount)
+	}
+	ret.PayOver = recommendPayOver(fuelRate, load)
+	return ret, nil
+}
@@ -0,0 +1,87 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package parser
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestCheckMaxFee(t *testing.T) {
+	cases := []struct {
+		maxFee string
+		err    error
+	}{
+		{``, nil},
+		{`1000`, nil},
+		{`0.5`, nil},
+		{`0`, ErrInvalidMaxFee},
+		{`-10`, ErrInvalidMaxFee},
+		{`abc`, ErrInvalidMaxFee},
+	}
+	for _, c := range cases {
+		if err := checkMaxFee(c.maxFee); err != c.err {
+			t.Errorf("%q: expected %v, got %v", c.maxFee, c.err, err)
+		}
+	}
+}
+
+func TestBlockFullness(t *testing.T) {
+	cases := []struct {
+		txCount, size int64
+		maxCount      int
+		maxSize       int64
+		want          float64
+	}{
+		{0, 0, 1000, 1000000, 0},
+		{500, 1000, 1000, 1000000, 0.5},
+		{10, 750000, 1000, 1000000, 0.75},
+		{2000, 0, 1000, 1000000, 1},
+		{10, 10, 0, 0, 0},
+	}
+	for _, c := range cases {
+		if got := blockFullness(c.txCount, c.size, c.maxCount, c.maxSize); got != c.want {
+			t.Errorf("%+v: expected %v, got %v", c, c.want, got)
+		}
+	}
+}
+
+func TestRecommendPayOver(t *testing.T) {
+	rate := decimal.New(1000, 0)
+	cases := []struct {
+		load float64
+		want int64
+	}{
+		{0, 0},
+		{0.5, 0},
+		{0.75, 250},
+		{2, 1500},
+		{10, 3000},
+	}
+	for _, c := range cases {
+		if got := recommendPayOver(rate, c.load); !got.Equal(decimal.New(c.want, 0)) {
+			t.Errorf("load %v: expected %d, got %s", c.load, c.want, got)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package parser
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/GenesisKernel/go-genesis/packages/model"
+)
+
+// BlockHeaderInfo is human readable header of block
+type BlockHeaderInfo struct {
+	BlockID      int64  `json:"block_id"`
+	Hash         string `json:"hash"`
+	Time         int64  `json:"time"`
+	Version      int    `json:"version"`
+	EcosystemID  int64  `json:"ecosystem_id"`
+	KeyID        int64  `json:"key_id"`
+	NodePosition int64  `json:"node_position"`
+	Sign         string `json:"sign"`
+	MrklRoot     string `json:"mrkl_root"`
+}
+
+// TxInfo is human readable transaction
+type TxInfo struct {
+	Hash        string                 `json:"hash"`
+	Type        int                    `json:"type"`
+	Time        int64                  `json:"time"`
+	EcosystemID int64                  `json:"ecosystem_id"`
+	KeyID       int64                  `json:"key_id"`
+	Contract    string                 `json:"contract,omitempty"`
+	PublicKey   string                 `json:"public_key,omitempty"`
+	Signature   string                 `json:"signature,omitempty"`
+	Params      map[string]interface{} `json:"params,omitempty"`
+	Struct      interface{}            `json:"struct,omitempty"`
+	Size        int                    `json:"size"`
+}
+
+// BlockInfo is human readable block
+type BlockInfo struct {
+	Header       BlockHeaderInfo `json:"header"`
+	Transactions []TxInfo        `json:"transactions"`
+}
+
+func txInfo(p *Parser) TxInfo {
+	info := TxInfo{
+		Hash:        hex.EncodeToString(p.TxHash),
+		Type:        p.dataType,
+		Time:        p.TxTime,
+		EcosystemID: p.TxEcosystemID,
+		KeyID:       p.TxKeyID,
+		Size:        len(p.TxFullData),
+	}
+	if p.TxSmart != nil {
+		info.PublicKey = hex.EncodeToString(p.TxSmart.PublicKey)
+		info.Signature = hex.EncodeToString(p.TxSmart.BinSignatures)
+		info.Params = p.TxData
+	}
+	if p.TxContract != nil {
+		info.Contract = p.TxContract.Name
+	}
+	if p.TxPtr != nil {
+		info.Struct = p.TxPtr
+	}
+	return info
+}
+
+// InspectBlock decodes the binary data of block, the contracts must be loaded to decode their parameters
+func InspectBlock(data []byte) (*BlockInfo, error) {
+	block, err := decodeBlock(bytes.NewBuffer(data))
+	if err != nil {
+		return nil, err
+	}
+	info := &BlockInfo{
+		Header: BlockHeaderInfo{
+			BlockID:      block.Header.BlockID,
+			Time:         block.Header.Time,
+			Version:      block.Header.Version,
+			EcosystemID:  block.Header.EcosystemID,
+			KeyID:        block.Header.KeyID,
+			NodePosition: block.Header.NodePosition,
+			Sign:         hex.EncodeToString(block.Header.Sign),
+			MrklRoot:     string(block.MrklRoot),
+		},
+		Transactions: make([]TxInfo, 0, len(block.Parsers)),
+	}
+	for _, p := range block.Parsers {
+		info.Transactions = append(info.Transactions, txInfo(p))
+	}
+	return info, nil
+}
+
+// InspectStoredBlock decodes the block from blockchain by its id or hex hash
+func InspectStoredBlock(id int64, hash []byte) (*BlockInfo, error) {
+	block := &model.Block{}
+	var (
+		found bool
+		err   error
+	)
+	if len(hash) > 0 {
+		found, err = block.GetByHash(hash)
+	} else {
+		found, err = block.Get(id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("block is not found")
+	}
+	info, err := InspectBlock(block.Data)
+	if err != nil {
+		return nil, err
+	}
+	info.Header.Hash = hex.EncodeToString(block.Hash)
+	return info, nil
+}
+
+// InspectTx decodes the transaction included in blockchain
+func InspectTx(hash []byte) (*TxInfo, int64, error) {
+	ts := &model.TransactionStatus{}
+	found, err := ts.Get(hash)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !found || ts.BlockID == 0 {
+		return nil, 0, fmt.Errorf("transaction is not found in blockchain")
+	}
+	block, err := InspectStoredBlock(ts.BlockID, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	hexHash := hex.EncodeToString(hash)
+	for _, tx := range block.Transactions {
+		if tx.Hash == hexHash {
+			return &tx, ts.BlockID, nil
+		}
+	}
+	return nil, 0, fmt.Errorf("transaction is not found in block %d", ts.BlockID)
+}
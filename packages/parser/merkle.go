@@ -0,0 +1,70 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package parser
+
+import (
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/crypto"
+	"github.com/GenesisKernel/go-genesis/packages/utils"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// merkleHashes collects the hex hashes of the block transactions in a pooled buffer
+type merkleHashes struct {
+	buf  *[]byte
+	list [][]byte
+}
+
+func newMerkleHashes() *merkleHashes {
+	return &merkleHashes{buf: converter.GetBytes()}
+}
+
+func (m *merkleHashes) add(data []byte) error {
+	hash, err := crypto.DoubleHash(data)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("double hashing tx full data")
+		return err
+	}
+	start := len(*m.buf)
+	*m.buf = converter.AppendHex(*m.buf, hash)
+	// the capacity is limited so that appending to the hash does not overwrite the next one
+	m.list = append(m.list, (*m.buf)[start:len(*m.buf):len(*m.buf)])
+	return nil
+}
+
+// root returns the merkle root of the collected hashes
+func (m *merkleHashes) root() []byte {
+	if len(m.list) == 0 {
+		return utils.MerkleTreeRoot([][]byte{[]byte("0")})
+	}
+	return utils.MerkleTreeRoot(m.list)
+}
+
+// release gives the buffer back to the pool
+func (m *merkleHashes) release() {
+	m.list = nil
+	converter.PutBytes(m.buf)
+	m.buf = nil
+}
@@ -0,0 +1,90 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/crypto"
+	"github.com/GenesisKernel/go-genesis/packages/utils"
+)
+
+func blockTxs(count int) [][]byte {
+	txs := make([][]byte, count)
+	for i := range txs {
+		txs[i] = []byte(fmt.Sprintf("transaction %d with some payload", i))
+	}
+	return txs
+}
+
+// sliceRoot calculates the merkle root with a new allocation for every hash
+func sliceRoot(txs [][]byte) []byte {
+	var list [][]byte
+	for _, tx := range txs {
+		hash, _ := crypto.DoubleHash(tx)
+		list = append(list, converter.BinToHex(hash))
+	}
+	if len(list) == 0 {
+		list = append(list, []byte("0"))
+	}
+	return utils.MerkleTreeRoot(list)
+}
+
+func pooledRoot(txs [][]byte) []byte {
+	hashes := newMerkleHashes()
+	defer hashes.release()
+	for _, tx := range txs {
+		hashes.add(tx)
+	}
+	return hashes.root()
+}
+
+func TestMerkleHashes(t *testing.T) {
+	for _, count := range []int{0, 1, 2, 3, 7, 100} {
+		txs := blockTxs(count)
+		if want, got := sliceRoot(txs), pooledRoot(txs); !bytes.Equal(want, got) {
+			t.Errorf("wrong merkle root for %d txs %s != %s", count, got, want)
+		}
+	}
+}
+
+func BenchmarkMerkleSlices1000(b *testing.B) {
+	txs := blockTxs(1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sliceRoot(txs)
+	}
+}
+
+func BenchmarkMerklePooled1000(b *testing.B) {
+	txs := blockTxs(1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pooledRoot(txs)
+	}
+}
@@ -0,0 +1,130 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package parser
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/GenesisKernel/go-genesis/packages/config/syspar"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// defaultNonceWindow is used if tx_nonce_window is not defined
+const defaultNonceWindow = 64
+
+var (
+	// ErrNonceRequired is returned for the transaction without nonce if tx_nonce_required is set
+	ErrNonceRequired = errors.New("transaction nonce is required")
+	// ErrNonceUsed is returned if the key has already used the nonce
+	ErrNonceUsed = errors.New("transaction nonce has already been used")
+	// ErrNonceInactive is returned for the transaction with nonce before the block tx_nonce_block
+	ErrNonceInactive = errors.New("transaction nonces are not activated")
+)
+
+// nonceWindow returns the range of accepted nonces around the greatest used nonce
+func nonceWindow() int64 {
+	if window := syspar.SysInt64(syspar.TxNonceWindow); window > 0 {
+		return window
+	}
+	return defaultNonceWindow
+}
+
+// checkNonceRange verifies that the nonce is inside the window of the greatest used nonce,
+// the nonces below it are accepted to allow the concurrent transactions of key
+func checkNonceRange(nonce, max, window int64) error {
+	if nonce <= 0 || nonce <= max-window || nonce > max+window {
+		return fmt.Errorf("transaction nonce %d is out of window, next nonce is %d", nonce, max+1)
+	}
+	return nil
+}
+
+// checkNonceActivation rejects the transaction with nonce before the activation block because
+// the nonce changes the signed data and the nodes of older versions don't accept it
+func checkNonceActivation(nonce, blockID, activation int64) error {
+	if nonce != 0 && !isActivated(blockID, activation) {
+		return ErrNonceInactive
+	}
+	return nil
+}
+
+// checkNonce verifies the nonce of the contract transaction for the block, the transaction without nonce
+// is deduplicated by its hash and time only. The nonces are checked since the block tx_nonce_block
+func checkNonce(transaction *model.DbTransaction, keyID, nonce, blockID int64) error {
+	activation := syspar.GetTxNonceBlock()
+	if err := checkNonceActivation(nonce, blockID, activation); err != nil || !isActivated(blockID, activation) {
+		return err
+	}
+	if nonce == 0 {
+		if syspar.SysInt64(syspar.TxNonceRequired) != 0 {
+			return ErrNonceRequired
+		}
+		return nil
+	}
+	max, err := model.GetMaxNonce(transaction, keyID)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting max nonce")
+		return err
+	}
+	if err = checkNonceRange(nonce, max, nonceWindow()); err != nil {
+		return err
+	}
+	used, err := model.IsNonceUsed(transaction, keyID, nonce)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("checking nonce")
+		return err
+	}
+	if used {
+		return ErrNonceUsed
+	}
+	return nil
+}
+
+// useNonce checks the nonce of the contract transaction and marks it as used by the key
+func (p *Parser) useNonce(blockID int64) error {
+	if p.TxSmart == nil {
+		return nil
+	}
+	if err := checkNonce(p.DbTransaction, p.TxSmart.KeyID, p.TxSmart.Nonce, blockID); err != nil || p.TxSmart.Nonce == 0 {
+		return err
+	}
+	nonce := &model.KeyNonce{KeyID: p.TxSmart.KeyID, Nonce: p.TxSmart.Nonce, Hash: p.TxHash, BlockID: blockID}
+	if err := nonce.Create(p.DbTransaction); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("inserting key nonce")
+		return err
+	}
+	return nil
+}
+
+// NextNonce returns the nonce expected in the next transaction of the key
+func NextNonce(keyID int64) (int64, error) {
+	max, err := model.GetMaxNonce(nil, keyID)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting max nonce")
+		return 0, err
+	}
+	return max + 1, nil
+}
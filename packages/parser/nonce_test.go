@@ -0,0 +1,67 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package parser
+
+import "testing"
+
+func TestCheckNonceRange(t *testing.T) {
+	cases := []struct {
+		nonce, max int64
+		ok         bool
+	}{
+		{1, 0, true},
+		{64, 0, true},
+		{65, 0, false},
+		{0, 0, false},
+		{-1, 0, false},
+		{90, 100, true},
+		{37, 100, true},
+		{36, 100, false},
+		{164, 100, true},
+		{165, 100, false},
+	}
+	for _, c := range cases {
+		err := checkNonceRange(c.nonce, c.max, 64)
+		if (err == nil) != c.ok {
+			t.Errorf("nonce %d with max %d: expected ok=%v, got %v", c.nonce, c.max, c.ok, err)
+		}
+	}
+}
+
+func TestCheckNonceActivation(t *testing.T) {
+	cases := []struct {
+		nonce, block, activation int64
+		err                      error
+	}{
+		{0, 10, 0, nil},
+		{1, 10, 0, ErrNonceInactive},
+		{1, 10, 11, ErrNonceInactive},
+		{1, 11, 11, nil},
+		{0, 11, 11, nil},
+	}
+	for _, c := range cases {
+		if err := checkNonceActivation(c.nonce, c.block, c.activation); err != c.err {
+			t.Errorf("nonce %d at block %d activation %d: expected %v, got %v", c.nonce, c.block, c.activation, c.err, err)
+		}
+	}
+}
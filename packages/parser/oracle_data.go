@@ -0,0 +1,199 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package parser
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/GenesisKernel/go-genesis/packages/config/syspar"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/crypto"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/utils/tx"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// MaxOracleValueSize is the maximum size of the value of oracle data feed
+const MaxOracleValueSize = 4096
+
+var (
+	oracleFeedRegexp = regexp.MustCompile(`^[\w\.\-]{1,100}$`)
+
+	// ErrOracleKey is returned if the key isn't in the list of oracle keys
+	ErrOracleKey = errors.New(`key is not an oracle`)
+	// ErrOracleFeed is returned for wrong name of the feed
+	ErrOracleFeed = errors.New(`wrong name of oracle feed`)
+	// ErrOracleValueSize is returned if the value is too large
+	ErrOracleValueSize = fmt.Errorf(`oracle value is larger than %d bytes`, MaxOracleValueSize)
+	// ErrOracleOutdated is returned if the feed already has the newer value
+	ErrOracleOutdated = errors.New(`oracle data is older than the current value`)
+	// ErrOracleSign is returned for wrong signature of oracle data
+	ErrOracleSign = errors.New(`incorrect signature of oracle data`)
+)
+
+// OracleForSign returns the string which is signed by the oracle key
+func OracleForSign(keyID int64, feed, value string, dataTime int64) string {
+	return fmt.Sprintf("%d,%s,%s,%d", keyID, feed, value, dataTime)
+}
+
+// CheckOracleData checks the feed, the oracle key and the signature of the oracle data
+func CheckOracleData(transaction *model.DbTransaction, data *consts.OracleData) error {
+	if !oracleFeedRegexp.MatchString(data.Feed) {
+		return ErrOracleFeed
+	}
+	if len(data.Value) > MaxOracleValueSize {
+		return ErrOracleValueSize
+	}
+	if !syspar.IsOracleKey(data.KeyID) {
+		return ErrOracleKey
+	}
+	key := &model.Key{}
+	key.SetTablePrefix(1)
+	found, err := key.Get(data.KeyID)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting oracle public key")
+		return err
+	}
+	if !found || len(key.PublicKey) == 0 {
+		return ErrOracleKey
+	}
+	ok, err := crypto.CheckSign(key.PublicKey, OracleForSign(data.KeyID, data.Feed, data.Value, data.DataTime), data.Sign)
+	if err != nil || !ok {
+		return ErrOracleSign
+	}
+
+	current := &model.OracleValue{}
+	found, err = current.GetByFeed(transaction, data.Feed)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting oracle value")
+		return err
+	}
+	if found && current.DataTime >= data.DataTime {
+		return ErrOracleOutdated
+	}
+	return nil
+}
+
+// OracleDataParser is parser of the transaction with oracle data
+type OracleDataParser struct {
+	*Parser
+}
+
+// Init oracle data
+func (p *OracleDataParser) Init() error {
+	return nil
+}
+
+// Validate oracle data
+func (p *OracleDataParser) Validate() error {
+	data := p.TxPtr.(*consts.OracleData)
+	if p.BlockData != nil && data.DataTime > p.BlockData.Time+consts.MAX_TX_FORW {
+		return p.ErrInfo(fmt.Errorf(`oracle data time is too big`))
+	}
+	if err := CheckOracleData(p.DbTransaction, data); err != nil {
+		return p.ErrInfo(err)
+	}
+	return nil
+}
+
+// Action writes the oracle value with the attestation
+func (p *OracleDataParser) Action() error {
+	logger := p.GetLogger()
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	data := p.TxPtr.(*consts.OracleData)
+
+	value := &model.OracleValue{}
+	found, err := value.GetByFeed(p.DbTransaction, data.Feed)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting oracle value")
+		return p.ErrInfo(err)
+	}
+	rollbackData := ``
+	if found {
+		prev, err := json.Marshal(map[string]string{
+			"value":     value.Value,
+			"data_time": converter.Int64ToStr(value.DataTime),
+			"key_id":    converter.Int64ToStr(value.KeyID),
+			"block_id":  converter.Int64ToStr(value.BlockID),
+			"source_tx": value.SourceTx,
+			"signature": value.Signature,
+		})
+		if err != nil {
+			logger.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Error("marshalling oracle rollback data")
+			return p.ErrInfo(err)
+		}
+		rollbackData = string(prev)
+	} else {
+		value.Feed = data.Feed
+		if value.ID, err = model.GetNextOracleValueID(p.DbTransaction); err != nil {
+			logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting next oracle value id")
+			return p.ErrInfo(err)
+		}
+	}
+	value.Value = data.Value
+	value.DataTime = data.DataTime
+	value.KeyID = data.KeyID
+	value.BlockID = p.BlockData.BlockID
+	value.SourceTx = hex.EncodeToString(p.TxHash)
+	value.Signature = hex.EncodeToString(data.Sign)
+	if found {
+		err = value.Update(p.DbTransaction)
+	} else {
+		err = value.Create(p.DbTransaction)
+	}
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("saving oracle value")
+		return p.ErrInfo(err)
+	}
+
+	rollbackTx := &model.RollbackTx{
+		BlockID:   p.BlockData.BlockID,
+		TxHash:    p.TxHash,
+		NameTable: value.TableName(),
+		TableID:   converter.Int64ToStr(value.ID),
+		Data:      rollbackData,
+	}
+	if err = rollbackTx.Create(p.DbTransaction); err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("creating oracle rollback tx")
+		return p.ErrInfo(err)
+	}
+	return nil
+}
+
+// Rollback restores the previous value of the feed
+func (p *OracleDataParser) Rollback() error {
+	return p.autoRollback()
+}
+
+// Header is returns oracle data header
+func (p OracleDataParser) Header() *tx.Header {
+	return nil
+}
@@ -0,0 +1,81 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package parser
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+)
+
+func TestParseOracleData(t *testing.T) {
+	data := &consts.OracleData{
+		TxHeader: consts.TxHeader{Type: consts.TxTypeOracleData, Time: 1520000000, KeyID: -42},
+		Feed:     "btc_usd",
+		Value:    "9876.54",
+		DataTime: 1519999990,
+		Sign:     []byte{1, 2, 3},
+	}
+	var txData []byte
+	if _, err := converter.BinMarshal(&txData, data); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := ParseTransaction(bytes.NewBuffer(txData))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := p.txParser.(*OracleDataParser); !ok {
+		t.Fatalf("expected oracle data parser, got %T", p.txParser)
+	}
+	if !reflect.DeepEqual(p.TxPtr, data) {
+		t.Errorf("expected %+v, got %+v", data, p.TxPtr)
+	}
+	if p.TxKeyID != data.KeyID || p.TxType != consts.TxTypeOracleData {
+		t.Errorf("wrong header %d %d", p.TxKeyID, p.TxType)
+	}
+}
+
+func TestCheckOracleDataFeed(t *testing.T) {
+	for _, feed := range []string{"", "btc usd", "a/b", string(make([]byte, 101))} {
+		if err := CheckOracleData(nil, &consts.OracleData{Feed: feed}); err != ErrOracleFeed {
+			t.Errorf("%q: expected %v, got %v", feed, ErrOracleFeed, err)
+		}
+	}
+	large := &consts.OracleData{Feed: "feed", Value: string(make([]byte, MaxOracleValueSize+1))}
+	if err := CheckOracleData(nil, large); err != ErrOracleValueSize {
+		t.Errorf("expected %v, got %v", ErrOracleValueSize, err)
+	}
+	if err := CheckOracleData(nil, &consts.OracleData{Feed: "feed"}); err != ErrOracleKey {
+		t.Errorf("expected %v, got %v", ErrOracleKey, err)
+	}
+}
+
+func TestOracleForSign(t *testing.T) {
+	if s := OracleForSign(-5, "eur_usd", "1.23", 100); s != "-5,eur_usd,1.23,100" {
+		t.Errorf("wrong string for sign %s", s)
+	}
+}
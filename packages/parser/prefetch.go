@@ -0,0 +1,65 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package parser
+
+import (
+	"bytes"
+
+	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/utils"
+)
+
+// PrefetchBlock parses the block and verifies the signatures of its transactions in advance.
+// The valid signatures are remembered by the cache of verified signatures, so the applying of
+// the block doesn't check them once more. Errors are ignored because the block is fully checked
+// when it is applied. The block isn't validated yet, so it is decoded without changes of the database
+// and the contracts are got by the lock of the virtual machine which is flushed by the applied blocks
+func PrefetchBlock(data []byte) {
+	block, err := decodeBlock(bytes.NewBuffer(data))
+	if err != nil {
+		return
+	}
+	for _, p := range block.Parsers {
+		if p.TxSmart == nil || p.TxData == nil {
+			continue
+		}
+		forsign, ok := p.TxData[`forsign`].(string)
+		if !ok || len(p.TxSmart.BinSignatures) == 0 {
+			continue
+		}
+		public := p.TxSmart.PublicKey
+		signedBy := p.TxSmart.KeyID
+		if p.TxSmart.SignedBy != 0 {
+			signedBy = p.TxSmart.SignedBy
+		}
+		wallet := &model.Key{}
+		wallet.SetTablePrefix(p.TxSmart.EcosystemID)
+		if found, err := wallet.Get(signedBy); err == nil && found && len(wallet.PublicKey) > 0 {
+			public = wallet.PublicKey
+		}
+		if len(public) == 0 || string(public) == `null` {
+			continue
+		}
+		utils.CheckSign([][]byte{public}, forsign, p.TxSmart.BinSignatures, false)
+	}
+}
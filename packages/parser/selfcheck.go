@@ -0,0 +1,223 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package parser
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Checks of the consistency of blockchain data
+const (
+	CheckChain     = "chain"
+	CheckInfoBlock = "info_block"
+	CheckRollback  = "rollback"
+)
+
+// checkBatch is the count of blocks which are read at once by the self-check
+const checkBatch = 100
+
+// ConsistencyProblem is the corruption of blockchain data found by the self-check
+type ConsistencyProblem struct {
+	Check   string `json:"check"`
+	BlockID int64  `json:"block_id"` // the first affected block
+	Message string `json:"message"`
+}
+
+func (p ConsistencyProblem) String() string {
+	return fmt.Sprintf("%s at block %d: %s", p.Check, p.BlockID, p.Message)
+}
+
+// merkleRoot returns the merkle root of the transactions of the block body without parsing them
+func merkleRoot(body *bytes.Buffer) ([]byte, error) {
+	hashes := newMerkleHashes()
+	defer hashes.release()
+	for body.Len() > 0 {
+		size, err := converter.DecodeLengthBuf(body)
+		if err != nil {
+			return nil, err
+		}
+		if size == 0 || body.Len() < size {
+			return nil, fmt.Errorf("bad block format (transaction len %d)", size)
+		}
+		if err = hashes.add(body.Next(size)); err != nil {
+			return nil, err
+		}
+	}
+	return hashes.root(), nil
+}
+
+// checkLink returns the description of the problem if the block doesn't follow prev
+func checkLink(prev, block *model.Block) (string, error) {
+	if block.ID != prev.ID+1 {
+		return fmt.Sprintf("block %d is missing", prev.ID+1), nil
+	}
+	buf := bytes.NewBuffer(block.Data)
+	header, err := ParseBlockHeader(buf)
+	if err != nil {
+		return fmt.Sprintf("header is invalid: %s", err), nil
+	}
+	if header.BlockID != block.ID {
+		return fmt.Sprintf("header has block id %d", header.BlockID), nil
+	}
+	root, err := merkleRoot(buf)
+	if err != nil {
+		return fmt.Sprintf("body is invalid: %s", err), nil
+	}
+	hash, err := blockHash(&header, prev.Hash, root)
+	if err != nil {
+		return ``, err
+	}
+	if !bytes.Equal(hash, block.Hash) {
+		return fmt.Sprintf("hash %x doesn't link to block %d", block.Hash, prev.ID), nil
+	}
+	return ``, nil
+}
+
+// checkChain verifies the hash links of the blocks after fromID up to lastID
+func checkChain(fromID, lastID int64) (*ConsistencyProblem, error) {
+	prev := &model.Block{}
+	found, err := prev.Get(fromID)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting block")
+		return nil, err
+	}
+	if !found {
+		return &ConsistencyProblem{Check: CheckChain, BlockID: fromID, Message: "block is missing"}, nil
+	}
+	for start := fromID; start < lastID; start += checkBatch {
+		blocks, err := model.GetBlockchain(start, start+checkBatch)
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting blockchain")
+			return nil, err
+		}
+		for i := range blocks {
+			msg, err := checkLink(prev, &blocks[i])
+			if err != nil {
+				return nil, err
+			}
+			if len(msg) > 0 {
+				return &ConsistencyProblem{Check: CheckChain, BlockID: prev.ID + 1, Message: msg}, nil
+			}
+			prev = &blocks[i]
+		}
+		if prev.ID < start+checkBatch && prev.ID < lastID {
+			return &ConsistencyProblem{Check: CheckChain, BlockID: prev.ID + 1, Message: "block is missing"}, nil
+		}
+	}
+	return nil, nil
+}
+
+// CheckConsistency verifies the hash links of the last depth blocks, the info block and the rollback records
+func CheckConsistency(depth int64) ([]ConsistencyProblem, error) {
+	last := &model.Block{}
+	found, err := last.GetMaxBlock()
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting max block")
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	problems := make([]ConsistencyProblem, 0)
+	fromID := last.ID
+	if depth > 0 {
+		if fromID -= depth; fromID < 1 {
+			fromID = 1
+		}
+		problem, err := checkChain(fromID, last.ID)
+		if err != nil {
+			return nil, err
+		}
+		if problem != nil {
+			problems = append(problems, *problem)
+		}
+	}
+
+	info := &model.InfoBlock{}
+	if found, err = info.Get(); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting info block")
+		return nil, err
+	}
+	switch {
+	case !found:
+		problems = append(problems, ConsistencyProblem{Check: CheckInfoBlock, BlockID: last.ID + 1, Message: "info block is missing"})
+	case info.BlockID < last.ID:
+		problems = append(problems, ConsistencyProblem{Check: CheckInfoBlock, BlockID: info.BlockID + 1,
+			Message: fmt.Sprintf("info block %d is behind the last block %d", info.BlockID, last.ID)})
+	case info.BlockID > last.ID || !bytes.Equal(info.Hash, last.Hash):
+		problems = append(problems, ConsistencyProblem{Check: CheckInfoBlock, BlockID: last.ID + 1,
+			Message: fmt.Sprintf("info block %d %x doesn't match the last block %d %x", info.BlockID, info.Hash, last.ID, last.Hash)})
+	}
+
+	count, err := model.CountIncoherentRollbackTx(fromID+1, last.ID)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("counting incoherent rollback records")
+		return nil, err
+	}
+	if count > 0 {
+		problems = append(problems, ConsistencyProblem{Check: CheckRollback, BlockID: fromID + 1,
+			Message: fmt.Sprintf("%d rollback records don't belong to the applied transactions", count)})
+	}
+	return problems, nil
+}
+
+// RepairConsistency deletes the incoherent rollback records and rolls back the blocks from the first corrupted one,
+// the blocks collection downloads them again from other nodes
+func RepairConsistency(problems []ConsistencyProblem) error {
+	var rollbackFrom int64
+	for _, problem := range problems {
+		log.WithFields(log.Fields{"check": problem.Check, "block_id": problem.BlockID}).Warning("repairing consistency")
+		switch problem.Check {
+		case CheckChain, CheckInfoBlock:
+			if rollbackFrom == 0 || problem.BlockID < rollbackFrom {
+				rollbackFrom = problem.BlockID
+			}
+		case CheckRollback:
+			last := &model.Block{}
+			if _, err := last.GetMaxBlock(); err != nil {
+				log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting max block")
+				return err
+			}
+			if _, err := model.DeleteIncoherentRollbackTx(nil, problem.BlockID, last.ID); err != nil {
+				log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("deleting incoherent rollback records")
+				return err
+			}
+		}
+	}
+	if rollbackFrom < 2 {
+		return nil
+	}
+	// the repair is the recovery of the interrupted application of blocks from the corrupted one
+	if err := markApplying(rollbackFrom); err != nil {
+		return err
+	}
+	_, err := RecoverInterruptedBlocks()
+	return err
+}
@@ -0,0 +1,63 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package parser
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/utils"
+)
+
+func TestMerkleRoot(t *testing.T) {
+	txs := [][]byte{[]byte(`first`), []byte(`second transaction`)}
+	var body []byte
+	hashes := newMerkleHashes()
+	defer hashes.release()
+	for _, tx := range txs {
+		body = append(append(body, converter.EncodeLength(int64(len(tx)))...), tx...)
+		hashes.add(tx)
+	}
+	root, err := merkleRoot(bytes.NewBuffer(body))
+	if err != nil || !bytes.Equal(root, hashes.root()) {
+		t.Errorf(`wrong merkle root %s %v`, root, err)
+	}
+	if root, err = merkleRoot(bytes.NewBuffer(nil)); err != nil || !bytes.Equal(root, utils.MerkleTreeRoot([][]byte{[]byte("0")})) {
+		t.Errorf(`wrong merkle root of empty block %s %v`, root, err)
+	}
+	if _, err = merkleRoot(bytes.NewBuffer(body[:len(body)-1])); err == nil {
+		t.Error(`expected error of truncated block`)
+	}
+}
+
+func TestBlockHash(t *testing.T) {
+	header := &utils.BlockData{BlockID: 2, Time: 100, EcosystemID: 1, KeyID: 5}
+	hash, err := blockHash(header, []byte{1}, []byte(`root`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other, _ := blockHash(header, []byte{2}, []byte(`root`)); bytes.Equal(hash, other) {
+		t.Error(`hash must depend on the previous block`)
+	}
+}
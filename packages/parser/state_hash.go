@@ -0,0 +1,112 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package parser
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/GenesisKernel/go-genesis/packages/config/syspar"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/utils"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// stateHashVersion is the first block version which has the state hash of the previous block in the header
+const stateHashVersion = 2
+
+// BlockVersion returns the version of the block header. The state hash is added to the headers
+// since the block state_hash_block, so all nodes switch to the new format at the same block
+func BlockVersion(blockID int64) int {
+	return blockVersion(blockID, syspar.GetStateHashBlock())
+}
+
+func blockVersion(blockID, activation int64) int {
+	if isActivated(blockID, activation) {
+		return consts.BLOCK_VERSION
+	}
+	return consts.BASE_BLOCK_VERSION
+}
+
+// isActivated returns true if the block is at or after the activation block, 0 means not activated
+func isActivated(blockID, activation int64) bool {
+	return activation > 0 && blockID >= activation
+}
+
+func blockForSign(header *utils.BlockData, prevHash, mrklRoot []byte) string {
+	forSign := fmt.Sprintf("0,%d,%x,%d,%d,%d,%d,%s", header.BlockID, prevHash,
+		header.Time, header.EcosystemID, header.KeyID, header.NodePosition, mrklRoot)
+	if header.Version >= stateHashVersion {
+		forSign += fmt.Sprintf(",%x", header.StateHash)
+	}
+	return forSign
+}
+
+// GetStateHash returns the state hash of the block from the blockchain
+func GetStateHash(blockID int64) ([]byte, error) {
+	block := &model.Block{}
+	if _, err := block.Get(blockID); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err, "block_id": blockID}).Error("getting block")
+		return nil, err
+	}
+	return block.StateHash, nil
+}
+
+// checkStateHash checks the version of the header and compares the state hash of the previous block
+// from the header with the local one. The comparison is skipped if the local hash is unknown,
+// e.g. the previous block was applied by an old node version
+func (b *Block) checkStateHash() error {
+	if b.Header.BlockID == 1 {
+		return nil
+	}
+	if err := checkHeaderVersion(&b.Header, syspar.GetStateHashBlock()); err != nil {
+		b.GetLogger().WithFields(log.Fields{"type": consts.InvalidObject, "version": b.Header.Version,
+			"error": err}).Error("checking version of block")
+		return utils.ErrInfo(err)
+	}
+	if b.Header.Version < stateHashVersion {
+		return nil
+	}
+	local, err := GetStateHash(b.Header.BlockID - 1)
+	if err != nil {
+		return utils.ErrInfo(err)
+	}
+	if len(local) == 0 || bytes.Equal(local, b.Header.StateHash) {
+		return nil
+	}
+	b.GetLogger().WithFields(log.Fields{"type": consts.InvalidObject, "state_hash": fmt.Sprintf("%x", b.Header.StateHash),
+		"local_state_hash": fmt.Sprintf("%x", local)}).Error("state of the previous block differs")
+	return utils.ErrInfo(fmt.Errorf("state hash of block %d does not match", b.Header.BlockID-1))
+}
+
+func checkHeaderVersion(header *utils.BlockData, activation int64) error {
+	if version := blockVersion(header.BlockID, activation); header.Version != version {
+		return fmt.Errorf("block %d has version %d instead of %d", header.BlockID, header.Version, version)
+	}
+	if header.Version >= stateHashVersion && len(header.StateHash) == 0 {
+		return fmt.Errorf("block %d has no state hash", header.BlockID)
+	}
+	return nil
+}
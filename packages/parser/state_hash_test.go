@@ -0,0 +1,79 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package parser
+
+import (
+	"testing"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/utils"
+)
+
+func TestBlockForSign(t *testing.T) {
+	header := &utils.BlockData{BlockID: 10, Time: 100, EcosystemID: 1, KeyID: 5, NodePosition: 2,
+		StateHash: []byte{0xab, 0xcd}, Version: 1}
+	if got := blockForSign(header, []byte{1}, []byte("root")); got != `0,10,01,100,1,5,2,root` {
+		t.Errorf("wrong data for sign %s", got)
+	}
+	header.Version = stateHashVersion
+	if got := blockForSign(header, []byte{1}, []byte("root")); got != `0,10,01,100,1,5,2,root,abcd` {
+		t.Errorf("wrong data for sign %s", got)
+	}
+}
+
+func TestBlockVersion(t *testing.T) {
+	for _, item := range []struct {
+		block, activation int64
+		version           int
+	}{
+		{10, 0, consts.BASE_BLOCK_VERSION},
+		{10, 11, consts.BASE_BLOCK_VERSION},
+		{11, 11, consts.BLOCK_VERSION},
+		{12, 11, consts.BLOCK_VERSION},
+	} {
+		if v := blockVersion(item.block, item.activation); v != item.version {
+			t.Errorf("block %d activation %d: wrong version %d", item.block, item.activation, v)
+		}
+	}
+}
+
+func TestCheckHeaderVersion(t *testing.T) {
+	header := &utils.BlockData{BlockID: 10, Version: consts.BASE_BLOCK_VERSION}
+	if err := checkHeaderVersion(header, 0); err != nil {
+		t.Error(err)
+	}
+	if err := checkHeaderVersion(header, 5); err == nil {
+		t.Error("old version must be rejected after the activation")
+	}
+	header.Version = consts.BLOCK_VERSION
+	if err := checkHeaderVersion(header, 0); err == nil {
+		t.Error("new version must be rejected before the activation")
+	}
+	if err := checkHeaderVersion(header, 5); err == nil {
+		t.Error("empty state hash must be rejected")
+	}
+	header.StateHash = []byte{1}
+	if err := checkHeaderVersion(header, 5); err != nil {
+		t.Error(err)
+	}
+}
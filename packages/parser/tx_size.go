@@ -0,0 +1,75 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package parser
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/GenesisKernel/go-genesis/packages/config/syspar"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// TxSizeError is returned if the transaction exceeds the size limit of its type
+type TxSizeError struct {
+	Type  string
+	Size  int64
+	Limit int64
+}
+
+func (e *TxSizeError) Error() string {
+	return fmt.Sprintf("size %d of %s transaction exceeds limit %d", e.Size, e.Type, e.Limit)
+}
+
+// CheckTxSizeByType returns TxSizeError if the size is more than max_tx_size or the limit
+// of the contract or embedded transaction in max_tx_size_by_type
+func CheckTxSizeByType(name string, size int64) error {
+	if limit := syspar.GetMaxTxSizeByType(name); limit > 0 && size > limit {
+		log.WithFields(log.Fields{"type": consts.ParameterExceeded, "tx_type": name, "size": size, "max_size": limit}).Error("transaction size exceeds max size")
+		return &TxSizeError{Type: name, Size: size, Limit: limit}
+	}
+	return nil
+}
+
+// CheckTxSize parses the binary transaction and checks its size
+func CheckTxSize(data []byte) error {
+	p, err := ParseTransaction(bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	return p.checkTxSize()
+}
+
+// txTypeName returns the name of contract or embedded transaction
+func (p *Parser) txTypeName() string {
+	if p.TxContract != nil {
+		return p.TxContract.Name
+	}
+	return consts.TxTypes[p.dataType]
+}
+
+func (p *Parser) checkTxSize() error {
+	return CheckTxSizeByType(p.txTypeName(), int64(len(p.TxFullData)))
+}
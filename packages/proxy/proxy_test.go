@@ -0,0 +1,145 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package proxy
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func listen(t *testing.T, serve func(net.Conn)) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				serve(conn)
+			}()
+		}
+	}()
+	return l.Addr().String()
+}
+
+func pipe(conn net.Conn, target string) {
+	remote, err := net.Dial("tcp", target)
+	if err != nil {
+		return
+	}
+	defer remote.Close()
+	go io.Copy(remote, conn)
+	io.Copy(conn, remote)
+}
+
+func echo(conn net.Conn) {
+	io.Copy(conn, conn)
+}
+
+// socks5 accepts user:secret and connects only to the domain name target
+func socks5(target string) func(net.Conn) {
+	return func(conn net.Conn) {
+		buf := make([]byte, 512)
+		io.ReadFull(conn, buf[:2])
+		io.ReadFull(conn, buf[:buf[1]])
+		conn.Write([]byte{5, 2})
+		io.ReadFull(conn, buf[:2])
+		ulen := int(buf[1])
+		io.ReadFull(conn, buf[:ulen+1])
+		user, plen := string(buf[:ulen]), int(buf[ulen])
+		io.ReadFull(conn, buf[:plen])
+		if user != "user" || string(buf[:plen]) != "secret" {
+			conn.Write([]byte{1, 1})
+			return
+		}
+		conn.Write([]byte{1, 0})
+		io.ReadFull(conn, buf[:5])
+		if buf[3] != 3 {
+			conn.Write([]byte{5, 8, 0, 1, 0, 0, 0, 0, 0, 0})
+			return
+		}
+		io.ReadFull(conn, buf[:int(buf[4])+2])
+		conn.Write([]byte{5, 0, 0, 1, 127, 0, 0, 1, 0, 0})
+		pipe(conn, target)
+	}
+}
+
+func httpProxy(conn net.Conn) {
+	req, err := http.ReadRequest(bufio.NewReader(conn))
+	if err != nil || req.Method != "CONNECT" {
+		return
+	}
+	conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+	pipe(conn, req.Host)
+}
+
+func check(t *testing.T, rawurl, addr string) error {
+	d, err := New(rawurl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := d.DialTimeout(addr, time.Second)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.Write([]byte("ping"))
+	buf := make([]byte, 4)
+	if _, err = io.ReadFull(conn, buf); err != nil || string(buf) != "ping" {
+		t.Errorf("wrong echo %q %v", buf, err)
+	}
+	return nil
+}
+
+func TestDialer(t *testing.T) {
+	target := listen(t, echo)
+	_, port, _ := net.SplitHostPort(target)
+
+	socks := listen(t, socks5(target))
+	if err := check(t, "socks5://user:secret@"+socks, "node.onion:"+port); err != nil {
+		t.Error(err)
+	}
+	if err := check(t, "socks5://user:wrong@"+socks, "node.onion:"+port); err != ErrAuth {
+		t.Errorf("expected auth error, got %v", err)
+	}
+	if err := check(t, "socks5h://user:secret@"+socks, target); err == nil {
+		t.Error("expected error of ip address")
+	}
+
+	if err := check(t, "http://"+listen(t, httpProxy), target); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := New("ftp://127.0.0.1:21"); err != ErrScheme {
+		t.Errorf("expected scheme error, got %v", err)
+	}
+}
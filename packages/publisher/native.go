@@ -0,0 +1,150 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package publisher
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/crypto"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// subscriberBuffer is the count of messages which are kept for the slow subscriber
+	subscriberBuffer = 16
+	// keepAliveInterval is the interval of comments which keep the idle stream open
+	keepAliveInterval = 30 * time.Second
+)
+
+// nativePublisher is the built-in publisher which delivers the messages as server-sent events
+type nativePublisher struct {
+	sync.RWMutex
+	subscribers map[string]map[chan []byte]bool
+}
+
+var native = &nativePublisher{subscribers: make(map[string]map[chan []byte]bool)}
+
+// Publish sends the message to all subscribers of the channel, the message is dropped for the full subscriber
+func (np *nativePublisher) Publish(channel string, data []byte) (bool, error) {
+	np.RLock()
+	defer np.RUnlock()
+	for ch := range np.subscribers[channel] {
+		select {
+		case ch <- data:
+		default:
+			log.WithFields(log.Fields{"type": consts.ParameterExceeded, "channel": channel}).Warning("subscriber is slow, message is dropped")
+		}
+	}
+	return true, nil
+}
+
+func (np *nativePublisher) subscribe(channel string) chan []byte {
+	ch := make(chan []byte, subscriberBuffer)
+	np.Lock()
+	defer np.Unlock()
+	if np.subscribers[channel] == nil {
+		np.subscribers[channel] = make(map[chan []byte]bool)
+	}
+	np.subscribers[channel][ch] = true
+	return ch
+}
+
+func (np *nativePublisher) unsubscribe(channel string, ch chan []byte) {
+	np.Lock()
+	defer np.Unlock()
+	delete(np.subscribers[channel], ch)
+	if len(np.subscribers[channel]) == 0 {
+		delete(np.subscribers, channel)
+	}
+}
+
+func randomSecret() string {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Fatal("generating notification secret")
+	}
+	return hex.EncodeToString(buf)
+}
+
+// checkNotifyKey checks the key which has been returned by GetHMACSign to the user
+func checkNotifyKey(user, timestamp, key string) bool {
+	sign, err := hex.DecodeString(key)
+	if err != nil {
+		return false
+	}
+	expected, err := crypto.GetHMACWithTimestamp(config.Secret, user, timestamp)
+	return err == nil && hmac.Equal(sign, expected)
+}
+
+// ServeSSE streams the notifications of the user as server-sent events if Centrifugo isn't used,
+// the user is authorized by notify_key and timestamp parameters which are returned by login
+func ServeSSE(w http.ResponseWriter, r *http.Request) {
+	if publisher != native {
+		http.Error(w, "notifications are served by Centrifugo", http.StatusNotFound)
+		return
+	}
+	user, timestamp := r.FormValue("user"), r.FormValue("timestamp")
+	userID, err := strconv.ParseInt(user, 10, 64)
+	if err != nil || !checkNotifyKey(user, timestamp, r.FormValue("notify_key")) {
+		log.WithFields(log.Fields{"type": consts.AccessDenied, "user": user}).Warning("wrong notify key")
+		http.Error(w, "notify key is not valid", http.StatusUnauthorized)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	channel := clientChannel(userID)
+	ch := native.subscribe(channel)
+	defer native.unsubscribe(channel, ch)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ticker := time.NewTicker(keepAliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case data := <-ch:
+			fmt.Fprintf(w, "event: notifications\ndata: %s\n\n", data)
+		case <-ticker.C:
+			fmt.Fprint(w, ": ping\n\n")
+		case <-r.Context().Done():
+			return
+		}
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,87 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package publisher
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+)
+
+func TestNativePublisher(t *testing.T) {
+	InitCentrifugo(conf.CentrifugoConfig{})
+	if publisher != native || len(config.Secret) == 0 {
+		t.Fatal("built-in publisher is not initialized")
+	}
+	server := httptest.NewServer(http.HandlerFunc(ServeSSE))
+	defer server.Close()
+
+	key, timestamp, err := GetHMACSign(42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.Get(server.URL + "?" + url.Values{"user": {"42"}, "timestamp": {timestamp},
+		"notify_key": {strings.Repeat("0", len(key))}}.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("wrong notify key is accepted %d", resp.StatusCode)
+	}
+
+	resp, err = http.Get(server.URL + "?" + url.Values{"user": {"42"}, "timestamp": {timestamp},
+		"notify_key": {key}}.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status %d", resp.StatusCode)
+	}
+	if ok, err := Write(43, `[]`); !ok || err != nil {
+		t.Errorf("writing to another user %v %v", ok, err)
+	}
+	if ok, err := Write(42, `[{"ecosystem":1,"role_id":2,"count":3}]`); !ok || err != nil {
+		t.Errorf("writing to user %v %v", ok, err)
+	}
+	reader := bufio.NewReader(resp.Body)
+	var lines []string
+	for len(lines) < 2 {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		if line = strings.TrimSpace(line); len(line) > 0 {
+			lines = append(lines, line)
+		}
+	}
+	if lines[0] != "event: notifications" || lines[1] != `data: [{"ecosystem":1,"role_id":2,"count":3}]` {
+		t.Errorf("unexpected event %v", lines)
+	}
+}
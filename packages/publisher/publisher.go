@@ -24,6 +24,7 @@ package publisher
 
 import (
 	"encoding/hex"
+	"errors"
 	"strconv"
 	"sync"
 	"time"
@@ -35,6 +36,8 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+var errNoPublisher = errors.New("publisher is not initialized")
+
 type ClientsChannels struct {
 	storage map[int64]string
 	sync.RWMutex
@@ -52,17 +55,46 @@ func (cn *ClientsChannels) Get(id int64) string {
 	return cn.storage[id]
 }
 
+// Publisher delivers the messages to the channels of clients
+type Publisher interface {
+	Publish(channel string, data []byte) (bool, error)
+}
+
+type centrifugoPublisher struct {
+	client *gocent.Client
+}
+
+func (cp *centrifugoPublisher) Publish(channel string, data []byte) (bool, error) {
+	return cp.client.Publish(channel, data)
+}
+
 var (
 	clientsChannels   = ClientsChannels{storage: make(map[int64]string)}
 	centrifugoTimeout = time.Second * 5
-	publisher         *gocent.Client
+	publisher         Publisher
 	config            conf.CentrifugoConfig
 )
 
-// InitCentrifugo client
+// InitCentrifugo sets Centrifugo as the publisher or the built-in server-sent events if its url is empty
 func InitCentrifugo(cfg conf.CentrifugoConfig) {
+	if len(cfg.URL) == 0 {
+		if len(cfg.Secret) == 0 {
+			cfg.Secret = config.Secret
+		}
+		if len(cfg.Secret) == 0 {
+			cfg.Secret = randomSecret()
+		}
+		config = cfg
+		publisher = native
+		return
+	}
 	config = cfg
-	publisher = gocent.NewClient(cfg.URL, cfg.Secret, centrifugoTimeout)
+	publisher = &centrifugoPublisher{client: gocent.NewClient(cfg.URL, cfg.Secret, centrifugoTimeout)}
+}
+
+// SetPublisher replaces the publisher of notifications
+func SetPublisher(pub Publisher) {
+	publisher = pub
 }
 
 func GetHMACSign(userID int64) (string, string, error) {
@@ -81,5 +113,12 @@ func GetHMACSign(userID int64) (string, string, error) {
 
 // Write is publishing data to server
 func Write(userID int64, data string) (bool, error) {
-	return publisher.Publish("client"+strconv.FormatInt(userID, 10), []byte(data))
+	if publisher == nil {
+		return false, errNoPublisher
+	}
+	return publisher.Publish(clientChannel(userID), []byte(data))
+}
+
+func clientChannel(userID int64) string {
+	return "client" + strconv.FormatInt(userID, 10)
 }
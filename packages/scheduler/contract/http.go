@@ -0,0 +1,141 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package contract
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/netguard"
+	"github.com/GenesisKernel/go-genesis/packages/scheduler"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	httpTaskTimeout     = 30 * time.Second
+	httpTaskMaxResponse = 1 << 20
+)
+
+// HTTPMethods are the methods allowed for HTTP tasks
+var HTTPMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete}
+
+// HTTPHandler requests the external URL on schedule and passes the answer
+// to the contract as Status and Response parameters, so the contract can write it to tables.
+// The URL must be in http_allowlist of the ecosystem and must not point to the private network
+type HTTPHandler struct {
+	URL       string
+	Method    string
+	Body      string
+	Contract  string
+	Ecosystem int64
+}
+
+func (hh *HTTPHandler) Run(t *scheduler.Task) {
+	logger := log.WithFields(log.Fields{"task": t.String(), "url": hh.URL, "contract": hh.Contract})
+
+	var (
+		status   int
+		response string
+	)
+	policy, err := httpTaskPolicy(ecosystemOrDefault(hh.Ecosystem))
+	if err == nil {
+		status, response, err = hh.request(policy)
+	}
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.NetworkError, "error": err}).Error("run http task")
+		response = err.Error()
+	}
+
+	params := url.Values{
+		"Status":   {converter.IntToStr(status)},
+		"Response": {response},
+	}
+	if _, err = NodeContractParams(ecosystemOrDefault(hh.Ecosystem), hh.Contract, params); err != nil {
+		logger.WithFields(log.Fields{"type": consts.ContractError, "error": err}).Error("run http task contract")
+		return
+	}
+
+	logger.WithFields(log.Fields{"status": status}).Info("run http task")
+}
+
+// httpTaskPolicy reads http_allowlist from the parameters of VDE
+func httpTaskPolicy(ecosystem int64) (*netguard.Policy, error) {
+	policy := &netguard.Policy{Timeout: httpTaskTimeout}
+	sp := &model.StateParameter{}
+	sp.SetTablePrefix(converter.Int64ToStr(ecosystem) + "_vde")
+	found, err := sp.Get(nil, netguard.AllowlistParam)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting http allowlist")
+		return nil, err
+	}
+	if found {
+		policy.Allowlist = netguard.ParseAllowlist(sp.Value)
+	}
+	return policy, nil
+}
+
+func (hh *HTTPHandler) request(policy *netguard.Policy) (int, string, error) {
+	method := strings.ToUpper(hh.Method)
+	if len(method) == 0 {
+		method = http.MethodGet
+	}
+
+	var body io.Reader
+	if len(hh.Body) > 0 {
+		body = strings.NewReader(hh.Body)
+	}
+	req, err := http.NewRequest(method, hh.URL, body)
+	if err != nil {
+		return 0, "", err
+	}
+	if err = policy.CheckURL(req.URL); err != nil {
+		return 0, "", err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := policy.Client().Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, httpTaskMaxResponse+1))
+	if err != nil {
+		return resp.StatusCode, "", err
+	}
+	if len(data) > httpTaskMaxResponse {
+		return resp.StatusCode, "", fmt.Errorf("response is larger than %d bytes", httpTaskMaxResponse)
+	}
+	return resp.StatusCode, string(data), nil
+}
@@ -0,0 +1,125 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package contract
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/netguard"
+)
+
+// localPolicy allows the requests to the local test servers
+var localPolicy = &netguard.Policy{Allowlist: []string{"127.0.0.1"}, Timeout: httpTaskTimeout, AllowPrivate: true}
+
+func TestHTTPHandlerRequest(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(r.Method + " " + string(body)))
+	}))
+	defer ts.Close()
+
+	cases := []struct {
+		handler  HTTPHandler
+		response string
+	}{
+		{HTTPHandler{URL: ts.URL}, "GET "},
+		{HTTPHandler{URL: ts.URL, Method: "post", Body: `{"a":1}`}, `POST {"a":1}`},
+	}
+	for _, v := range cases {
+		status, response, err := v.handler.request(localPolicy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status != http.StatusAccepted || response != v.response {
+			t.Errorf("expected %d %q, got %d %q", http.StatusAccepted, v.response, status, response)
+		}
+	}
+}
+
+func TestHTTPHandlerResponseLimit(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("a", httpTaskMaxResponse+1)))
+	}))
+	defer ts.Close()
+
+	h := HTTPHandler{URL: ts.URL}
+	if _, _, err := h.request(localPolicy); err == nil {
+		t.Error("expected error for too large response")
+	}
+}
+
+func TestHTTPHandlerBlocked(t *testing.T) {
+	var requested bool
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = true
+	}))
+	defer ts.Close()
+
+	h := HTTPHandler{URL: ts.URL}
+	if _, _, err := h.request(&netguard.Policy{Timeout: httpTaskTimeout, AllowPrivate: true}); err == nil {
+		t.Error("expected error for the domain out of allowlist")
+	}
+	if _, _, err := h.request(&netguard.Policy{Allowlist: []string{"*"}, Timeout: httpTaskTimeout}); err == nil ||
+		!strings.Contains(err.Error(), netguard.ErrPrivateAddress.Error()) {
+		t.Errorf("expected %v, got %v", netguard.ErrPrivateAddress, err)
+	}
+	if requested {
+		t.Error("blocked target has been requested")
+	}
+}
+
+func TestHTTPHandlerRedirectBlocked(t *testing.T) {
+	var requested bool
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = true
+	}))
+	defer target.Close()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, strings.Replace(target.URL, "127.0.0.1", "localhost", 1), http.StatusFound)
+	}))
+	defer ts.Close()
+
+	h := HTTPHandler{URL: ts.URL}
+	if _, _, err := h.request(localPolicy); err == nil {
+		t.Error("expected error for the redirect to the domain out of allowlist")
+	}
+	if requested {
+		t.Error("blocked target has been requested")
+	}
+}
+
+func TestNewTaskHandler(t *testing.T) {
+	if _, ok := NewTaskHandler(&model.Cron{Contract: "@1Test"}, 2).(*ContractHandler); !ok {
+		t.Error("expected contract handler")
+	}
+	h, ok := NewTaskHandler(&model.Cron{Contract: "@1Test", URL: "http://localhost"}, 2).(*HTTPHandler)
+	if !ok || h.Ecosystem != 2 || h.URL != "http://localhost" {
+		t.Errorf("unexpected handler %+v", h)
+	}
+}
@@ -31,6 +31,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/GenesisKernel/go-genesis/packages/conf"
@@ -61,7 +62,31 @@ type contractResult struct {
 	Result string `json:"result,omitempty"`
 }
 
+// NodeContract calls the VDE contract of the first ecosystem on behalf of the node
 func NodeContract(Name string) (result contractResult, err error) {
+	return NodeContractParams(1, Name, nil)
+}
+
+// NodeContractParams calls the VDE contract of the ecosystem on behalf of the node with the specified parameters
+func NodeContractParams(ecosystemID int64, Name string, params url.Values) (result contractResult, err error) {
+	return nodeContract(ecosystemID, Name, contractForm(params, true))
+}
+
+// NodeTxContract sends the transaction of the blockchain contract signed by the node key
+func NodeTxContract(ecosystemID int64, Name string, params url.Values) (result contractResult, err error) {
+	return nodeContract(ecosystemID, Name, contractForm(params, false))
+}
+
+func contractForm(params url.Values, vde bool) *url.Values {
+	form := url.Values{}
+	for key, values := range params {
+		form[key] = values
+	}
+	form.Set(`vde`, strconv.FormatBool(vde))
+	return &form
+}
+
+func nodeContract(ecosystemID int64, Name string, contractForm *url.Values) (result contractResult, err error) {
 	var (
 		sign                          []byte
 		ret                           authResult
@@ -90,15 +115,14 @@ func NodeContract(Name string) (result contractResult, err error) {
 		return
 	}
 	form := url.Values{"pubkey": {NodePublicKey}, "signature": {hex.EncodeToString(sign)},
-		`ecosystem`: {converter.Int64ToStr(1)}}
+		`ecosystem`: {converter.Int64ToStr(ecosystemID)}}
 	var logret authResult
 	err = sendAPIRequest(`POST`, `login`, &form, &logret, auth)
 	if err != nil {
 		return
 	}
 	auth = logret.Token
-	form = url.Values{`vde`: {`true`}}
-	err = sendAPIRequest(`POST`, `node/`+Name, &form, &result, auth)
+	err = sendAPIRequest(`POST`, `node/`+Name, contractForm, &result, auth)
 	if err != nil {
 		return
 	}
@@ -111,8 +135,8 @@ func sendAPIRequest(rtype, url string, form *url.Values, v interface{}, auth str
 	if form != nil {
 		ioform = strings.NewReader(form.Encode())
 	}
-	req, err := http.NewRequest(rtype, fmt.Sprintf(`http://%s:%d%s%s`, conf.Config.HTTP.Host,
-		conf.Config.HTTP.Port, consts.ApiPath, url), ioform)
+	req, err := http.NewRequest(rtype, fmt.Sprintf(`http://%s%s%s`, conf.Config.HTTP.Str(),
+		consts.ApiPath, url), ioform)
 	if err != nil {
 		log.WithFields(log.Fields{"type": consts.NetworkError, "error": err}).Error("new api request")
 		return err
@@ -24,17 +24,20 @@ package contract
 
 import (
 	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/model"
 	"github.com/GenesisKernel/go-genesis/packages/scheduler"
 
 	log "github.com/sirupsen/logrus"
 )
 
+// ContractHandler calls the contract on schedule
 type ContractHandler struct {
-	Contract string
+	Contract  string
+	Ecosystem int64
 }
 
 func (ch *ContractHandler) Run(t *scheduler.Task) {
-	_, err := NodeContract(ch.Contract)
+	_, err := NodeContractParams(ecosystemOrDefault(ch.Ecosystem), ch.Contract, nil)
 	if err != nil {
 		log.WithFields(log.Fields{"type": consts.ContractError, "error": err, "task": t.String(), "contract": ch.Contract}).Error("run contract task")
 		return
@@ -42,3 +45,27 @@ func (ch *ContractHandler) Run(t *scheduler.Task) {
 
 	log.WithFields(log.Fields{"task": t.String(), "contract": ch.Contract}).Info("run contract task")
 }
+
+// NewTaskHandler returns the handler of the cron task of the ecosystem
+func NewTaskHandler(cronTask *model.Cron, ecosystem int64) scheduler.Handler {
+	if cronTask.IsHTTP() {
+		return &HTTPHandler{
+			URL:       cronTask.URL,
+			Method:    cronTask.Method,
+			Body:      cronTask.Body,
+			Contract:  cronTask.Contract,
+			Ecosystem: ecosystem,
+		}
+	}
+	return &ContractHandler{
+		Contract:  cronTask.Contract,
+		Ecosystem: ecosystem,
+	}
+}
+
+func ecosystemOrDefault(ecosystem int64) int64 {
+	if ecosystem == 0 {
+		return 1
+	}
+	return ecosystem
+}
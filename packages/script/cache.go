@@ -0,0 +1,131 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package script
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// maxCompileCache is the maximum count of the compiled blocks kept by the virtual machine
+var maxCompileCache int64 = 512
+
+// CacheStats contains the counters of the compilation cache
+type CacheStats struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+}
+
+var compileHits, compileMisses int64
+
+// compileCache keeps the compiled blocks which have not been loaded into the virtual machine yet.
+// The blocks are bound to the generation of the virtual machine, so any FlushBlock invalidates them
+type compileCache struct {
+	mutex      sync.Mutex
+	generation uint64
+	items      map[string]*Block
+	keys       []string
+}
+
+func newCompileCache() *compileCache {
+	return &compileCache{items: make(map[string]*Block)}
+}
+
+func compileKey(input []rune, owner *OwnerInfo, extern bool) string {
+	return fmt.Sprintf(`%x:%d:%d:%d:%t`, sha256.Sum256([]byte(string(input))), owner.StateID,
+		owner.WalletID, owner.TokenID, extern)
+}
+
+func (c *compileCache) get(key string) (*Block, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	block, ok := c.items[key]
+	return block, ok
+}
+
+func (c *compileCache) set(key string, generation uint64, block *Block) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if generation != c.generation {
+		return
+	}
+	if _, ok := c.items[key]; ok {
+		return
+	}
+	for len(c.keys) > 0 && int64(len(c.keys)) >= atomic.LoadInt64(&maxCompileCache) {
+		delete(c.items, c.keys[0])
+		c.keys = c.keys[1:]
+	}
+	c.items[key] = block
+	c.keys = append(c.keys, key)
+}
+
+// invalidate removes all blocks because the virtual machine has been changed
+func (c *compileCache) invalidate() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.generation++
+	c.items = make(map[string]*Block)
+	c.keys = c.keys[:0]
+}
+
+func (c *compileCache) current() uint64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.generation
+}
+
+// CompileBlockCached returns the compiled block of the source code. It compiles the source only if
+// the same code with the same owner has not been compiled since the last change of the virtual machine
+func (vm *VM) CompileBlockCached(input []rune, owner *OwnerInfo) (*Block, error) {
+	key := compileKey(input, owner, vm.Extern)
+	if block, ok := vm.cache.get(key); ok {
+		atomic.AddInt64(&compileHits, 1)
+		return block, nil
+	}
+	atomic.AddInt64(&compileMisses, 1)
+	generation := vm.cache.current()
+	block, err := vm.CompileBlock(input, owner)
+	if err != nil {
+		return nil, err
+	}
+	vm.cache.set(key, generation, block)
+	return block, nil
+}
+
+// SetCompileCacheSize changes the maximum count of the compiled blocks kept by each virtual machine
+func SetCompileCacheSize(limit int) {
+	if limit > 0 {
+		atomic.StoreInt64(&maxCompileCache, int64(limit))
+	}
+}
+
+// GetCacheStats returns the counters of the compilation cache of all virtual machines
+func GetCacheStats() CacheStats {
+	return CacheStats{
+		Hits:   atomic.LoadInt64(&compileHits),
+		Misses: atomic.LoadInt64(&compileMisses),
+	}
+}
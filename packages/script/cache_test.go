@@ -0,0 +1,63 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package script
+
+import "testing"
+
+func TestCompileCache(t *testing.T) {
+	vm := NewVM()
+	source := []rune(`func cached(a int) int { return a + 1 }`)
+	owner := &OwnerInfo{StateID: 1}
+	before := GetCacheStats()
+
+	first, err := vm.CompileBlockCached(source, owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := vm.CompileBlockCached(source, owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second {
+		t.Error("the cached block must be returned")
+	}
+	if other, _ := vm.CompileBlockCached(source, &OwnerInfo{StateID: 2}); other == first {
+		t.Error("the block of another owner must be compiled")
+	}
+	stats := GetCacheStats()
+	if stats.Hits-before.Hits != 1 || stats.Misses-before.Misses != 2 {
+		t.Errorf("wrong stats %+v %+v", before, stats)
+	}
+
+	vm.FlushBlock(first)
+	third, err := vm.CompileBlockCached(source, owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if third == first {
+		t.Error("the cache must be invalidated by FlushBlock")
+	}
+	if _, err := vm.CompileBlockCached([]rune(`contract {`), owner); err == nil {
+		t.Error("wrong source must not be compiled")
+	}
+}
@@ -26,6 +26,7 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
 
 	"github.com/GenesisKernel/go-genesis/packages/consts"
 
@@ -763,6 +764,9 @@ func (vm *VM) CompileBlock(input []rune, owner *OwnerInfo) (*Block, error) {
 
 // FlushBlock loads the compiled Block into the virtual machine
 func (vm *VM) FlushBlock(root *Block) {
+	vm.childMutex.Lock()
+	defer vm.childMutex.Unlock()
+	vm.cache.invalidate()
 	shift := len(vm.Children)
 	for key, item := range root.Objects {
 		if cur, ok := vm.Objects[key]; ok {
@@ -801,6 +805,17 @@ func (vm *VM) FlushBlock(root *Block) {
 	}
 }
 
+// Child returns the child of the virtual machine by its index or nil if it doesn't exist.
+// It can be called while the other goroutine flushes the block
+func (vm *VM) Child(id int) *Block {
+	vm.childMutex.RLock()
+	defer vm.childMutex.RUnlock()
+	if id < 0 || id >= len(vm.Children) {
+		return nil
+	}
+	return vm.Children[id]
+}
+
 // FlushExtern switches off the extern mode of the compilation
 func (vm *VM) FlushExtern() {
 	vm.Extern = false
@@ -962,7 +977,7 @@ main:
 								wantlen--
 							}
 						}
-						if count != wantlen && (!extinfo.Variadic || count < wantlen) {
+						if count != wantlen && (!extinfo.Variadic || count < wantlen-1) {
 							errtext = fmt.Sprintf(eWrongParams, extinfo.Name, wantlen)
 							logger.WithFields(log.Fields{"error": errtext, "type": consts.ParseError}).Error(errtext)
 							return fmt.Errorf(errtext)
@@ -1176,3 +1191,72 @@ func ContractsList(value string) []string {
 	}
 	return names
 }
+
+// lexemOffset returns the offset of the lexem in the source by the offsets of lines.
+// The lexer counts the columns of the first line from 1 and of the next lines from 2
+func lexemOffset(lines []int, lexem *Lexem) int {
+	if lexem.Line == 1 {
+		return int(lexem.Column) - 1
+	}
+	return lines[lexem.Line-1] + int(lexem.Column) - 2
+}
+
+// ContractConditions returns the source of conditions block of the contract without braces,
+// the empty string is returned if the contract doesn't have conditions
+func ContractConditions(value, name string) string {
+	input := []rune(value)
+	lexems, err := lexParser(input)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.ParseError, "error": err}).Error("getting contract conditions")
+		return ``
+	}
+	lines := []int{0}
+	for i, ch := range input {
+		if ch == 0x0a {
+			lines = append(lines, i+1)
+		}
+	}
+	var (
+		level, start int
+		inContract   bool
+	)
+	for i, lexem := range lexems {
+		switch lexem.Type {
+		case isLCurly:
+			level++
+		case isRCurly:
+			level--
+			if start > 0 && level == 1 {
+				return strings.TrimSpace(string(input[start:lexemOffset(lines, lexem)]))
+			}
+			if level == 0 {
+				inContract = false
+			}
+		case lexKeyword | (keyContract << 8):
+			if level == 0 && i+1 < len(lexems) && lexems[i+1].Type == lexIdent {
+				inContract = lexems[i+1].Value.(string) == name
+			}
+		case lexKeyword | (keyFunc << 8):
+			// the lexer turns the conditions block into the function named conditions
+			if inContract && level == 1 && i+1 < len(lexems) && lexems[i+1].Value == `conditions` {
+				for _, next := range lexems[i+1:] {
+					if next.Type == isLCurly {
+						start = lexemOffset(lines, next) + 1
+						break
+					}
+				}
+			}
+		}
+	}
+	return ``
+}
+
+// TypeName returns the name of the type of contract language
+func TypeName(t reflect.Type) string {
+	for name, typ := range types {
+		if typ == t {
+			return name
+		}
+	}
+	return t.String()
+}
@@ -387,6 +387,9 @@ func TestVMCompile(t *testing.T) {
 			}
 			return m["id"] + "=" + GetData().WhereId(100).One("name")
 		}`, `result`, `123=Test value 100`},
+		{`func notail() string {
+			return Sprintf("no tail")
+			}`, `notail`, `no tail`},
 		{`func mapbug() string {
 			$data[10] = "extend ok"
 			return $data[10]
@@ -466,3 +469,105 @@ func TestContractList(t *testing.T) {
 		}
 	}
 }
+
+func TestContractConditions(t *testing.T) {
+	source := `contract Other {
+	conditions { Other() }
+}
+contract demo_сontract {
+	data {
+		Name string "optional"
+	}
+	func conditionsCheck() {}
+	conditions {
+		if $Name == "}" {
+			error "wrong name {"
+		}
+	}
+	action {}
+}`
+	want := "if $Name == \"}\" {\n\t\t\terror \"wrong name {\"\n\t\t}"
+	if cond := ContractConditions(source, `demo_сontract`); cond != want {
+		t.Errorf("wrong conditions %q", cond)
+	}
+	if cond := ContractConditions(source, `Other`); cond != `Other()` {
+		t.Errorf("wrong conditions %q", cond)
+	}
+	if cond := ContractConditions(`contract A { action {} }`, `A`); cond != `` {
+		t.Errorf("wrong conditions %q", cond)
+	}
+}
+
+func TestCallDepth(t *testing.T) {
+	vm := NewVM()
+	vm.Extern = true
+	source := `contract depth_a {
+		action { depth_b() }
+	}
+	contract depth_b {
+		action { depth_c() }
+	}
+	contract depth_c {
+		settings { nonreentrant = 1 }
+		action { $result = "c" }
+	}
+	func calldepth() string {
+		depth_a()
+		return "OK"
+	}`
+	if err := vm.Compile([]rune(source), &OwnerInfo{StateID: 1, Active: true, TableID: 1}); err != nil {
+		t.Fatal(err)
+	}
+	run := func(extend map[string]interface{}) error {
+		extend[`rt_state`] = uint32(1)
+		_, err := vm.Call(`calldepth`, nil, &extend)
+		return err
+	}
+	for limit, fail := range map[int64]bool{0: false, 2: true, 3: false} {
+		if err := run(map[string]interface{}{`max_call_depth`: limit}); (err != nil) != fail {
+			t.Errorf(`limit %d: %v`, limit, err)
+		}
+	}
+	name := StateName(1, `depth_c`)
+	if !vm.Objects[name].Value.(*Block).Info.(*ContractInfo).NonReentrant() ||
+		vm.Objects[StateName(1, `depth_a`)].Value.(*Block).Info.(*ContractInfo).NonReentrant() {
+		t.Error(`wrong nonreentrant setting`)
+	}
+	extend := map[string]interface{}{}
+	LockContract(&extend, name)
+	if err := run(extend); err == nil || err.Error() != fmt.Sprintf(eContractLoop, name) {
+		t.Errorf(`expected loop error, got %v`, err)
+	}
+}
+
+func TestChildWhileFlush(t *testing.T) {
+	vm := NewVM()
+	owner := &OwnerInfo{StateID: 1}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			root, err := vm.CompileBlock([]rune(fmt.Sprintf(`contract Flushed%d { action { } }`, i)), owner)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			vm.FlushBlock(root)
+		}
+	}()
+	for {
+		select {
+		case <-done:
+			block := vm.Child(256)
+			if block == nil || block.Type != ObjContract || block.Info.(*ContractInfo).Name != `@1Flushed0` {
+				t.Errorf("wrong child %+v", block)
+			}
+			if vm.Child(-1) != nil || vm.Child(256+50) != nil {
+				t.Error("child out of range must be nil")
+			}
+			return
+		default:
+			vm.Child(256)
+		}
+	}
+}
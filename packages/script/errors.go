@@ -25,6 +25,7 @@ package script
 import "errors"
 
 const (
+	eCallDepth       = `maximum depth %d of contract calls is exceeded in %s contract`
 	eContractLoop    = `there is loop in %s contract`
 	eTypeParam       = `parameter %d has wrong type`
 	eUndefinedParam  = `%s is not defined`
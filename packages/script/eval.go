@@ -41,7 +41,7 @@ var (
 // CompileEval compiles conditional exppression
 func (vm *VM) CompileEval(input string, state uint32) error {
 	source := `func eval bool { return ` + input + `}`
-	block, err := vm.CompileBlock([]rune(source), &OwnerInfo{StateID: state})
+	block, err := vm.CompileBlockCached([]rune(source), &OwnerInfo{StateID: state})
 	if err == nil {
 		crc, err := crypto.CalcChecksum([]byte(input))
 		if err != nil {
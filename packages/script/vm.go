@@ -156,6 +156,8 @@ func (rt *RunTime) callFunc(cmd uint16, obj *ObjInfo) (err error) {
 		}
 		if i > 0 {
 			pars[in-1] = reflect.ValueOf(rt.stack[size-i : size])
+		} else if finfo.Variadic {
+			pars[in-1] = reflect.MakeSlice(foo.Type().In(in-1), 0, 0)
 		}
 		if finfo.Name == `ExecContract` && (pars[2].Type().String() != `string` || !pars[3].IsValid()) {
 			return fmt.Errorf(`unknown function %v`, pars[1])
@@ -28,6 +28,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/GenesisKernel/go-genesis/packages/consts"
 
@@ -104,6 +105,21 @@ type ContractInfo struct {
 	Settings map[string]interface{}
 }
 
+// NonReentrant returns true if the contract has nonreentrant setting, such contract
+// can't be called again until it finishes even if it is called by the transaction
+func (info *ContractInfo) NonReentrant() bool {
+	if info.Settings == nil {
+		return false
+	}
+	val, ok := info.Settings[`nonreentrant`]
+	return ok && valueToBool(val)
+}
+
+// LockContract marks the contract as running, so nested calls of it fail
+func LockContract(extend *map[string]interface{}, name string) {
+	(*extend)[`loop_`+name] = true
+}
+
 // FuncNameCmd for cmdFuncName
 type FuncNameCmd struct {
 	Name  string
@@ -176,6 +192,8 @@ type VM struct {
 	FuncCallsDB map[string]struct{}
 	Extern      bool // extern mode of compilation
 	logger      *log.Entry
+	cache       *compileCache
+	childMutex  sync.RWMutex // Children are read by other goroutines while the block is flushed
 }
 
 // ExtendData is used for the definition of the extended functions and variables
@@ -239,7 +257,16 @@ func ExecContract(rt *RunTime, name, txs string, params ...interface{}) (string,
 		logger.WithFields(log.Fields{"type": consts.ContractError, "contract_name": name}).Error("there is loop in contract")
 		return ``, fmt.Errorf(eContractLoop, name)
 	}
-	(*rt.extend)[`loop_`+name] = true
+	if limit, ok := (*rt.extend)[`max_call_depth`].(int64); ok && limit > 0 {
+		depth, _ := (*rt.extend)[`call_depth`].(int64)
+		if depth >= limit {
+			logger.WithFields(log.Fields{"type": consts.ContractError, "depth": depth}).Error("call depth is exceeded")
+			return ``, fmt.Errorf(eCallDepth, limit, name)
+		}
+		(*rt.extend)[`call_depth`] = depth + 1
+		defer func() { (*rt.extend)[`call_depth`] = depth }()
+	}
+	LockContract(rt.extend, name)
 	defer delete(*rt.extend, `loop_`+name)
 	for i, ipar := range pars {
 		(*rt.extend)[ipar] = params[i]
@@ -304,6 +331,7 @@ func NewVM() *VM {
 	vm.Objects = make(map[string]*ObjInfo)
 	// Reserved 256 indexes for system purposes
 	vm.Children = make(Blocks, 256, 1024)
+	vm.cache = newCompileCache()
 	vm.Extend(&ExtendData{map[string]interface{}{"ExecContract": ExecContract, "CallContract": ExContract,
 		"Settings": GetSettings},
 		map[string]string{
@@ -0,0 +1,44 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package service integrates the node with the service managers: Windows service control manager and systemd
+package service
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNotSupported is returned if the service manager is not available on the platform
+var ErrNotSupported = errors.New("service manager is not supported on this platform")
+
+// Config is the description of the node service
+type Config struct {
+	Name        string
+	DisplayName string
+	Description string
+	Executable  string   // absolute path to the binary
+	Args        []string // arguments of the binary
+	WorkDir     string
+	User        string        // systemd only, root if it's empty
+	Watchdog    time.Duration // systemd only, 0 disables the watchdog
+}
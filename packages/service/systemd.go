@@ -0,0 +1,137 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package service
+
+import (
+	"bytes"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const (
+	// NotifyReady tells systemd that the node has been started
+	NotifyReady = "READY=1"
+	// NotifyReloading tells systemd that the config is reloading
+	NotifyReloading = "RELOADING=1"
+	// NotifyStopping tells systemd that the node is stopping
+	NotifyStopping = "STOPPING=1"
+	// NotifyWatchdog resets the watchdog timer of systemd
+	NotifyWatchdog = "WATCHDOG=1"
+)
+
+var unitTemplate = template.Must(template.New("unit").Funcs(template.FuncMap{
+	"quote": quoteArg,
+}).Parse(`[Unit]
+Description={{.Description}}
+After=network-online.target postgresql.service
+Wants=network-online.target
+
+[Service]
+Type=notify
+NotifyAccess=all
+ExecStart={{quote .Executable}}{{range .Args}} {{quote .}}{{end}}
+ExecReload=/bin/kill -HUP $MAINPID
+{{- if .WorkDir}}
+WorkingDirectory={{.WorkDir}}
+{{- end}}
+{{- if .User}}
+User={{.User}}
+{{- end}}
+{{- if .Watchdog}}
+WatchdogSec={{.Watchdog.Seconds}}
+{{- end}}
+TimeoutStartSec=600
+Restart=on-failure
+RestartSec=5
+LimitNOFILE=65536
+
+[Install]
+WantedBy=multi-user.target
+`))
+
+func quoteArg(arg string) string {
+	if len(arg) > 0 && !strings.ContainsAny(arg, " \t\"'\\$%") {
+		return arg
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `$$`, `%`, `%%`)
+	return `"` + r.Replace(arg) + `"`
+}
+
+// SystemdUnit returns the systemd unit file of the service
+func SystemdUnit(cfg *Config) (string, error) {
+	var buf bytes.Buffer
+	if err := unitTemplate.Execute(&buf, cfg); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// Notify sends the state to systemd. It returns false if the node isn't started by systemd with Type=notify
+func Notify(state string) (bool, error) {
+	addr := os.Getenv("NOTIFY_SOCKET")
+	if len(addr) == 0 {
+		return false, nil
+	}
+	// the name of abstract socket starts with @
+	if addr[0] == '@' {
+		addr = "\x00" + addr[1:]
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: addr, Net: "unixgram"})
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+	if _, err = conn.Write([]byte(state)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// WatchdogInterval returns the watchdog timeout of systemd or 0 if the watchdog is disabled
+func WatchdogInterval() time.Duration {
+	if pid := os.Getenv("WATCHDOG_PID"); len(pid) > 0 && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+	return time.Duration(usec) * time.Microsecond
+}
+
+// RunWatchdog resets the watchdog timer of systemd twice per its timeout while healthy returns true
+func RunWatchdog(healthy func() bool) {
+	interval := WatchdogInterval()
+	if interval == 0 {
+		return
+	}
+	for range time.Tick(interval / 2) {
+		if healthy() {
+			Notify(NotifyWatchdog)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package service
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSystemdUnit(t *testing.T) {
+	unit, err := SystemdUnit(&Config{
+		Description: "Genesis blockchain node",
+		Executable:  "/opt/genesis/go-genesis",
+		Args:        []string{"-workDir=/var/lib/genesis", "-configPath=/etc/genesis/my config.toml", "start"},
+		WorkDir:     "/var/lib/genesis",
+		User:        "genesis",
+		Watchdog:    time.Minute,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		"Type=notify",
+		`ExecStart=/opt/genesis/go-genesis -workDir=/var/lib/genesis "-configPath=/etc/genesis/my config.toml" start`,
+		"WorkingDirectory=/var/lib/genesis",
+		"User=genesis",
+		"WatchdogSec=60",
+	} {
+		if !strings.Contains(unit, line+"\n") {
+			t.Errorf("line %s is not found in unit:\n%s", line, unit)
+		}
+	}
+
+	if unit, err = SystemdUnit(&Config{Executable: "/opt/genesis/go-genesis"}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(unit, "WatchdogSec") || strings.Contains(unit, "User=") {
+		t.Errorf("unexpected options in unit:\n%s", unit)
+	}
+}
+
+func TestNotify(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("unix datagram sockets are required")
+	}
+	os.Unsetenv("NOTIFY_SOCKET")
+	if ok, err := Notify(NotifyReady); ok || err != nil {
+		t.Fatalf("notified without socket: %v", err)
+	}
+
+	dir, err := ioutil.TempDir("", "notify")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	addr := filepath.Join(dir, "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: addr, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	os.Setenv("NOTIFY_SOCKET", addr)
+	defer os.Unsetenv("NOTIFY_SOCKET")
+
+	if ok, err := Notify(NotifyReady); !ok || err != nil {
+		t.Fatalf("not notified: %v", err)
+	}
+	buf := make([]byte, 64)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(buf[:n]) != NotifyReady {
+		t.Errorf("wrong state %s", buf[:n])
+	}
+}
+
+func TestWatchdogInterval(t *testing.T) {
+	defer os.Unsetenv("WATCHDOG_USEC")
+	defer os.Unsetenv("WATCHDOG_PID")
+
+	os.Setenv("WATCHDOG_USEC", "30000000")
+	if interval := WatchdogInterval(); interval != 30*time.Second {
+		t.Errorf("wrong interval %s", interval)
+	}
+	os.Setenv("WATCHDOG_PID", strconv.Itoa(os.Getpid()+1))
+	if interval := WatchdogInterval(); interval != 0 {
+		t.Errorf("watchdog of other process is enabled")
+	}
+	os.Unsetenv("WATCHDOG_PID")
+	os.Setenv("WATCHDOG_USEC", "")
+	if interval := WatchdogInterval(); interval != 0 {
+		t.Errorf("watchdog is enabled without timeout")
+	}
+}
@@ -0,0 +1,172 @@
+// +build windows
+
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package service
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const acceptedControls = windows.SERVICE_ACCEPT_STOP | windows.SERVICE_ACCEPT_SHUTDOWN
+
+var (
+	advapi32                         = windows.NewLazySystemDLL("advapi32.dll")
+	procRegisterServiceCtrlHandlerEx = advapi32.NewProc("RegisterServiceCtrlHandlerExW")
+
+	serviceMainCallback = syscall.NewCallback(serviceMain)
+	ctrlHandlerCallback = syscall.NewCallback(ctrlHandler)
+
+	// current is the service of the process, the callbacks of SCM can't have the context
+	current *winService
+)
+
+type winService struct {
+	name   *uint16
+	run    func()
+	stop   func()
+	handle windows.Handle
+	state  uint32
+	stopCh chan struct{}
+	err    error
+}
+
+func (s *winService) setStatus(state uint32) {
+	status := windows.SERVICE_STATUS{ServiceType: windows.SERVICE_WIN32_OWN_PROCESS, CurrentState: state}
+	if state == windows.SERVICE_RUNNING {
+		status.ControlsAccepted = acceptedControls
+	}
+	if state == windows.SERVICE_START_PENDING || state == windows.SERVICE_STOP_PENDING {
+		status.WaitHint = 30000
+	}
+	s.state = state
+	windows.SetServiceStatus(s.handle, &status)
+}
+
+func serviceMain(argc uint32, argv **uint16) uintptr {
+	s := current
+	handle, _, err := procRegisterServiceCtrlHandlerEx.Call(uintptr(unsafe.Pointer(s.name)), ctrlHandlerCallback, 0)
+	if handle == 0 {
+		s.err = err
+		return 0
+	}
+	s.handle = windows.Handle(handle)
+	s.setStatus(windows.SERVICE_START_PENDING)
+	go s.run()
+	s.setStatus(windows.SERVICE_RUNNING)
+	<-s.stopCh
+	s.setStatus(windows.SERVICE_STOP_PENDING)
+	s.stop()
+	s.setStatus(windows.SERVICE_STOPPED)
+	return 0
+}
+
+func ctrlHandler(ctrl, eventType uint32, eventData, context uintptr) uintptr {
+	s := current
+	switch ctrl {
+	case windows.SERVICE_CONTROL_STOP, windows.SERVICE_CONTROL_SHUTDOWN:
+		select {
+		case s.stopCh <- struct{}{}:
+		default:
+		}
+	case windows.SERVICE_CONTROL_INTERROGATE:
+		s.setStatus(s.state)
+	}
+	return windows.NO_ERROR
+}
+
+// Run runs the Windows service, run starts the node and stop stops it.
+// The function returns after the service has been stopped
+func Run(name string, run, stop func()) error {
+	current = &winService{
+		name:   windows.StringToUTF16Ptr(name),
+		run:    run,
+		stop:   stop,
+		stopCh: make(chan struct{}, 1),
+	}
+	table := []windows.SERVICE_TABLE_ENTRY{
+		{ServiceName: current.name, ServiceProc: serviceMainCallback},
+		{ServiceName: nil, ServiceProc: 0},
+	}
+	if err := windows.StartServiceCtrlDispatcher(&table[0]); err != nil {
+		return err
+	}
+	return current.err
+}
+
+func openManager() (windows.Handle, error) {
+	return windows.OpenSCManager(nil, nil, windows.SC_MANAGER_ALL_ACCESS)
+}
+
+// Install registers the Windows service which is started automatically
+func Install(cfg *Config) error {
+	mgr, err := openManager()
+	if err != nil {
+		return err
+	}
+	defer windows.CloseServiceHandle(mgr)
+
+	name := windows.StringToUTF16Ptr(cfg.Name)
+	if h, err := windows.OpenService(mgr, name, windows.SERVICE_QUERY_STATUS); err == nil {
+		windows.CloseServiceHandle(h)
+		return fmt.Errorf("service %s already exists", cfg.Name)
+	}
+	cmd := syscall.EscapeArg(cfg.Executable)
+	for _, arg := range cfg.Args {
+		cmd += " " + syscall.EscapeArg(arg)
+	}
+	h, err := windows.CreateService(mgr, name, windows.StringToUTF16Ptr(cfg.DisplayName),
+		windows.SERVICE_ALL_ACCESS, windows.SERVICE_WIN32_OWN_PROCESS, windows.SERVICE_AUTO_START,
+		windows.SERVICE_ERROR_NORMAL, windows.StringToUTF16Ptr(cmd), nil, nil, nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer windows.CloseServiceHandle(h)
+	if len(cfg.Description) > 0 {
+		desc := windows.SERVICE_DESCRIPTION{Description: windows.StringToUTF16Ptr(cfg.Description)}
+		if err = windows.ChangeServiceConfig2(h, windows.SERVICE_CONFIG_DESCRIPTION, (*byte)(unsafe.Pointer(&desc))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Uninstall removes the Windows service
+func Uninstall(name string) error {
+	mgr, err := openManager()
+	if err != nil {
+		return err
+	}
+	defer windows.CloseServiceHandle(mgr)
+
+	h, err := windows.OpenService(mgr, windows.StringToUTF16Ptr(name), windows.SERVICE_ALL_ACCESS)
+	if err != nil {
+		return fmt.Errorf("service %s is not installed: %s", name, err)
+	}
+	defer windows.CloseServiceHandle(h)
+	return windows.DeleteService(h)
+}
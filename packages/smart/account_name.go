@@ -0,0 +1,159 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/GenesisKernel/go-genesis/packages/config/syspar"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// defaultNamePeriod is used if name_registration_period is not defined
+const defaultNamePeriod = 365 * 24 * 3600
+
+var regexpAccountName = regexp.MustCompile(`^[a-z][a-z0-9_\-\.]{2,63}$`)
+
+// NormalizeAccountName returns the name in lower case or an error if the name is not valid.
+// The name must begin with a letter so it can't be confused with the address
+func NormalizeAccountName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !regexpAccountName.MatchString(name) {
+		return ``, fmt.Errorf(`wrong account name %s`, name)
+	}
+	return name, nil
+}
+
+func namePeriod() int64 {
+	if period := syspar.SysInt64(syspar.NameRegistrationPeriod); period > 0 {
+		return period
+	}
+	return defaultNamePeriod
+}
+
+func getAccountName(sc *SmartContract, name string) (*model.AccountName, bool, error) {
+	record := &model.AccountName{}
+	record.SetTablePrefix(sc.TxSmart.EcosystemID)
+	found, err := record.GetByName(sc.DbTransaction, name)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting account name")
+	}
+	return record, found, err
+}
+
+// ownAccountName returns the active name which belongs to the caller
+func ownAccountName(sc *SmartContract, name string) (*model.AccountName, error) {
+	name, err := NormalizeAccountName(name)
+	if err != nil {
+		return nil, err
+	}
+	record, found, err := getAccountName(sc, name)
+	if err != nil {
+		return nil, err
+	}
+	if !found || record.Expire < currentBlockTime(sc) {
+		log.WithFields(log.Fields{"type": consts.NotFound, "name": name}).Error("account name not found")
+		return nil, fmt.Errorf(`name %s has not been registered`, name)
+	}
+	if record.KeyID != sc.TxSmart.KeyID {
+		log.WithFields(log.Fields{"type": consts.AccessDenied, "name": name}).Error("Access denied")
+		return nil, errAccessDenied
+	}
+	return record, nil
+}
+
+func updateAccountName(sc *SmartContract, record *model.AccountName, fields []string, values []interface{}) error {
+	_, _, err := sc.selectiveLoggingAndUpd(fields, values, ecosystemTable(sc, `names`),
+		[]string{`id`}, []string{converter.Int64ToStr(record.ID)}, !sc.VDE && sc.Rollback, true)
+	return err
+}
+
+// RegisterName assigns the name to the caller. The expired name can be registered by anyone
+func RegisterName(sc *SmartContract, name string) (int64, error) {
+	name, err := NormalizeAccountName(name)
+	if err != nil {
+		return 0, err
+	}
+	record, found, err := getAccountName(sc, name)
+	if err != nil {
+		return 0, err
+	}
+	now := currentBlockTime(sc)
+	if !found {
+		_, id, err := sc.selectiveLoggingAndUpd([]string{`name`, `key_id`, `expire`, `block_id`},
+			[]interface{}{name, sc.TxSmart.KeyID, now + namePeriod(), currentBlockID(sc)},
+			ecosystemTable(sc, `names`), nil, nil, !sc.VDE && sc.Rollback, false)
+		if err != nil {
+			return 0, err
+		}
+		return converter.StrToInt64(id), nil
+	}
+	if record.Expire >= now {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "name": name}).Error("account name is already registered")
+		return 0, fmt.Errorf(`name %s is already registered`, name)
+	}
+	err = updateAccountName(sc, record, []string{`key_id`, `expire`, `block_id`},
+		[]interface{}{sc.TxSmart.KeyID, now + namePeriod(), currentBlockID(sc)})
+	return record.ID, err
+}
+
+// RenewName extends the registration of the name of the caller for one more period
+func RenewName(sc *SmartContract, name string) error {
+	record, err := ownAccountName(sc, name)
+	if err != nil {
+		return err
+	}
+	return updateAccountName(sc, record, []string{`expire`}, []interface{}{record.Expire + namePeriod()})
+}
+
+// TransferName assigns the name of the caller to the recipient, the expiration is kept
+func TransferName(sc *SmartContract, name string, recipient int64) error {
+	if recipient == 0 {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "recipient": recipient}).Error("wrong recipient")
+		return fmt.Errorf(`wrong recipient`)
+	}
+	record, err := ownAccountName(sc, name)
+	if err != nil {
+		return err
+	}
+	return updateAccountName(sc, record, []string{`key_id`}, []interface{}{recipient})
+}
+
+// ResolveName returns the key of the registered name or zero
+func ResolveName(sc *SmartContract, name string) (int64, error) {
+	name, err := NormalizeAccountName(name)
+	if err != nil {
+		return 0, nil
+	}
+	record, found, err := getAccountName(sc, name)
+	if err != nil || !found || record.Expire < currentBlockTime(sc) {
+		return 0, err
+	}
+	return record.KeyID, nil
+}
@@ -0,0 +1,44 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import "testing"
+
+func TestNormalizeAccountName(t *testing.T) {
+	for name, want := range map[string]string{
+		`alice`:         `alice`,
+		` Bob.Smith `:   `bob.smith`,
+		`john_doe-2`:    `john_doe-2`,
+		`ab`:            ``,
+		`1234-5678`:     ``,
+		`_alice`:        ``,
+		`alice smith`:   ``,
+		`алиса`:         ``,
+		`a234567890123`: `a234567890123`,
+	} {
+		got, err := NormalizeAccountName(name)
+		if got != want || (err == nil) != (len(want) > 0) {
+			t.Errorf(`%q: got %q, %v`, name, got, err)
+		}
+	}
+}
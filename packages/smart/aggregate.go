@@ -0,0 +1,153 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+	"github.com/GenesisKernel/go-genesis/packages/config/syspar"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	// aggregateFuncs are SQL functions which can be used by Aggregate
+	aggregateFuncs = map[string]bool{`count`: true, `sum`: true, `min`: true, `max`: true}
+
+	regexpColumnName = regexp.MustCompile(`^[a-z_][a-z0-9_]{0,63}$`)
+)
+
+// Aggregate returns the result of the aggregate function over the column of the rows which match where condition.
+// The read permissions and row_read condition of the table are checked, the count of scanned rows
+// is limited by max_aggregate_rows system parameter. The count of scanned rows is returned as the cost
+func Aggregate(sc *SmartContract, fn, table, column, where string, params []interface{}) (int64, string, error) {
+	fn = strings.ToLower(fn)
+	if !aggregateFuncs[fn] {
+		return 0, ``, fmt.Errorf(`unknown aggregate function %s`, fn)
+	}
+	tblname := getDefTableName(sc, table)
+	expr := `*`
+	if fn != `count` || len(column) > 0 {
+		column = strings.ToLower(column)
+		if !regexpColumnName.MatchString(column) {
+			return 0, ``, fmt.Errorf(`wrong column name %s`, column)
+		}
+		expr = `"` + column + `"`
+	}
+	if sc.VDE && *conf.CheckReadAccess {
+		if _, err := sc.AccessTablePerm(tblname, `read`); err != nil {
+			return 0, ``, err
+		}
+		if expr != `*` {
+			cols := []string{column}
+			if err := sc.AccessColumns(tblname, &cols, false); err != nil {
+				return 0, ``, err
+			}
+		}
+	}
+	where = strings.Replace(converter.Escape(where), `$`, `?`, -1)
+	where, err := sc.filterDeleted(tblname, where)
+	if err != nil {
+		return 0, ``, err
+	}
+	rowWhere, rowParams, err := sc.RowAccess(tblname, RowRead)
+	if err != nil {
+		return 0, ``, err
+	}
+	if len(rowWhere) > 0 {
+		if len(where) > 0 {
+			where = `(` + where + `) and ` + rowWhere
+		} else {
+			where = rowWhere
+		}
+		params = append(params, rowParams...)
+	}
+	if len(where) > 0 {
+		where = ` WHERE ` + where
+	}
+	var scan string
+	limit := syspar.GetMaxAggregateRows()
+	if limit > 0 {
+		scan = fmt.Sprintf(` LIMIT %d`, limit+1)
+	}
+	count, err := model.GetOneRowTransaction(sc.DbTransaction, fmt.Sprintf(`SELECT count(*) AS count FROM
+		(SELECT 1 FROM "%s"%s%s) AS rows`, tblname, where, scan), params...).Int64()
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err, "table": tblname}).Error("counting rows")
+		return 0, ``, err
+	}
+	cost := count[`count`]
+	if limit > 0 && cost > limit {
+		log.WithFields(log.Fields{"type": consts.ParameterExceeded, "table": tblname, "max_size": limit}).Error("too many rows to aggregate")
+		return 0, ``, fmt.Errorf(`Too many rows. Limit is %d`, limit)
+	}
+	if fn == `count` && expr == `*` {
+		return cost, converter.Int64ToStr(cost), nil
+	}
+	ret, err := model.GetOneRowTransaction(sc.DbTransaction, fmt.Sprintf(`SELECT %s(%s) AS value FROM "%s"%s`,
+		fn, expr, tblname, where), params...).String()
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err, "table": tblname}).Error("aggregating rows")
+		return 0, ``, err
+	}
+	if len(ret[`value`]) == 0 && (fn == `count` || fn == `sum`) {
+		return cost, `0`, nil
+	}
+	return cost, ret[`value`], nil
+}
+
+// DBCount returns the count of rows of the table which match where condition
+func DBCount(sc *SmartContract, table, where string, params ...interface{}) (int64, int64, error) {
+	cost, ret, err := Aggregate(sc, `count`, table, ``, where, params)
+	if err != nil {
+		return 0, 0, err
+	}
+	return cost, converter.StrToInt64(ret), nil
+}
+
+// DBSum returns the sum of the column of rows of the table which match where condition
+func DBSum(sc *SmartContract, table, column, where string, params ...interface{}) (int64, decimal.Decimal, error) {
+	cost, ret, err := Aggregate(sc, `sum`, table, column, where, params)
+	if err != nil {
+		return 0, decimal.Zero, err
+	}
+	sum, err := decimal.NewFromString(ret)
+	return cost, sum, err
+}
+
+// DBMin returns the minimum value of the column or empty string if there are no rows
+func DBMin(sc *SmartContract, table, column, where string, params ...interface{}) (int64, string, error) {
+	return Aggregate(sc, `min`, table, column, where, params)
+}
+
+// DBMax returns the maximum value of the column or empty string if there are no rows
+func DBMax(sc *SmartContract, table, column, where string, params ...interface{}) (int64, string, error) {
+	return Aggregate(sc, `max`, table, column, where, params)
+}
@@ -0,0 +1,188 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/GenesisKernel/go-genesis/packages/apps/manifest"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func parseManifest(data string) (*manifest.Manifest, error) {
+	m, err := manifest.Parse(data)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "error": err}).Error("parsing manifest")
+		return nil, err
+	}
+	return m, nil
+}
+
+func getApplication(sc *SmartContract, name string) (*model.Application, bool, error) {
+	app := &model.Application{}
+	app.SetTablePrefix(sc.TxSmart.EcosystemID)
+	found, err := app.GetByName(sc.DbTransaction, name)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting application")
+		return nil, false, err
+	}
+	return app, found, nil
+}
+
+func getApplications(sc *SmartContract) ([]model.Application, error) {
+	list, err := model.GetApplications(sc.DbTransaction, sc.TxSmart.EcosystemID)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting applications")
+	}
+	return list, err
+}
+
+func hasParameter(sc *SmartContract, name string) bool {
+	sp := &model.StateParameter{}
+	sp.SetTablePrefix(converter.Int64ToStr(sc.TxSmart.EcosystemID))
+	found, err := sp.Get(sc.DbTransaction, name)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting ecosystem parameter")
+	}
+	return found && err == nil
+}
+
+// ApplicationVersion returns the installed version of application or empty string
+func ApplicationVersion(sc *SmartContract, name string) (string, error) {
+	app, found, err := getApplication(sc, name)
+	if err != nil || !found {
+		return ``, err
+	}
+	return app.Version, nil
+}
+
+// ApplicationHooks checks the dependencies and parameters of the manifest and returns the contracts
+// which must be called to install or upgrade the application
+func ApplicationHooks(sc *SmartContract, data string) ([]interface{}, error) {
+	m, err := parseManifest(data)
+	if err != nil {
+		return nil, err
+	}
+	installed, err := ApplicationVersion(sc, m.Name)
+	if err != nil {
+		return nil, err
+	}
+	hooks, err := m.Hooks(installed)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "error": err, "installed": installed}).Error("getting hooks of application")
+		return nil, fmt.Errorf(`application %s %s: %v`, m.Name, installed, err)
+	}
+	list, err := getApplications(sc)
+	if err != nil {
+		return nil, err
+	}
+	versions := make(map[string]string)
+	for _, app := range list {
+		versions[app.Name] = app.Version
+	}
+	problems := m.Problems(versions, func(name string) bool { return hasParameter(sc, name) })
+	if len(problems) > 0 {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "problems": problems}).Error("checking application")
+		return nil, errors.New(strings.Join(problems, `; `))
+	}
+	ret := make([]interface{}, len(hooks))
+	for i, hook := range hooks {
+		ret[i] = hook
+	}
+	return ret, nil
+}
+
+// SetApplication registers the version of the installed application
+func SetApplication(sc *SmartContract, data string) error {
+	if !accessContracts(sc, `InstallApplication`) {
+		log.WithFields(log.Fields{"type": consts.IncorrectCallingContract}).Error("SetApplication can be only called from @1InstallApplication")
+		return fmt.Errorf(`SetApplication can be only called from InstallApplication`)
+	}
+	m, err := parseManifest(data)
+	if err != nil {
+		return err
+	}
+	app, found, err := getApplication(sc, m.Name)
+	if err != nil {
+		return err
+	}
+	var (
+		where    []string
+		whereVal []string
+	)
+	if found {
+		where, whereVal = []string{`id`}, []string{converter.Int64ToStr(app.ID)}
+	}
+	_, _, err = sc.selectiveLoggingAndUpd([]string{`name`, `version`, `manifest`, `block_id`},
+		[]interface{}{m.Name, m.Version, data, currentBlockID(sc)}, ecosystemTable(sc, `applications`),
+		where, whereVal, !sc.VDE && sc.Rollback, found)
+	return err
+}
+
+// RemoveApplication uninstalls the application if there are not dependent applications.
+// It returns the uninstall contract of the manifest
+func RemoveApplication(sc *SmartContract, name string) (string, error) {
+	if !accessContracts(sc, `UninstallApplication`) {
+		log.WithFields(log.Fields{"type": consts.IncorrectCallingContract}).Error("RemoveApplication can be only called from @1UninstallApplication")
+		return ``, fmt.Errorf(`RemoveApplication can be only called from UninstallApplication`)
+	}
+	app, found, err := getApplication(sc, name)
+	if err != nil {
+		return ``, err
+	}
+	if !found || len(app.Version) == 0 {
+		log.WithFields(log.Fields{"type": consts.NotFound, "name": name}).Error("application is not installed")
+		return ``, fmt.Errorf(`application %s is not installed`, name)
+	}
+	list, err := getApplications(sc)
+	if err != nil {
+		return ``, err
+	}
+	manifests := make([]*manifest.Manifest, 0, len(list))
+	for _, item := range list {
+		if m, err := manifest.Parse(item.Manifest); err == nil {
+			manifests = append(manifests, m)
+		}
+	}
+	if dependents := manifest.Dependents(name, manifests); len(dependents) > 0 {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "dependents": dependents}).Error("application is required")
+		return ``, fmt.Errorf(`application %s is required by %s`, name, strings.Join(dependents, `,`))
+	}
+	m, err := parseManifest(app.Manifest)
+	if err != nil {
+		return ``, err
+	}
+	_, _, err = sc.selectiveLoggingAndUpd([]string{`version`, `block_id`}, []interface{}{``, currentBlockID(sc)},
+		ecosystemTable(sc, `applications`), []string{`id`}, []string{converter.Int64ToStr(app.ID)},
+		!sc.VDE && sc.Rollback, true)
+	if err != nil {
+		return ``, err
+	}
+	return m.Uninstall, nil
+}
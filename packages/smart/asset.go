@@ -0,0 +1,274 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+)
+
+var regexpAssetSymbol = regexp.MustCompile(`^[A-Z][A-Z0-9]{0,15}$`)
+
+// ecosystemTable returns the name of the table of the ecosystem of the transaction
+func ecosystemTable(sc *SmartContract, name string) string {
+	return GetTableName(sc, name, sc.TxSmart.EcosystemID)
+}
+
+// assetAmount checks that the amount is a positive integer
+func assetAmount(amount string) (decimal.Decimal, error) {
+	value, err := decimal.NewFromString(amount)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.ConversionError, "error": err, "value": amount}).Error("converting amount from string to decimal")
+		return value, err
+	}
+	if value.Sign() <= 0 || !value.Equal(value.Floor()) {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "value": amount}).Error("wrong amount")
+		return value, fmt.Errorf(`wrong amount %s`, amount)
+	}
+	return value, nil
+}
+
+func getAsset(sc *SmartContract, symbol string) (*model.Asset, error) {
+	asset := &model.Asset{}
+	asset.SetTablePrefix(sc.TxSmart.EcosystemID)
+	found, err := asset.GetBySymbol(sc.DbTransaction, symbol)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting asset")
+		return nil, err
+	}
+	if !found {
+		log.WithFields(log.Fields{"type": consts.NotFound, "symbol": symbol}).Error("asset not found")
+		return nil, fmt.Errorf(`asset %s has not been found`, symbol)
+	}
+	return asset, nil
+}
+
+// checkAssetConditions allows to mint and burn the asset if its conditions are true.
+// The asset without conditions is managed only by its owner
+func checkAssetConditions(sc *SmartContract, asset *model.Asset) error {
+	if len(asset.Conditions) == 0 {
+		if sc.TxSmart.KeyID != asset.Owner {
+			log.WithFields(log.Fields{"type": consts.AccessDenied, "symbol": asset.Symbol}).Error("Access denied")
+			return errAccessDenied
+		}
+		return nil
+	}
+	ret, err := sc.EvalIf(asset.Conditions)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.EvalError, "error": err}).Error("evaluating asset conditions")
+		return err
+	}
+	if !ret {
+		log.WithFields(log.Fields{"type": consts.AccessDenied, "symbol": asset.Symbol}).Error("Access denied")
+		return errAccessDenied
+	}
+	return nil
+}
+
+func assetCredit(sc *SmartContract, asset *model.Asset, wallet int64, amount decimal.Decimal) error {
+	_, _, err := sc.selectiveLoggingAndUpd([]string{`+amount`}, []interface{}{amount}, ecosystemTable(sc, `asset_balances`),
+		[]string{`asset_id`, `wallet`}, []string{converter.Int64ToStr(asset.ID), converter.Int64ToStr(wallet)},
+		!sc.VDE && sc.Rollback, false)
+	return err
+}
+
+func assetDebit(sc *SmartContract, asset *model.Asset, wallet int64, amount decimal.Decimal) error {
+	balance := &model.AssetBalance{}
+	balance.SetTablePrefix(sc.TxSmart.EcosystemID)
+	found, err := balance.Get(sc.DbTransaction, asset.ID, wallet)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting asset balance")
+		return err
+	}
+	current := decimal.Zero
+	if found {
+		if current, err = decimal.NewFromString(balance.Amount); err != nil {
+			log.WithFields(log.Fields{"type": consts.ConversionError, "error": err, "value": balance.Amount}).Error("converting balance from string to decimal")
+			return err
+		}
+	}
+	if current.Cmp(amount) < 0 {
+		log.WithFields(log.Fields{"type": consts.NoFunds, "symbol": asset.Symbol, "wallet": wallet}).Error("not enough tokens")
+		return fmt.Errorf(`not enough %s`, asset.Symbol)
+	}
+	_, _, err = sc.selectiveLoggingAndUpd([]string{`-amount`}, []interface{}{amount}, ecosystemTable(sc, `asset_balances`),
+		[]string{`id`}, []string{converter.Int64ToStr(balance.ID)}, !sc.VDE && sc.Rollback, true)
+	return err
+}
+
+func assetHistory(sc *SmartContract, asset *model.Asset, sender, recipient int64, amount decimal.Decimal) error {
+	_, _, err := sc.selectiveLoggingAndUpd([]string{`asset_id`, `sender`, `recipient`, `amount`, `block_id`},
+		[]interface{}{asset.ID, sender, recipient, amount, currentBlockID(sc)}, ecosystemTable(sc, `asset_history`),
+		nil, nil, !sc.VDE && sc.Rollback, false)
+	return err
+}
+
+// CreateAsset registers the new asset of the ecosystem. The caller becomes the owner of the asset.
+// If maxSupply is zero then the supply is unlimited
+func CreateAsset(sc *SmartContract, symbol, name, maxSupply, conditions string) (int64, error) {
+	if !regexpAssetSymbol.MatchString(symbol) {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "symbol": symbol}).Error("wrong asset symbol")
+		return 0, fmt.Errorf(`wrong asset symbol %s`, symbol)
+	}
+	limit := decimal.Zero
+	if len(maxSupply) > 0 && maxSupply != `0` {
+		var err error
+		if limit, err = assetAmount(maxSupply); err != nil {
+			return 0, err
+		}
+	}
+	if len(conditions) > 0 {
+		if err := ValidateCondition(sc, conditions, sc.TxSmart.EcosystemID); err != nil {
+			return 0, err
+		}
+	}
+	asset := &model.Asset{}
+	asset.SetTablePrefix(sc.TxSmart.EcosystemID)
+	found, err := asset.GetBySymbol(sc.DbTransaction, symbol)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting asset")
+		return 0, err
+	}
+	if found {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "symbol": symbol}).Error("asset already exists")
+		return 0, fmt.Errorf(`asset %s already exists`, symbol)
+	}
+	_, id, err := sc.selectiveLoggingAndUpd([]string{`symbol`, `name`, `supply`, `max_supply`, `owner`, `conditions`, `block_id`},
+		[]interface{}{symbol, name, 0, limit, sc.TxSmart.KeyID, conditions, currentBlockID(sc)}, ecosystemTable(sc, `assets`),
+		nil, nil, !sc.VDE && sc.Rollback, false)
+	if err != nil {
+		return 0, err
+	}
+	return converter.StrToInt64(id), nil
+}
+
+// MintAsset issues the amount of the asset to the recipient
+func MintAsset(sc *SmartContract, symbol string, recipient int64, amount string) error {
+	asset, err := getAsset(sc, symbol)
+	if err != nil {
+		return err
+	}
+	if err = checkAssetConditions(sc, asset); err != nil {
+		return err
+	}
+	value, err := assetAmount(amount)
+	if err != nil {
+		return err
+	}
+	limit, err := decimal.NewFromString(asset.MaxSupply)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.ConversionError, "error": err, "value": asset.MaxSupply}).Error("converting max supply from string to decimal")
+		return err
+	}
+	supply, err := decimal.NewFromString(asset.Supply)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.ConversionError, "error": err, "value": asset.Supply}).Error("converting supply from string to decimal")
+		return err
+	}
+	if limit.Sign() > 0 && supply.Add(value).Cmp(limit) > 0 {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "symbol": symbol}).Error("max supply of asset is exceeded")
+		return fmt.Errorf(`max supply of %s is exceeded`, symbol)
+	}
+	_, _, err = sc.selectiveLoggingAndUpd([]string{`+supply`}, []interface{}{value}, ecosystemTable(sc, `assets`),
+		[]string{`id`}, []string{converter.Int64ToStr(asset.ID)}, !sc.VDE && sc.Rollback, true)
+	if err != nil {
+		return err
+	}
+	if err = assetCredit(sc, asset, recipient, value); err != nil {
+		return err
+	}
+	return assetHistory(sc, asset, 0, recipient, value)
+}
+
+// BurnAsset destroys the amount of the asset from the wallet of the caller
+func BurnAsset(sc *SmartContract, symbol string, amount string) error {
+	asset, err := getAsset(sc, symbol)
+	if err != nil {
+		return err
+	}
+	if err = checkAssetConditions(sc, asset); err != nil {
+		return err
+	}
+	value, err := assetAmount(amount)
+	if err != nil {
+		return err
+	}
+	if err = assetDebit(sc, asset, sc.TxSmart.KeyID, value); err != nil {
+		return err
+	}
+	_, _, err = sc.selectiveLoggingAndUpd([]string{`-supply`}, []interface{}{value}, ecosystemTable(sc, `assets`),
+		[]string{`id`}, []string{converter.Int64ToStr(asset.ID)}, !sc.VDE && sc.Rollback, true)
+	if err != nil {
+		return err
+	}
+	return assetHistory(sc, asset, sc.TxSmart.KeyID, 0, value)
+}
+
+// TransferAsset moves the amount of the asset from the wallet of the caller to the recipient
+func TransferAsset(sc *SmartContract, symbol string, recipient int64, amount string) error {
+	asset, err := getAsset(sc, symbol)
+	if err != nil {
+		return err
+	}
+	if recipient == 0 || recipient == sc.TxSmart.KeyID {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "recipient": recipient}).Error("wrong recipient")
+		return fmt.Errorf(`wrong recipient`)
+	}
+	value, err := assetAmount(amount)
+	if err != nil {
+		return err
+	}
+	if err = assetDebit(sc, asset, sc.TxSmart.KeyID, value); err != nil {
+		return err
+	}
+	if err = assetCredit(sc, asset, recipient, value); err != nil {
+		return err
+	}
+	return assetHistory(sc, asset, sc.TxSmart.KeyID, recipient, value)
+}
+
+// AssetBalance returns the amount of the asset on the wallet
+func AssetBalance(sc *SmartContract, symbol string, wallet int64) (string, error) {
+	asset, err := getAsset(sc, symbol)
+	if err != nil {
+		return ``, err
+	}
+	balance := &model.AssetBalance{}
+	balance.SetTablePrefix(sc.TxSmart.EcosystemID)
+	found, err := balance.Get(sc.DbTransaction, asset.ID, wallet)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting asset balance")
+		return ``, err
+	}
+	if !found {
+		return `0`, nil
+	}
+	return balance.Amount, nil
+}
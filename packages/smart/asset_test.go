@@ -0,0 +1,47 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import "testing"
+
+func TestAssetAmount(t *testing.T) {
+	for _, amount := range []string{`1`, `100`, `1000000000000000000000`} {
+		if _, err := assetAmount(amount); err != nil {
+			t.Errorf(`%s: unexpected error %v`, amount, err)
+		}
+	}
+	for _, amount := range []string{``, `0`, `-1`, `1.5`, `abc`} {
+		if _, err := assetAmount(amount); err == nil {
+			t.Errorf(`%s: expected error`, amount)
+		}
+	}
+}
+
+func TestAssetSymbol(t *testing.T) {
+	for symbol, ok := range map[string]bool{`GLD`: true, `T1`: true, `A`: true, `gld`: false,
+		`1T`: false, ``: false, `ABCDEFGHIJKLMNOPQ`: false, `G-L`: false} {
+		if regexpAssetSymbol.MatchString(symbol) != ok {
+			t.Errorf(`%s: want %v`, symbol, ok)
+		}
+	}
+}
@@ -0,0 +1,315 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/model/querycost"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// maxBatchRows is the maximum count of rows which can be inserted or updated by one call
+const maxBatchRows = 1000
+
+// batchColumns checks the list of columns of the batch
+func batchColumns(params string) ([]string, error) {
+	columns := strings.Split(params, `,`)
+	for i, column := range columns {
+		column = strings.ToLower(strings.TrimSpace(column))
+		if !regexpColumnName.MatchString(column) || column == `id` {
+			return nil, fmt.Errorf(`wrong column name %s`, column)
+		}
+		columns[i] = column
+	}
+	return columns, nil
+}
+
+// batchRows converts the rows of the batch to the slices of values
+func batchRows(rows []interface{}, count int) ([][]interface{}, error) {
+	if len(rows) == 0 {
+		return nil, fmt.Errorf(`rows are undefined`)
+	}
+	if len(rows) > maxBatchRows {
+		return nil, fmt.Errorf(`Too many rows. Limit is %d`, maxBatchRows)
+	}
+	ret := make([][]interface{}, len(rows))
+	for i, row := range rows {
+		values, ok := row.([]interface{})
+		if !ok || len(values) != count {
+			return nil, fmt.Errorf(`row %d must be an array of %d values`, i, count)
+		}
+		ret[i] = values
+	}
+	return ret, nil
+}
+
+// batchValue returns the parameter of the query for the value of the column
+func batchValue(value interface{}, bytea bool) interface{} {
+	str := converter.InterfaceToStr(value)
+	if str == `NULL` {
+		return nil
+	}
+	if bytea {
+		if reflect.TypeOf(value) == reflect.TypeOf([]byte{}) {
+			return value
+		}
+		if data, err := hex.DecodeString(str); err == nil {
+			return data
+		}
+	}
+	return str
+}
+
+// rollbackValue returns the value of the column for the rollback record
+func rollbackValue(value string, bytea bool) string {
+	if bytea && len(value) > 0 {
+		return string(converter.BinToHex([]byte(value)))
+	}
+	return value
+}
+
+// checkBatchTable checks that the rows of the table can be changed by the batch
+func (sc *SmartContract) checkBatchTable(table string) error {
+	if err := sc.checkFrozen(table); err != nil {
+		return err
+	}
+	// the erasure of personal data redacts only the rollback records of single rows
+	personal, err := sc.flaggedColumns(table, func(perm permColumn) bool { return perm.Personal })
+	if err != nil {
+		return err
+	}
+	if len(personal) > 0 {
+		return fmt.Errorf(`table %s has personal columns`, table)
+	}
+	return nil
+}
+
+// addBatchRollback writes one rollback record for all rows of the batch
+func (sc *SmartContract) addBatchRollback(table string, items []model.RollbackBatchItem) error {
+	if sc.VDE || !sc.Rollback {
+		return nil
+	}
+	if sc.BlockData == nil {
+		log.WithFields(log.Fields{"type": consts.EmptyObject}).Error("Block is undefined")
+		return fmt.Errorf(`It is impossible to write to DB when Block is undefined`)
+	}
+	data, err := json.Marshal(items)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Error("marshalling batch rollback")
+		return err
+	}
+	rollbackTx := &model.RollbackTx{BlockID: sc.BlockData.BlockID, TxHash: sc.TxHash, NameTable: table,
+		TableID: model.BatchTableID, Data: string(data)}
+	if err = rollbackTx.Create(sc.DbTransaction); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("creating rollback tx")
+		return err
+	}
+	return nil
+}
+
+// DBInsertBatch inserts the rows into the table by one query. Each row is an array of values
+// of the columns which are specified in params. It returns the identifiers of the new rows
+func DBInsertBatch(sc *SmartContract, tblname string, params string, rows []interface{}) (int64, []interface{}, error) {
+	tblname = getDefTableName(sc, tblname)
+	if err := sc.AccessTable(tblname, `insert`); err != nil {
+		return 0, nil, err
+	}
+	columns, err := batchColumns(params)
+	if err != nil {
+		return 0, nil, err
+	}
+	list, err := batchRows(rows, len(columns))
+	if err != nil {
+		return 0, nil, err
+	}
+	if err = sc.checkBatchTable(tblname); err != nil {
+		return 0, nil, err
+	}
+	id, err := model.GetNextID(sc.DbTransaction, tblname)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting next id for table")
+		return 0, nil, err
+	}
+	isBytea := GetBytea(sc.DbTransaction, tblname)
+	var (
+		args         []interface{}
+		placeholders []string
+	)
+	ids := make([]interface{}, len(list))
+	items := make([]model.RollbackBatchItem, len(list))
+	for i, values := range list {
+		if err = sc.checkReferences(tblname, columns, values); err != nil {
+			return 0, nil, err
+		}
+		if values, err = sc.encryptColumns(tblname, columns, values); err != nil {
+			return 0, nil, err
+		}
+		ids[i] = id + int64(i)
+		items[i].ID = converter.Int64ToStr(id + int64(i))
+		args = append(args, id+int64(i))
+		for j, value := range values {
+			args = append(args, batchValue(value, isBytea[columns[j]]))
+		}
+		placeholders = append(placeholders, `(?`+strings.Repeat(`,?`, len(columns))+`)`)
+	}
+	query := fmt.Sprintf(`INSERT INTO "%s" (id,"%s") VALUES %s`, tblname, strings.Join(columns, `","`),
+		strings.Join(placeholders, `,`))
+	cost, err := querycost.GetQueryCoster(querycost.FormulaQueryCosterType).QueryCost(sc.DbTransaction, query)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting query total cost for insert query")
+		return 0, nil, err
+	}
+	if err = model.GetDB(sc.DbTransaction).Exec(query, args...).Error; err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("executing batch insert")
+		return 0, nil, err
+	}
+	if err = sc.addBatchRollback(tblname, items); err != nil {
+		return 0, nil, err
+	}
+	return cost, ids, nil
+}
+
+// DBUpdateBatch updates the rows of the table by one query. Each row is an array where the first item
+// is the identifier of the row and the next items are the values of the columns which are specified in params
+func DBUpdateBatch(sc *SmartContract, tblname string, params string, rows []interface{}) (int64, error) {
+	tblname = getDefTableName(sc, tblname)
+	if err := sc.AccessTable(tblname, `update`); err != nil {
+		return 0, err
+	}
+	if strings.Contains(tblname, `_reports_`) {
+		return 0, fmt.Errorf(`Access denied to report table`)
+	}
+	columns, err := batchColumns(params)
+	if err != nil {
+		return 0, err
+	}
+	if err = sc.AccessColumns(tblname, &columns, true); err != nil {
+		return 0, err
+	}
+	list, err := batchRows(rows, len(columns)+1)
+	if err != nil {
+		return 0, err
+	}
+	if err = sc.checkBatchTable(tblname); err != nil {
+		return 0, err
+	}
+	ids := make([]int64, len(list))
+	unique := make(map[int64]bool)
+	for i, values := range list {
+		ids[i] = converter.StrToInt64(converter.InterfaceToStr(values[0]))
+		if ids[i] <= 0 || unique[ids[i]] {
+			return 0, fmt.Errorf(`wrong identifier %v of row %d`, values[0], i)
+		}
+		unique[ids[i]] = true
+	}
+	if err = sc.checkRowUpdate(tblname, `id in (?)`, ids); err != nil {
+		return 0, err
+	}
+	types := make([]string, len(columns))
+	for i, column := range columns {
+		col, err := model.GetColumnSchema(sc.DbTransaction, tblname, column)
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting column schema")
+			return 0, err
+		}
+		if col == nil {
+			return 0, fmt.Errorf(`column %s doesn't exists`, column)
+		}
+		types[i] = col.Type
+	}
+	selectQuery := fmt.Sprintf(`SELECT id,"%s" FROM "%s" WHERE id in (?)`, strings.Join(columns, `","`), tblname)
+	queryCoster := querycost.GetQueryCoster(querycost.FormulaQueryCosterType)
+	cost, err := queryCoster.QueryCost(sc.DbTransaction, selectQuery)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting query total cost for select query")
+		return 0, err
+	}
+	prev, err := model.GetAllTransaction(sc.DbTransaction, selectQuery, -1, ids)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting previous values of rows")
+		return 0, err
+	}
+	if len(prev) != len(ids) {
+		log.WithFields(log.Fields{"type": consts.NotFound, "err": errUpdNotExistRecord}).Error("updating for not existing record")
+		return 0, errUpdNotExistRecord
+	}
+	isBytea := GetBytea(sc.DbTransaction, tblname)
+	items := make([]model.RollbackBatchItem, len(prev))
+	for i, row := range prev {
+		items[i] = model.RollbackBatchItem{ID: row[`id`], Data: make(map[string]string)}
+		for _, column := range columns {
+			items[i].Data[column] = rollbackValue(row[column], isBytea[column])
+		}
+	}
+	var (
+		args         []interface{}
+		placeholders []string
+	)
+	for i, values := range list {
+		values = values[1:]
+		if err = sc.checkReferences(tblname, columns, values); err != nil {
+			return 0, err
+		}
+		if marker, ok := deletedMarker(columns, values); ok {
+			if err = sc.markDeleted(tblname, ids[i], marker, 0); err != nil {
+				return 0, err
+			}
+		}
+		if values, err = sc.encryptColumns(tblname, columns, values); err != nil {
+			return 0, err
+		}
+		args = append(args, ids[i])
+		for j, value := range values {
+			args = append(args, batchValue(value, isBytea[columns[j]]))
+		}
+		placeholders = append(placeholders, `(?`+strings.Repeat(`,?`, len(columns))+`)`)
+	}
+	set := make([]string, len(columns))
+	for i, column := range columns {
+		set[i] = fmt.Sprintf(`"%[1]s" = v."%[1]s"::%[2]s`, column, types[i])
+	}
+	query := fmt.Sprintf(`UPDATE "%s" SET %s FROM (VALUES %s) AS v(id,"%s") WHERE "%[1]s".id = v.id::bigint`,
+		tblname, strings.Join(set, `,`), strings.Join(placeholders, `,`), strings.Join(columns, `","`))
+	updateCost, err := queryCoster.QueryCost(sc.DbTransaction, query)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting query total cost for update query")
+		return 0, err
+	}
+	if err = model.GetDB(sc.DbTransaction).Exec(query, args...).Error; err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("executing batch update")
+		return 0, err
+	}
+	if err = sc.addBatchRollback(tblname, items); err != nil {
+		return 0, err
+	}
+	return cost + updateCost, nil
+}
@@ -0,0 +1,58 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBatchParams(t *testing.T) {
+	columns, err := batchColumns(` Name, amount `)
+	if err != nil || !reflect.DeepEqual(columns, []string{`name`, `amount`}) {
+		t.Errorf(`wrong columns %v %v`, columns, err)
+	}
+	for _, params := range []string{`id,name`, `name,+amount`, `name;drop`, ``} {
+		if _, err := batchColumns(params); err == nil {
+			t.Errorf(`%s: expected error`, params)
+		}
+	}
+	rows, err := batchRows([]interface{}{[]interface{}{`a`, 1}, []interface{}{`b`, 2}}, 2)
+	if err != nil || len(rows) != 2 || rows[1][0] != `b` {
+		t.Errorf(`wrong rows %v %v`, rows, err)
+	}
+	for _, list := range [][]interface{}{nil, {[]interface{}{`a`}}, {`a`}, make([]interface{}, maxBatchRows+1)} {
+		if _, err := batchRows(list, 2); err == nil {
+			t.Errorf(`%v: expected error`, list)
+		}
+	}
+	if v := batchValue(`NULL`, false); v != nil {
+		t.Errorf(`NULL must be nil`)
+	}
+	if v := batchValue(`0a0b`, true); !reflect.DeepEqual(v, []byte{10, 11}) {
+		t.Errorf(`wrong bytea value %v`, v)
+	}
+	if v := batchValue(int64(5), false); v != `5` {
+		t.Errorf(`wrong value %v`, v)
+	}
+}
@@ -0,0 +1,134 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"mime"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/GenesisKernel/go-genesis/packages/apps/manifest"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	regexpAssetApp  = regexp.MustCompile(`^[\w\-]{1,100}$`)
+	regexpAssetName = regexp.MustCompile(`^[\w\-]+(\.[\w\-]+)*(/[\w\-]+(\.[\w\-]+)*)*$`)
+
+	// assetTypes doesn't depend on the mime tables of the system, so all nodes detect the same types
+	assetTypes = map[string]string{
+		`.css`:   `text/css; charset=utf-8`,
+		`.gif`:   `image/gif`,
+		`.htm`:   `text/html; charset=utf-8`,
+		`.html`:  `text/html; charset=utf-8`,
+		`.ico`:   `image/x-icon`,
+		`.jpeg`:  `image/jpeg`,
+		`.jpg`:   `image/jpeg`,
+		`.js`:    `application/javascript`,
+		`.json`:  `application/json`,
+		`.map`:   `application/json`,
+		`.png`:   `image/png`,
+		`.svg`:   `image/svg+xml`,
+		`.ttf`:   `font/ttf`,
+		`.txt`:   `text/plain; charset=utf-8`,
+		`.webp`:  `image/webp`,
+		`.woff`:  `font/woff`,
+		`.woff2`: `font/woff2`,
+	}
+)
+
+// checkAsset checks the name of the asset and returns its mime type. If mimeType is empty
+// then the type is detected by the extension of the name or by the content
+func checkAsset(name, mimeType string, data []byte) (string, error) {
+	if len(name) > 255 || !regexpAssetName.MatchString(name) {
+		return ``, fmt.Errorf(`wrong asset name %s`, name)
+	}
+	if len(data) == 0 {
+		return ``, fmt.Errorf(`asset %s is empty`, name)
+	}
+	if len(mimeType) == 0 {
+		var ok bool
+		if mimeType, ok = assetTypes[strings.ToLower(path.Ext(name))]; !ok {
+			mimeType = http.DetectContentType(data)
+		}
+	}
+	if _, _, err := mime.ParseMediaType(mimeType); err != nil || len(mimeType) > 255 {
+		return ``, fmt.Errorf(`wrong mime type %s`, mimeType)
+	}
+	return mimeType, nil
+}
+
+// UploadAsset stores the static asset of the application. The asset can be replaced only by the same or newer version
+func UploadAsset(sc *SmartContract, app, version, name, mimeType string, data []byte) (int64, error) {
+	if !accessContracts(sc, `UploadAsset`) {
+		log.WithFields(log.Fields{"type": consts.IncorrectCallingContract}).Error("UploadAsset can be only called from @1UploadAsset")
+		return 0, fmt.Errorf(`UploadAsset can be only called from UploadAsset`)
+	}
+	if !regexpAssetApp.MatchString(app) {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "app": app}).Error("wrong application name")
+		return 0, fmt.Errorf(`wrong application name %s`, app)
+	}
+	ver, err := manifest.ParseVersion(version)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "error": err}).Error("parsing version of asset")
+		return 0, err
+	}
+	if mimeType, err = checkAsset(name, mimeType, data); err != nil {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "error": err}).Error("checking asset")
+		return 0, err
+	}
+	binary := &model.Binary{}
+	binary.SetTablePrefix(sc.TxSmart.EcosystemID)
+	found, err := binary.Get(sc.DbTransaction, app, name)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting asset")
+		return 0, err
+	}
+	var (
+		where    []string
+		whereVal []string
+	)
+	if found {
+		if cur, err := manifest.ParseVersion(binary.Version); err == nil && ver.Compare(cur) < 0 {
+			log.WithFields(log.Fields{"type": consts.InvalidObject, "version": version}).Error("asset version is older")
+			return 0, fmt.Errorf(`asset %s has newer version %s`, name, binary.Version)
+		}
+		where, whereVal = []string{`id`}, []string{converter.Int64ToStr(binary.ID)}
+	}
+	checksum := sha256.Sum256(data)
+	_, id, err := sc.selectiveLoggingAndUpd([]string{`app`, `name`, `version`, `mime_type`, `data`, `checksum`, `block_id`},
+		[]interface{}{app, name, ver.String(), mimeType, data, fmt.Sprintf(`%x`, checksum), currentBlockID(sc)},
+		ecosystemTable(sc, `binaries`), where, whereVal, !sc.VDE && sc.Rollback, found)
+	if err != nil {
+		return 0, err
+	}
+	return converter.StrToInt64(id), nil
+}
@@ -0,0 +1,49 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import "testing"
+
+func TestCheckAsset(t *testing.T) {
+	png := []byte("\x89PNG\x0D\x0A\x1A\x0A0000")
+	for _, item := range []struct {
+		name, mimeType string
+		data           []byte
+		want           string
+	}{
+		{`js/app.min.js`, ``, []byte(`var a`), `application/javascript`},
+		{`Style.CSS`, ``, []byte(`a{}`), `text/css; charset=utf-8`},
+		{`logo`, ``, png, `image/png`},
+		{`data.bin`, `application/octet-stream`, png, `application/octet-stream`},
+		{`../secret`, ``, png, ``},
+		{`/abs.js`, ``, png, ``},
+		{`dir//file.js`, ``, png, ``},
+		{`empty.js`, ``, nil, ``},
+		{`file.js`, `not a type`, png, ``},
+	} {
+		got, err := checkAsset(item.name, item.mimeType, item.data)
+		if got != item.want || (err == nil) != (len(item.want) > 0) {
+			t.Errorf(`%s: got %q, %v`, item.name, got, err)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/GenesisKernel/go-genesis/packages/bridge"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func getBridgeNetwork(network int64) (*bridge.Network, error) {
+	route, err := bridge.GetNetwork(network)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.BridgeError, "network": network, "error": err}).Error("getting bridge network")
+	}
+	return route, err
+}
+
+// BridgeTransfer locks or burns the tokens of the sender and registers the outgoing transfer to the network
+func BridgeTransfer(sc *SmartContract, network int64, recipient, amount string) (int64, error) {
+	route, err := getBridgeNetwork(network)
+	if err != nil {
+		return 0, err
+	}
+	recipientID := AddressToID(recipient)
+	if recipientID == 0 {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "recipient": recipient}).Error("wrong recipient")
+		return 0, fmt.Errorf(`wrong recipient %s`, recipient)
+	}
+	if amount, err = bridge.NormalizeAmount(amount); err != nil {
+		log.WithFields(log.Fields{"type": consts.ConversionError, "error": err}).Error("converting amount")
+		return 0, err
+	}
+	if err = walletDebit(sc, route.Ecosystem, sc.TxSmart.KeyID, amount); err != nil {
+		return 0, err
+	}
+	if route.Mode == bridge.ModeLock {
+		if err = walletCredit(sc, route.Ecosystem, route.Wallet, amount); err != nil {
+			return 0, err
+		}
+	}
+	_, id, err := sc.selectiveLoggingAndUpd([]string{`network`, `ecosystem`, `sender`, `recipient`, `amount`, `block_id`},
+		[]interface{}{network, route.Ecosystem, sc.TxSmart.KeyID, recipientID, amount, currentBlockID(sc)},
+		`bridge_transfers`, nil, nil, !sc.VDE && sc.Rollback, false)
+	if err != nil {
+		return 0, err
+	}
+	return converter.StrToInt64(id), nil
+}
+
+// BridgeReceive releases or mints the tokens of the transfer from the network which has been attested by its validators
+func BridgeReceive(sc *SmartContract, network, transferID int64, recipient, amount, attestations string) error {
+	route, err := getBridgeNetwork(network)
+	if err != nil {
+		return err
+	}
+	recipientID := AddressToID(recipient)
+	if recipientID == 0 {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "recipient": recipient}).Error("wrong recipient")
+		return fmt.Errorf(`wrong recipient %s`, recipient)
+	}
+	if amount, err = bridge.NormalizeAmount(amount); err != nil {
+		log.WithFields(log.Fields{"type": consts.ConversionError, "error": err}).Error("converting amount")
+		return err
+	}
+	var list []bridge.Attestation
+	if err = json.Unmarshal([]byte(attestations), &list); err != nil {
+		log.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err}).Error("unmarshalling attestations")
+		return err
+	}
+	if err = route.Verify(bridge.ForSign(network, bridge.NetworkID(), transferID, recipientID, amount), list); err != nil {
+		log.WithFields(log.Fields{"type": consts.BridgeError, "network": network, "transfer": transferID}).Error(err)
+		return err
+	}
+	receipt := &model.BridgeReceipt{}
+	found, err := receipt.Get(sc.DbTransaction, network, transferID)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting bridge receipt")
+		return err
+	}
+	if found {
+		log.WithFields(log.Fields{"type": consts.BridgeError, "network": network, "transfer": transferID}).Error("transfer has already been received")
+		return fmt.Errorf(`transfer %d has already been received`, transferID)
+	}
+	if route.Mode == bridge.ModeLock {
+		if err = walletDebit(sc, route.Ecosystem, route.Wallet, amount); err != nil {
+			return err
+		}
+	}
+	if err = walletCredit(sc, route.Ecosystem, recipientID, amount); err != nil {
+		return err
+	}
+	_, _, err = sc.selectiveLoggingAndUpd([]string{`network`, `transfer_id`, `recipient`, `amount`, `block_id`},
+		[]interface{}{network, transferID, recipientID, amount, currentBlockID(sc)},
+		`bridge_receipts`, nil, nil, !sc.VDE && sc.Rollback, false)
+	return err
+}
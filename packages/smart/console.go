@@ -0,0 +1,81 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/script"
+	"github.com/GenesisKernel/go-genesis/packages/utils/tx"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const consoleName = `console`
+
+// compileConsole compiles the source as an expression whose value is returned,
+// if it isn't an expression then the source is compiled as a list of statements
+func compileConsole(vm *script.VM, ecosystemID int64, source string) (*script.Block, error) {
+	owner := &script.OwnerInfo{StateID: uint32(ecosystemID)}
+	block, err := vm.CompileBlock([]rune(fmt.Sprintf("func %s string {\nreturn Str(%s)\n}", consoleName, source)), owner)
+	if err != nil {
+		block, err = vm.CompileBlock([]rune(fmt.Sprintf("func %s {\n%s\n}", consoleName, source)), owner)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(block.Children) != 1 {
+		return nil, fmt.Errorf(`wrong console source`)
+	}
+	return block, nil
+}
+
+// ConsoleEval runs the source code against VDE of the ecosystem on behalf of the key.
+// The source can be an expression or statements which call functions and contracts of VDE
+func ConsoleEval(ecosystemID, keyID int64, source string) (string, error) {
+	vm := GetVM(true, ecosystemID)
+	if vm == nil {
+		log.WithFields(log.Fields{"type": consts.NotFound, "ecosystem": ecosystemID}).Error("VDE not found")
+		return ``, fmt.Errorf(`VDE %d doesn't exist`, ecosystemID)
+	}
+	block, err := compileConsole(vm, ecosystemID, source)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.EvalError, "error": err}).Error("compiling console source")
+		return ``, err
+	}
+	sc := &SmartContract{VDE: true, VM: vm, TxSmart: tx.SmartContract{Header: tx.Header{
+		Time: time.Now().Unix(), EcosystemID: ecosystemID, KeyID: keyID}}}
+	sc.TxContract = &Contract{Name: consoleName, Block: block, StackCont: []string{consoleName}}
+	extend := sc.getExtend()
+	(*extend)[`stack_cont`] = StackCont
+	ret, err := VMRun(vm, block.Children[0], nil, extend)
+	if err != nil {
+		return ``, err
+	}
+	if len(ret) == 0 {
+		return ``, nil
+	}
+	return fmt.Sprint(ret[0]), nil
+}
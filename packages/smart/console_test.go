@@ -0,0 +1,57 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"testing"
+
+	"github.com/GenesisKernel/go-genesis/packages/script"
+)
+
+func TestConsoleEval(t *testing.T) {
+	const ecosystemID = 1000
+	vm := newVM()
+	EmbedFuncs(vm, script.VMTypeVDE)
+	smartVDE[ecosystemID] = vm
+	defer delete(smartVDE, ecosystemID)
+
+	for _, item := range []struct {
+		source, want string
+		fail         bool
+	}{
+		{`2 * (3 + 4)`, `14`, false},
+		{`Sprintf("%d-%s", 5, "a")`, `5-a`, false},
+		{`$key_id`, `7`, false},
+		{"var i int\ni = 10\nwhile i > 0 {\ni = i - 1\n}", ``, false},
+		{`UnknownFunc(1)`, ``, true},
+		{`var i int i = `, ``, true},
+	} {
+		got, err := ConsoleEval(ecosystemID, 7, item.source)
+		if (err != nil) != item.fail || got != item.want {
+			t.Errorf(`%q: got %q, %v`, item.source, got, err)
+		}
+	}
+	if _, err := ConsoleEval(ecosystemID+1, 7, `1`); err == nil {
+		t.Error(`expected error for unknown VDE`)
+	}
+}
@@ -0,0 +1,249 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	crand "crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/crypto"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// encryptedPrefix is the prefix of the values of encrypted columns. The value has the form enc:<key id>:<base64 data>
+const encryptedPrefix = `enc:`
+
+var (
+	errMasterKey    = errors.New(`Data master key is not configured`)
+	errEncryptedVDE = errors.New(`Encrypted columns are available only in VDE`)
+
+	// dataKeys is the cache of decrypted data keys by the name of the table and the identifier
+	dataKeys      = make(map[string][]byte)
+	dataKeysMutex = &sync.Mutex{}
+)
+
+func masterKey() ([]byte, error) {
+	if len(conf.Config.DataMasterKey) == 0 {
+		log.WithFields(log.Fields{"type": consts.CryptoError}).Error("data master key is not configured")
+		return nil, errMasterKey
+	}
+	key, err := hex.DecodeString(conf.Config.DataMasterKey)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.ConversionError, "error": err}).Error("decoding data master key")
+		return nil, err
+	}
+	return key, nil
+}
+
+func dataKeyPrefix(sc *SmartContract) string {
+	return fmt.Sprintf(`%d_vde`, sc.TxSmart.EcosystemID)
+}
+
+// unwrapDataKey decrypts the data key with the master key and caches it
+func unwrapDataKey(prefix string, dataKey *model.DataKey) ([]byte, error) {
+	master, err := masterKey()
+	if err != nil {
+		return nil, err
+	}
+	key, err := crypto.DecryptAEAD(dataKey.DataKey, master)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err, "id": dataKey.ID}).Error("decrypting data key")
+		return nil, err
+	}
+	dataKeysMutex.Lock()
+	dataKeys[prefix+converter.Int64ToStr(dataKey.ID)] = key
+	dataKeysMutex.Unlock()
+	return key, nil
+}
+
+// getDataKey returns the data key of the ecosystem by its identifier
+func getDataKey(sc *SmartContract, id int64) ([]byte, error) {
+	prefix := dataKeyPrefix(sc)
+	dataKeysMutex.Lock()
+	key, ok := dataKeys[prefix+converter.Int64ToStr(id)]
+	dataKeysMutex.Unlock()
+	if ok {
+		return key, nil
+	}
+	dataKey := &model.DataKey{}
+	dataKey.SetTablePrefix(prefix)
+	found, err := dataKey.Get(sc.DbTransaction, id)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting data key")
+		return nil, err
+	}
+	if !found {
+		log.WithFields(log.Fields{"type": consts.NotFound, "id": id}).Error("data key not found")
+		return nil, fmt.Errorf(`data key %d has not been found`, id)
+	}
+	return unwrapDataKey(prefix, dataKey)
+}
+
+// currentDataKey returns the last data key of the ecosystem. The key is created if there is not any
+func currentDataKey(sc *SmartContract) (int64, []byte, error) {
+	dataKey := &model.DataKey{}
+	dataKey.SetTablePrefix(dataKeyPrefix(sc))
+	found, err := dataKey.GetLast(sc.DbTransaction)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting data key")
+		return 0, nil, err
+	}
+	if !found {
+		id, err := RotateDataKey(sc)
+		if err != nil {
+			return 0, nil, err
+		}
+		key, err := getDataKey(sc, id)
+		return id, key, err
+	}
+	key, err := getDataKey(sc, dataKey.ID)
+	return dataKey.ID, key, err
+}
+
+// RotateDataKey creates the new data key of the ecosystem. The new values of encrypted columns
+// are encrypted with this key, the previous values are still decrypted with their keys
+func RotateDataKey(sc *SmartContract) (int64, error) {
+	if !sc.VDE {
+		return 0, errEncryptedVDE
+	}
+	master, err := masterKey()
+	if err != nil {
+		return 0, err
+	}
+	key := make([]byte, 32)
+	if _, err = crand.Read(key); err != nil {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("generating data key")
+		return 0, err
+	}
+	wrapped, err := crypto.EncryptAEAD(key, master)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("encrypting data key")
+		return 0, err
+	}
+	prefix := dataKeyPrefix(sc)
+	id, err := model.GetNextID(sc.DbTransaction, prefix+`_data_keys`)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting next ID")
+		return 0, err
+	}
+	dataKey := &model.DataKey{ID: id, DataKey: wrapped, CreatedAt: sc.TxSmart.Time}
+	dataKey.SetTablePrefix(prefix)
+	if err = dataKey.Create(sc.DbTransaction); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("creating data key")
+		return 0, err
+	}
+	return id, nil
+}
+
+func encryptValue(id int64, key []byte, value string) (string, error) {
+	data, err := crypto.EncryptAEAD([]byte(value), key)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("encrypting value")
+		return ``, err
+	}
+	return fmt.Sprintf(`%s%d:%s`, encryptedPrefix, id, base64.StdEncoding.EncodeToString(data)), nil
+}
+
+// parseEncrypted returns the identifier of the data key and the encrypted data of the value
+func parseEncrypted(value string) (int64, []byte, error) {
+	parts := strings.SplitN(strings.TrimPrefix(value, encryptedPrefix), `:`, 2)
+	if !strings.HasPrefix(value, encryptedPrefix) || len(parts) != 2 {
+		return 0, nil, fmt.Errorf(`value is not encrypted`)
+	}
+	data, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return 0, nil, err
+	}
+	return converter.StrToInt64(parts[0]), data, nil
+}
+
+// encryptColumns returns the copy of values where the values of encrypted columns of the table are encrypted
+func (sc *SmartContract) encryptColumns(table string, columns []string, values []interface{}) ([]interface{}, error) {
+	if !sc.VDE {
+		return values, nil
+	}
+	encrypted, err := sc.flaggedColumns(table, func(perm permColumn) bool { return perm.Encrypted })
+	if err != nil || len(encrypted) == 0 {
+		return values, err
+	}
+	var (
+		id  int64
+		key []byte
+	)
+	ret := make([]interface{}, len(values))
+	copy(ret, values)
+	for i, column := range columns {
+		if i >= len(ret) || !encrypted[strings.ToLower(strings.TrimSpace(column))] {
+			continue
+		}
+		if key == nil {
+			if id, key, err = currentDataKey(sc); err != nil {
+				return nil, err
+			}
+		}
+		if ret[i], err = encryptValue(id, key, fmt.Sprint(ret[i])); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+// DecryptColumn decrypts the value of the encrypted column if the caller has the read access to this column
+func DecryptColumn(sc *SmartContract, table, column, value string) (string, error) {
+	if !sc.VDE {
+		return ``, errEncryptedVDE
+	}
+	tblname := getDefTableName(sc, table)
+	cols := []string{column}
+	if err := sc.AccessColumns(tblname, &cols, false); err != nil {
+		return ``, err
+	}
+	if !strings.HasPrefix(value, encryptedPrefix) {
+		return value, nil
+	}
+	id, data, err := parseEncrypted(value)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.ConversionError, "error": err}).Error("parsing encrypted value")
+		return ``, err
+	}
+	key, err := getDataKey(sc, id)
+	if err != nil {
+		return ``, err
+	}
+	out, err := crypto.DecryptAEAD(data, key)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("decrypting value")
+		return ``, err
+	}
+	return string(out), nil
+}
@@ -0,0 +1,57 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+)
+
+// BlockTime returns the time of the current block, the time of the transaction is returned in VDE.
+// Contracts must use it instead of the clock of the node
+func BlockTime(sc *SmartContract) int64 {
+	if sc.BlockData != nil {
+		return sc.BlockData.Time
+	}
+	return sc.TxSmart.Time
+}
+
+// ParseTime returns the unix time of the date and time, UTC is used if the offset isn't specified
+func ParseTime(value string) (int64, error) {
+	return converter.ParseTime(value)
+}
+
+// FormatTime returns UTC date and time of the unix time in the format like YYYY-MM-DD HH:MI:SS
+func FormatTime(format string, unix int64) string {
+	return converter.FormatTime(format, unix)
+}
+
+// AddTime adds the interval like '1 day' or '-2 months' to the unix time
+func AddTime(unix int64, interval string) (int64, error) {
+	return converter.AddInterval(unix, interval)
+}
+
+// CmpTime returns -1, 0 or 1 if the first date and time is earlier, equal or later than the second one
+func CmpTime(left, right string) (int64, error) {
+	ret, err := converter.CompareTime(left, right)
+	return int64(ret), err
+}
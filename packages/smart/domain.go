@@ -0,0 +1,137 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const defaultDomainPage = `default_page`
+
+var regexpHost = regexp.MustCompile(`^([a-z0-9]([a-z0-9\-]{0,61}[a-z0-9])?\.)+[a-z][a-z0-9\-]{0,61}[a-z0-9]$`)
+
+// NormalizeHost returns the hostname in lower case without the port and the trailing dot
+func NormalizeHost(host string) (string, error) {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if i := strings.LastIndex(host, `:`); i >= 0 && !strings.Contains(host[i:], `]`) {
+		host = host[:i]
+	}
+	host = strings.TrimSuffix(host, `.`)
+	if len(host) > 253 || !regexpHost.MatchString(host) {
+		return ``, fmt.Errorf(`wrong hostname %s`, host)
+	}
+	return host, nil
+}
+
+func getDomain(sc *SmartContract, host string) (*model.Domain, bool, error) {
+	domain := &model.Domain{}
+	found, err := domain.GetByHost(sc.DbTransaction, host)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting domain")
+	}
+	return domain, found, err
+}
+
+// checkDomainAccess allows the function to be called only from the contract of the same name
+// by the founder of the ecosystem
+func checkDomainAccess(sc *SmartContract, name string) error {
+	if !accessContracts(sc, name) {
+		log.WithFields(log.Fields{"type": consts.IncorrectCallingContract}).Errorf("%[1]s can be only called from @1%[1]s", name)
+		return fmt.Errorf(`%[1]s can be only called from %[1]s`, name)
+	}
+	if sc.TxSmart.KeyID != converter.StrToInt64(EcosysParam(sc, `founder_account`)) {
+		log.WithFields(log.Fields{"type": consts.AccessDenied, "key_id": sc.TxSmart.KeyID}).Error("changing domain by not founder")
+		return errAccessDenied
+	}
+	return nil
+}
+
+// SetDomain binds the hostname to the page of the ecosystem or its VDE. The default page is default_page.
+// The hostname bound to another ecosystem can't be taken until it is removed there.
+// It can be called by the founder only
+func SetDomain(sc *SmartContract, host, page string, vde int64) error {
+	if err := checkDomainAccess(sc, `SetDomain`); err != nil {
+		return err
+	}
+	host, err := NormalizeHost(host)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "error": err}).Error("checking hostname")
+		return err
+	}
+	if len(page) == 0 {
+		page = defaultDomainPage
+	}
+	if vde != 0 {
+		vde = 1
+	}
+	domain, found, err := getDomain(sc, host)
+	if err != nil {
+		return err
+	}
+	if found && domain.Ecosystem != 0 && domain.Ecosystem != sc.TxSmart.EcosystemID {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "host": host}).Error("domain is bound to another ecosystem")
+		return fmt.Errorf(`domain %s is bound to another ecosystem`, host)
+	}
+	var (
+		where    []string
+		whereVal []string
+	)
+	if found {
+		where, whereVal = []string{`id`}, []string{converter.Int64ToStr(domain.ID)}
+	}
+	_, _, err = sc.selectiveLoggingAndUpd([]string{`host`, `ecosystem`, `vde`, `page`, `block_id`},
+		[]interface{}{host, sc.TxSmart.EcosystemID, vde, page, currentBlockID(sc)}, `ecosystem_domains`,
+		where, whereVal, !sc.VDE && sc.Rollback, found)
+	return err
+}
+
+// RemoveDomain unbinds the hostname from the ecosystem. It can be called by the founder only
+func RemoveDomain(sc *SmartContract, host string) error {
+	if err := checkDomainAccess(sc, `RemoveDomain`); err != nil {
+		return err
+	}
+	host, err := NormalizeHost(host)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "error": err}).Error("checking hostname")
+		return err
+	}
+	domain, found, err := getDomain(sc, host)
+	if err != nil {
+		return err
+	}
+	if !found || domain.Ecosystem != sc.TxSmart.EcosystemID {
+		log.WithFields(log.Fields{"type": consts.NotFound, "host": host}).Error("domain not found")
+		return fmt.Errorf(`domain %s has not been found`, host)
+	}
+	_, _, err = sc.selectiveLoggingAndUpd([]string{`ecosystem`, `block_id`}, []interface{}{0, currentBlockID(sc)},
+		`ecosystem_domains`, []string{`id`}, []string{converter.Int64ToStr(domain.ID)}, !sc.VDE && sc.Rollback, true)
+	return err
+}
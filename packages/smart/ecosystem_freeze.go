@@ -0,0 +1,172 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	freezeContract   = `@1FreezeEcosystem`
+	unfreezeContract = `@1UnfreezeEcosystem`
+	archiveContract  = `@1ArchiveEcosystem`
+)
+
+var (
+	// ErrEcosystemFrozen is returned when a contract tries to change the data of frozen ecosystem
+	ErrEcosystemFrozen = errors.New(`Ecosystem is frozen`)
+	// ErrEcosystemArchived is returned when a contract tries to change the data of archived ecosystem
+	ErrEcosystemArchived = errors.New(`Ecosystem is archived`)
+)
+
+func getEcosystemState(sc *SmartContract, id int64) (*model.SystemState, error) {
+	state := &model.SystemState{}
+	found, err := state.Get(sc.DbTransaction, id)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err, "ecosystem": id}).Error("getting ecosystem")
+		return nil, err
+	}
+	if !found {
+		log.WithFields(log.Fields{"type": consts.NotFound, "ecosystem": id}).Error("ecosystem not found")
+		return nil, fmt.Errorf(`Ecosystem %d does not exist`, id)
+	}
+	return state, nil
+}
+
+func updateEcosystemState(sc *SmartContract, id int64, fields []string, values []interface{}) error {
+	_, _, err := sc.selectiveLoggingAndUpd(fields, values, `system_states`, []string{`id`},
+		[]string{converter.Int64ToStr(id)}, !sc.VDE && sc.Rollback, true)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err, "ecosystem": id}).Error("updating ecosystem state")
+	}
+	return err
+}
+
+func blockID(sc *SmartContract) int64 {
+	if sc.BlockData != nil && sc.BlockData.BlockID > 0 {
+		return sc.BlockData.BlockID
+	}
+	return 1
+}
+
+// FreezeEcosystem rejects all changes of the ecosystem except the ones made by the unfreeze contract
+func FreezeEcosystem(sc *SmartContract, id int64, contract string) error {
+	if sc.TxContract.Name != freezeContract {
+		log.WithFields(log.Fields{"type": consts.IncorrectCallingContract}).Error("FreezeEcosystem can be only called from " + freezeContract)
+		return fmt.Errorf(`FreezeEcosystem can be only called from %s`, freezeContract)
+	}
+	if id == 1 {
+		return fmt.Errorf(`The first ecosystem cannot be frozen`)
+	}
+	state, err := getEcosystemState(sc, id)
+	if err != nil {
+		return err
+	}
+	if state.Frozen > 0 {
+		return ErrEcosystemFrozen
+	}
+	contract = strings.TrimSpace(contract)
+	if len(contract) > 0 && !strings.HasPrefix(contract, `@`) {
+		contract = fmt.Sprintf(`@%d%s`, id, contract)
+	}
+	return updateEcosystemState(sc, id, []string{`frozen`, `unfreeze_contract`}, []interface{}{blockID(sc), contract})
+}
+
+// UnfreezeEcosystem allows the changes of the frozen ecosystem
+func UnfreezeEcosystem(sc *SmartContract, id int64) error {
+	state, err := getEcosystemState(sc, id)
+	if err != nil {
+		return err
+	}
+	name := sc.TxContract.Name
+	if name != unfreezeContract && (len(state.UnfreezeContract) == 0 || name != state.UnfreezeContract) {
+		log.WithFields(log.Fields{"type": consts.IncorrectCallingContract, "contract": name}).Error("UnfreezeEcosystem is called from wrong contract")
+		return fmt.Errorf(`UnfreezeEcosystem cannot be called from %s`, name)
+	}
+	if state.Archived > 0 {
+		return ErrEcosystemArchived
+	}
+	if state.Frozen == 0 {
+		return fmt.Errorf(`Ecosystem %d is not frozen`, id)
+	}
+	return updateEcosystemState(sc, id, []string{`frozen`, `unfreeze_contract`}, []interface{}{0, ``})
+}
+
+// ArchiveEcosystem marks the frozen ecosystem as archived, after that nodes can remove its tables
+func ArchiveEcosystem(sc *SmartContract, id int64) error {
+	if sc.TxContract.Name != archiveContract {
+		log.WithFields(log.Fields{"type": consts.IncorrectCallingContract}).Error("ArchiveEcosystem can be only called from " + archiveContract)
+		return fmt.Errorf(`ArchiveEcosystem can be only called from %s`, archiveContract)
+	}
+	state, err := getEcosystemState(sc, id)
+	if err != nil {
+		return err
+	}
+	if state.Frozen == 0 {
+		return fmt.Errorf(`Ecosystem %d is not frozen`, id)
+	}
+	if state.Archived > 0 {
+		return ErrEcosystemArchived
+	}
+	return updateEcosystemState(sc, id, []string{`archived`}, []interface{}{blockID(sc)})
+}
+
+// tableEcosystem returns the ecosystem of the table from its prefix
+func tableEcosystem(table string) int64 {
+	if off := strings.IndexByte(table, '_'); off > 0 {
+		return converter.StrToInt64(table[:off])
+	}
+	return 0
+}
+
+// checkFrozen returns an error if the table belongs to the frozen ecosystem
+func (sc *SmartContract) checkFrozen(table string) error {
+	id := tableEcosystem(table)
+	if sc.VDE || id <= 1 || sc.TxContract == nil {
+		return nil
+	}
+	state := &model.SystemState{}
+	found, err := state.Get(sc.DbTransaction, id)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err, "ecosystem": id}).Error("getting ecosystem")
+		return err
+	}
+	if !found || state.Frozen == 0 {
+		return nil
+	}
+	if state.Archived > 0 {
+		return ErrEcosystemArchived
+	}
+	if name := sc.TxContract.Name; name == unfreezeContract || name == state.UnfreezeContract {
+		return nil
+	}
+	return ErrEcosystemFrozen
+}
@@ -0,0 +1,113 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"encoding/hex"
+	"errors"
+
+	"github.com/GenesisKernel/go-genesis/packages/config/syspar"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrEcosystemQuota is returned if the key has already created the maximum number of ecosystems
+var ErrEcosystemQuota = errors.New(`The limit of ecosystems for the key has been reached`)
+
+// checkEcosystemQuota checks max_ecosystems_per_key system parameter, zero value means no limit
+func checkEcosystemQuota(sc *SmartContract, wallet int64) error {
+	limit := syspar.SysInt64(syspar.MaxEcosystemsPerKey)
+	if limit <= 0 {
+		return nil
+	}
+	count, err := model.CountEcosystemsByKey(sc.DbTransaction, wallet)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("counting ecosystems of key")
+		return err
+	}
+	if count >= limit {
+		log.WithFields(log.Fields{"type": consts.ParameterExceeded, "key_id": wallet, "limit": limit}).Error("ecosystem quota")
+		return ErrEcosystemQuota
+	}
+	return nil
+}
+
+// chargeEcosystemFee transfers ecosystem_fee tokens of the first ecosystem from the key to ecosystem_treasury.
+// The fee goes to the founder of the first ecosystem if the treasury is not specified
+func chargeEcosystemFee(sc *SmartContract, wallet, founder int64) error {
+	value := syspar.SysString(syspar.EcosystemFee)
+	if len(value) == 0 {
+		return nil
+	}
+	fee, err := decimal.NewFromString(value)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.ConversionError, "error": err, "value": value}).Error("converting ecosystem fee")
+		return err
+	}
+	if fee.Sign() <= 0 {
+		return nil
+	}
+	treasury := syspar.SysInt64(syspar.EcosystemTreasury)
+	if treasury == 0 {
+		treasury = founder
+	}
+	if treasury == wallet {
+		return nil
+	}
+	key := &model.Key{}
+	found, err := key.SetTablePrefix(1).GetTx(sc.DbTransaction, wallet)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting key")
+		return err
+	}
+	amount, err := decimal.NewFromString(key.Amount)
+	if !found || err != nil || amount.LessThan(fee) {
+		log.WithFields(log.Fields{"type": consts.NoFunds, "key_id": wallet, "fee": fee}).Error("paying ecosystem fee")
+		return ErrCurrentBalance
+	}
+	rollback := !sc.VDE && sc.Rollback
+	if _, _, err = sc.selectiveLoggingAndUpd([]string{`-amount`}, []interface{}{fee}, `1_keys`, []string{`id`},
+		[]string{converter.Int64ToStr(wallet)}, rollback, false); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("charging ecosystem fee")
+		return err
+	}
+	if _, _, err = sc.selectiveLoggingAndUpd([]string{`+amount`}, []interface{}{fee}, `1_keys`, []string{`id`},
+		[]string{converter.Int64ToStr(treasury)}, rollback, false); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("crediting ecosystem fee")
+		return err
+	}
+	var blockID int64
+	if sc.BlockData != nil {
+		blockID = sc.BlockData.BlockID
+	}
+	_, _, err = sc.selectiveLoggingAndUpd([]string{`sender_id`, `recipient_id`, `amount`, `comment`, `block_id`, `txhash`},
+		[]interface{}{wallet, treasury, fee, `Ecosystem fee`, blockID, hex.EncodeToString(sc.TxHash)}, `1_history`, nil, nil, rollback, false)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("inserting ecosystem fee into history")
+	}
+	return err
+}
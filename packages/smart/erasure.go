@@ -0,0 +1,166 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/crypto"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// redactedPrefix is the prefix of the digest which replaces the erased value in rollback records
+const redactedPrefix = `redacted:`
+
+// redactValue returns the digest of the personal value
+func redactValue(value string) (string, error) {
+	if len(value) == 0 || strings.HasPrefix(value, redactedPrefix) {
+		return value, nil
+	}
+	hash, err := crypto.Hash([]byte(value))
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("hashing personal value")
+		return ``, err
+	}
+	return redactedPrefix + hex.EncodeToString(hash), nil
+}
+
+// redactData replaces the personal columns in JSON data of the rollback record with their digests
+func redactData(data string, columns []string) (string, bool, error) {
+	var values map[string]string
+	if err := json.Unmarshal([]byte(data), &values); err != nil {
+		return ``, false, err
+	}
+	var changed bool
+	for _, column := range columns {
+		value, ok := values[column]
+		if !ok {
+			continue
+		}
+		redacted, err := redactValue(value)
+		if err != nil {
+			return ``, false, err
+		}
+		if redacted != value {
+			values[column] = redacted
+			changed = true
+		}
+	}
+	if !changed {
+		return data, false, nil
+	}
+	out, err := json.Marshal(values)
+	return string(out), true, err
+}
+
+// ErasePersonalData clears the personal columns of the row and replaces their previous values in
+// rollback records with digests. The erasure is allowed by 'erase' permission of the table and it
+// can't be rolled back. The hash of the erased values is written to the audit log
+func ErasePersonalData(sc *SmartContract, table string, id int64) error {
+	tblname := getDefTableName(sc, table)
+	perm, err := sc.AccessTablePerm(tblname, `erase`)
+	if err != nil {
+		return err
+	}
+	if len(perm[`erase`]) == 0 {
+		log.WithFields(log.Fields{"type": consts.AccessDenied, "table": tblname}).Error("erasure is not allowed")
+		return errAccessDenied
+	}
+	personal, err := sc.flaggedColumns(tblname, func(perm permColumn) bool { return perm.Personal })
+	if err != nil {
+		return err
+	}
+	if len(personal) == 0 {
+		log.WithFields(log.Fields{"type": consts.EmptyObject, "table": tblname}).Error("table has no personal columns")
+		return fmt.Errorf(`table %s has no personal columns`, table)
+	}
+	columns := make([]string, 0, len(personal))
+	for column := range personal {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	row, err := model.GetOneRowTransaction(sc.DbTransaction, `SELECT `+strings.Join(columns, `,`)+
+		` FROM "`+tblname+`" WHERE id = ?`, id).String()
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting personal data")
+		return err
+	}
+	if len(row) == 0 {
+		log.WithFields(log.Fields{"type": consts.NotFound, "table": tblname, "id": id}).Error("row not found")
+		return fmt.Errorf("Item %d has not been found", id)
+	}
+	set := make([]string, len(columns))
+	digests := make([]string, len(columns))
+	for i, column := range columns {
+		set[i] = `"` + column + `" = ''`
+		if digests[i], err = redactValue(row[column]); err != nil {
+			return err
+		}
+	}
+	if err = model.Update(sc.DbTransaction, tblname, strings.Join(set, `,`), fmt.Sprintf(`WHERE id = '%d'`, id)); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("erasing personal data")
+		return err
+	}
+
+	rollbacks, err := (&model.RollbackTx{}).GetByTableRow(sc.DbTransaction, tblname, converter.Int64ToStr(id))
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting rollback records")
+		return err
+	}
+	for i := range rollbacks {
+		data, changed, err := redactData(rollbacks[i].Data, columns)
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err}).Error("redacting rollback record")
+			return err
+		}
+		if !changed {
+			continue
+		}
+		if err = rollbacks[i].Redact(sc.DbTransaction, data); err != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("redacting rollback record")
+			return err
+		}
+	}
+
+	digest, err := crypto.Hash([]byte(strings.Join(digests, `,`)))
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("hashing erased data")
+		return err
+	}
+	audit := &model.AuditLog{Time: sc.TxSmart.Time, KeyID: sc.TxSmart.KeyID, Ecosystem: sc.TxSmart.EcosystemID,
+		Action: model.AuditErasure, Target: fmt.Sprintf(`%s.%d`, tblname, id), Digest: digest}
+	if err = audit.Create(sc.DbTransaction); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("writing audit log")
+		return err
+	}
+	return nil
+}
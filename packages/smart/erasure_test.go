@@ -0,0 +1,52 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRedactData(t *testing.T) {
+	data, changed, err := redactData(`{"name":"John","email":"john@example.com","amount":"10","phone":""}`,
+		[]string{`name`, `email`, `phone`, `address`})
+	if err != nil || !changed {
+		t.Fatalf(`unexpected result %v %v`, changed, err)
+	}
+	var values map[string]string
+	if err = json.Unmarshal([]byte(data), &values); err != nil {
+		t.Fatal(err)
+	}
+	for _, column := range []string{`name`, `email`} {
+		if !strings.HasPrefix(values[column], redactedPrefix) {
+			t.Errorf(`%s has not been redacted: %s`, column, values[column])
+		}
+	}
+	if values[`amount`] != `10` || values[`phone`] != `` {
+		t.Errorf(`wrong values %v`, values)
+	}
+	if _, changed, _ = redactData(data, []string{`name`, `email`}); changed {
+		t.Error(`redacted data has been changed twice`)
+	}
+}
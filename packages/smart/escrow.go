@@ -0,0 +1,175 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"fmt"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// currentBlockTime returns the time of the block which is being processed
+func currentBlockTime(sc *SmartContract) int64 {
+	if sc.BlockData != nil {
+		return sc.BlockData.Time
+	}
+	return sc.TxSmart.Time
+}
+
+func getEscrow(sc *SmartContract, id int64) (*model.Escrow, error) {
+	escrow := &model.Escrow{}
+	escrow.SetTablePrefix(sc.TxSmart.EcosystemID)
+	found, err := escrow.Get(sc.DbTransaction, id)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting escrow")
+		return nil, err
+	}
+	if !found {
+		log.WithFields(log.Fields{"type": consts.NotFound, "id": id}).Error("escrow not found")
+		return nil, fmt.Errorf(`escrow %d has not been found`, id)
+	}
+	if escrow.Status != model.EscrowOpen {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "id": id}).Error("escrow is closed")
+		return nil, fmt.Errorf(`escrow %d is closed`, id)
+	}
+	return escrow, nil
+}
+
+// closeEscrow pays the tokens of the escrow to the wallet and sets the status
+func closeEscrow(sc *SmartContract, escrow *model.Escrow, wallet, status int64) error {
+	if err := walletCredit(sc, sc.TxSmart.EcosystemID, wallet, escrow.Amount); err != nil {
+		return err
+	}
+	_, _, err := sc.selectiveLoggingAndUpd([]string{`status`}, []interface{}{status}, ecosystemTable(sc, `escrows`),
+		[]string{`id`}, []string{converter.Int64ToStr(escrow.ID)}, !sc.VDE && sc.Rollback, true)
+	return err
+}
+
+// CreateEscrow withdraws the amount from the wallet of the caller and holds it until
+// the arbiter releases it to the recipient or refunds it
+func CreateEscrow(sc *SmartContract, recipient, arbiter int64, amount string) (int64, error) {
+	if recipient == 0 || arbiter == 0 || recipient == sc.TxSmart.KeyID {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "recipient": recipient, "arbiter": arbiter}).Error("wrong escrow parties")
+		return 0, fmt.Errorf(`wrong recipient or arbiter`)
+	}
+	value, err := assetAmount(amount)
+	if err != nil {
+		return 0, err
+	}
+	if err = walletDebit(sc, sc.TxSmart.EcosystemID, sc.TxSmart.KeyID, value.String()); err != nil {
+		return 0, err
+	}
+	_, id, err := sc.selectiveLoggingAndUpd([]string{`sender`, `recipient`, `arbiter`, `amount`, `status`, `block_id`},
+		[]interface{}{sc.TxSmart.KeyID, recipient, arbiter, value, model.EscrowOpen, currentBlockID(sc)},
+		ecosystemTable(sc, `escrows`), nil, nil, !sc.VDE && sc.Rollback, false)
+	if err != nil {
+		return 0, err
+	}
+	return converter.StrToInt64(id), nil
+}
+
+// ReleaseEscrow pays the tokens of the escrow to the recipient. It can be called by the arbiter or the sender
+func ReleaseEscrow(sc *SmartContract, id int64) error {
+	escrow, err := getEscrow(sc, id)
+	if err != nil {
+		return err
+	}
+	if sc.TxSmart.KeyID != escrow.Arbiter && sc.TxSmart.KeyID != escrow.Sender {
+		log.WithFields(log.Fields{"type": consts.AccessDenied, "id": id}).Error("Access denied")
+		return errAccessDenied
+	}
+	return closeEscrow(sc, escrow, escrow.Recipient, model.EscrowReleased)
+}
+
+// RefundEscrow returns the tokens of the escrow to the sender. It can be called by the arbiter or the recipient
+func RefundEscrow(sc *SmartContract, id int64) error {
+	escrow, err := getEscrow(sc, id)
+	if err != nil {
+		return err
+	}
+	if sc.TxSmart.KeyID != escrow.Arbiter && sc.TxSmart.KeyID != escrow.Recipient {
+		log.WithFields(log.Fields{"type": consts.AccessDenied, "id": id}).Error("Access denied")
+		return errAccessDenied
+	}
+	return closeEscrow(sc, escrow, escrow.Sender, model.EscrowRefunded)
+}
+
+// CreateTimeLock withdraws the amount from the wallet of the caller. The recipient can claim it
+// when the blockchain reaches unlockBlock and unlockTime. Zero value means no restriction
+func CreateTimeLock(sc *SmartContract, recipient int64, amount string, unlockBlock, unlockTime int64) (int64, error) {
+	if recipient == 0 {
+		log.WithFields(log.Fields{"type": consts.InvalidObject}).Error("wrong recipient")
+		return 0, fmt.Errorf(`wrong recipient`)
+	}
+	if unlockBlock < 0 || unlockTime < 0 || (unlockBlock <= currentBlockID(sc) && unlockTime <= currentBlockTime(sc)) {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "unlock_block": unlockBlock, "unlock_time": unlockTime}).Error("wrong unlock condition")
+		return 0, fmt.Errorf(`unlock block or time must be in the future`)
+	}
+	value, err := assetAmount(amount)
+	if err != nil {
+		return 0, err
+	}
+	if err = walletDebit(sc, sc.TxSmart.EcosystemID, sc.TxSmart.KeyID, value.String()); err != nil {
+		return 0, err
+	}
+	_, id, err := sc.selectiveLoggingAndUpd([]string{`sender`, `recipient`, `amount`, `unlock_block`, `unlock_time`,
+		`claimed`, `block_id`}, []interface{}{sc.TxSmart.KeyID, recipient, value, unlockBlock, unlockTime, 0,
+		currentBlockID(sc)}, ecosystemTable(sc, `timelocks`), nil, nil, !sc.VDE && sc.Rollback, false)
+	if err != nil {
+		return 0, err
+	}
+	return converter.StrToInt64(id), nil
+}
+
+// ClaimTimeLock pays the unlocked tokens to the recipient who calls it
+func ClaimTimeLock(sc *SmartContract, id int64) error {
+	lock := &model.TimeLock{}
+	lock.SetTablePrefix(sc.TxSmart.EcosystemID)
+	found, err := lock.Get(sc.DbTransaction, id)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting time-lock")
+		return err
+	}
+	if !found || lock.Claimed != 0 {
+		log.WithFields(log.Fields{"type": consts.NotFound, "id": id}).Error("time-lock not found")
+		return fmt.Errorf(`time-lock %d has not been found or has been claimed`, id)
+	}
+	if sc.TxSmart.KeyID != lock.Recipient {
+		log.WithFields(log.Fields{"type": consts.AccessDenied, "id": id}).Error("Access denied")
+		return errAccessDenied
+	}
+	if currentBlockID(sc) < lock.UnlockBlock || currentBlockTime(sc) < lock.UnlockTime {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "id": id}).Error("time-lock is locked")
+		return fmt.Errorf(`time-lock %d is locked`, id)
+	}
+	if err = walletCredit(sc, sc.TxSmart.EcosystemID, lock.Recipient, lock.Amount); err != nil {
+		return err
+	}
+	_, _, err = sc.selectiveLoggingAndUpd([]string{`claimed`}, []interface{}{1}, ecosystemTable(sc, `timelocks`),
+		[]string{`id`}, []string{converter.Int64ToStr(id)}, !sc.VDE && sc.Rollback, true)
+	return err
+}
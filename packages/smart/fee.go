@@ -0,0 +1,211 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/GenesisKernel/go-genesis/packages/config/syspar"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// FeePayerCaller means that the fuel is always paid by the caller of the contract
+	FeePayerCaller = `caller`
+	// FeePayerContract means that the fuel of the activated contract is paid by its bound wallet
+	// and the fuel of other contracts is paid by the caller. It is the default policy
+	FeePayerContract = `contract`
+	// FeePayerSponsor means that the fuel is paid by the wallet from fee_sponsor parameter
+	FeePayerSponsor = `sponsor`
+)
+
+var (
+	// ErrFeeSponsor is returned if the sponsor policy is specified without the sponsor wallet
+	ErrFeeSponsor = errors.New(`Fee sponsor is undefined`)
+	// ErrMaxFee is returned if max_fee of the transaction doesn't cover the minimal payment
+	ErrMaxFee = errors.New(`Max fee is not enough`)
+)
+
+// getFeePolicy returns the fee policy of the ecosystem from fee_payer parameter and the sponsor wallet
+func getFeePolicy(sc *SmartContract) (policy string, sponsor int64, err error) {
+	policy = EcosysParam(sc, `fee_payer`)
+	switch policy {
+	case FeePayerCaller, FeePayerContract:
+	case FeePayerSponsor:
+		sponsor = converter.StringToAddress(EcosysParam(sc, `fee_sponsor`))
+		if sponsor == 0 {
+			log.WithFields(log.Fields{"type": consts.InvalidObject, "ecosystem": sc.TxSmart.EcosystemID}).Error("fee sponsor is undefined")
+			return ``, 0, ErrFeeSponsor
+		}
+	default:
+		if len(policy) > 0 {
+			log.WithFields(log.Fields{"type": consts.InvalidObject, "value": policy}).Warning("unknown fee policy, using default")
+		}
+		policy = FeePayerContract
+	}
+	return
+}
+
+// maxFeeFuel returns the amount of fuel which can be bought for maxFee tokens at fuelRate
+func maxFeeFuel(maxFee string, fuelRate decimal.Decimal) (int64, error) {
+	fee, err := decimal.NewFromString(maxFee)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.ConversionError, "error": err, "value": maxFee}).Error("converting max fee from string to decimal")
+		return 0, err
+	}
+	if fee.Sign() <= 0 {
+		return 0, ErrMaxFee
+	}
+	return fee.Div(fuelRate).Floor().IntPart(), nil
+}
+
+// limitFuelByFee decreases the fuel limit of the transaction so that the payment doesn't exceed max_fee.
+// The size of transaction and the price of contract must be covered by max_fee
+func (sc *SmartContract) limitFuelByFee(fuelRate decimal.Decimal, sizeFuel, price int64) error {
+	if len(sc.TxSmart.MaxFee) == 0 {
+		return nil
+	}
+	maxFuel, err := maxFeeFuel(sc.TxSmart.MaxFee, fuelRate)
+	if err != nil {
+		return err
+	}
+	if maxFuel < sizeFuel+price {
+		log.WithFields(log.Fields{"type": consts.ParameterExceeded, "max_fee": sc.TxSmart.MaxFee, "fuel_rate": fuelRate}).Error("max fee is not enough")
+		return ErrMaxFee
+	}
+	if limit := maxFuel - price; (*sc.TxContract.Extend)[`txcost`].(int64) > limit {
+		(*sc.TxContract.Extend)[`txcost`] = limit
+	}
+	return nil
+}
+
+// feeSplit is the transaction fee divided among the recipients according to fee_routing
+type feeSplit struct {
+	Producer     decimal.Decimal
+	Treasury     decimal.Decimal
+	Burn         decimal.Decimal
+	PerValidator decimal.Decimal
+}
+
+// splitFee divides the fee by the shares, the validators part is split equally among the validators.
+// The remainders of rounding and the validators part without validators are paid to the block producer
+func splitFee(fee decimal.Decimal, shares syspar.FeeShares, validators int) feeSplit {
+	percent := func(share int64) decimal.Decimal {
+		return fee.Mul(decimal.New(share, 0)).Div(decimal.New(100, 0)).Floor()
+	}
+	ret := feeSplit{Treasury: percent(shares.Treasury), Burn: percent(shares.Burn), PerValidator: decimal.Zero}
+	paid := ret.Treasury.Add(ret.Burn)
+	if validators > 0 {
+		ret.PerValidator = percent(shares.Validators).Div(decimal.New(int64(validators), 0)).Floor()
+		paid = paid.Add(ret.PerValidator.Mul(decimal.New(int64(validators), 0)))
+	}
+	ret.Producer = fee.Sub(paid)
+	return ret
+}
+
+// feeRewardsActive returns true if the fees of the producer and the validators are accrued as rewards
+// at the block, 0 activation means that they are paid to their wallets
+func feeRewardsActive(blockID, activation int64) bool {
+	return activation > 0 && blockID >= activation
+}
+
+// payFee charges the fee from the payer wallet. Since the block fee_reward_block the parts of the block producer
+// and the validators are accrued as their rewards, before it they are paid to their wallets.
+// The treasury part is paid to the wallet and the burned part is only charged.
+// The part of the recipient without a wallet isn't charged
+func (sc *SmartContract) payFee(fee decimal.Decimal, producer, payer int64) error {
+	validators := syspar.GetValidatorKeys()
+	split := splitFee(fee, syspar.GetFeeRouting(), len(validators))
+	walletTable := fmt.Sprintf(`%d_keys`, sc.TxSmart.TokenEcosystem)
+	if !feeRewardsActive(currentBlockID(sc), syspar.GetFeeRewardBlock()) {
+		return sc.payFeeToWallets(split, walletTable, producer, payer, validators)
+	}
+	charged := fee.Sub(split.Treasury)
+	if err := sc.accrueReward(producer, model.RewardProducer, split.Producer); err != nil {
+		return err
+	}
+	for _, keyID := range validators {
+		if err := sc.accrueReward(keyID, model.RewardValidator, split.PerValidator); err != nil {
+			return err
+		}
+	}
+	if split.Treasury.Sign() > 0 {
+		_, _, err := sc.selectiveLoggingAndUpd([]string{`+amount`}, []interface{}{split.Treasury}, walletTable,
+			[]string{`id`}, []string{syspar.GetCommissionWallet(sc.TxSmart.TokenEcosystem)}, true, true)
+		if err == nil {
+			charged = charged.Add(split.Treasury)
+		} else if err != errUpdNotExistRecord {
+			return err
+		}
+	}
+	if _, _, err := sc.selectiveLoggingAndUpd([]string{`-amount`}, []interface{}{charged}, walletTable, []string{`id`},
+		[]string{converter.Int64ToStr(payer)}, true, true); err != nil {
+		return err
+	}
+	log.WithFields(log.Fields{"treasury": split.Treasury, "burn": split.Burn, "validators": split.PerValidator}).Debug("Paid commission")
+	return nil
+}
+
+// payFeeToWallets transfers the fee from the payer wallet to the recipients of fee_routing, the burned part
+// is only charged. The part of the recipient without a wallet isn't charged
+func (sc *SmartContract) payFeeToWallets(split feeSplit, walletTable string, producer, payer int64, validators []int64) error {
+	charged := split.Burn
+	credit := func(wallet string, amount decimal.Decimal) error {
+		if amount.Sign() <= 0 {
+			return nil
+		}
+		if _, _, err := sc.selectiveLoggingAndUpd([]string{`+amount`}, []interface{}{amount}, walletTable,
+			[]string{`id`}, []string{wallet}, true, true); err != nil {
+			if err == errUpdNotExistRecord {
+				return nil
+			}
+			return err
+		}
+		charged = charged.Add(amount)
+		return nil
+	}
+	if err := credit(converter.Int64ToStr(producer), split.Producer); err != nil {
+		return err
+	}
+	for _, keyID := range validators {
+		if err := credit(converter.Int64ToStr(keyID), split.PerValidator); err != nil {
+			return err
+		}
+	}
+	if err := credit(syspar.GetCommissionWallet(sc.TxSmart.TokenEcosystem), split.Treasury); err != nil {
+		return err
+	}
+	if _, _, err := sc.selectiveLoggingAndUpd([]string{`-amount`}, []interface{}{charged}, walletTable, []string{`id`},
+		[]string{converter.Int64ToStr(payer)}, true, true); err != nil {
+		return err
+	}
+	log.WithFields(log.Fields{"producer": split.Producer, "treasury": split.Treasury, "burn": split.Burn,
+		"validators": split.PerValidator}).Debug("Paid commission")
+	return nil
+}
@@ -0,0 +1,69 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"testing"
+
+	"github.com/GenesisKernel/go-genesis/packages/config/syspar"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestSplitFee(t *testing.T) {
+	cases := []struct {
+		fee        int64
+		shares     syspar.FeeShares
+		validators int
+		want       [4]int64 // producer, treasury, burn, per validator
+	}{
+		{1000, syspar.FeeShares{Producer: 97, Treasury: 3}, 3, [4]int64{970, 30, 0, 0}},
+		{1000, syspar.FeeShares{Producer: 60, Validators: 30, Treasury: 5, Burn: 5}, 3, [4]int64{600, 50, 50, 100}},
+		{1000, syspar.FeeShares{Producer: 60, Validators: 30, Treasury: 10}, 7, [4]int64{606, 100, 0, 42}},
+		{1000, syspar.FeeShares{Producer: 50, Validators: 50}, 0, [4]int64{1000, 0, 0, 0}},
+		{99, syspar.FeeShares{Treasury: 50, Burn: 50}, 1, [4]int64{1, 49, 49, 0}},
+	}
+	for _, c := range cases {
+		split := splitFee(decimal.New(c.fee, 0), c.shares, c.validators)
+		got := [4]int64{split.Producer.IntPart(), split.Treasury.IntPart(), split.Burn.IntPart(), split.PerValidator.IntPart()}
+		if got != c.want {
+			t.Errorf("%d by %+v among %d: expected %v, got %v", c.fee, c.shares, c.validators, c.want, got)
+		}
+	}
+}
+
+func TestFeeRewardsActive(t *testing.T) {
+	for _, item := range []struct {
+		block, activation int64
+		active            bool
+	}{
+		{10, 0, false},
+		{10, 11, false},
+		{11, 11, true},
+		{12, 11, true},
+	} {
+		if active := feeRewardsActive(item.block, item.activation); active != item.active {
+			t.Errorf("block %d activation %d: expected %v", item.block, item.activation, item.active)
+		}
+	}
+}
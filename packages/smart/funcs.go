@@ -29,8 +29,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"io/ioutil"
-	"math/rand"
 	"net/http"
 	"net/url"
 	"reflect"
@@ -61,11 +59,18 @@ type permTable struct {
 	NewColumn string `json:"new_column"`
 	Read      string `json:"read,omitempty"`
 	Filter    string `json:"filter,omitempty"`
+	RowRead   string `json:"row_read,omitempty"`
+	RowUpdate string `json:"row_update,omitempty"`
+	Erase     string `json:"erase,omitempty"`
 }
 
 type permColumn struct {
-	Update string `json:"update"`
-	Read   string `json:"read,omitempty"`
+	Update    string `json:"update"`
+	Read      string `json:"read,omitempty"`
+	Encrypted bool   `json:"encrypted,omitempty"`
+	Personal  bool   `json:"personal,omitempty"`
+	Ref       string `json:"ref,omitempty"`
+	OnDelete  string `json:"on_delete,omitempty"`
 }
 
 // SmartContract is storing smart contract data
@@ -73,24 +78,36 @@ type SmartContract struct {
 	VDE           bool
 	Rollback      bool
 	SysUpdate     bool
+	AuditLog      []*model.AuditLog // records of the audit log which are written after the block is committed
 	VM            *script.VM
 	TxSmart       tx.SmartContract
 	TxData        map[string]interface{}
 	TxContract    *Contract
 	TxCost        int64           // Maximum cost of executing contract
 	TxUsedCost    decimal.Decimal // Used cost of CPU resources
+	TxPayer       int64           // Wallet which pays for the transaction
 	BlockData     *utils.BlockData
 	TxHash        []byte
 	PublicKeys    [][]byte
 	DbTransaction *model.DbTransaction
+	randSeed      []byte                 // seed of Random which is got from the block and the transaction
+	randCount     int64                  // number of Random calls in the transaction
+	txVars        map[string]interface{} // variables shared by the contracts of the transaction
 }
 
 var (
 	funcCallsDB = map[string]struct{}{
-		"DBInsert":    {},
-		"DBSelect":    {},
-		"DBUpdate":    {},
-		"DBUpdateExt": {},
+		"DBInsert":      {},
+		"DBSelect":      {},
+		"DBUpdate":      {},
+		"DBUpdateExt":   {},
+		"DBCount":       {},
+		"DBSum":         {},
+		"DBMin":         {},
+		"DBMax":         {},
+		"DBInsertBatch": {},
+		"DBUpdateBatch": {},
+		"DBRestore":     {},
 	}
 	extendCost = map[string]int64{
 		"AddressToId":        10,
@@ -111,6 +128,8 @@ var (
 		"HMac":               50,
 		"Join":               10,
 		"JSONToMap":          50,
+		"JSONValue":          50,
+		"JSONSet":            50,
 		"Sha256":             50,
 		"IdToAddress":        10,
 		"IsObject":           10,
@@ -160,6 +179,13 @@ func EmbedFuncs(vm *script.VM, vt script.VMType) {
 		"DBUpdate":           DBUpdate,
 		"DBUpdateSysParam":   UpdateSysParam,
 		"DBUpdateExt":        DBUpdateExt,
+		"DBCount":            DBCount,
+		"DBSum":              DBSum,
+		"DBMin":              DBMin,
+		"DBMax":              DBMax,
+		"DBInsertBatch":      DBInsertBatch,
+		"DBUpdateBatch":      DBUpdateBatch,
+		"DBRestore":          DBRestore,
 		"EcosysParam":        EcosysParam,
 		"SysParamString":     SysParamString,
 		"SysParamInt":        SysParamInt,
@@ -173,11 +199,25 @@ func EmbedFuncs(vm *script.VM, vt script.VMType) {
 		"HMac":               HMac,
 		"Join":               Join,
 		"JSONToMap":          JSONToMap,
+		"JSONValue":          JSONValue,
+		"JSONSet":            JSONSet,
+		"SetTxVar":           SetTxVar,
+		"GetTxVar":           GetTxVar,
+		"HasTxVar":           HasTxVar,
 		"IdToAddress":        IDToAddress,
 		"Int":                Int,
 		"IsObject":           IsObject,
 		"Len":                Len,
 		"Money":              Money,
+		"FormatMoney":        FormatMoney,
+		"ParseMoney":         ParseMoney,
+		"BlockTime":          BlockTime,
+		"ParseTime":          ParseTime,
+		"FormatTime":         FormatTime,
+		"AddTime":            AddTime,
+		"CmpTime":            CmpTime,
+		"OracleValue":        OracleValue,
+		"OracleIsFresh":      OracleIsFresh,
 		"PermColumn":         PermColumn,
 		"PermTable":          PermTable,
 		"Random":             Random,
@@ -190,11 +230,16 @@ func EmbedFuncs(vm *script.VM, vt script.VMType) {
 		"PubToID":            PubToID,
 		"HexToBytes":         HexToBytes,
 		"LangRes":            LangRes,
+		"LangDocument":       LangDocument,
+		"ParseLangDocument":  ParseLangDocument,
 		"HasPrefix":          strings.HasPrefix,
 		"ValidateCondition":  ValidateCondition,
 		"TrimSpace":          strings.TrimSpace,
 		"ToLower":            strings.ToLower,
 		"CreateEcosystem":    CreateEcosystem,
+		"FreezeEcosystem":    FreezeEcosystem,
+		"UnfreezeEcosystem":  UnfreezeEcosystem,
+		"ArchiveEcosystem":   ArchiveEcosystem,
 		"RollbackEcosystem":  RollbackEcosystem,
 		"RollbackTable":      RollbackTable,
 		"TableConditions":    TableConditions,
@@ -215,9 +260,69 @@ func EmbedFuncs(vm *script.VM, vt script.VMType) {
 		f["HTTPPostJSON"] = HTTPPostJSON
 		f["ValidateCron"] = ValidateCron
 		f["UpdateCron"] = UpdateCron
+		f["ValidateCronURL"] = ValidateCronURL
+		f["DecryptColumn"] = DecryptColumn
+		f["RotateDataKey"] = RotateDataKey
+		f["ErasePersonalData"] = ErasePersonalData
 		vmExtendCost(vm, getCost)
 		vmFuncCallsDB(vm, funcCallsDB)
 	case script.VMTypeSmart:
+		f["BridgeTransfer"] = BridgeTransfer
+		f["BridgeReceive"] = BridgeReceive
+		f["CreateAsset"] = CreateAsset
+		f["MintAsset"] = MintAsset
+		f["BurnAsset"] = BurnAsset
+		f["TransferAsset"] = TransferAsset
+		f["AssetBalance"] = AssetBalance
+		f["CreateNFTCollection"] = CreateNFTCollection
+		f["MintNFT"] = MintNFT
+		f["TransferNFT"] = TransferNFT
+		f["ApproveNFT"] = ApproveNFT
+		f["NFTInfo"] = NFTInfo
+		f["CreateEscrow"] = CreateEscrow
+		f["ReleaseEscrow"] = ReleaseEscrow
+		f["RefundEscrow"] = RefundEscrow
+		f["CreateTimeLock"] = CreateTimeLock
+		f["ClaimTimeLock"] = ClaimTimeLock
+		f["ImportKeys"] = ImportKeys
+		f["CreateInvite"] = CreateInvite
+		f["RedeemInvite"] = RedeemInvite
+		f["RevokeInvite"] = RevokeInvite
+		f["SetGuardians"] = SetGuardians
+		f["ApproveRecovery"] = ApproveRecovery
+		f["CancelRecovery"] = CancelRecovery
+		f["FinishRecovery"] = FinishRecovery
+		f["ClaimRewards"] = ClaimRewards
+		f["RegisterValidator"] = RegisterValidator
+		f["DeregisterValidator"] = DeregisterValidator
+		f["Bond"] = Bond
+		f["Unbond"] = Unbond
+		f["WithdrawUnbonded"] = WithdrawUnbonded
+		f["SlashValidator"] = SlashValidator
+		f["ElectValidators"] = ElectValidators
+		f["CreateProposal"] = CreateProposal
+		f["VoteProposal"] = VoteProposal
+		f["ApplyProposal"] = ApplyProposal
+		f["RoleAccess"] = RoleAccess
+		f["AssignRole"] = AssignRole
+		f["RevokeRole"] = RevokeRole
+		f["ErasePersonalData"] = ErasePersonalData
+		f["RegisterName"] = RegisterName
+		f["RenewName"] = RenewName
+		f["TransferName"] = TransferName
+		f["ResolveName"] = ResolveName
+		f["ApplicationVersion"] = ApplicationVersion
+		f["ApplicationHooks"] = ApplicationHooks
+		f["SetApplication"] = SetApplication
+		f["RemoveApplication"] = RemoveApplication
+		f["ScheduleSysParam"] = ScheduleSysParam
+		f["SetDomain"] = SetDomain
+		f["RemoveDomain"] = RemoveDomain
+		f["UploadAsset"] = UploadAsset
+		f["AlterColumnType"] = AlterColumnType
+		f["RemoveColumn"] = RemoveColumn
+		f["CreateTableIndex"] = CreateTableIndex
+		f["RemoveTableIndex"] = RemoveTableIndex
 		ExtendCost(getCostP)
 		FuncCallsDB(funcCallsDBP)
 	}
@@ -373,6 +478,34 @@ func CreateTable(sc *SmartContract, name string, columns, permissions string) er
 		}
 		colsSQL += `"` + colname + `" ` + colType + " " + colDef + " ,\n"
 		colperm[colname] = data[`conditions`]
+		if perm, err := getPermColumns(data[`conditions`]); err == nil && !strings.EqualFold(perm.Ref, name) {
+			if err = checkReference(sc, perm); err != nil {
+				return err
+			}
+		}
+		if data[`encrypted`] == `true` || data[`personal`] == `true` {
+			if data[`type`] != `varchar` && data[`type`] != `text` {
+				log.WithFields(log.Fields{"type": consts.InvalidObject, "column": colname}).Error("column can't be encrypted or personal")
+				return fmt.Errorf(`only text and varchar columns can be encrypted or personal`)
+			}
+			if data[`encrypted`] == `true` && !sc.VDE {
+				log.WithFields(log.Fields{"type": consts.InvalidObject, "column": colname}).Error("column can't be encrypted")
+				return errEncryptedVDE
+			}
+			perm, err := getPermColumns(data[`conditions`])
+			if err != nil {
+				log.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err}).Error("unmarshalling column permissions from json")
+				return err
+			}
+			perm.Encrypted = data[`encrypted`] == `true`
+			perm.Personal = data[`personal`] == `true`
+			out, err := json.Marshal(perm)
+			if err != nil {
+				log.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Error("marshalling column permissions to json")
+				return err
+			}
+			colperm[colname] = string(out)
+		}
 	}
 	colout, err := json.Marshal(colperm)
 	if err != nil {
@@ -459,6 +592,12 @@ func DBInsert(sc *SmartContract, tblname string, params string, val ...interface
 	if reflect.TypeOf(val[0]) == reflect.TypeOf([]interface{}{}) {
 		val = val[0].([]interface{})
 	}
+	if err = sc.checkReferences(tblname, strings.Split(params, `,`), val); err != nil {
+		return
+	}
+	if val, err = sc.encryptColumns(tblname, strings.Split(params, `,`), val); err != nil {
+		return
+	}
 	qcost, lastID, err = sc.selectiveLoggingAndUpd(strings.Split(params, `,`), val, tblname, nil,
 		nil, !sc.VDE && sc.Rollback, false)
 	if ind > 0 {
@@ -500,6 +639,9 @@ func DBSelect(sc *SmartContract, tblname string, columns string, id int64, order
 		ecosystem = sc.TxSmart.EcosystemID
 	}
 	tblname = GetTableName(sc, tblname, ecosystem)
+	if where, err = sc.filterDeleted(tblname, where); err != nil {
+		return 0, nil, err
+	}
 	if sc.VDE && *conf.CheckReadAccess && tblname != GetTableName(sc, "tables", ecosystem) {
 		perm, err = sc.AccessTablePerm(tblname, `read`)
 		if err != nil {
@@ -511,6 +653,18 @@ func DBSelect(sc *SmartContract, tblname string, columns string, id int64, order
 		}
 		columns = strings.Join(cols, `,`)
 	}
+	rowWhere, rowParams, err := sc.RowAccess(tblname, RowRead)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(rowWhere) > 0 {
+		if len(where) > 0 {
+			where = `(` + where + `) and ` + rowWhere
+		} else {
+			where = rowWhere
+		}
+		params = append(params, rowParams...)
+	}
 	rows, err = model.GetDB(sc.DbTransaction).Table(tblname).Select(columns).Where(where, params...).Order(order).
 		Offset(offset).Limit(limit).Rows()
 	if err != nil {
@@ -577,6 +731,23 @@ func DBUpdate(sc *SmartContract, tblname string, id int64, params string, val ..
 	if err = sc.AccessColumns(tblname, &columns, true); err != nil {
 		return
 	}
+	if err = sc.checkRowUpdate(tblname, `id = ?`, id); err != nil {
+		return
+	}
+	if err = sc.checkNotDeleted(tblname, id, columns); err != nil {
+		return
+	}
+	if err = sc.checkReferences(tblname, columns, val); err != nil {
+		return
+	}
+	if marker, ok := deletedMarker(columns, val); ok {
+		if err = sc.markDeleted(tblname, id, marker, 0); err != nil {
+			return
+		}
+	}
+	if val, err = sc.encryptColumns(tblname, columns, val); err != nil {
+		return
+	}
 	qcost, _, err = sc.selectiveLoggingAndUpd(columns, val, tblname, []string{`id`}, []string{converter.Int64ToStr(id)}, !sc.VDE && sc.Rollback, false)
 	return
 }
@@ -703,7 +874,14 @@ func TableConditions(sc *SmartContract, name, columns, permissions string) (err
 	for i := 0; i < v.NumField(); i++ {
 		cond := v.Field(i).Interface().(string)
 		name := v.Type().Field(i).Name
-		if len(cond) == 0 && name != `Read` && name != `Filter` {
+		if name == `RowRead` || name == `RowUpdate` {
+			if _, _, err = RowCondition(cond, 0, 0); err != nil {
+				log.WithFields(log.Fields{"condition_type": name, "type": consts.InvalidObject, "error": err}).Error("parsing row condition")
+				return err
+			}
+			continue
+		}
+		if len(cond) == 0 && name != `Read` && name != `Filter` && name != `Erase` {
 			log.WithFields(log.Fields{"condition_type": name, "type": consts.EmptyObject}).Error("condition is empty")
 			return fmt.Errorf(`%v condition is empty`, name)
 		}
@@ -823,6 +1001,9 @@ func ColumnCondition(sc *SmartContract, tableName, name, coltype, permissions st
 	if err = VMCompileEval(sc.VM, perm.Update, uint32(sc.TxSmart.EcosystemID)); err != nil {
 		return err
 	}
+	if err = checkReference(sc, perm); err != nil {
+		return err
+	}
 	if len(perm.Read) > 0 {
 		if err = VMCompileEval(sc.VM, perm.Read, uint32(sc.TxSmart.EcosystemID)); err != nil {
 			return err
@@ -887,6 +1068,14 @@ func CreateColumn(sc *SmartContract, tableName, name, coltype, permissions strin
 	name = strings.ToLower(name)
 	tableName = strings.ToLower(tableName)
 	tblname := getDefTableName(sc, tableName)
+	permissions, err := columnFlags(``, permissions)
+	if err != nil {
+		return err
+	}
+	if perm, _ := getPermColumns(permissions); perm.Personal && coltype != `varchar` && coltype != `text` {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "column": name}).Error("column can't be personal")
+		return fmt.Errorf(`only text and varchar columns can be encrypted or personal`)
+	}
 
 	var colType string
 	switch coltype {
@@ -907,7 +1096,7 @@ func CreateColumn(sc *SmartContract, tableName, name, coltype, permissions strin
 	default:
 		colType = coltype
 	}
-	err := model.AlterTableAddColumn(sc.DbTransaction, tblname, name, colType)
+	err = model.AlterTableAddColumn(sc.DbTransaction, tblname, name, colType)
 	if err != nil {
 		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("adding column to the table")
 		return err
@@ -943,6 +1132,33 @@ func CreateColumn(sc *SmartContract, tableName, name, coltype, permissions strin
 	return nil
 }
 
+// columnFlags returns the new permissions of the column with the flags of the old permissions.
+// The encryption of the column can't be changed and the personal flag can't be removed
+func columnFlags(old, permissions string) (string, error) {
+	oldPerm, err := getPermColumns(old)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err}).Error("unmarshalling column permissions from json")
+		return ``, err
+	}
+	newPerm, err := getPermColumns(permissions)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err}).Error("unmarshalling column permissions from json")
+		return ``, err
+	}
+	personal := oldPerm.Personal || newPerm.Personal
+	if newPerm.Encrypted == oldPerm.Encrypted && newPerm.Personal == personal {
+		return permissions, nil
+	}
+	newPerm.Encrypted = oldPerm.Encrypted
+	newPerm.Personal = personal
+	out, err := json.Marshal(newPerm)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Error("marshalling column permissions to json")
+		return ``, err
+	}
+	return string(out), nil
+}
+
 // PermColumn is contract func
 func PermColumn(sc *SmartContract, tableName, name, permissions string) error {
 	if !accessContracts(sc, `EditColumn`) {
@@ -967,6 +1183,9 @@ func PermColumn(sc *SmartContract, tableName, name, permissions string) error {
 		log.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err}).Error("unmarshalling columns permissions from json")
 		return err
 	}
+	if permissions, err = columnFlags(perm[name], permissions); err != nil {
+		return err
+	}
 	perm[name] = permissions
 	permout, err := json.Marshal(perm)
 	if err != nil {
@@ -1045,20 +1264,24 @@ func SortedKeys(m map[string]interface{}) []interface{} {
 	return ret
 }
 
-//Formats timestamp to specified date format
+//Formats timestamp to specified date format in UTC
 func Date(time_format string, timestamp int64) string {
-	t := time.Unix(timestamp, 0)
+	t := time.Unix(timestamp, 0).UTC()
 	return t.Format(time_format)
 }
 
-// HTTPRequest sends http request
-func HTTPRequest(requrl, method string, headers map[string]interface{},
+// HTTPRequest sends http request to the domain from allowlist of VDE
+func HTTPRequest(sc *SmartContract, requrl, method string, headers map[string]interface{},
 	params map[string]interface{}) (string, error) {
 
+	policy, err := getHTTPPolicy(sc)
+	if err != nil {
+		return ``, err
+	}
+
 	var ioform io.Reader
 
 	form := &url.Values{}
-	client := &http.Client{}
 	for key, v := range params {
 		form.Set(key, fmt.Sprint(v))
 	}
@@ -1074,28 +1297,15 @@ func HTTPRequest(requrl, method string, headers map[string]interface{},
 	for key, v := range headers {
 		req.Header.Set(key, fmt.Sprint(v))
 	}
-	resp, err := client.Do(req)
-	if err != nil {
-		log.WithFields(log.Fields{"type": consts.NetworkError, "error": err}).Error("http request")
-		return ``, err
-	}
-	defer resp.Body.Close()
-	data, err := ioutil.ReadAll(resp.Body)
+	return httpDo(policy, req)
+}
+
+// HTTPPostJSON sends post http request with json to the domain from allowlist of VDE
+func HTTPPostJSON(sc *SmartContract, requrl string, headers map[string]interface{}, json_str string) (string, error) {
+	policy, err := getHTTPPolicy(sc)
 	if err != nil {
-		log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("reading http answer")
 		return ``, err
 	}
-	if resp.StatusCode != http.StatusOK {
-		log.WithFields(log.Fields{"type": consts.NetworkError, "error": err}).Error("http status code")
-		return ``, fmt.Errorf(`%d %s`, resp.StatusCode, strings.TrimSpace(string(data)))
-	}
-	return string(data), nil
-}
-
-// HTTPPostJSON sends post http request with json
-func HTTPPostJSON(requrl string, headers map[string]interface{}, json_str string) (string, error) {
-
-	client := &http.Client{}
 
 	req, err := http.NewRequest("POST", requrl, bytes.NewBuffer([]byte(json_str)))
 	if err != nil {
@@ -1106,30 +1316,7 @@ func HTTPPostJSON(requrl string, headers map[string]interface{}, json_str string
 	for key, v := range headers {
 		req.Header.Set(key, fmt.Sprint(v))
 	}
-	resp, err := client.Do(req)
-	if err != nil {
-		log.WithFields(log.Fields{"type": consts.NetworkError, "error": err}).Error("http request")
-		return ``, err
-	}
-	defer resp.Body.Close()
-	data, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("reading http answer")
-		return ``, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		log.WithFields(log.Fields{"type": consts.NetworkError, "error": err}).Error("http status code")
-		return ``, fmt.Errorf(`%d %s`, resp.StatusCode, strings.TrimSpace(string(data)))
-	}
-	return string(data), nil
-}
-
-func Random(min int64, max int64) (int64, error) {
-	if min < 0 || max < 0 || min >= max {
-		log.WithFields(log.Fields{"type": consts.InvalidObject}).Error("getting random")
-		return 0, fmt.Errorf(`wrong random parameters %d %d`, min, max)
-	}
-	return min + rand.New(rand.NewSource(time.Now().Unix())).Int63n(max-min), nil
+	return httpDo(policy, req)
 }
 
 func ValidateCron(cronSpec string) error {
@@ -1140,6 +1327,32 @@ func ValidateCron(cronSpec string) error {
 	return nil
 }
 
+// ValidateCronURL checks the URL and the method of HTTP cron task
+func ValidateCronURL(rawURL, method string) error {
+	if len(rawURL) == 0 {
+		return nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.ParseError, "error": err}).Error("parsing cron url")
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "url": rawURL}).Error("cron url must be absolute http(s) url")
+		return fmt.Errorf(`wrong url %s`, rawURL)
+	}
+	if len(method) == 0 {
+		return nil
+	}
+	for _, m := range contract.HTTPMethods {
+		if strings.ToUpper(method) == m {
+			return nil
+		}
+	}
+	log.WithFields(log.Fields{"type": consts.InvalidObject, "method": method}).Error("unsupported cron http method")
+	return fmt.Errorf(`unsupported method %s`, method)
+}
+
 func UpdateCron(sc *SmartContract, id int64) error {
 	cronTask := &model.Cron{}
 	cronTask.SetTablePrefix(converter.Int64ToStr(sc.TxSmart.EcosystemID) + "_vde")
@@ -1157,9 +1370,7 @@ func UpdateCron(sc *SmartContract, id int64) error {
 	err = scheduler.UpdateTask(&scheduler.Task{
 		ID:       cronTask.UID(),
 		CronSpec: cronTask.Cron,
-		Handler: &contract.ContractHandler{
-			Contract: cronTask.Contract,
-		},
+		Handler:  contract.NewTaskHandler(cronTask, sc.TxSmart.EcosystemID),
 	})
 	if err != nil {
 		return err
@@ -0,0 +1,284 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"fmt"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// GovernanceBalance means that the weight of the vote is the balance of the voter
+	GovernanceBalance = `balance`
+	// GovernanceRole means that every member of governance_role has one vote
+	GovernanceRole = `role`
+
+	defaultThreshold = 50
+)
+
+// proposalPassed returns true if the votes reach the quorum and the share of the votes
+// for the proposal reaches the threshold in percent
+func proposalPassed(votesFor, votesAgainst, quorum decimal.Decimal, threshold int64) bool {
+	total := votesFor.Add(votesAgainst)
+	if total.Sign() <= 0 || total.Cmp(quorum) < 0 {
+		return false
+	}
+	return votesFor.Mul(decimal.New(100, 0)).Cmp(total.Mul(decimal.New(threshold, 0))) >= 0
+}
+
+func getProposal(sc *SmartContract, id int64) (*model.Proposal, error) {
+	proposal := &model.Proposal{}
+	proposal.SetTablePrefix(sc.TxSmart.EcosystemID)
+	found, err := proposal.Get(sc.DbTransaction, id)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting proposal")
+		return nil, err
+	}
+	if !found {
+		log.WithFields(log.Fields{"type": consts.NotFound, "id": id}).Error("proposal not found")
+		return nil, fmt.Errorf(`proposal %d has not been found`, id)
+	}
+	return proposal, nil
+}
+
+// voteWeight returns the weight of the vote of the wallet according to the strategy of the proposal
+func voteWeight(sc *SmartContract, proposal *model.Proposal, wallet int64) (decimal.Decimal, error) {
+	if proposal.Strategy == GovernanceRole {
+		roles, err := roleWithParents(sc, proposal.RoleID)
+		if err != nil {
+			return decimal.Zero, err
+		}
+		member, err := model.IsRoleMember(sc.DbTransaction, sc.TxSmart.EcosystemID, roles, wallet, currentBlockTime(sc))
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("checking role member")
+			return decimal.Zero, err
+		}
+		if member {
+			return decimal.New(1, 0), nil
+		}
+		return decimal.Zero, nil
+	}
+	key := &model.Key{}
+	key.SetTablePrefix(sc.TxSmart.EcosystemID)
+	found, err := key.GetTx(sc.DbTransaction, wallet)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting wallet")
+		return decimal.Zero, err
+	}
+	if !found {
+		return decimal.Zero, nil
+	}
+	weight, err := decimal.NewFromString(key.Amount)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.ConversionError, "error": err, "value": key.Amount}).Error("converting wallet amount from string to decimal")
+		return decimal.Zero, err
+	}
+	return weight, nil
+}
+
+// CreateProposal creates the proposal which is voted until endBlock. The voting strategy, the quorum and
+// the threshold are taken from governance_* parameters of the ecosystem. If paramName is specified then
+// the approved proposal changes the system parameter at applyBlock
+func CreateProposal(sc *SmartContract, title, description, paramName, paramValue string, endBlock, applyBlock int64) (int64, error) {
+	if len(title) == 0 {
+		log.WithFields(log.Fields{"type": consts.EmptyObject}).Error("proposal title is empty")
+		return 0, fmt.Errorf(`title is empty`)
+	}
+	if endBlock <= currentBlockID(sc) {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "end_block": endBlock}).Error("wrong end block")
+		return 0, fmt.Errorf(`end block must be in the future`)
+	}
+	if len(paramName) > 0 {
+		if sc.TxSmart.EcosystemID != 1 {
+			log.WithFields(log.Fields{"type": consts.AccessDenied, "ecosystem": sc.TxSmart.EcosystemID}).Error("system parameters can be changed only in the first ecosystem")
+			return 0, errAccessDenied
+		}
+		par := &model.SystemParameter{}
+		found, err := par.Get(paramName)
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("system parameter get")
+			return 0, err
+		}
+		if !found {
+			log.WithFields(log.Fields{"type": consts.NotFound, "name": paramName}).Error("system parameter get")
+			return 0, fmt.Errorf(`Parameter %s has not been found`, paramName)
+		}
+		if len(paramValue) == 0 {
+			log.WithFields(log.Fields{"type": consts.EmptyObject, "name": paramName}).Error("empty value of system parameter")
+			return 0, fmt.Errorf(`empty value of parameter %s`, paramName)
+		}
+		if err = checkSysParamValue(paramName, paramValue); err != nil {
+			return 0, err
+		}
+		if applyBlock < endBlock {
+			applyBlock = endBlock
+		}
+	} else {
+		paramValue = ``
+		applyBlock = 0
+	}
+	strategy := EcosysParam(sc, `governance_strategy`)
+	var roleID int64
+	switch strategy {
+	case GovernanceRole:
+		roleID = converter.StrToInt64(EcosysParam(sc, `governance_role`))
+		if roleID <= 0 {
+			log.WithFields(log.Fields{"type": consts.InvalidObject, "ecosystem": sc.TxSmart.EcosystemID}).Error("governance role is undefined")
+			return 0, fmt.Errorf(`governance role is undefined`)
+		}
+	default:
+		strategy = GovernanceBalance
+	}
+	quorum, err := decimal.NewFromString(EcosysParam(sc, `governance_quorum`))
+	if err != nil || quorum.Sign() < 0 {
+		quorum = decimal.Zero
+	}
+	threshold := converter.StrToInt64(EcosysParam(sc, `governance_threshold`))
+	if threshold <= 0 || threshold > 100 {
+		threshold = defaultThreshold
+	}
+	_, id, err := sc.selectiveLoggingAndUpd([]string{`creator`, `title`, `description`, `strategy`, `role_id`,
+		`quorum`, `threshold`, `end_block`, `param_name`, `param_value`, `apply_block`, `status`, `votes_for`,
+		`votes_against`, `block_id`}, []interface{}{sc.TxSmart.KeyID, title, description, strategy, roleID,
+		quorum, threshold, endBlock, paramName, paramValue, applyBlock, model.ProposalOpen, 0, 0, currentBlockID(sc)},
+		ecosystemTable(sc, `proposals`), nil, nil, !sc.VDE && sc.Rollback, false)
+	if err != nil {
+		return 0, err
+	}
+	return converter.StrToInt64(id), nil
+}
+
+// VoteProposal votes for or against the open proposal. Every wallet can vote only once
+func VoteProposal(sc *SmartContract, id, approve int64) error {
+	proposal, err := getProposal(sc, id)
+	if err != nil {
+		return err
+	}
+	if proposal.Status != model.ProposalOpen || currentBlockID(sc) >= proposal.EndBlock {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "id": id}).Error("voting is over")
+		return fmt.Errorf(`voting for proposal %d is over`, id)
+	}
+	vote := &model.ProposalVote{}
+	vote.SetTablePrefix(sc.TxSmart.EcosystemID)
+	voted, err := vote.Get(sc.DbTransaction, id, sc.TxSmart.KeyID)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting proposal vote")
+		return err
+	}
+	if voted {
+		log.WithFields(log.Fields{"type": consts.DuplicateObject, "id": id}).Error("wallet has already voted")
+		return fmt.Errorf(`you have already voted for proposal %d`, id)
+	}
+	weight, err := voteWeight(sc, proposal, sc.TxSmart.KeyID)
+	if err != nil {
+		return err
+	}
+	if weight.Sign() <= 0 {
+		log.WithFields(log.Fields{"type": consts.AccessDenied, "id": id}).Error("wallet has no voting weight")
+		return fmt.Errorf(`you have no right to vote for proposal %d`, id)
+	}
+	if approve != 0 {
+		approve = 1
+	}
+	if _, _, err = sc.selectiveLoggingAndUpd([]string{`proposal_id`, `voter`, `weight`, `approve`, `block_id`},
+		[]interface{}{id, sc.TxSmart.KeyID, weight, approve, currentBlockID(sc)},
+		ecosystemTable(sc, `proposal_votes`), nil, nil, !sc.VDE && sc.Rollback, false); err != nil {
+		return err
+	}
+	field := `+votes_against`
+	if approve == 1 {
+		field = `+votes_for`
+	}
+	_, _, err = sc.selectiveLoggingAndUpd([]string{field}, []interface{}{weight.String()}, ecosystemTable(sc, `proposals`),
+		[]string{`id`}, []string{converter.Int64ToStr(id)}, !sc.VDE && sc.Rollback, true)
+	return err
+}
+
+func setProposalStatus(sc *SmartContract, id, status int64) error {
+	_, _, err := sc.selectiveLoggingAndUpd([]string{`status`}, []interface{}{status}, ecosystemTable(sc, `proposals`),
+		[]string{`id`}, []string{converter.Int64ToStr(id)}, !sc.VDE && sc.Rollback, true)
+	return err
+}
+
+// ApplyProposal counts the votes of the proposal when the voting is over and applies the change
+// of the approved proposal when the blockchain reaches its apply block. Anyone can call it.
+// It returns the new status of the proposal
+func ApplyProposal(sc *SmartContract, id int64) (int64, error) {
+	proposal, err := getProposal(sc, id)
+	if err != nil {
+		return 0, err
+	}
+	blockID := currentBlockID(sc)
+	switch proposal.Status {
+	case model.ProposalOpen:
+		if blockID < proposal.EndBlock {
+			log.WithFields(log.Fields{"type": consts.InvalidObject, "id": id}).Error("voting is not over")
+			return 0, fmt.Errorf(`voting for proposal %d is not over`, id)
+		}
+		votesFor, errFor := decimal.NewFromString(proposal.VotesFor)
+		votesAgainst, errAgainst := decimal.NewFromString(proposal.VotesAgainst)
+		quorum, errQuorum := decimal.NewFromString(proposal.Quorum)
+		for _, err = range []error{errFor, errAgainst, errQuorum} {
+			if err != nil {
+				log.WithFields(log.Fields{"type": consts.ConversionError, "error": err, "id": id}).Error("converting votes from string to decimal")
+				return 0, err
+			}
+		}
+		if !proposalPassed(votesFor, votesAgainst, quorum, proposal.Threshold) {
+			return model.ProposalRejected, setProposalStatus(sc, id, model.ProposalRejected)
+		}
+		if len(proposal.ParamName) == 0 || blockID < proposal.ApplyBlock {
+			return model.ProposalApproved, setProposalStatus(sc, id, model.ProposalApproved)
+		}
+	case model.ProposalApproved:
+		if len(proposal.ParamName) == 0 {
+			return proposal.Status, nil
+		}
+		if blockID < proposal.ApplyBlock {
+			log.WithFields(log.Fields{"type": consts.InvalidObject, "id": id}).Error("apply block is not reached")
+			return 0, fmt.Errorf(`proposal %d can be applied at block %d`, id, proposal.ApplyBlock)
+		}
+	default:
+		return proposal.Status, nil
+	}
+	par := &model.SystemParameter{}
+	found, err := par.Get(proposal.ParamName)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("system parameter get")
+		return 0, err
+	}
+	if !found {
+		log.WithFields(log.Fields{"type": consts.NotFound, "name": proposal.ParamName}).Error("system parameter get")
+		return 0, fmt.Errorf(`Parameter %s has not been found`, proposal.ParamName)
+	}
+	if _, err = setSysParam(sc, par, proposal.ParamValue, ``); err != nil {
+		return 0, err
+	}
+	return model.ProposalApplied, setProposalStatus(sc, id, model.ProposalApplied)
+}
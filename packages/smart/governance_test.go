@@ -0,0 +1,51 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestProposalPassed(t *testing.T) {
+	for _, item := range []struct {
+		votesFor, votesAgainst, quorum int64
+		threshold                      int64
+		want                           bool
+	}{
+		{0, 0, 0, 50, false},
+		{1, 0, 0, 50, true},
+		{5, 5, 0, 50, true},
+		{5, 5, 0, 51, false},
+		{60, 40, 100, 60, true},
+		{60, 39, 100, 60, false},
+		{100, 0, 0, 100, true},
+	} {
+		got := proposalPassed(decimal.New(item.votesFor, 0), decimal.New(item.votesAgainst, 0),
+			decimal.New(item.quorum, 0), item.threshold)
+		if got != item.want {
+			t.Errorf(`%+v: got %v`, item, got)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/netguard"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// httpTimeoutParam is the VDE parameter with timeout of http requests in seconds
+	httpTimeoutParam = `http_timeout`
+
+	defaultHTTPTimeout = 10 * time.Second
+	maxHTTPTimeout     = 60 * time.Second
+	maxHTTPResponse    = 1 << 20
+)
+
+var errHTTPNotAllowed = errors.New(`http requests are allowed only in VDE`)
+
+func parseHTTPTimeout(value string) time.Duration {
+	seconds, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || seconds <= 0 {
+		return defaultHTTPTimeout
+	}
+	timeout := time.Duration(seconds) * time.Second
+	if timeout > maxHTTPTimeout {
+		return maxHTTPTimeout
+	}
+	return timeout
+}
+
+// httpDo sends the request if the url is allowed and returns the limited body of the answer
+func httpDo(policy *netguard.Policy, req *http.Request) (string, error) {
+	if err := policy.CheckURL(req.URL); err != nil {
+		log.WithFields(log.Fields{"type": consts.AccessDenied, "error": err}).Error("http request")
+		return ``, err
+	}
+	resp, err := policy.Client().Do(req)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.NetworkError, "error": err}).Error("http request")
+		return ``, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxHTTPResponse+1))
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("reading http answer")
+		return ``, err
+	}
+	if len(data) > maxHTTPResponse {
+		log.WithFields(log.Fields{"type": consts.ParameterExceeded, "size": len(data)}).Error("http answer is too large")
+		return ``, fmt.Errorf(`http answer is larger than %d bytes`, maxHTTPResponse)
+	}
+	if resp.StatusCode != http.StatusOK {
+		log.WithFields(log.Fields{"type": consts.NetworkError, "status": resp.StatusCode}).Error("http status code")
+		return ``, fmt.Errorf(`%d %s`, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return string(data), nil
+}
+
+// getHTTPPolicy reads the http allowlist and the timeout from the parameters of VDE
+func getHTTPPolicy(sc *SmartContract) (*netguard.Policy, error) {
+	if !sc.VDE {
+		return nil, errHTTPNotAllowed
+	}
+	sp := &model.StateParameter{}
+	sp.SetTablePrefix(converter.Int64ToStr(sc.TxSmart.EcosystemID) + `_vde`)
+	// the allowlist of VDE contracts may include the local services of the node
+	policy := &netguard.Policy{Timeout: defaultHTTPTimeout, AllowPrivate: true}
+
+	found, err := sp.Get(sc.DbTransaction, netguard.AllowlistParam)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting http allowlist")
+		return nil, err
+	}
+	if found {
+		policy.Allowlist = netguard.ParseAllowlist(sp.Value)
+	}
+
+	sp = &model.StateParameter{}
+	sp.SetTablePrefix(converter.Int64ToStr(sc.TxSmart.EcosystemID) + `_vde`)
+	found, err = sp.Get(sc.DbTransaction, httpTimeoutParam)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting http timeout")
+		return nil, err
+	}
+	if found {
+		policy.Timeout = parseHTTPTimeout(sp.Value)
+	}
+	return policy, nil
+}
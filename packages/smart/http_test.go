@@ -0,0 +1,49 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseHTTPTimeout(t *testing.T) {
+	cases := map[string]time.Duration{
+		``:     defaultHTTPTimeout,
+		`-1`:   defaultHTTPTimeout,
+		`abc`:  defaultHTTPTimeout,
+		`5`:    5 * time.Second,
+		`3600`: maxHTTPTimeout,
+	}
+	for value, timeout := range cases {
+		if v := parseHTTPTimeout(value); v != timeout {
+			t.Errorf("%q: expected %v, got %v", value, timeout, v)
+		}
+	}
+}
+
+func TestHTTPRequestNotVDE(t *testing.T) {
+	if _, err := HTTPRequest(&SmartContract{}, `http://localhost`, `GET`, nil, nil); err != errHTTPNotAllowed {
+		t.Errorf("expected %v, got %v", errHTTPNotAllowed, err)
+	}
+}
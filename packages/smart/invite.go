@@ -0,0 +1,212 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/crypto"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrInviteCode is returned if the invite code can't be redeemed
+var ErrInviteCode = errors.New(`Invite code is invalid, expired or has already been used`)
+
+// InviteHash returns the hash of the invite code which is stored in the blockchain
+func InviteHash(code string) (string, error) {
+	hash, err := crypto.Hash([]byte(strings.TrimSpace(code)))
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("hashing invite code")
+		return ``, err
+	}
+	return hex.EncodeToString(hash), nil
+}
+
+// parseInviteRoles returns the identifiers of the roles separated by comma
+func parseInviteRoles(roles string) ([]int64, error) {
+	var ret []int64
+	for _, item := range strings.Split(roles, `,`) {
+		if item = strings.TrimSpace(item); len(item) == 0 {
+			continue
+		}
+		id := converter.StrToInt64(item)
+		if id <= 0 {
+			return nil, fmt.Errorf(`wrong role %s`, item)
+		}
+		ret = append(ret, id)
+	}
+	return ret, nil
+}
+
+// isInviteActive returns true if the invite code can be redeemed at the time
+func isInviteActive(invite *model.InviteCode, now int64) bool {
+	return invite.RedeemedBy == 0 && invite.Revoked == 0 && (invite.Expire == 0 || invite.Expire > now)
+}
+
+// CreateInvite stores the hash of the invite code of the ecosystem. The member which redeems the code gets
+// the roles and the starter balance from the wallet of the founder. It can be called by the founder only
+func CreateInvite(sc *SmartContract, hash, roles, amount string, expire int64) (int64, error) {
+	if sc.TxSmart.KeyID != converter.StrToInt64(EcosysParam(sc, `founder_account`)) {
+		log.WithFields(log.Fields{"type": consts.AccessDenied, "key_id": sc.TxSmart.KeyID}).Error("creating invite by not founder")
+		return 0, errAccessDenied
+	}
+	hash = strings.ToLower(strings.TrimSpace(hash))
+	if bhash, err := hex.DecodeString(hash); err != nil || len(bhash) != 32 {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "hash": hash}).Error("wrong hash of invite code")
+		return 0, fmt.Errorf(`wrong hash of invite code`)
+	}
+	ids, err := parseInviteRoles(roles)
+	if err != nil {
+		return 0, err
+	}
+	for _, id := range ids {
+		if _, err = getRole(sc, id); err != nil {
+			return 0, err
+		}
+	}
+	value := decimal.Zero
+	if len(amount) > 0 && amount != `0` {
+		if value, err = assetAmount(amount); err != nil {
+			return 0, err
+		}
+	}
+	if expire < 0 || (expire > 0 && expire <= currentBlockTime(sc)) {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "expire": expire}).Error("wrong expiration time")
+		return 0, fmt.Errorf(`expiration time must be in the future`)
+	}
+	invite := &model.InviteCode{}
+	found, err := invite.GetByHash(sc.DbTransaction, hash)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting invite code")
+		return 0, err
+	}
+	if found {
+		log.WithFields(log.Fields{"type": consts.DuplicateObject, "hash": hash}).Error("invite code already exists")
+		return 0, fmt.Errorf(`invite code already exists`)
+	}
+	list := make([]string, len(ids))
+	for i, id := range ids {
+		list[i] = converter.Int64ToStr(id)
+	}
+	_, id, err := sc.selectiveLoggingAndUpd([]string{`ecosystem`, `hash`, `roles`, `amount`, `expire`, `created_by`},
+		[]interface{}{sc.TxSmart.EcosystemID, hash, strings.Join(list, `,`), value.String(), expire, sc.TxSmart.KeyID},
+		model.InviteCode{}.TableName(), nil, nil, !sc.VDE && sc.Rollback, false)
+	if err != nil {
+		return 0, err
+	}
+	return converter.StrToInt64(id), nil
+}
+
+// RedeemInvite registers the public key in the ecosystem of the invite code, assigns the preset roles
+// and transfers the starter balance. The code can be redeemed once
+func RedeemInvite(sc *SmartContract, code, publicKey string) (int64, error) {
+	pub, err := hex.DecodeString(publicKey)
+	if err != nil || len(pub) != 64 {
+		log.WithFields(log.Fields{"type": consts.InvalidObject}).Error("wrong public key of member")
+		return 0, fmt.Errorf(`wrong public key`)
+	}
+	hash, err := InviteHash(code)
+	if err != nil {
+		return 0, err
+	}
+	invite := &model.InviteCode{}
+	found, err := invite.GetByHash(sc.DbTransaction, hash)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting invite code")
+		return 0, err
+	}
+	if !found || invite.Ecosystem != sc.TxSmart.EcosystemID || !isInviteActive(invite, currentBlockTime(sc)) {
+		log.WithFields(log.Fields{"type": consts.NotFound, "hash": hash}).Error("invite code can't be redeemed")
+		return 0, ErrInviteCode
+	}
+	member := crypto.Address(pub)
+	key := &model.Key{}
+	key.SetTablePrefix(invite.Ecosystem)
+	if found, err = key.GetTx(sc.DbTransaction, member); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting wallet")
+		return 0, err
+	}
+	if found {
+		log.WithFields(log.Fields{"type": consts.DuplicateObject, "wallet": member}).Error("key has already been registered")
+		return 0, fmt.Errorf(`key %s has already been registered`, converter.AddressToString(member))
+	}
+	amount, _ := decimal.NewFromString(invite.Amount)
+	if amount.Sign() > 0 {
+		if err = walletDebit(sc, invite.Ecosystem, invite.CreatedBy, amount.String()); err != nil {
+			return 0, err
+		}
+	} else {
+		amount = decimal.Zero
+	}
+	if _, _, err = sc.selectiveLoggingAndUpd([]string{`id`, `pub`, `amount`}, []interface{}{member, pub, amount.String()},
+		fmt.Sprintf(`%d_keys`, invite.Ecosystem), nil, nil, !sc.VDE && sc.Rollback, false); err != nil {
+		return 0, err
+	}
+	roles, err := parseInviteRoles(invite.Roles)
+	if err != nil {
+		return 0, err
+	}
+	for _, role := range roles {
+		if _, err = assignRole(sc, role, member, 0); err != nil {
+			return 0, err
+		}
+	}
+	if _, _, err = sc.selectiveLoggingAndUpd([]string{`redeemed_by`, `redeemed_at`},
+		[]interface{}{member, currentBlockTime(sc)}, invite.TableName(), []string{`id`},
+		[]string{converter.Int64ToStr(invite.ID)}, !sc.VDE && sc.Rollback, true); err != nil {
+		return 0, err
+	}
+	return member, nil
+}
+
+// RevokeInvite cancels the invite code which hasn't been redeemed. It can be called by the founder only
+func RevokeInvite(sc *SmartContract, id int64) error {
+	if sc.TxSmart.KeyID != converter.StrToInt64(EcosysParam(sc, `founder_account`)) {
+		log.WithFields(log.Fields{"type": consts.AccessDenied, "key_id": sc.TxSmart.KeyID}).Error("revoking invite by not founder")
+		return errAccessDenied
+	}
+	invite := &model.InviteCode{}
+	found, err := invite.Get(sc.DbTransaction, id)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting invite code")
+		return err
+	}
+	if !found || invite.Ecosystem != sc.TxSmart.EcosystemID {
+		log.WithFields(log.Fields{"type": consts.NotFound, "id": id}).Error("invite code not found")
+		return fmt.Errorf(`invite code %d has not been found`, id)
+	}
+	if invite.RedeemedBy != 0 || invite.Revoked != 0 {
+		return fmt.Errorf(`invite code %d has already been used or revoked`, id)
+	}
+	_, _, err = sc.selectiveLoggingAndUpd([]string{`revoked`}, []interface{}{1}, invite.TableName(), []string{`id`},
+		[]string{converter.Int64ToStr(id)}, !sc.VDE && sc.Rollback, true)
+	return err
+}
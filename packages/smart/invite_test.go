@@ -0,0 +1,60 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/GenesisKernel/go-genesis/packages/model"
+)
+
+func TestParseInviteRoles(t *testing.T) {
+	ids, err := parseInviteRoles(` 2, 5,,7 `)
+	if err != nil || !reflect.DeepEqual(ids, []int64{2, 5, 7}) {
+		t.Errorf(`wrong roles %v %v`, ids, err)
+	}
+	if ids, err = parseInviteRoles(``); err != nil || len(ids) != 0 {
+		t.Errorf(`wrong empty roles %v %v`, ids, err)
+	}
+	if _, err = parseInviteRoles(`2,admin`); err == nil {
+		t.Error(`expected error of wrong role`)
+	}
+}
+
+func TestIsInviteActive(t *testing.T) {
+	for i, item := range []struct {
+		invite model.InviteCode
+		want   bool
+	}{
+		{model.InviteCode{}, true},
+		{model.InviteCode{Expire: 200}, true},
+		{model.InviteCode{Expire: 100}, false},
+		{model.InviteCode{RedeemedBy: 10}, false},
+		{model.InviteCode{Revoked: 1}, false},
+	} {
+		if got := isInviteActive(&item.invite, 100); got != item.want {
+			t.Errorf(`%d: got %v want %v`, i, got, item.want)
+		}
+	}
+}
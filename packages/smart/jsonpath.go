@@ -0,0 +1,130 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func unmarshalJSON(input string) (interface{}, error) {
+	var ret interface{}
+	if len(strings.TrimSpace(input)) == 0 {
+		return ret, nil
+	}
+	if err := json.Unmarshal([]byte(input), &ret); err != nil {
+		log.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err}).Error("unmarshalling json")
+		return nil, err
+	}
+	return ret, nil
+}
+
+// JSONValue returns the value of JSON by the path. Empty string is returned if there is no such value
+func JSONValue(input, path string) (interface{}, error) {
+	keys, err := converter.JSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+	value, err := unmarshalJSON(input)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			value = v[key]
+		case []interface{}:
+			ind, err := strconv.Atoi(key)
+			if err != nil || ind >= len(v) {
+				return ``, nil
+			}
+			value = v[ind]
+		default:
+			return ``, nil
+		}
+	}
+	if value == nil {
+		return ``, nil
+	}
+	return value, nil
+}
+
+// setPath sets the value of the object by the keys creating the missing objects
+func setPath(obj interface{}, keys []string, value interface{}) (interface{}, error) {
+	if len(keys) == 0 {
+		return value, nil
+	}
+	switch v := obj.(type) {
+	case []interface{}:
+		ind, err := strconv.Atoi(keys[0])
+		if err != nil || ind > len(v) {
+			return nil, fmt.Errorf(`wrong index %s of array`, keys[0])
+		}
+		if ind == len(v) {
+			v = append(v, nil)
+		}
+		if v[ind], err = setPath(v[ind], keys[1:], value); err != nil {
+			return nil, err
+		}
+		return v, nil
+	case map[string]interface{}:
+		item, err := setPath(v[keys[0]], keys[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		v[keys[0]] = item
+		return v, nil
+	case nil:
+		return setPath(map[string]interface{}{}, keys, value)
+	}
+	return nil, fmt.Errorf(`%s is not an object`, keys[0])
+}
+
+// JSONSet sets the value of JSON by the path and returns the new JSON.
+// The missing objects are created, the item can be appended to the array by the index which equals its length
+func JSONSet(input, path string, value interface{}) (string, error) {
+	keys, err := converter.JSONPath(path)
+	if err != nil {
+		return ``, err
+	}
+	obj, err := unmarshalJSON(input)
+	if err != nil {
+		return ``, err
+	}
+	if obj, err = setPath(obj, keys, value); err != nil {
+		return ``, err
+	}
+	out, err := json.Marshal(obj)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Error("marshalling json")
+		return ``, err
+	}
+	return string(out), nil
+}
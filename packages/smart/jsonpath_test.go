@@ -0,0 +1,63 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import "testing"
+
+func TestJSONPath(t *testing.T) {
+	src := `{"name":"John","address":{"city":"Paris"},"phones":["123","456"]}`
+	for path, want := range map[string]interface{}{
+		`name`:         `John`,
+		`address.city`: `Paris`,
+		`phones.1`:     `456`,
+		`phones.2`:     ``,
+		`address.zip`:  ``,
+		`name.first`:   ``,
+	} {
+		if got, err := JSONValue(src, path); err != nil || got != want {
+			t.Errorf(`%s: got %v want %v (%v)`, path, got, want, err)
+		}
+	}
+	if _, err := JSONValue(src, `address..city`); err == nil {
+		t.Error(`expected error of path`)
+	}
+	for _, item := range []struct {
+		src, path string
+		value     interface{}
+		want      string
+	}{
+		{``, `a.b`, int64(1), `{"a":{"b":1}}`},
+		{src, `address.city`, `Berlin`, `{"address":{"city":"Berlin"},"name":"John","phones":["123","456"]}`},
+		{src, `phones.2`, `789`, `{"address":{"city":"Paris"},"name":"John","phones":["123","456","789"]}`},
+		{`[]`, `0.name`, `x`, `[{"name":"x"}]`},
+	} {
+		if got, err := JSONSet(item.src, item.path, item.value); err != nil || got != item.want {
+			t.Errorf(`%s: got %s want %s (%v)`, item.path, got, item.want, err)
+		}
+	}
+	for _, path := range []string{`name.first`, `phones.5`, `phones.x`} {
+		if _, err := JSONSet(src, path, 1); err == nil {
+			t.Errorf(`%s: expected error`, path)
+		}
+	}
+}
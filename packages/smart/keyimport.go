@@ -0,0 +1,181 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/crypto"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+)
+
+// MaxImportKeys is the limit of keys which can be imported by one transaction
+const MaxImportKeys = 1000
+
+// KeyImport is the key which is registered by the bulk import
+type KeyImport struct {
+	PublicKey string  `json:"public_key"`
+	Amount    string  `json:"amount"`
+	Roles     []int64 `json:"roles"`
+	ID        int64   `json:"-"`
+	pub       []byte
+}
+
+// ParseKeyImport parses the list of imported keys. The data is either JSON array of objects or CSV
+// with public_key,amount,roles columns where the roles are separated by semicolon.
+// The header line of CSV is optional
+func ParseKeyImport(data string) ([]KeyImport, error) {
+	var list []KeyImport
+	data = strings.TrimSpace(data)
+	if strings.HasPrefix(data, `[`) {
+		if err := json.Unmarshal([]byte(data), &list); err != nil {
+			log.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err}).Error("unmarshalling imported keys")
+			return nil, err
+		}
+	} else {
+		reader := csv.NewReader(strings.NewReader(data))
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		records, err := reader.ReadAll()
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.ParseError, "error": err}).Error("reading imported keys")
+			return nil, err
+		}
+		for i, record := range records {
+			if i == 0 && strings.EqualFold(strings.TrimSpace(record[0]), `public_key`) {
+				continue
+			}
+			item := KeyImport{PublicKey: record[0]}
+			if len(record) > 1 {
+				item.Amount = record[1]
+			}
+			if len(record) > 2 {
+				for _, role := range strings.Split(record[2], `;`) {
+					if role = strings.TrimSpace(role); len(role) > 0 {
+						item.Roles = append(item.Roles, converter.StrToInt64(role))
+					}
+				}
+			}
+			list = append(list, item)
+		}
+	}
+	if len(list) == 0 {
+		return nil, fmt.Errorf(`there are no keys to import`)
+	}
+	if len(list) > MaxImportKeys {
+		return nil, fmt.Errorf(`too many keys. Limit is %d`, MaxImportKeys)
+	}
+	ids := make(map[int64]bool)
+	for i := range list {
+		item := &list[i]
+		pub, err := hex.DecodeString(strings.TrimSpace(item.PublicKey))
+		if err != nil || len(pub) != 64 {
+			return nil, fmt.Errorf(`wrong public key in line %d`, i+1)
+		}
+		item.pub = pub
+		item.ID = crypto.Address(pub)
+		if ids[item.ID] {
+			return nil, fmt.Errorf(`duplicate key %s`, converter.AddressToString(item.ID))
+		}
+		ids[item.ID] = true
+		item.Amount = strings.TrimSpace(item.Amount)
+		if len(item.Amount) == 0 {
+			item.Amount = `0`
+		}
+		amount, err := decimal.NewFromString(item.Amount)
+		if err != nil || amount.Sign() < 0 || !amount.Equal(amount.Floor()) {
+			return nil, fmt.Errorf(`wrong amount %s in line %d`, item.Amount, i+1)
+		}
+		item.Amount = amount.String()
+		for _, role := range item.Roles {
+			if role <= 0 {
+				return nil, fmt.Errorf(`wrong role in line %d`, i+1)
+			}
+		}
+	}
+	return list, nil
+}
+
+// ImportTotal returns the sum of the initial balances of the imported keys
+func ImportTotal(list []KeyImport) decimal.Decimal {
+	total := decimal.Zero
+	for _, item := range list {
+		amount, _ := decimal.NewFromString(item.Amount)
+		total = total.Add(amount)
+	}
+	return total
+}
+
+// ImportKeys registers the batch of keys in the ecosystem, assigns the roles to them and transfers
+// the initial balances from the wallet of the founder. It can be called by the founder only
+func ImportKeys(sc *SmartContract, data string) (int64, error) {
+	if sc.TxSmart.KeyID != converter.StrToInt64(EcosysParam(sc, `founder_account`)) {
+		log.WithFields(log.Fields{"type": consts.AccessDenied, "key_id": sc.TxSmart.KeyID}).Error("importing keys by not founder")
+		return 0, errAccessDenied
+	}
+	list, err := ParseKeyImport(data)
+	if err != nil {
+		return 0, err
+	}
+	ecosystem := sc.TxSmart.EcosystemID
+	for _, item := range list {
+		key := &model.Key{}
+		key.SetTablePrefix(ecosystem)
+		found, err := key.GetTx(sc.DbTransaction, item.ID)
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting wallet")
+			return 0, err
+		}
+		if found {
+			log.WithFields(log.Fields{"type": consts.DuplicateObject, "wallet": item.ID}).Error("key has already been registered")
+			return 0, fmt.Errorf(`key %s has already been registered`, converter.AddressToString(item.ID))
+		}
+	}
+	if total := ImportTotal(list); total.Sign() > 0 {
+		if err = walletDebit(sc, ecosystem, sc.TxSmart.KeyID, total.String()); err != nil {
+			return 0, err
+		}
+	}
+	for _, item := range list {
+		if _, _, err = sc.selectiveLoggingAndUpd([]string{`id`, `pub`, `amount`},
+			[]interface{}{item.ID, item.pub, item.Amount}, fmt.Sprintf(`%d_keys`, ecosystem), nil, nil,
+			!sc.VDE && sc.Rollback, false); err != nil {
+			return 0, err
+		}
+		for _, role := range item.Roles {
+			if _, err = assignRole(sc, role, item.ID, 0); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return int64(len(list)), nil
+}
@@ -0,0 +1,62 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseKeyImport(t *testing.T) {
+	pub1 := strings.Repeat(`a1`, 64)
+	pub2 := strings.Repeat(`b2`, 64)
+	csvData := "public_key,amount,roles\n" + pub1 + ",100,2;3\n" + pub2 + "\n"
+	jsonData := `[{"public_key":"` + pub1 + `","amount":"100","roles":[2,3]},{"public_key":"` + pub2 + `"}]`
+	for _, data := range []string{csvData, jsonData} {
+		list, err := ParseKeyImport(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(list) != 2 || list[0].Amount != `100` || list[1].Amount != `0` ||
+			!reflect.DeepEqual(list[0].Roles, []int64{2, 3}) || len(list[1].Roles) != 0 ||
+			list[0].ID == 0 || list[0].ID == list[1].ID {
+			t.Errorf(`wrong result %+v`, list)
+		}
+		if total := ImportTotal(list); total.String() != `100` {
+			t.Errorf(`wrong total %s`, total)
+		}
+	}
+	for _, data := range []string{
+		``,
+		`abcd,10`,
+		pub1 + ",-5",
+		pub1 + ",1.5",
+		pub1 + ",1,x",
+		pub1 + "\n" + pub1,
+	} {
+		if _, err := ParseKeyImport(data); err == nil {
+			t.Errorf(`%s: expected error`, data)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"encoding/json"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/language"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ecosystemPrefix returns the prefix of the tables of the current ecosystem
+func ecosystemPrefix(sc *SmartContract) string {
+	prefix := converter.Int64ToStr(sc.TxSmart.EcosystemID)
+	if sc.VDE {
+		prefix += `_vde`
+	}
+	return prefix
+}
+
+// LangDocument returns all language resources of the ecosystem as json or csv document
+func LangDocument(sc *SmartContract, format string) (string, error) {
+	doc, err := language.LoadDocument(ecosystemPrefix(sc))
+	if err != nil {
+		return ``, err
+	}
+	return doc.Marshal(format)
+}
+
+// ParseLangDocument parses json or csv document of language resources and returns the list of
+// the new and changed resources. Each item contains Name, Trans and Action (new or edit) fields
+func ParseLangDocument(sc *SmartContract, document, format string) ([]interface{}, error) {
+	doc, err := language.ParseDocument(document, format)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.ParseError, "error": err}).Error("parsing language document")
+		return nil, err
+	}
+	current, err := language.LoadDocument(ecosystemPrefix(sc))
+	if err != nil {
+		return nil, err
+	}
+	diff := doc.Diff(current)
+	result := make([]interface{}, 0, len(diff.Added)+len(diff.Changed))
+	for _, names := range [][]string{diff.Added, diff.Changed} {
+		for _, name := range names {
+			trans, err := json.Marshal(doc[name])
+			if err != nil {
+				log.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Error("marshalling language resource")
+				return nil, err
+			}
+			action := `new`
+			if _, ok := current[name]; ok {
+				action = `edit`
+			}
+			result = append(result, map[string]interface{}{
+				`Name`:   name,
+				`Trans`:  string(trans),
+				`Action`: action,
+			})
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,80 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"fmt"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// symbolAfter is the value of money_symbol_position parameter when the symbol follows the amount
+const symbolAfter = `after`
+
+// GetMoneyFormat returns the money format of the ecosystem with the specified table prefix
+func GetMoneyFormat(prefix string) (*converter.MoneyFormat, error) {
+	list, err := model.GetAll(`SELECT name, value FROM "`+prefix+`_parameters" WHERE name IN ('money_digit',
+		'money_decimal_separator', 'money_thousands_separator', 'money_symbol', 'money_symbol_position')`, -1)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting money parameters")
+		return nil, err
+	}
+	format := converter.DefaultMoneyFormat(0)
+	for _, item := range list {
+		switch item[`name`] {
+		case `money_digit`:
+			format.Digits = int32(converter.StrToInt(item[`value`]))
+		case `money_decimal_separator`:
+			format.DecimalSeparator = item[`value`]
+		case `money_thousands_separator`:
+			format.ThousandsSeparator = item[`value`]
+		case `money_symbol`:
+			format.Symbol = item[`value`]
+		case `money_symbol_position`:
+			format.SymbolAfter = item[`value`] == symbolAfter
+		}
+	}
+	return format, nil
+}
+
+// FormatMoney returns the amount in minimal units formatted according to the parameters of the ecosystem
+func FormatMoney(sc *SmartContract, value interface{}) (string, error) {
+	format, err := GetMoneyFormat(ecosystemPrefix(sc))
+	if err != nil {
+		return ``, err
+	}
+	return format.Format(fmt.Sprint(value))
+}
+
+// ParseMoney converts the amount formatted according to the parameters of the ecosystem into minimal units
+func ParseMoney(sc *SmartContract, text string) (string, error) {
+	format, err := GetMoneyFormat(ecosystemPrefix(sc))
+	if err != nil {
+		return ``, err
+	}
+	return format.Parse(text)
+}
@@ -0,0 +1,250 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const maxNFTURILength = 2048
+
+var (
+	regexpNFTCollection = regexp.MustCompile(`^[\w\-]{1,100}$`)
+	regexpNFTHash       = regexp.MustCompile(`^([0-9a-fA-F]{64})?$`)
+)
+
+func getNFTCollection(sc *SmartContract, id int64, name string) (*model.NFTCollection, error) {
+	var (
+		found bool
+		err   error
+	)
+	collection := &model.NFTCollection{}
+	collection.SetTablePrefix(sc.TxSmart.EcosystemID)
+	if id != 0 {
+		found, err = collection.Get(sc.DbTransaction, id)
+	} else {
+		found, err = collection.GetByName(sc.DbTransaction, name)
+	}
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting nft collection")
+		return nil, err
+	}
+	if !found {
+		log.WithFields(log.Fields{"type": consts.NotFound, "id": id, "name": name}).Error("nft collection not found")
+		return nil, fmt.Errorf(`collection %s has not been found`, name)
+	}
+	return collection, nil
+}
+
+func getNFT(sc *SmartContract, id int64) (*model.NFT, error) {
+	nft := &model.NFT{}
+	nft.SetTablePrefix(sc.TxSmart.EcosystemID)
+	found, err := nft.Get(sc.DbTransaction, id)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting nft")
+		return nil, err
+	}
+	if !found {
+		log.WithFields(log.Fields{"type": consts.NotFound, "id": id}).Error("nft not found")
+		return nil, fmt.Errorf(`NFT %d has not been found`, id)
+	}
+	return nft, nil
+}
+
+// checkNFTConditions evaluates the conditions of the collection. Empty conditions allow the action only to owner
+func checkNFTConditions(sc *SmartContract, conditions string, owner int64) error {
+	if len(conditions) == 0 {
+		if sc.TxSmart.KeyID != owner {
+			log.WithFields(log.Fields{"type": consts.AccessDenied}).Error("Access denied")
+			return errAccessDenied
+		}
+		return nil
+	}
+	ret, err := sc.EvalIf(conditions)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.EvalError, "error": err}).Error("evaluating nft conditions")
+		return err
+	}
+	if !ret {
+		log.WithFields(log.Fields{"type": consts.AccessDenied}).Error("Access denied")
+		return errAccessDenied
+	}
+	return nil
+}
+
+// CreateNFTCollection registers the new collection of non-fungible assets. The caller becomes the owner of the collection.
+// If maxSupply is zero then the count of assets is unlimited. Mint conditions restrict the issue of assets and
+// transfer conditions are checked at every transfer, for example, they can require the payment of royalties
+func CreateNFTCollection(sc *SmartContract, name string, maxSupply int64, mintConditions, transferConditions string) (int64, error) {
+	if !regexpNFTCollection.MatchString(name) {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "name": name}).Error("wrong collection name")
+		return 0, fmt.Errorf(`wrong collection name %s`, name)
+	}
+	if maxSupply < 0 {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "max_supply": maxSupply}).Error("wrong max supply")
+		return 0, fmt.Errorf(`wrong max supply %d`, maxSupply)
+	}
+	for _, cond := range []string{mintConditions, transferConditions} {
+		if len(cond) > 0 {
+			if err := ValidateCondition(sc, cond, sc.TxSmart.EcosystemID); err != nil {
+				return 0, err
+			}
+		}
+	}
+	collection := &model.NFTCollection{}
+	collection.SetTablePrefix(sc.TxSmart.EcosystemID)
+	found, err := collection.GetByName(sc.DbTransaction, name)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting nft collection")
+		return 0, err
+	}
+	if found {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "name": name}).Error("collection already exists")
+		return 0, fmt.Errorf(`collection %s already exists`, name)
+	}
+	_, id, err := sc.selectiveLoggingAndUpd([]string{`name`, `supply`, `max_supply`, `owner`, `mint_conditions`,
+		`transfer_conditions`, `block_id`}, []interface{}{name, 0, maxSupply, sc.TxSmart.KeyID, mintConditions,
+		transferConditions, currentBlockID(sc)}, ecosystemTable(sc, `nft_collections`), nil, nil, !sc.VDE && sc.Rollback, false)
+	if err != nil {
+		return 0, err
+	}
+	return converter.StrToInt64(id), nil
+}
+
+// MintNFT issues the new asset of the collection to the recipient. The hash is SHA256 of the metadata and can be empty
+func MintNFT(sc *SmartContract, collectionName string, recipient int64, uri, hash string) (int64, error) {
+	collection, err := getNFTCollection(sc, 0, collectionName)
+	if err != nil {
+		return 0, err
+	}
+	if err = checkNFTConditions(sc, collection.MintConditions, collection.Owner); err != nil {
+		return 0, err
+	}
+	if recipient == 0 {
+		log.WithFields(log.Fields{"type": consts.InvalidObject}).Error("wrong recipient")
+		return 0, fmt.Errorf(`wrong recipient`)
+	}
+	if len(uri) > maxNFTURILength || !regexpNFTHash.MatchString(hash) {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "hash": hash}).Error("wrong nft metadata")
+		return 0, fmt.Errorf(`wrong metadata`)
+	}
+	if collection.MaxSupply > 0 && collection.Supply >= collection.MaxSupply {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "name": collectionName}).Error("max supply of collection is exceeded")
+		return 0, fmt.Errorf(`max supply of %s is exceeded`, collectionName)
+	}
+	_, _, err = sc.selectiveLoggingAndUpd([]string{`+supply`}, []interface{}{1}, ecosystemTable(sc, `nft_collections`),
+		[]string{`id`}, []string{converter.Int64ToStr(collection.ID)}, !sc.VDE && sc.Rollback, true)
+	if err != nil {
+		return 0, err
+	}
+	_, id, err := sc.selectiveLoggingAndUpd([]string{`collection_id`, `owner`, `approved`, `uri`, `hash`, `block_id`},
+		[]interface{}{collection.ID, recipient, 0, uri, hash, currentBlockID(sc)}, ecosystemTable(sc, `nfts`),
+		nil, nil, !sc.VDE && sc.Rollback, false)
+	if err != nil {
+		return 0, err
+	}
+	nftID := converter.StrToInt64(id)
+	return nftID, nftHistory(sc, nftID, 0, recipient)
+}
+
+func nftHistory(sc *SmartContract, id, sender, recipient int64) error {
+	_, _, err := sc.selectiveLoggingAndUpd([]string{`nft_id`, `sender`, `recipient`, `block_id`},
+		[]interface{}{id, sender, recipient, currentBlockID(sc)}, ecosystemTable(sc, `nft_history`),
+		nil, nil, !sc.VDE && sc.Rollback, false)
+	return err
+}
+
+// TransferNFT moves the asset to the recipient. The caller must be the owner or the approved wallet.
+// The approval is reset after the transfer
+func TransferNFT(sc *SmartContract, id, recipient int64) error {
+	nft, err := getNFT(sc, id)
+	if err != nil {
+		return err
+	}
+	if sc.TxSmart.KeyID != nft.Owner && (nft.Approved == 0 || sc.TxSmart.KeyID != nft.Approved) {
+		log.WithFields(log.Fields{"type": consts.AccessDenied, "id": id}).Error("Access denied")
+		return errAccessDenied
+	}
+	if recipient == 0 || recipient == nft.Owner {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "recipient": recipient}).Error("wrong recipient")
+		return fmt.Errorf(`wrong recipient`)
+	}
+	collection, err := getNFTCollection(sc, nft.CollectionID, converter.Int64ToStr(nft.CollectionID))
+	if err != nil {
+		return err
+	}
+	if len(collection.TransferConditions) > 0 {
+		if err = checkNFTConditions(sc, collection.TransferConditions, collection.Owner); err != nil {
+			return err
+		}
+	}
+	_, _, err = sc.selectiveLoggingAndUpd([]string{`owner`, `approved`}, []interface{}{recipient, 0},
+		ecosystemTable(sc, `nfts`), []string{`id`}, []string{converter.Int64ToStr(id)}, !sc.VDE && sc.Rollback, true)
+	if err != nil {
+		return err
+	}
+	return nftHistory(sc, id, nft.Owner, recipient)
+}
+
+// ApproveNFT allows the spender to transfer the asset of the caller. Zero spender revokes the approval
+func ApproveNFT(sc *SmartContract, id, spender int64) error {
+	nft, err := getNFT(sc, id)
+	if err != nil {
+		return err
+	}
+	if sc.TxSmart.KeyID != nft.Owner {
+		log.WithFields(log.Fields{"type": consts.AccessDenied, "id": id}).Error("Access denied")
+		return errAccessDenied
+	}
+	_, _, err = sc.selectiveLoggingAndUpd([]string{`approved`}, []interface{}{spender},
+		ecosystemTable(sc, `nfts`), []string{`id`}, []string{converter.Int64ToStr(id)}, !sc.VDE && sc.Rollback, true)
+	return err
+}
+
+// NFTInfo returns the asset with the name of its collection
+func NFTInfo(sc *SmartContract, id int64) (map[string]interface{}, error) {
+	nft, err := getNFT(sc, id)
+	if err != nil {
+		return nil, err
+	}
+	collection, err := getNFTCollection(sc, nft.CollectionID, converter.Int64ToStr(nft.CollectionID))
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		`id`:         nft.ID,
+		`collection`: collection.Name,
+		`owner`:      nft.Owner,
+		`approved`:   nft.Approved,
+		`uri`:        nft.URI,
+		`hash`:       nft.Hash,
+		`block_id`:   nft.BlockID,
+	}, nil
+}
@@ -0,0 +1,43 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNFTMetadata(t *testing.T) {
+	for name, ok := range map[string]bool{`Art`: true, `art_2018`: true, `my-art`: true, ``: false,
+		`my art`: false, strings.Repeat(`a`, 101): false} {
+		if regexpNFTCollection.MatchString(name) != ok {
+			t.Errorf(`collection %s: want %v`, name, ok)
+		}
+	}
+	for hash, ok := range map[string]bool{``: true, strings.Repeat(`a1`, 32): true, `abc`: false,
+		strings.Repeat(`zz`, 32): false} {
+		if regexpNFTHash.MatchString(hash) != ok {
+			t.Errorf(`hash %s: want %v`, hash, ok)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"fmt"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// oracleTime returns the time which is used for staleness checks of oracle data
+func oracleTime(sc *SmartContract) int64 {
+	if sc.BlockData != nil {
+		return sc.BlockData.Time
+	}
+	return sc.TxSmart.Time
+}
+
+func getOracleValue(sc *SmartContract, feed string) (*model.OracleValue, error) {
+	value := &model.OracleValue{}
+	found, err := value.GetByFeed(sc.DbTransaction, feed)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting oracle value")
+		return nil, err
+	}
+	if !found {
+		log.WithFields(log.Fields{"type": consts.NotFound, "feed": feed}).Error("oracle feed not found")
+		return nil, fmt.Errorf(`oracle feed %s has not been found`, feed)
+	}
+	return value, nil
+}
+
+// OracleValue returns the latest value of the oracle feed with its attestation.
+// If maxAge is greater than zero, the value older than maxAge seconds is considered stale
+func OracleValue(sc *SmartContract, feed string, maxAge int64) (map[string]interface{}, error) {
+	value, err := getOracleValue(sc, feed)
+	if err != nil {
+		return nil, err
+	}
+	if maxAge > 0 && oracleTime(sc)-value.DataTime > maxAge {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "feed": feed, "data_time": value.DataTime}).Error("oracle value is stale")
+		return nil, fmt.Errorf(`oracle feed %s is stale`, feed)
+	}
+	return map[string]interface{}{
+		`feed`:      value.Feed,
+		`value`:     value.Value,
+		`data_time`: value.DataTime,
+		`key_id`:    value.KeyID,
+		`block_id`:  value.BlockID,
+		`source_tx`: value.SourceTx,
+		`signature`: value.Signature,
+	}, nil
+}
+
+// OracleIsFresh returns true if the value of the oracle feed isn't older than maxAge seconds
+func OracleIsFresh(sc *SmartContract, feed string, maxAge int64) (bool, error) {
+	value, err := getOracleValue(sc, feed)
+	if err != nil {
+		return false, err
+	}
+	return oracleTime(sc)-value.DataTime <= maxAge, nil
+}
@@ -0,0 +1,80 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// randomSeed returns the hash of the previous block joined with the id of the current block
+// and the hash of the transaction. The hash of the current block isn't known while it is generated
+func randomSeed(sc *SmartContract) ([]byte, error) {
+	seed := sha256.New()
+	if sc.BlockData != nil && sc.BlockData.BlockID > 1 {
+		prev := &model.Block{}
+		found, err := prev.Get(sc.BlockData.BlockID - 1)
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting previous block")
+			return nil, err
+		}
+		if !found {
+			log.WithFields(log.Fields{"type": consts.NotFound, "block_id": sc.BlockData.BlockID - 1}).Error("previous block not found")
+			return nil, fmt.Errorf(`block %d has not been found`, sc.BlockData.BlockID-1)
+		}
+		seed.Write(prev.Hash)
+		binary.Write(seed, binary.BigEndian, sc.BlockData.BlockID)
+	}
+	seed.Write(sc.TxHash)
+	return seed.Sum(nil), nil
+}
+
+// Random returns the number from min up to max (exclusive). The number is derived from the block,
+// the transaction, the optional seed values and the count of previous calls, so all nodes get the same values
+func Random(sc *SmartContract, min int64, max int64, seed ...interface{}) (int64, error) {
+	if min < 0 || max < 0 || min >= max {
+		log.WithFields(log.Fields{"type": consts.InvalidObject}).Error("getting random")
+		return 0, fmt.Errorf(`wrong random parameters %d %d`, min, max)
+	}
+	if sc.randSeed == nil {
+		var err error
+		if sc.randSeed, err = randomSeed(sc); err != nil {
+			return 0, err
+		}
+	}
+	hash := sha256.New()
+	hash.Write(sc.randSeed)
+	binary.Write(hash, binary.BigEndian, sc.randCount)
+	for _, item := range seed {
+		fmt.Fprintf(hash, "%v\x00", item)
+	}
+	sc.randCount++
+	value := binary.BigEndian.Uint64(hash.Sum(nil))
+	return min + int64(value%uint64(max-min)), nil
+}
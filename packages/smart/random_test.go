@@ -0,0 +1,65 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import "testing"
+
+func TestRandom(t *testing.T) {
+	newSC := func(txHash string) *SmartContract {
+		return &SmartContract{TxHash: []byte(txHash)}
+	}
+	sequence := func(sc *SmartContract, seed ...interface{}) (ret []int64) {
+		for i := 0; i < 5; i++ {
+			v, err := Random(sc, 10, 20, seed...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if v < 10 || v >= 20 {
+				t.Fatalf(`%d is out of range`, v)
+			}
+			ret = append(ret, v)
+		}
+		return
+	}
+	equal := func(a, b []int64) bool {
+		for i := range a {
+			if a[i] != b[i] {
+				return false
+			}
+		}
+		return true
+	}
+	first := sequence(newSC(`tx1`), `lottery`)
+	if !equal(first, sequence(newSC(`tx1`), `lottery`)) {
+		t.Error(`the same transaction must get the same values`)
+	}
+	if equal(first, sequence(newSC(`tx2`), `lottery`)) {
+		t.Error(`another transaction must get other values`)
+	}
+	if equal(first, sequence(newSC(`tx1`), `sample`)) {
+		t.Error(`another seed must get other values`)
+	}
+	if _, err := Random(newSC(`tx1`), 5, 5); err == nil {
+		t.Error(`expected error of wrong range`)
+	}
+}
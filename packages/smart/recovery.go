@@ -0,0 +1,261 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/GenesisKernel/go-genesis/packages/config/syspar"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// maxGuardians is the limit of guardians of the account
+const maxGuardians = 10
+
+// joinIDs returns the identifiers separated by comma
+func joinIDs(ids []int64) string {
+	list := make([]string, len(ids))
+	for i, id := range ids {
+		list[i] = converter.Int64ToStr(id)
+	}
+	return strings.Join(list, `,`)
+}
+
+// splitIDs returns the identifiers which are separated by comma
+func splitIDs(value string) []int64 {
+	var ret []int64
+	for _, item := range strings.Split(value, `,`) {
+		if id := converter.StrToInt64(strings.TrimSpace(item)); id != 0 {
+			ret = append(ret, id)
+		}
+	}
+	return ret
+}
+
+func containsID(ids []int64, id int64) bool {
+	for _, item := range ids {
+		if item == id {
+			return true
+		}
+	}
+	return false
+}
+
+// parseGuardians returns the identifiers of the guardian accounts separated by comma
+func parseGuardians(owner int64, guardians string) ([]int64, error) {
+	var ret []int64
+	for _, item := range strings.Split(guardians, `,`) {
+		if item = strings.TrimSpace(item); len(item) == 0 {
+			continue
+		}
+		id := AddressToID(item)
+		if id == 0 || id == owner {
+			return nil, fmt.Errorf(`wrong guardian %s`, item)
+		}
+		if containsID(ret, id) {
+			return nil, fmt.Errorf(`duplicate guardian %s`, item)
+		}
+		ret = append(ret, id)
+	}
+	if len(ret) > maxGuardians {
+		return nil, fmt.Errorf(`too many guardians. Limit is %d`, maxGuardians)
+	}
+	return ret, nil
+}
+
+// recoveryReleased returns true if the challenge period of the approved request has expired at the time
+func recoveryReleased(request *model.RecoveryRequest, now int64) bool {
+	return request.Status == model.RecoveryPending && request.ReleaseTime > 0 && request.ReleaseTime <= now
+}
+
+// notify sends the notification to the account of the ecosystem
+func (sc *SmartContract) notify(recipient int64, header, body string) error {
+	_, _, err := sc.selectiveLoggingAndUpd([]string{`recipient_id`, `header_text`, `body_text`, `notification_type`,
+		`timestamp date_create`}, []interface{}{recipient, header, body, 1, currentBlockTime(sc)},
+		ecosystemTable(sc, `notifications`), nil, nil, !sc.VDE && sc.Rollback, false)
+	return err
+}
+
+func getPendingRecovery(sc *SmartContract, account int64) (*model.RecoveryRequest, bool, error) {
+	request := &model.RecoveryRequest{}
+	found, err := request.GetPending(sc.DbTransaction, sc.TxSmart.EcosystemID, account)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting recovery request")
+	}
+	return request, found, err
+}
+
+// SetGuardians designates the guardians of the caller which can jointly rotate its public key. The threshold is
+// the number of required approvals, the delay is the challenge period in seconds when the recovery can be cancelled.
+// Empty list of guardians switches off the recovery
+func SetGuardians(sc *SmartContract, guardians string, threshold, delay int64) error {
+	owner := sc.TxSmart.KeyID
+	ids, err := parseGuardians(owner, guardians)
+	if err != nil {
+		return err
+	}
+	if len(ids) > 0 {
+		if threshold <= 0 || threshold > int64(len(ids)) {
+			return fmt.Errorf(`threshold must be from 1 to %d`, len(ids))
+		}
+		if min := syspar.GetRecoveryMinDelay(); delay < min {
+			return fmt.Errorf(`challenge period must be at least %d seconds`, min)
+		}
+	} else {
+		threshold, delay = 0, 0
+	}
+	for _, id := range ids {
+		key := &model.Key{}
+		key.SetTablePrefix(sc.TxSmart.EcosystemID)
+		found, err := key.GetTx(sc.DbTransaction, id)
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting wallet")
+			return err
+		}
+		if !found {
+			return fmt.Errorf(`wallet %s has not been found`, converter.AddressToString(id))
+		}
+	}
+	if _, found, err := getPendingRecovery(sc, owner); err != nil {
+		return err
+	} else if found {
+		return fmt.Errorf(`guardians can't be changed while the recovery is pending`)
+	}
+	_, _, err = sc.selectiveLoggingAndUpd([]string{`guardians`, `threshold`, `delay`},
+		[]interface{}{joinIDs(ids), threshold, delay}, model.RecoveryGuardians{}.TableName(),
+		[]string{`ecosystem`, `key_id`}, []string{converter.Int64ToStr(sc.TxSmart.EcosystemID),
+			converter.Int64ToStr(owner)}, !sc.VDE && sc.Rollback, false)
+	return err
+}
+
+// ApproveRecovery starts or approves the recovery of the account with the new public key by the guardian.
+// When the threshold of approvals is reached the challenge period starts. It returns the identifier of the request
+func ApproveRecovery(sc *SmartContract, account int64, publicKey string) (int64, error) {
+	guardian := sc.TxSmart.KeyID
+	guardians := &model.RecoveryGuardians{}
+	found, err := guardians.Get(sc.DbTransaction, sc.TxSmart.EcosystemID, account)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting guardians")
+		return 0, err
+	}
+	if !found || !containsID(splitIDs(guardians.Guardians), guardian) {
+		log.WithFields(log.Fields{"type": consts.AccessDenied, "account": account, "guardian": guardian}).Error("not guardian")
+		return 0, errAccessDenied
+	}
+	publicKey = strings.ToLower(strings.TrimSpace(publicKey))
+	if pub, err := hex.DecodeString(publicKey); err != nil || len(pub) != 64 {
+		log.WithFields(log.Fields{"type": consts.InvalidObject}).Error("wrong public key of recovery")
+		return 0, fmt.Errorf(`wrong public key`)
+	}
+	now := currentBlockTime(sc)
+	request, found, err := getPendingRecovery(sc, account)
+	if err != nil {
+		return 0, err
+	}
+	if !found {
+		request = &model.RecoveryRequest{Ecosystem: sc.TxSmart.EcosystemID, KeyID: account, PublicKey: publicKey,
+			StartedAt: now}
+	} else if request.PublicKey != publicKey {
+		return 0, fmt.Errorf(`recovery of %s with another public key is pending`, converter.AddressToString(account))
+	}
+	approvals := splitIDs(request.Approvals)
+	if containsID(approvals, guardian) {
+		return 0, fmt.Errorf(`recovery has already been approved`)
+	}
+	approvals = append(approvals, guardian)
+	request.Approvals = joinIDs(approvals)
+	if request.ReleaseTime == 0 && int64(len(approvals)) >= guardians.Threshold {
+		request.ReleaseTime = now + guardians.Delay
+	}
+	fields := []string{`ecosystem`, `key_id`, `public_key`, `approvals`, `started_at`, `release_time`, `status`}
+	values := []interface{}{request.Ecosystem, request.KeyID, request.PublicKey, request.Approvals,
+		request.StartedAt, request.ReleaseTime, model.RecoveryPending}
+	var (
+		whereFields, whereValues []string
+		id                       string
+	)
+	if found {
+		whereFields, whereValues = []string{`id`}, []string{converter.Int64ToStr(request.ID)}
+	}
+	if _, id, err = sc.selectiveLoggingAndUpd(fields, values, request.TableName(), whereFields, whereValues,
+		!sc.VDE && sc.Rollback, found); err != nil {
+		return 0, err
+	}
+	body := fmt.Sprintf(`Guardian %s has approved the recovery of your account`, converter.AddressToString(guardian))
+	if request.ReleaseTime > 0 {
+		body += fmt.Sprintf(`. The public key will be rotated after %d unless you cancel the recovery`,
+			request.ReleaseTime)
+	}
+	if err = sc.notify(account, `Account recovery`, body); err != nil {
+		return 0, err
+	}
+	return converter.StrToInt64(id), nil
+}
+
+// CancelRecovery cancels the pending recovery of the account of the caller
+func CancelRecovery(sc *SmartContract) error {
+	request, found, err := getPendingRecovery(sc, sc.TxSmart.KeyID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf(`there is no pending recovery`)
+	}
+	_, _, err = sc.selectiveLoggingAndUpd([]string{`status`}, []interface{}{model.RecoveryCancelled},
+		request.TableName(), []string{`id`}, []string{converter.Int64ToStr(request.ID)}, !sc.VDE && sc.Rollback, true)
+	return err
+}
+
+// FinishRecovery rotates the public key of the account after the challenge period of the approved recovery
+func FinishRecovery(sc *SmartContract, account int64) error {
+	request, found, err := getPendingRecovery(sc, account)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf(`there is no pending recovery`)
+	}
+	if !recoveryReleased(request, currentBlockTime(sc)) {
+		log.WithFields(log.Fields{"type": consts.AccessDenied, "account": account, "release_time": request.ReleaseTime}).Error("recovery is locked")
+		return fmt.Errorf(`recovery is not approved or the challenge period hasn't expired`)
+	}
+	pub, _ := hex.DecodeString(request.PublicKey)
+	if _, _, err = sc.selectiveLoggingAndUpd([]string{`pub`}, []interface{}{pub}, ecosystemTable(sc, `keys`),
+		[]string{`id`}, []string{converter.Int64ToStr(account)}, !sc.VDE && sc.Rollback, true); err != nil {
+		if err == errUpdNotExistRecord {
+			return fmt.Errorf(`wallet %s has not been found`, converter.AddressToString(account))
+		}
+		return err
+	}
+	if _, _, err = sc.selectiveLoggingAndUpd([]string{`status`}, []interface{}{model.RecoveryDone},
+		request.TableName(), []string{`id`}, []string{converter.Int64ToStr(request.ID)}, !sc.VDE && sc.Rollback, true); err != nil {
+		return err
+	}
+	return sc.notify(account, `Account recovery`, `The public key of your account has been rotated by the guardians`)
+}
@@ -0,0 +1,67 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/crypto"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+)
+
+func TestParseGuardians(t *testing.T) {
+	first, second := crypto.Address([]byte{1}), crypto.Address([]byte{2})
+	ids, err := parseGuardians(1, ` `+converter.Int64ToStr(first)+`, `+converter.AddressToString(second)+`,`)
+	if err != nil || !reflect.DeepEqual(ids, []int64{first, second}) {
+		t.Errorf(`wrong guardians %v %v`, ids, err)
+	}
+	if !reflect.DeepEqual(splitIDs(joinIDs(ids)), ids) {
+		t.Errorf(`wrong conversion of %v`, ids)
+	}
+	guardian := converter.AddressToString(first)
+	for _, guardians := range []string{guardian + `,` + guardian, guardian + `,100`,
+		converter.AddressToString(1) + `,` + guardian} {
+		if _, err = parseGuardians(1, guardians); err == nil {
+			t.Errorf(`%s: expected error`, guardians)
+		}
+	}
+}
+
+func TestRecoveryReleased(t *testing.T) {
+	for i, item := range []struct {
+		request model.RecoveryRequest
+		want    bool
+	}{
+		{model.RecoveryRequest{}, false},
+		{model.RecoveryRequest{ReleaseTime: 150}, false},
+		{model.RecoveryRequest{ReleaseTime: 100}, true},
+		{model.RecoveryRequest{ReleaseTime: 50, Status: model.RecoveryCancelled}, false},
+		{model.RecoveryRequest{ReleaseTime: 50, Status: model.RecoveryDone}, false},
+	} {
+		if got := recoveryReleased(&item.request, 100); got != item.want {
+			t.Errorf(`%d: got %v want %v`, i, got, item.want)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// onDeleteRestrict forbids to mark the row as deleted while there are active rows referencing it
+	onDeleteRestrict = `restrict`
+	// onDeleteCascade marks the referencing rows as deleted too
+	onDeleteCascade = `cascade`
+	// deletedColumn is the column which marks the row as deleted
+	deletedColumn = `deleted`
+	// maxCascadeDepth is the limit of nested cascading of the delete markers
+	maxCascadeDepth = 8
+)
+
+// isDeletedMarker returns true if the value of deleted column marks the row as deleted
+func isDeletedMarker(value interface{}) bool {
+	switch strings.TrimSpace(fmt.Sprint(value)) {
+	case ``, `0`, `false`, `<nil>`:
+		return false
+	}
+	return true
+}
+
+// activeRows is the condition of the rows which haven't been marked as deleted
+const activeRows = `coalesce("deleted"::text, '0') in ('', '0', 'false')`
+
+// checkReference validates the reference of the column permissions to the table of the ecosystem
+func checkReference(sc *SmartContract, perm permColumn) error {
+	if len(perm.Ref) == 0 {
+		if len(perm.OnDelete) > 0 {
+			return fmt.Errorf(`on_delete requires ref`)
+		}
+		return nil
+	}
+	if perm.OnDelete != `` && perm.OnDelete != onDeleteRestrict && perm.OnDelete != onDeleteCascade {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "on_delete": perm.OnDelete}).Error("unknown on_delete marker")
+		return fmt.Errorf(`unknown on_delete %s`, perm.OnDelete)
+	}
+	prefix, _ := PrefixName(getDefTableName(sc, `tables`))
+	t := &model.Table{}
+	t.SetTablePrefix(prefix)
+	exists, err := t.ExistsByName(sc.DbTransaction, strings.ToLower(perm.Ref))
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("table is exists")
+		return err
+	}
+	if !exists {
+		log.WithFields(log.Fields{"table_name": perm.Ref, "type": consts.NotFound}).Error("referenced table does not exists")
+		return fmt.Errorf(eTableNotFound, perm.Ref)
+	}
+	return nil
+}
+
+// checkReferences checks that the values of the reference columns are the identifiers of existing rows.
+// Zero or empty value means that the column doesn't refer to any row
+func (sc *SmartContract) checkReferences(table string, columns []string, values []interface{}) error {
+	perms, err := sc.columnPerms(table)
+	if err != nil {
+		return err
+	}
+	for i, column := range columns {
+		perm, ok := perms[strings.ToLower(strings.TrimSpace(column))]
+		if !ok || len(perm.Ref) == 0 || i >= len(values) {
+			continue
+		}
+		value := strings.TrimSpace(fmt.Sprint(values[i]))
+		if value == `` || value == `0` {
+			continue
+		}
+		id := converter.StrToInt64(value)
+		if id <= 0 {
+			return fmt.Errorf(`column %s must contain the identifier of %s`, column, perm.Ref)
+		}
+		reftable := getDefTableName(sc, perm.Ref)
+		found, err := model.GetOneRowTransaction(sc.DbTransaction, `SELECT id FROM "`+reftable+`" WHERE id = ?`, id).String()
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting referenced row")
+			return err
+		}
+		if len(found) == 0 {
+			log.WithFields(log.Fields{"type": consts.NotFound, "table": reftable, "id": id}).Error("referenced row not found")
+			return fmt.Errorf(`row %d of %s referenced by column %s doesn't exist`, id, perm.Ref, column)
+		}
+	}
+	return nil
+}
+
+// deletedMarker returns the value of deleted column if the update marks the rows as deleted
+func deletedMarker(columns []string, values []interface{}) (interface{}, bool) {
+	for i, column := range columns {
+		if i < len(values) && strings.ToLower(strings.TrimSpace(column)) == deletedColumn && isDeletedMarker(values[i]) {
+			return values[i], true
+		}
+	}
+	return nil, false
+}
+
+// markDeleted applies on_delete markers of the columns referencing the row which is marked as deleted
+func (sc *SmartContract) markDeleted(table string, id int64, value interface{}, depth int) error {
+	if depth >= maxCascadeDepth {
+		return fmt.Errorf(`cascading of deletion is too deep`)
+	}
+	prefix, name := PrefixName(table)
+	var list []model.Table
+	if err := model.GetDB(sc.DbTransaction).Table(prefix + `_tables`).Order(`name`).Find(&list).Error; err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting tables")
+		return err
+	}
+	for _, item := range list {
+		reftable := prefix + `_` + item.Name
+		perms, err := sc.columnPerms(reftable)
+		if err != nil {
+			return err
+		}
+		_, hasDeleted := perms[deletedColumn]
+		columns := make([]string, 0, len(perms))
+		for column := range perms {
+			columns = append(columns, column)
+		}
+		sort.Strings(columns)
+		for _, column := range columns {
+			perm := perms[column]
+			if strings.ToLower(perm.Ref) != name {
+				continue
+			}
+			where := fmt.Sprintf(`"%s" = ?`, column)
+			if hasDeleted {
+				where += ` and ` + activeRows
+			}
+			rows, err := model.GetAllTransaction(sc.DbTransaction, `SELECT id FROM "`+reftable+`" WHERE `+where+
+				` ORDER BY id`, -1, id)
+			if err != nil {
+				log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting referencing rows")
+				return err
+			}
+			if len(rows) == 0 {
+				continue
+			}
+			if perm.OnDelete != onDeleteCascade || !hasDeleted {
+				log.WithFields(log.Fields{"type": consts.AccessDenied, "table": reftable, "column": column}).Error("row is referenced")
+				return fmt.Errorf(`row %d is referenced by %s.%s`, id, item.Name, column)
+			}
+			if err = sc.AccessTable(reftable, `update`); err != nil {
+				return err
+			}
+			for _, row := range rows {
+				if _, _, err = sc.selectiveLoggingAndUpd([]string{deletedColumn}, []interface{}{value}, reftable,
+					[]string{`id`}, []string{row[`id`]}, !sc.VDE && sc.Rollback, false); err != nil {
+					return err
+				}
+				if err = sc.markDeleted(reftable, converter.StrToInt64(row[`id`]), value, depth+1); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import "testing"
+
+func TestDeletedMarker(t *testing.T) {
+	for _, item := range []interface{}{``, `0`, int64(0), `false`, nil, ` 0 `} {
+		if isDeletedMarker(item) {
+			t.Errorf(`%v must not mark the row as deleted`, item)
+		}
+	}
+	for _, item := range []interface{}{`1`, int64(1), `true`, `2018-05-01`} {
+		if !isDeletedMarker(item) {
+			t.Errorf(`%v must mark the row as deleted`, item)
+		}
+	}
+	if _, ok := deletedMarker([]string{`name`, ` Deleted`}, []interface{}{`1`, 1}); !ok {
+		t.Error(`deleted column has not been found`)
+	}
+	if _, ok := deletedMarker([]string{`name`, `deleted`}, []interface{}{`1`}); ok {
+		t.Error(`deleted column without value`)
+	}
+	perm, err := getPermColumns(`{"update":"true","ref":"members","on_delete":"cascade"}`)
+	if err != nil || perm.Ref != `members` || perm.OnDelete != onDeleteCascade {
+		t.Errorf(`wrong reference %v %v`, perm, err)
+	}
+}
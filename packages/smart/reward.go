@@ -0,0 +1,93 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"errors"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrNoRewards is returned if the key has no unclaimed rewards
+var ErrNoRewards = errors.New(`There are no rewards to claim`)
+
+// accrueReward adds the amount to the reward of the key for the current block
+func (sc *SmartContract) accrueReward(keyID int64, role string, amount decimal.Decimal) error {
+	if amount.Sign() <= 0 || keyID == 0 {
+		return nil
+	}
+	_, _, err := sc.selectiveLoggingAndUpd([]string{`+amount`}, []interface{}{amount}, model.ValidatorReward{}.TableName(),
+		[]string{`block_id`, `key_id`, `ecosystem`, `role`}, []string{converter.Int64ToStr(sc.BlockData.BlockID),
+			converter.Int64ToStr(keyID), converter.Int64ToStr(sc.TxSmart.TokenEcosystem), role}, true, false)
+	return err
+}
+
+// unclaimedRewards returns the rewards of the key in the token ecosystem which haven't been claimed yet
+func unclaimedRewards(sc *SmartContract, keyID, ecosystem int64) (decimal.Decimal, error) {
+	accrued, claimed, err := model.GetRewardTotals(sc.DbTransaction, keyID, ecosystem)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting reward totals")
+		return decimal.Zero, err
+	}
+	total, err := decimal.NewFromString(accrued)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.ConversionError, "error": err, "value": accrued}).Error("converting accrued rewards to decimal")
+		return decimal.Zero, err
+	}
+	paid, err := decimal.NewFromString(claimed)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.ConversionError, "error": err, "value": claimed}).Error("converting claimed rewards to decimal")
+		return decimal.Zero, err
+	}
+	return total.Sub(paid), nil
+}
+
+// ClaimRewards pays the unclaimed rewards of the caller in the token ecosystem to its wallet
+// and returns the paid amount
+func ClaimRewards(sc *SmartContract, ecosystem int64) (string, error) {
+	if ecosystem == 0 {
+		ecosystem = 1
+	}
+	keyID := sc.TxSmart.KeyID
+	amount, err := unclaimedRewards(sc, keyID, ecosystem)
+	if err != nil {
+		return ``, err
+	}
+	if amount.Sign() <= 0 {
+		return ``, ErrNoRewards
+	}
+	if err = walletCredit(sc, ecosystem, keyID, amount.String()); err != nil {
+		return ``, err
+	}
+	if _, _, err = sc.selectiveLoggingAndUpd([]string{`key_id`, `ecosystem`, `amount`, `block_id`},
+		[]interface{}{keyID, ecosystem, amount, currentBlockID(sc)}, model.RewardClaim{}.TableName(),
+		nil, nil, !sc.VDE && sc.Rollback, false); err != nil {
+		return ``, err
+	}
+	return amount.String(), nil
+}
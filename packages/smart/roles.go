@@ -0,0 +1,186 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"fmt"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// maxRoleDepth limits the depth of the hierarchy of roles
+const maxRoleDepth = 16
+
+func getRole(sc *SmartContract, id int64) (*model.Role, error) {
+	role := &model.Role{}
+	role.SetTablePrefix(sc.TxSmart.EcosystemID)
+	found, err := role.Get(sc.DbTransaction, id)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting role")
+		return nil, err
+	}
+	if !found || role.Delete != 0 {
+		log.WithFields(log.Fields{"type": consts.NotFound, "id": id}).Error("role not found")
+		return nil, fmt.Errorf(`role %d has not been found`, id)
+	}
+	return role, nil
+}
+
+// roleWithParents returns the identifier of the role and the identifiers of its parent roles
+func roleWithParents(sc *SmartContract, id int64) ([]int64, error) {
+	ret := []int64{id}
+	for i := 0; i < maxRoleDepth; i++ {
+		role := &model.Role{}
+		role.SetTablePrefix(sc.TxSmart.EcosystemID)
+		found, err := role.Get(sc.DbTransaction, id)
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting role")
+			return nil, err
+		}
+		if !found || role.ParentID == 0 {
+			break
+		}
+		for _, prev := range ret {
+			if prev == role.ParentID {
+				return ret, nil
+			}
+		}
+		id = role.ParentID
+		ret = append(ret, id)
+	}
+	return ret, nil
+}
+
+// RoleAccess returns true if the caller is a member of any of the specified roles or of their parent roles.
+// Expired and deleted memberships are ignored
+func RoleAccess(sc *SmartContract, ids ...interface{}) (bool, error) {
+	var roles []int64
+	for _, iid := range ids {
+		var id int64
+		switch v := iid.(type) {
+		case int64:
+			id = v
+		case string:
+			id = converter.StrToInt64(v)
+		}
+		if id <= 0 {
+			continue
+		}
+		list, err := roleWithParents(sc, id)
+		if err != nil {
+			return false, err
+		}
+		roles = append(roles, list...)
+	}
+	if len(roles) == 0 {
+		return false, nil
+	}
+	member, err := model.IsRoleMember(sc.DbTransaction, sc.TxSmart.EcosystemID, roles, sc.TxSmart.KeyID, currentBlockTime(sc))
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("checking role member")
+		return false, err
+	}
+	return member, nil
+}
+
+// AssignRole makes the wallet a member of the role. If expire is not zero then the membership
+// is valid until this unix time. The insert permission of roles_assign table is checked
+func AssignRole(sc *SmartContract, roleID, member, expire int64) (int64, error) {
+	if err := sc.AccessTable(ecosystemTable(sc, `roles_assign`), "insert"); err != nil {
+		return 0, err
+	}
+	return assignRole(sc, roleID, member, expire)
+}
+
+// assignRole makes the wallet a member of the role without checking the permissions of roles_assign table
+func assignRole(sc *SmartContract, roleID, member, expire int64) (int64, error) {
+	if member == 0 {
+		log.WithFields(log.Fields{"type": consts.InvalidObject}).Error("wrong member")
+		return 0, fmt.Errorf(`wrong member`)
+	}
+	now := currentBlockTime(sc)
+	if expire < 0 || (expire > 0 && expire <= now) {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "expire": expire}).Error("wrong expiration time")
+		return 0, fmt.Errorf(`expiration time must be in the future`)
+	}
+	role, err := getRole(sc, roleID)
+	if err != nil {
+		return 0, err
+	}
+	assign := &model.RoleAssign{}
+	assign.SetTablePrefix(sc.TxSmart.EcosystemID)
+	found, err := assign.GetActive(sc.DbTransaction, roleID, member, now)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting role membership")
+		return 0, err
+	}
+	if found {
+		log.WithFields(log.Fields{"type": consts.DuplicateObject, "role": roleID, "member": member}).Error("role has already been assigned")
+		return 0, fmt.Errorf(`role %d has already been assigned to %s`, roleID, converter.AddressToString(member))
+	}
+	fields := []string{`role_id`, `role_type`, `role_name`, `member_id`, `appointed_by_id`, `timestamp date_start`, `delete`}
+	values := []interface{}{roleID, role.RoleType, role.RoleName, member, sc.TxSmart.KeyID, now, 0}
+	if expire > 0 {
+		fields = append(fields, `timestamp date_end`)
+		values = append(values, expire)
+	}
+	_, id, err := sc.selectiveLoggingAndUpd(fields, values, ecosystemTable(sc, `roles_assign`), nil, nil,
+		!sc.VDE && sc.Rollback, false)
+	if err != nil {
+		return 0, err
+	}
+	return converter.StrToInt64(id), nil
+}
+
+// RevokeRole deletes the membership of the wallet in the role. The update permissions
+// of roles_assign table are checked
+func RevokeRole(sc *SmartContract, roleID, member int64) error {
+	table := ecosystemTable(sc, `roles_assign`)
+	if err := sc.AccessTable(table, "update"); err != nil {
+		return err
+	}
+	columns := []string{`delete`, `date_end`}
+	if err := sc.AccessColumns(table, &columns, true); err != nil {
+		return err
+	}
+	now := currentBlockTime(sc)
+	assign := &model.RoleAssign{}
+	assign.SetTablePrefix(sc.TxSmart.EcosystemID)
+	found, err := assign.GetActive(sc.DbTransaction, roleID, member, now)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting role membership")
+		return err
+	}
+	if !found {
+		log.WithFields(log.Fields{"type": consts.NotFound, "role": roleID, "member": member}).Error("role membership not found")
+		return fmt.Errorf(`role %d has not been assigned to %s`, roleID, converter.AddressToString(member))
+	}
+	_, _, err = sc.selectiveLoggingAndUpd([]string{`delete`, `timestamp date_end`}, []interface{}{1, now},
+		ecosystemTable(sc, `roles_assign`), []string{`id`}, []string{converter.Int64ToStr(assign.ID)},
+		!sc.VDE && sc.Rollback, true)
+	return err
+}
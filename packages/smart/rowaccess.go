@@ -0,0 +1,201 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// RowRead is the permission of the table which limits the rows available for reading
+	RowRead = `row_read`
+	// RowUpdate is the permission of the table which limits the rows available for updating
+	RowUpdate = `row_update`
+)
+
+var rowKeywords = map[string]bool{`and`: true, `or`: true, `not`: true, `is`: true, `null`: true,
+	`in`: true, `like`: true, `true`: true, `false`: true}
+
+func identEnd(runes []rune, i int) int {
+	for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+		i++
+	}
+	return i
+}
+
+// RowCondition converts the row condition of the table to SQL expression with parameters.
+// The condition can contain column names, numbers, strings in single quotes, comparison and
+// logical operators and $key_id, $ecosystem_id variables. For example, owner == $key_id.
+// The keys of json columns are specified by the path and compared as text, for example, info.city == 'Paris'
+func RowCondition(cond string, keyID, ecosystemID int64) (string, []interface{}, error) {
+	var (
+		out    []string
+		params []interface{}
+	)
+	vars := map[string]interface{}{`key_id`: keyID, `ecosystem_id`: ecosystemID}
+	runes := []rune(cond)
+	for i := 0; i < len(runes); {
+		ch := runes[i]
+		switch {
+		case unicode.IsSpace(ch):
+			i++
+		case ch == '(' || ch == ')' || ch == ',':
+			out = append(out, string(ch))
+			i++
+		case strings.ContainsRune(`=!<>`, ch):
+			j := i
+			for j < len(runes) && strings.ContainsRune(`=!<>`, runes[j]) {
+				j++
+			}
+			op := string(runes[i:j])
+			switch op {
+			case `==`:
+				op = `=`
+			case `!=`:
+				op = `<>`
+			case `=`, `<>`, `<`, `>`, `<=`, `>=`:
+			default:
+				return ``, nil, fmt.Errorf(`unknown operator %s in row condition`, op)
+			}
+			out = append(out, op)
+			i = j
+		case ch == '\'':
+			j := i + 1
+			for j < len(runes) && runes[j] != '\'' {
+				j++
+			}
+			if j == len(runes) {
+				return ``, nil, fmt.Errorf(`unclosed string in row condition`)
+			}
+			out = append(out, `?`)
+			params = append(params, string(runes[i+1:j]))
+			i = j + 1
+		case ch == '$':
+			j := identEnd(runes, i+1)
+			val, ok := vars[string(runes[i+1:j])]
+			if !ok {
+				return ``, nil, fmt.Errorf(`unknown variable %s in row condition`, string(runes[i:j]))
+			}
+			out = append(out, `?`)
+			params = append(params, val)
+			i = j
+		case unicode.IsDigit(ch) || ch == '-':
+			j := i + 1
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			num := string(runes[i:j])
+			if _, err := strconv.ParseFloat(num, 64); err != nil {
+				return ``, nil, fmt.Errorf(`wrong number %s in row condition`, num)
+			}
+			out = append(out, num)
+			i = j
+		case unicode.IsLetter(ch) || ch == '_':
+			j := identEnd(runes, i)
+			word := strings.ToLower(string(runes[i:j]))
+			if rowKeywords[word] {
+				out = append(out, word)
+			} else {
+				next := j
+				for next < len(runes) && unicode.IsSpace(runes[next]) {
+					next++
+				}
+				if next < len(runes) && runes[next] == '(' {
+					return ``, nil, fmt.Errorf(`functions are not allowed in row condition`)
+				}
+				for j < len(runes) && runes[j] == '.' {
+					j = identEnd(runes, j+1)
+				}
+				if path := string(runes[i:j]); strings.Contains(path, `.`) {
+					keys, err := converter.JSONPath(path)
+					if err != nil {
+						return ``, nil, err
+					}
+					out = append(out, converter.JSONPathSQL(strings.ToLower(keys[0]), keys[1:]))
+				} else {
+					out = append(out, `"`+word+`"`)
+				}
+			}
+			i = j
+		default:
+			return ``, nil, fmt.Errorf(`unexpected character %c in row condition`, ch)
+		}
+	}
+	return strings.Join(out, ` `), params, nil
+}
+
+// RowAccess returns the SQL condition which limits the rows of the table for the action
+func (sc *SmartContract) RowAccess(table, action string) (string, []interface{}, error) {
+	prefix, name := PrefixName(table)
+	tables := &model.Table{}
+	tables.SetTablePrefix(prefix)
+	cond, err := tables.GetPermissions(sc.DbTransaction, name, ``)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting table permissions")
+		return ``, nil, err
+	}
+	if len(cond[action]) == 0 {
+		return ``, nil, nil
+	}
+	where, params, err := RowCondition(cond[action], sc.TxSmart.KeyID, sc.TxSmart.EcosystemID)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "error": err, "table": table}).Error("parsing row condition")
+		return ``, nil, err
+	}
+	return where, params, nil
+}
+
+// checkRowUpdate checks that the existing rows which are selected by the condition can be updated by the caller
+func (sc *SmartContract) checkRowUpdate(table, where string, args ...interface{}) error {
+	rowWhere, rowParams, err := sc.RowAccess(table, RowUpdate)
+	if err != nil || len(rowWhere) == 0 {
+		return err
+	}
+	var total, allowed int64
+	if err = model.GetDB(sc.DbTransaction).Table(table).Where(where, args...).Count(&total).Error; err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("counting rows")
+		return err
+	}
+	if total == 0 {
+		return nil
+	}
+	if err = model.GetDB(sc.DbTransaction).Table(table).Where(where, args...).Where(rowWhere, rowParams...).
+		Count(&allowed).Error; err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("counting rows")
+		return err
+	}
+	if allowed < total {
+		log.WithFields(log.Fields{"type": consts.AccessDenied, "table": table}).Error("access denied to the row")
+		return errAccessDenied
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRowCondition(t *testing.T) {
+	for cond, want := range map[string]string{
+		``:                              ``,
+		`owner == $key_id`:              `"owner" = ?`,
+		`owner = $key_id or public = 1`: `"owner" = ? or "public" = 1`,
+		`(status != 'closed') AND ecosystem = $ecosystem_id`: `( "status" <> ? ) and "ecosystem" = ?`,
+		`deleted_at IS NULL`:      `"deleted_at" is null`,
+		`info.city == 'Paris'`:    `("info"->>'city') = ?`,
+		`Info.items.0.Name != ''`: `("info"->'items'->0->>'Name') <> ?`,
+	} {
+		got, _, err := RowCondition(cond, 10, 1)
+		if err != nil {
+			t.Errorf(`%s: unexpected error %v`, cond, err)
+		} else if got != want {
+			t.Errorf(`%s: got %s want %s`, cond, got, want)
+		}
+	}
+	_, params, _ := RowCondition(`owner == $key_id and name = 'abc'`, 10, 1)
+	if !reflect.DeepEqual(params, []interface{}{int64(10), `abc`}) {
+		t.Errorf(`wrong params %v`, params)
+	}
+	for _, cond := range []string{`owner == $wallet`, `pg_sleep(10) = 1`, `name = 'abc`, `a; drop table x`,
+		`a === 1`, `a - 1`, `info..city = 1`, `info. = 1`} {
+		if _, _, err := RowCondition(cond, 10, 1); err == nil {
+			t.Errorf(`%s: expected error`, cond)
+		}
+	}
+}
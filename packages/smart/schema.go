@@ -0,0 +1,344 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/GenesisKernel/go-genesis/packages/config/syspar"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	// columnSQLTypes are SQL types of the column types without constraints
+	columnSQLTypes = map[string]string{
+		`json`:      `jsonb`,
+		`varchar`:   `varchar(102400)`,
+		`character`: `character(1)`,
+		`number`:    `bigint`,
+		`datetime`:  `timestamp`,
+		`double`:    `double precision`,
+		`money`:     `numeric(30,0)`,
+		`text`:      `text`,
+		`bytea`:     `bytea`,
+	}
+
+	// safeConversions are the changes of column types which keep all values and can be reverted
+	safeConversions = map[string][]string{
+		`number`:    {`money`, `varchar`, `text`},
+		`money`:     {`varchar`, `text`},
+		`double`:    {`varchar`, `text`},
+		`character`: {`varchar`, `text`},
+		`varchar`:   {`text`},
+		`datetime`:  {`varchar`, `text`},
+		`json`:      {`text`},
+	}
+
+	regexpIndexName = regexp.MustCompile(`^[a-z0-9_]{1,32}$`)
+)
+
+// maxIndexNameLength is the limit of identifiers in PostgreSQL
+const maxIndexNameLength = 63
+
+// isSafeConversion returns true if the values of the column can be converted from one type to another and back
+func isSafeConversion(from, to string) bool {
+	for _, item := range safeConversions[from] {
+		if item == to {
+			return true
+		}
+	}
+	return false
+}
+
+// addSchemaRollback saves the statement which restores the schema when the transaction is rolled back
+func addSchemaRollback(sc *SmartContract, query, data string) error {
+	if sc.VDE || !sc.Rollback || sc.BlockData == nil {
+		return nil
+	}
+	rollback := &model.SchemaRollback{BlockID: sc.BlockData.BlockID, TxHash: sc.TxHash, Query: query, Data: data}
+	if err := rollback.Create(sc.DbTransaction); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("creating schema rollback")
+		return err
+	}
+	return nil
+}
+
+// changeColumnType returns the statement which changes the type and the default value of the column
+func changeColumnType(tblname, column string, col *model.ColumnSchema) string {
+	query := fmt.Sprintf(`ALTER TABLE "%[1]s" ALTER COLUMN "%[2]s" DROP DEFAULT, ALTER COLUMN "%[2]s" TYPE %[3]s USING "%[2]s"::%[3]s`,
+		tblname, column, col.Type)
+	if len(col.Default) > 0 {
+		query += fmt.Sprintf(`, ALTER COLUMN "%s" SET DEFAULT %s`, column, col.Default)
+	}
+	return query
+}
+
+// tableColumns checks the access to change the schema of the table and returns the permissions of its columns
+func tableColumns(sc *SmartContract, tableName string) (*model.Table, map[string]string, error) {
+	table := &model.Table{}
+	prefix := converter.Int64ToStr(sc.TxSmart.EcosystemID)
+	if sc.VDE {
+		prefix += `_vde`
+	}
+	table.SetTablePrefix(prefix)
+	found, err := table.Get(sc.DbTransaction, tableName)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting table")
+		return nil, nil, err
+	}
+	if !found {
+		log.WithFields(log.Fields{"type": consts.NotFound, "table": tableName}).Error("table not found")
+		return nil, nil, fmt.Errorf(`table %s doesn't exist`, tableName)
+	}
+	if err = sc.AccessTable(getDefTableName(sc, tableName), `new_column`); err != nil {
+		return nil, nil, err
+	}
+	var columns map[string]string
+	if err = json.Unmarshal([]byte(table.Columns), &columns); err != nil {
+		log.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err}).Error("unmarshalling columns of table")
+		return nil, nil, err
+	}
+	return table, columns, nil
+}
+
+// AlterColumnType changes the type of the column if the values can be converted back on rollback
+func AlterColumnType(sc *SmartContract, tableName, name, coltype string) error {
+	if !accessContracts(sc, `AlterColumn`) {
+		log.WithFields(log.Fields{"type": consts.IncorrectCallingContract}).Error("AlterColumnType can be only called from @1AlterColumn")
+		return fmt.Errorf(`AlterColumnType can be only called from AlterColumn`)
+	}
+	name = strings.ToLower(name)
+	tableName = strings.ToLower(tableName)
+	_, columns, err := tableColumns(sc, tableName)
+	if err != nil {
+		return err
+	}
+	if _, ok := columns[name]; !ok {
+		return fmt.Errorf(`column %s doesn't exists`, name)
+	}
+	tblname := getDefTableName(sc, tableName)
+	oldType, err := model.GetColumnType(tblname, name)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting column type")
+		return err
+	}
+	if !isSafeConversion(oldType, coltype) {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "from": oldType, "to": coltype}).Error("unsafe conversion of column")
+		return fmt.Errorf(`type of column %s can't be changed from %s to %s`, name, oldType, coltype)
+	}
+	col, err := model.GetColumnSchema(sc.DbTransaction, tblname, name)
+	if err != nil || col == nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting column schema")
+		return fmt.Errorf(`column %s doesn't exists`, name)
+	}
+	if err = addSchemaRollback(sc, changeColumnType(tblname, name, col), ``); err != nil {
+		return err
+	}
+	err = model.ExecSchemaChange(sc.DbTransaction, fmt.Sprintf(`ALTER TABLE "%s" ALTER COLUMN "%s" TYPE %s`,
+		tblname, name, columnSQLTypes[coltype]))
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("changing column type")
+	}
+	return err
+}
+
+// RemoveColumn drops the column, its values are saved to restore the column on rollback
+func RemoveColumn(sc *SmartContract, tableName, name string) error {
+	if !accessContracts(sc, `DropColumn`) {
+		log.WithFields(log.Fields{"type": consts.IncorrectCallingContract}).Error("RemoveColumn can be only called from @1DropColumn")
+		return fmt.Errorf(`RemoveColumn can be only called from DropColumn`)
+	}
+	name = strings.ToLower(name)
+	tableName = strings.ToLower(tableName)
+	table, columns, err := tableColumns(sc, tableName)
+	if err != nil {
+		return err
+	}
+	if _, ok := columns[name]; !ok {
+		return fmt.Errorf(`column %s doesn't exists`, name)
+	}
+	tblname := getDefTableName(sc, tableName)
+	col, err := model.GetColumnSchema(sc.DbTransaction, tblname, name)
+	if err == nil && col == nil {
+		err = fmt.Errorf(`column %s doesn't exists`, name)
+	}
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting column schema")
+		return err
+	}
+	indexes, err := model.GetColumnIndexes(sc.DbTransaction, tblname, name)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting column indexes")
+		return err
+	}
+	values, err := model.GetColumnValues(sc.DbTransaction, tblname, name)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting column values")
+		return err
+	}
+	// the statements are executed in reverse order on rollback
+	var undo [][2]string
+	if col.NotNull {
+		undo = append(undo, [2]string{fmt.Sprintf(`ALTER TABLE "%s" ALTER COLUMN "%s" SET NOT NULL`, tblname, name)})
+	}
+	for _, def := range indexes {
+		undo = append(undo, [2]string{def})
+	}
+	undo = append(undo, [2]string{fmt.Sprintf(`UPDATE "%[1]s" SET "%[2]s" = d.value::%[3]s FROM jsonb_each_text(?::jsonb) AS d
+		WHERE "%[1]s".id = d.key::bigint`, tblname, name, col.Type), values})
+	add := fmt.Sprintf(`ALTER TABLE "%s" ADD COLUMN "%s" %s`, tblname, name, col.Type)
+	if len(col.Default) > 0 {
+		add += ` DEFAULT ` + col.Default
+	}
+	undo = append(undo, [2]string{add})
+	for _, item := range undo {
+		if err = addSchemaRollback(sc, item[0], item[1]); err != nil {
+			return err
+		}
+	}
+	if err = model.ExecSchemaChange(sc.DbTransaction, fmt.Sprintf(`ALTER TABLE "%s" DROP COLUMN "%s"`, tblname, name)); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("dropping column")
+		return err
+	}
+	delete(columns, name)
+	out, err := json.Marshal(columns)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Error("marshalling columns to json")
+		return err
+	}
+	_, _, err = sc.selectiveLoggingAndUpd([]string{`columns`}, []interface{}{string(out)},
+		table.TableName(), []string{`name`}, []string{tableName}, !sc.VDE && sc.Rollback, false)
+	return err
+}
+
+// indexName returns the name of index of the table
+func indexName(tblname, name string) string {
+	return fmt.Sprintf(`%s_%s_index`, tblname, name)
+}
+
+// CreateTableIndex creates the index on the columns of the table, the columns are separated by commas.
+// The key of json column can be specified by the path, for example, info.city
+func CreateTableIndex(sc *SmartContract, tableName, name, columns string, unique bool) error {
+	if !accessContracts(sc, `NewIndex`) {
+		log.WithFields(log.Fields{"type": consts.IncorrectCallingContract}).Error("CreateTableIndex can be only called from @1NewIndex")
+		return fmt.Errorf(`CreateTableIndex can be only called from NewIndex`)
+	}
+	name = strings.ToLower(name)
+	tableName = strings.ToLower(tableName)
+	if !regexpIndexName.MatchString(name) {
+		return fmt.Errorf(`wrong index name %s`, name)
+	}
+	_, cols, err := tableColumns(sc, tableName)
+	if err != nil {
+		return err
+	}
+	var list []string
+	for _, item := range strings.Split(columns, `,`) {
+		keys, err := converter.JSONPath(strings.TrimSpace(item))
+		if err != nil {
+			return err
+		}
+		column := strings.ToLower(keys[0])
+		if _, ok := cols[column]; !ok && column != `id` {
+			return fmt.Errorf(`column %s doesn't exists`, column)
+		}
+		// the keys of json column are indexed by the expression
+		if len(keys) > 1 {
+			list = append(list, `(`+converter.JSONPathSQL(column, keys[1:])+`)`)
+		} else {
+			list = append(list, `"`+column+`"`)
+		}
+	}
+	tblname := getDefTableName(sc, tableName)
+	idx := indexName(tblname, name)
+	if len(idx) > maxIndexNameLength {
+		return fmt.Errorf(`index name %s is too long`, name)
+	}
+	def, err := model.GetIndexDef(sc.DbTransaction, tblname, idx)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting index")
+		return err
+	}
+	if len(def) > 0 {
+		return fmt.Errorf(`index %s exists`, name)
+	}
+	count, err := model.GetIndexCount(sc.DbTransaction, tblname)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("counting indexes")
+		return err
+	}
+	if count >= int64(syspar.GetMaxIndexes()) {
+		log.WithFields(log.Fields{"size": count, "max_size": syspar.GetMaxIndexes(), "type": consts.ParameterExceeded}).Error("Too many indexes")
+		return fmt.Errorf(`Too many indexes. Limit is %d`, syspar.GetMaxIndexes())
+	}
+	if err = addSchemaRollback(sc, fmt.Sprintf(`DROP INDEX "%s"`, idx), ``); err != nil {
+		return err
+	}
+	var kind string
+	if unique {
+		kind = `UNIQUE `
+	}
+	err = model.ExecSchemaChange(sc.DbTransaction, fmt.Sprintf(`CREATE %sINDEX "%s" ON "%s" (%s)`, kind, idx,
+		tblname, strings.Join(list, `,`)))
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("creating index")
+	}
+	return err
+}
+
+// RemoveTableIndex drops the index which has been created by CreateTableIndex
+func RemoveTableIndex(sc *SmartContract, tableName, name string) error {
+	if !accessContracts(sc, `DropIndex`) {
+		log.WithFields(log.Fields{"type": consts.IncorrectCallingContract}).Error("RemoveTableIndex can be only called from @1DropIndex")
+		return fmt.Errorf(`RemoveTableIndex can be only called from DropIndex`)
+	}
+	name = strings.ToLower(name)
+	tableName = strings.ToLower(tableName)
+	if _, _, err := tableColumns(sc, tableName); err != nil {
+		return err
+	}
+	tblname := getDefTableName(sc, tableName)
+	idx := indexName(tblname, name)
+	def, err := model.GetIndexDef(sc.DbTransaction, tblname, idx)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting index")
+		return err
+	}
+	if len(def) == 0 {
+		return fmt.Errorf(`index %s doesn't exist`, name)
+	}
+	if err = addSchemaRollback(sc, def, ``); err != nil {
+		return err
+	}
+	if err = model.ExecSchemaChange(sc.DbTransaction, fmt.Sprintf(`DROP INDEX "%s"`, idx)); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("dropping index")
+	}
+	return err
+}
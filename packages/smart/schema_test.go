@@ -0,0 +1,51 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import "testing"
+
+func TestIsSafeConversion(t *testing.T) {
+	var table = []struct {
+		from, to string
+		want     bool
+	}{
+		{`number`, `money`, true},
+		{`number`, `text`, true},
+		{`varchar`, `text`, true},
+		{`text`, `varchar`, false},
+		{`money`, `number`, false},
+		{`varchar`, `number`, false},
+		{`json`, `varchar`, false},
+		{`unknown`, `text`, false},
+	}
+	for _, item := range table {
+		if got := isSafeConversion(item.from, item.to); got != item.want {
+			t.Errorf(`%s -> %s: got %v want %v`, item.from, item.to, got, item.want)
+		}
+		if item.want {
+			if _, ok := columnSQLTypes[item.to]; !ok {
+				t.Errorf(`unknown SQL type of %s`, item.to)
+			}
+		}
+	}
+}
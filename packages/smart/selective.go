@@ -56,6 +56,9 @@ func (sc *SmartContract) selectiveLoggingAndUpd(fields []string, ivalues []inter
 		logger.WithFields(log.Fields{"type": consts.EmptyObject}).Error("Block is undefined")
 		return 0, ``, fmt.Errorf(`It is impossible to write to DB when Block is undefined`)
 	}
+	if err := sc.checkFrozen(table); err != nil {
+		return 0, ``, err
+	}
 
 	isBytea := GetBytea(sc.DbTransaction, table)
 	for i, v := range ivalues {
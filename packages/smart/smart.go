@@ -32,6 +32,7 @@ import (
 	"github.com/GenesisKernel/go-genesis/packages/config/syspar"
 	"github.com/GenesisKernel/go-genesis/packages/consts"
 	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/faults"
 	"github.com/GenesisKernel/go-genesis/packages/model"
 	"github.com/GenesisKernel/go-genesis/packages/script"
 	"github.com/GenesisKernel/go-genesis/packages/utils"
@@ -140,7 +141,7 @@ func vmCompile(vm *script.VM, src string, owner *script.OwnerInfo) error {
 
 // VMCompileBlock is compiling block
 func VMCompileBlock(vm *script.VM, src string, owner *script.OwnerInfo) (*script.Block, error) {
-	return vm.CompileBlock([]rune(src), owner)
+	return vm.CompileBlockCached([]rune(src), owner)
 }
 
 func VMCompileEval(vm *script.VM, src string, prefix uint32) error {
@@ -217,11 +218,11 @@ func vmGetUsedContracts(vm *script.VM, name string, state uint32, full bool) []s
 
 func VMGetContractByID(vm *script.VM, id int32) *Contract {
 	idcont := id // - CNTOFF
-	if len(vm.Children) <= int(idcont) || vm.Children[idcont].Type != script.ObjContract {
+	block := vm.Child(int(idcont))
+	if block == nil || block.Type != script.ObjContract {
 		return nil
 	}
-	return &Contract{Name: vm.Children[idcont].Info.(*script.ContractInfo).Name,
-		Block: vm.Children[idcont]}
+	return &Contract{Name: block.Info.(*script.ContractInfo).Name, Block: block}
 }
 
 func vmExtendCost(vm *script.VM, ext func(string) int64) {
@@ -413,7 +414,8 @@ func (sc *SmartContract) getExtend() *map[string]interface{} {
 		`node_position`: head.NodePosition,
 		`block`:         block, `key_id`: keyID, `block_key_id`: blockKeyID,
 		`parent`: ``, `txcost`: sc.GetContractLimit(), `txhash`: sc.TxHash, `result`: ``,
-		`sc`: sc, `contract`: sc.TxContract, `block_time`: blockTime}
+		`sc`: sc, `contract`: sc.TxContract, `block_time`: blockTime,
+		`max_call_depth`: syspar.SysInt64(syspar.MaxCallDepth)}
 	for key, val := range sc.TxData {
 		extend[key] = val
 	}
@@ -520,6 +522,48 @@ func getPermColumns(input string) (perm permColumn, err error) {
 	return
 }
 
+// flaggedColumns returns the columns of the table which permissions have the flag
+func (sc *SmartContract) flaggedColumns(table string, flag func(permColumn) bool) (map[string]bool, error) {
+	perms, err := sc.columnPerms(table)
+	if err != nil {
+		return nil, err
+	}
+	ret := make(map[string]bool)
+	for column, perm := range perms {
+		if flag(perm) {
+			ret[column] = true
+		}
+	}
+	return ret, nil
+}
+
+// columnPerms returns the parsed permissions of the columns of the table
+func (sc *SmartContract) columnPerms(table string) (map[string]permColumn, error) {
+	prefix, name := PrefixName(table)
+	tables := &model.Table{}
+	tables.SetTablePrefix(prefix)
+	found, err := tables.Get(sc.DbTransaction, name)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting table columns")
+		return nil, err
+	}
+	ret := make(map[string]permColumn)
+	if !found || len(tables.Columns) == 0 {
+		return ret, nil
+	}
+	var cols map[string]string
+	if err = json.Unmarshal([]byte(tables.Columns), &cols); err != nil {
+		log.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err}).Error("getting table columns")
+		return nil, err
+	}
+	for column, cond := range cols {
+		if perm, err := getPermColumns(cond); err == nil {
+			ret[column] = perm
+		}
+	}
+	return ret, nil
+}
+
 // AccessColumns checks access rights to the columns
 func (sc *SmartContract) AccessColumns(table string, columns *[]string, update bool) error {
 	logger := sc.GetLogger()
@@ -689,6 +733,9 @@ func (sc *SmartContract) CallContract(flags int) (string, error) {
 		fuelRate                      decimal.Decimal
 	)
 	logger := sc.GetLogger()
+	if err = faults.Hit(faults.CallContract); err != nil {
+		return ``, err
+	}
 	payWallet := &model.Key{}
 	sc.TxContract.Extend = sc.getExtend()
 
@@ -703,6 +750,9 @@ func (sc *SmartContract) CallContract(flags int) (string, error) {
 	methods := []string{`init`, `conditions`, `action`, `rollback`}
 	sc.TxContract.StackCont = []string{sc.TxContract.Name}
 	(*sc.TxContract.Extend)[`stack_cont`] = StackCont
+	if sc.TxContract.Block.Info.(*script.ContractInfo).NonReentrant() {
+		script.LockContract(sc.TxContract.Extend, sc.TxContract.Name)
+	}
 	sc.VM = GetVM(sc.VDE, sc.TxSmart.EcosystemID)
 	if (flags&CallRollback) == 0 && (flags&CallAction) != 0 {
 		if !sc.VDE {
@@ -771,8 +821,17 @@ func (sc *SmartContract) CallContract(flags int) (string, error) {
 				}
 				fuelRate = fuelRate.Add(payOver)
 			}
-			isActive := sc.TxContract.Block.Info.(*script.ContractInfo).Owner.Active
-			if isActive {
+			var (
+				policy  string
+				sponsor int64
+			)
+			if policy, sponsor, err = getFeePolicy(sc); err != nil {
+				return retError(err)
+			}
+			isActive := policy != FeePayerCaller && sc.TxContract.Block.Info.(*script.ContractInfo).Owner.Active
+			if policy == FeePayerSponsor {
+				fromID = sponsor
+			} else if isActive {
 				fromID = sc.TxContract.Block.Info.(*script.ContractInfo).Owner.WalletID
 				sc.TxSmart.TokenEcosystem = sc.TxContract.Block.Info.(*script.ContractInfo).Owner.TokenID
 			} else if len(sc.TxSmart.PayOver) > 0 {
@@ -791,7 +850,7 @@ func (sc *SmartContract) CallContract(flags int) (string, error) {
 				logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting wallet")
 				return retError(err)
 			}
-			if !isActive && !bytes.Equal(wallet.PublicKey, payWallet.PublicKey) && !bytes.Equal(sc.TxSmart.PublicKey, payWallet.PublicKey) && sc.TxSmart.SignedBy == 0 {
+			if !isActive && policy != FeePayerSponsor && !bytes.Equal(wallet.PublicKey, payWallet.PublicKey) && !bytes.Equal(sc.TxSmart.PublicKey, payWallet.PublicKey) && sc.TxSmart.SignedBy == 0 {
 				return retError(ErrDiffKeys)
 			}
 			var amount decimal.Decimal
@@ -820,6 +879,9 @@ func (sc *SmartContract) CallContract(flags int) (string, error) {
 				logger.WithFields(log.Fields{"type": consts.NoFunds}).Error("current balance is not enough")
 				return retError(ErrCurrentBalance)
 			}
+			if err = sc.limitFuelByFee(fuelRate, sizeFuel, price); err != nil {
+				return retError(err)
+			}
 		}
 	}
 	before := (*sc.TxContract.Extend)[`txcost`].(int64) + price
@@ -843,6 +905,7 @@ func (sc *SmartContract) CallContract(flags int) (string, error) {
 		}
 	}
 	sc.TxUsedCost = decimal.New(before-(*sc.TxContract.Extend)[`txcost`].(int64), 0)
+	sc.TxPayer = fromID
 	sc.TxContract.TxPrice = price
 	if (*sc.TxContract.Extend)[`result`] != nil {
 		result = fmt.Sprint((*sc.TxContract.Extend)[`result`])
@@ -861,27 +924,9 @@ func (sc *SmartContract) CallContract(flags int) (string, error) {
 		if wltAmount.Cmp(apl) < 0 {
 			apl = wltAmount
 		}
-		commission := apl.Mul(decimal.New(syspar.SysInt64(`commission_size`), 0)).Div(decimal.New(100, 0)).Floor()
-		walletTable := fmt.Sprintf(`%d_keys`, sc.TxSmart.TokenEcosystem)
-		if _, _, ierr := sc.selectiveLoggingAndUpd([]string{`+amount`}, []interface{}{apl.Sub(commission)}, walletTable, []string{`id`},
-			[]string{converter.Int64ToStr(toID)}, true, true); ierr != nil {
-			if ierr != errUpdNotExistRecord {
-				return retError(ierr)
-			}
-			apl = commission
-		}
-		if _, _, ierr := sc.selectiveLoggingAndUpd([]string{`+amount`}, []interface{}{commission}, walletTable, []string{`id`},
-			[]string{syspar.GetCommissionWallet(sc.TxSmart.TokenEcosystem)}, true, true); ierr != nil {
-			if ierr != errUpdNotExistRecord {
-				return retError(ierr)
-			}
-			apl = apl.Sub(commission)
-		}
-		if _, _, ierr := sc.selectiveLoggingAndUpd([]string{`-amount`}, []interface{}{apl}, walletTable, []string{`id`},
-			[]string{converter.Int64ToStr(fromID)}, true, true); ierr != nil {
+		if ierr := sc.payFee(apl, toID, fromID); ierr != nil {
 			return retError(ierr)
 		}
-		logger.WithFields(log.Fields{"commission": commission}).Debug("Paid commission")
 	}
 	if err != nil {
 		return retError(err)
@@ -29,13 +29,16 @@ import (
 	"reflect"
 	"regexp"
 	"strings"
+	"time"
 
+	"github.com/GenesisKernel/go-genesis/packages/bridge"
 	"github.com/GenesisKernel/go-genesis/packages/config/syspar"
 	"github.com/GenesisKernel/go-genesis/packages/consts"
 	"github.com/GenesisKernel/go-genesis/packages/converter"
 	"github.com/GenesisKernel/go-genesis/packages/crypto"
 	"github.com/GenesisKernel/go-genesis/packages/language"
 	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/nodecert"
 	"github.com/GenesisKernel/go-genesis/packages/script"
 	"github.com/GenesisKernel/go-genesis/packages/utils"
 
@@ -50,6 +53,13 @@ var (
 		"DBUpdateSysParam": {},
 		"DBUpdateExt":      {},
 		"DBSelect":         {},
+		"DBCount":          {},
+		"DBSum":            {},
+		"DBMin":            {},
+		"DBMax":            {},
+		"DBInsertBatch":    {},
+		"DBUpdateBatch":    {},
+		"DBRestore":        {},
 	}
 
 	extendCostSysParams = map[string]string{
@@ -117,87 +127,95 @@ func getCostP(name string) int64 {
 	return -1
 }
 
-// UpdateSysParam updates the system parameter
-func UpdateSysParam(sc *SmartContract, name, value, conditions string) (int64, error) {
-	var (
-		fields []string
-		values []interface{}
-	)
+// getSysParamAccess returns the system parameter if the conditions of the parameter are satisfied
+func getSysParamAccess(sc *SmartContract, name string) (*model.SystemParameter, error) {
 	par := &model.SystemParameter{}
 	found, err := par.Get(name)
 	if err != nil {
 		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("system parameter get")
-		return 0, err
+		return nil, err
 	}
 	if !found {
 		log.WithFields(log.Fields{"type": consts.NotFound, "error": err}).Error("system parameter get")
-		return 0, fmt.Errorf(`Parameter %s has not been found`, name)
+		return nil, fmt.Errorf(`Parameter %s has not been found`, name)
 	}
 	cond := par.Conditions
 	if len(cond) > 0 {
 		ret, err := sc.EvalIf(cond)
 		if err != nil {
 			log.WithFields(log.Fields{"type": consts.EvalError, "error": err}).Error("evaluating conditions")
-			return 0, err
+			return nil, err
 		}
 		if !ret {
 			log.WithFields(log.Fields{"type": consts.AccessDenied}).Error("Access denied")
-			return 0, errAccessDenied
+			return nil, errAccessDenied
 		}
 	}
+	return par, nil
+}
+
+// UpdateSysParam updates the system parameter
+func UpdateSysParam(sc *SmartContract, name, value, conditions string) (int64, error) {
+	par, err := getSysParamAccess(sc, name)
+	if err != nil {
+		return 0, err
+	}
+	return setSysParam(sc, par, value, conditions)
+}
+
+// ScheduleSysParam schedules the change of the system parameter at the block, so all nodes
+// switch to the new value at the same block. It returns the identifier of the scheduled change
+func ScheduleSysParam(sc *SmartContract, name, value, conditions string, activationBlock int64) (int64, error) {
+	par, err := getSysParamAccess(sc, name)
+	if err != nil {
+		return 0, err
+	}
+	if activationBlock <= currentBlockID(sc) {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "activation_block": activationBlock}).Error("activation block is in the past")
+		return 0, fmt.Errorf(`activation block %d must be greater than the current block`, activationBlock)
+	}
+	if len(value) == 0 && len(conditions) == 0 {
+		log.WithFields(log.Fields{"type": consts.EmptyObject}).Error("empty value and condition")
+		return 0, fmt.Errorf(`empty value and condition`)
+	}
 	if len(value) > 0 {
-		var (
-			ok, checked bool
-			list        [][]string
-		)
-		ival := converter.StrToInt64(value)
-	check:
-		switch name {
-		case `gap_between_blocks`:
-			ok = ival > 0 && ival < 86400
-		case `rb_blocks_1`, `number_of_nodes`:
-			ok = ival > 0 && ival < 1000
-		case `ecosystem_price`, `contract_price`, `column_price`, `table_price`, `menu_price`,
-			`page_price`, `commission_size`:
-			ok = ival >= 0
-		case `max_block_size`, `max_tx_size`, `max_tx_count`, `max_columns`, `max_indexes`,
-			`max_block_user_tx`, `max_fuel_tx`, `max_fuel_block`:
-			ok = ival > 0
-		case `fuel_rate`, `full_nodes`, `commission_wallet`:
-			err := json.Unmarshal([]byte(value), &list)
-			if err != nil {
-				log.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err}).Error("unmarshalling system param")
-				return 0, err
-			}
-			for _, item := range list {
-				switch name {
-				case `fuel_rate`, `commission_wallet`:
-					if len(item) != 2 || converter.StrToInt64(item[0]) <= 0 ||
-						(name == `fuel_rate` && converter.StrToInt64(item[1]) <= 0) ||
-						(name == `commission_wallet` && converter.StrToInt64(item[1]) == 0) {
-						break check
-					}
-				case `full_nodes`:
-					if len(item) != 3 {
-						break check
-					}
-					key := converter.StrToInt64(item[1])
-					if key == 0 || len(item[2]) != 128 || len(item[0]) == 0 {
-						break check
-					}
-				}
-			}
-			checked = true
-		default:
-			if strings.HasPrefix(name, `extend_cost_`) {
-				ok = ival >= 0
-				break
-			}
-			checked = true
+		if err = checkSysParamValue(par.Name, value); err != nil {
+			return 0, err
 		}
-		if !checked && (!ok || converter.Int64ToStr(ival) != value) {
-			log.WithFields(log.Fields{"type": consts.InvalidObject, "value": value, "name": name}).Error(ErrInvalidValue.Error())
-			return 0, ErrInvalidValue
+		if err = checkActivationBlock(par, value, activationBlock); err != nil {
+			return 0, err
+		}
+	}
+	if len(conditions) > 0 {
+		if err = CompileEval(conditions, 0); err != nil {
+			log.WithFields(log.Fields{"error": err, "conditions": conditions, "state_id": 0, "type": consts.EvalError}).Error("compiling eval")
+			return 0, err
+		}
+	}
+	_, id, err := sc.selectiveLoggingAndUpd([]string{`name`, `value`, `conditions`, `activation_block`},
+		[]interface{}{par.Name, value, conditions, activationBlock}, `system_parameters_schedule`, nil, nil,
+		!sc.VDE && sc.Rollback, false)
+	if err != nil {
+		return 0, err
+	}
+	if err = sc.writeAudit(model.AuditSysParam, par.Name); err != nil {
+		return 0, err
+	}
+	return converter.StrToInt64(id), nil
+}
+
+// setSysParam writes the value and the conditions of the system parameter
+func setSysParam(sc *SmartContract, par *model.SystemParameter, value, conditions string) (int64, error) {
+	var (
+		fields []string
+		values []interface{}
+	)
+	if len(value) > 0 {
+		if err := checkSysParamValue(par.Name, value); err != nil {
+			return 0, err
+		}
+		if err := checkActivationBlock(par, value, currentBlockID(sc)); err != nil {
+			return 0, err
 		}
 		fields = append(fields, "value")
 		values = append(values, value)
@@ -214,7 +232,7 @@ func UpdateSysParam(sc *SmartContract, name, value, conditions string) (int64, e
 		log.WithFields(log.Fields{"type": consts.EmptyObject}).Error("empty value and condition")
 		return 0, fmt.Errorf(`empty value and condition`)
 	}
-	_, _, err = sc.selectiveLoggingAndUpd(fields, values, "system_parameters", []string{"id"}, []string{converter.Int64ToStr(par.ID)}, !sc.VDE && sc.Rollback, false)
+	_, _, err := sc.selectiveLoggingAndUpd(fields, values, "system_parameters", []string{"id"}, []string{converter.Int64ToStr(par.ID)}, !sc.VDE && sc.Rollback, false)
 	if err != nil {
 		return 0, err
 	}
@@ -224,9 +242,186 @@ func UpdateSysParam(sc *SmartContract, name, value, conditions string) (int64, e
 		return 0, err
 	}
 	sc.SysUpdate = true
+	if err = sc.writeAudit(model.AuditSysParam, par.Name); err != nil {
+		return 0, err
+	}
 	return 0, nil
 }
 
+// writeAudit records the action in the audit log, the hash of transaction is used as the digest of the request.
+// The audit log is append-only and can't be rolled back, so the records of blockchain transactions
+// are kept in the contract and written by the parser once the block is committed
+func (sc *SmartContract) writeAudit(action, target string) error {
+	audit := &model.AuditLog{Time: sc.TxSmart.Time, KeyID: sc.TxSmart.KeyID, Ecosystem: sc.TxSmart.EcosystemID,
+		Action: action, Target: target, Digest: sc.TxHash}
+	if !sc.VDE {
+		sc.AuditLog = append(sc.AuditLog, audit)
+		return nil
+	}
+	if err := audit.Create(sc.DbTransaction); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("writing audit log")
+		return err
+	}
+	return nil
+}
+
+// checkActivationBlock checks the change of the parameter which activates the new rules of blocks
+// at the block. The activation can't be moved once the block is reached and it can't be set in the past
+func checkActivationBlock(par *model.SystemParameter, value string, blockID int64) error {
+	switch par.Name {
+	case syspar.StateHashBlock, syspar.TxNonceBlock, syspar.FeeRewardBlock:
+	default:
+		return nil
+	}
+	cur, ival := converter.StrToInt64(par.Value), converter.StrToInt64(value)
+	if cur > 0 && cur <= blockID {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "name": par.Name, "value": cur}).Error("parameter has been activated")
+		return fmt.Errorf(`%s has been activated at block %d`, par.Name, cur)
+	}
+	if ival > 0 && ival <= blockID {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "name": par.Name, "value": ival}).Error("activation block is in the past")
+		return fmt.Errorf(`activation block %d must be greater than the current block`, ival)
+	}
+	return nil
+}
+
+// checkNodeCert checks the certificate of the item of full_nodes. The expiration isn't checked here
+// because the result must not depend on the time of the replaying of block
+func checkNodeCert(item []string, ca []byte) error {
+	if len(item) < 4 {
+		return fmt.Errorf("full node %s has no certificate", item[0])
+	}
+	pub, err := hex.DecodeString(item[2])
+	if err != nil {
+		return err
+	}
+	if err = nodecert.VerifyNode(item[3], pub, ca, time.Unix(0, 0)); err != nil {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "error": err, "host": item[0]}).Error("checking certificate of full node")
+		return fmt.Errorf("full node %s: %s", item[0], err)
+	}
+	return nil
+}
+
+// checkSysParamValue checks the new value of the system parameter
+func checkSysParamValue(name, value string) error {
+	var (
+		ok, checked bool
+		list        [][]string
+	)
+	ival := converter.StrToInt64(value)
+check:
+	switch name {
+	case `gap_between_blocks`:
+		ok = ival > 0 && ival < 86400
+	case `rb_blocks_1`, `number_of_nodes`:
+		ok = ival > 0 && ival < 1000
+	case `ecosystem_price`, `contract_price`, `column_price`, `table_price`, `menu_price`,
+		`page_price`, `commission_size`:
+		ok = ival >= 0
+	case `max_block_size`, `max_tx_size`, `max_tx_count`, `max_columns`, `max_indexes`,
+		`max_block_user_tx`, `max_fuel_tx`, `max_fuel_block`, `max_aggregate_rows`:
+		ok = ival > 0
+	case `max_call_depth`:
+		ok = ival >= 0 && ival <= 1000
+	case syspar.StakingValidators:
+		ok = ival >= 0 && ival < 1000
+	case syspar.StakingUnbondingBlocks, syspar.RecoveryMinDelay, syspar.StateHashBlock, syspar.TxNonceBlock,
+		syspar.FeeRewardBlock:
+		ok = ival >= 0
+	case syspar.StakingMinStake:
+		stake, err := decimal.NewFromString(value)
+		ok = err == nil && stake.Sign() >= 0 && stake.Equal(stake.Floor())
+		checked = ok
+	case `fuel_rate`, `full_nodes`, `commission_wallet`:
+		err := json.Unmarshal([]byte(value), &list)
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err}).Error("unmarshalling system param")
+			return err
+		}
+		for _, item := range list {
+			switch name {
+			case `fuel_rate`, `commission_wallet`:
+				if len(item) != 2 || converter.StrToInt64(item[0]) <= 0 ||
+					(name == `fuel_rate` && converter.StrToInt64(item[1]) <= 0) ||
+					(name == `commission_wallet` && converter.StrToInt64(item[1]) == 0) {
+					break check
+				}
+			case `full_nodes`:
+				if len(item) != 3 && len(item) != 4 {
+					break check
+				}
+				key := converter.StrToInt64(item[1])
+				if key == 0 || len(item[2]) != 128 || len(item[0]) == 0 {
+					break check
+				}
+				if ca := syspar.GetNetworkCA(); ca != nil {
+					if err := checkNodeCert(item, ca); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		checked = true
+	case syspar.BridgeNetworkID:
+		ok = ival >= 0
+	case syspar.NetworkCA:
+		if len(value) == 0 {
+			checked = true
+			break
+		}
+		ca, err := hex.DecodeString(value)
+		if err != nil || len(ca) != 64 {
+			break
+		}
+		// the current full nodes must be certified by the new CA, otherwise they can't connect to each other
+		for _, item := range syspar.GetFullNodes() {
+			if err = checkNodeCert(item, ca); err != nil {
+				return err
+			}
+		}
+		checked = true
+	case syspar.SystemTxReserve:
+		ok = ival >= 0 && ival <= 100
+	case syspar.SystemTxContracts:
+		if _, err := syspar.ParseSystemTxContracts(value); err != nil {
+			log.WithFields(log.Fields{"type": consts.InvalidObject, "error": err}).Error("parsing system tx contracts")
+			return err
+		}
+		checked = true
+	case syspar.MaxTxSizeByType:
+		if _, err := syspar.ParseTxSizes(value); err != nil {
+			log.WithFields(log.Fields{"type": consts.InvalidObject, "error": err}).Error("parsing max tx sizes")
+			return err
+		}
+		checked = true
+	case syspar.FeeRouting:
+		if len(value) > 0 {
+			if _, err := syspar.ParseFeeRouting(value); err != nil {
+				log.WithFields(log.Fields{"type": consts.InvalidObject, "error": err}).Error("parsing fee routing")
+				return err
+			}
+		}
+		checked = true
+	case syspar.BridgeNetworks:
+		if _, err := bridge.ParseNetworks(value); err != nil {
+			log.WithFields(log.Fields{"type": consts.InvalidObject, "error": err}).Error("parsing bridge networks")
+			return err
+		}
+		checked = true
+	default:
+		if strings.HasPrefix(name, `extend_cost_`) {
+			ok = ival >= 0
+			break
+		}
+		checked = true
+	}
+	if !checked && (!ok || converter.Int64ToStr(ival) != value) {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "value": value, "name": name}).Error(ErrInvalidValue.Error())
+		return ErrInvalidValue
+	}
+	return nil
+}
+
 // DBUpdateExt updates the record in the specified table. You can specify 'where' query in params and then the values for this query
 func DBUpdateExt(sc *SmartContract, tblname string, column string, value interface{},
 	params string, val ...interface{}) (qcost int64, err error) {
@@ -242,6 +437,29 @@ func DBUpdateExt(sc *SmartContract, tblname string, column string, value interfa
 	if err = sc.AccessColumns(tblname, &columns, true); err != nil {
 		return
 	}
+	if err = sc.checkRowUpdate(tblname, converter.EscapeName(column)+` = ?`, fmt.Sprint(value)); err != nil {
+		return
+	}
+	if err = sc.checkReferences(tblname, columns, val); err != nil {
+		return
+	}
+	if marker, ok := deletedMarker(columns, val); ok {
+		var rows []map[string]string
+		rows, err = model.GetAllTransaction(sc.DbTransaction, `SELECT id FROM "`+tblname+`" WHERE `+
+			converter.EscapeName(column)+` = ?`, -1, fmt.Sprint(value))
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting updated rows")
+			return
+		}
+		for _, row := range rows {
+			if err = sc.markDeleted(tblname, converter.StrToInt64(row[`id`]), marker, 0); err != nil {
+				return
+			}
+		}
+	}
+	if val, err = sc.encryptColumns(tblname, columns, val); err != nil {
+		return
+	}
 	qcost, _, err = sc.selectiveLoggingAndUpd(columns, val, tblname, []string{column}, []string{fmt.Sprint(value)}, !sc.VDE && sc.Rollback, false)
 	return
 }
@@ -396,9 +614,7 @@ func CreateEcosystem(sc *SmartContract, wallet int64, name string) (int64, error
 		log.WithFields(log.Fields{"type": consts.IncorrectCallingContract}).Error("CreateEcosystem can be only called from @1NewEcosystem")
 		return 0, fmt.Errorf(`CreateEcosystem can be only called from @1NewEcosystem`)
 	}
-	_, id, err := sc.selectiveLoggingAndUpd(nil, nil, `system_states`, nil, nil, !sc.VDE && sc.Rollback, false)
-	if err != nil {
-		log.WithFields(log.Fields{"type": consts.DBError}).Error("CreateEcosystem")
+	if err := checkEcosystemQuota(sc, wallet); err != nil {
 		return 0, err
 	}
 	var sp model.StateParameter
@@ -412,6 +628,14 @@ func CreateEcosystem(sc *SmartContract, wallet int64, name string) (int64, error
 		log.WithFields(log.Fields{"type": consts.NotFound, "error": ErrFounderAccount}).Error("founder not found")
 		return 0, ErrFounderAccount
 	}
+	if err := chargeEcosystemFee(sc, wallet, converter.StrToInt64(sp.Value)); err != nil {
+		return 0, err
+	}
+	_, id, err := sc.selectiveLoggingAndUpd([]string{`key_id`}, []interface{}{wallet}, `system_states`, nil, nil, !sc.VDE && sc.Rollback, false)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError}).Error("CreateEcosystem")
+		return 0, err
+	}
 	err = model.ExecSchemaEcosystem(sc.DbTransaction, converter.StrToInt(id), wallet, name,
 		converter.StrToInt64(sp.Value))
 	if err != nil {
@@ -66,7 +66,7 @@ func TestNewContract(t *testing.T) {
 	}
 	cnt := GetContract(`NewCitizen`, 1)
 	cfunc := cnt.GetFunc(`conditions`)
-	_, err := Run(cfunc, nil, &map[string]interface{}{})
+	_, err := Run(cfunc, nil, &map[string]interface{}{`sc`: &SmartContract{TxHash: []byte(`hash`)}})
 	if err != nil {
 		t.Error(err)
 	}
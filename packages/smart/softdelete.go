@@ -0,0 +1,135 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// deletedAtColumn is the column which marks the row as softly deleted. The row is deleted
+	// if the column is neither null nor zero
+	deletedAtColumn = `deleted_at`
+	// NotDeletedRows is the condition of the rows which haven't been softly deleted
+	NotDeletedRows = `coalesce("deleted_at"::text, '0') in ('', '0')`
+)
+
+// regexpDeletedAt matches the conditions which refer to deleted_at column
+var regexpDeletedAt = regexp.MustCompile(`(?i)(^|[^a-z0-9_])deleted_at([^a-z0-9_]|$)`)
+
+// excludeDeleted appends the filter of the softly deleted rows to where condition. The condition which
+// refers to deleted_at column selects the deleted rows explicitly, so it is left as is
+func excludeDeleted(where string) string {
+	if regexpDeletedAt.MatchString(where) {
+		return where
+	}
+	if len(where) > 0 {
+		return `(` + where + `) and ` + NotDeletedRows
+	}
+	return NotDeletedRows
+}
+
+// hasDeletedAt returns true if the table follows the convention of soft deletion
+func (sc *SmartContract) hasDeletedAt(table string) (bool, error) {
+	perms, err := sc.columnPerms(table)
+	if err != nil {
+		return false, err
+	}
+	_, ok := perms[deletedAtColumn]
+	return ok, nil
+}
+
+// filterDeleted excludes the softly deleted rows from the selection of the table
+func (sc *SmartContract) filterDeleted(table, where string) (string, error) {
+	ok, err := sc.hasDeletedAt(table)
+	if err != nil || !ok {
+		return where, err
+	}
+	return excludeDeleted(where), nil
+}
+
+// checkNotDeleted forbids to update the softly deleted row unless the update changes deleted_at
+func (sc *SmartContract) checkNotDeleted(table string, id int64, columns []string) error {
+	for _, column := range columns {
+		if strings.ToLower(strings.TrimSpace(column)) == deletedAtColumn {
+			return nil
+		}
+	}
+	ok, err := sc.hasDeletedAt(table)
+	if err != nil || !ok {
+		return err
+	}
+	found, err := model.GetOneRowTransaction(sc.DbTransaction, `SELECT id FROM "`+table+`" WHERE id = ? and `+
+		NotDeletedRows, id).String()
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting deleted row")
+		return err
+	}
+	if len(found) == 0 {
+		return fmt.Errorf(`row %d has been deleted`, id)
+	}
+	return nil
+}
+
+// DBRestore restores the softly deleted row of the table
+func DBRestore(sc *SmartContract, tblname string, id int64) (qcost int64, err error) {
+	tblname = getDefTableName(sc, tblname)
+	if err = sc.AccessTable(tblname, "update"); err != nil {
+		return
+	}
+	ok, err := sc.hasDeletedAt(tblname)
+	if err != nil {
+		return
+	}
+	if !ok {
+		err = fmt.Errorf(`table %s doesn't have %s column`, tblname, deletedAtColumn)
+		return
+	}
+	columns := []string{deletedAtColumn}
+	if err = sc.AccessColumns(tblname, &columns, true); err != nil {
+		return
+	}
+	if err = sc.checkRowUpdate(tblname, `id = ?`, id); err != nil {
+		return
+	}
+	coltype, err := model.GetColumnType(tblname, deletedAtColumn)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting column type")
+		return
+	}
+	value := `0`
+	if coltype != `number` && coltype != `money` && coltype != `double` {
+		value = `NULL`
+	}
+	qcost, _, err = sc.selectiveLoggingAndUpd(columns, []interface{}{value}, tblname, []string{`id`},
+		[]string{converter.Int64ToStr(id)}, !sc.VDE && sc.Rollback, true)
+	return
+}
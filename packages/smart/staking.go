@@ -0,0 +1,306 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/GenesisKernel/go-genesis/packages/config/syspar"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+)
+
+// stakingEcosystem is the ecosystem which tokens are bonded
+const stakingEcosystem = 1
+
+var (
+	// ErrStakingDisabled is returned if staking_validators is 0
+	ErrStakingDisabled = errors.New(`Election of validators by stake is disabled`)
+	// ErrNoCandidates is returned if there are no candidates with enough stake
+	ErrNoCandidates = errors.New(`There are no candidates with enough stake`)
+)
+
+func getCandidate(sc *SmartContract, id int64, active bool) (*model.ValidatorCandidate, error) {
+	candidate := &model.ValidatorCandidate{}
+	found, err := candidate.Get(sc.DbTransaction, id)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting validator candidate")
+		return nil, err
+	}
+	if !found || (active && candidate.Active == 0) {
+		log.WithFields(log.Fields{"type": consts.NotFound, "validator": id}).Error("validator candidate not found")
+		return nil, fmt.Errorf(`validator %s has not been found`, converter.AddressToString(id))
+	}
+	return candidate, nil
+}
+
+// updateStake adds the amount to the stake of the candidate, the fields are +amount or -amount
+func updateStake(sc *SmartContract, table, field string, amount decimal.Decimal, whereFields, whereValues []string) error {
+	_, _, err := sc.selectiveLoggingAndUpd([]string{field}, []interface{}{amount}, table, whereFields, whereValues,
+		!sc.VDE && sc.Rollback, field[0] == '-')
+	return err
+}
+
+// RegisterValidator registers the caller as the candidate for full_nodes or updates the data of its node
+func RegisterValidator(sc *SmartContract, host, publicKey, cert string) error {
+	if pub, err := hex.DecodeString(publicKey); err != nil || len(pub) != 64 || len(host) == 0 {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "host": host}).Error("wrong host or public key of node")
+		return fmt.Errorf(`wrong host or public key of node`)
+	}
+	keyID := converter.Int64ToStr(sc.TxSmart.KeyID)
+	if ca := syspar.GetNetworkCA(); ca != nil {
+		if err := checkNodeCert([]string{host, keyID, publicKey, cert}, ca); err != nil {
+			return err
+		}
+	}
+	_, _, err := sc.selectiveLoggingAndUpd([]string{`host`, `public_key`, `cert`, `active`},
+		[]interface{}{host, publicKey, cert, 1}, model.ValidatorCandidate{}.TableName(), []string{`id`},
+		[]string{keyID}, !sc.VDE && sc.Rollback, false)
+	return err
+}
+
+// DeregisterValidator excludes the caller from the next elections, the bonded stakes stay until they are unbonded
+func DeregisterValidator(sc *SmartContract) error {
+	if _, err := getCandidate(sc, sc.TxSmart.KeyID, true); err != nil {
+		return err
+	}
+	_, _, err := sc.selectiveLoggingAndUpd([]string{`active`}, []interface{}{0}, model.ValidatorCandidate{}.TableName(),
+		[]string{`id`}, []string{converter.Int64ToStr(sc.TxSmart.KeyID)}, !sc.VDE && sc.Rollback, true)
+	return err
+}
+
+// Bond withdraws the amount from the wallet of the caller and bonds it to the active candidate
+func Bond(sc *SmartContract, validator int64, amount string) error {
+	if _, err := getCandidate(sc, validator, true); err != nil {
+		return err
+	}
+	value, err := assetAmount(amount)
+	if err != nil {
+		return err
+	}
+	if err = walletDebit(sc, stakingEcosystem, sc.TxSmart.KeyID, value.String()); err != nil {
+		return err
+	}
+	if err = updateStake(sc, model.Stake{}.TableName(), `+amount`, value, []string{`key_id`, `validator`},
+		[]string{converter.Int64ToStr(sc.TxSmart.KeyID), converter.Int64ToStr(validator)}); err != nil {
+		return err
+	}
+	return updateStake(sc, model.ValidatorCandidate{}.TableName(), `+stake`, value, []string{`id`},
+		[]string{converter.Int64ToStr(validator)})
+}
+
+// Unbond decreases the stake of the caller bonded to the validator. The amount can be withdrawn
+// after staking_unbonding_blocks blocks, until then it can be slashed. It returns the identifier of unbonding
+func Unbond(sc *SmartContract, validator int64, amount string) (int64, error) {
+	value, err := assetAmount(amount)
+	if err != nil {
+		return 0, err
+	}
+	stake := &model.Stake{}
+	found, err := stake.Get(sc.DbTransaction, sc.TxSmart.KeyID, validator)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting stake")
+		return 0, err
+	}
+	if found {
+		bonded, err := decimal.NewFromString(stake.Amount)
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.ConversionError, "error": err, "value": stake.Amount}).Error("converting stake to decimal")
+			return 0, err
+		}
+		found = bonded.Cmp(value) >= 0
+	}
+	if !found {
+		log.WithFields(log.Fields{"type": consts.NoFunds, "validator": validator}).Error("stake is not enough")
+		return 0, fmt.Errorf(`stake is not enough`)
+	}
+	if err = updateStake(sc, model.Stake{}.TableName(), `-amount`, value, []string{`id`},
+		[]string{converter.Int64ToStr(stake.ID)}); err != nil {
+		return 0, err
+	}
+	if err = updateStake(sc, model.ValidatorCandidate{}.TableName(), `-stake`, value, []string{`id`},
+		[]string{converter.Int64ToStr(validator)}); err != nil {
+		return 0, err
+	}
+	_, id, err := sc.selectiveLoggingAndUpd([]string{`key_id`, `validator`, `amount`, `release_block`, `claimed`},
+		[]interface{}{sc.TxSmart.KeyID, validator, value, currentBlockID(sc) + syspar.GetStakingUnbondingBlocks(), 0},
+		model.Unbonding{}.TableName(), nil, nil, !sc.VDE && sc.Rollback, false)
+	if err != nil {
+		return 0, err
+	}
+	return converter.StrToInt64(id), nil
+}
+
+// WithdrawUnbonded pays the released unbonding to the wallet of the caller
+func WithdrawUnbonded(sc *SmartContract, id int64) error {
+	unbonding := &model.Unbonding{}
+	found, err := unbonding.Get(sc.DbTransaction, id)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting unbonding")
+		return err
+	}
+	if !found || unbonding.KeyID != sc.TxSmart.KeyID || unbonding.Claimed != 0 {
+		log.WithFields(log.Fields{"type": consts.NotFound, "id": id}).Error("unbonding not found")
+		return fmt.Errorf(`unbonding %d has not been found`, id)
+	}
+	if currentBlockID(sc) < unbonding.ReleaseBlock {
+		return fmt.Errorf(`unbonding %d is released at block %d`, id, unbonding.ReleaseBlock)
+	}
+	if err = walletCredit(sc, stakingEcosystem, unbonding.KeyID, unbonding.Amount); err != nil {
+		return err
+	}
+	_, _, err = sc.selectiveLoggingAndUpd([]string{`claimed`}, []interface{}{1}, model.Unbonding{}.TableName(),
+		[]string{`id`}, []string{converter.Int64ToStr(id)}, !sc.VDE && sc.Rollback, true)
+	return err
+}
+
+// slashedPart returns the percent of the amount rounded down
+func slashedPart(amount string, percent int64) (decimal.Decimal, error) {
+	value, err := decimal.NewFromString(amount)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.ConversionError, "error": err, "value": amount}).Error("converting stake to decimal")
+		return value, err
+	}
+	return value.Mul(decimal.New(percent, 0)).Div(decimal.New(100, 0)).Floor(), nil
+}
+
+// SlashValidator burns the percent of the stakes bonded to the validator and of the unbondings
+// which haven't been released yet. It returns the slashed amount
+func SlashValidator(sc *SmartContract, validator, percent int64) (string, error) {
+	if !accessContracts(sc, `SlashStake`) {
+		log.WithFields(log.Fields{"type": consts.IncorrectCallingContract}).Error("SlashValidator can be only called from @1SlashStake")
+		return ``, fmt.Errorf(`SlashValidator can be only called from SlashStake`)
+	}
+	if percent <= 0 || percent > 100 {
+		return ``, fmt.Errorf(`wrong percent %d`, percent)
+	}
+	if _, err := getCandidate(sc, validator, false); err != nil {
+		return ``, err
+	}
+	stakes, err := model.GetStakesOfValidator(sc.DbTransaction, validator)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting stakes of validator")
+		return ``, err
+	}
+	bonded := decimal.Zero
+	for _, stake := range stakes {
+		cut, err := slashedPart(stake.Amount, percent)
+		if err != nil {
+			return ``, err
+		}
+		if cut.Sign() <= 0 {
+			continue
+		}
+		if err = updateStake(sc, model.Stake{}.TableName(), `-amount`, cut, []string{`id`},
+			[]string{converter.Int64ToStr(stake.ID)}); err != nil {
+			return ``, err
+		}
+		bonded = bonded.Add(cut)
+	}
+	if bonded.Sign() > 0 {
+		if err = updateStake(sc, model.ValidatorCandidate{}.TableName(), `-stake`, bonded, []string{`id`},
+			[]string{converter.Int64ToStr(validator)}); err != nil {
+			return ``, err
+		}
+	}
+	unbondings, err := model.GetPendingUnbondings(sc.DbTransaction, validator, currentBlockID(sc))
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting unbondings of validator")
+		return ``, err
+	}
+	total := bonded
+	for _, unbonding := range unbondings {
+		cut, err := slashedPart(unbonding.Amount, percent)
+		if err != nil {
+			return ``, err
+		}
+		if cut.Sign() <= 0 {
+			continue
+		}
+		if err = updateStake(sc, model.Unbonding{}.TableName(), `-amount`, cut, []string{`id`},
+			[]string{converter.Int64ToStr(unbonding.ID)}); err != nil {
+			return ``, err
+		}
+		total = total.Add(cut)
+	}
+	return total.String(), nil
+}
+
+// electedNodes returns the value of full_nodes for the elected candidates
+func electedNodes(candidates []model.ValidatorCandidate) (string, error) {
+	nodes := make([][]string, 0, len(candidates))
+	for _, c := range candidates {
+		item := []string{c.Host, converter.Int64ToStr(c.ID), c.PublicKey}
+		if len(c.Cert) > 0 {
+			item = append(item, c.Cert)
+		}
+		nodes = append(nodes, item)
+	}
+	out, err := json.Marshal(nodes)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Error("marshalling full nodes")
+		return ``, err
+	}
+	return string(out), nil
+}
+
+// ElectValidators writes staking_validators active candidates with the greatest stakes to full_nodes
+// in order of decreasing stake. The election is allowed by the conditions of full_nodes.
+// It returns the number of elected validators
+func ElectValidators(sc *SmartContract) (int64, error) {
+	if !accessContracts(sc, `ElectNodes`) {
+		log.WithFields(log.Fields{"type": consts.IncorrectCallingContract}).Error("ElectValidators can be only called from @1ElectNodes")
+		return 0, fmt.Errorf(`ElectValidators can be only called from ElectNodes`)
+	}
+	par, err := getSysParamAccess(sc, syspar.FullNodes)
+	if err != nil {
+		return 0, err
+	}
+	count := syspar.GetStakingValidators()
+	if count <= 0 {
+		return 0, ErrStakingDisabled
+	}
+	candidates, err := model.GetElectableCandidates(sc.DbTransaction, syspar.GetStakingMinStake(), count)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting electable candidates")
+		return 0, err
+	}
+	if len(candidates) == 0 {
+		return 0, ErrNoCandidates
+	}
+	value, err := electedNodes(candidates)
+	if err != nil {
+		return 0, err
+	}
+	if _, err = setSysParam(sc, par, value, ``); err != nil {
+		return 0, err
+	}
+	return int64(len(candidates)), nil
+}
@@ -0,0 +1,68 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"testing"
+
+	"github.com/GenesisKernel/go-genesis/packages/model"
+)
+
+func TestSlashedPart(t *testing.T) {
+	cases := []struct {
+		amount  string
+		percent int64
+		want    string
+	}{
+		{`1000`, 10, `100`},
+		{`999`, 10, `99`},
+		{`5`, 100, `5`},
+		{`1`, 50, `0`},
+	}
+	for _, c := range cases {
+		got, err := slashedPart(c.amount, c.percent)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.String() != c.want {
+			t.Errorf("%d%% of %s: expected %s, got %s", c.percent, c.amount, c.want, got)
+		}
+	}
+	if _, err := slashedPart(`abc`, 10); err == nil {
+		t.Error("expected error for wrong amount")
+	}
+}
+
+func TestElectedNodes(t *testing.T) {
+	value, err := electedNodes([]model.ValidatorCandidate{
+		{ID: 100, Host: `10.0.0.1:7078`, PublicKey: `ab`},
+		{ID: 200, Host: `10.0.0.2:7078`, PublicKey: `cd`, Cert: `cert`},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[["10.0.0.1:7078","100","ab"],["10.0.0.2:7078","200","cd","cert"]]`
+	if value != want {
+		t.Errorf("expected %s, got %s", want, value)
+	}
+}
@@ -0,0 +1,97 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// maxTxVars is the maximum count of the variables of the transaction
+const maxTxVars = 256
+
+var regexpTxVar = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]{0,63}$`)
+
+// copyTxValue returns the copy of maps and arrays so the contracts don't share the changes of the stored value
+func copyTxValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		ret := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			ret[key] = copyTxValue(item)
+		}
+		return ret
+	case []interface{}:
+		ret := make([]interface{}, len(v))
+		for i, item := range v {
+			ret[i] = copyTxValue(item)
+		}
+		return ret
+	}
+	return value
+}
+
+func checkTxVar(name string) error {
+	if !regexpTxVar.MatchString(name) {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "name": name}).Error("wrong name of transaction variable")
+		return fmt.Errorf(`wrong name of transaction variable %s`, name)
+	}
+	return nil
+}
+
+// SetTxVar sets the variable which is available to all contracts called in the transaction
+func SetTxVar(sc *SmartContract, name string, value interface{}) error {
+	if err := checkTxVar(name); err != nil {
+		return err
+	}
+	if sc.txVars == nil {
+		sc.txVars = make(map[string]interface{})
+	}
+	if _, ok := sc.txVars[name]; !ok && len(sc.txVars) >= maxTxVars {
+		log.WithFields(log.Fields{"type": consts.ParameterExceeded, "max_size": maxTxVars}).Error("too many transaction variables")
+		return fmt.Errorf(`Too many transaction variables. Limit is %d`, maxTxVars)
+	}
+	sc.txVars[name] = copyTxValue(value)
+	return nil
+}
+
+// GetTxVar returns the variable of the transaction or empty string if it hasn't been set
+func GetTxVar(sc *SmartContract, name string) (interface{}, error) {
+	if err := checkTxVar(name); err != nil {
+		return nil, err
+	}
+	if value, ok := sc.txVars[name]; ok {
+		return copyTxValue(value), nil
+	}
+	return ``, nil
+}
+
+// HasTxVar returns true if the variable of the transaction has been set
+func HasTxVar(sc *SmartContract, name string) bool {
+	_, ok := sc.txVars[name]
+	return ok
+}
@@ -0,0 +1,67 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTxVars(t *testing.T) {
+	sc := &SmartContract{}
+	if HasTxVar(sc, `total`) {
+		t.Error(`variable must be undefined`)
+	}
+	if v, err := GetTxVar(sc, `total`); err != nil || v != `` {
+		t.Errorf(`wrong undefined variable %v %v`, v, err)
+	}
+	data := map[string]interface{}{`list`: []interface{}{int64(1)}}
+	if err := SetTxVar(sc, `data`, data); err != nil {
+		t.Fatal(err)
+	}
+	// the changes of the source value must not change the stored variable
+	data[`list`].([]interface{})[0] = int64(2)
+	v, err := GetTxVar(sc, `data`)
+	if err != nil || !reflect.DeepEqual(v, map[string]interface{}{`list`: []interface{}{int64(1)}}) {
+		t.Errorf(`wrong variable %v %v`, v, err)
+	}
+	if !HasTxVar(sc, `data`) {
+		t.Error(`variable must be defined`)
+	}
+	for _, name := range []string{``, `1a`, `a-b`, `$a`} {
+		if err := SetTxVar(sc, name, 1); err == nil {
+			t.Errorf(`%s: expected error`, name)
+		}
+	}
+	for i := len(sc.txVars); i < maxTxVars; i++ {
+		if err := SetTxVar(sc, `v`+Str(i), i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := SetTxVar(sc, `extra`, 1); err == nil {
+		t.Error(`expected error of limit`)
+	}
+	if err := SetTxVar(sc, `data`, 1); err != nil {
+		t.Errorf(`existing variable must be changed %v`, err)
+	}
+}
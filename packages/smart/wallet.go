@@ -0,0 +1,84 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package smart
+
+import (
+	"fmt"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+)
+
+// currentBlockID returns the identifier of the block which is being processed
+func currentBlockID(sc *SmartContract) int64 {
+	if sc.BlockData != nil {
+		return sc.BlockData.BlockID
+	}
+	return 0
+}
+
+// walletDebit checks the balance of the wallet and withdraws the amount
+func walletDebit(sc *SmartContract, ecosystem, wallet int64, amount string) error {
+	key := &model.Key{}
+	key.SetTablePrefix(ecosystem)
+	found, err := key.GetTx(sc.DbTransaction, wallet)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting wallet")
+		return err
+	}
+	if !found {
+		log.WithFields(log.Fields{"type": consts.NotFound, "wallet": wallet}).Error("wallet not found")
+		return fmt.Errorf(`wallet %s has not been found`, converter.AddressToString(wallet))
+	}
+	balance, err := decimal.NewFromString(key.Amount)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.ConversionError, "error": err, "value": key.Amount}).Error("converting wallet amount from string to decimal")
+		return err
+	}
+	value, err := decimal.NewFromString(amount)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.ConversionError, "error": err, "value": amount}).Error("converting amount from string to decimal")
+		return err
+	}
+	if balance.Cmp(value) < 0 {
+		log.WithFields(log.Fields{"type": consts.NoFunds, "wallet": wallet}).Error("not enough money")
+		return fmt.Errorf(`not enough money`)
+	}
+	_, _, err = sc.selectiveLoggingAndUpd([]string{`-amount`}, []interface{}{amount}, fmt.Sprintf(`%d_keys`, ecosystem),
+		[]string{`id`}, []string{converter.Int64ToStr(wallet)}, !sc.VDE && sc.Rollback, true)
+	return err
+}
+
+// walletCredit deposits the amount to the wallet
+func walletCredit(sc *SmartContract, ecosystem, wallet int64, amount string) error {
+	_, _, err := sc.selectiveLoggingAndUpd([]string{`+amount`}, []interface{}{amount}, fmt.Sprintf(`%d_keys`, ecosystem),
+		[]string{`id`}, []string{converter.Int64ToStr(wallet)}, !sc.VDE && sc.Rollback, true)
+	if err == errUpdNotExistRecord {
+		return fmt.Errorf(`wallet %s has not been found`, converter.AddressToString(wallet))
+	}
+	return err
+}
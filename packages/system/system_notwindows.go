@@ -1,3 +1,5 @@
+// +build !windows
+
 // MIT License
 //
 // Copyright (c) 2016-2018 GenesisKernel
@@ -22,5 +24,16 @@
 
 package system
 
+import "syscall"
+
 func killChildProc() {
 }
+
+// DiskFree returns the count of free bytes available to the user on the disk with the path
+func DiskFree(path string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, err
+	}
+	return stat.Bavail * uint64(stat.Bsize), nil
+}
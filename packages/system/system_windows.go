@@ -24,6 +24,8 @@ package system
 
 import (
 	"os"
+	"syscall"
+	"unsafe"
 )
 
 /*
@@ -69,3 +71,19 @@ import "C"
 func killChildProc() {
 	C.kill_childproc(C.DWORD(os.Getpid()))
 }
+
+var getDiskFreeSpaceEx = syscall.NewLazyDLL("kernel32.dll").NewProc("GetDiskFreeSpaceExW")
+
+// DiskFree returns the count of free bytes available to the user on the disk with the path
+func DiskFree(path string) (uint64, error) {
+	var free uint64
+	ptr, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, err
+	}
+	ret, _, err := getDiskFreeSpaceEx.Call(uintptr(unsafe.Pointer(ptr)), uintptr(unsafe.Pointer(&free)), 0, 0)
+	if ret == 0 {
+		return 0, err
+	}
+	return free, nil
+}
@@ -0,0 +1,124 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package tcpserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/utils"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// GetBlockBody gets the block data
+func GetBlockBody(host string, blockID int64, dataTypeBlockBody int64) ([]byte, error) {
+	conn, err := Dial(host)
+	if err != nil {
+		return nil, utils.ErrInfo(err)
+	}
+	defer conn.Close()
+
+	// send the type of data
+	_, err = conn.Write(converter.DecToBin(dataTypeBlockBody, 2))
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("writing data type block body to connection")
+		return nil, utils.ErrInfo(err)
+	}
+
+	// send the number of a block
+	_, err = conn.Write(converter.DecToBin(blockID, 4))
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("writing data type block body to connection")
+		return nil, utils.ErrInfo(err)
+	}
+
+	// receive the data size as a response that server wants to transfer
+	buf := make([]byte, 4)
+	_, err = io.ReadFull(conn, buf)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("reading block data size from connection")
+		return nil, utils.ErrInfo(err)
+	}
+	// if the data size is less than 10mb, we will receive them
+	dataSize := converter.BinToDec(buf)
+	var binaryBlock []byte
+	if dataSize < 10485760 && dataSize > 0 {
+		binaryBlock = make([]byte, dataSize)
+
+		_, err = io.ReadFull(conn, binaryBlock)
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("reading block data from connection")
+			return nil, utils.ErrInfo(err)
+		}
+	} else {
+		log.Error("null block")
+		return nil, utils.ErrInfo("null block")
+	}
+	conn.Release()
+	return binaryBlock, nil
+}
+
+// ErrNotFirstBlock is returned if the node has sent the block which isn't first
+var ErrNotFirstBlock = errors.New("received block isn't first")
+
+// GetFirstBlock gets the first block and the parameters of network from the node
+func GetFirstBlock(host string) ([]byte, *NetworkParams, error) {
+	peer, err := GetPeerInfo(host)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !peer.Supports(CapFirstBlock) {
+		return nil, nil, fmt.Errorf("node %s doesn't support the request of first block", host)
+	}
+	conn, err := Dial(host)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer conn.Close()
+
+	if err = SendRequest(&TransactionType{Type: DataTypeFirstBlock}, conn); err != nil {
+		return nil, nil, err
+	}
+	resp := &FirstBlockResponse{}
+	if err = ReadRequest(resp, conn); err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err, "host": host}).Error("reading first block")
+		return nil, nil, err
+	}
+	conn.Release()
+
+	// the block starts with 2 bytes of version and 4 bytes of block id
+	if len(resp.Block) < 6 || converter.BinToDec(resp.Block[2:6]) != 1 {
+		return nil, nil, ErrNotFirstBlock
+	}
+	params := &NetworkParams{}
+	if err = json.Unmarshal(resp.Params, params); err != nil {
+		log.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err, "host": host}).Error("unmarshalling network params")
+		return nil, nil, err
+	}
+	return resp.Block, params, nil
+}
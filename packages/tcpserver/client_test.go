@@ -0,0 +1,76 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package tcpserver
+
+import (
+	"io"
+	"net"
+	"testing"
+
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+)
+
+// firstBlockServer responds with the block which id is blockID
+func firstBlockServer(t *testing.T, blockID int64) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				buf := make([]byte, 2)
+				for {
+					if _, err := io.ReadFull(conn, buf); err != nil {
+						return
+					}
+					if converter.BinToDec(buf) != DataTypeFirstBlock {
+						handleRequest(uint16(converter.BinToDec(buf)), conn)
+						continue
+					}
+					block := append(converter.DecToBin(1, 2), converter.DecToBin(blockID, 4)...)
+					SendRequest(&FirstBlockResponse{Block: block, Params: []byte(`{"version":"1.0","block_id":10}`)}, conn)
+				}
+			}()
+		}
+	}()
+	return l.Addr().String()
+}
+
+func TestGetFirstBlock(t *testing.T) {
+	data, params, err := GetFirstBlock(firstBlockServer(t, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 6 || params.Version != "1.0" || params.BlockID != 10 {
+		t.Errorf("wrong first block %x %+v", data, params)
+	}
+	if _, _, err = GetFirstBlock(firstBlockServer(t, 2)); err != ErrNotFirstBlock {
+		t.Errorf("expected not first block error, got %v", err)
+	}
+}
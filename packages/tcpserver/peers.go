@@ -0,0 +1,127 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package tcpserver
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/utils"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DataTypeHello is the type of the handshake request
+	DataTypeHello = 12
+
+	// peerInfoTTL is the time after which the peer is asked again, it may have been updated
+	peerInfoTTL = 10 * time.Minute
+)
+
+// PeerInfo is the negotiated protocol with the peer
+type PeerInfo struct {
+	Version      uint16
+	Capabilities uint64
+	updated      time.Time
+}
+
+// Supports returns true if both nodes have the capability
+func (p PeerInfo) Supports(capability uint64) bool {
+	return p.Capabilities&capability == capability
+}
+
+var (
+	peersMutex sync.Mutex
+	peers      = make(map[string]PeerInfo)
+)
+
+// negotiate returns the common protocol of this node and the peer
+func negotiate(resp *HelloResponse) PeerInfo {
+	info := PeerInfo{Version: resp.Version, Capabilities: resp.Capabilities & Capabilities}
+	if info.Version > ProtocolVersion {
+		info.Version = ProtocolVersion
+	}
+	return info
+}
+
+// handshake sends the hello request to the peer. The old nodes close the connection
+// on unknown request type, such peers get the zero version without capabilities
+func handshake(rw io.ReadWriter) (PeerInfo, error) {
+	// the request is sent by one write, so the old node gets it before closing the connection
+	var req bytes.Buffer
+	if err := SendRequest(&TransactionType{Type: DataTypeHello}, &req); err != nil {
+		return PeerInfo{}, err
+	}
+	if err := SendRequest(&HelloRequest{Version: ProtocolVersion, Capabilities: Capabilities}, &req); err != nil {
+		return PeerInfo{}, err
+	}
+	if _, err := rw.Write(req.Bytes()); err != nil {
+		return PeerInfo{}, err
+	}
+	buf := make([]byte, 10)
+	if _, err := io.ReadFull(rw, buf); err != nil {
+		// the connection is reset if it is closed with the unread hello request
+		if err == io.EOF || err == io.ErrUnexpectedEOF || errors.Is(err, syscall.ECONNRESET) {
+			return PeerInfo{}, nil
+		}
+		return PeerInfo{}, err
+	}
+	resp := &HelloResponse{}
+	if err := ReadRequest(resp, bytes.NewReader(buf)); err != nil {
+		return PeerInfo{}, err
+	}
+	return negotiate(resp), nil
+}
+
+// GetPeerInfo returns the negotiated protocol with the peer, the result is cached for some time
+func GetPeerInfo(addr string) (PeerInfo, error) {
+	peersMutex.Lock()
+	info, ok := peers[addr]
+	peersMutex.Unlock()
+	if ok && time.Since(info.updated) < peerInfoTTL {
+		return info, nil
+	}
+
+	conn, err := utils.TCPConn(addr)
+	if err != nil {
+		return PeerInfo{}, err
+	}
+	defer conn.Close()
+
+	if info, err = handshake(conn); err != nil {
+		log.WithFields(log.Fields{"type": consts.ProtocolError, "error": err, "host": addr}).Error("protocol handshake")
+		return PeerInfo{}, err
+	}
+	info.updated = time.Now()
+
+	peersMutex.Lock()
+	peers[addr] = info
+	peersMutex.Unlock()
+	return info, nil
+}
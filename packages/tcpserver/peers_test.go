@@ -0,0 +1,68 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package tcpserver
+
+import (
+	"bytes"
+	"net"
+	"testing"
+)
+
+type testPeer struct {
+	*bytes.Buffer
+	sent bytes.Buffer
+}
+
+func (p *testPeer) Write(data []byte) (int, error) {
+	return p.sent.Write(data)
+}
+
+func TestHandshake(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	go func() {
+		HandleTCPRequest(server)
+		server.Close()
+	}()
+	info, err := handshake(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Version != ProtocolVersion || !info.Supports(CapBridge) {
+		t.Errorf("wrong peer info %+v", info)
+	}
+
+	// the old node closes the connection without the response
+	info, err = handshake(&testPeer{Buffer: &bytes.Buffer{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Version != 0 || info.Supports(CapBridge) {
+		t.Errorf("wrong legacy peer info %+v", info)
+	}
+
+	info = negotiate(&HelloResponse{Version: ProtocolVersion + 1, Capabilities: Capabilities | 1<<40})
+	if info.Version != ProtocolVersion || info.Capabilities != Capabilities {
+		t.Errorf("wrong negotiated info %+v", info)
+	}
+}
@@ -0,0 +1,203 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package tcpserver
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/config/syspar"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/utils"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	poolMaxIdle     = 4                // idle connections to one node
+	poolIdleTimeout = 60 * time.Second // must be less than keepAliveTimeout of the server
+	poolMinBackoff  = time.Second
+	poolMaxBackoff  = time.Minute
+)
+
+// ErrBackoff is returned if the last connections to the node have failed and the time of the next attempt hasn't come
+var ErrBackoff = errors.New("node is unavailable, waiting for reconnect")
+
+type idleConn struct {
+	net.Conn
+	since time.Time
+}
+
+type nodePool struct {
+	idle     []idleConn
+	failures uint
+	retry    time.Time
+}
+
+var (
+	poolMutex sync.Mutex
+	pool      = make(map[string]*nodePool)
+)
+
+// Conn is the connection to the node. Release returns it to the pool after the complete exchange
+// of request and response, Close drops it. Close after Release does nothing
+type Conn struct {
+	net.Conn
+	addr      string
+	keepAlive bool
+	done      bool
+}
+
+// Release puts the connection to the pool if the node supports keep-alive or closes it
+func (c *Conn) Release() {
+	if c.done {
+		return
+	}
+	c.done = true
+	if !c.keepAlive {
+		c.Conn.Close()
+		return
+	}
+	poolMutex.Lock()
+	defer poolMutex.Unlock()
+	np := getNodePool(c.addr)
+	if len(np.idle) >= poolMaxIdle {
+		c.Conn.Close()
+		return
+	}
+	np.idle = append(np.idle, idleConn{Conn: c.Conn, since: time.Now()})
+}
+
+// Close closes the connection if it hasn't been released
+func (c *Conn) Close() error {
+	if c.done {
+		return nil
+	}
+	c.done = true
+	return c.Conn.Close()
+}
+
+func getNodePool(addr string) *nodePool {
+	np, ok := pool[addr]
+	if !ok {
+		np = &nodePool{}
+		pool[addr] = np
+	}
+	return np
+}
+
+// alive checks that the idle connection hasn't been closed by the node
+func alive(conn net.Conn) bool {
+	conn.SetReadDeadline(time.Now().Add(time.Millisecond))
+	_, err := conn.Read(make([]byte, 1))
+	if ne, ok := err.(net.Error); ok && ne.Timeout() {
+		return true
+	}
+	return false
+}
+
+func setTimeouts(conn net.Conn) {
+	conn.SetReadDeadline(time.Now().Add(consts.READ_TIMEOUT * time.Second))
+	conn.SetWriteDeadline(time.Now().Add(consts.WRITE_TIMEOUT * time.Second))
+}
+
+// idleConnection returns the live idle connection to the node or nil
+func idleConnection(addr string) (net.Conn, error) {
+	poolMutex.Lock()
+	defer poolMutex.Unlock()
+	np := getNodePool(addr)
+	if time.Now().Before(np.retry) {
+		return nil, ErrBackoff
+	}
+	for len(np.idle) > 0 {
+		ic := np.idle[len(np.idle)-1]
+		np.idle = np.idle[:len(np.idle)-1]
+		if time.Since(ic.since) < poolIdleTimeout && alive(ic.Conn) {
+			return ic.Conn, nil
+		}
+		ic.Close()
+	}
+	return nil, nil
+}
+
+// failed increases the time of the next attempt to connect to the node
+func failed(addr string) {
+	poolMutex.Lock()
+	defer poolMutex.Unlock()
+	np := getNodePool(addr)
+	np.failures++
+	backoff := poolMaxBackoff
+	if np.failures < 8 {
+		if backoff = poolMinBackoff << (np.failures - 1); backoff > poolMaxBackoff {
+			backoff = poolMaxBackoff
+		}
+	}
+	np.retry = time.Now().Add(backoff)
+}
+
+func succeeded(addr string) {
+	poolMutex.Lock()
+	defer poolMutex.Unlock()
+	np := getNodePool(addr)
+	np.failures, np.retry = 0, time.Time{}
+}
+
+// Dial returns the connection to the node from the pool or connects to it
+func Dial(addr string) (*Conn, error) {
+	conn, err := idleConnection(addr)
+	if err != nil {
+		return nil, err
+	}
+	if conn != nil {
+		setTimeouts(conn)
+		return &Conn{Conn: conn, addr: addr, keepAlive: true}, nil
+	}
+
+	peer, err := GetPeerInfo(addr)
+	if err != nil {
+		failed(addr)
+		return nil, err
+	}
+	if conn, err = utils.TCPConn(addr); err != nil {
+		failed(addr)
+		log.WithFields(log.Fields{"type": consts.ConnectionError, "error": err, "host": addr}).Debug("connecting to node")
+		return nil, err
+	}
+	if syspar.IsPermissioned() {
+		if !peer.Supports(CapAuth) {
+			err = ErrNotAuthenticated
+		} else {
+			err = authenticate(conn)
+		}
+		if err != nil {
+			conn.Close()
+			failed(addr)
+			log.WithFields(log.Fields{"type": consts.ProtocolError, "error": err, "host": addr}).Error("authenticating to node")
+			return nil, err
+		}
+	}
+	succeeded(addr)
+	return &Conn{Conn: conn, addr: addr, keepAlive: peer.Supports(CapKeepAlive)}, nil
+}
@@ -0,0 +1,123 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package tcpserver
+
+import (
+	"io"
+	"net"
+	"testing"
+
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+)
+
+// testServer starts the tcp server, the old server closes the connection after the request
+// and doesn't know the hello request
+func testServer(t *testing.T, old bool) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				if !old {
+					serveConn(conn)
+					return
+				}
+				buf := make([]byte, 2)
+				if _, err := io.ReadFull(conn, buf); err == nil && converter.BinToDec(buf) != DataTypeHello {
+					handleRequest(uint16(converter.BinToDec(buf)), conn)
+				}
+			}()
+		}
+	}()
+	return l.Addr().String()
+}
+
+func sendHello(t *testing.T, conn io.ReadWriter) {
+	if err := SendRequest(&TransactionType{Type: DataTypeHello}, conn); err != nil {
+		t.Fatal(err)
+	}
+	if err := SendRequest(&HelloRequest{Version: ProtocolVersion}, conn); err != nil {
+		t.Fatal(err)
+	}
+	resp := &HelloResponse{}
+	if err := ReadRequest(resp, conn); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPool(t *testing.T) {
+	addr := testServer(t, false)
+	conn, err := Dial(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sendHello(t, conn)
+	local := conn.LocalAddr().String()
+	conn.Release()
+	conn.Close()
+
+	if conn, err = Dial(addr); err != nil {
+		t.Fatal(err)
+	}
+	if conn.LocalAddr().String() != local {
+		t.Error("connection hasn't been reused")
+	}
+	sendHello(t, conn)
+	conn.Close()
+
+	if conn, err = Dial(addr); err != nil {
+		t.Fatal(err)
+	}
+	if conn.LocalAddr().String() == local {
+		t.Error("closed connection has been reused")
+	}
+	conn.Close()
+
+	old := testServer(t, true)
+	if conn, err = Dial(old); err != nil {
+		t.Fatal(err)
+	}
+	if conn.keepAlive {
+		t.Error("old node doesn't support keep-alive")
+	}
+	conn.Release()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Close()
+	if _, err = Dial(l.Addr().String()); err == nil {
+		t.Fatal("expected connection error")
+	}
+	if _, err = Dial(l.Addr().String()); err != ErrBackoff {
+		t.Errorf("expected backoff, got %v", err)
+	}
+}
@@ -83,6 +83,17 @@ type DisHashResponse struct {
 	Data []byte
 }
 
+// BridgeRequest contains the network which requests transfers and the last received transfer
+type BridgeRequest struct {
+	Network int64
+	FromID  int64
+}
+
+// BridgeResponse contains JSON list of transfers signed by the validator
+type BridgeResponse struct {
+	Data []byte
+}
+
 // ReadRequest is reading request
 func ReadRequest(request interface{}, r io.Reader) error {
 	if reflect.ValueOf(request).Elem().Kind() != reflect.Struct {
@@ -23,43 +23,101 @@
 package tcpserver
 
 import (
+	"context"
+	"errors"
 	"io"
 	"net"
+	"strconv"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+	"github.com/GenesisKernel/go-genesis/packages/config/syspar"
 	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/graceful"
 
 	log "github.com/sirupsen/logrus"
 )
 
+// keepAliveTimeout is the time after which the idle connection is closed
+const keepAliveTimeout = 90 * time.Second
+
 var (
-	counter int64
+	counter  int64
+	requests sync.WaitGroup
+
+	errUnknownType = errors.New("unknown request type")
+	errBusy        = errors.New("too many requests")
 )
 
 // HandleTCPRequest proceed TCP requests
 func HandleTCPRequest(rw io.ReadWriter) {
+	dType := &TransactionType{}
+	err := ReadRequest(dType, rw)
+	if err != nil {
+		log.Errorf("read request type failed: %s", err)
+		return
+	}
+	// the requests of permissioned network must be sent by the authenticated connections
+	if syspar.IsPermissioned() && dType.Type != DataTypeHello {
+		return
+	}
+	handleRequest(dType.Type, rw)
+}
+
+// serveConn handles the requests of the connection until the client closes it or it is idle for keepAliveTimeout.
+// The old nodes close the connection after the first request
+func serveConn(conn net.Conn) {
+	buf := make([]byte, 2)
+	var authenticated bool
+	for !graceful.Draining() {
+		conn.SetDeadline(time.Now().Add(keepAliveTimeout))
+		if _, err := io.ReadFull(conn, buf); err != nil {
+			return
+		}
+		reqType := uint16(converter.BinToDec(buf))
+		if reqType == DataTypeAuth {
+			if err := Type14(conn); err != nil {
+				return
+			}
+			authenticated = true
+			continue
+		}
+		if !authenticated && reqType != DataTypeHello && syspar.IsPermissioned() {
+			log.WithFields(log.Fields{"type": consts.ProtocolError, "request_type": reqType, "host": conn.RemoteAddr().String()}).
+				Warning("request of not authenticated node")
+			return
+		}
+		requests.Add(1)
+		err := handleRequest(reqType, conn)
+		requests.Done()
+		if err != nil {
+			return
+		}
+	}
+}
+
+// handleRequest processes the request, the connection must be closed if an error is returned
+func handleRequest(reqType uint16, rw io.ReadWriter) error {
 	defer func() {
 		atomic.AddInt64(&counter, -1)
 	}()
 
 	count := atomic.AddInt64(&counter, +1)
 	if count > 20 {
-		return
+		return errBusy
 	}
 
-	dType := &TransactionType{}
-	err := ReadRequest(dType, rw)
-	if err != nil {
-		log.Errorf("read request type failed: %s", err)
-		return
-	}
-
-	log.WithFields(log.Fields{"request_type": dType.Type}).Debug("tcpserver got request type")
-	var response interface{}
+	log.WithFields(log.Fields{"request_type": reqType}).Debug("tcpserver got request type")
+	var (
+		response interface{}
+		err      error
+	)
 
-	switch dType.Type {
+	switch reqType {
 	case 1:
 		req := &DisRequest{}
 		err = ReadRequest(req, rw)
@@ -90,13 +148,33 @@ func HandleTCPRequest(rw io.ReadWriter) {
 
 	case 10:
 		response, err = Type10()
+
+	case 11:
+		req := &BridgeRequest{}
+		err = ReadRequest(req, rw)
+		if err == nil {
+			response, err = Type11(req)
+		}
+
+	case DataTypeHello:
+		req := &HelloRequest{}
+		err = ReadRequest(req, rw)
+		if err == nil {
+			response, err = Type12(req)
+		}
+
+	case DataTypeFirstBlock:
+		response, err = Type13()
+
+	default:
+		err = errUnknownType
 	}
 
 	if err != nil {
-		return
+		return err
 	}
 	if response == nil {
-		return
+		return nil
 	}
 
 	log.WithFields(log.Fields{"response": response}).Debug("tcpserver responded")
@@ -104,31 +182,49 @@ func HandleTCPRequest(rw io.ReadWriter) {
 	if err != nil {
 		log.Errorf("tcpserver handle error: %s", err)
 	}
+	return err
 }
 
-// TcpListener is listening tcp address
-func TcpListener(laddr string) error {
+// TcpListener is listening tcp addresses, the first one is the main address
+func TcpListener(laddrs ...string) error {
+	for i, laddr := range laddrs {
+		name := "tcp"
+		if i > 0 {
+			name += strconv.Itoa(i)
+		}
+		if err := listen(name, laddr); err != nil {
+			return err
+		}
+	}
+	graceful.OnDrain(drain)
+	return nil
+}
 
-	if strings.HasPrefix(laddr, "127.") {
+func listen(name, laddr string) error {
+	if strings.HasPrefix(laddr, "127.") || strings.HasPrefix(laddr, "[::1]") {
 		log.Warn("Listening at local address: ", laddr)
 	}
 
-	l, err := net.Listen("tcp4", laddr)
+	l, err := graceful.Listen(name, conf.ListenNetwork(laddr), laddr)
 	if err != nil {
 		log.WithFields(log.Fields{"type": consts.ConnectionError, "error": err, "host": laddr}).Error("Error listening")
 		return err
 	}
+	log.WithFields(log.Fields{"host": laddr}).Info("tcp server is listening at")
 
 	go func() {
 		defer l.Close()
 		for {
 			conn, err := l.Accept()
 			if err != nil {
+				if graceful.Draining() {
+					return
+				}
 				log.WithFields(log.Fields{"type": consts.ConnectionError, "error": err, "host": laddr}).Error("Error accepting")
 				time.Sleep(time.Second)
 			} else {
 				go func(conn net.Conn) {
-					HandleTCPRequest(conn)
+					serveConn(conn)
 					conn.Close()
 				}(conn)
 			}
@@ -137,3 +233,17 @@ func TcpListener(laddr string) error {
 
 	return nil
 }
+
+// drain waits for the current requests after the listener has been closed
+func drain(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		requests.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		log.WithFields(log.Fields{"type": consts.JustWaiting}).Warning("tcp requests have not been finished")
+	}
+}
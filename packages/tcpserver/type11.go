@@ -0,0 +1,51 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package tcpserver
+
+import (
+	"encoding/json"
+
+	"github.com/GenesisKernel/go-genesis/packages/bridge"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Type11 sends the outgoing bridge transfers signed by the node key
+// The request is sent by 'BridgeRelay' daemon of other network
+func Type11(r *BridgeRequest) (*BridgeResponse, error) {
+	transfers, err := bridge.Attest(r.Network, r.FromID)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.BridgeError, "error": err, "network": r.Network}).Error("attesting bridge transfers")
+		return nil, err
+	}
+	if transfers == nil {
+		transfers = []bridge.SignedTransfer{}
+	}
+	data, err := json.Marshal(transfers)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Error("marshalling bridge transfers")
+		return nil, err
+	}
+	return &BridgeResponse{Data: data}, nil
+}
@@ -0,0 +1,64 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package tcpserver
+
+import (
+	log "github.com/sirupsen/logrus"
+)
+
+// ProtocolVersion is the version of the binary protocol of the node
+const ProtocolVersion uint16 = 1
+
+// Capabilities of the node, the request types and the features which aren't supported by all nodes
+const (
+	// CapBridge is the request of the signed bridge transfers (type 11)
+	CapBridge uint64 = 1 << iota
+	// CapKeepAlive means the node handles several requests in one connection
+	CapKeepAlive
+	// CapFirstBlock is the request of the first block and the parameters of network (type 13)
+	CapFirstBlock
+	// CapAuth is the authentication of node by the certificate of permissioned network (type 14)
+	CapAuth
+)
+
+// Capabilities are all capabilities of this node
+const Capabilities = CapBridge | CapKeepAlive | CapFirstBlock | CapAuth
+
+// HelloRequest contains the protocol version and the capabilities of the requesting node
+type HelloRequest struct {
+	Version      uint16
+	Capabilities uint64
+}
+
+// HelloResponse contains the protocol version and the capabilities of the node
+type HelloResponse struct {
+	Version      uint16
+	Capabilities uint64
+}
+
+// Type12 sends the protocol version and the capabilities of the node
+// The request is sent by any node before using the requests which are not supported by old nodes
+func Type12(r *HelloRequest) (*HelloResponse, error) {
+	log.WithFields(log.Fields{"version": r.Version, "capabilities": r.Capabilities}).Debug("tcpserver got hello")
+	return &HelloResponse{Version: ProtocolVersion, Capabilities: Capabilities}, nil
+}
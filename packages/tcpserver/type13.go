@@ -0,0 +1,85 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package tcpserver
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/GenesisKernel/go-genesis/packages/config/syspar"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// DataTypeFirstBlock is the type of the request of the first block
+const DataTypeFirstBlock = 13
+
+// FirstBlockRequest is the request of the first block and the parameters of network
+type FirstBlockRequest struct{}
+
+// FirstBlockResponse contains the first block and JSON of NetworkParams
+type FirstBlockResponse struct {
+	Block  []byte
+	Params []byte
+}
+
+// NetworkParams are the basic parameters of network for the joining node
+type NetworkParams struct {
+	Version   string          `json:"version"`
+	Protocol  uint16          `json:"protocol"`
+	BlockID   int64           `json:"block_id"`
+	FullNodes json.RawMessage `json:"full_nodes,omitempty"`
+}
+
+// Type13 sends the first block and the parameters of network to the joining node
+func Type13() (*FirstBlockResponse, error) {
+	block := &model.Block{}
+	found, err := block.Get(1)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting first block")
+		return nil, err
+	}
+	if !found {
+		log.WithFields(log.Fields{"type": consts.NotFound}).Error("first block not found")
+		return nil, errors.New("first block not found")
+	}
+
+	params := NetworkParams{Version: consts.VERSION, Protocol: ProtocolVersion}
+	infoBlock := &model.InfoBlock{}
+	if _, err = infoBlock.Get(); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting info block")
+		return nil, err
+	}
+	params.BlockID = infoBlock.BlockID
+	if nodes := syspar.SysString(syspar.FullNodes); json.Valid([]byte(nodes)) {
+		params.FullNodes = json.RawMessage(nodes)
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Error("marshalling network params")
+		return nil, err
+	}
+	return &FirstBlockResponse{Block: block.Data, Params: data}, nil
+}
@@ -0,0 +1,146 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package tcpserver
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+	"github.com/GenesisKernel/go-genesis/packages/config/syspar"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/crypto"
+	"github.com/GenesisKernel/go-genesis/packages/nodecert"
+	"github.com/GenesisKernel/go-genesis/packages/utils"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// DataTypeAuth is the type of the authentication of node in permissioned network
+const DataTypeAuth = 14
+
+// ErrNotAuthenticated is returned if the node of permissioned network hasn't been authenticated
+var ErrNotAuthenticated = errors.New("node isn't authenticated")
+
+// AuthChallenge is the random data which the node must sign by its key
+type AuthChallenge struct {
+	Nonce []byte `size:"32"`
+}
+
+// AuthRequest contains the certificate of node and the signature of the challenge
+type AuthRequest struct {
+	Cert []byte
+	Sign []byte
+}
+
+// AuthResponse contains 1 if the node has been authenticated
+type AuthResponse struct {
+	OK uint8
+}
+
+func authData(nonce []byte) string {
+	return fmt.Sprintf("auth,%x", nonce)
+}
+
+// Type14 authenticates the node by the certificate of network CA and the signature of the random challenge
+func Type14(rw io.ReadWriter) error {
+	challenge := &AuthChallenge{Nonce: make([]byte, 32)}
+	if _, err := rand.Read(challenge.Nonce); err != nil {
+		return err
+	}
+	if err := SendRequest(challenge, rw); err != nil {
+		return err
+	}
+	req := &AuthRequest{}
+	if err := ReadRequest(req, rw); err != nil {
+		return err
+	}
+	err := checkAuth(req, challenge.Nonce, syspar.GetNetworkCA())
+	resp := &AuthResponse{}
+	if err == nil {
+		resp.OK = 1
+	} else {
+		log.WithFields(log.Fields{"type": consts.ProtocolError, "error": err}).Warning("node authentication failed")
+	}
+	if errSend := SendRequest(resp, rw); errSend != nil {
+		return errSend
+	}
+	return err
+}
+
+func checkAuth(req *AuthRequest, nonce, ca []byte) error {
+	if ca == nil {
+		return nil
+	}
+	cert, err := nodecert.Parse(string(req.Cert))
+	if err != nil {
+		return err
+	}
+	if err = cert.Verify(ca, time.Now()); err != nil {
+		return err
+	}
+	ok, err := crypto.CheckSign(cert.NodePublicKey, authData(nonce), req.Sign)
+	if err != nil || !ok {
+		return ErrNotAuthenticated
+	}
+	return nil
+}
+
+// authenticate proves to the node of permissioned network that this node has the certificate of network CA
+func authenticate(rw io.ReadWriter) error {
+	cert, err := nodecert.Load(filepath.Join(conf.Config.PrivateDir, consts.NodeCertificateFilename))
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("loading node certificate")
+		return err
+	}
+	privateKey, _, err := utils.GetNodeKeys()
+	if err != nil {
+		return err
+	}
+	if err = SendRequest(&TransactionType{Type: DataTypeAuth}, rw); err != nil {
+		return err
+	}
+	challenge := &AuthChallenge{}
+	if err = ReadRequest(challenge, rw); err != nil {
+		return err
+	}
+	sign, err := crypto.Sign(privateKey, authData(challenge.Nonce))
+	if err != nil {
+		return err
+	}
+	if err = SendRequest(&AuthRequest{Cert: []byte(cert.String()), Sign: sign}, rw); err != nil {
+		return err
+	}
+	resp := &AuthResponse{}
+	if err = ReadRequest(resp, rw); err != nil {
+		return err
+	}
+	if resp.OK != 1 {
+		return ErrNotAuthenticated
+	}
+	return nil
+}
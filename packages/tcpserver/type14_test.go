@@ -0,0 +1,65 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package tcpserver
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/crypto"
+	"github.com/GenesisKernel/go-genesis/packages/nodecert"
+)
+
+func TestCheckAuth(t *testing.T) {
+	caPriv, caPub, err := crypto.GenBytesKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodePriv, nodePub, err := crypto.GenBytesKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := nodecert.Issue(hex.EncodeToString(caPriv), nodePub, time.Now().Add(time.Hour).Unix())
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonce := []byte("0123456789abcdef0123456789abcdef")
+	sign, err := crypto.Sign(hex.EncodeToString(nodePriv), authData(nonce))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := &AuthRequest{Cert: []byte(cert.String()), Sign: sign}
+	if err = checkAuth(req, nonce, caPub); err != nil {
+		t.Error(err)
+	}
+	if err = checkAuth(req, []byte("another nonce"), caPub); err != ErrNotAuthenticated {
+		t.Errorf("expected %v, got %v", ErrNotAuthenticated, err)
+	}
+	if err = checkAuth(req, nonce, nodePub); err != nodecert.ErrSign {
+		t.Errorf("expected %v, got %v", nodecert.ErrSign, err)
+	}
+	if err = checkAuth(&AuthRequest{}, nonce, nil); err != nil {
+		t.Error(err)
+	}
+}
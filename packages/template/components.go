@@ -0,0 +1,185 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package template
+
+import (
+	"strings"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// maxInclude is the maximum depth of nested includes and components
+	maxInclude = 5
+	// maxRange is the maximum count of rows of Range source
+	maxRange = 1000
+	// slotVar is the name of the variable which contains the body of the component
+	slotVar = `body`
+)
+
+// splitParams parses the list "name=value,name2=value2" of the parameters of the component,
+// the values can be quoted and can contain commas inside brackets
+func splitParams(input string) map[string]string {
+	result := make(map[string]string)
+	var (
+		level int
+		quote rune
+		start int
+	)
+	add := func(item string) {
+		off := strings.IndexByte(item, '=')
+		if off <= 0 {
+			return
+		}
+		name, value := strings.TrimSpace(item[:off]), strings.TrimSpace(item[off+1:])
+		if len(value) > 1 && (value[0] == '"' || value[0] == '`') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		if len(name) > 0 && name[0] != '_' {
+			result[name] = value
+		}
+	}
+	for off, ch := range input {
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '"' || ch == '`':
+			quote = ch
+		case ch == '(' || ch == '[' || ch == '{':
+			level++
+		case ch == ')' || ch == ']' || ch == '}':
+			if level > 0 {
+				level--
+			}
+		case ch == ',' && level == 0:
+			add(input[start:off])
+			start = off + 1
+		}
+	}
+	add(input[start:])
+	return result
+}
+
+// renderPartial processes the template with the variables which are visible only inside it
+func renderPartial(par parFunc, pattern string, params map[string]string) {
+	vars := *par.Workspace.Vars
+	if len(pattern) == 0 || len(vars[`_include`]) >= maxInclude {
+		return
+	}
+	type savedVar struct {
+		value  string
+		exists bool
+	}
+	saved := make(map[string]savedVar, len(params))
+	for name, value := range params {
+		old, ok := vars[name]
+		saved[name] = savedVar{old, ok}
+		vars[name] = value
+	}
+	vars[`_include`] += `1`
+	root := node{}
+	process(pattern, &root, par.Workspace)
+	vars[`_include`] = vars[`_include`][:len(vars[`_include`])-1]
+	for name, old := range saved {
+		if old.exists {
+			vars[name] = old.value
+		} else {
+			delete(vars, name)
+		}
+	}
+	par.Owner.Children = append(par.Owner.Children, root.Children...)
+}
+
+func getBlock(par parFunc, name string) (string, error) {
+	pattern, err := model.Single(`select value from "`+(*par.Workspace.Vars)[`ecosystem_id`]+`_blocks" where name=?`, name).String()
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting block by name")
+	}
+	return pattern, err
+}
+
+// defineTag saves the body of the component, it is rendered by Component
+func defineTag(par parFunc) string {
+	name := (*par.Pars)[`Name`]
+	if len(name) == 0 {
+		return ``
+	}
+	if par.Workspace.Components == nil {
+		par.Workspace.Components = make(map[string]string)
+	}
+	par.Workspace.Components[name] = (*par.Pars)[`Data`]
+	return ``
+}
+
+// componentTag renders the component which is defined on the page or the block with the same name,
+// the parameters and the body of the component are available as variables inside it
+func componentTag(par parFunc) string {
+	name := (*par.Pars)[`Name`]
+	if len(name) == 0 {
+		return ``
+	}
+	pattern, ok := par.Workspace.Components[name]
+	if !ok {
+		var err error
+		if pattern, err = getBlock(par, name); err != nil {
+			return err.Error()
+		}
+	}
+	params := splitParams((*par.Pars)[`Params`])
+	params[slotVar] = (*par.Pars)[`Data`]
+	renderPartial(par, pattern, params)
+	return ``
+}
+
+// rangeTag creates the source with id column which contains the numbers from From to To excluding To
+func rangeTag(par parFunc) string {
+	setAllAttr(par)
+	from := converter.StrToInt64((*par.Pars)[`From`])
+	to := converter.StrToInt64((*par.Pars)[`To`])
+	step := converter.StrToInt64((*par.Pars)[`Step`])
+	if step == 0 {
+		step = 1
+	}
+	data := make([][]string, 0)
+	for i := from; (step > 0 && i < to) || (step < 0 && i > to); i += step {
+		if len(data) >= maxRange {
+			par.Node.Attr[`error`] = `too many rows`
+			break
+		}
+		data = append(data, []string{converter.Int64ToStr(i)})
+	}
+	cols := []string{`id`}
+	types := []string{`text`}
+	par.Node.Attr[`columns`] = &cols
+	par.Node.Attr[`types`] = &types
+	par.Node.Attr[`data`] = &data
+	newSource(par)
+	par.Owner.Children = append(par.Owner.Children, par.Node)
+	return ``
+}
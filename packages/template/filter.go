@@ -0,0 +1,264 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package template
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+)
+
+const (
+	// maxFilterDepth is the maximum nesting of $and and $or of the filter
+	maxFilterDepth = 8
+	// maxFilterConditions is the maximum count of conditions of the filter
+	maxFilterConditions = 64
+)
+
+var filterOperators = map[string]string{
+	`$eq`:  `=`,
+	`$neq`: `<>`,
+	`$gt`:  `>`,
+	`$gte`: `>=`,
+	`$lt`:  `<`,
+	`$lte`: `<=`,
+}
+
+type filterBuilder struct {
+	columns map[string]string
+	params  []interface{}
+	count   int
+}
+
+// FilterToWhere converts JSON filter like {"name": "John", "amount": {"$gt": 10}, "$or": [{...}, {...}]}
+// to SQL condition with placeholders, only the columns of the table can be used.
+// The keys of jsonb columns are specified by the path and compared as text, for example, {"info.city": "Paris"}
+func FilterToWhere(filter string, columns map[string]string) (string, []interface{}, error) {
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewBufferString(filter))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return ``, nil, fmt.Errorf(`filter is not valid JSON: %v`, err)
+	}
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		return ``, nil, fmt.Errorf(`filter must be an object`)
+	}
+	fb := &filterBuilder{columns: columns}
+	where, err := fb.object(obj, 0)
+	if err != nil {
+		return ``, nil, err
+	}
+	return where, fb.params, nil
+}
+
+func (fb *filterBuilder) object(obj map[string]interface{}, depth int) (string, error) {
+	if depth > maxFilterDepth {
+		return ``, fmt.Errorf(`filter is too deep`)
+	}
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	// the order of conditions doesn't depend on the order of map
+	sort.Strings(keys)
+	list := make([]string, 0, len(keys))
+	for _, key := range keys {
+		var (
+			cond string
+			err  error
+		)
+		switch key {
+		case `$and`, `$or`:
+			cond, err = fb.group(key, obj[key], depth)
+		default:
+			cond, err = fb.column(key, obj[key])
+		}
+		if err != nil {
+			return ``, err
+		}
+		list = append(list, cond)
+	}
+	if len(list) == 0 {
+		return `true`, nil
+	}
+	return `(` + strings.Join(list, ` and `) + `)`, nil
+}
+
+func (fb *filterBuilder) group(op string, value interface{}, depth int) (string, error) {
+	items, ok := value.([]interface{})
+	if !ok || len(items) == 0 {
+		return ``, fmt.Errorf(`%s must be a non-empty array`, op)
+	}
+	list := make([]string, 0, len(items))
+	for _, item := range items {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			return ``, fmt.Errorf(`items of %s must be objects`, op)
+		}
+		cond, err := fb.object(obj, depth+1)
+		if err != nil {
+			return ``, err
+		}
+		list = append(list, cond)
+	}
+	return `(` + strings.Join(list, ` `+op[1:]+` `) + `)`, nil
+}
+
+func (fb *filterBuilder) param(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		fb.params = append(fb.params, v)
+	case json.Number:
+		fb.params = append(fb.params, v.String())
+	case bool:
+		fb.params = append(fb.params, v)
+	default:
+		return ``, fmt.Errorf(`value %v must be a string, a number or a boolean`, value)
+	}
+	return `?`, nil
+}
+
+func (fb *filterBuilder) column(name string, value interface{}) (string, error) {
+	var path []string
+	column := name
+	if strings.Contains(name, `.`) {
+		var err error
+		if path, err = converter.JSONPath(name); err != nil {
+			return ``, err
+		}
+		column = path[0]
+	}
+	colType, ok := fb.columns[column]
+	if !ok {
+		return ``, fmt.Errorf(`column %s doesn't exist`, column)
+	}
+	quoted := `"` + column + `"`
+	if len(path) > 0 {
+		if colType != `jsonb` {
+			return ``, fmt.Errorf(`column %s is not json`, column)
+		}
+		quoted = converter.JSONPathSQL(column, path[1:])
+		colType = `text`
+	}
+	ops, ok := value.(map[string]interface{})
+	if !ok {
+		ops = map[string]interface{}{`$eq`: value}
+	}
+	keys := make([]string, 0, len(ops))
+	for key := range ops {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	list := make([]string, 0, len(keys))
+	for _, op := range keys {
+		if fb.count++; fb.count > maxFilterConditions {
+			return ``, fmt.Errorf(`filter has too many conditions`)
+		}
+		arg := ops[op]
+		if sqlOp, ok := filterOperators[op]; ok {
+			ph, err := fb.param(arg)
+			if err != nil {
+				return ``, err
+			}
+			list = append(list, quoted+` `+sqlOp+` `+ph)
+			continue
+		}
+		switch op {
+		case `$in`, `$nin`:
+			items, ok := arg.([]interface{})
+			if !ok || len(items) == 0 || len(items) > maxFilterConditions {
+				return ``, fmt.Errorf(`%s of %s must be a non-empty array`, op, name)
+			}
+			phs := make([]string, len(items))
+			for i, item := range items {
+				ph, err := fb.param(item)
+				if err != nil {
+					return ``, err
+				}
+				phs[i] = ph
+			}
+			sqlOp := ` in `
+			if op == `$nin` {
+				sqlOp = ` not in `
+			}
+			list = append(list, quoted+sqlOp+`(`+strings.Join(phs, `,`)+`)`)
+		case `$like`, `$begin`, `$end`:
+			text, ok := arg.(string)
+			if !ok {
+				return ``, fmt.Errorf(`%s of %s must be a string`, op, name)
+			}
+			text = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(text)
+			switch op {
+			case `$like`:
+				text = `%` + text + `%`
+			case `$begin`:
+				text += `%`
+			default:
+				text = `%` + text
+			}
+			fb.params = append(fb.params, text)
+			if colType == `text` || colType == `character varying` {
+				list = append(list, quoted+` ilike ?`)
+			} else {
+				list = append(list, quoted+`::text ilike ?`)
+			}
+		default:
+			return ``, fmt.Errorf(`unknown operator %s`, op)
+		}
+	}
+	if len(list) == 0 {
+		return ``, fmt.Errorf(`condition of %s is empty`, name)
+	}
+	return strings.Join(list, ` and `), nil
+}
+
+// sortToOrder converts the list of columns like "name,-amount" to order clause,
+// the columns which aren't allowed or don't exist are ignored
+func sortToOrder(value, allowed string, columns map[string]string) string {
+	allow := make(map[string]bool)
+	for _, item := range strings.Split(allowed, `,`) {
+		if item = strings.TrimSpace(item); len(item) > 0 {
+			allow[item] = true
+		}
+	}
+	list := make([]string, 0)
+	for _, item := range strings.Split(value, `,`) {
+		item = strings.TrimSpace(item)
+		desc := strings.HasPrefix(item, `-`)
+		item = strings.TrimLeft(item, `+-`)
+		if _, ok := columns[item]; !ok || (len(allow) > 0 && !allow[item]) {
+			continue
+		}
+		if desc {
+			list = append(list, `"`+item+`" desc`)
+		} else {
+			list = append(list, `"`+item+`"`)
+		}
+	}
+	return strings.Join(list, `,`)
+}
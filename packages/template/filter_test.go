@@ -0,0 +1,93 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package template
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestFilterToWhere(t *testing.T) {
+	columns := map[string]string{`id`: `bigint`, `name`: `character varying`, `amount`: `numeric`, `status`: `bigint`,
+		`info`: `jsonb`}
+	cases := []struct {
+		filter string
+		where  string
+		params string
+	}{
+		{`{}`, `true`, `[]`},
+		{`{"name": "John"}`, `("name" = ?)`, `[John]`},
+		{`{"amount": {"$gt": 10, "$lte": 20.5}, "id": 5}`, `("amount" > ? and "amount" <= ? and "id" = ?)`, `[10 20.5 5]`},
+		{`{"$or": [{"status": {"$in": [1, 2]}}, {"name": {"$begin": "a_b"}}]}`,
+			`((("status" in (?,?)) or ("name" ilike ?)))`, `[1 2 a\_b%]`},
+		{`{"id": {"$nin": [3]}, "amount": {"$like": "5"}}`, `("amount"::text ilike ? and "id" not in (?))`, `[%5% 3]`},
+		{`{"$and": [{"name": {"$neq": "x"}}, {"$or": [{"id": 1}, {"id": 2}]}]}`,
+			`((("name" <> ?) and ((("id" = ?) or ("id" = ?)))))`, `[x 1 2]`},
+		{`{"info.city": "Paris", "info.tags.0": {"$like": "a"}}`,
+			`(("info"->>'city') = ? and ("info"->'tags'->>0) ilike ?)`, `[Paris %a%]`},
+	}
+	for _, item := range cases {
+		where, params, err := FilterToWhere(item.filter, columns)
+		if err != nil {
+			t.Errorf("%s: %v", item.filter, err)
+			continue
+		}
+		if where != item.where || fmt.Sprint(params) != item.params {
+			t.Errorf("%s: %s %v", item.filter, where, params)
+		}
+	}
+	for _, filter := range []string{
+		`[1]`,
+		`{"password": "x"}`,
+		`{"name; drop table": "x"}`,
+		`{"name": {"$regex": "x"}}`,
+		`{"name": {"$in": []}}`,
+		`{"$or": {"name": "x"}}`,
+		`{"name": {"$eq": {"a": 1}}}`,
+		`{"name": "x"`,
+		`{"name.first": "x"}`,
+		`{"info.ci'ty": "x"}`,
+	} {
+		if _, _, err := FilterToWhere(filter, columns); err == nil {
+			t.Errorf("%s must be rejected", filter)
+		}
+	}
+}
+
+func TestSortToOrder(t *testing.T) {
+	columns := map[string]string{`id`: `bigint`, `name`: `character varying`, `amount`: `numeric`}
+	cases := []struct {
+		sort, allowed, want string
+	}{
+		{`name`, ``, `"name"`},
+		{`-amount, id`, ``, `"amount" desc,"id"`},
+		{`-amount,name`, `name,id`, `"name"`},
+		{`name; drop table`, ``, ``},
+		{`unknown,+id`, ``, `"id"`},
+	}
+	for _, item := range cases {
+		if out := sortToOrder(item.sort, item.allowed, columns); out != item.want {
+			t.Errorf("%s %s: %s", item.sort, item.allowed, out)
+		}
+	}
+}
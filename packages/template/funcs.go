@@ -52,12 +52,17 @@ func init() {
 	funcs[`Lower`] = tplFunc{lowerTag, defaultTag, `lower`, `Text`}
 	funcs[`AddToolButton`] = tplFunc{defaultTag, defaultTag, `addtoolbutton`, `Title,Icon,Page,PageParams`}
 	funcs[`Address`] = tplFunc{addressTag, defaultTag, `address`, `Wallet`}
+	funcs[`AppAsset`] = tplFunc{appAssetTag, defaultTag, `appasset`, `App,Name`}
+	funcs[`AccountName`] = tplFunc{accountNameTag, defaultTag, `accountname`, `Wallet`}
+	funcs[`ResolveName`] = tplFunc{resolveNameTag, defaultTag, `resolvename`, `Name`}
 	funcs[`Calculate`] = tplFunc{calculateTag, defaultTag, `calculate`, `Exp,Type,Prec`}
 	funcs[`CmpTime`] = tplFunc{cmpTimeTag, defaultTag, `cmptime`, `Time1,Time2`}
 	funcs[`Code`] = tplFunc{defaultTag, defaultTag, `code`, `Text`}
 	funcs[`DateTime`] = tplFunc{dateTimeTag, defaultTag, `datetime`, `DateTime,Format`}
+	funcs[`AddTime`] = tplFunc{addTimeTag, defaultTag, `addtime`, `DateTime,Interval,Format`}
 	funcs[`EcosysParam`] = tplFunc{ecosysparTag, defaultTag, `ecosyspar`, `Name,Index,Source`}
 	funcs[`Em`] = tplFunc{defaultTag, defaultTag, `em`, `Body,Class`}
+	funcs[`FormatMoney`] = tplFunc{formatMoneyTag, defaultTag, `formatmoney`, `Value,Digits`}
 	funcs[`GetVar`] = tplFunc{getvarTag, defaultTag, `getvar`, `Name`}
 	funcs[`ImageInput`] = tplFunc{defaultTag, defaultTag, `imageinput`, `Name,Width,Ratio,Format`}
 	funcs[`InputErr`] = tplFunc{defaultTag, defaultTag, `inputerr`, `*`}
@@ -75,15 +80,23 @@ func init() {
 	funcs[`Form`] = tplFunc{defaultTailTag, defaultTailTag, `form`, `Class,Body`}
 	funcs[`If`] = tplFunc{ifTag, ifFull, `if`, `Condition,Body`}
 	funcs[`Image`] = tplFunc{defaultTailTag, defaultTailTag, `image`, `Src,Alt,Class`}
-	funcs[`Include`] = tplFunc{includeTag, defaultTag, `include`, `Name`}
+	funcs[`Include`] = tplFunc{includeTag, defaultTag, `include`, `Name,Params`}
+	funcs[`Define`] = tplFunc{defineTag, defaultTag, tagDefine, `Name,Data`}
+	funcs[`Component`] = tplFunc{componentTag, defaultTag, `component`, `Name,Params,Data`}
+	funcs[`Range`] = tplFunc{rangeTag, defaultTag, `range`, `Source,From,To,Step`}
 	funcs[`Input`] = tplFunc{defaultTailTag, defaultTailTag, `input`, `Name,Class,Placeholder,Type,@Value,Disabled`}
 	funcs[`Label`] = tplFunc{defaultTailTag, defaultTailTag, `label`, `Body,Class,For`}
 	funcs[`LinkPage`] = tplFunc{defaultTailTag, defaultTailTag, `linkpage`, `Body,Page,Class,PageParams`}
 	funcs[`Data`] = tplFunc{dataTag, defaultTailTag, `data`, `Source,Columns,Data`}
 	funcs[`DBFind`] = tplFunc{dbfindTag, defaultTailTag, `dbfind`, `Name,Source`}
+	funcs[`DBCount`] = tplFunc{aggregateTag(`count`), defaultTag, `dbcount`, `Name,Where`}
+	funcs[`DBSum`] = tplFunc{aggregateTag(`sum`), defaultTag, `dbsum`, `Name,Column,Where`}
+	funcs[`DBMin`] = tplFunc{aggregateTag(`min`), defaultTag, `dbmin`, `Name,Column,Where`}
+	funcs[`DBMax`] = tplFunc{aggregateTag(`max`), defaultTag, `dbmax`, `Name,Column,Where`}
 	funcs[`And`] = tplFunc{andTag, defaultTag, `and`, `*`}
 	funcs[`Or`] = tplFunc{orTag, defaultTag, `or`, `*`}
 	funcs[`P`] = tplFunc{defaultTailTag, defaultTailTag, `p`, `Body,Class`}
+	funcs[`ParseMoney`] = tplFunc{parseMoneyTag, defaultTag, `parsemoney`, `Text,Digits`}
 	funcs[`RadioGroup`] = tplFunc{defaultTailTag, defaultTailTag, `radiogroup`, `Name,Source,NameColumn,ValueColumn,Value,Class`}
 	funcs[`Span`] = tplFunc{defaultTailTag, defaultTailTag, `span`, `Body,Class`}
 	funcs[`QRcode`] = tplFunc{defaultTag, defaultTag, `qrcode`, `Text`}
@@ -134,6 +147,9 @@ func init() {
 		`Custom`:    {tplFunc{customTag, defaultTailFull, `custom`, `Column,Body`}, false},
 		`Vars`:      {tplFunc{tailTag, defaultTailFull, `vars`, `Prefix`}, false},
 		`Cutoff`:    {tplFunc{tailTag, defaultTailFull, `cutoff`, `Cutoff`}, false},
+		`Filter`:    {tplFunc{tailTag, defaultTailFull, `filter`, `Filter`}, false},
+		`Sort`:      {tplFunc{tailTag, defaultTailFull, `sort`, `Sort,Allowed`}, false},
+		`Count`:     {tplFunc{tailTag, defaultTailFull, `count`, `Count`}, false},
 	}}
 	tails[`p`] = forTails{map[string]tailInfo{
 		`Style`: {tplFunc{tailTag, defaultTailFull, `style`, `Style`}, false},
@@ -237,11 +253,112 @@ func addressTag(par parFunc) string {
 	return converter.AddressToString(id)
 }
 
+// accountNameTag returns the first registered name of the wallet or its address
+func accountNameTag(par parFunc) string {
+	idval := (*par.Pars)[`Wallet`]
+	if len(idval) == 0 {
+		idval = (*par.Workspace.Vars)[`key_id`]
+	}
+	id, _ := strconv.ParseInt(idval, 10, 64)
+	if id == 0 {
+		return `unknown address`
+	}
+	if !par.Workspace.SmartContract.VDE {
+		names, err := model.GetAccountNames(converter.StrToInt64((*par.Workspace.Vars)[`ecosystem_id`]),
+			id, time.Now().Unix())
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting account names")
+		} else if len(names) > 0 {
+			return names[0].Name
+		}
+	}
+	return converter.AddressToString(id)
+}
+
+// resolveNameTag returns the address of the registered name
+func resolveNameTag(par parFunc) string {
+	if par.Workspace.SmartContract.VDE {
+		return ``
+	}
+	name, err := smart.NormalizeAccountName((*par.Pars)[`Name`])
+	if err != nil {
+		return ``
+	}
+	id, err := model.ResolveAccountName(converter.StrToInt64((*par.Workspace.Vars)[`ecosystem_id`]),
+		name, time.Now().Unix())
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("resolving account name")
+	}
+	if id == 0 {
+		return ``
+	}
+	return converter.AddressToString(id)
+}
+
+// appAssetTag returns the versioned url of the static asset of the application
+func appAssetTag(par parFunc) string {
+	if par.Workspace.SmartContract.VDE {
+		return ``
+	}
+	binary := &model.Binary{}
+	ecosystemID := converter.StrToInt64((*par.Workspace.Vars)[`ecosystem_id`])
+	binary.SetTablePrefix(ecosystemID)
+	found, err := binary.Get(nil, (*par.Pars)[`App`], (*par.Pars)[`Name`])
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting asset")
+	}
+	if !found {
+		return ``
+	}
+	return fmt.Sprintf(`%sstatic/%d/%s/%s?v=%s`, consts.ApiPath, ecosystemID, binary.App, binary.Name,
+		binary.Version)
+}
+
 func calculateTag(par parFunc) string {
 	return calculate((*par.Pars)[`Exp`], (*par.Pars)[`Type`],
 		converter.StrToInt((*par.Pars)[`Prec`]))
 }
 
+// moneyFormat returns the money format of the ecosystem, Digits parameter overrides money_digit
+func moneyFormat(par parFunc) (*converter.MoneyFormat, error) {
+	prefix := (*par.Workspace.Vars)[`ecosystem_id`]
+	if par.Workspace.SmartContract.VDE {
+		prefix += `_vde`
+	}
+	format, err := smart.GetMoneyFormat(prefix)
+	if err != nil {
+		return nil, err
+	}
+	if digits := (*par.Pars)[`Digits`]; len(digits) > 0 {
+		format.Digits = int32(converter.StrToInt(digits))
+	}
+	return format, nil
+}
+
+func formatMoneyTag(par parFunc) string {
+	format, err := moneyFormat(par)
+	if err != nil {
+		return err.Error()
+	}
+	out, err := format.Format((*par.Pars)[`Value`])
+	if err != nil {
+		return err.Error()
+	}
+	return out
+}
+
+func parseMoneyTag(par parFunc) string {
+	format, err := moneyFormat(par)
+	if err != nil {
+		return err.Error()
+	}
+	out, err := format.Parse((*par.Pars)[`Text`])
+	if err != nil {
+		return err.Error()
+	}
+	return out
+}
+
 func ecosysparTag(par parFunc) string {
 	if len((*par.Pars)[`Name`]) == 0 {
 		return ``
@@ -435,6 +552,21 @@ func dataTag(par parFunc) string {
 	return ``
 }
 
+// aggregateTag returns the function of template which computes the aggregate value of the table
+func aggregateTag(fn string) func(par parFunc) string {
+	return func(par parFunc) string {
+		if len((*par.Pars)[`Name`]) == 0 {
+			return ``
+		}
+		_, ret, err := smart.Aggregate(par.Workspace.SmartContract, fn, (*par.Pars)[`Name`],
+			(*par.Pars)[`Column`], (*par.Pars)[`Where`], nil)
+		if err != nil {
+			return err.Error()
+		}
+		return ret
+	}
+}
+
 func dbfindTag(par parFunc) string {
 	var (
 		fields string
@@ -445,6 +577,7 @@ func dbfindTag(par parFunc) string {
 		where  string
 		order  string
 		limit  = 25
+		offset int
 
 		cutoffColumns   = make(map[string]bool)
 		extendedColumns = make(map[string]string)
@@ -522,6 +655,52 @@ func dbfindTag(par parFunc) string {
 		}
 	}
 
+	rowWhere, rowParams, err := sc.RowAccess(tblname, smart.RowRead)
+	if err != nil {
+		return `Access denied`
+	}
+	addWhere := func(cond string) {
+		if len(where) > 0 {
+			where = ` where (` + where[len(` where `):] + `) and ` + cond
+		} else {
+			where = ` where ` + cond
+		}
+	}
+	if len(rowWhere) > 0 {
+		addWhere(rowWhere)
+	}
+	if par.Node.Attr[`filter`] != nil {
+		filterWhere, filterParams, err := FilterToWhere(par.Node.Attr[`filter`].(string), columnTypes)
+		if err != nil {
+			return err.Error()
+		}
+		addWhere(filterWhere)
+		rowParams = append(rowParams, filterParams...)
+	}
+	if par.Node.Attr[`sort`] != nil {
+		var allowed string
+		if par.Node.Attr[`allowed`] != nil {
+			allowed = par.Node.Attr[`allowed`].(string)
+		}
+		if sortOrder := sortToOrder(par.Node.Attr[`sort`].(string), allowed, columnTypes); len(sortOrder) > 0 {
+			order = ` order by ` + sortOrder
+		}
+	}
+	if par.Node.Attr[`offset`] != nil {
+		if offset = converter.StrToInt(par.Node.Attr[`offset`].(string)); offset < 0 {
+			offset = 0
+		}
+	}
+	if par.Node.Attr[`count`] != nil {
+		total, err := model.Single(`select count(*) from "`+tblname+`"`+where, rowParams...).Int64()
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting count of rows")
+			return err.Error()
+		}
+		par.Node.Attr[`total`] = converter.Int64ToStr(total)
+		(*par.Workspace.Vars)[par.Node.Attr[`count`].(string)] = converter.Int64ToStr(total)
+	}
+
 	columnNames := make([]string, len(queryColumns))
 	copy(columnNames, queryColumns)
 	for i, col := range queryColumns {
@@ -541,7 +720,8 @@ func dbfindTag(par parFunc) string {
 
 	fields = strings.Join(queryColumns, ",")
 
-	list, err := model.GetAll(`select `+fields+` from "`+tblname+`"`+where+order, limit)
+	list, err := model.GetAll(`select `+fields+` from "`+tblname+`"`+where+order+
+		fmt.Sprintf(` limit %d offset %d`, limit, offset), limit, rowParams...)
 	if err != nil {
 		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting all from db")
 		return err.Error()
@@ -674,21 +854,12 @@ func tailTag(par parFunc) string {
 }
 
 func includeTag(par parFunc) string {
-	if len((*par.Pars)[`Name`]) >= 0 && len((*par.Workspace.Vars)[`_include`]) < 5 {
-		pattern, err := model.Single(`select value from "`+(*par.Workspace.Vars)[`ecosystem_id`]+`_blocks" where name=?`, (*par.Pars)[`Name`]).String()
+	if len((*par.Pars)[`Name`]) > 0 {
+		pattern, err := getBlock(par, (*par.Pars)[`Name`])
 		if err != nil {
-			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting block by name")
 			return err.Error()
 		}
-		if len(pattern) > 0 {
-			root := node{}
-			(*par.Workspace.Vars)[`_include`] += `1`
-			process(pattern, &root, par.Workspace)
-			(*par.Workspace.Vars)[`_include`] = (*par.Workspace.Vars)[`_include`][:len((*par.Workspace.Vars)[`_include`])-1]
-			for _, item := range root.Children {
-				par.Owner.Children = append(par.Owner.Children, item)
-			}
-		}
+		renderPartial(par, pattern, splitParams((*par.Pars)[`Params`]))
 	}
 	return ``
 }
@@ -835,32 +1006,45 @@ func dateTimeTag(par parFunc) string {
 	if len(datetime) == 0 || datetime[0] < '0' || datetime[0] > '9' {
 		return ``
 	}
-	defTime := `1970-01-01T00:00:00`
-	lenTime := len(datetime)
-	if lenTime < len(defTime) {
-		datetime += defTime[lenTime:]
-	}
-	itime, err := time.Parse(`2006-01-02T15:04:05`, strings.Replace(datetime[:19], ` `, `T`, -1))
+	unix, err := parseDateTime(datetime)
 	if err != nil {
 		return err.Error()
 	}
+	return converter.FormatTime(timeFormat(par), unix)
+}
+
+// parseDateTime returns the unix time of the value, the omitted parts of date and time are set to zero
+func parseDateTime(datetime string) (int64, error) {
+	defTime := `1970-01-01T00:00:00`
+	if lenTime := len(datetime); lenTime < len(defTime) && strings.IndexByte(datetime, '-') > 0 {
+		datetime += defTime[lenTime:]
+	}
+	return converter.ParseTime(datetime)
+}
+
+// timeFormat returns Format parameter or the format of date and time of the language
+func timeFormat(par parFunc) string {
 	format := (*par.Pars)[`Format`]
 	if len(format) == 0 {
 		format, _ = language.LangText(`timeformat`, converter.StrToInt((*par.Workspace.Vars)[`ecosystem_id`]),
 			(*par.Workspace.Vars)[`lang`], par.Workspace.SmartContract.VDE)
 		if format == `timeformat` {
-			format = `2006-01-02 15:04:05`
+			format = converter.DefaultTimeFormat
 		}
 	}
-	format = strings.Replace(format, `YYYY`, `2006`, -1)
-	format = strings.Replace(format, `YY`, `06`, -1)
-	format = strings.Replace(format, `MM`, `01`, -1)
-	format = strings.Replace(format, `DD`, `02`, -1)
-	format = strings.Replace(format, `HH`, `15`, -1)
-	format = strings.Replace(format, `MI`, `04`, -1)
-	format = strings.Replace(format, `SS`, `05`, -1)
+	return format
+}
 
-	return itime.Format(format)
+// addTimeTag adds the interval to the date and time, the result is UTC
+func addTimeTag(par parFunc) string {
+	unix, err := parseDateTime((*par.Pars)[`DateTime`])
+	if err != nil {
+		return err.Error()
+	}
+	if unix, err = converter.AddInterval(unix, (*par.Pars)[`Interval`]); err != nil {
+		return err.Error()
+	}
+	return converter.FormatTime(timeFormat(par), unix)
 }
 
 func cmpTimeTag(par parFunc) string {
@@ -871,6 +1055,9 @@ func cmpTimeTag(par parFunc) string {
 		}
 		return val
 	}
+	if cmp, err := converter.CompareTime((*par.Pars)[`Time1`], (*par.Pars)[`Time2`]); err == nil {
+		return strconv.Itoa(cmp)
+	}
 	left := prepare((*par.Pars)[`Time1`])
 	right := prepare((*par.Pars)[`Time2`])
 	if left == right {
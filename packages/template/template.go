@@ -40,9 +40,10 @@ import (
 )
 
 const (
-	tagText = `text`
-	tagData = `data`
-	maxDeep = 16
+	tagText   = `text`
+	tagData   = `data`
+	tagDefine = `define`
+	maxDeep   = 16
 )
 
 type node struct {
@@ -64,6 +65,7 @@ type Workspace struct {
 	Vars          *map[string]string
 	SmartContract *smart.SmartContract
 	Timeout       *bool
+	Components    map[string]string // templates of components which are defined on the page
 }
 
 type parFunc struct {
@@ -378,7 +380,11 @@ func callFunc(curFunc *tplFunc, owner *node, workspace *Workspace, params *[][]r
 	} else {
 		for i, v := range strings.Split(curFunc.Params, `,`) {
 			if i < len(*params) {
-				val := macro(strings.TrimSpace(string((*params)[i])), workspace.Vars)
+				val := strings.TrimSpace(string((*params)[i]))
+				// the body of the component is saved as is, its variables are replaced when it's rendered
+				if curFunc.Tag != tagDefine {
+					val = macro(val, workspace.Vars)
+				}
 				off := strings.IndexByte(val, ':')
 				if off != -1 && strings.Contains(curFunc.Params, val[:off]) {
 					pars[val[:off]] = trim(val[off+1:], val[:off] != `Data`)
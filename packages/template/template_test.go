@@ -105,6 +105,8 @@ var forTest = tplList{
 	{`DateTime(2017-11-07T17:51:08)+DateTime(2015-08-27T09:01:00,HH:MI DD.MM.YYYY)
 	+CmpTime(2017-11-07T17:51:08,2017-11-07)CmpTime(2017-11-07T17:51:08,2017-11-07T20:22:01)CmpTime(2015-10-01T17:51:08,2015-10-01T17:51:08)=DateTime(NULL)`,
 		`[{"tag":"text","text":"2017-11-07 17:51:08"},{"tag":"text","text":"+09:01 27.08.2015"},{"tag":"text","text":"\n\t+1-10"},{"tag":"text","text":"="}]`},
+	{`DateTime(2017-11-07T20:51:08+03:00)AddTime(2018-01-31, 1 month, DD.MM.YYYY)CmpTime(2017-11-07T20:51:08+03:00,2017-11-07T18:00:00)`,
+		`[{"tag":"text","text":"2017-11-07 17:51:08"},{"tag":"text","text":"28.02.2018"},{"tag":"text","text":"-1"}]`},
 	{`SetVar(pref,unicode Р)Input(Name: myid, Value: #pref#)Strong(qqq)`,
 		`[{"tag":"input","attr":{"name":"myid","value":"unicode Р"}},{"tag":"strong","children":[{"tag":"text","text":"qqq"}]}]`},
 	{`ImageInput(myimg,100,40)`,
@@ -237,3 +239,29 @@ var forFullTest = tplList{
 			}.Else {Fourth}If(0).Else{ALL right}.What`,
 		`[{"tag":"if","attr":{"condition":"true"},"children":[{"tag":"text","text":"OK"}],"tail":[{"tag":"else","children":[{"tag":"text","text":"false"}]}]},{"tag":"if","attr":{"condition":"false"},"children":[{"tag":"text","text":"FALSE"}],"tail":[{"tag":"elseif","attr":{"condition":"1"},"children":[{"tag":"text","text":"Else OK"}]},{"tag":"else","children":[{"tag":"text","text":"Fourth"}]}]},{"tag":"if","attr":{"condition":"0"},"tail":[{"tag":"else","children":[{"tag":"text","text":"ALL right"}]}]},{"tag":"text","text":".What"}]`},
 }
+
+func TestComponents(t *testing.T) {
+	var timeout bool
+	for _, item := range forComponents {
+		vars := map[string]string{`_full`: `0`, `title`: `page`}
+		templ := Template2JSON(item.input, &timeout, &vars)
+		if string(templ) != item.want {
+			t.Errorf("wrong json \r\n%s != \r\n%s", templ, item.want)
+		}
+		if vars[`title`] != `page` || len(vars[`_include`]) > 0 {
+			t.Errorf("variables are not restored %v", vars)
+		}
+	}
+}
+
+var forComponents = tplList{
+	{`Define(card){Div(card){P(#caption#)#body#}}Component(card, "caption=First"){Span(one)}Component(card, caption=Second)`,
+		`[{"tag":"div","attr":{"class":"card"},"children":[{"tag":"p","children":[{"tag":"text","text":"First"}]},{"tag":"span","children":[{"tag":"text","text":"one"}]}]},{"tag":"div","attr":{"class":"card"},"children":[{"tag":"p","children":[{"tag":"text","text":"Second"}]}]}]`},
+	{`Define(Name: hdr, Data: Strong(#title#))Component(hdr, "title=Inner, _include=11111")Span(#title#)`,
+		`[{"tag":"strong","children":[{"tag":"text","text":"Inner"}]},{"tag":"span","children":[{"tag":"text","text":"page"}]}]`},
+	{`Define(loop){Component(loop)}Component(loop)`, `[]`},
+	{`Range(nums, 0, 10, 3)ForList(nums, Index: i){Span(#i#:#id#)}`,
+		`[{"tag":"range","attr":{"columns":["id"],"data":[["0"],["3"],["6"],["9"]],"from":"0","source":"nums","step":"3","to":"10","types":["text"]}},{"tag":"forlist","attr":{"index":"i","source":"nums"},"children":[{"tag":"span","children":[{"tag":"text","text":"1:0"}]},{"tag":"span","children":[{"tag":"text","text":"2:3"}]},{"tag":"span","children":[{"tag":"text","text":"3:6"}]},{"tag":"span","children":[{"tag":"text","text":"4:9"}]}]}]`},
+	{`Range(Source: down, From: 3, To: 0, Step: -1)`,
+		`[{"tag":"range","attr":{"columns":["id"],"data":[["3"],["2"],["1"]],"from":"3","source":"down","step":"-1","to":"0","types":["text"]}}]`},
+}
@@ -0,0 +1,98 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package thumbnail
+
+import (
+	"container/list"
+	"sync"
+)
+
+type cacheItem struct {
+	key      string
+	data     []byte
+	mimeType string
+}
+
+// Cache keeps the recently used thumbnails within the limit of the total size
+type Cache struct {
+	mutex    sync.Mutex
+	maxBytes int
+	size     int
+	items    map[string]*list.Element
+	order    *list.List
+}
+
+// NewCache returns a new cache which keeps up to maxBytes of thumbnails
+func NewCache(maxBytes int) *Cache {
+	return &Cache{
+		maxBytes: maxBytes,
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Get returns the cached thumbnail and its mime type
+func (c *Cache) Get(key string) ([]byte, string, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	el, ok := c.items[key]
+	if !ok {
+		return nil, ``, false
+	}
+	c.order.MoveToFront(el)
+	item := el.Value.(*cacheItem)
+	return item.data, item.mimeType, true
+}
+
+// Set stores the thumbnail removing the least recently used ones if the cache is full
+func (c *Cache) Set(key string, data []byte, mimeType string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if len(data) > c.maxBytes {
+		return
+	}
+	if el, ok := c.items[key]; ok {
+		c.remove(el)
+	}
+	c.items[key] = c.order.PushFront(&cacheItem{key: key, data: data, mimeType: mimeType})
+	c.size += len(data)
+	for c.size > c.maxBytes {
+		c.remove(c.order.Back())
+	}
+}
+
+// SetMaxBytes changes the limit of the total size removing the least recently used thumbnails
+func (c *Cache) SetMaxBytes(maxBytes int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.maxBytes = maxBytes
+	for c.size > c.maxBytes {
+		c.remove(c.order.Back())
+	}
+}
+
+func (c *Cache) remove(el *list.Element) {
+	item := c.order.Remove(el).(*cacheItem)
+	delete(c.items, item.key)
+	c.size -= len(item.data)
+}
@@ -0,0 +1,145 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package thumbnail
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/draw"
+	_ "image/gif" // decoding of gif images
+	"image/jpeg"
+	"image/png"
+)
+
+const (
+	// MaxSourceSize is the maximum size in bytes of the original image
+	MaxSourceSize = 16 << 20
+	// MaxSourcePixels is the maximum count of pixels of the original image
+	MaxSourcePixels = 40000000
+	// MaxSide is the maximum width or height of the thumbnail
+	MaxSide = 1024
+
+	jpegQuality = 85
+)
+
+var (
+	// ErrTooLarge is returned when the original image exceeds the limits
+	ErrTooLarge = errors.New("image is too large")
+	// ErrSize is returned when the requested size of the thumbnail is wrong
+	ErrSize = errors.New("wrong thumbnail size")
+)
+
+// Make returns the thumbnail of the image which fits width x height and its mime type.
+// Zero width or height means that the side is not limited. The image is never enlarged.
+func Make(data []byte, width, height int) ([]byte, string, error) {
+	if width < 0 || height < 0 || width > MaxSide || height > MaxSide || width+height == 0 {
+		return nil, ``, ErrSize
+	}
+	if len(data) > MaxSourceSize {
+		return nil, ``, ErrTooLarge
+	}
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ``, err
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > MaxSourcePixels {
+		return nil, ``, ErrTooLarge
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ``, err
+	}
+	w, h := fitSize(cfg.Width, cfg.Height, width, height)
+	dst := resize(src, w, h)
+
+	var out bytes.Buffer
+	if format == `jpeg` {
+		err = jpeg.Encode(&out, dst, &jpeg.Options{Quality: jpegQuality})
+		return out.Bytes(), `image/jpeg`, err
+	}
+	err = png.Encode(&out, dst)
+	return out.Bytes(), `image/png`, err
+}
+
+// fitSize returns the size of the thumbnail keeping the aspect ratio of the original
+func fitSize(srcWidth, srcHeight, width, height int) (int, int) {
+	if width == 0 || width > srcWidth {
+		width = srcWidth
+	}
+	if height == 0 || height > srcHeight {
+		height = srcHeight
+	}
+	if w := srcWidth * height / srcHeight; w <= width {
+		width = w
+	} else {
+		height = srcHeight * width / srcWidth
+	}
+	if width < 1 {
+		width = 1
+	}
+	if height < 1 {
+		height = 1
+	}
+	return width, height
+}
+
+// resize scales the image down averaging the source pixels covered by each pixel of the result
+func resize(src image.Image, width, height int) *image.RGBA {
+	bounds := src.Bounds()
+	rgba, ok := src.(*image.RGBA)
+	if !ok || bounds.Min != (image.Point{}) {
+		rgba = image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+		draw.Draw(rgba, rgba.Bounds(), src, bounds.Min, draw.Src)
+	}
+	srcWidth, srcHeight := bounds.Dx(), bounds.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0, y1 := y*srcHeight/height, (y+1)*srcHeight/height
+		if y1 == y0 {
+			y1++
+		}
+		for x := 0; x < width; x++ {
+			x0, x1 := x*srcWidth/width, (x+1)*srcWidth/width
+			if x1 == x0 {
+				x1++
+			}
+			var sum [4]int
+			for sy := y0; sy < y1; sy++ {
+				off := sy*rgba.Stride + x0*4
+				for sx := x0; sx < x1; sx++ {
+					for i := 0; i < 4; i++ {
+						sum[i] += int(rgba.Pix[off+i])
+					}
+					off += 4
+				}
+			}
+			count := (y1 - y0) * (x1 - x0)
+			off := y*dst.Stride + x*4
+			for i := 0; i < 4; i++ {
+				dst.Pix[off+i] = uint8(sum[i] / count)
+			}
+		}
+	}
+	return dst
+}
@@ -0,0 +1,121 @@
+// MIT License
+//
+// Copyright (c) 2016-2018 GenesisKernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package thumbnail
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func encodePNG(t *testing.T, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{uint8(x), uint8(y), 100, 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestMake(t *testing.T) {
+	src := encodePNG(t, 200, 100)
+	cases := []struct {
+		width, height int
+		outW, outH    int
+	}{
+		{50, 50, 50, 25},
+		{0, 20, 40, 20},
+		{100, 0, 100, 50},
+		{400, 400, 200, 100},
+	}
+	for _, item := range cases {
+		out, mimeType, err := Make(src, item.width, item.height)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mimeType != `image/png` {
+			t.Errorf("wrong mime type %s", mimeType)
+		}
+		cfg, err := png.DecodeConfig(bytes.NewReader(out))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cfg.Width != item.outW || cfg.Height != item.outH {
+			t.Errorf("%dx%d: got %dx%d", item.width, item.height, cfg.Width, cfg.Height)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, 64, 64)), nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, mimeType, err := Make(buf.Bytes(), 16, 16); err != nil || mimeType != `image/jpeg` {
+		t.Errorf("jpeg: %s %v", mimeType, err)
+	}
+
+	for _, size := range [][2]int{{0, 0}, {-1, 10}, {MaxSide + 1, 10}} {
+		if _, _, err := Make(src, size[0], size[1]); err != ErrSize {
+			t.Errorf("%v: %v", size, err)
+		}
+	}
+	if _, _, err := Make([]byte(`not an image`), 10, 10); err == nil {
+		t.Error("wrong image must be rejected")
+	}
+}
+
+func TestCache(t *testing.T) {
+	cache := NewCache(10)
+	cache.Set(`a`, []byte(`1234`), `image/png`)
+	cache.Set(`b`, []byte(`1234`), `image/png`)
+	if _, _, ok := cache.Get(`a`); !ok {
+		t.Error("a must be cached")
+	}
+	cache.Set(`c`, []byte(`1234`), `image/png`)
+	if _, _, ok := cache.Get(`b`); ok {
+		t.Error("b must be evicted")
+	}
+	if data, mimeType, ok := cache.Get(`a`); !ok || string(data) != `1234` || mimeType != `image/png` {
+		t.Error("a must be kept")
+	}
+	cache.Set(`d`, make([]byte, 11), `image/png`)
+	if _, _, ok := cache.Get(`d`); ok {
+		t.Error("d must not be cached")
+	}
+
+	// a is the most recently used, so c is evicted
+	cache.SetMaxBytes(5)
+	if _, _, ok := cache.Get(`c`); ok {
+		t.Error("c must be evicted")
+	}
+	if _, _, ok := cache.Get(`a`); !ok {
+		t.Error("a must be kept")
+	}
+}
@@ -31,4 +31,9 @@ type Header struct {
 	NodePosition  int64
 	PublicKey     []byte
 	BinSignatures []byte
+	Nonce         int64
+	// ExpireBlock is the last block which can contain the transaction, 0 means no limit
+	ExpireBlock int64
+	// ExpireTime is the latest time of the block which can contain the transaction, 0 means no limit
+	ExpireTime int64
 }
@@ -32,10 +32,23 @@ type SmartContract struct {
 	PayOver        string
 	SignedBy       int64
 	Data           []byte
+	// MaxFee is the maximal amount of tokens which can be paid for the transaction, empty means no limit
+	MaxFee string
 }
 
-// ForSign is converting SmartContract to string
+// ForSign is converting SmartContract to string, the nonce, the expiration and the max fee are appended if they are set.
+// The transactions with nonce are accepted since the block tx_nonce_block so the older data for sign is unchanged
 func (s SmartContract) ForSign() string {
-	return fmt.Sprintf("%d,%d,%d,%d,%d,%s,%s,%d", s.Type, s.Time, s.KeyID, s.EcosystemID,
+	ret := fmt.Sprintf("%d,%d,%d,%d,%d,%s,%s,%d", s.Type, s.Time, s.KeyID, s.EcosystemID,
 		s.TokenEcosystem, s.MaxSum, s.PayOver, s.SignedBy)
+	if s.Nonce != 0 {
+		ret += fmt.Sprintf(",n%d", s.Nonce)
+	}
+	if s.ExpireBlock != 0 || s.ExpireTime != 0 {
+		ret += fmt.Sprintf(",e%d,%d", s.ExpireBlock, s.ExpireTime)
+	}
+	if len(s.MaxFee) > 0 {
+		ret += ",f" + s.MaxFee
+	}
+	return ret
 }
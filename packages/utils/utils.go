@@ -23,7 +23,6 @@
 package utils
 
 import (
-	"context"
 	"encoding/hex"
 	"errors"
 	"flag"
@@ -43,6 +42,8 @@ import (
 	"github.com/GenesisKernel/go-genesis/packages/consts"
 	"github.com/GenesisKernel/go-genesis/packages/converter"
 	"github.com/GenesisKernel/go-genesis/packages/crypto"
+	"github.com/GenesisKernel/go-genesis/packages/faults"
+	"github.com/GenesisKernel/go-genesis/packages/proxy"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -55,16 +56,11 @@ type BlockData struct {
 	NodePosition int64
 	Sign         []byte
 	Hash         []byte
+	StateHash    []byte
 	Version      int
 }
 
 var (
-	// ReturnCh is chan for returns
-	ReturnCh chan string
-	// CancelFunc is represents cancel func
-	CancelFunc context.CancelFunc
-	// DaemonsCount is number of daemons
-	DaemonsCount      int
 	PrivateBlockchain = flag.Bool("privateBlockchain", false, "Is blockchain private")
 )
 
@@ -228,19 +224,21 @@ func CheckSign(publicKeys [][]byte, forSign string, signs []byte, nodeKeyOrLogin
 			return false, fmt.Errorf("sign error %d!=%d", len(publicKeys), len(signsSlice))
 		}
 	}
-	return crypto.CheckSign(publicKeys[0], forSign, signsSlice[0])
+	return crypto.CheckSignCached(publicKeys[0], forSign, signsSlice[0])
 }
 
 // MerkleTreeRoot rertun Merkle value
 func MerkleTreeRoot(dataArray [][]byte) []byte {
 	log.Debug("dataArray: %s", dataArray)
 	result := make(map[int32][][]byte)
+	pair := converter.GetBytes()
+	defer converter.PutBytes(pair)
 	for _, v := range dataArray {
 		hash, err := crypto.DoubleHash(v)
 		if err != nil {
 			log.WithFields(log.Fields{"error": err, "type": consts.CryptoError}).Fatal("double hasing value, while calculating merkle tree root")
 		}
-		hash = converter.BinToHex(hash)
+		hash = converter.AppendHex(nil, hash)
 		result[0] = append(result[0], hash)
 	}
 	var j int32
@@ -254,18 +252,20 @@ func MerkleTreeRoot(dataArray [][]byte) []byte {
 				}
 			} else {
 				if _, ok := result[j+1]; !ok {
-					hash, err := crypto.DoubleHash(append(result[j][i], result[j][i+1]...))
+					*pair = append(append((*pair)[:0], result[j][i]...), result[j][i+1]...)
+					hash, err := crypto.DoubleHash(*pair)
 					if err != nil {
 						log.WithFields(log.Fields{"error": err, "type": consts.CryptoError}).Fatal("double hasing value, while calculating merkle tree root")
 					}
-					hash = converter.BinToHex(hash)
+					hash = converter.AppendHex(nil, hash)
 					result[j+1] = [][]byte{hash}
 				} else {
-					hash, err := crypto.DoubleHash([]byte(append(result[j][i], result[j][i+1]...)))
+					*pair = append(append((*pair)[:0], result[j][i]...), result[j][i+1]...)
+					hash, err := crypto.DoubleHash(*pair)
 					if err != nil {
 						log.WithFields(log.Fields{"error": err, "type": consts.CryptoError}).Fatal("double hasing value, while calculating merkle tree root")
 					}
-					hash = converter.BinToHex(hash)
+					hash = converter.AppendHex(nil, hash)
 					result[j+1] = append(result[j+1], hash)
 				}
 			}
@@ -289,7 +289,10 @@ func TypeInt(txType string) int64 {
 
 // TCPConn connects to the address
 func TCPConn(Addr string) (net.Conn, error) {
-	conn, err := net.DialTimeout("tcp", Addr, 10*time.Second)
+	if err := faults.Hit(faults.PeerConnect); err != nil {
+		return nil, ErrInfo(err)
+	}
+	conn, err := proxy.DialTimeout(Addr, 10*time.Second)
 	if err != nil {
 		log.WithFields(log.Fields{"type": consts.ConnectionError, "error": err, "address": Addr}).Debug("dialing tcp")
 		return nil, ErrInfo(err)
@@ -309,54 +312,6 @@ func GetCurrentDir() string {
 	return dir
 }
 
-// GetBlockBody gets the block data
-func GetBlockBody(host string, blockID int64, dataTypeBlockBody int64) ([]byte, error) {
-	conn, err := TCPConn(host)
-	if err != nil {
-		return nil, ErrInfo(err)
-	}
-	defer conn.Close()
-
-	// send the type of data
-	_, err = conn.Write(converter.DecToBin(dataTypeBlockBody, 2))
-	if err != nil {
-		log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("writing data type block body to connection")
-		return nil, ErrInfo(err)
-	}
-
-	// send the number of a block
-	_, err = conn.Write(converter.DecToBin(blockID, 4))
-	if err != nil {
-		log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("writing data type block body to connection")
-		return nil, ErrInfo(err)
-	}
-
-	// receive the data size as a response that server wants to transfer
-	buf := make([]byte, 4)
-	_, err = conn.Read(buf)
-	if err != nil {
-		log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("reading block data size from connection")
-		return nil, ErrInfo(err)
-	}
-	// if the data size is less than 10mb, we will receive them
-	dataSize := converter.BinToDec(buf)
-	var binaryBlock []byte
-	if dataSize < 10485760 && dataSize > 0 {
-		binaryBlock = make([]byte, dataSize)
-
-		_, err = io.ReadFull(conn, binaryBlock)
-		if err != nil {
-			log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("reading block data from connection")
-			return nil, ErrInfo(err)
-		}
-	} else {
-		log.Error("null block")
-		return nil, ErrInfo("null block")
-	}
-	return binaryBlock, nil
-
-}
-
 // ShellExecute runs cmdline
 func ShellExecute(cmdline string) {
 	time.Sleep(500 * time.Millisecond)
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	flags "github.com/jessevdk/go-flags"
+	"github.com/pkg/errors"
+
+	"github.com/GenesisKernel/go-genesis/packages/client"
+	"github.com/GenesisKernel/go-genesis/packages/offline"
+)
+
+var opts struct {
+	Node      string   `long:"node" description:"node address, for example http://127.0.0.1:7079" required:"true"`
+	KeyPath   string   `long:"key-path" description:"path to private key in hex" required:"true"`
+	Ecosystem int64    `long:"ecosystem" description:"ecosystem of the contracts" default:"1"`
+	Contracts []string `long:"contract" description:"name of the contract, can be repeated, all contracts of the ecosystem by default"`
+	Package   string   `long:"package" description:"name of the generated package" default:"contracts"`
+	Out       string   `long:"out" description:"path to the generated file" default:"contracts.go"`
+}
+
+func generate() error {
+	key, err := ioutil.ReadFile(opts.KeyPath)
+	if err != nil {
+		return errors.Wrapf(err, "reading private key")
+	}
+	c := client.New(opts.Node)
+	if err = c.Login(strings.TrimSpace(string(key)), opts.Ecosystem); err != nil {
+		return errors.Wrapf(err, "login")
+	}
+	names := opts.Contracts
+	if len(names) == 0 {
+		if names, err = c.Contracts(); err != nil {
+			return errors.Wrapf(err, "getting contracts")
+		}
+	}
+	contracts := make([]*offline.Contract, 0, len(names))
+	for _, name := range names {
+		contract, err := c.Contract(name)
+		if err != nil {
+			return errors.Wrapf(err, "getting contract %s", name)
+		}
+		contracts = append(contracts, contract)
+	}
+	src, err := client.Generate(opts.Package, contracts)
+	if err != nil {
+		return errors.Wrapf(err, "generating bindings")
+	}
+	return ioutil.WriteFile(opts.Out, src, 0644)
+}
+
+func main() {
+	if _, err := flags.Parse(&opts); err != nil {
+		os.Exit(1)
+	}
+	if err := generate(); err != nil {
+		fmt.Println("Error while generating:", err.Error())
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,320 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	flags "github.com/jessevdk/go-flags"
+	"github.com/pkg/errors"
+
+	"github.com/GenesisKernel/go-genesis/packages/offline"
+)
+
+const apiPath = "/api/v2/"
+
+type NodeOpt struct {
+	Node string `long:"node" description:"node address, for example http://127.0.0.1:7079" required:"true"`
+}
+
+var opts struct {
+	FetchCommand struct {
+		NodeOpt
+
+		Token    string `long:"token" description:"JWT token of any session of the ecosystem" required:"true"`
+		Contract string `long:"contract" description:"name of the contract" required:"true"`
+		Out      string `long:"out" description:"path to the description of the contract" default:"contract.json"`
+	} `command:"fetch"`
+
+	SignCommand struct {
+		TxOpt
+
+		Out string `long:"out" description:"path to the signed transaction in hex" default:"tx.hex"`
+	} `command:"sign"`
+
+	SendCommand struct {
+		NodeOpt
+
+		Tx string `long:"tx" description:"path to the signed transaction in hex" default:"tx.hex"`
+	} `command:"send"`
+
+	SubmitCommand struct {
+		NodeOpt
+		TxOpt
+
+		Token    string `long:"token" description:"JWT token of any session of the ecosystem" required:"true"`
+		Attempts int    `long:"attempts" description:"how many times the expired transaction is signed and sent again" default:"3"`
+	} `command:"submit"`
+}
+
+type TxOpt struct {
+	Contract       string            `long:"contract" description:"path to the description of the contract" default:"contract.json"`
+	KeyPath        string            `long:"key-path" description:"path to private key in hex" required:"true"`
+	Ecosystem      int64             `long:"ecosystem" description:"ecosystem of the transaction" default:"1"`
+	Params         map[string]string `long:"param" description:"parameter of the contract as name:value"`
+	List           []string          `long:"list" description:"item of array parameter as name:value, can be repeated"`
+	Time           int64             `long:"time" description:"time of the transaction, the current time by default"`
+	TokenEcosystem int64             `long:"token-ecosystem" description:"ecosystem of tokens which pay for the transaction"`
+	MaxSum         string            `long:"max-sum" description:"maximum sum of the payment"`
+	PayOver        string            `long:"payover" description:"additional payment"`
+	MaxFee         string            `long:"max-fee" description:"maximum amount of tokens paid for the transaction"`
+	Nonce          int64             `long:"nonce" description:"nonce of the transaction, see nonce API"`
+	ExpireBlock    int64             `long:"expire-block" description:"the last block which can contain the transaction"`
+	ExpireTime     int64             `long:"expire-time" description:"the latest block time which the transaction is valid in"`
+}
+
+func fetch() error {
+	o := opts.FetchCommand
+	req, err := http.NewRequest("GET", strings.TrimRight(o.Node, "/")+apiPath+"contract/"+o.Contract, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+o.Token)
+	body, err := doRequest(req)
+	if err != nil {
+		return errors.Wrapf(err, "getting contract")
+	}
+	var contract offline.Contract
+	if err = json.Unmarshal(body, &contract); err != nil {
+		return errors.Wrapf(err, "unmarshalling contract")
+	}
+	out, err := json.MarshalIndent(contract, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(o.Out, out, 0644)
+}
+
+func newTransaction(o TxOpt) (*offline.Transaction, string, error) {
+	var contract offline.Contract
+	data, err := ioutil.ReadFile(o.Contract)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "reading contract")
+	}
+	if err = json.Unmarshal(data, &contract); err != nil {
+		return nil, "", errors.Wrapf(err, "unmarshalling contract")
+	}
+	key, err := ioutil.ReadFile(o.KeyPath)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "reading private key")
+	}
+	t := &offline.Transaction{
+		Contract:       &contract,
+		EcosystemID:    o.Ecosystem,
+		Time:           o.Time,
+		TokenEcosystem: o.TokenEcosystem,
+		MaxSum:         o.MaxSum,
+		PayOver:        o.PayOver,
+		MaxFee:         o.MaxFee,
+		Nonce:          o.Nonce,
+		ExpireBlock:    o.ExpireBlock,
+		ExpireTime:     o.ExpireTime,
+		Params:         url.Values{},
+	}
+	if t.Time == 0 {
+		t.Time = time.Now().Unix()
+	}
+	for name, value := range o.Params {
+		t.Params.Set(name, value)
+	}
+	for _, item := range o.List {
+		pair := strings.SplitN(item, ":", 2)
+		if len(pair) != 2 {
+			return nil, "", fmt.Errorf("wrong list item %s", item)
+		}
+		t.Params.Add(pair[0], pair[1])
+	}
+	return t, strings.TrimSpace(string(key)), nil
+}
+
+func sign() error {
+	o := opts.SignCommand
+	t, key, err := newTransaction(o.TxOpt)
+	if err != nil {
+		return err
+	}
+	blob, err := t.Sign(key)
+	if err != nil {
+		return errors.Wrapf(err, "signing transaction")
+	}
+	hash, err := offline.Hash(blob)
+	if err != nil {
+		return err
+	}
+	fmt.Println("hash:", hex.EncodeToString(hash))
+	return ioutil.WriteFile(o.Out, []byte(hex.EncodeToString(blob)), 0644)
+}
+
+func send() error {
+	o := opts.SendCommand
+	blob, err := ioutil.ReadFile(o.Tx)
+	if err != nil {
+		return errors.Wrapf(err, "reading transaction")
+	}
+	data, err := hex.DecodeString(strings.TrimSpace(string(blob)))
+	if err != nil {
+		return errors.Wrapf(err, "decoding transaction")
+	}
+	hash, err := (&node{url: o.Node}).SendTx(data)
+	if err != nil {
+		return err
+	}
+	fmt.Println("hash:", hex.EncodeToString(hash))
+	return nil
+}
+
+func submit() error {
+	o := opts.SubmitCommand
+	t, key, err := newTransaction(o.TxOpt)
+	if err != nil {
+		return err
+	}
+	hash, blockID, err := t.Submit(&node{url: o.Node, token: o.Token}, key, o.Attempts)
+	if len(hash) > 0 {
+		fmt.Println("hash:", hex.EncodeToString(hash))
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Println("block:", blockID)
+	return nil
+}
+
+// txStatusInterval is the interval of polling txstatus API
+const txStatusInterval = time.Second
+
+// node implements offline.Node by the API of the node
+type node struct {
+	url   string
+	token string
+}
+
+func (n *node) newRequest(method, path string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, strings.TrimRight(n.url, "/")+apiPath+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if len(n.token) > 0 {
+		req.Header.Set("Authorization", "Bearer "+n.token)
+	}
+	return req, nil
+}
+
+func (n *node) SendTx(blob []byte) ([]byte, error) {
+	req, err := n.newRequest("POST", "sendtx", strings.NewReader(url.Values{"data": {hex.EncodeToString(blob)}}.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	body, err := doRequest(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "sending transaction")
+	}
+	var result struct {
+		Hash string `json:"hash"`
+	}
+	if err = json.Unmarshal(body, &result); err != nil {
+		return nil, errors.Wrapf(err, "unmarshalling result")
+	}
+	return hex.DecodeString(result.Hash)
+}
+
+func (n *node) WaitTx(hash []byte) (int64, error) {
+	for {
+		req, err := n.newRequest("GET", "txstatus/"+hex.EncodeToString(hash), nil)
+		if err != nil {
+			return 0, err
+		}
+		body, err := doRequest(req)
+		if err != nil {
+			return 0, errors.Wrapf(err, "getting transaction status")
+		}
+		var status struct {
+			BlockID string `json:"blockid"`
+			Message *struct {
+				Type  string `json:"type"`
+				Error string `json:"error"`
+			} `json:"errmsg"`
+		}
+		if err = json.Unmarshal(body, &status); err != nil {
+			return 0, errors.Wrapf(err, "unmarshalling transaction status")
+		}
+		if len(status.BlockID) > 0 {
+			return strconv.ParseInt(status.BlockID, 10, 64)
+		}
+		if status.Message != nil {
+			if status.Message.Type == "expired" {
+				fmt.Println("transaction has expired:", hex.EncodeToString(hash))
+				return 0, offline.ErrExpired
+			}
+			return 0, fmt.Errorf("%s %s", status.Message.Type, status.Message.Error)
+		}
+		time.Sleep(txStatusInterval)
+	}
+}
+
+func (n *node) MaxBlockID() (int64, error) {
+	req, err := n.newRequest("GET", "maxblockid", nil)
+	if err != nil {
+		return 0, err
+	}
+	body, err := doRequest(req)
+	if err != nil {
+		return 0, errors.Wrapf(err, "getting max block id")
+	}
+	var result struct {
+		MaxBlockID int64 `json:"max_block_id"`
+	}
+	if err = json.Unmarshal(body, &result); err != nil {
+		return 0, errors.Wrapf(err, "unmarshalling max block id")
+	}
+	return result.MaxBlockID, nil
+}
+
+func doRequest(req *http.Request) ([]byte, error) {
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%d %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+func main() {
+	p := flags.NewParser(&opts, flags.Default)
+	if _, err := p.Parse(); err != nil {
+		os.Exit(1)
+	}
+
+	var err error
+	switch p.Active.Name {
+	case "fetch":
+		err = fetch()
+	case "sign":
+		err = sign()
+	case "send":
+		err = send()
+	case "submit":
+		err = submit()
+	}
+
+	if err != nil {
+		fmt.Printf("Error while %s: %s\n", p.Active.Name, err.Error())
+		os.Exit(1)
+	}
+}